@@ -0,0 +1,92 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmarks
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+// TestFixturesBuild keeps the fixtures buildable,
+// since benchmarks don't run with the other tests.
+func TestFixturesBuild(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	if err := WriteManyResources(fSys, "/many", 8); err != nil {
+		t.Fatal(err)
+	}
+	top, err := WriteDeepOverlays(fSys, "/deep", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteManyGenerators(fSys, "/gen", 2); err != nil {
+		t.Fatal(err)
+	}
+	for dir, expected := range map[string]string{
+		"/many": "name: bench-app1\n",
+		top:     "name: o2-o1-o0-bench-app0\n",
+		"/gen":  "name: config1-",
+	} {
+		out, err := Build(fSys, dir, 4)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", dir, err)
+		}
+		if !bytes.Contains(out, []byte(expected)) {
+			t.Fatalf("%s: expected %q in\n%s", dir, expected, out)
+		}
+	}
+}
+
+func benchmarkBuild(
+	b *testing.B, dir string, fSys fs.FileSystem, procs int) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Build(fSys, dir, procs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkManyResources(b *testing.B, n, procs int) {
+	fSys := fs.MakeFakeFS()
+	if err := WriteManyResources(fSys, "/app", n); err != nil {
+		b.Fatal(err)
+	}
+	benchmarkBuild(b, "/app", fSys, procs)
+}
+
+func BenchmarkBuild100Resources(b *testing.B) {
+	benchmarkManyResources(b, 100, 1)
+}
+
+func BenchmarkBuild1000Resources(b *testing.B) {
+	benchmarkManyResources(b, 1000, 1)
+}
+
+func BenchmarkBuild10000Resources(b *testing.B) {
+	benchmarkManyResources(b, 10000, 1)
+}
+
+func BenchmarkBuild1000ResourcesMaxProcs4(b *testing.B) {
+	benchmarkManyResources(b, 1000, 4)
+}
+
+func BenchmarkBuildDeepOverlays(b *testing.B) {
+	fSys := fs.MakeFakeFS()
+	top, err := WriteDeepOverlays(fSys, "/app", 20)
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkBuild(b, top, fSys, 1)
+}
+
+func BenchmarkBuildManyGenerators(b *testing.B) {
+	fSys := fs.MakeFakeFS()
+	if err := WriteManyGenerators(fSys, "/app", 200); err != nil {
+		b.Fatal(err)
+	}
+	benchmarkBuild(b, "/app", fSys, 1)
+}
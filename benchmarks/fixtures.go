@@ -0,0 +1,251 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package benchmarks holds synthetic kustomizations
+// representative of large real world ones, and
+// benchmarks building them, run with `make bench`.
+package benchmarks
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+)
+
+// Build builds the kustomization in dir as
+// `kustomize build --max-procs procs` does,
+// returning its output.
+func Build(fSys fs.FileSystem, dir string, procs int) ([]byte, error) {
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl()).
+		WithMaxProcs(procs)
+	ldr, err := loader.NewLoader(
+		loader.RestrictionRootOnly, validator.NewKustValidator(), dir, fSys)
+	if err != nil {
+		return nil, err
+	}
+	defer ldr.Cleanup()
+	kt, err := target.NewKustTarget(
+		ldr, rf, transformer.NewFactoryImpl(),
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	if err != nil {
+		return nil, err
+	}
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		return nil, err
+	}
+	return m.AsYaml()
+}
+
+// WriteManyResources writes a kustomization of n resources,
+// a mix of Deployments, Services, ConfigMaps and a large
+// custom resource, with a prefix, labels and annotations
+// applied to all of them.
+func WriteManyResources(fSys fs.FileSystem, dir string, n int) error {
+	var b bytes.Buffer
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("app%d", i/4)
+		switch i % 4 {
+		case 0:
+			b.WriteString(deployment(name))
+		case 1:
+			b.WriteString(service(name))
+		case 2:
+			b.WriteString(configMap(name))
+		case 3:
+			b.WriteString(customResource(name))
+		}
+	}
+	if err := fSys.WriteFile(
+		filepath.Join(dir, "resources.yaml"), b.Bytes()); err != nil {
+		return err
+	}
+	return fSys.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(`
+namePrefix: bench-
+commonLabels:
+  team: bench
+commonAnnotations:
+  owner: bench@example.com
+resources:
+- resources.yaml
+`))
+}
+
+// WriteDeepOverlays writes a base of a few resources
+// under a chain of depth overlays, each adding a prefix,
+// a label and a patch, returning the top overlay.
+func WriteDeepOverlays(
+	fSys fs.FileSystem, dir string, depth int) (string, error) {
+	base := filepath.Join(dir, "base")
+	if err := WriteManyResources(fSys, base, 8); err != nil {
+		return "", err
+	}
+	below := base
+	for i := 0; i < depth; i++ {
+		overlay := filepath.Join(dir, fmt.Sprintf("overlay%d", i))
+		rel, err := filepath.Rel(overlay, below)
+		if err != nil {
+			return "", err
+		}
+		if err := fSys.WriteFile(
+			filepath.Join(overlay, "patch.yaml"), []byte(fmt.Sprintf(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app0
+spec:
+  replicas: %d
+`, i+1))); err != nil {
+			return "", err
+		}
+		if err := fSys.WriteFile(
+			filepath.Join(overlay, "kustomization.yaml"), []byte(fmt.Sprintf(`
+namePrefix: o%d-
+commonLabels:
+  overlay%d: "true"
+resources:
+- %s
+patchesStrategicMerge:
+- patch.yaml
+`, i, i, rel))); err != nil {
+			return "", err
+		}
+		below = overlay
+	}
+	return below, nil
+}
+
+// WriteManyGenerators writes a kustomization with n
+// ConfigMap and n Secret generators, each referred
+// to by a Deployment.
+func WriteManyGenerators(fSys fs.FileSystem, dir string, n int) error {
+	var k, r bytes.Buffer
+	k.WriteString("resources:\n- resources.yaml\nconfigMapGenerator:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&k,
+			"- name: config%d\n  literals:\n  - a=%d\n  - b=%d\n  files:\n  - data%d.properties\n",
+			i, i, i*i, i)
+		if err := fSys.WriteFile(
+			filepath.Join(dir, fmt.Sprintf("data%d.properties", i)),
+			[]byte(fmt.Sprintf("x=%d\ny=%d\n", i, i+1))); err != nil {
+			return err
+		}
+	}
+	k.WriteString("secretGenerator:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&k,
+			"- name: secret%d\n  literals:\n  - password=p%d\n", i, i)
+		fmt.Fprintf(&r, `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app%d
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: example.com/app:v1
+        envFrom:
+        - configMapRef:
+            name: config%d
+      volumes:
+      - name: secret
+        secret:
+          secretName: secret%d
+`, i, i, i)
+	}
+	if err := fSys.WriteFile(
+		filepath.Join(dir, "resources.yaml"), r.Bytes()); err != nil {
+		return err
+	}
+	return fSys.WriteFile(
+		filepath.Join(dir, "kustomization.yaml"), k.Bytes())
+}
+
+func deployment(name string) string {
+	return fmt.Sprintf(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: %[1]s
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: %[1]s
+  template:
+    metadata:
+      labels:
+        app: %[1]s
+    spec:
+      containers:
+      - name: app
+        image: example.com/%[1]s:v1
+        args: ["--port", "8080"]
+        ports:
+        - containerPort: 8080
+        envFrom:
+        - configMapRef:
+            name: %[1]s
+`, name)
+}
+
+func service(name string) string {
+	return fmt.Sprintf(`---
+apiVersion: v1
+kind: Service
+metadata:
+  name: %[1]s
+spec:
+  selector:
+    app: %[1]s
+  ports:
+  - port: 80
+    targetPort: 8080
+`, name)
+}
+
+func configMap(name string) string {
+	return fmt.Sprintf(`---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %[1]s
+data:
+  LOG_LEVEL: info
+  NAME: %[1]s
+`, name)
+}
+
+// customResource is bulky and has no fields
+// transformers know about beyond its metadata.
+func customResource(name string) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `---
+apiVersion: bench.example.com/v1
+kind: Dashboard
+metadata:
+  name: %s
+spec:
+  panels:
+`, name)
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&b, `  - title: panel %d
+    query: rate(http_requests_total{app="%s"}[5m])
+    thresholds: [0.5, 0.9, 0.99]
+`, i, name)
+	}
+	return b.String()
+}
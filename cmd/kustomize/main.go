@@ -6,12 +6,23 @@ package main
 import (
 	"os"
 
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/commands"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
 )
 
 func main() {
-	if err := commands.NewDefaultCommand().Execute(); err != nil {
-		os.Exit(1)
+	fs.RemoveAllTmpOnSignal()
+	if err := execute(); err != nil {
+		commands.PrintError(os.Stderr, err)
+		os.Exit(kusterr.ExitCode(err))
 	}
-	os.Exit(0)
+	os.Exit(kusterr.ExitOK)
+}
+
+// execute runs the command, then removes temporary
+// files, even if the command panics.
+func execute() error {
+	defer fs.RemoveAllTmp()
+	return commands.NewDefaultCommand().Execute()
 }
@@ -0,0 +1,40 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package krusty, at the import path of upstream's,
+// is sigs.k8s.io/kustomize/v3/pkg/krusty, so programs
+// written against upstream build against this module,
+// unchanged, given a replace directive; see the README
+// of the compat directory.  It has only the names that
+// package promises to match.
+package krusty
+
+import (
+	"sigs.k8s.io/kustomize/v3/pkg/krusty"
+)
+
+// Kustomizer performs kustomizations.
+type Kustomizer = krusty.Kustomizer
+
+// Options holds high-level kustomize configuration options.
+type Options = krusty.Options
+
+// ReorderOption says how to order the resources
+// a Kustomizer returns.
+type ReorderOption = krusty.ReorderOption
+
+const (
+	ReorderOptionLegacy      = krusty.ReorderOptionLegacy
+	ReorderOptionNone        = krusty.ReorderOptionNone
+	ReorderOptionUnspecified = krusty.ReorderOptionUnspecified
+)
+
+// MakeKustomizer returns an instance of Kustomizer.
+func MakeKustomizer(o *Options) *Kustomizer {
+	return krusty.MakeKustomizer(o)
+}
+
+// MakeDefaultOptions returns a default instance of Options.
+func MakeDefaultOptions() *Options {
+	return krusty.MakeDefaultOptions()
+}
@@ -0,0 +1,15 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package resmap, at the import path of upstream's,
+// is the ResMap of sigs.k8s.io/kustomize/v3/pkg/resmap,
+// that a Kustomizer returns.
+package resmap
+
+import (
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+// ResMap is an interface describing operations
+// on the core kustomize data structure.
+type ResMap = resmap.ResMap
@@ -0,0 +1,31 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package types, at the import path of upstream's,
+// has the names of sigs.k8s.io/kustomize/v3/pkg/types
+// that krusty's Options use.
+package types
+
+import (
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// LoadRestrictions are the restrictions on what
+// files a kustomization may refer to.
+type LoadRestrictions = types.LoadRestrictions
+
+const (
+	LoadRestrictionsUnknown      = types.LoadRestrictionsUnknown
+	LoadRestrictionsRootOnly     = types.LoadRestrictionsRootOnly
+	LoadRestrictionsNone         = types.LoadRestrictionsNone
+	LoadRestrictionsRepoRootOnly = types.LoadRestrictionsRepoRootOnly
+)
+
+// PluginConfig says whether, and from where, to load plugins.
+type PluginConfig = types.PluginConfig
+
+// DisabledPluginConfig returns a PluginConfig
+// loading no plugins.
+func DisabledPluginConfig() *PluginConfig {
+	return types.DisabledPluginConfig()
+}
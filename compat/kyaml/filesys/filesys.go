@@ -0,0 +1,46 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package filesys, at the import path of upstream's,
+// is sigs.k8s.io/kustomize/v3/pkg/filesys, whose file
+// systems a Kustomizer reads.
+package filesys
+
+import (
+	"sigs.k8s.io/kustomize/v3/pkg/filesys"
+)
+
+const (
+	Separator = filesys.Separator
+	SelfDir   = filesys.SelfDir
+	ParentDir = filesys.ParentDir
+)
+
+// FileSystem groups basic os filesystem methods.
+type FileSystem = filesys.FileSystem
+
+// File groups the basic os.File methods.
+type File = filesys.File
+
+// ConfirmedDir is a clean, absolute, delinkified
+// path that was confirmed to point to an existing
+// directory.
+type ConfirmedDir = filesys.ConfirmedDir
+
+// MakeFsOnDisk returns a FileSystem reading and
+// writing the local disk.
+func MakeFsOnDisk() FileSystem {
+	return filesys.MakeFsOnDisk()
+}
+
+// MakeFsInMemory returns an empty FileSystem
+// held in memory, e.g. for tests.
+func MakeFsInMemory() FileSystem {
+	return filesys.MakeFsInMemory()
+}
+
+// NewTmpConfirmedDir returns a new temporary
+// directory on disk.
+func NewTmpConfirmedDir() (ConfirmedDir, error) {
+	return filesys.NewTmpConfirmedDir()
+}
@@ -0,0 +1,7 @@
+module sigs.k8s.io/kustomize/kyaml
+
+go 1.12
+
+require sigs.k8s.io/kustomize/v3 v3.0.0-00010101000000-000000000000
+
+replace sigs.k8s.io/kustomize/v3 => ../..
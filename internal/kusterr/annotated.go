@@ -0,0 +1,99 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kusterr
+
+// annotated attaches to an error, without changing its
+// message, what programs and the CLI key off: its Class,
+// which maps to an exit code, its kind, an exported error
+// value like loader.ErrLoadRestricted, for errors.Is, and
+// the ID of its message in pkg/messages.
+type annotated struct {
+	class Class
+	kind  error
+	id    string
+	err   error
+}
+
+func (e annotated) Error() string {
+	return e.err.Error()
+}
+
+// Cause satisfies the causer interface of
+// github.com/pkg/errors, and Unwrap the wrapper
+// interface of the errors package of Go 1.13.
+func (e annotated) Cause() error {
+	return e.err
+}
+
+func (e annotated) Unwrap() error {
+	return e.err
+}
+
+// Is is true of the kind, for errors.Is.
+func (e annotated) Is(target error) bool {
+	return e.kind != nil && target == e.kind
+}
+
+// annotate returns err with the annotations set in a,
+// or nil if err is nil.  Annotations err already has
+// win, being closer to the root cause, except for kinds,
+// of which an error may have more than one.
+func annotate(err error, a annotated) error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(annotated)
+	if !ok || (a.kind != nil && e.kind != nil) {
+		a.err = err
+		return a
+	}
+	if e.class == ClassUnknown {
+		e.class = a.class
+	}
+	if e.kind == nil {
+		e.kind = a.kind
+	}
+	if e.id == "" {
+		e.id = a.id
+	}
+	return e
+}
+
+// WithID gives err the message ID, keeping its message,
+// or returns nil if err is nil.  See pkg/messages.
+func WithID(id string, err error) error {
+	return annotate(err, annotated{id: id})
+}
+
+// IDOf returns the message ID of err, looking through
+// errors it wraps, or "" if it has none.  As with
+// ClassOf, the innermost ID wins.
+func IDOf(err error) string {
+	result := ""
+	walk(err, func(e error) bool {
+		if a, ok := e.(annotated); ok && a.id != "" {
+			result = a.id
+		}
+		return false
+	})
+	return result
+}
+
+// walk calls f with err and each error it wraps, from
+// the outermost, until f returns true.
+func walk(err error, f func(error) bool) {
+	for err != nil {
+		if f(err) {
+			return
+		}
+		switch x := err.(type) {
+		case interface{ Cause() error }:
+			err = x.Cause()
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kusterr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestAnnotationsCombine(t *testing.T) {
+	kind := fmt.Errorf("some kind")
+	other := fmt.Errorf("other kind")
+	err := WithClass(ClassRemote, WithKind(other,
+		WithKind(kind, WithID("bad-clone", fmt.Errorf("bad clone")))))
+	if err.Error() != "bad clone" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+	if ClassOf(err) != ClassRemote || IDOf(err) != "bad-clone" ||
+		!Is(err, kind) || !Is(err, other) {
+		t.Fatalf("lost an annotation: %v %q %v %v",
+			ClassOf(err), IDOf(err), Is(err, kind), Is(err, other))
+	}
+}
+
+func TestIDOf(t *testing.T) {
+	base := fmt.Errorf("boom")
+	testCases := map[string]struct {
+		err      error
+		expected string
+	}{
+		"nil": {
+			err:      nil,
+			expected: "",
+		},
+		"none": {
+			err:      WithClass(ClassLoad, base),
+			expected: "",
+		},
+		"wrapped": {
+			err:      errors.Wrap(WithID("inner", base), "accumulating"),
+			expected: "inner",
+		},
+		"innermostWins": {
+			err: WithID("outer", errors.Wrap(
+				WithID("inner", base), "accumulating")),
+			expected: "inner",
+		},
+		"innermostWinsUnwrapped": {
+			err:      WithID("outer", WithID("inner", base)),
+			expected: "inner",
+		},
+	}
+	for n, tc := range testCases {
+		if actual := IDOf(tc.err); actual != tc.expected {
+			t.Errorf("%s: expected %q, got %q", n, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kusterr
+
+// Class is a coarse category of failure.  The CLI maps
+// it to a process exit code, so scripts can branch on
+// the kind of failure rather than grepping stderr.
+type Class int
+
+const (
+	ClassUnknown Class = iota
+	// Bad flags or arguments.
+	ClassUsage
+	// A local file or directory couldn't be read,
+	// or its loading was disallowed by a restriction.
+	ClassLoad
+	// A remote base couldn't be fetched.
+	ClassRemote
+	// Some input failed validation.
+	ClassValidation
+	// A plugin couldn't be loaded, configured or run.
+	ClassPlugin
+)
+
+// Exit codes, one per Class.  These are a public
+// contract; don't renumber them.
+const (
+	ExitOK         = 0
+	ExitUnknown    = 1
+	ExitUsage      = 2
+	ExitLoad       = 3
+	ExitRemote     = 4
+	ExitValidation = 5
+	ExitPlugin     = 6
+)
+
+// ExitCode returns the process exit code for the class.
+func (c Class) ExitCode() int {
+	switch c {
+	case ClassUsage:
+		return ExitUsage
+	case ClassLoad:
+		return ExitLoad
+	case ClassRemote:
+		return ExitRemote
+	case ClassValidation:
+		return ExitValidation
+	case ClassPlugin:
+		return ExitPlugin
+	default:
+		return ExitUnknown
+	}
+}
+
+// String returns the name of the class, e.g. "load".
+func (c Class) String() string {
+	switch c {
+	case ClassUsage:
+		return "usage"
+	case ClassLoad:
+		return "load"
+	case ClassRemote:
+		return "remote"
+	case ClassValidation:
+		return "validation"
+	case ClassPlugin:
+		return "plugin"
+	default:
+		return "unknown"
+	}
+}
+
+// WithClass returns err tagged with the given class,
+// or nil if err is nil.
+func WithClass(c Class, err error) error {
+	return annotate(err, annotated{class: c})
+}
+
+// ClassOf returns the class of err, looking through
+// errors wrapped via github.com/pkg/errors.  If more
+// than one class appears in the chain, the innermost
+// (the one closest to the root cause) wins.
+func ClassOf(err error) Class {
+	result := ClassUnknown
+	walk(err, func(e error) bool {
+		if a, ok := e.(annotated); ok && a.class != ClassUnknown {
+			result = a.class
+		}
+		return false
+	})
+	return result
+}
+
+// ExitCode returns the process exit code for err.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	return ClassOf(err).ExitCode()
+}
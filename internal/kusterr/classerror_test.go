@@ -0,0 +1,57 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kusterr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestExitCode(t *testing.T) {
+	base := fmt.Errorf("boom")
+	testCases := map[string]struct {
+		err      error
+		expected int
+	}{
+		"nil": {
+			err:      nil,
+			expected: ExitOK,
+		},
+		"unclassified": {
+			err:      base,
+			expected: ExitUnknown,
+		},
+		"direct": {
+			err:      WithClass(ClassRemote, base),
+			expected: ExitRemote,
+		},
+		"wrapped": {
+			err: errors.Wrap(
+				WithClass(ClassValidation, base), "accumulating"),
+			expected: ExitValidation,
+		},
+		"innermostWins": {
+			err: WithClass(ClassPlugin, errors.Wrap(
+				WithClass(ClassLoad, base), "config")),
+			expected: ExitLoad,
+		},
+	}
+	for n, tc := range testCases {
+		if actual := ExitCode(tc.err); actual != tc.expected {
+			t.Errorf("%s: expected %d, got %d", n, tc.expected, actual)
+		}
+	}
+}
+
+func TestWithClassKeepsMessage(t *testing.T) {
+	err := WithClass(ClassUsage, fmt.Errorf("bad flag"))
+	if err.Error() != "bad flag" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+	if WithClass(ClassUsage, nil) != nil {
+		t.Fatalf("expected nil")
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kusterr
+
+// WithKind marks err as of a kind, an exported error
+// value like loader.ErrLoadRestricted, so that programs
+// building kustomizations can test for it, with errors.Is,
+// rather than match its message.  It keeps err's message,
+// and returns nil if err is nil.
+func WithKind(kind, err error) error {
+	return annotate(err, annotated{kind: kind})
+}
+
+// Is is errors.Is for Go 1.12: it's true if err, or an
+// error err wraps, is kind or was marked as of it.
+func Is(err, kind error) bool {
+	found := false
+	walk(err, func(e error) bool {
+		a, ok := e.(annotated)
+		found = e == kind || ok && a.Is(kind)
+		return found
+	})
+	return found
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kusterr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestIs(t *testing.T) {
+	kind := fmt.Errorf("some kind")
+	other := fmt.Errorf("other kind")
+	base := fmt.Errorf("boom")
+	testCases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"nil": {
+			err:      nil,
+			expected: false,
+		},
+		"unmarked": {
+			err:      base,
+			expected: false,
+		},
+		"kind": {
+			err:      kind,
+			expected: true,
+		},
+		"marked": {
+			err:      WithKind(kind, base),
+			expected: true,
+		},
+		"otherKind": {
+			err:      WithKind(other, base),
+			expected: false,
+		},
+		"wrapped": {
+			err: WithClass(ClassLoad, errors.Wrap(
+				WithKind(other, WithKind(kind, base)), "accumulating")),
+			expected: true,
+		},
+	}
+	for n, tc := range testCases {
+		if actual := Is(tc.err, kind); actual != tc.expected {
+			t.Errorf("%s: expected %v, got %v", n, tc.expected, actual)
+		}
+	}
+}
+
+func TestWithKindKeepsMessageAndClass(t *testing.T) {
+	err := WithKind(fmt.Errorf("some kind"),
+		WithClass(ClassRemote, fmt.Errorf("bad clone")))
+	if err.Error() != "bad clone" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+	if ClassOf(err) != ClassRemote {
+		t.Fatalf("unexpected class %v", ClassOf(err))
+	}
+	if WithKind(fmt.Errorf("some kind"), nil) != nil {
+		t.Fatalf("expected nil")
+	}
+}
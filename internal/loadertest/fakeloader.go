@@ -5,6 +5,7 @@
 package loadertest
 
 import (
+	"io"
 	"log"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
@@ -72,6 +73,11 @@ func (f FakeLoader) Load(location string) ([]byte, error) {
 	return f.delegate.Load(location)
 }
 
+// Open delegates.
+func (f FakeLoader) Open(location string) (io.ReadCloser, error) {
+	return f.delegate.Open(location)
+}
+
 // Cleanup delegates.
 func (f FakeLoader) Cleanup() error {
 	return f.delegate.Cleanup()
@@ -52,7 +52,7 @@ func (f *Factory) addKvToConfigMap(configMap *v1.ConfigMap, p types.Pair) error
 	}
 	// If the configmap data contains byte sequences that are all in the UTF-8
 	// range, we will write it to .Data
-	if utf8.Valid([]byte(p.Value)) {
+	if utf8.ValidString(p.Value) {
 		if _, entryExists := configMap.Data[p.Key]; entryExists {
 			return fmt.Errorf(keyExistsErrorMsg, p.Key, configMap.Data)
 		}
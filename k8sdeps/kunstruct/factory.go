@@ -4,6 +4,7 @@
 package kunstruct
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"io"
@@ -18,14 +19,23 @@ import (
 
 // KunstructuredFactoryImpl hides construction using apimachinery types.
 type KunstructuredFactoryImpl struct {
-	hasher *kustHash
+	hasher ifc.KunstructuredHasher
+	cache  generatorCache
 }
 
 var _ ifc.KunstructuredFactory = &KunstructuredFactoryImpl{}
 
 // NewKunstructuredFactoryImpl returns a factory.
 func NewKunstructuredFactoryImpl() ifc.KunstructuredFactory {
-	return &KunstructuredFactoryImpl{hasher: NewKustHash()}
+	return NewKunstructuredFactoryWithHasher(NewKustHash())
+}
+
+// NewKunstructuredFactoryWithHasher returns a factory whose
+// Hasher is h, e.g. a NewKustHMACHash, which names
+// generated resources.
+func NewKunstructuredFactoryWithHasher(
+	h ifc.KunstructuredHasher) ifc.KunstructuredFactory {
+	return &KunstructuredFactoryImpl{hasher: h}
 }
 
 // Hasher returns a kunstructured hasher
@@ -35,7 +45,44 @@ func (kf *KunstructuredFactoryImpl) Hasher() ifc.KunstructuredHasher {
 }
 
 // SliceFromBytes returns a slice of Kunstructured.
+// YAML documents are decoded lazily, see lazyAdapter.
 func (kf *KunstructuredFactoryImpl) SliceFromBytes(
+	in []byte) ([]ifc.Kunstructured, error) {
+	if isJSON(in) {
+		return kf.sliceFromBytesEagerly(in)
+	}
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(in)))
+	var result []ifc.Kunstructured
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if l, ok := newLazyAdapter(doc); ok &&
+			kf.validate(l.head.Unstructured) == nil {
+			result = append(result, l)
+			continue
+		}
+		// Decoding eagerly skips empty documents,
+		// and gives the errors for invalid ones.
+		eager, err := kf.sliceFromBytesEagerly(doc)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, eager...)
+	}
+}
+
+// isJSON is true if in looks like JSON rather than YAML.
+func isJSON(in []byte) bool {
+	trimmed := bytes.TrimSpace(in)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+func (kf *KunstructuredFactoryImpl) sliceFromBytesEagerly(
 	in []byte) ([]ifc.Kunstructured, error) {
 	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(in), 1024)
 	var result []ifc.Kunstructured
@@ -75,12 +122,16 @@ func (kf *KunstructuredFactoryImpl) MakeConfigMap(
 	ldr ifc.Loader,
 	options *types.GeneratorOptions,
 	args *types.ConfigMapArgs) (ifc.Kunstructured, error) {
-	o, err := configmapandsecret.NewFactory(
-		ldr, options).MakeConfigMap(args)
-	if err != nil {
-		return nil, err
-	}
-	return NewKunstructuredFromObject(o)
+	return kf.cache.generate(
+		ldr, "ConfigMap", options, args, args.GeneratorArgs,
+		func() (ifc.Kunstructured, error) {
+			o, err := configmapandsecret.NewFactory(
+				ldr, options).MakeConfigMap(args)
+			if err != nil {
+				return nil, err
+			}
+			return NewKunstructuredFromObject(o)
+		})
 }
 
 // MakeSecret returns an instance of Kunstructured for Secret
@@ -88,15 +139,20 @@ func (kf *KunstructuredFactoryImpl) MakeSecret(
 	ldr ifc.Loader,
 	options *types.GeneratorOptions,
 	args *types.SecretArgs) (ifc.Kunstructured, error) {
-	o, err := configmapandsecret.NewFactory(
-		ldr, options).MakeSecret(args)
-	if err != nil {
-		return nil, err
-	}
-	return NewKunstructuredFromObject(o)
+	return kf.cache.generate(
+		ldr, "Secret", options, args, args.GeneratorArgs,
+		func() (ifc.Kunstructured, error) {
+			o, err := configmapandsecret.NewFactory(
+				ldr, options).MakeSecret(args)
+			if err != nil {
+				return nil, err
+			}
+			return NewKunstructuredFromObject(o)
+		})
 }
 
-// validate validates that u has kind and name
+// validate validates that u has kind and name, or a
+// generateName for the API server to make a name from,
 // except for kind `List`, which doesn't require a name
 func (kf *KunstructuredFactoryImpl) validate(u unstructured.Unstructured) error {
 	kind := u.GetKind()
@@ -105,7 +161,7 @@ func (kf *KunstructuredFactoryImpl) validate(u unstructured.Unstructured) error
 	} else if strings.HasSuffix(kind, "List") {
 		return nil
 	}
-	if u.GetName() == "" {
+	if u.GetName() == "" && u.GetGenerateName() == "" {
 		return fmt.Errorf("missing metadata.name in object %v", u)
 	}
 	return nil
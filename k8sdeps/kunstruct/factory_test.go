@@ -193,7 +193,7 @@ items:
 				test.name, len(rs), len(test.expectedOut))
 		}
 		for i := range rs {
-			if !reflect.DeepEqual(test.expectedOut[i], rs[i]) {
+			if !reflect.DeepEqual(test.expectedOut[i].Map(), rs[i].Map()) {
 				t.Fatalf("%s: Got: %v\nexpected:%v",
 					test.name, test.expectedOut[i], rs[i])
 			}
@@ -0,0 +1,249 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kunstruct
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// generatorCache remembers the ConfigMaps and Secrets
+// generators make, by a key hashing the generator's
+// arguments and options and the content of its files,
+// so a generator heavy base included many times is only
+// generated once.  Generated ConfigMaps are also kept
+// across builds in the directory the loader names, if
+// it's a cacheDirLoader; Secrets never are, to keep
+// their data out of the cache directory.
+type generatorCache struct {
+	sync.Mutex
+	objects map[string]map[string]interface{}
+}
+
+// cacheDirLoader is a loader that names a directory
+// generated ConfigMaps are cached in across builds.
+// An empty dir caches them only within a build.
+type cacheDirLoader interface {
+	GeneratorCacheDir() string
+}
+
+// generate returns what fresh would, from the
+// cache if it can.
+func (c *generatorCache) generate(
+	ldr ifc.Loader, kind string, options *types.GeneratorOptions,
+	args interface{}, gArgs types.GeneratorArgs,
+	fresh func() (ifc.Kunstructured, error)) (ifc.Kunstructured, error) {
+	key, ok := generatorKey(ldr, kind, options, args, gArgs)
+	if !ok {
+		return fresh()
+	}
+	// The directory of the cache on disk, if any.
+	var dir string
+	if l, ok := ldr.(cacheDirLoader); ok && kind == "ConfigMap" {
+		dir = l.GeneratorCacheDir()
+	}
+	if m, ok := c.get(key, dir); ok {
+		return &UnstructAdapter{Unstructured: unstructured.Unstructured{Object: m}}, nil
+	}
+	k, err := fresh()
+	if err != nil {
+		return nil, err
+	}
+	c.put(key, runtime.DeepCopyJSON(k.Map()), dir)
+	return k, nil
+}
+
+// get returns a copy of the object cached under key,
+// looking in dir too, if it isn't empty.
+func (c *generatorCache) get(
+	key, dir string) (map[string]interface{}, bool) {
+	c.Lock()
+	defer c.Unlock()
+	if m, ok := c.objects[key]; ok {
+		return runtime.DeepCopyJSON(m), true
+	}
+	if dir == "" {
+		return nil, false
+	}
+	path := filepath.Join(dir, key+".json")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	// The entry's time is when it was last used,
+	// for CleanGeneratorCache.
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	var u unstructured.Unstructured
+	if u.UnmarshalJSON(b) != nil {
+		return nil, false
+	}
+	if c.objects == nil {
+		c.objects = make(map[string]map[string]interface{})
+	}
+	c.objects[key] = u.Object
+	return runtime.DeepCopyJSON(u.Object), true
+}
+
+// put caches m under key, in dir too, if it isn't empty.
+// Failing to write dir only loses the cache entry.
+func (c *generatorCache) put(
+	key string, m map[string]interface{}, dir string) {
+	c.Lock()
+	defer c.Unlock()
+	if c.objects == nil {
+		c.objects = make(map[string]map[string]interface{})
+	}
+	c.objects[key] = m
+	if dir == "" {
+		return
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(dir, 0700) != nil {
+		return
+	}
+	// Write then rename, so concurrent
+	// builds never read a partial entry.
+	f, err := ioutil.TempFile(dir, key)
+	if err != nil {
+		return
+	}
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(dir, key+".json"))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// CleanGeneratorCache removes the entries of the
+// generator cache in dir last used longer ago than
+// olderThan, and files left by interrupted writes,
+// returning their paths.  Other files, whose names
+// don't start with a key, are left be.  Builds read an
+// entry whole, so removing it never breaks one.
+func CleanGeneratorCache(dir string, olderThan time.Duration) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, f := range files {
+		if f.IsDir() || !isGeneratorFile(f.Name()) ||
+			time.Since(f.ModTime()) < olderThan {
+			continue
+		}
+		p := filepath.Join(dir, f.Name())
+		if err := os.Remove(p); err != nil {
+			return removed, err
+		}
+		removed = append(removed, p)
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// isGeneratorFile is true if the name starts with a
+// key, as entries and their temporary files' do.
+func isGeneratorFile(name string) bool {
+	if len(name) < 2*sha256.Size {
+		return false
+	}
+	_, err := hex.DecodeString(name[:2*sha256.Size])
+	return err == nil
+}
+
+// generatorKey hashes everything a generator's output
+// depends on, or returns false if that can't be known
+// up front, i.e. a file can't be read, or an env file
+// takes a value from the environment.
+func generatorKey(
+	ldr ifc.Loader, kind string, options *types.GeneratorOptions,
+	args interface{}, gArgs types.GeneratorArgs) (string, bool) {
+	h := sha256.New()
+	b, err := json.Marshal(struct {
+		Kind    string
+		Options *types.GeneratorOptions
+		Args    interface{}
+	}{kind, options, args})
+	if err != nil {
+		return "", false
+	}
+	h.Write(b)
+	for _, s := range gArgs.FileSources {
+		// Files may be large, so they're streamed.
+		p := s[strings.LastIndex(s, "=")+1:]
+		r, err := ldr.Open(p)
+		if err != nil {
+			return "", false
+		}
+		fh := sha256.New()
+		_, err = io.Copy(fh, r)
+		r.Close()
+		if err != nil {
+			return "", false
+		}
+		hashFile(h, p, fh.Sum(nil))
+	}
+	envs := gArgs.EnvSources
+	if gArgs.EnvSource != "" {
+		envs = append([]string{gArgs.EnvSource}, envs...)
+	}
+	for _, p := range envs {
+		content, err := ldr.Load(p)
+		if err != nil || readsEnvironment(content) {
+			return "", false
+		}
+		sum := sha256.Sum256(content)
+		hashFile(h, p, sum[:])
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+func hashFile(h hash.Hash, path string, sum []byte) {
+	h.Write([]byte(path))
+	h.Write(sum)
+}
+
+// readsEnvironment is true if an env file has a line
+// that's only a key, whose value comes from the
+// environment.
+func readsEnvironment(content []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") &&
+			!strings.Contains(line, "=") {
+			return true
+		}
+	}
+	return false
+}
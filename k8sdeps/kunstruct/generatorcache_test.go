@@ -0,0 +1,142 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kunstruct
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/v3/internal/loadertest"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// cachingLoader names a directory to cache
+// generated ConfigMaps in.
+type cachingLoader struct {
+	loadertest.FakeLoader
+	dir string
+}
+
+func (l cachingLoader) GeneratorCacheDir() string {
+	return l.dir
+}
+
+func TestGeneratorCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-generator-cache-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ldr := cachingLoader{loadertest.NewFakeLoader("/app"), dir}
+	ldr.AddFile("/app/app.properties", []byte("a=1\n"))
+	ldr.AddFile("/app/app.env", []byte("B=2\n"))
+	cmArgs := &types.ConfigMapArgs{GeneratorArgs: types.GeneratorArgs{
+		Name: "app",
+		DataSources: types.DataSources{
+			FileSources: []string{"app.properties"},
+			EnvSources:  []string{"app.env"},
+		}}}
+	secretArgs := &types.SecretArgs{GeneratorArgs: cmArgs.GeneratorArgs}
+
+	kf := NewKunstructuredFactoryImpl()
+	first, err := kf.MakeConfigMap(ldr, nil, cmArgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := first.Copy().Map()
+	first.SetName("changed-by-a-transformer")
+	second, err := kf.MakeConfigMap(ldr, nil, cmArgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(second.Map(), expected) {
+		t.Fatalf("expected %v, got %v", expected, second.Map())
+	}
+	if _, err := kf.MakeSecret(ldr, nil, secretArgs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected the ConfigMap alone cached on disk, got %v", entries)
+	}
+
+	// A new factory, as in a later build, reads the disk cache.
+	third, err := NewKunstructuredFactoryImpl().MakeConfigMap(ldr, nil, cmArgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(third.Map(), expected) {
+		t.Fatalf("expected %v, got %v", expected, third.Map())
+	}
+
+	ldr.AddFile("/app/app.properties", []byte("a=3\n"))
+	changed, err := kf.MakeConfigMap(ldr, nil, cmArgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := changed.Map()["data"].(map[string]interface{})
+	if data["app.properties"] != "a=3\n" {
+		t.Fatalf("expected changed file to be read, got %v", data)
+	}
+}
+
+func TestGeneratorKeyUnknowable(t *testing.T) {
+	ldr := loadertest.NewFakeLoader("/app")
+	ldr.AddFile("/app/app.env", []byte("# from the environment\nHOME\n"))
+	args := types.GeneratorArgs{DataSources: types.DataSources{
+		EnvSources: []string{"app.env"}}}
+	if _, ok := generatorKey(ldr, "ConfigMap", nil, args, args); ok {
+		t.Fatalf("expected env file reading the environment to be uncacheable")
+	}
+	args = types.GeneratorArgs{DataSources: types.DataSources{
+		FileSources: []string{"key=missing.properties"}}}
+	if _, ok := generatorKey(ldr, "ConfigMap", nil, args, args); ok {
+		t.Fatalf("expected missing file to be uncacheable")
+	}
+}
+
+func TestCleanGeneratorCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-generator-cache-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	key := strings.Repeat("ab", sha256.Size)
+	for _, n := range []string{key + ".json", key + "123", "notes.txt"} {
+		if err := ioutil.WriteFile(
+			filepath.Join(dir, n), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	removed, err := CleanGeneratorCache(dir, time.Hour)
+	if err != nil || len(removed) != 0 {
+		t.Fatalf("expected fresh entries kept, got %v, %v", removed, err)
+	}
+	removed, err = CleanGeneratorCache(dir, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		filepath.Join(dir, key+".json"), filepath.Join(dir, key+"123")}
+	if !reflect.DeepEqual(removed, expected) {
+		t.Fatalf("expected %v, got %v", expected, removed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Fatalf("expected other files kept: %v", err)
+	}
+	if removed, err := CleanGeneratorCache(
+		filepath.Join(dir, "absent"), 0); err != nil || removed != nil {
+		t.Fatalf("expected nothing to clean, got %v, %v", removed, err)
+	}
+}
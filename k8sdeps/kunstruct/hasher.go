@@ -6,19 +6,40 @@ package kunstruct
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/kustomize/v3/pkg/hasher"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 )
 
 // kustHash computes a hash of an unstructured object.
-type kustHash struct{}
+type kustHash struct {
+	// sum returns the hex form of a digest of its argument.
+	sum func(string) string
+}
 
 // NewKustHash returns a kustHash object
 func NewKustHash() *kustHash {
-	return &kustHash{}
+	return &kustHash{sum: hasher.Hash}
+}
+
+// NewKustHMACHash returns a kustHash whose hashes are
+// HMACs keyed by key, so that no one without the key
+// can change a generated resource yet keep its name.
+func NewKustHMACHash(key []byte) *kustHash {
+	return &kustHash{sum: func(data string) string {
+		return hasher.HMAC(key, data)
+	}}
+}
+
+// hashIncluder is a Kunstructured, e.g. a generated
+// resource, whose hash covers some of its metadata.
+type hashIncluder interface {
+	HashIncludes() []string
 }
 
 // Hash returns a hash of either a ConfigMap or a Secret
@@ -26,6 +47,10 @@ func (h *kustHash) Hash(m ifc.Kunstructured) (string, error) {
 	u := unstructured.Unstructured{
 		Object: m.Map(),
 	}
+	var includes []string
+	if hi, ok := m.(hashIncluder); ok {
+		includes = hi.HashIncludes()
+	}
 	kind := u.GetKind()
 	switch kind {
 	case "ConfigMap":
@@ -33,14 +58,14 @@ func (h *kustHash) Hash(m ifc.Kunstructured) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		return configMapHash(cm)
+		return h.configMapHash(cm, includes)
 	case "Secret":
 		sec, err := unstructuredToSecret(u)
 
 		if err != nil {
 			return "", err
 		}
-		return secretHash(sec)
+		return h.secretHash(sec, includes)
 	default:
 		return "", fmt.Errorf(
 			"type %s is not supported for hashing in %v",
@@ -49,31 +74,79 @@ func (h *kustHash) Hash(m ifc.Kunstructured) (string, error) {
 }
 
 // configMapHash returns a hash of the ConfigMap.
-// The Data, Kind, and Name are taken into account.
-func configMapHash(cm *v1.ConfigMap) (string, error) {
+// The Data, Kind, and Name, and the metadata
+// included, are taken into account.
+func (h *kustHash) configMapHash(
+	cm *v1.ConfigMap, includes []string) (string, error) {
 	encoded, err := encodeConfigMap(cm)
 	if err != nil {
 		return "", err
 	}
-	h, err := hasher.Encode(hasher.Hash(encoded))
+	return h.hash(encoded, cm.ObjectMeta, includes)
+}
+
+// SecretHash returns a hash of the Secret.
+// The Data, Kind, Name, and Type, and the metadata
+// included, are taken into account.
+func (h *kustHash) secretHash(
+	sec *v1.Secret, includes []string) (string, error) {
+	encoded, err := encodeSecret(sec)
 	if err != nil {
 		return "", err
 	}
-	return h, nil
+	return h.hash(encoded, sec.ObjectMeta, includes)
 }
 
-// SecretHash returns a hash of the Secret.
-// The Data, Kind, Name, and Type are taken into account.
-func secretHash(sec *v1.Secret) (string, error) {
-	encoded, err := encodeSecret(sec)
+// hash returns the hash of the encoded resource
+// and the metadata included.
+func (h *kustHash) hash(
+	encoded string, meta metav1.ObjectMeta, includes []string) (string, error) {
+	metadata, err := encodeMetadata(meta, includes)
 	if err != nil {
 		return "", err
 	}
-	h, err := hasher.Encode(hasher.Hash(encoded))
+	return hasher.Encode(h.sum(encoded + metadata))
+}
+
+// encodeMetadata encodes the metadata included, but
+// the annotations of kustomize itself, or returns an
+// empty string if there's none, so resources without
+// it keep their hashes.
+func encodeMetadata(
+	meta metav1.ObjectMeta, includes []string) (string, error) {
+	m := map[string]interface{}{}
+	for _, s := range includes {
+		switch s {
+		case types.HashIncludesLabels:
+			if len(meta.Labels) > 0 {
+				m["labels"] = meta.Labels
+			}
+		case types.HashIncludesAnnotations:
+			annotations := map[string]string{}
+			for k, v := range meta.Annotations {
+				if !isKustomizeAnnotation(k) {
+					annotations[k] = v
+				}
+			}
+			if len(annotations) > 0 {
+				m["annotations"] = annotations
+			}
+		}
+	}
+	if len(m) == 0 {
+		return "", nil
+	}
+	// json.Marshal sorts the keys in a stable order in the encoding
+	data, err := json.Marshal(m)
 	if err != nil {
 		return "", err
 	}
-	return h, nil
+	return string(data), nil
+}
+
+func isKustomizeAnnotation(k string) bool {
+	return strings.HasPrefix(k, "config.kubernetes.io/") ||
+		strings.HasPrefix(k, "kustomize.config.k8s.io/")
 }
 
 // encodeConfigMap encodes a ConfigMap.
@@ -35,7 +35,7 @@ func TestConfigMapHash(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		h, err := configMapHash(c.cm)
+		h, err := NewKustHash().configMapHash(c.cm, nil)
 		if SkipRest(t, c.desc, err, c.err) {
 			continue
 		}
@@ -61,7 +61,7 @@ func TestSecretHash(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		h, err := secretHash(c.secret)
+		h, err := NewKustHash().secretHash(c.secret, nil)
 		if SkipRest(t, c.desc, err, c.err) {
 			continue
 		}
@@ -137,6 +137,48 @@ func TestEncodeSecret(t *testing.T) {
 	}
 }
 
+func TestHashIncludes(t *testing.T) {
+	data := map[string]string{"one": ""}
+	plain := &v1.ConfigMap{Data: data}
+	labeled := &v1.ConfigMap{Data: data}
+	labeled.Labels = map[string]string{"env": "prod"}
+	annotated := &v1.ConfigMap{Data: data}
+	annotated.Annotations = map[string]string{
+		"config.kubernetes.io/origin": "path: configmap.yaml"}
+	h := NewKustHash()
+	hash := func(cm *v1.ConfigMap, includes ...string) string {
+		s, err := h.configMapHash(cm, includes)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return s
+	}
+	// Without metadata included, or any to include,
+	// the hash doesn't change.
+	for _, s := range []string{
+		hash(labeled),
+		hash(plain, "labels", "annotations"),
+		hash(annotated, "annotations"),
+	} {
+		if s != "9g67k2htb6" {
+			t.Fatalf("expected hash 9g67k2htb6, got %s", s)
+		}
+	}
+	if s := hash(labeled, "labels"); s == "9g67k2htb6" {
+		t.Fatalf("expected the labels to change the hash")
+	}
+
+	h = NewKustHMACHash([]byte("key"))
+	s := hash(plain)
+	if s == "9g67k2htb6" {
+		t.Fatalf("expected an HMAC to differ from the plain hash")
+	}
+	h = NewKustHMACHash([]byte("other key"))
+	if hash(plain) == s {
+		t.Fatalf("expected HMACs of different keys to differ")
+	}
+}
+
 // warn devs who change types that they might have to update a hash function
 // not perfect, as it only checks the number of top-level fields
 func TestTypeStability(t *testing.T) {
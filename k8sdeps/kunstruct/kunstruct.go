@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/openapi"
 )
 
 var _ ifc.Kunstructured = &UnstructAdapter{}
@@ -47,13 +48,13 @@ type UnstructAdapter struct {
 
 // NewKunstructuredFromObject returns a new instance of Kunstructured.
 func NewKunstructuredFromObject(obj runtime.Object) (ifc.Kunstructured, error) {
-	// Convert obj to a byte stream, then convert that to JSON (Unstructured).
-	marshaled, err := json.Marshal(obj)
+	// Convert obj directly, rather than through JSON,
+	// which copies the data of a large ConfigMap twice.
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 	if err != nil {
 		return &UnstructAdapter{}, err
 	}
-	var u unstructured.Unstructured
-	err = u.UnmarshalJSON(marshaled)
+	u := unstructured.Unstructured{Object: m}
 	// creationTimestamp always 'null', remove it
 	u.SetCreationTimestamp(metav1.Time{})
 	return &UnstructAdapter{Unstructured: u}, err
@@ -296,12 +297,20 @@ func (fs *UnstructAdapter) MatchesAnnotationSelector(selector string) (bool, err
 }
 
 func (fs *UnstructAdapter) Patch(patch ifc.Kunstructured) error {
-	versionedObj, err := scheme.Scheme.New(
-		toSchemaGvk(patch.GetGvk()))
+	return fs.PatchWithSchema(patch, nil)
+}
+
+// PatchWithSchema is Patch, taking the strategic merge
+// patch metadata of types the schema has from it.
+func (fs *UnstructAdapter) PatchWithSchema(
+	patch ifc.Kunstructured, s *openapi.Schema) error {
+	lookupPatchMeta, err := lookupPatchMeta(s, patch.GetGvk())
+	if err != nil {
+		return err
+	}
 	merged := map[string]interface{}{}
 	saveName := fs.GetName()
-	switch {
-	case runtime.IsNotRegisteredError(err):
+	if lookupPatchMeta == nil {
 		baseBytes, err := json.Marshal(fs.Map())
 		if err != nil {
 			return err
@@ -318,17 +327,11 @@ func (fs *UnstructAdapter) Patch(patch ifc.Kunstructured) error {
 		if err != nil {
 			return err
 		}
-	case err != nil:
-		return err
-	default:
+	} else {
 		// Use Strategic-Merge-Patch to handle types w/ schema
 		// TODO: Change this to use the new Merge package.
 		// Store the name of the target object, because this name may have been munged.
 		// Apply this name to the patched object.
-		lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(versionedObj)
-		if err != nil {
-			return err
-		}
 		merged, err = strategicpatch.StrategicMergeMapPatchUsingLookupPatchMeta(
 			fs.Map(),
 			patch.Map(),
@@ -346,6 +349,26 @@ func (fs *UnstructAdapter) Patch(patch ifc.Kunstructured) error {
 	return nil
 }
 
+// lookupPatchMeta returns the strategic merge patch
+// metadata of the given type, from the OpenAPI schema
+// s if it has the type, else from the compiled in API
+// types.  It returns nil for types without a schema,
+// e.g. custom resources, which get a JSON merge patch.
+func lookupPatchMeta(
+	s *openapi.Schema, x gvk.Gvk) (strategicpatch.LookupPatchMeta, error) {
+	if s := s.Lookup(x); s != nil {
+		return strategicpatch.NewPatchMetaFromOpenAPI(s), nil
+	}
+	versionedObj, err := scheme.Scheme.New(toSchemaGvk(x))
+	if runtime.IsNotRegisteredError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strategicpatch.NewPatchMetaFromStruct(versionedObj)
+}
+
 // toSchemaGvk converts to a schema.GroupVersionKind.
 func toSchemaGvk(x gvk.Gvk) schema.GroupVersionKind {
 	return schema.GroupVersionKind{
@@ -0,0 +1,366 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kunstruct
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	yamlv2 "gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/openapi"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// headFields are the top level fields a lazyAdapter
+// holds before the rest of the document is decoded.
+var headFields = []string{"apiVersion", "kind", "metadata"}
+
+// head receives the headFields of a document.
+type head struct {
+	APIVersion interface{} `yaml:"apiVersion,omitempty"`
+	Kind       interface{} `yaml:"kind,omitempty"`
+	Metadata   interface{} `yaml:"metadata,omitempty"`
+}
+
+// lazyAdapter is a Kunstructured that decodes only the
+// apiVersion, kind and metadata of a YAML document up front.
+// Identity, label and annotation accessors are served from
+// that head; anything else decodes the whole document once,
+// keeping whatever changes were made to the head meanwhile.
+// Most builds leave large resources like CRDs untouched
+// beyond their metadata, so they're never fully decoded.
+// The document itself is kept, as read, for Original.
+type lazyAdapter struct {
+	original []byte
+	raw      []byte
+	head     *UnstructAdapter
+	full     *UnstructAdapter
+	// err is why the rest of the document couldn't be
+	// decoded.  Only the head is left then, so getters,
+	// Patch and MarshalJSON fail with it from then on,
+	// even after SetMap, which can only have been given
+	// what Map returned, rather than the document.
+	err error
+}
+
+// explicitKey matches the indicator of a YAML mapping
+// key given explicitly, e.g. "? [x, y]", as keys that
+// JSON can't have are.
+var explicitKey = regexp.MustCompile(`(?m)^[ \t-]*\?[ \t]`)
+
+var _ ifc.Kunstructured = &lazyAdapter{}
+
+// newLazyAdapter returns a lazyAdapter for the YAML document
+// in doc, or false if the document's syntax is invalid, it
+// has no kind, or it may not convert to JSON, in which case
+// decoding it eagerly gives the right error or skips it.
+func newLazyAdapter(doc []byte) (*lazyAdapter, bool) {
+	if explicitKey.Match(doc) {
+		return nil, false
+	}
+	var h head
+	if yamlv2.Unmarshal(doc, &h) != nil || h.Kind == nil {
+		return nil, false
+	}
+	y, err := yamlv2.Marshal(h)
+	if err != nil {
+		return nil, false
+	}
+	j, err := sigsyaml.YAMLToJSON(y)
+	if err != nil {
+		return nil, false
+	}
+	var u unstructured.Unstructured
+	if u.UnmarshalJSON(j) != nil {
+		return nil, false
+	}
+	return &lazyAdapter{
+		original: doc, raw: doc,
+		head: &UnstructAdapter{Unstructured: u}}, true
+}
+
+// Original returns the YAML document the adapter was
+// read from, however it's been changed since.
+func (l *lazyAdapter) Original() []byte {
+	return l.original
+}
+
+// current returns the adapter accessors should use
+// without forcing the document to be decoded.
+func (l *lazyAdapter) current() *UnstructAdapter {
+	if l.full != nil {
+		return l.full
+	}
+	return l.head
+}
+
+// decoded returns the fully decoded document, or, if it
+// couldn't be decoded, its head, and why.
+func (l *lazyAdapter) decoded() (*UnstructAdapter, error) {
+	if l.full != nil {
+		return l.full, l.err
+	}
+	var u unstructured.Unstructured
+	// The document already parsed as YAML in newLazyAdapter,
+	// so only one that doesn't convert to JSON, e.g. with a
+	// key that's a list, or a value of .inf, fails here.
+	err := yaml.NewYAMLOrJSONDecoder(
+		bytes.NewReader(l.raw), 1024).Decode(&u)
+	if err != nil {
+		l.err = fmt.Errorf("decoding %s %s: %v",
+			l.head.GetKind(), l.head.GetName(), err)
+		u.Object = map[string]interface{}{}
+	}
+	for _, f := range headFields {
+		if v, ok := l.head.Object[f]; ok {
+			u.Object[f] = v
+		} else {
+			delete(u.Object, f)
+		}
+	}
+	l.full = &UnstructAdapter{Unstructured: u}
+	l.head = nil
+	l.raw = nil
+	return l.full, l.err
+}
+
+// inHead is true if path starts with one of the headFields.
+func inHead(path string) bool {
+	first := path
+	if i := strings.IndexAny(path, ".["); i >= 0 {
+		first = path[:i]
+	}
+	for _, f := range headFields {
+		if first == f {
+			return true
+		}
+	}
+	return false
+}
+
+// forPath returns the adapter that can answer for path.
+func (l *lazyAdapter) forPath(path string) (*UnstructAdapter, error) {
+	if l.full == nil && inHead(path) {
+		return l.head, nil
+	}
+	return l.decoded()
+}
+
+// Copy provides a copy behind an interface, leaving
+// the copy just as lazy as the original.
+func (l *lazyAdapter) Copy() ifc.Kunstructured {
+	if l.full != nil {
+		return &lazyAdapter{
+			original: l.original,
+			full:     l.full.Copy().(*UnstructAdapter),
+			err:      l.err}
+	}
+	return &lazyAdapter{
+		original: l.original, raw: l.raw,
+		head: l.head.Copy().(*UnstructAdapter)}
+}
+
+// Map returns the unstructured content map, only the
+// head of it if the document couldn't be decoded.
+func (l *lazyAdapter) Map() map[string]interface{} {
+	u, _ := l.decoded()
+	return u.Map()
+}
+
+// SetMap overrides the unstructured content map, but
+// not why the document couldn't be decoded, if it
+// couldn't.
+func (l *lazyAdapter) SetMap(m map[string]interface{}) {
+	l.full = &UnstructAdapter{Unstructured: unstructured.Unstructured{Object: m}}
+	l.head = nil
+	l.raw = nil
+}
+
+// GetFieldValue returns the value at the given fieldpath.
+func (l *lazyAdapter) GetFieldValue(path string) (interface{}, error) {
+	u, err := l.forPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return u.GetFieldValue(path)
+}
+
+// GetString returns value at the given fieldpath.
+func (l *lazyAdapter) GetString(path string) (string, error) {
+	u, err := l.forPath(path)
+	if err != nil {
+		return "", err
+	}
+	return u.GetString(path)
+}
+
+// GetStringSlice returns value at the given fieldpath.
+func (l *lazyAdapter) GetStringSlice(path string) ([]string, error) {
+	u, err := l.forPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return u.GetStringSlice(path)
+}
+
+// GetBool returns value at the given fieldpath.
+func (l *lazyAdapter) GetBool(path string) (bool, error) {
+	u, err := l.forPath(path)
+	if err != nil {
+		return false, err
+	}
+	return u.GetBool(path)
+}
+
+// GetFloat64 returns value at the given fieldpath.
+func (l *lazyAdapter) GetFloat64(path string) (float64, error) {
+	u, err := l.forPath(path)
+	if err != nil {
+		return 0, err
+	}
+	return u.GetFloat64(path)
+}
+
+// GetInt64 returns value at the given fieldpath.
+func (l *lazyAdapter) GetInt64(path string) (int64, error) {
+	u, err := l.forPath(path)
+	if err != nil {
+		return 0, err
+	}
+	return u.GetInt64(path)
+}
+
+// GetSlice returns value at the given fieldpath.
+func (l *lazyAdapter) GetSlice(path string) ([]interface{}, error) {
+	u, err := l.forPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return u.GetSlice(path)
+}
+
+// GetStringMap returns value at the given fieldpath.
+func (l *lazyAdapter) GetStringMap(path string) (map[string]string, error) {
+	u, err := l.forPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return u.GetStringMap(path)
+}
+
+// GetMap returns value at the given fieldpath.
+func (l *lazyAdapter) GetMap(path string) (map[string]interface{}, error) {
+	u, err := l.forPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return u.GetMap(path)
+}
+
+// MarshalJSON encodes the whole document.
+func (l *lazyAdapter) MarshalJSON() ([]byte, error) {
+	u, err := l.decoded()
+	if err != nil {
+		return nil, err
+	}
+	return u.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the whole document.
+func (l *lazyAdapter) UnmarshalJSON(b []byte) error {
+	u := &UnstructAdapter{}
+	if err := u.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	l.full = u
+	l.head = nil
+	l.raw = nil
+	l.err = nil
+	return nil
+}
+
+// GetGvk returns the Gvk name of the object.
+func (l *lazyAdapter) GetGvk() gvk.Gvk {
+	return l.current().GetGvk()
+}
+
+// SetGvk set the Gvk of the object to the input Gvk
+func (l *lazyAdapter) SetGvk(g gvk.Gvk) {
+	l.current().SetGvk(g)
+}
+
+// GetKind returns the kind of the object.
+func (l *lazyAdapter) GetKind() string {
+	return l.current().GetKind()
+}
+
+// GetName returns the name of the object.
+func (l *lazyAdapter) GetName() string {
+	return l.current().GetName()
+}
+
+// SetName sets the name of the object.
+func (l *lazyAdapter) SetName(n string) {
+	l.current().SetName(n)
+}
+
+// SetNamespace sets the namespace of the object.
+func (l *lazyAdapter) SetNamespace(n string) {
+	l.current().SetNamespace(n)
+}
+
+// GetLabels returns the labels of the object.
+func (l *lazyAdapter) GetLabels() map[string]string {
+	return l.current().GetLabels()
+}
+
+// SetLabels sets the labels of the object.
+func (l *lazyAdapter) SetLabels(m map[string]string) {
+	l.current().SetLabels(m)
+}
+
+// GetAnnotations returns the annotations of the object.
+func (l *lazyAdapter) GetAnnotations() map[string]string {
+	return l.current().GetAnnotations()
+}
+
+// SetAnnotations sets the annotations of the object.
+func (l *lazyAdapter) SetAnnotations(m map[string]string) {
+	l.current().SetAnnotations(m)
+}
+
+// MatchesLabelSelector returns true on a label selector match.
+func (l *lazyAdapter) MatchesLabelSelector(selector string) (bool, error) {
+	return l.current().MatchesLabelSelector(selector)
+}
+
+// MatchesAnnotationSelector returns true on an annotation selector match.
+func (l *lazyAdapter) MatchesAnnotationSelector(selector string) (bool, error) {
+	return l.current().MatchesAnnotationSelector(selector)
+}
+
+// Patch applies a strategic merge patch to the whole document.
+func (l *lazyAdapter) Patch(patch ifc.Kunstructured) error {
+	u, err := l.decoded()
+	if err != nil {
+		return err
+	}
+	return u.Patch(patch)
+}
+
+// PatchWithSchema is Patch, with the given schema.
+func (l *lazyAdapter) PatchWithSchema(
+	patch ifc.Kunstructured, s *openapi.Schema) error {
+	u, err := l.decoded()
+	if err != nil {
+		return err
+	}
+	return u.PatchWithSchema(patch, s)
+}
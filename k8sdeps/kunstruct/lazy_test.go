@@ -0,0 +1,153 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kunstruct
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const crd = `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+  labels:
+    app: cron
+spec:
+  group: stable.example.com
+  versions:
+  - name: v1
+    served: true
+    storage: true
+  scope: Namespaced
+`
+
+func lazyFromBytes(t *testing.T, in string) *lazyAdapter {
+	rs, err := NewKunstructuredFactoryImpl().SliceFromBytes([]byte(in))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rs) != 1 {
+		t.Fatalf("expected one object, got %d", len(rs))
+	}
+	l, ok := rs[0].(*lazyAdapter)
+	if !ok {
+		t.Fatalf("expected a lazyAdapter, got %T", rs[0])
+	}
+	return l
+}
+
+func TestLazyAdapterServesHeadWithoutDecoding(t *testing.T) {
+	l := lazyFromBytes(t, crd)
+	if l.GetKind() != "CustomResourceDefinition" ||
+		l.GetName() != "crontabs.stable.example.com" ||
+		l.GetGvk().Group != "apiextensions.k8s.io" {
+		t.Fatalf("unexpected identity %v %s", l.GetGvk(), l.GetName())
+	}
+	if ok, _ := l.MatchesLabelSelector("app=cron"); !ok {
+		t.Fatalf("expected label selector to match")
+	}
+	if s, _ := l.GetString("metadata.labels.app"); s != "cron" {
+		t.Fatalf("unexpected label %q", s)
+	}
+	l.SetNamespace("jobs")
+	l.SetLabels(map[string]string{"app": "cron", "team": "a"})
+	c := l.Copy().(*lazyAdapter)
+	if l.full != nil || c.full != nil {
+		t.Fatalf("expected document to stay undecoded")
+	}
+	if s, _ := l.GetString("spec.scope"); s != "Namespaced" {
+		t.Fatalf("unexpected scope %q", s)
+	}
+	if l.full == nil {
+		t.Fatalf("expected document to be decoded")
+	}
+	if c.full != nil {
+		t.Fatalf("expected copy to stay undecoded")
+	}
+	expected := map[string]interface{}{
+		"name":      "crontabs.stable.example.com",
+		"namespace": "jobs",
+		"labels": map[string]interface{}{
+			"app":  "cron",
+			"team": "a",
+		},
+	}
+	for _, x := range []*lazyAdapter{l, c} {
+		if !reflect.DeepEqual(x.Map()["metadata"], expected) {
+			t.Fatalf("unexpected metadata %v", x.Map()["metadata"])
+		}
+		if _, ok := x.Map()["spec"]; !ok {
+			t.Fatalf("expected spec in %v", x.Map())
+		}
+	}
+}
+
+func TestLazyAdapterMatchesEagerDecoding(t *testing.T) {
+	l := lazyFromBytes(t, crd)
+	eager, err := NewKunstructuredFactoryImpl().(*KunstructuredFactoryImpl).
+		sliceFromBytesEagerly([]byte(crd))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(l.Map(), eager[0].Map()) {
+		t.Fatalf("expected %v, got %v", eager[0].Map(), l.Map())
+	}
+}
+
+func TestSliceFromBytesDecodesJSONEagerly(t *testing.T) {
+	rs, err := NewKunstructuredFactoryImpl().SliceFromBytes([]byte(
+		`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "winnie"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := rs[0].(*UnstructAdapter); !ok {
+		t.Fatalf("expected an UnstructAdapter, got %T", rs[0])
+	}
+}
+
+func TestSliceFromBytesFailsOnKeysJSONCantHave(t *testing.T) {
+	_, err := NewKunstructuredFactoryImpl().SliceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: winnie
+data:
+  ? [x, y]
+  : z
+  keep: me
+`))
+	if err == nil || !strings.Contains(err.Error(), "invalid map key") {
+		t.Fatalf("expected an invalid map key error, got %v", err)
+	}
+}
+
+func TestLazyAdapterKeepsDecodingError(t *testing.T) {
+	l := lazyFromBytes(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: winnie
+data: {[x, y]: z, keep: me}
+`)
+	if l.GetName() != "winnie" {
+		t.Fatalf("unexpected name %q", l.GetName())
+	}
+	if _, err := l.GetString("data.keep"); err == nil ||
+		!strings.Contains(err.Error(), "ConfigMap winnie") {
+		t.Fatalf("expected a decoding error, got %v", err)
+	}
+	if _, err := l.MarshalJSON(); err == nil {
+		t.Fatalf("expected MarshalJSON to fail")
+	}
+	l.SetMap(l.Map())
+	if _, err := l.MarshalJSON(); err == nil {
+		t.Fatalf("expected MarshalJSON to fail after SetMap")
+	}
+	if _, err := l.Copy().MarshalJSON(); err == nil {
+		t.Fatalf("expected MarshalJSON of a copy to fail")
+	}
+}
@@ -7,13 +7,16 @@ package validator
 
 import (
 	"errors"
-	"fmt"
 	"strings"
 
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/api/validation/path"
 	v1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
 )
 
 // KustValidator validates Labels and annotations by apimachinery
@@ -26,18 +29,16 @@ func NewKustValidator() *KustValidator {
 
 func (v *KustValidator) ErrIfInvalidKey(k string) error {
 	if errs := validation.IsConfigMapKey(k); len(errs) != 0 {
-		return fmt.Errorf(
-			"%q is not a valid key name: %s",
-			k, strings.Join(errs, ";"))
+		return validationErr(messages.Errorf(
+			messages.InvalidKey, k, strings.Join(errs, ";")))
 	}
 	return nil
 }
 
 func (v *KustValidator) IsEnvVarName(k string) error {
 	if errs := validation.IsEnvVarName(k); len(errs) != 0 {
-		return fmt.Errorf(
-			"%q is not a valid key name: %s",
-			k, strings.Join(errs, ";"))
+		return validationErr(messages.Errorf(
+			messages.InvalidKey, k, strings.Join(errs, ";")))
 	}
 	return nil
 }
@@ -47,7 +48,8 @@ func (v *KustValidator) MakeAnnotationValidator() func(map[string]string) error
 	return func(x map[string]string) error {
 		errs := apivalidation.ValidateAnnotations(x, field.NewPath("field"))
 		if len(errs) > 0 {
-			return errors.New(errs.ToAggregate().Error())
+			return validationErr(messages.WithID(messages.InvalidAnnotations,
+				errors.New(errs.ToAggregate().Error())))
 		}
 		return nil
 	}
@@ -64,7 +66,8 @@ func (v *KustValidator) MakeAnnotationNameValidator() func([]string) error {
 			}
 		}
 		if len(errs) > 0 {
-			return errors.New(errs.ToAggregate().Error())
+			return validationErr(messages.WithID(messages.InvalidAnnotations,
+				errors.New(errs.ToAggregate().Error())))
 		}
 		return nil
 	}
@@ -75,7 +78,8 @@ func (v *KustValidator) MakeLabelValidator() func(map[string]string) error {
 	return func(x map[string]string) error {
 		errs := v1validation.ValidateLabels(x, field.NewPath("field"))
 		if len(errs) > 0 {
-			return errors.New(errs.ToAggregate().Error())
+			return validationErr(messages.WithID(messages.InvalidLabels,
+				errors.New(errs.ToAggregate().Error())))
 		}
 		return nil
 	}
@@ -90,7 +94,8 @@ func (v *KustValidator) MakeLabelNameValidator() func([]string) error {
 			errs = append(errs, v1validation.ValidateLabelName(k, fldPath)...)
 		}
 		if len(errs) > 0 {
-			return errors.New(errs.ToAggregate().Error())
+			return validationErr(messages.WithID(messages.InvalidLabels,
+				errors.New(errs.ToAggregate().Error())))
 		}
 		return nil
 	}
@@ -100,3 +105,17 @@ func (v *KustValidator) MakeLabelNameValidator() func([]string) error {
 func (v *KustValidator) ValidateNamespace(s string) []string {
 	return validation.IsDNS1123Label(s)
 }
+
+// ValidateName validates the name of a resource of the
+// given gvk using apimachinery; most must be DNS subdomains,
+// but RBAC's need only be path segments, e.g. system:admin.
+func (v *KustValidator) ValidateName(x gvk.Gvk, s string) []string {
+	if x.Group == "rbac.authorization.k8s.io" {
+		return path.IsValidPathSegmentName(s)
+	}
+	return validation.IsDNS1123Subdomain(s)
+}
+
+func validationErr(err error) error {
+	return kusterr.WithClass(kusterr.ClassValidation, err)
+}
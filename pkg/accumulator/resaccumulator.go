@@ -22,6 +22,12 @@ type ResAccumulator struct {
 	resMap  resmap.ResMap
 	tConfig *config.TransformerConfig
 	varSet  types.VarSet
+	// values replace $(name), as vars do, but with
+	// values given rather than taken from resources.
+	values map[string]string
+	// failOnUnusedVars makes ResolveVars fail, rather
+	// than warn, if vars are never replaced.
+	failOnUnusedVars bool
 }
 
 func MakeEmptyAccumulator() *ResAccumulator {
@@ -29,6 +35,7 @@ func MakeEmptyAccumulator() *ResAccumulator {
 	ra.resMap = resmap.New()
 	ra.tConfig = &config.TransformerConfig{}
 	ra.varSet = types.NewVarSet()
+	ra.values = make(map[string]string)
 	return ra
 }
 
@@ -85,6 +92,14 @@ func (ra *ResAccumulator) MergeVars(incoming []types.Var) error {
 	return ra.varSet.MergeSlice(incoming)
 }
 
+// SetValues sets values, e.g. those of a kustomization,
+// replacing any of the same name, e.g. of its bases.
+func (ra *ResAccumulator) SetValues(values map[string]string) {
+	for k, v := range values {
+		ra.values[k] = v
+	}
+}
+
 func (ra *ResAccumulator) MergeAccumulator(other *ResAccumulator) (err error) {
 	err = ra.AppendAll(other.resMap)
 	if err != nil {
@@ -94,6 +109,14 @@ func (ra *ResAccumulator) MergeAccumulator(other *ResAccumulator) (err error) {
 	if err != nil {
 		return err
 	}
+	for k, v := range other.values {
+		if old, ok := ra.values[k]; ok && old != v {
+			return fmt.Errorf(
+				"value %s is '%s' in one base and '%s' in another",
+				k, old, v)
+		}
+		ra.values[k] = v
+	}
 	return ra.varSet.MergeSet(other.varSet)
 }
 
@@ -123,7 +146,14 @@ func (ra *ResAccumulator) findVarValueFromResources(v types.Var) (interface{}, e
 // for substitution wherever the $(var.Name) occurs.
 func (ra *ResAccumulator) makeVarReplacementMap() (map[string]interface{}, error) {
 	result := map[string]interface{}{}
+	for k, v := range ra.values {
+		result[k] = v
+	}
 	for _, v := range ra.Vars() {
+		if _, ok := ra.values[v.Name]; ok {
+			return nil, fmt.Errorf(
+				"var '%s' has the name of a value", v.Name)
+		}
 		s, err := ra.findVarValueFromResources(v)
 		if err != nil {
 			return nil, err
@@ -139,6 +169,12 @@ func (ra *ResAccumulator) Transform(t transformers.Transformer) error {
 	return t.Transform(ra.resMap)
 }
 
+// FailOnUnusedVars makes ResolveVars fail, rather than
+// warn, if some vars are never replaced.
+func (ra *ResAccumulator) FailOnUnusedVars() {
+	ra.failOnUnusedVars = true
+}
+
 func (ra *ResAccumulator) ResolveVars() error {
 	replacementMap, err := ra.makeVarReplacementMap()
 	if err != nil {
@@ -150,10 +186,22 @@ func (ra *ResAccumulator) ResolveVars() error {
 	t := transformers.NewRefVarTransformer(
 		replacementMap, ra.tConfig.VarReference)
 	err = ra.Transform(t)
-	if len(t.UnusedVars()) > 0 {
+	var unused []string
+	for _, name := range t.UnusedVars() {
+		// Values are shared, so needn't all be used.
+		if _, ok := ra.values[name]; !ok {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		if err == nil && ra.failOnUnusedVars {
+			return fmt.Errorf(
+				"well-defined vars that were never replaced: %s",
+				strings.Join(unused, ","))
+		}
 		log.Printf(
 			"well-defined vars that were never replaced: %s\n",
-			strings.Join(t.UnusedVars(), ","))
+			strings.Join(unused, ","))
 	}
 	return err
 }
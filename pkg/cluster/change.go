@@ -0,0 +1,147 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind classifies the change applying an
+// object makes to the cluster, by risk.
+type ChangeKind int
+
+const (
+	// ChangeNone leaves the object as it is.
+	ChangeNone ChangeKind = iota
+	// ChangeMetadata changes only metadata, e.g. labels.
+	ChangeMetadata
+	// ChangeUpdate changes the object in place.
+	ChangeUpdate
+	// ChangeNew creates the object.
+	ChangeNew
+	// ChangeRecreate changes immutable fields, so the
+	// object must be deleted and created again.
+	ChangeRecreate
+	// ChangeDelete deletes the object, on pruning.
+	ChangeDelete
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeNone:
+		return "none"
+	case ChangeMetadata:
+		return "metadata"
+	case ChangeUpdate:
+		return "update"
+	case ChangeNew:
+		return "new"
+	case ChangeRecreate:
+		return "recreate"
+	case ChangeDelete:
+		return "delete"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// immutableFields are, per kind, the fields the
+// API server refuses to update.
+var immutableFields = map[string][]string{
+	"DaemonSet":  {"spec.selector"},
+	"Deployment": {"spec.selector"},
+	"Job":        {"spec.selector", "spec.template"},
+	"PersistentVolumeClaim": {
+		"spec.accessModes", "spec.selector",
+		"spec.storageClassName", "spec.volumeName"},
+	"ReplicaSet": {"spec.selector"},
+	"Secret":     {"type"},
+	"Service":    {"spec.clusterIP"},
+	"StatefulSet": {
+		"spec.podManagementPolicy", "spec.selector",
+		"spec.serviceName", "spec.volumeClaimTemplates"},
+}
+
+// Classify returns how applying the desired object
+// changes the live one, nil if absent, and the fields
+// it changes.  Only fields of the desired object are
+// compared, since the live one also has fields set
+// by the cluster, e.g. status and defaults.
+func Classify(
+	kind string, desired, live map[string]interface{}) (ChangeKind, []string) {
+	if live == nil {
+		return ChangeNew, nil
+	}
+	var fields []string
+	changedFields("", desired, live, &fields)
+	if len(fields) == 0 {
+		return ChangeNone, nil
+	}
+	result := ChangeMetadata
+	for _, f := range fields {
+		if isImmutable(kind, f, live) {
+			return ChangeRecreate, fields
+		}
+		if !strings.HasPrefix(f, "metadata.") {
+			result = ChangeUpdate
+		}
+	}
+	return result, fields
+}
+
+func isImmutable(kind, field string, live map[string]interface{}) bool {
+	if (kind == "ConfigMap" || kind == "Secret") && live["immutable"] == true {
+		return !strings.HasPrefix(field, "metadata.")
+	}
+	for _, p := range immutableFields[kind] {
+		if field == p ||
+			strings.HasPrefix(field, p+".") ||
+			strings.HasPrefix(field, p+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// changedFields appends the paths of the leaf fields
+// of desired whose values differ in live.
+func changedFields(
+	path string, desired, live interface{}, fields *[]string) {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			*fields = append(*fields, path)
+			return
+		}
+		var keys []string
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			changedFields(p, d[k], l[k], fields)
+		}
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			*fields = append(*fields, path)
+			return
+		}
+		for i := range d {
+			changedFields(
+				fmt.Sprintf("%s[%d]", path, i), d[i], l[i], fields)
+		}
+	default:
+		// Numbers may be decoded as ints or floats.
+		if fmt.Sprint(desired) != fmt.Sprint(live) {
+			*fields = append(*fields, path)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestClassify(t *testing.T) {
+	live := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+  uid: 1234
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: web
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:1
+        terminationMessagePath: /dev/termination-log
+status:
+  replicas: 1
+`
+	tests := map[string]struct {
+		kind     string
+		desired  string
+		live     string
+		expected ChangeKind
+		fields   []string
+	}{
+		"new": {
+			kind:     "Deployment",
+			desired:  `metadata: {name: web}`,
+			expected: ChangeNew,
+		},
+		"none": {
+			kind: "Deployment",
+			desired: `
+metadata: {name: web}
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:1
+`,
+			live:     live,
+			expected: ChangeNone,
+		},
+		"metadata": {
+			kind:     "Deployment",
+			desired:  `metadata: {name: web, labels: {app: web2}}`,
+			live:     live,
+			expected: ChangeMetadata,
+			fields:   []string{"metadata.labels.app"},
+		},
+		"update": {
+			kind: "Deployment",
+			desired: `
+metadata: {name: web, labels: {app: web2}}
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:2
+`,
+			live:     live,
+			expected: ChangeUpdate,
+			fields: []string{
+				"metadata.labels.app",
+				"spec.template.spec.containers[0].image"},
+		},
+		"recreate": {
+			kind: "Deployment",
+			desired: `
+metadata: {name: web}
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: web2
+`,
+			live:     live,
+			expected: ChangeRecreate,
+			fields: []string{
+				"spec.replicas", "spec.selector.matchLabels.app"},
+		},
+		"immutable ConfigMap": {
+			kind:     "ConfigMap",
+			desired:  `{metadata: {name: c}, data: {a: b}}`,
+			live:     `{metadata: {name: c}, data: {a: c}, immutable: true}`,
+			expected: ChangeRecreate,
+			fields:   []string{"data.a"},
+		},
+	}
+	for n, tc := range tests {
+		var desired, l map[string]interface{}
+		if err := yaml.Unmarshal([]byte(tc.desired), &desired); err != nil {
+			t.Fatalf("%s: %v", n, err)
+		}
+		if tc.live != "" {
+			if err := yaml.Unmarshal([]byte(tc.live), &l); err != nil {
+				t.Fatalf("%s: %v", n, err)
+			}
+		}
+		kind, fields := Classify(tc.kind, desired, l)
+		if kind != tc.expected || !reflect.DeepEqual(fields, tc.fields) {
+			t.Fatalf("%s: expected %s %v, got %s %v",
+				n, tc.expected, tc.fields, kind, fields)
+		}
+	}
+}
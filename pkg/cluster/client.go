@@ -0,0 +1,248 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cluster reads and writes objects in a
+// Kubernetes cluster with client-go.
+package cluster
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+// Client is a cluster reached by client-go.
+type Client struct {
+	dynamic dynamic.Interface
+	mapper  meta.RESTMapper
+	// namespace is that of objects without one.
+	namespace string
+	// poll is how often Wait checks a rollout.
+	poll time.Duration
+}
+
+// NewClient returns a Client of the cluster of the
+// kubeconfig context, or the current one if empty,
+// with the kubeconfig kubectl would use.
+func NewClient(context string) (*Client, error) {
+	config := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: context})
+	rc, err := config.ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading kubeconfig")
+	}
+	namespace, _, err := config.Namespace()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading kubeconfig")
+	}
+	d, err := dynamic.NewForConfig(rc)
+	if err != nil {
+		return nil, err
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(rc)
+	if err != nil {
+		return nil, err
+	}
+	// The cluster's APIs are discovered on first use.
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(
+		memory.NewMemCacheClient(dc))
+	return NewClientFor(d, mapper, namespace), nil
+}
+
+// NewClientFor returns a Client using the dynamic
+// client, finding the resource of each kind with
+// mapper, and putting objects without a namespace
+// in namespace.
+func NewClientFor(
+	d dynamic.Interface, mapper meta.RESTMapper,
+	namespace string) *Client {
+	return &Client{
+		dynamic:   d,
+		mapper:    mapper,
+		namespace: namespace,
+		poll:      2 * time.Second,
+	}
+}
+
+// resource returns the client of the object's resource.
+func (c *Client) resource(id resid.ResId) (dynamic.ResourceInterface, error) {
+	m, err := c.mapper.RESTMapping(
+		schema.GroupKind{Group: id.Group, Kind: id.Kind}, id.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding the resource of %s", id)
+	}
+	if m.Scope.Name() != meta.RESTScopeNameNamespace {
+		return c.dynamic.Resource(m.Resource), nil
+	}
+	namespace := id.Namespace
+	if namespace == "" {
+		namespace = c.namespace
+	}
+	return c.dynamic.Resource(m.Resource).Namespace(namespace), nil
+}
+
+// Apply applies the objects server side, as the field
+// manager, taking over fields other managers own, as
+// client side apply does.
+func (c *Client) Apply(m resmap.ResMap, fieldManager string) error {
+	force := true
+	for _, r := range m.Resources() {
+		id := r.CurId()
+		ri, err := c.resource(id)
+		if err != nil {
+			return err
+		}
+		data, err := r.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		_, err = ri.Patch(
+			id.Name, types.ApplyPatchType, data,
+			metav1.PatchOptions{FieldManager: fieldManager, Force: &force})
+		if err != nil {
+			return errors.Wrapf(err, "applying %s", id)
+		}
+	}
+	return nil
+}
+
+// Get returns the object as JSON, or nil if it
+// doesn't exist.
+func (c *Client) Get(id resid.ResId) ([]byte, error) {
+	ri, err := c.resource(id)
+	if err != nil {
+		return nil, err
+	}
+	u, err := ri.Get(id.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting %s", id)
+	}
+	return u.MarshalJSON()
+}
+
+// Delete deletes the object, if it exists, and
+// then, in the background, the objects it owns.
+func (c *Client) Delete(id resid.ResId) error {
+	ri, err := c.resource(id)
+	if err != nil {
+		return err
+	}
+	policy := metav1.DeletePropagationBackground
+	err = ri.Delete(id.Name, &metav1.DeleteOptions{PropagationPolicy: &policy})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "deleting %s", id)
+	}
+	return nil
+}
+
+// HasRollout returns true if the object's kind
+// rolls out pods, whose completion Wait awaits.
+func HasRollout(id resid.ResId) bool {
+	switch id.Kind {
+	case "Deployment", "StatefulSet", "DaemonSet":
+		return true
+	}
+	return false
+}
+
+// Wait waits up to the timeout, or, if 0, for as
+// long as it takes, for the rollout of the object
+// to complete.
+func (c *Client) Wait(id resid.ResId, timeout time.Duration) error {
+	ri, err := c.resource(id)
+	if err != nil {
+		return err
+	}
+	err = wait.PollImmediate(c.poll, timeout, func() (bool, error) {
+		u, err := ri.Get(id.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return rolledOut(u)
+	})
+	if err == wait.ErrWaitTimeout {
+		return errors.Errorf(
+			"rollout of %s not complete after %s", id, timeout)
+	}
+	return errors.Wrapf(err, "waiting for the rollout of %s", id)
+}
+
+// rolledOut returns true if all the pods of the
+// object are updated and available, as 'kubectl
+// rollout status' sees it.
+func rolledOut(u *unstructured.Unstructured) (bool, error) {
+	observed, _, _ := unstructured.NestedInt64(
+		u.Object, "status", "observedGeneration")
+	if observed < u.GetGeneration() {
+		return false, nil
+	}
+	status := func(field string) int64 {
+		n, _, _ := unstructured.NestedInt64(u.Object, "status", field)
+		return n
+	}
+	replicas := int64(1)
+	if n, ok, _ := unstructured.NestedInt64(
+		u.Object, "spec", "replicas"); ok {
+		replicas = n
+	}
+	switch u.GetKind() {
+	case "Deployment":
+		if progressDeadlineExceeded(u) {
+			return false, errors.Errorf(
+				"deployment %s exceeded its progress deadline", u.GetName())
+		}
+		return status("updatedReplicas") >= replicas &&
+			status("replicas") <= status("updatedReplicas") &&
+			status("availableReplicas") >= status("updatedReplicas"), nil
+	case "StatefulSet":
+		if status("readyReplicas") < replicas {
+			return false, nil
+		}
+		partition, ok, _ := unstructured.NestedInt64(u.Object,
+			"spec", "updateStrategy", "rollingUpdate", "partition")
+		if ok {
+			return status("updatedReplicas") >= replicas-partition, nil
+		}
+		current, _, _ := unstructured.NestedString(
+			u.Object, "status", "currentRevision")
+		update, _, _ := unstructured.NestedString(
+			u.Object, "status", "updateRevision")
+		return current == update, nil
+	case "DaemonSet":
+		desired := status("desiredNumberScheduled")
+		return status("updatedNumberScheduled") >= desired &&
+			status("numberAvailable") >= desired, nil
+	}
+	return true, nil
+}
+
+func progressDeadlineExceeded(u *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(
+		u.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if ok && m["type"] == "Progressing" &&
+			m["reason"] == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+const rolledOutDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: ns
+  generation: 2
+spec:
+  replicas: 2
+status:
+  observedGeneration: 2
+  replicas: 2
+  updatedReplicas: 2
+  availableReplicas: 2
+`
+
+func actions(d interface{ Actions() []k8stesting.Action }) []string {
+	var result []string
+	for _, a := range d.Actions() {
+		result = append(result, a.GetVerb()+" "+
+			a.GetResource().Resource+" "+a.GetNamespace())
+	}
+	return result
+}
+
+func TestClient(t *testing.T) {
+	c, d, err := NewFakeClient(rolledOutDeployment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deployment := resid.NewResIdWithNamespace(
+		gvk.Gvk{Group: "apps", Version: "v1", Kind: "Deployment"}, "web", "ns")
+	namespace := resid.NewResId(
+		gvk.Gvk{Version: "v1", Kind: "Namespace"}, "ns")
+
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	m, err := rf.NewResMapFromBytes([]byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ns
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Apply(m, "me"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := c.Get(namespace)
+	if err != nil || data != nil {
+		t.Fatalf("unexpected result: %s, %v", data, err)
+	}
+	data, err = c.Get(deployment)
+	if err != nil || !strings.Contains(string(data), `"updatedReplicas":2`) {
+		t.Fatalf("unexpected result: %s, %v", data, err)
+	}
+	if err := c.Wait(deployment, time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Delete(deployment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Delete(deployment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"patch namespaces ",
+		"get namespaces ",
+		"get deployments ns",
+		"get deployments ns",
+		"delete deployments ns",
+		"delete deployments ns",
+	}
+	if got := actions(d); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected:\n%s\ngot:\n%s",
+			strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+	patch := d.Actions()[0].(k8stesting.PatchAction)
+	if !strings.Contains(string(patch.GetPatch()), `"kind":"Namespace"`) {
+		t.Fatalf("unexpected patch %s", patch.GetPatch())
+	}
+}
+
+func TestWaitTimesOut(t *testing.T) {
+	c, _, err := NewFakeClient(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: ns
+spec:
+  replicas: 2
+status:
+  updatedReplicas: 1
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deployment := resid.NewResIdWithNamespace(
+		gvk.Gvk{Group: "apps", Version: "v1", Kind: "Deployment"}, "web", "ns")
+	err = c.Wait(deployment, 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "not complete") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRolledOut(t *testing.T) {
+	testCases := map[string]struct {
+		object   string
+		expected bool
+	}{
+		"deploymentNotObserved": {`
+kind: Deployment
+metadata:
+  generation: 3
+status:
+  observedGeneration: 2
+`, false},
+		"deploymentDone": {rolledOutDeployment, true},
+		"deploymentOldReplicas": {`
+kind: Deployment
+spec:
+  replicas: 2
+status:
+  replicas: 3
+  updatedReplicas: 2
+  availableReplicas: 2
+`, false},
+		"statefulSetPartitioned": {`
+kind: StatefulSet
+spec:
+  replicas: 3
+  updateStrategy:
+    rollingUpdate:
+      partition: 2
+status:
+  readyReplicas: 3
+  updatedReplicas: 1
+`, true},
+		"statefulSetUpdating": {`
+kind: StatefulSet
+spec:
+  replicas: 1
+status:
+  readyReplicas: 1
+  currentRevision: a
+  updateRevision: b
+`, false},
+		"daemonSetUnavailable": {`
+kind: DaemonSet
+status:
+  desiredNumberScheduled: 3
+  updatedNumberScheduled: 3
+  numberAvailable: 2
+`, false},
+	}
+	for n, tc := range testCases {
+		u, err := fromYAML(tc.object)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		actual, err := rolledOut(u)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		if actual != tc.expected {
+			t.Fatalf("%s: expected %v", n, tc.expected)
+		}
+	}
+}
+
+func TestHasRollout(t *testing.T) {
+	if !HasRollout(resid.NewResIdKindOnly("StatefulSet", "db")) ||
+		HasRollout(resid.NewResIdKindOnly("Service", "db")) {
+		t.Fatalf("unexpected rollout kinds")
+	}
+}
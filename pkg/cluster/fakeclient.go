@@ -0,0 +1,83 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+)
+
+// fakeKinds are the kinds a fake cluster serves,
+// besides those of its objects.
+var fakeKinds = []schema.GroupVersionKind{
+	{Version: "v1", Kind: "ConfigMap"},
+	{Version: "v1", Kind: "Secret"},
+	{Version: "v1", Kind: "Service"},
+	{Version: "v1", Kind: "ServiceAccount"},
+	{Version: "v1", Kind: "Namespace"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+}
+
+// NewFakeClient returns a Client of a cluster in memory
+// holding the YAML objects, for tests, and the fake
+// client-go client under it, which records each call.
+// Objects applied are returned, but not kept.
+func NewFakeClient(
+	objects ...string) (*Client, *fake.FakeDynamicClient, error) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	add := func(gvk schema.GroupVersionKind) {
+		scope := meta.RESTScopeNamespace
+		if gvk.Kind == "Namespace" {
+			scope = meta.RESTScopeRoot
+		}
+		mapper.Add(gvk, scope)
+	}
+	for _, gvk := range fakeKinds {
+		add(gvk)
+	}
+	var objs []runtime.Object
+	for _, y := range objects {
+		u, err := fromYAML(y)
+		if err != nil {
+			return nil, nil, err
+		}
+		add(u.GroupVersionKind())
+		objs = append(objs, u)
+	}
+	d := fake.NewSimpleDynamicClient(runtime.NewScheme(), objs...)
+	// The object tracker can't apply server side.
+	d.PrependReactor("patch", "*", func(
+		a k8stesting.Action) (bool, runtime.Object, error) {
+		p := a.(k8stesting.PatchAction)
+		if p.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		u := &unstructured.Unstructured{}
+		return true, u, u.UnmarshalJSON(p.GetPatch())
+	})
+	c := NewClientFor(d, mapper, "default")
+	c.poll = 10 * time.Millisecond
+	return c, d, nil
+}
+
+// fromYAML decodes the object as client-go would,
+// with integers as int64 rather than float64.
+func fromYAML(y string) (*unstructured.Unstructured, error) {
+	j, err := yaml.YAMLToJSON([]byte(y))
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{}
+	return u, u.UnmarshalJSON(j)
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/inventory"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// Prunable returns the objects of the inventory in the
+// cluster that the build no longer holds, nor refers to,
+// and records, in the inventory of the build, those that
+// it still refers to, so a later apply can prune them.
+func Prunable(
+	m resmap.ResMap, rf *resmap.Factory,
+	k *Client) ([]resid.ResId, error) {
+	invObj := FindInventory(m)
+	if invObj == nil {
+		return nil, errors.New("no inventory in the build")
+	}
+	current := inventory.NewInventory()
+	err := current.LoadFromAnnotation(invObj.GetAnnotations())
+	if err != nil {
+		return nil, err
+	}
+	inv := inventory.NewInventory()
+	data, err := k.Get(invObj.CurId())
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		old, err := rf.RF().FromBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		err = inv.LoadFromAnnotation(old.GetAnnotations())
+		if err != nil {
+			return nil, err
+		}
+	}
+	inv.UpdateCurrent(current.Current)
+	pruned := inv.Prune()
+	annotations := invObj.GetAnnotations()
+	err = inv.UpdateAnnotations(annotations)
+	if err != nil {
+		return nil, err
+	}
+	invObj.SetAnnotations(annotations)
+	return pruned, nil
+}
+
+// FindInventory returns the inventory object of
+// the build, or nil.
+func FindInventory(m resmap.ResMap) *resource.Resource {
+	for _, r := range m.Resources() {
+		if _, ok := r.GetAnnotations()[inventory.ContentAnnotation]; ok {
+			return r
+		}
+	}
+	return nil
+}
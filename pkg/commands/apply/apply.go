@@ -0,0 +1,176 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package apply builds a kustomization and applies
+// the result to a cluster.
+package apply
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/cluster"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/build"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+// Options contain the options for running apply.
+type Options struct {
+	build        *build.Options
+	fieldManager string
+	prune        bool
+	wait         bool
+	timeout      time.Duration
+}
+
+var examples = `
+To build 'someDir/kustomization.yaml' and apply the
+result, server side, to the cluster of the current
+kubeconfig context, run
+
+  kustomize apply someDir
+
+To also delete the objects applied before but no
+longer in the build, as recorded by the kustomization's
+inventory object, and wait for Deployments, StatefulSets
+and DaemonSets to roll out, run
+
+  kustomize apply someDir --prune --wait
+
+As a kubectl plugin, e.g. with a link to kustomize
+named kubectl-kustomize on the path, run
+
+  kubectl kustomize apply someDir
+
+The flags of 'kustomize build' saying how to build,
+e.g. --kube-version, --set or --strict, work the same
+here.  To apply to the cluster of the kubeconfig
+context prod, built for the context prod of
+contexts.yaml in kustomize's config directory, if it
+has one, run
+
+  kustomize apply --context prod
+
+The cluster is reached as kubectl would reach it,
+per $KUBECONFIG or ~/.kube/config.
+`
+
+// NewCmdApply creates a new apply command.
+func NewCmdApply(
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, rf *resmap.Factory,
+	ptf resmap.PatchFactory) *cobra.Command {
+	o := Options{build: build.NewOptions("", "")}
+
+	pluginConfig := plugins.DefaultPluginConfig()
+	pl := plugins.NewLoader(pluginConfig, rf)
+
+	cmd := &cobra.Command{
+		Use: "apply {path}",
+		Short: "Apply configuration per contents of " +
+			pgmconfig.KustomizationFileNames[0] + " to a cluster",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			k, err := cluster.NewClient(o.build.ContextName())
+			if err != nil {
+				return err
+			}
+			return o.RunApply(out, v, fSys, rf, ptf, pl, k)
+		},
+	}
+	cmd.Flags().StringVar(
+		&o.fieldManager,
+		"field-manager", "kustomize",
+		"The name of the manager of the fields applied.")
+	cmd.Flags().BoolVar(
+		&o.prune,
+		"prune", false,
+		"Delete the objects of the previous apply, per the "+
+			"inventory object, that are no longer in the build.")
+	cmd.Flags().BoolVar(
+		&o.wait,
+		"wait", false,
+		"Wait for Deployments, StatefulSets and DaemonSets to roll out.")
+	cmd.Flags().DurationVar(
+		&o.timeout,
+		"timeout", 5*time.Minute,
+		"How long --wait waits for each rollout; 0 for no limit.")
+	o.build.AddClusterFlags(cmd.Flags(), pluginConfig)
+	return cmd
+}
+
+// Validate validates apply command.
+func (o *Options) Validate(args []string) error {
+	if o.fieldManager == "" {
+		return errors.New("--field-manager may not be empty")
+	}
+	return o.build.Validate(args)
+}
+
+// RunApply builds the kustomization as build does,
+// applies the result, then prunes and waits, if asked to.
+func (o *Options) RunApply(
+	out io.Writer, v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader, k *cluster.Client) error {
+	objects, err := o.build.Render(v, fSys, rf, ptf, pl)
+	if err != nil {
+		return err
+	}
+	m, err := rf.NewResMapFromBytes(objects)
+	if err != nil {
+		return err
+	}
+	var pruned []resid.ResId
+	if o.prune {
+		if cluster.FindInventory(m) == nil {
+			return errors.New(
+				"--prune requires an inventory in the kustomization")
+		}
+		pruned, err = cluster.Prunable(m, rf, k)
+		if err != nil {
+			return err
+		}
+	}
+	err = k.Apply(m, o.fieldManager)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "applied %d objects\n", m.Size())
+	for _, id := range pruned {
+		err = k.Delete(id)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "pruned %s\n", id)
+	}
+	if !o.wait {
+		return nil
+	}
+	for _, r := range m.Resources() {
+		id := r.CurId()
+		if !cluster.HasRollout(id) {
+			continue
+		}
+		err = k.Wait(id, o.timeout)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "rolled out %s\n", id)
+	}
+	return nil
+}
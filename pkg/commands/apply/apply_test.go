@@ -0,0 +1,156 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/cluster"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/build"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+const oldInventory = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inv
+  namespace: prod
+  annotations:
+    kustomize.config.k8s.io/Inventory: '{"current":{"apps_v1_Deployment|prod|web":null,"~G_v1_Service|prod|old":null}}'
+`
+
+func TestRunApply(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+namespace: prod
+resources:
+- deployment.yaml
+inventory:
+  type: ConfigMap
+  configMap:
+    name: inv
+    namespace: prod
+`))
+	fSys.WriteFile("/app/deployment.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+
+	k, d, err := cluster.NewFakeClient(oldInventory, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+status:
+  updatedReplicas: 1
+  availableReplicas: 1
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	o := Options{
+		build:        build.NewOptions("", ""),
+		fieldManager: "kustomize",
+		prune:        true,
+		wait:         true,
+	}
+	if err := o.Validate([]string{"/app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = o.RunApply(&out, validator.NewKustValidator(), fSys, rf, pf, pl, k)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var calls []string
+	var applied []string
+	for _, a := range d.Actions() {
+		calls = append(calls, a.GetVerb()+" "+a.GetResource().Resource)
+		if p, ok := a.(k8stesting.PatchAction); ok {
+			applied = append(applied, string(p.GetPatch()))
+		}
+	}
+	expected := []string{
+		"get configmaps",
+		"patch configmaps",
+		"patch deployments",
+		"delete services",
+		"get deployments",
+	}
+	if strings.Join(calls, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected:\n%s\ngot:\n%s",
+			strings.Join(expected, "\n"), strings.Join(calls, "\n"))
+	}
+	if !strings.Contains(applied[1], `"kind":"Deployment"`) ||
+		strings.Contains(applied[0], "Service|prod|old") {
+		t.Fatalf("unexpected objects applied:\n%s", strings.Join(applied, "\n"))
+	}
+	if !strings.Contains(out.String(), "pruned ~G_v1_Service|prod|old") {
+		t.Fatalf("unexpected output %s", out.String())
+	}
+}
+
+func TestRunApplyPruneNeedsInventory(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources: []
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+	k, d, err := cluster.NewFakeClient()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	o := Options{
+		build:        build.NewOptions("", ""),
+		fieldManager: "kustomize",
+		prune:        true,
+	}
+	if err := o.Validate([]string{"/app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = o.RunApply(nil, validator.NewKustValidator(), fSys, rf, pf, pl, k)
+	if err == nil || !strings.Contains(err.Error(), "inventory") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(d.Actions()) > 0 {
+		t.Fatalf("unexpected calls %v", d.Actions())
+	}
+}
+
+func TestNewCmdApplyHasBuildFlags(t *testing.T) {
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	cmd := NewCmdApply(
+		nil, fs.MakeFakeFS(), validator.NewKustValidator(), rf, pf)
+	for _, name := range []string{
+		"kube-version", "set", "strict", "context",
+		"deprecated-apis", "enable-post-renderers", "load_restrictor"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Fatalf("no flag --%s", name)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/version"
+)
+
+const (
+	flagAttestName = "attest"
+	flagAttestHelp = "If specified, write to this path an in-toto " +
+		"provenance statement, signed with --" + flagAttestKeyName +
+		", binding the hash of the --output file to the inputs " +
+		"of the build."
+	flagAttestKeyName = "attest-key"
+	flagAttestKeyHelp = "PEM file holding the ECDSA private key " +
+		"signing the --" + flagAttestName + " statement; keyless " +
+		"signing, e.g. Sigstore's, isn't supported."
+
+	inTotoPayloadType = "application/vnd.in-toto+json"
+)
+
+// inTotoStatement is an in-toto v0.1 statement
+// with a SLSA v0.2 provenance predicate.
+type inTotoStatement struct {
+	Type          string          `json:"_type"`
+	Subject       []inTotoSubject `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     slsaProvenance  `json:"predicate"`
+}
+
+type inTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type slsaProvenance struct {
+	Builder    slsaBuilder    `json:"builder"`
+	BuildType  string         `json:"buildType"`
+	Invocation slsaInvocation `json:"invocation"`
+	Materials  []slsaMaterial `json:"materials"`
+}
+
+type slsaBuilder struct {
+	Id string `json:"id"`
+}
+
+type slsaInvocation struct {
+	Parameters map[string]string `json:"parameters"`
+}
+
+type slsaMaterial struct {
+	Uri    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
+
+// dsseEnvelope is a signed DSSE envelope.
+type dsseEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []dsseSignature `json:"signatures"`
+}
+
+type dsseSignature struct {
+	KeyId string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// attest writes the signed provenance of the output.
+func (o *Options) attest(
+	fSys fs.FileSystem, root string, r *inputRecorder,
+	plugins []pluginFile, manifest []byte) error {
+	if fSys.IsDir(o.outputPath) {
+		return fmt.Errorf(
+			"--%s requires --output to be a file", flagAttestName)
+	}
+	s, err := o.makeProvenance(fSys, root, r, plugins, manifest)
+	if err != nil {
+		return err
+	}
+	key, err := fSys.ReadFile(o.attestKeyPath)
+	if err != nil {
+		return err
+	}
+	envelope, err := signStatement(s, key)
+	if err != nil {
+		return err
+	}
+	return fSys.WriteFile(o.attestPath, envelope)
+}
+
+// makeProvenance returns the statement that the output
+// was built from the recorded inputs, and from the
+// inputs manifest, if written.  The inputs have the
+// digests the inputs manifest has.
+func (o *Options) makeProvenance(
+	fSys fs.FileSystem, root string, r *inputRecorder,
+	plugins []pluginFile, manifest []byte) (*inTotoStatement, error) {
+	output, err := fSys.ReadFile(o.outputPath)
+	if err != nil {
+		return nil, err
+	}
+	s := &inTotoStatement{
+		Type: "https://in-toto.io/Statement/v0.1",
+		Subject: []inTotoSubject{{
+			Name:   filepath.Base(o.outputPath),
+			Digest: sha256Digest(output),
+		}},
+		PredicateType: "https://slsa.dev/provenance/v0.2",
+		Predicate: slsaProvenance{
+			Builder: slsaBuilder{
+				Id: "https://sigs.k8s.io/kustomize@" +
+					version.Get().KustomizeVersion},
+			BuildType: "https://sigs.k8s.io/kustomize/build@v1",
+			Invocation: slsaInvocation{Parameters: map[string]string{
+				"path": o.kustomizationPath}},
+		},
+	}
+	materials := &s.Predicate.Materials
+	for _, raw := range sortedRemotes(r.remotes) {
+		repoSpec := r.remotes[raw]
+		*materials = append(*materials, slsaMaterial{
+			Uri:    "git+" + repoSpec.CloneSpec(),
+			Digest: map[string]string{"sha1": repoSpec.Commit},
+		})
+	}
+	for _, path := range sortedKeys(r.files) {
+		d, err := r.digest(path)
+		if err != nil {
+			return nil, err
+		}
+		*materials = append(*materials, slsaMaterial{
+			Uri:    "file:" + relativeTo(root, path),
+			Digest: map[string]string{"sha256": d}})
+	}
+	for _, p := range plugins {
+		*materials = append(*materials, slsaMaterial{
+			Uri:    "file:" + p.path,
+			Digest: map[string]string{"sha256": p.digest}})
+	}
+	if manifest != nil {
+		*materials = append(*materials, slsaMaterial{
+			Uri:    "file:" + o.inputsPath,
+			Digest: sha256Digest(manifest)})
+	}
+	return s, nil
+}
+
+func sha256Digest(data []byte) map[string]string {
+	return map[string]string{"sha256": fmt.Sprintf("%x", sha256.Sum256(data))}
+}
+
+// signStatement returns the statement in a DSSE
+// envelope signed with the key, as one JSON line.
+func signStatement(s *inTotoStatement, keyPEM []byte) ([]byte, error) {
+	key, err := parseECPrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(dssePAE(inTotoPayloadType, payload))
+	r, ss, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, ss})
+	if err != nil {
+		return nil, err
+	}
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(dsseEnvelope{
+		PayloadType: inTotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures: []dsseSignature{{
+			KeyId: fmt.Sprintf("%x", sha256.Sum256(pub)),
+			Sig:   base64.StdEncoding.EncodeToString(sig),
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// dssePAE is the DSSE v1 pre-authentication
+// encoding of a payload, which is what is signed.
+func dssePAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s",
+		len(payloadType), payloadType, len(payload), payload))
+}
+
+func parseECPrivateKey(keyPEM []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("--" + flagAttestKeyName + " holds no PEM block")
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing --"+flagAttestKeyName)
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf(
+			"--%s holds a %T, not an ECDSA key", flagAttestKeyName, key)
+	}
+	return ecKey, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestRunBuildAttest(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/key.pem", pem.EncodeToMemory(
+		&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- service.yaml
+`))
+	fSys.WriteFile("/app/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+
+	o := NewOptions("/app", "/out.yaml")
+	o.attestPath = "/out.intoto.jsonl"
+	o.attestKeyPath = "/key.pem"
+	err = o.RunBuild(nil, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := fSys.ReadFile("/out.intoto.jsonl")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var envelope dsseEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(envelope.Signatures[0].Sig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rs struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(sig, &rs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	digest := sha256.Sum256(dssePAE(envelope.PayloadType, payload))
+	if !ecdsa.Verify(&key.PublicKey, digest[:], rs.R, rs.S) {
+		t.Fatalf("bad signature")
+	}
+
+	var s inTotoStatement
+	if err := json.Unmarshal(payload, &s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output, _ := fSys.ReadFile("/out.yaml")
+	if s.Subject[0].Name != "out.yaml" ||
+		s.Subject[0].Digest["sha256"] != fmt.Sprintf("%x", sha256.Sum256(output)) {
+		t.Fatalf("unexpected subject %v", s.Subject)
+	}
+	if len(s.Predicate.Materials) != 2 ||
+		s.Predicate.Materials[0].Uri != "file:./kustomization.yaml" {
+		t.Fatalf("unexpected materials %v", s.Predicate.Materials)
+	}
+}
+
+func TestValidateAttest(t *testing.T) {
+	for _, o := range []Options{
+		{attestPath: "a.jsonl", outputPath: "out.yaml"},
+		{attestKeyPath: "key.pem", outputPath: "out.yaml"},
+		{attestPath: "a.jsonl", attestKeyPath: "key.pem"},
+	} {
+		if err := o.Validate(nil); err == nil {
+			t.Fatalf("expected error for %+v", o)
+		}
+	}
+}
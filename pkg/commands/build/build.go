@@ -4,30 +4,72 @@
 package build
 
 import (
+	"bytes"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"path/filepath"
+	"runtime"
 	"strings"
 
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/openapi"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
 	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/render"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/target"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 	"sigs.k8s.io/kustomize/v3/plugin/builtin"
-	"sigs.k8s.io/yaml"
 )
 
 // Options contain the options for running a build
 type Options struct {
 	kustomizationPath string
 	outputPath        string
+	depfilePath       string
+	inputsPath        string
+	attestPath        string
+	attestKeyPath     string
+	garbageListPath   string
+	garbageStatePath  string
+	garbageState      []*resource.Resource
+	kubeVersion       string
+	cluster           string
+	contextName       string
+	clusterContext    bool
+	contextsPath      string
+	context           *Context
+	redaction         resource.Redaction
+	redactionKeyPath  string
+	redactionKey      []byte
+	patchConflicts    target.PatchConflicts
+	duplicateKeys     resource.DuplicateKeys
+	deprecatedAPIs    deprecatedAPIs
+	maxProcs          int
+	cpuProfilePath    string
+	memProfilePath    string
+	tracePath         string
+	only              []string
+	onlySelectors     []resourceSelector
+	set               []string
+	values            map[string]string
+	keepGoing         bool
+	strict            bool
+	emitDeps          bool
+	postRenderers     bool
 	loadRestrictor    loader.LoadRestrictorFunc
 	outOrder          reorderOutput
+	loader            loader.Options
+	resource          resource.Options
 }
 
 // NewOptions creates a Options object
@@ -36,6 +78,10 @@ func NewOptions(p, o string) *Options {
 		kustomizationPath: p,
 		outputPath:        o,
 		loadRestrictor:    loader.RestrictionRootOnly,
+		patchConflicts:    target.PatchConflictsLastWins,
+		duplicateKeys:     resource.DuplicateKeysLastWins,
+		deprecatedAPIs:    deprecatedAPIsWarn,
+		maxProcs:          1,
 	}
 }
 
@@ -54,6 +100,144 @@ with a kustomization.yaml file, e.g.
 
 The URL should be formulated as described at
 https://github.com/hashicorp/go-getter#url-format
+
+To also write a Make-compatible dependency file, e.g.
+for an incremental build system, run
+
+  kustomize build someDir -o out.yaml --depfile out.d
+
+Only the API types of Kubernetes 1.14 are built in.  To
+patch resources per the API schema of a 1.16 cluster, save
+it, with that of its custom resources, by running
+
+  kustomize openapi fetch
+
+against the cluster, then run
+
+  kustomize build someDir --kube-version 1.16
+
+which also warns of resources whose APIs 1.16 deprecates,
+e.g. extensions/v1beta1 Ingresses.  To fail if any of their
+APIs are removed in 1.16, e.g. extensions/v1beta1 Deployments, run
+
+  kustomize build someDir --kube-version 1.16 --deprecated-apis error
+
+If the kustomization declares clusters, write the output
+for each into a subdirectory of an existing directory with
+
+  kustomize build someDir -o someOutDir
+
+or build for one of them with
+
+  kustomize build someDir --cluster someCluster
+
+To build with the defaults of a context, e.g. prod, of
+` + contextsFileName + ` in kustomize's config directory,
+
+  contexts:
+    prod:
+      path: overlays/prod
+      namespace: prod
+      imageRegistry: registry.example.com/prod
+
+run
+
+  kustomize build --context prod
+
+To record the inputs of the build, with their hashes,
+e.g. for a reproducibility attestation, run
+
+  kustomize build someDir -o out.yaml --inputs-manifest inputs.spdx.json
+
+To also write a signed provenance statement binding the
+output to those inputs, e.g. for a GitOps pipeline to
+check that manifests come from an approved build, run
+
+  kustomize build someDir -o out.yaml \
+    --attest out.intoto.jsonl --attest-key key.pem
+
+To share the output for review without revealing Secrets, run
+
+  kustomize build someDir --redact-secrets
+
+To output the resources no transformer changed, e.g.
+vendored manifests, just as they are in their files,
+comments and all, run
+
+  kustomize build someDir --preserve-untouched
+
+To fail on maps with a key more than once, which
+otherwise keep the last value, run
+
+  kustomize build someDir --duplicate-keys error
+
+To fail, e.g. in CI, on anything the build would otherwise
+warn of or let pass, such as unused vars, patches selecting
+no resources, or invalid labels, run
+
+  kustomize build someDir --strict
+
+To run the kustomization's postRenderers, e.g. yq,
+on the output, run
+
+  kustomize build someDir --enable-post-renderers
+
+To spread the work of building many resources over
+all CPUs, run
+
+  kustomize build someDir --max-procs 0
+
+To reuse the ConfigMaps generated by earlier builds
+whose generators' arguments and files haven't changed, run
+
+  kustomize build someDir --generator-cache-dir ~/.cache/kustomize
+
+To profile a slow build, e.g. for a bug report, run
+
+  kustomize build someDir --cpuprofile cpu.pprof --memprofile mem.pprof
+
+To build all that can be built of a broken kustomization,
+and report every failure rather than just the first, run
+
+  kustomize build someDir --keep-going
+
+To build a preview environment of a kustomization
+whose namespaceFrom names the value env, run
+
+  kustomize build someDir --set env=feature-xyz
+
+To fail, rather than clone, fetch or pull, on any
+remote base, URL or OCI artifact, e.g. in a hermetic
+CI build, run
+
+  kustomize build someDir --network=false
+
+To ride out a flaky git host, e.g. in CI, trying failed
+clones and fetches up to three more times, and reusing
+the clones of earlier builds, run
+
+  kustomize build someDir --remote-retries 3 --enable-repo-cache
+
+To build a kustomization whose files, e.g. patches, are
+committed encrypted with age, run
+
+  kustomize build someDir --age-identity ~/.config/age/keys.txt
+
+To see which kustomizations read which files and bases,
+as a JSON graph, rather than the resources, run
+
+  kustomize build someDir --emit-deps
+
+To see just one resource of a large build, run
+
+  kustomize build someDir --only Deployment/my-app
+
+To list the generated ConfigMaps and Secrets that an
+earlier build output, now applied, holds but this build
+supersedes, so a pipeline can delete them, run
+
+  kustomize build someDir -o new.yaml \
+    --garbage-state old.yaml --garbage-list garbage.yaml
 `
 
 // NewCmdBuild creates a new build command.
@@ -73,10 +257,18 @@ func NewCmdBuild(
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			stop, err := o.startProfiling()
 			if err != nil {
 				return err
 			}
-			return o.RunBuild(out, v, fSys, rf, ptf, pl)
+			err = o.RunBuild(out, v, fSys, rf, ptf, pl)
+			if serr := stop(); err == nil {
+				err = serr
+			}
+			return err
 		},
 	}
 
@@ -84,30 +276,206 @@ func NewCmdBuild(
 		&o.outputPath,
 		"output", "o", "",
 		"If specified, write the build output to this path.")
-	loader.AddFlagLoadRestrictor(cmd.Flags())
-	plugins.AddFlagEnablePlugins(
-		cmd.Flags(), &pluginConfig.Enabled)
-	addFlagReorderOutput(cmd.Flags())
+	cmd.Flags().StringVar(
+		&o.depfilePath,
+		flagDepfileName, "", flagDepfileHelp)
+	cmd.Flags().StringVar(
+		&o.inputsPath,
+		flagInputsManifestName, "", flagInputsManifestHelp)
+	cmd.Flags().StringVar(
+		&o.attestPath,
+		flagAttestName, "", flagAttestHelp)
+	cmd.Flags().StringVar(
+		&o.attestKeyPath,
+		flagAttestKeyName, "", flagAttestKeyHelp)
+	cmd.Flags().StringVar(
+		&o.garbageListPath,
+		flagGarbageListName, "", flagGarbageListHelp)
+	cmd.Flags().StringVar(
+		&o.garbageStatePath,
+		flagGarbageStateName, "", flagGarbageStateHelp)
+	cmd.Flags().StringVar(
+		&o.cpuProfilePath,
+		flagCPUProfileName, "", flagCPUProfileHelp)
+	cmd.Flags().StringVar(
+		&o.memProfilePath,
+		flagMemProfileName, "", flagMemProfileHelp)
+	cmd.Flags().StringVar(
+		&o.tracePath,
+		flagTraceName, "", flagTraceHelp)
+	cmd.Flags().BoolVar(
+		&o.keepGoing,
+		flagKeepGoingName, false, flagKeepGoingHelp)
+	cmd.Flags().BoolVar(
+		&o.emitDeps,
+		flagEmitDepsName, false, flagEmitDepsHelp)
+	cmd.Flags().StringArrayVar(
+		&o.only,
+		flagOnlyName, nil, flagOnlyHelp)
+	cmd.Flags().StringVar(
+		&o.contextName,
+		flagContextName, "", flagContextHelp)
+	addFlagRedactSecrets(cmd.Flags())
+	cmd.Flags().StringVar(
+		&o.redactionKeyPath,
+		flagRedactSecretsKeyName, "", flagRedactSecretsKeyHelp)
+	o.resource.AddFlagPreserveUntouched(cmd.Flags())
+	o.addBuildFlags(cmd.Flags(), pluginConfig)
 	cmd.AddCommand(NewCmdBuildPrune(out, v, fSys, rf, ptf, pl))
 	return cmd
 }
 
+// addBuildFlags adds the flags saying how to build,
+// rather than what to output, or where.
+func (o *Options) addBuildFlags(
+	set *pflag.FlagSet, pluginConfig *types.PluginConfig) {
+	set.StringVar(
+		&o.kubeVersion,
+		"kube-version", "",
+		"Kubernetes version whose API schema guides strategic merge "+
+			"patches, overriding the kustomization's openapi field, "+
+			"and whose deprecated APIs are checked for. "+
+			"Version "+openapi.BuiltinVersion+" is built in; save the "+
+			"schemas of others with 'kustomize openapi fetch'.  "+
+			"Without one, patches follow the built-in types, but "+
+			"deprecated APIs are still checked for.")
+	set.StringVar(
+		&o.loader.GeneratorCacheDir,
+		flagGeneratorCacheName, "", flagGeneratorCacheHelp)
+	set.IntVar(
+		&o.maxProcs,
+		flagMaxProcsName, 1, flagMaxProcsHelp)
+	set.BoolVar(
+		&o.strict,
+		flagStrictName, false, flagStrictHelp)
+	set.StringArrayVar(
+		&o.set,
+		flagSetName, nil, flagSetHelp)
+	set.StringVar(
+		&o.cluster,
+		"cluster", "",
+		"Build for this one of the kustomization's clusters.")
+	set.BoolVar(
+		&o.postRenderers,
+		flagEnablePostRenderersName, false, flagEnablePostRenderersHelp)
+	loader.AddFlagLoadRestrictor(set)
+	loader.AddFlagSymlinks(set)
+	o.loader.AddFlags(set)
+	plugins.AddFlagEnablePlugins(set, &pluginConfig.Enabled)
+	addFlagReorderOutput(set)
+	addFlagPatchConflicts(set)
+	addFlagDuplicateKeys(set)
+	addFlagDeprecatedAPIs(set)
+}
+
+// AddClusterFlags adds to set the flags of build saying
+// how to build, for commands that build, then change a
+// cluster per the output, e.g. apply.  Their --context
+// names the kubeconfig context of the cluster, and the
+// context of contexts.yaml to build for, if it has one.
+func (o *Options) AddClusterFlags(
+	set *pflag.FlagSet, pluginConfig *types.PluginConfig) {
+	o.addBuildFlags(set, pluginConfig)
+	o.clusterContext = true
+	set.StringVar(
+		&o.contextName,
+		flagContextName, "", flagClusterContextHelp)
+}
+
+// ContextName is the name given by --context.
+func (o *Options) ContextName() string {
+	return o.contextName
+}
+
+// Render builds as RunBuild does, but returns
+// the output rather than writing it.
+func (o *Options) Render(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader) ([]byte, error) {
+	var out bytes.Buffer
+	err := o.RunBuild(&out, v, fSys, rf, ptf, pl)
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
 // Validate validates build command.
 func (o *Options) Validate(args []string) (err error) {
 	if len(args) > 1 {
-		return errors.New(
-			"specify one path to " + pgmconfig.KustomizationFileNames[0])
+		return messages.Errorf(
+			messages.BuildOnePath, pgmconfig.KustomizationFileNames[0])
 	}
 	if len(args) == 0 {
-		o.kustomizationPath = loader.CWD
+		// A context may give the path.
+		if o.contextName == "" {
+			o.kustomizationPath = loader.CWD
+		}
 	} else {
 		o.kustomizationPath = args[0]
 	}
+	if o.depfilePath != "" && o.outputPath == "" {
+		return messages.Errorf(
+			messages.FlagRequires, flagDepfileName, "output")
+	}
+	if (o.attestPath == "") != (o.attestKeyPath == "") {
+		return messages.Errorf(
+			messages.FlagsGoTogether, flagAttestName, flagAttestKeyName)
+	}
+	if o.attestPath != "" && o.outputPath == "" {
+		return messages.Errorf(
+			messages.FlagRequires, flagAttestName, "output")
+	}
+	if (o.garbageListPath == "") != (o.garbageStatePath == "") {
+		return messages.Errorf(messages.FlagsGoTogether,
+			flagGarbageListName, flagGarbageStateName)
+	}
+	if o.maxProcs < 0 {
+		return messages.Errorf(messages.FlagNegative, flagMaxProcsName)
+	}
+	if o.maxProcs == 0 {
+		o.maxProcs = runtime.NumCPU()
+	}
+	o.onlySelectors, err = parseFlagOnly(o.only)
+	if err != nil {
+		return err
+	}
+	o.values, err = parseFlagSet(o.set)
+	if err != nil {
+		return err
+	}
 	o.loadRestrictor, err = loader.ValidateFlagLoadRestrictor()
 	if err != nil {
 		return err
 	}
 	o.outOrder, err = validateFlagReorderOutput()
+	if err != nil {
+		return err
+	}
+	o.redaction, err = validateFlagRedactSecrets()
+	if err != nil {
+		return err
+	}
+	if o.redactionKeyPath != "" && o.redaction != resource.RedactHash {
+		return messages.Errorf(messages.FlagRequires,
+			flagRedactSecretsKeyName, flagRedactSecretsName+"="+redactHash)
+	}
+	o.patchConflicts, err = validateFlagPatchConflicts()
+	if err != nil {
+		return err
+	}
+	o.duplicateKeys, err = validateFlagDuplicateKeys()
+	if err != nil {
+		return err
+	}
+	o.deprecatedAPIs, err = validateFlagDeprecatedAPIs()
+	if err != nil {
+		return err
+	}
+	if o.strict {
+		o.applyStrict()
+	}
 	return
 }
 
@@ -116,47 +484,239 @@ func (o *Options) RunBuild(
 	out io.Writer, v ifc.Validator, fSys fs.FileSystem,
 	rf *resmap.Factory, ptf resmap.PatchFactory,
 	pl *plugins.Loader) error {
-	ldr, err := loader.NewLoader(
-		o.loadRestrictor, v, o.kustomizationPath, fSys)
-	if err != nil {
+	if o.contextName != "" {
+		if err := o.applyContext(fSys); err != nil {
+			return kusterr.WithClass(kusterr.ClassUsage, err)
+		}
+	}
+	if o.redaction == resource.RedactHash {
+		key, err := redactionKey(fSys, o.redactionKeyPath)
+		if err != nil {
+			return kusterr.WithClass(kusterr.ClassUsage, err)
+		}
+		o.redactionKey = key
+	}
+	ro := o.resource
+	ro.Redaction = o.redaction
+	ro.RedactionKey = o.redactionKey
+	ro.DuplicateKeys = o.duplicateKeys
+	if o.kubeVersion != "" {
+		s, err := o.kubeSchema(fSys)
+		if err != nil {
+			return err
+		}
+		ro.Schema = s
+	}
+	// The settings go with this build's factories, so
+	// that builds at once needn't share them.
+	rf = rf.WithOptions(ro).WithMaxProcs(o.maxProcs)
+	pl = pl.WithFactory(rf)
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
 		return err
 	}
-	defer ldr.Cleanup()
-	kt, err := target.NewKustTarget(ldr, rf, ptf, pl)
+	if o.garbageStatePath != "" {
+		var err error
+		o.garbageState, err = loadGarbageState(fSys, rf, o.garbageStatePath)
+		if err != nil {
+			return err
+		}
+	}
+	var rec *loader.DepRecorder
+	var inputs *inputRecorder
+	var graph *loader.GraphRecorder
+	var tracers []loader.Tracer
+	if o.depfilePath != "" {
+		rec = loader.NewDepRecorder()
+		tracers = append(tracers, rec)
+	}
+	stdOut := out
+	if o.emitDeps {
+		graph = loader.NewGraphRecorder()
+		tracers = append(tracers, graph)
+		// The graph is printed in place of the resources.
+		if o.outputPath == "" {
+			out = ioutil.Discard
+		}
+	}
+	if o.inputsPath != "" || o.attestPath != "" {
+		inputs = newInputRecorder()
+		tracers = append(tracers, inputs)
+	}
+	var tracer loader.Tracer
+	if len(tracers) > 0 {
+		tracer = loader.MultiTracer(tracers...)
+	}
+	kt, cleanup, err := render.NewRendererWith(fSys, v, rf, ptf, pl).
+		WithLoadRestrictor(o.loadRestrictor).
+		WithLoaderOptions(o.loader).
+		WithTracer(tracer).
+		Target(o.kustomizationPath)
 	if err != nil {
 		return err
 	}
-	m, err := kt.MakeCustomizedResMap()
+	defer cleanup()
+	kt.SetOptions(target.Options{
+		PatchConflicts: o.patchConflicts,
+		Strict:         o.strict,
+	})
+	if o.keepGoing {
+		kt.KeepGoing()
+	}
+	if o.values != nil {
+		kt.SetValues(o.values)
+	}
+	err = o.buildAndEmit(out, fSys, kt)
 	if err != nil {
 		return err
 	}
-	return o.emitResources(out, fSys, m)
+	if failures := kt.Failures(); len(failures) > 0 {
+		return failuresError(failures)
+	}
+	if inputs != nil {
+		err = o.writeInputs(fSys, kt.Root(), inputs, pl.Loaded())
+		if err != nil {
+			return err
+		}
+	}
+	if graph != nil {
+		if err := emitDeps(stdOut, graph.Graph()); err != nil {
+			return err
+		}
+	}
+	if rec == nil {
+		return nil
+	}
+	return fSys.WriteFile(
+		o.depfilePath, makeDepfile(o.outputPath, rec.Dependencies()))
 }
 
 func (o *Options) RunBuildPrune(
 	out io.Writer, v ifc.Validator, fSys fs.FileSystem,
 	rf *resmap.Factory, ptf resmap.PatchFactory,
 	pl *plugins.Loader) error {
-	ldr, err := loader.NewLoader(
-		o.loadRestrictor, v, o.kustomizationPath, fSys)
-	if err != nil {
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
 		return err
 	}
-	defer ldr.Cleanup()
-	kt, err := target.NewKustTarget(ldr, rf, ptf, pl)
+	kt, cleanup, err := render.NewRendererWith(fSys, v, rf, ptf, pl).
+		WithLoadRestrictor(o.loadRestrictor).
+		WithLoaderOptions(o.loader).
+		Target(o.kustomizationPath)
 	if err != nil {
 		return err
 	}
+	defer cleanup()
 	m, err := kt.MakePruneConfigMap()
 	if err != nil {
 		return err
 	}
-	return o.emitResources(out, fSys, m)
+	return o.emitResources(out, fSys, m, nil)
+}
+
+// buildAndEmit builds the target and writes its output,
+// or, if the kustomization declares clusters, that of
+// each cluster into a subdirectory of the output path,
+// or that of the cluster chosen by a flag.
+func (o *Options) buildAndEmit(
+	out io.Writer, fSys fs.FileSystem, kt *target.KustTarget) error {
+	clusters := kt.Clusters()
+	if o.cluster != "" {
+		clusters = []string{o.cluster}
+	}
+	if len(clusters) == 0 {
+		return o.buildAndEmitOne(out, fSys, kt)
+	}
+	if o.cluster == "" && (o.outputPath == "" || !fSys.IsDir(o.outputPath)) {
+		return kusterr.WithClass(kusterr.ClassUsage, fmt.Errorf(
+			"kustomization declares clusters %v; "+
+				"use --cluster, or --output with a directory",
+			clusters))
+	}
+	if o.cluster == "" && o.garbageListPath != "" {
+		return kusterr.WithClass(kusterr.ClassUsage, fmt.Errorf(
+			"kustomization declares clusters %v; "+
+				"use --cluster with --%s",
+			clusters, flagGarbageListName))
+	}
+	for _, name := range clusters {
+		ckt, err := kt.ForCluster(name)
+		if err != nil {
+			return kusterr.WithClass(kusterr.ClassUsage, err)
+		}
+		if o.cluster != "" {
+			return o.buildAndEmitOne(out, fSys, ckt)
+		}
+		co := *o
+		co.outputPath = filepath.Join(o.outputPath, name)
+		if err := fSys.MkdirAll(co.outputPath); err != nil {
+			return err
+		}
+		if err := co.buildAndEmitOne(out, fSys, ckt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Options) buildAndEmitOne(
+	out io.Writer, fSys fs.FileSystem, kt *target.KustTarget) error {
+	if o.context != nil && o.context.Namespace != "" {
+		kt = kt.WithOverrides(types.Cluster{Namespace: o.context.Namespace})
+	}
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		return err
+	}
+	if o.context != nil && o.context.ImageRegistry != "" {
+		prefixImages(m, o.context.ImageRegistry)
+	}
+	if o.garbageListPath != "" {
+		garbage, err := makeGarbageList(o.garbageState, m)
+		if err != nil {
+			return err
+		}
+		err = fSys.WriteFile(o.garbageListPath, garbage)
+		if err != nil {
+			return err
+		}
+	}
+	if len(o.onlySelectors) > 0 {
+		if err := keepOnly(m, o.onlySelectors); err != nil {
+			return err
+		}
+	}
+	if err := checkAPIs(o.deprecatedAPIs, o.kubeVersion, m); err != nil {
+		return err
+	}
+	exporters, err := kt.MakeExporters()
+	if err != nil {
+		return err
+	}
+	render, err := o.postRenderer(kt)
+	if err != nil {
+		return err
+	}
+	return o.emitResources(out, fSys, m, render, exporters...)
 }
 
+// emitResources writes the resources as YAML, or
+// as the concatenated output of the exporters, if any,
+// piped through render, if not nil.
 func (o *Options) emitResources(
-	out io.Writer, fSys fs.FileSystem, m resmap.ResMap) error {
+	out io.Writer, fSys fs.FileSystem, m resmap.ResMap,
+	render func([]byte) ([]byte, error),
+	exporters ...transformers.Exporter) error {
+	for _, r := range m.Resources() {
+		r.Redact(o.redaction, o.redactionKey)
+	}
 	if o.outputPath != "" && fSys.IsDir(o.outputPath) {
+		if len(exporters) > 0 {
+			return fmt.Errorf(
+				"exporters can't write to directory %s", o.outputPath)
+		}
+		if render != nil {
+			return fmt.Errorf(
+				"postRenderers can't write to directory %s", o.outputPath)
+		}
 		return writeIndividualFiles(fSys, o.outputPath, m)
 	}
 	if o.outOrder == legacy {
@@ -166,10 +726,16 @@ func (o *Options) emitResources(
 		// it and call transform.
 		builtin.NewLegacyOrderTransformerPlugin().Transform(m)
 	}
-	res, err := m.AsYaml()
+	res, err := export(m, exporters)
 	if err != nil {
 		return err
 	}
+	if render != nil {
+		res, err = render(res)
+		if err != nil {
+			return err
+		}
+	}
 	if o.outputPath != "" {
 		return fSys.WriteFile(o.outputPath, res)
 	}
@@ -177,6 +743,21 @@ func (o *Options) emitResources(
 	return err
 }
 
+func export(m resmap.ResMap, exporters []transformers.Exporter) ([]byte, error) {
+	if len(exporters) == 0 {
+		return m.AsYaml()
+	}
+	var result []byte
+	for _, e := range exporters {
+		b, err := e.Export(m)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, b...)
+	}
+	return result, nil
+}
+
 func NewCmdBuildPrune(
 	out io.Writer, v ifc.Validator, fSys fs.FileSystem,
 	rf *resmap.Factory, ptf resmap.PatchFactory,
@@ -191,7 +772,7 @@ func NewCmdBuildPrune(
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
 			if err != nil {
-				return err
+				return kusterr.WithClass(kusterr.ClassUsage, err)
 			}
 			return o.RunBuildPrune(out, v, fSys, rf, ptf, pl)
 		},
@@ -230,7 +811,7 @@ func fileName(res *resource.Resource) string {
 
 func writeFile(
 	fSys fs.FileSystem, path, fName string, res *resource.Resource) error {
-	out, err := yaml.Marshal(res.Map())
+	out, err := res.AsYAML()
 	if err != nil {
 		return err
 	}
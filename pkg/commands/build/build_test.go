@@ -17,9 +17,20 @@ limitations under the License.
 package build
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
 	"testing"
 
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
 )
 
 func TestNewOptionsToSilenceCodeInspectionError(t *testing.T) {
@@ -62,3 +73,223 @@ func TestBuildValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildValidateStrict(t *testing.T) {
+	opts := Options{strict: true}
+	if err := opts.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.duplicateKeys != resource.DuplicateKeysError ||
+		opts.patchConflicts != target.PatchConflictsError ||
+		opts.deprecatedAPIs != deprecatedAPIsError {
+		t.Fatalf("expected --strict to fail on all checks, got %+v", opts)
+	}
+}
+
+type fakeExporter string
+
+func (e fakeExporter) Export(m resmap.ResMap) ([]byte, error) {
+	return []byte(fmt.Sprintf("%s: %d\n", e, m.Size())), nil
+}
+
+func TestEmitResourcesWithExporters(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	o := Options{outputPath: "/out.txt"}
+	err := o.emitResources(
+		nil, fSys, resmap.New(), nil, fakeExporter("a"), fakeExporter("b"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := fSys.ReadFile("/out.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != "a: 0\nb: 0\n" {
+		t.Fatalf("unexpected output %q", out)
+	}
+	fSys.Mkdir("/outdir")
+	o.outputPath = "/outdir"
+	err = o.emitResources(nil, fSys, resmap.New(), nil, fakeExporter("a"))
+	if err == nil || !strings.Contains(err.Error(), "directory") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRunBuildClusters(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- service.yaml
+clusters:
+- name: east
+  namespace: east
+- name: west
+  namespace: west
+`))
+	fSys.WriteFile("/app/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`))
+	fSys.Mkdir("/out")
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+	v := validator.NewKustValidator()
+
+	o := NewOptions("/app", "")
+	err := o.RunBuild(nil, v, fSys, rf, pf, pl)
+	if err == nil || !strings.Contains(err.Error(), "declares clusters") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	o.cluster = "west"
+	if err := o.RunBuild(&out, v, fSys, rf, pf, pl); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "namespace: west") {
+		t.Fatalf("unexpected output %s", out.String())
+	}
+
+	o = NewOptions("/app", "/out")
+	if err := o.RunBuild(nil, v, fSys, rf, pf, pl); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, c := range []string{"east", "west"} {
+		data, err := fSys.ReadFile("/out/" + c + "/~g_v1_service_svc.yaml")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(string(data), "namespace: "+c) {
+			t.Fatalf("unexpected output %s", data)
+		}
+	}
+}
+
+func TestRunBuildRedactSecrets(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+secretGenerator:
+- name: creds
+  literals:
+  - password=hunny
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+
+	var out bytes.Buffer
+	o := NewOptions("/app", "")
+	o.redaction = resource.RedactMask
+	err := o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "password: <redacted>") ||
+		strings.Contains(out.String(), "aHVubnk=") {
+		t.Fatalf("unexpected output %s", out.String())
+	}
+}
+
+func TestRunBuildRedactSecretsHash(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+secretGenerator:
+- name: creds
+  literals:
+  - password=hunny
+`))
+	fSys.WriteFile("/key", []byte("s3cret\n"))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+	build := func(keyPath string) string {
+		var out bytes.Buffer
+		o := NewOptions("/app", "")
+		o.redaction = resource.RedactHash
+		o.redactionKeyPath = keyPath
+		err := o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(out.String(), "password: <redacted:hmac-sha256:") {
+			t.Fatalf("unexpected output %s", out.String())
+		}
+		return out.String()
+	}
+
+	// Builds with the same key can be compared,
+	// but by default each has its own.
+	if build("/key") != build("/key") {
+		t.Fatalf("expected the same hashes under the same key")
+	}
+	if build("") == build("") {
+		t.Fatalf("expected builds without a key to differ")
+	}
+}
+
+func TestRunBuildPreserveUntouched(t *testing.T) {
+	crd := `# Vendored from upstream; don't edit.
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  scope: Namespaced
+  group: "stable.example.com"
+  versions: [{name: v1, served: true, storage: true}]
+`
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- crd.yaml
+- deployment.yaml
+patchesStrategicMerge:
+- patch.yaml
+`))
+	fSys.WriteFile("/app/crd.yaml", []byte(crd))
+	fSys.WriteFile("/app/deployment.yaml", []byte(`
+kind: Deployment
+apiVersion: apps/v1
+metadata:
+  name: web  # the frontend
+spec:
+  replicas: 1
+`))
+	fSys.WriteFile("/app/patch.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+	v := validator.NewKustValidator()
+
+	o := NewOptions("/app", "")
+	o.resource.PreserveUntouched = true
+	var out bytes.Buffer
+	if err := o.RunBuild(&out, v, fSys, rf, pf, pl); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := crd + `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`
+	if out.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, out.String())
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	flagContextName = "context"
+	flagContextHelp = "Build for this context of " + contextsFileName +
+		" in kustomize's config directory, e.g. that of a " +
+		"kubeconfig context, taking its path, unless one is " +
+		"given, namespace and image registry."
+	flagClusterContextHelp = "The kubeconfig context of the cluster, " +
+		"if unspecified, the current one; if " + contextsFileName +
+		" in kustomize's config directory has a context of the " +
+		"name, the build is for it, as with 'kustomize build --context'."
+	contextsFileName = "contexts.yaml"
+)
+
+// noContextError says there's no context of the name.
+type noContextError struct {
+	error
+}
+
+// Context holds the defaults of a build for a target
+// cluster, named as, e.g., its kubeconfig context.
+type Context struct {
+	// Path of the kustomization to build when none is
+	// given, relative to the working directory.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Namespace, if set, replaces the namespace field,
+	// as that of a cluster does.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// ImageRegistry, if set, prefixes the image of every
+	// container, e.g. registry.example.com/prod makes
+	// nginx:1.17 registry.example.com/prod/nginx:1.17.
+	ImageRegistry string `json:"imageRegistry,omitempty" yaml:"imageRegistry,omitempty"`
+}
+
+// ContextsPath is where the contexts are read from.
+func ContextsPath() string {
+	return filepath.Join(pgmconfig.ConfigRoot(), contextsFileName)
+}
+
+// loadContext reads the named context from a file like
+//
+//	contexts:
+//	  prod:
+//	    path: overlays/prod
+//	    namespace: prod
+//	    imageRegistry: registry.example.com/prod
+func loadContext(fSys fs.FileSystem, path, name string) (*Context, error) {
+	if !fSys.Exists(path) {
+		return nil, noContextError{fmt.Errorf(
+			"no context %s, as there's no %s", name, path)}
+	}
+	data, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f struct {
+		Contexts map[string]Context `json:"contexts" yaml:"contexts"`
+	}
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, errors.Wrapf(err, "reading contexts %s", path)
+	}
+	c, ok := f.Contexts[name]
+	if !ok {
+		var names []string
+		for n := range f.Contexts {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, noContextError{fmt.Errorf(
+			"%s has no context %s; contexts: %v", path, name, names)}
+	}
+	return &c, nil
+}
+
+// applyContext reads the context of the flag, and
+// builds its path if none was given, or, for a
+// cluster's context without one, the current directory.
+func (o *Options) applyContext(fSys fs.FileSystem) error {
+	path := o.contextsPath
+	if path == "" {
+		path = ContextsPath()
+	}
+	c, err := loadContext(fSys, path, o.contextName)
+	if _, ok := err.(noContextError); ok && o.clusterContext {
+		// A kubeconfig context needn't be in the file.
+		c, err = &Context{}, nil
+	}
+	if err != nil {
+		return err
+	}
+	o.context = c
+	if o.kustomizationPath == "" {
+		o.kustomizationPath = c.Path
+	}
+	if o.kustomizationPath == "" {
+		o.kustomizationPath = loader.CWD
+	}
+	return nil
+}
+
+// prefixImages prefixes the image of every container
+// and init container in the resources with registry,
+// unless it already has the prefix.
+func prefixImages(m resmap.ResMap, registry string) {
+	prefix := strings.TrimSuffix(registry, "/") + "/"
+	for _, r := range m.Resources() {
+		obj := r.Map()
+		prefixContainerImages(obj, prefix)
+		r.SetMap(obj)
+	}
+}
+
+func prefixContainerImages(obj interface{}, prefix string) {
+	switch x := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range x {
+			if k == "containers" || k == "initContainers" {
+				prefixImagesOf(v, prefix)
+				continue
+			}
+			prefixContainerImages(v, prefix)
+		}
+	case []interface{}:
+		for _, v := range x {
+			prefixContainerImages(v, prefix)
+		}
+	}
+}
+
+func prefixImagesOf(containers interface{}, prefix string) {
+	list, ok := containers.([]interface{})
+	if !ok {
+		return
+	}
+	for _, c := range list {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		image, ok := container["image"].(string)
+		if !ok || image == "" || strings.HasPrefix(image, prefix) {
+			continue
+		}
+		container["image"] = prefix + image
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestRunBuildContext(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/config/contexts.yaml", []byte(`
+contexts:
+  prod:
+    path: /app/overlays/prod
+    namespace: prod
+    imageRegistry: registry.example.com/prod/
+  dev:
+    path: /app/overlays/dev
+`))
+	fSys.WriteFile("/app/overlays/prod/kustomization.yaml", []byte(`
+namespace: default
+resources:
+- deployment.yaml
+`))
+	fSys.WriteFile("/app/overlays/prod/deployment.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: registry.example.com/prod/init:v1
+      containers:
+      - name: app
+        image: nginx:1.17
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+
+	o := Options{contextName: "prod", contextsPath: "/config/contexts.yaml"}
+	if err := o.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	err := o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: prod
+spec:
+  template:
+    spec:
+      containers:
+      - image: registry.example.com/prod/nginx:1.17
+        name: app
+      initContainers:
+      - image: registry.example.com/prod/init:v1
+        name: init
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	o = Options{contextName: "staging", contextsPath: "/config/contexts.yaml"}
+	err = o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err == nil || !strings.Contains(err.Error(), "contexts: [dev prod]") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateContextGivesPath(t *testing.T) {
+	o := Options{contextName: "prod"}
+	if err := o.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.kustomizationPath != "" {
+		t.Fatalf("unexpected path %s", o.kustomizationPath)
+	}
+	if err := o.Validate([]string{"someDir"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.kustomizationPath != "someDir" {
+		t.Fatalf("unexpected path %s", o.kustomizationPath)
+	}
+}
+
+func TestClusterContextNeedntBeInContexts(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+namePrefix: p-
+`))
+	o := Options{
+		kustomizationPath: "/app",
+		contextName:       "kind-kind",
+		contextsPath:      "/config/contexts.yaml",
+		clusterContext:    true,
+	}
+	if err := o.applyContext(fSys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.kustomizationPath != "/app" || o.context.Namespace != "" {
+		t.Fatalf("unexpected options %+v", o)
+	}
+	o.clusterContext = false
+	err := o.applyContext(fSys)
+	if err == nil || !strings.Contains(err.Error(), "no context kind-kind") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
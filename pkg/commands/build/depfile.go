@@ -0,0 +1,44 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+)
+
+const (
+	flagDepfileName = "depfile"
+	flagDepfileHelp = "If specified, write a Make-compatible " +
+		"dependency file listing the local files read while " +
+		"building the --output path."
+)
+
+// makeDepfile returns a Make rule declaring that target
+// depends on every local file listed in d.  Directories
+// and remote bases aren't listed, since Make can't
+// usefully compare their timestamps.
+func makeDepfile(target string, d loader.Dependencies) []byte {
+	var b bytes.Buffer
+	b.WriteString(escapeMakePath(target))
+	b.WriteString(":")
+	for _, f := range d.Files {
+		b.WriteString(" \\\n  ")
+		b.WriteString(escapeMakePath(f))
+	}
+	b.WriteString("\n")
+	return b.Bytes()
+}
+
+var makeEscaper = strings.NewReplacer(
+	" ", `\ `,
+	"#", `\#`,
+	"$", "$$",
+)
+
+func escapeMakePath(p string) string {
+	return makeEscaper.Replace(p)
+}
@@ -0,0 +1,39 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+)
+
+func TestMakeDepfile(t *testing.T) {
+	d := loader.Dependencies{
+		Files: []string{
+			"/app/base/kustomization.yaml",
+			"/app/my dir/$x#y.yaml",
+		},
+		Directories: []string{"/app/base"},
+		Remotes:     []string{"github.com/org/repo"},
+	}
+	expected := `out.yaml: \
+  /app/base/kustomization.yaml \
+  /app/my\ dir/$$x\#y.yaml
+`
+	if actual := string(makeDepfile("out.yaml", d)); actual != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestValidateDepfileNeedsOutput(t *testing.T) {
+	o := Options{depfilePath: "out.d"}
+	if err := o.Validate(nil); err == nil {
+		t.Fatalf("expected error")
+	}
+	o.outputPath = "out.yaml"
+	if err := o.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
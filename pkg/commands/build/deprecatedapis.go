@@ -0,0 +1,116 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/openapi"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+// deprecatedAPIs says what to do when the output has
+// resources of APIs the --kube-version deprecates.
+type deprecatedAPIs string
+
+const (
+	// deprecatedAPIsWarn warns of each resource.
+	deprecatedAPIsWarn deprecatedAPIs = "warn"
+	// deprecatedAPIsError fails the build if any resource
+	// has an API the version removed, warning of the others.
+	deprecatedAPIsError deprecatedAPIs = "error"
+	// deprecatedAPIsIgnore doesn't check.
+	deprecatedAPIsIgnore deprecatedAPIs = "ignore"
+)
+
+const flagDeprecatedAPIsName = "deprecated-apis"
+
+var (
+	flagDeprecatedAPIsValue = string(deprecatedAPIsWarn)
+	flagDeprecatedAPIsHelp  = "What to do, given --kube-version, with output " +
+		"resources of APIs that version deprecates or removes, e.g. " +
+		"extensions/v1beta1 Deployments in 1.16: '" +
+		string(deprecatedAPIsWarn) + "' warns of each, '" +
+		string(deprecatedAPIsError) + "' fails the build if any API is " +
+		"removed, and '" + string(deprecatedAPIsIgnore) + "' doesn't check."
+)
+
+func addFlagDeprecatedAPIs(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagDeprecatedAPIsValue, flagDeprecatedAPIsName,
+		string(deprecatedAPIsWarn), flagDeprecatedAPIsHelp)
+}
+
+func validateFlagDeprecatedAPIs() (deprecatedAPIs, error) {
+	switch d := deprecatedAPIs(flagDeprecatedAPIsValue); d {
+	case deprecatedAPIsWarn,
+		deprecatedAPIsError,
+		deprecatedAPIsIgnore:
+		return d, nil
+	default:
+		return "", messages.Errorf(messages.FlagIllegalValue,
+			flagDeprecatedAPIsName, flagDeprecatedAPIsValue,
+			[]string{
+				string(deprecatedAPIsWarn),
+				string(deprecatedAPIsError),
+				string(deprecatedAPIsIgnore),
+			})
+	}
+}
+
+// kubeSchema returns the schema of the --kube-version,
+// or nil, with a warning, if none is saved; the check of
+// deprecated APIs needs only the version.
+func (o *Options) kubeSchema(fSys fs.FileSystem) (*openapi.Schema, error) {
+	has, err := openapi.HasSchema(fSys, o.kubeVersion)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassUsage, err)
+	}
+	if !has {
+		log.Printf("warning: no openapi schema for kubernetes %s, "+
+			"so strategic merge patches follow the kustomization's "+
+			"openapi field, if any, or the built-in %s API types; "+
+			"save the schema with 'kustomize openapi fetch'",
+			o.kubeVersion, openapi.BuiltinVersion)
+		return nil, nil
+	}
+	return openapi.ForVersion(fSys, o.kubeVersion)
+}
+
+// checkAPIs warns of the resources of m whose APIs
+// Kubernetes deprecates by the given version, or,
+// as d says, fails if the version removed any.
+func checkAPIs(d deprecatedAPIs, version string, m resmap.ResMap) error {
+	if d == deprecatedAPIsIgnore || version == "" {
+		return nil
+	}
+	var removed []string
+	for _, r := range m.Resources() {
+		api, gone, err := openapi.Deprecation(version, r.GetGvk())
+		if err != nil {
+			return err
+		}
+		if api == nil {
+			continue
+		}
+		msg := fmt.Sprintf("%s %s: %s", r.GetKind(), r.GetName(), api)
+		if gone && d == deprecatedAPIsError {
+			removed = append(removed, msg)
+			continue
+		}
+		log.Printf("warning: %s", msg)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	return kusterr.WithClass(kusterr.ClassValidation, messages.Errorf(
+		messages.APIsNotServed,
+		len(removed), version, strings.Join(removed, "\n")))
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/openapi"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestRunBuildDeprecatedAPIs(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile(filepath.Join(openapi.Dir(), "1.22.json"), []byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Kubernetes", "version": "v1.22.0"},
+  "paths": {},
+  "definitions": {}
+}`))
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- resources.yaml
+`))
+	fSys.WriteFile("/app/resources.yaml", []byte(`
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: web
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+
+	o := NewOptions("/app", "")
+	o.kubeVersion = "1.22"
+	err := o.RunBuild(
+		&bytes.Buffer{}, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	o.deprecatedAPIs = deprecatedAPIsError
+	err = o.RunBuild(
+		&bytes.Buffer{}, validator.NewKustValidator(), fSys, rf, pf, pl)
+	expected := `1 resources have APIs kubernetes 1.22 doesn't serve:
+Ingress web: extensions/v1beta1 is deprecated since kubernetes 1.14, ` +
+		`and removed in 1.22; use networking.k8s.io/v1`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error:\n%s\ngot:\n%v", expected, err)
+	}
+
+	// The check needs no schema of the version.
+	fSys.RemoveAll(filepath.Join(openapi.Dir(), "1.22.json"))
+	err = o.RunBuild(
+		&bytes.Buffer{}, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error:\n%s\ngot:\n%v", expected, err)
+	}
+
+	// Kubernetes 1.14 only deprecates the Ingress.
+	o.kubeVersion = openapi.BuiltinVersion
+	var out bytes.Buffer
+	err = o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "kind: Ingress") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+const flagDuplicateKeysName = "duplicate-keys"
+
+var (
+	flagDuplicateKeysValue = string(resource.DuplicateKeysLastWins)
+	flagDuplicateKeysHelp  = "What to do when a map in a kustomization, " +
+		"resource or patch file has a key more than once: '" +
+		string(resource.DuplicateKeysError) + "' fails the build, '" +
+		string(resource.DuplicateKeysWarn) + "' lets the last value win, " +
+		"with a warning, and '" +
+		string(resource.DuplicateKeysLastWins) + "' does so silently."
+)
+
+func addFlagDuplicateKeys(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagDuplicateKeysValue, flagDuplicateKeysName,
+		string(resource.DuplicateKeysLastWins), flagDuplicateKeysHelp)
+}
+
+func validateFlagDuplicateKeys() (resource.DuplicateKeys, error) {
+	switch d := resource.DuplicateKeys(flagDuplicateKeysValue); d {
+	case resource.DuplicateKeysError,
+		resource.DuplicateKeysWarn,
+		resource.DuplicateKeysLastWins:
+		return d, nil
+	default:
+		return "", messages.Errorf(messages.FlagIllegalValue,
+			flagDuplicateKeysName, flagDuplicateKeysValue,
+			[]string{
+				string(resource.DuplicateKeysError),
+				string(resource.DuplicateKeysWarn),
+				string(resource.DuplicateKeysLastWins),
+			})
+	}
+}
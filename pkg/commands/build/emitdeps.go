@@ -0,0 +1,29 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"encoding/json"
+	"io"
+
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+)
+
+const (
+	flagEmitDepsName = "emit-deps"
+	flagEmitDepsHelp = "Print, as JSON, rather than the resources, the " +
+		"graph of which kustomizations read which files and bases, " +
+		"local, remote, and in the repos cloned, with their refs; " +
+		"with --output, the resources are still written there."
+)
+
+// emitDeps writes the graph as indented JSON.
+func emitDeps(out io.Writer, g loader.DepGraph) error {
+	b, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestRunBuildEmitDeps(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/overlay/kustomization.yaml", []byte(`
+resources:
+- ../base
+- service.yaml
+`))
+	fSys.WriteFile("/app/overlay/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`))
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(`
+configMapGenerator:
+- name: config
+  literals:
+  - a=b
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+
+	var out bytes.Buffer
+	o := NewOptions("/app/overlay", "")
+	o.emitDeps = true
+	err := o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var actual loader.DepGraph
+	if err := json.Unmarshal(out.Bytes(), &actual); err != nil {
+		t.Fatalf("expected the graph alone, got %v:\n%s", err, out.String())
+	}
+	expected := loader.DepGraph{
+		Root: "/app/overlay",
+		Nodes: []loader.GraphNode{
+			{ID: "/app/base", Node: loader.Node{
+				Kind: loader.NodeDirectory, Path: "/app/base"}},
+			{ID: "/app/base/kustomization.yaml", Node: loader.Node{
+				Kind: loader.NodeFile, Path: "/app/base/kustomization.yaml"}},
+			{ID: "/app/overlay", Node: loader.Node{
+				Kind: loader.NodeDirectory, Path: "/app/overlay"}},
+			{ID: "/app/overlay/kustomization.yaml", Node: loader.Node{
+				Kind: loader.NodeFile, Path: "/app/overlay/kustomization.yaml"}},
+			{ID: "/app/overlay/service.yaml", Node: loader.Node{
+				Kind: loader.NodeFile, Path: "/app/overlay/service.yaml"}},
+		},
+		Edges: []loader.Edge{
+			{From: "/app/base", To: "/app/base/kustomization.yaml"},
+			{From: "/app/overlay", To: "/app/base"},
+			{From: "/app/overlay", To: "/app/overlay/kustomization.yaml"},
+			{From: "/app/overlay", To: "/app/overlay/service.yaml"},
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, actual)
+	}
+
+	// With --output, the resources are written there too.
+	out.Reset()
+	o.outputPath = "/out.yaml"
+	err = o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(out.Bytes(), &actual); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := fSys.ReadFile("/out.yaml")
+	if err != nil || !bytes.Contains(b, []byte("kind: Service")) {
+		t.Fatalf("expected the resources written, got %v:\n%s", err, b)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	flagGarbageListName = "garbage-list"
+	flagGarbageListHelp = "If specified, write to this path the " +
+		"generated ConfigMaps and Secrets of the --garbage-state " +
+		"that the build output supersedes, e.g. for " +
+		"'kubectl delete -f'."
+	flagGarbageStateName = "garbage-state"
+	flagGarbageStateHelp = "Resources previously applied, e.g. an " +
+		"earlier build output, or the output of " +
+		"'kubectl get configmaps,secrets -o yaml'. " +
+		"A missing file holds no resources."
+)
+
+// loadGarbageState reads the resources of the state file,
+// if any.
+func loadGarbageState(
+	fSys fs.FileSystem, rf *resmap.Factory,
+	path string) ([]*resource.Resource, error) {
+	if !fSys.Exists(path) {
+		return nil, nil
+	}
+	data, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return rf.RF().SliceFromBytes(data)
+}
+
+// makeGarbageList returns, as YAML holding just their
+// apiVersion, kind, name and namespace, the resources
+// of the state that are earlier generations of generated
+// resources in m, i.e. that differ from them only by the
+// name's hash suffix.
+func makeGarbageList(
+	state []*resource.Resource, m resmap.ResMap) ([]byte, error) {
+	var b bytes.Buffer
+	for _, old := range state {
+		if !isSuperseded(old, m) {
+			continue
+		}
+		apiVersion, err := old.GetString("apiVersion")
+		if err != nil {
+			return nil, err
+		}
+		meta := map[string]interface{}{"name": old.GetName()}
+		if old.GetNamespace() != "" {
+			meta["namespace"] = old.GetNamespace()
+		}
+		out, err := yaml.Marshal(map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       old.GetKind(),
+			"metadata":   meta,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if b.Len() > 0 {
+			b.WriteString("---\n")
+		}
+		b.Write(out)
+	}
+	return b.Bytes(), nil
+}
+
+func isSuperseded(old *resource.Resource, m resmap.ResMap) bool {
+	var superseded bool
+	for _, r := range m.Resources() {
+		if !r.GetGvk().Equals(old.GetGvk()) ||
+			!sameNamespace(r.GetNamespace(), old.GetNamespace()) {
+			continue
+		}
+		if r.GetName() == old.GetName() {
+			return false
+		}
+		if r.NeedHashSuffix() &&
+			trimHashSuffix(r.GetName()) == trimHashSuffix(old.GetName()) {
+			superseded = true
+		}
+	}
+	return superseded
+}
+
+// trimHashSuffix returns the name without the
+// hash appended to generated names.
+func trimHashSuffix(name string) string {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return name
+	}
+	return name[:i]
+}
+
+// sameNamespace treats the namespace omitted from a
+// build output as the default one of kubectl output.
+func sameNamespace(a, b string) bool {
+	if a == "" {
+		a = "default"
+	}
+	if b == "" {
+		b = "default"
+	}
+	return a == b
+}
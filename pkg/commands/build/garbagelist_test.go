@@ -0,0 +1,109 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestRunBuildGarbageList(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+configMapGenerator:
+- name: config
+  literals:
+  - key=new
+- name: stable
+  literals:
+  - key=new
+`))
+	fSys.WriteFile("/state.yaml", []byte(`
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config-old1234567
+    namespace: default
+  data:
+    key: old
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config-extra-1234567890
+    namespace: default
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config-old1234567
+    namespace: other
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: config-old1234567
+    namespace: default
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+
+	var out bytes.Buffer
+	o := NewOptions("/app", "")
+	o.garbageStatePath = "/state.yaml"
+	o.garbageListPath = "/garbage.yaml"
+	err := o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := fSys.ReadFile("/garbage.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-old1234567
+  namespace: default
+`
+	if string(data) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	// With the state being the output just built,
+	// nothing is superseded.
+	fSys.WriteFile("/state.yaml", out.Bytes())
+	err = o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err = fSys.ReadFile("/garbage.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) != 0 {
+		t.Fatalf("unexpected garbage %s", data)
+	}
+}
+
+func TestValidateGarbageListNeedsState(t *testing.T) {
+	o := Options{garbageListPath: "garbage.yaml"}
+	if err := o.Validate(nil); err == nil {
+		t.Fatalf("expected error")
+	}
+	o.garbageStatePath = "state.yaml"
+	if err := o.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+const (
+	flagGeneratorCacheName = "generator-cache-dir"
+	flagGeneratorCacheHelp = "Directory to keep generated ConfigMaps in " +
+		"across builds, keyed by their generators' arguments and the " +
+		"hashes of their files.  Secrets are only cached within a build."
+)
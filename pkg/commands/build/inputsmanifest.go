@@ -0,0 +1,281 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/oci"
+	"sigs.k8s.io/kustomize/v3/pkg/version"
+)
+
+const (
+	flagInputsManifestName = "inputs-manifest"
+	flagInputsManifestHelp = "If specified, write to this path an " +
+		"SPDX-style JSON manifest of the inputs of the build: local " +
+		"files and plugins with their SHA256, remote bases with " +
+		"the commits cloned, and the kustomize version."
+)
+
+// inputRecorder is a loader.DigestTracer recording the
+// local files read, with the sha256 of their content as
+// the loader read it, the commits of the repos cloned,
+// and the URLs of files fetched and of OCI artifacts
+// pulled.  It's what both the inputs manifest and the
+// provenance statement are made of.
+type inputRecorder struct {
+	files   map[string]bool
+	digests map[string]string
+	changed map[string]bool
+	remotes map[string]*git.RepoSpec
+	urls    map[string]bool
+}
+
+func newInputRecorder() *inputRecorder {
+	return &inputRecorder{
+		files:   make(map[string]bool),
+		digests: make(map[string]string),
+		changed: make(map[string]bool),
+		remotes: make(map[string]*git.RepoSpec),
+		urls:    make(map[string]bool),
+	}
+}
+
+func (r *inputRecorder) Digested(path string, sum [sha256.Size]byte) {
+	d := fmt.Sprintf("%x", sum)
+	if old, ok := r.digests[path]; ok && old != d {
+		r.changed[path] = true
+	}
+	r.digests[path] = d
+}
+
+// digest returns the sha256 of the local file the
+// build read, or an error if the build read it twice,
+// finding it changed, or never read it in full.
+func (r *inputRecorder) digest(path string) (string, error) {
+	if r.changed[path] {
+		return "", fmt.Errorf("%s changed during the build", path)
+	}
+	d, ok := r.digests[path]
+	if !ok {
+		return "", fmt.Errorf("%s wasn't read in full", path)
+	}
+	return d, nil
+}
+
+func (r *inputRecorder) Loaded(path string, repoSpec *git.RepoSpec) {
+	if repoSpec != nil {
+		r.remotes[repoSpec.Raw()] = repoSpec
+		return
+	}
+	if loader.IsFileURL(path) || oci.IsReference(path) ||
+		loader.IsObjectURL(path) || loader.IsArchiveURL(path) {
+		r.urls[path] = true
+		return
+	}
+	r.files[path] = true
+}
+
+func (r *inputRecorder) Rooted(root string, repoSpec *git.RepoSpec) {
+	if repoSpec != nil {
+		r.remotes[repoSpec.Raw()] = repoSpec
+	}
+}
+
+// writeInputs writes the inputs manifest, and the
+// provenance statement, as the flags ask, from the
+// inputs recorded, and the plugins loaded.
+func (o *Options) writeInputs(
+	fSys fs.FileSystem, root string,
+	r *inputRecorder, pluginPaths []string) error {
+	plugins, err := hashPlugins(pluginPaths)
+	if err != nil {
+		return err
+	}
+	var manifest []byte
+	if o.inputsPath != "" {
+		manifest, err = makeInputsManifest(root, r, plugins)
+		if err != nil {
+			return err
+		}
+		if err := fSys.WriteFile(o.inputsPath, manifest); err != nil {
+			return err
+		}
+	}
+	if o.attestPath == "" {
+		return nil
+	}
+	return o.attest(fSys, root, r, plugins, manifest)
+}
+
+// The subset of SPDX 2.2 used.  There's no creation
+// time, so that builds of the same inputs have the
+// same manifest.
+type spdxDocument struct {
+	SpdxVersion  string        `json:"spdxVersion"`
+	DataLicense  string        `json:"dataLicense"`
+	SpdxId       string        `json:"SPDXID"`
+	Name         string        `json:"name"`
+	CreationInfo spdxCreation  `json:"creationInfo"`
+	Packages     []spdxPackage `json:"packages,omitempty"`
+	Files        []spdxFile    `json:"files,omitempty"`
+}
+
+type spdxCreation struct {
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SpdxId           string `json:"SPDXID"`
+	Name             string `json:"name"`
+	DownloadLocation string `json:"downloadLocation"`
+	VersionInfo      string `json:"versionInfo"`
+}
+
+type spdxFile struct {
+	SpdxId    string         `json:"SPDXID"`
+	FileName  string         `json:"fileName"`
+	FileTypes []string       `json:"fileTypes"`
+	Checksums []spdxChecksum `json:"checksums"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+// makeInputsManifest returns the manifest of the
+// recorded inputs of the build of the root, and of
+// the plugins.  Paths below the root are relative
+// to it.
+func makeInputsManifest(
+	root string, r *inputRecorder, plugins []pluginFile) ([]byte, error) {
+	doc := spdxDocument{
+		SpdxVersion: "SPDX-2.2",
+		DataLicense: "CC0-1.0",
+		SpdxId:      "SPDXRef-DOCUMENT",
+		Name:        "kustomize build " + filepath.Base(root),
+		CreationInfo: spdxCreation{Creators: []string{
+			"Tool: kustomize-" + version.Get().KustomizeVersion}},
+	}
+	for i, raw := range sortedRemotes(r.remotes) {
+		repoSpec := r.remotes[raw]
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SpdxId:           fmt.Sprintf("SPDXRef-Remote-%d", i),
+			Name:             raw,
+			DownloadLocation: "git+" + repoSpec.CloneSpec(),
+			VersionInfo:      repoSpec.Commit,
+		})
+	}
+	for _, u := range sortedKeys(r.urls) {
+		// Only the digest of a pinned artifact is known.
+		var digest string
+		ref, _ := oci.SplitPath(u)
+		if ref, err := oci.ParseReference(ref); err == nil {
+			digest = ref.Digest
+		}
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SpdxId:           fmt.Sprintf("SPDXRef-Remote-%d", len(doc.Packages)),
+			Name:             u,
+			DownloadLocation: u,
+			VersionInfo:      digest,
+		})
+	}
+	for _, path := range sortedKeys(r.files) {
+		d, err := r.digest(path)
+		if err != nil {
+			return nil, err
+		}
+		doc.Files = append(doc.Files, makeSpdxFile(
+			len(doc.Files), relativeTo(root, path), "TEXT", d))
+	}
+	for _, p := range plugins {
+		doc.Files = append(doc.Files, makeSpdxFile(
+			len(doc.Files), p.path, "BINARY", p.digest))
+	}
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func makeSpdxFile(i int, name, fileType, digest string) spdxFile {
+	return spdxFile{
+		SpdxId:    fmt.Sprintf("SPDXRef-File-%d", i),
+		FileName:  name,
+		FileTypes: []string{fileType},
+		Checksums: []spdxChecksum{{
+			Algorithm:     "SHA256",
+			ChecksumValue: digest,
+		}},
+	}
+}
+
+// pluginFile is a plugin the build loaded, with
+// the sha256 of its file.
+type pluginFile struct {
+	path   string
+	digest string
+}
+
+// hashPlugins returns the plugin files with their
+// sha256.  The loader doesn't read them, so, unlike
+// the other inputs, they're read here, once for both
+// the inputs manifest and the provenance statement.
+func hashPlugins(paths []string) ([]pluginFile, error) {
+	var result []pluginFile
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pluginFile{path, fmt.Sprintf("%x", h.Sum(nil))})
+	}
+	return result, nil
+}
+
+// relativeTo returns the path, if below the root,
+// in SPDX form, e.g. ./base/kustomization.yaml.
+func relativeTo(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return "./" + filepath.ToSlash(rel)
+}
+
+func sortedRemotes(m map[string]*git.RepoSpec) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
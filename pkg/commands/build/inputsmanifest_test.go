@@ -0,0 +1,144 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/version"
+)
+
+func TestRunBuildInputsManifest(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- service.yaml
+`))
+	fSys.WriteFile("/app/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+
+	o := NewOptions("/app", "/out.yaml")
+	o.inputsPath = "/inputs.json"
+	err := o.RunBuild(nil, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := fSys.ReadFile("/inputs.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{
+  "spdxVersion": "SPDX-2.2",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "kustomize build app",
+  "creationInfo": {
+    "creators": [
+      "Tool: kustomize-` + version.Get().KustomizeVersion + `"
+    ]
+  },
+  "files": [
+    {
+      "SPDXID": "SPDXRef-File-0",
+      "fileName": "./kustomization.yaml",
+      "fileTypes": [
+        "TEXT"
+      ],
+      "checksums": [
+        {
+          "algorithm": "SHA256",
+          "checksumValue": "d69577bea8cffb885e5d745299ad07a526dad1f8a924dbf8efc958d8a55246ca"
+        }
+      ]
+    },
+    {
+      "SPDXID": "SPDXRef-File-1",
+      "fileName": "./service.yaml",
+      "fileTypes": [
+        "TEXT"
+      ],
+      "checksums": [
+        {
+          "algorithm": "SHA256",
+          "checksumValue": "38f41cc21b1181678a4db83ddf640502fa87607e8c035dfcbe4e96264d207c3c"
+        }
+      ]
+    }
+  ]
+}
+`
+	if string(data) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+}
+
+func TestMakeInputsManifestRemotes(t *testing.T) {
+	r := newInputRecorder()
+	r.Rooted("/tmp/clone/base", &git.RepoSpec{
+		Host: "https://github.com/", OrgRepo: "org/repo",
+		GitSuffix: ".git", Commit: "abc"})
+	r.Loaded("https://example.com/crds.yaml", nil)
+	r.Loaded("oci://ghcr.io/org/bases@sha256:"+strings.Repeat("0", 64)+
+		"//prod", nil)
+	data, err := makeInputsManifest("/app", r, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{
+		`"downloadLocation": "git+https://github.com/org/repo.git"`,
+		`"versionInfo": "abc"`,
+		`"downloadLocation": "https://example.com/crds.yaml"`,
+		`"versionInfo": "sha256:` + strings.Repeat("0", 64) + `"`,
+	} {
+		if !strings.Contains(string(data), s) {
+			t.Fatalf("expected %s in:\n%s", s, data)
+		}
+	}
+}
+
+func TestMakeInputsManifestDigests(t *testing.T) {
+	r := newInputRecorder()
+	r.Loaded("/app/kustomization.yaml", nil)
+	r.Digested("/app/kustomization.yaml", sha256.Sum256([]byte("as read")))
+	plugins := []pluginFile{{"/plugins/gen", strings.Repeat("1", 64)}}
+	data, err := makeInputsManifest("/app", r, plugins)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{
+		fmt.Sprintf(`"checksumValue": "%x"`, sha256.Sum256([]byte("as read"))),
+		`"fileName": "/plugins/gen"`,
+		`"checksumValue": "` + strings.Repeat("1", 64) + `"`,
+	} {
+		if !strings.Contains(string(data), s) {
+			t.Fatalf("expected %s in:\n%s", s, data)
+		}
+	}
+
+	// A file read twice, changed between, has no digest.
+	r.Digested("/app/kustomization.yaml", sha256.Sum256([]byte("changed")))
+	_, err = makeInputsManifest("/app", r, plugins)
+	if err == nil || !strings.Contains(err.Error(), "changed during the build") {
+		t.Fatalf("expected changed file error, got %v", err)
+	}
+}
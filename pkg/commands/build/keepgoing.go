@@ -0,0 +1,30 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	flagKeepGoingName = "keep-going"
+	flagKeepGoingHelp = "Skip the resource files, bases and generators " +
+		"that fail, writing the rest of the output, then report " +
+		"every failure, as YAML, instead of stopping at the first."
+)
+
+// failuresError reports the parts of a build
+// skipped under --keep-going, with the class
+// they share, if any, to exit with.
+func failuresError(failures []target.Failure) error {
+	b, err := yaml.Marshal(failures)
+	if err != nil {
+		return err
+	}
+	return kusterr.WithClass(target.FailuresClass(failures), messages.Errorf(
+		messages.BuildSkippedFailures, len(failures), b))
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestRunBuildKeepGoing(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- service.yaml
+- missing.yaml
+- ../broken
+- ../base
+`))
+	fSys.WriteFile("/app/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`))
+	fSys.WriteFile("/broken/kustomization.yaml", []byte(`
+namePrefix: [not, a, string]
+`))
+	fSys.WriteFile("/base/kustomization.yaml", []byte(`
+configMapGenerator:
+- name: config
+  files:
+  - missing.properties
+- name: other
+  literals:
+  - a=b
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+
+	var out bytes.Buffer
+	o := NewOptions("/app", "")
+	o.keepGoing = true
+	err := o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err == nil {
+		t.Fatalf("expected the failures reported")
+	}
+	expected := `apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+---
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  name: other-776k659td5
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+	expected = `skipped 3 failed parts of the build:
+- error: 'accumulating resources from ''missing.yaml'': cannot read file "/app/missing.yaml"'
+  path: missing.yaml
+- error: 'couldn''t make target for path ''../broken'': json: cannot unmarshal array
+    into Go struct field Kustomization.namePrefix of type string'
+  path: ../broken
+- error: 'file sources: [missing.properties]: cannot read file "/base/missing.properties"'
+  path: ../base/kustomization.yaml
+`
+	if err.Error() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, err)
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+const (
+	flagMaxProcsName = "max-procs"
+	flagMaxProcsHelp = "How many resources CPU bound transformers, " +
+		"e.g. of labels, annotations and name hashes, work on in " +
+		"parallel; 0 means one per CPU.  The output doesn't change."
+)
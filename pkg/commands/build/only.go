@@ -0,0 +1,70 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+const (
+	flagOnlyName = "only"
+	flagOnlyHelp = "Write only the resource with this Kind/name " +
+		"in the output, e.g. Deployment/my-app; repeat for more.  " +
+		"The whole kustomization is still built, as a resource's " +
+		"output depends on others, e.g. the ConfigMaps it names."
+)
+
+// resourceSelector picks resources of the
+// output by their kind and name.
+type resourceSelector struct {
+	kind string
+	name string
+}
+
+func (s resourceSelector) String() string {
+	return s.kind + "/" + s.name
+}
+
+func parseFlagOnly(values []string) ([]resourceSelector, error) {
+	var result []resourceSelector
+	for _, v := range values {
+		parts := strings.Split(v, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, messages.Errorf(messages.FlagWantValue,
+				flagOnlyName, v, "Kind/name")
+		}
+		result = append(result, resourceSelector{kind: parts[0], name: parts[1]})
+	}
+	return result, nil
+}
+
+// keepOnly removes the resources not selected from m,
+// failing if a selector selects none.
+func keepOnly(m resmap.ResMap, selectors []resourceSelector) error {
+	selected := make([]bool, len(selectors))
+	for _, r := range m.Resources() {
+		keep := false
+		for i, s := range selectors {
+			if r.GetKind() == s.kind && r.GetName() == s.name {
+				selected[i] = true
+				keep = true
+			}
+		}
+		if !keep {
+			if err := m.Remove(r.CurId()); err != nil {
+				return err
+			}
+		}
+	}
+	for i, s := range selectors {
+		if !selected[i] {
+			return fmt.Errorf("no resource in the output is %s", s)
+		}
+	}
+	return nil
+}
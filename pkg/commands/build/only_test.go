@@ -0,0 +1,90 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestRunBuildOnly(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+namePrefix: p-
+resources:
+- deployment.yaml
+configMapGenerator:
+- name: config
+  literals:
+  - color=red
+`))
+	fSys.WriteFile("/app/deployment.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      volumes:
+      - name: config
+        configMap:
+          name: config
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+
+	var out bytes.Buffer
+	o := NewOptions("/app", "")
+	o.onlySelectors = []resourceSelector{{kind: "Deployment", name: "p-app"}}
+	err := o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The reference to the ConfigMap is still resolved.
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: p-app
+spec:
+  template:
+    spec:
+      volumes:
+      - configMap:
+          name: p-config-m2968fgcc6
+        name: config
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	o.onlySelectors = []resourceSelector{{kind: "Deployment", name: "app"}}
+	err = o.RunBuild(&out, validator.NewKustValidator(), fSys, rf, pf, pl)
+	if err == nil || !strings.Contains(err.Error(), "Deployment/app") {
+		t.Fatalf("expected no match error, got %v", err)
+	}
+}
+
+func TestParseFlagOnly(t *testing.T) {
+	s, err := parseFlagOnly([]string{"Deployment/my-app"})
+	if err != nil || len(s) != 1 || s[0].String() != "Deployment/my-app" {
+		t.Fatalf("unexpected %v, %v", s, err)
+	}
+	for _, v := range []string{"Deployment", "Deployment/", "a/b/c"} {
+		if _, err := parseFlagOnly([]string{v}); err == nil {
+			t.Fatalf("expected error for %s", v)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+)
+
+const flagPatchConflictsName = "patch-conflicts"
+
+var (
+	flagPatchConflictsValue = string(target.PatchConflictsLastWins)
+	flagPatchConflictsHelp  = "What to do when patches of one kustomization " +
+		"change the same field of a resource: '" +
+		string(target.PatchConflictsError) + "' fails the build, '" +
+		string(target.PatchConflictsLastWins) + "' lets the last patch win, and '" +
+		string(target.PatchConflictsReport) + "' does too, with a warning."
+)
+
+func addFlagPatchConflicts(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagPatchConflictsValue, flagPatchConflictsName,
+		string(target.PatchConflictsLastWins), flagPatchConflictsHelp)
+}
+
+func validateFlagPatchConflicts() (target.PatchConflicts, error) {
+	switch p := target.PatchConflicts(flagPatchConflictsValue); p {
+	case target.PatchConflictsError,
+		target.PatchConflictsLastWins,
+		target.PatchConflictsReport:
+		return p, nil
+	default:
+		return "", messages.Errorf(messages.FlagIllegalValue,
+			flagPatchConflictsName, flagPatchConflictsValue,
+			[]string{
+				string(target.PatchConflictsError),
+				string(target.PatchConflictsLastWins),
+				string(target.PatchConflictsReport),
+			})
+	}
+}
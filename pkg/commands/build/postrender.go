@@ -0,0 +1,37 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+)
+
+const (
+	flagEnablePostRenderersName = "enable-post-renderers"
+	flagEnablePostRenderersHelp = "Run the programs the kustomization's " +
+		"postRenderers name, piping the output through them.  They run " +
+		"in the kustomization's directory, with only $PATH, $HOME and " +
+		"$TMPDIR of the environment."
+)
+
+// postRenderer returns the function piping the output
+// through the target's postRenderers, if it has any,
+// failing if they aren't enabled, as running them
+// without the flag would change the output silently.
+func (o *Options) postRenderer(
+	kt *target.KustTarget) (func([]byte) ([]byte, error), error) {
+	if len(kt.PostRenderers()) == 0 {
+		return nil, nil
+	}
+	if !o.postRenderers {
+		return nil, kusterr.WithClass(kusterr.ClassUsage, fmt.Errorf(
+			"the kustomization has postRenderers, which run "+
+				"programs; use --%s to run them",
+			flagEnablePostRenderersName))
+	}
+	return kt.PostRender, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+const (
+	flagCPUProfileName = "cpuprofile"
+	flagCPUProfileHelp = "Write a pprof CPU profile of the build to this file."
+	flagMemProfileName = "memprofile"
+	flagMemProfileHelp = "Write a pprof heap profile, taken when the build " +
+		"is done, to this file."
+	flagTraceName = "trace"
+	flagTraceHelp = "Write an execution trace of the build, " +
+		"for 'go tool trace', to this file."
+)
+
+// startProfiling starts the profiles asked for by flags,
+// returning a func to stop them and write them out, whose
+// error is that of the first profile that failed.
+func (o *Options) startProfiling() (func() error, error) {
+	var stops []func() error
+	stop := func() error {
+		var result error
+		for i := len(stops) - 1; i >= 0; i-- {
+			if err := stops[i](); err != nil && result == nil {
+				result = err
+			}
+		}
+		return result
+	}
+	if o.cpuProfilePath != "" {
+		f, err := os.Create(o.cpuProfilePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		})
+	}
+	if o.tracePath != "" {
+		f, err := os.Create(o.tracePath)
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			stop()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return f.Close()
+		})
+	}
+	if o.memProfilePath != "" {
+		stops = append(stops, func() error {
+			f, err := os.Create(o.memProfilePath)
+			if err != nil {
+				return err
+			}
+			// Up to date statistics, per the runtime/pprof docs.
+			runtime.GC()
+			err = pprof.WriteHeapProfile(f)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			return err
+		})
+	}
+	return stop, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStartProfiling(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-profile-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	o := Options{
+		cpuProfilePath: filepath.Join(dir, "cpu.pprof"),
+		memProfilePath: filepath.Join(dir, "mem.pprof"),
+		tracePath:      filepath.Join(dir, "trace.out"),
+	}
+	stop, err := o.startProfiling()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, p := range []string{o.cpuProfilePath, o.memProfilePath, o.tracePath} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Size() == 0 {
+			t.Fatalf("expected %s to be written", p)
+		}
+	}
+	o = Options{cpuProfilePath: filepath.Join(dir, "missing", "cpu.pprof")}
+	if _, err := o.startProfiling(); err == nil {
+		t.Fatalf("expected an error for an unwritable profile")
+	}
+}
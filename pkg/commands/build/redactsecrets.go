@@ -0,0 +1,78 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+const (
+	flagRedactSecretsName    = "redact-secrets"
+	flagRedactSecretsKeyName = "redact-secrets-key"
+	redactMask               = "mask"
+	redactHash               = "hash"
+)
+
+var (
+	flagRedactSecretsValue = ""
+	flagRedactSecretsHelp  = "Replace the values of Secrets, in the output " +
+		"and in error messages, keeping their keys. " +
+		"Use '" + redactMask + "' (the default) to write " +
+		resource.RedactedValue + ", or '" + redactHash +
+		"' to write an HMAC-SHA256 of each value, so changes stay " +
+		"visible.  The HMAC key is random, so values can't be " +
+		"found by hashing guesses, but then hashes show changes " +
+		"only within the output; give --" + flagRedactSecretsKeyName +
+		" to compare outputs."
+	flagRedactSecretsKeyHelp = "File holding the HMAC key of --" +
+		flagRedactSecretsName + "=" + redactHash + ", so outputs " +
+		"made with the same key can be compared.  Anyone with the " +
+		"key can test guesses of the values; keep it as secret " +
+		"as they are."
+)
+
+func addFlagRedactSecrets(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagRedactSecretsValue, flagRedactSecretsName,
+		"", flagRedactSecretsHelp)
+	set.Lookup(flagRedactSecretsName).NoOptDefVal = redactMask
+}
+
+// redactionKey returns the key of the file at path, or
+// if path is empty, a random one.
+func redactionKey(fSys fs.FileSystem, path string) ([]byte, error) {
+	if path == "" {
+		return resource.NewRedactionKey()
+	}
+	b, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key := bytes.TrimSpace(b)
+	if len(key) == 0 {
+		return nil, fmt.Errorf("redaction key file %s is empty", path)
+	}
+	return key, nil
+}
+
+func validateFlagRedactSecrets() (resource.Redaction, error) {
+	switch flagRedactSecretsValue {
+	case "":
+		return resource.RedactNone, nil
+	case redactMask:
+		return resource.RedactMask, nil
+	case redactHash:
+		return resource.RedactHash, nil
+	default:
+		return resource.RedactNone, messages.Errorf(messages.FlagIllegalValue,
+			flagRedactSecretsName, flagRedactSecretsValue,
+			[]string{redactMask, redactHash})
+	}
+}
@@ -4,9 +4,8 @@
 package build
 
 import (
-	"fmt"
-
 	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
 )
 
 //go:generate stringer -type=reorderOutput
@@ -42,8 +41,7 @@ func validateFlagReorderOutput() (reorderOutput, error) {
 	case legacy.String():
 		return legacy, nil
 	default:
-		return unspecified, fmt.Errorf(
-			"illegal flag value --%s %s; legal values: %v",
+		return unspecified, messages.Errorf(messages.FlagIllegalValue,
 			flagReorderOutputName, flagReorderOutputValue,
 			[]string{legacy.String(), none.String()})
 	}
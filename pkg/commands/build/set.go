@@ -0,0 +1,33 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+)
+
+const (
+	flagSetName = "set"
+	flagSetHelp = "Set a value, as name=value, replacing any of " +
+		"that name in the kustomization's valuesFrom, e.g. " +
+		"env=feature-xyz for its namespaceFrom; repeat for more."
+)
+
+func parseFlagSet(args []string) (map[string]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]string, len(args))
+	for _, a := range args {
+		i := strings.Index(a, "=")
+		if i < 1 {
+			return nil, messages.Errorf(messages.FlagWantValue,
+				flagSetName, a, "name=value")
+		}
+		result[a[:i]] = a[i+1:]
+	}
+	return result, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestParseFlagSet(t *testing.T) {
+	values, err := parseFlagSet([]string{"env=feature-xyz", "query=a=b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values["env"] != "feature-xyz" || values["query"] != "a=b" {
+		t.Fatalf("unexpected values %v", values)
+	}
+	for _, bad := range []string{"env", "=x"} {
+		_, err := parseFlagSet([]string{bad})
+		if err == nil || !strings.Contains(err.Error(), "want name=value") {
+			t.Fatalf("%s: unexpected error: %v", bad, err)
+		}
+	}
+}
+
+func TestRunBuildNamespaceFrom(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("no git")
+	}
+	dir, err := ioutil.TempDir("", "kustomize-namespacefrom-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fSys := fs.MakeRealFS()
+	fSys.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(`
+resources:
+- service.yaml
+namespaceFrom:
+  value: env
+  gitBranch: true
+  prefix: myapp-
+`))
+	fSys.WriteFile(filepath.Join(dir, "service.yaml"), []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"checkout", "-q", "-b", "preview/Login"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+	v := validator.NewKustValidator()
+
+	var out bytes.Buffer
+	o := NewOptions(dir, "")
+	if err := o.RunBuild(&out, v, fSys, rf, pf, pl); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "namespace: myapp-preview-login") {
+		t.Fatalf("unexpected output %s", out.String())
+	}
+
+	out.Reset()
+	o.values = map[string]string{"env": "pr-42"}
+	if err := o.RunBuild(&out, v, fSys, rf, pf, pl); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "namespace: myapp-pr-42") {
+		t.Fatalf("unexpected output %s", out.String())
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+)
+
+const (
+	flagStrictName = "strict"
+	flagStrictHelp = "Fail on anything the build would otherwise " +
+		"warn of or let pass: duplicate keys, patch conflicts, " +
+		"patches selecting no resources, unused vars, APIs the " +
+		"--kube-version removes, invalid names, labels and " +
+		"annotations, and, given a schema of their kinds, fields " +
+		"of output resources it doesn't allow.  This overrides " +
+		"--" + flagDuplicateKeysName + ", --" + flagPatchConflictsName +
+		" and --" + flagDeprecatedAPIsName + "."
+)
+
+// applyStrict sets the checks other flags control
+// to fail, as --strict does.
+func (o *Options) applyStrict() {
+	o.duplicateKeys = resource.DuplicateKeysError
+	o.patchConflicts = target.PatchConflictsError
+	o.deprecatedAPIs = deprecatedAPIsError
+}
@@ -0,0 +1,299 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bump sets the tag or digest of an image
+// in every kustomization of a tree.
+package bump
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/image"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// Options contain the options for running bump.
+type Options struct {
+	name   string
+	tag    string
+	digest string
+	dir    string
+	dryRun bool
+}
+
+var examples = `
+To set the tag of my-app to v2 in the images field, and
+the images of the clusters field, of every kustomization
+under someDir that names my-app there, run
+
+  kustomize bump my-app:v2 someDir
+
+The image may be named by the name or the newName of
+an entry.  A digest replaces the tag:
+
+  kustomize bump my-app@sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3 someDir
+
+Each entry is listed with its old and new tag or digest.
+Either all the kustomization files are written, or, if
+one can't be, none are.  With --dry-run, the list is
+printed, but nothing is written.
+`
+
+// NewCmdBump creates a new bump command.
+func NewCmdBump(out io.Writer, fSys fs.FileSystem) *cobra.Command {
+	var o Options
+
+	cmd := &cobra.Command{
+		Use:          "bump {image}:{tag}|{image}@{digest} [{dir}]",
+		Short:        "Set the tag or digest of an image in every kustomization of a tree",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			bumps, err := o.RunBump(fSys)
+			if err != nil {
+				return err
+			}
+			return o.emit(out, bumps)
+		},
+	}
+	cmd.Flags().BoolVar(
+		&o.dryRun,
+		"dry-run", false,
+		"List the entries that would change, without "+
+			"writing the kustomization files.")
+	return cmd
+}
+
+// Validate validates bump command.
+func (o *Options) Validate(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(
+			"specify an image with a tag or digest, " +
+				"and optionally a directory")
+	}
+	var err error
+	o.name, o.tag, o.digest, err = parseImage(args[0])
+	if err != nil {
+		return err
+	}
+	o.dir = loader.CWD
+	if len(args) == 2 {
+		o.dir = args[1]
+	}
+	return nil
+}
+
+// parseImage splits name:tag or name@digest.  The
+// tag follows the last colon after the last slash, as
+// a registry's host may have a port.
+func parseImage(arg string) (name, tag, digest string, err error) {
+	if i := strings.Index(arg, "@"); i > 0 && i < len(arg)-1 {
+		return arg[:i], "", arg[i+1:], nil
+	}
+	i := strings.LastIndex(arg, ":")
+	if i > 0 && i < len(arg)-1 && i > strings.LastIndex(arg, "/") {
+		return arg[:i], arg[i+1:], "", nil
+	}
+	return "", "", "", fmt.Errorf(
+		"image %s should be {image}:{tag} or {image}@{digest}", arg)
+}
+
+// Bump is the change to one images entry.
+type Bump struct {
+	// Path is the kustomization file.
+	Path string
+	// Field is images, or the images of
+	// a cluster, e.g. clusters[prod].images.
+	Field string
+	// From and To are the tag, as :tag, or digest,
+	// as @digest, before and after, "" if unset.
+	From string
+	To   string
+}
+
+// Changed is true if the bump changes the entry.
+func (b Bump) Changed() bool {
+	return b.From != b.To
+}
+
+// RunBump sets the tag or digest of the image in the
+// entries naming it of every kustomization under the
+// directory.  Unless in a dry run, it writes the
+// kustomization files changed, all or none of them.
+func (o *Options) RunBump(fSys fs.FileSystem) ([]Bump, error) {
+	dirs, err := kustomizationDirs(fSys, o.dir)
+	if err != nil {
+		return nil, err
+	}
+	var bumps []Bump
+	var changed []kustomization
+	for _, dir := range dirs {
+		mf, err := kustfile.NewKustomizationFileIn(fSys, dir)
+		if err != nil {
+			return nil, err
+		}
+		k, err := mf.Read()
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading kustomization in %s", dir)
+		}
+		path := kustomizationPath(fSys, dir)
+		b := o.bump(path, "images", k.Images)
+		for i := range k.Clusters {
+			c := &k.Clusters[i]
+			b = append(b, o.bump(path,
+				fmt.Sprintf("clusters[%s].images", c.Name), c.Images)...)
+		}
+		bumps = append(bumps, b...)
+		for _, x := range b {
+			if x.Changed() {
+				changed = append(changed, kustomization{path, mf, k})
+				break
+			}
+		}
+	}
+	if len(bumps) == 0 {
+		return nil, fmt.Errorf(
+			"no kustomization under %s has an image %s", o.dir, o.name)
+	}
+	if o.dryRun {
+		return bumps, nil
+	}
+	return bumps, write(fSys, changed)
+}
+
+// bump sets the tag or digest of the image in the
+// entries naming it, returning the changes.
+func (o *Options) bump(path, field string, images []image.Image) []Bump {
+	var result []Bump
+	for i := range images {
+		img := &images[i]
+		if img.Name != o.name && img.NewName != o.name {
+			continue
+		}
+		b := Bump{Path: path, Field: field, From: tagOrDigest(*img)}
+		img.NewTag, img.Digest = o.tag, o.digest
+		b.To = tagOrDigest(*img)
+		result = append(result, b)
+	}
+	return result
+}
+
+// tagOrDigest returns the digest of the entry,
+// which takes precedence, else its tag.
+func tagOrDigest(img image.Image) string {
+	if img.Digest != "" {
+		return "@" + img.Digest
+	}
+	if img.NewTag != "" {
+		return ":" + img.NewTag
+	}
+	return ""
+}
+
+// writer writes a kustomization file, as those
+// of kustfile do.
+type writer interface {
+	Write(*types.Kustomization) error
+}
+
+// kustomization is a kustomization file to write.
+type kustomization struct {
+	path string
+	mf   writer
+	k    *types.Kustomization
+}
+
+// write writes the kustomizations, restoring those
+// already written, and the one that failed, which may
+// be half written, if one fails.
+func write(fSys fs.FileSystem, ks []kustomization) error {
+	originals := make([][]byte, len(ks))
+	for i, x := range ks {
+		data, err := fSys.ReadFile(x.path)
+		if err != nil {
+			return err
+		}
+		originals[i] = data
+	}
+	for i, x := range ks {
+		if err := x.mf.Write(x.k); err != nil {
+			for j := 0; j <= i; j++ {
+				fSys.WriteFile(ks[j].path, originals[j])
+			}
+			return errors.Wrapf(
+				err, "writing %s; no kustomization was changed", x.path)
+		}
+	}
+	return nil
+}
+
+// kustomizationDirs returns dir and the directories
+// under it with kustomization files, skipping hidden
+// ones, e.g. .git.
+func kustomizationDirs(fSys fs.FileSystem, dir string) ([]string, error) {
+	var result []string
+	if kustomizationPath(fSys, dir) != "" {
+		result = append(result, dir)
+	}
+	entries, err := fSys.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(filepath.Base(e), ".") || !fSys.IsDir(e) {
+			continue
+		}
+		sub, err := kustomizationDirs(fSys, e)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sub...)
+	}
+	return result, nil
+}
+
+// kustomizationPath returns the path of the
+// kustomization file in dir, "" if it has none.
+func kustomizationPath(fSys fs.FileSystem, dir string) string {
+	for _, n := range pgmconfig.KustomizationFileNames {
+		p := filepath.Join(dir, n)
+		if fSys.Exists(p) && !fSys.IsDir(p) {
+			return p
+		}
+	}
+	return ""
+}
+
+func (o *Options) emit(out io.Writer, bumps []Bump) error {
+	n := 0
+	for _, b := range bumps {
+		if !b.Changed() {
+			fmt.Fprintf(out, "%s %s: %s%s unchanged\n",
+				b.Path, b.Field, o.name, b.To)
+			continue
+		}
+		n++
+		fmt.Fprintf(out, "%s %s: %s%s -> %s%s\n",
+			b.Path, b.Field, o.name, b.From, o.name, b.To)
+	}
+	verb := "changed"
+	if o.dryRun {
+		verb = "would change"
+	}
+	_, err := fmt.Fprintf(out, "%s %d of %d entries\n", verb, n, len(bumps))
+	return err
+}
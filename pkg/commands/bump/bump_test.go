@@ -0,0 +1,166 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package bump
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+func writeTree(fSys fs.FileSystem) {
+	for _, d := range []string{"/app", "/app/base", "/app/prod", "/app/staging", "/app/.git"} {
+		fSys.Mkdir(d)
+	}
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(`resources:
+- deployment.yaml
+`))
+	fSys.WriteFile("/app/prod/kustomization.yaml", []byte(`# prod
+resources:
+- ../base
+images:
+- name: my-app
+  newTag: v1
+- name: other
+  newTag: v1
+clusters:
+- name: east
+  images:
+  - name: my-app
+    digest: sha256:abc
+`))
+	fSys.WriteFile("/app/staging/kustomization.yaml", []byte(`resources:
+- ../base
+images:
+- name: app
+  newName: registry:5000/my-app
+  newTag: v2
+`))
+	fSys.WriteFile("/app/.git/kustomization.yaml", []byte(`images:
+- name: my-app
+`))
+}
+
+func TestParseImage(t *testing.T) {
+	for _, tc := range []struct {
+		arg, name, tag, digest string
+	}{
+		{"my-app:v2", "my-app", "v2", ""},
+		{"registry:5000/my-app:v2", "registry:5000/my-app", "v2", ""},
+		{"my-app@sha256:abc", "my-app", "", "sha256:abc"},
+	} {
+		name, tag, digest, err := parseImage(tc.arg)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.arg, err)
+		}
+		if name != tc.name || tag != tc.tag || digest != tc.digest {
+			t.Fatalf("%s: got %s %s %s", tc.arg, name, tag, digest)
+		}
+	}
+	for _, arg := range []string{"my-app", "registry:5000/my-app", "my-app:"} {
+		if _, _, _, err := parseImage(arg); err == nil {
+			t.Fatalf("%s: expected error", arg)
+		}
+	}
+}
+
+func TestRunBump(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeTree(fSys)
+	o := Options{}
+	if err := o.Validate([]string{"my-app:v2", "/app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bumps, err := o.RunBump(fSys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := o.emit(&out, bumps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `/app/prod/kustomization.yaml images: my-app:v1 -> my-app:v2
+/app/prod/kustomization.yaml clusters[east].images: my-app@sha256:abc -> my-app:v2
+changed 2 of 2 entries
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+	content, _ := fSys.ReadFile("/app/prod/kustomization.yaml")
+	if !strings.HasPrefix(string(content), "# prod\n") ||
+		!strings.Contains(string(content), `images:
+- name: my-app
+  newTag: v2
+- name: other
+  newTag: v1
+`) || strings.Contains(string(content), "sha256") {
+		t.Fatalf("unexpected kustomization:\n%s", content)
+	}
+
+	o = Options{dryRun: true}
+	if err := o.Validate([]string{"registry:5000/my-app:v2", "/app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bumps, err = o.RunBump(fSys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out.Reset()
+	o.emit(&out, bumps)
+	expected = `/app/staging/kustomization.yaml images: registry:5000/my-app:v2 unchanged
+would change 0 of 1 entries
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	o = Options{}
+	o.Validate([]string{"missing:v2", "/app"})
+	_, err = o.RunBump(fSys)
+	if err == nil || err.Error() != "no kustomization under /app has an image missing" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// truncatingWriter empties the file, then fails,
+// as a write cut short might.
+type truncatingWriter struct {
+	fSys fs.FileSystem
+	path string
+}
+
+func (w truncatingWriter) Write(*types.Kustomization) error {
+	w.fSys.WriteFile(w.path, nil)
+	return errors.New("disk full")
+}
+
+func TestWriteRestores(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeTree(fSys)
+	var ks []kustomization
+	for _, p := range []string{
+		"/app/base/kustomization.yaml", "/app/prod/kustomization.yaml"} {
+		ks = append(ks, kustomization{
+			path: p,
+			mf:   truncatingWriter{fSys: fSys, path: p},
+			k:    &types.Kustomization{},
+		})
+	}
+	originals := map[string][]byte{}
+	for _, k := range ks {
+		originals[k.path], _ = fSys.ReadFile(k.path)
+	}
+	if err := write(fSys, ks); err == nil {
+		t.Fatalf("expected error")
+	}
+	for _, k := range ks {
+		if b, _ := fSys.ReadFile(k.path); !bytes.Equal(b, originals[k.path]) {
+			t.Fatalf("expected %s restored, got:\n%s", k.path, b)
+		}
+	}
+}
@@ -9,13 +9,30 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
 	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
 	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/apply"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/build"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/bump"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/compare"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/create"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/deps"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/diff"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/edit"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/flatten"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/misc"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/patch"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/promote"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/resolve"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/serve"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/test"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/upgradebase"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/vendoring"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/verify"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
@@ -25,6 +42,7 @@ import (
 func NewDefaultCommand() *cobra.Command {
 	fSys := fs.MakeRealFS()
 	stdOut := os.Stdout
+	var tmpDir string
 
 	c := &cobra.Command{
 		Use:   pgmconfig.ProgramName,
@@ -32,7 +50,47 @@ func NewDefaultCommand() *cobra.Command {
 		Long: `
 Manages declarative configuration of Kubernetes.
 See https://sigs.k8s.io/kustomize
+
+Commands exit with a code saying what kind of failure
+stopped them, for scripts to branch on:
+
+  0  success
+  1  unclassified failure
+  2  usage error, e.g. a bad flag or argument
+  3  load error, e.g. a missing file, or a load restriction
+  4  remote failure, e.g. a remote base that couldn't be cloned
+  5  validation failure, e.g. a bad kustomization field
+  6  plugin failure
+
+With --error-format=json, the error is printed as a line
+of JSON with its message ID, which, unlike the message,
+doesn't change between releases.  Messages are printed
+in the language of $` + messages.LangEnv + `, else $LC_ALL, $LC_MESSAGES
+or $LANG, if translated, message ID to text, in e.g.
+$XDG_CONFIG_HOME/kustomize/messages/de_DE.yaml, or de.yaml.
 `,
+		// Errors are printed, as --error-format says,
+		// by PrintError.
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateFlagErrorFormat(); err != nil {
+				return err
+			}
+			if flagErrorFormatValue == errorFormatJSON {
+				// Usage would be mistaken for part of the error.
+				cmd.Root().SilenceUsage = true
+			}
+			t, err := messages.LoadTranslations(
+				fSys, messages.TranslationsDir(), messages.Language())
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassLoad, err)
+			}
+			messages.SetTranslations(t)
+			if tmpDir != "" {
+				fs.SetTmpBase(tmpDir)
+			}
+			return nil
+		},
 	}
 
 	uf := kunstruct.NewKunstructuredFactoryImpl()
@@ -40,14 +98,41 @@ See https://sigs.k8s.io/kustomize
 	rf := resmap.NewFactory(resource.NewFactory(uf), pf)
 	v := validator.NewKustValidator()
 	c.AddCommand(
+		apply.NewCmdApply(stdOut, fSys, v, rf, pf),
 		build.NewCmdBuild(
 			stdOut, fSys, v,
 			rf, pf),
-		edit.NewCmdEdit(fSys, v, uf),
-		misc.NewCmdConfig(fSys),
+		bump.NewCmdBump(stdOut, fSys),
+		compare.NewCmdCompare(stdOut, fSys, v, rf, pf),
+		create.NewCmdCreate(stdOut, fSys),
+		deps.NewCmdDeps(stdOut, fSys, v, rf, pf),
+		diff.NewCmdDiff(stdOut, fSys, v, rf, pf),
+		edit.NewCmdEdit(stdOut, fSys, v, uf),
+		flatten.NewCmdFlatten(stdOut, fSys, v, rf, pf),
+		misc.NewCmdCleanCache(stdOut, fSys),
+		misc.NewCmdConfig(stdOut, fSys, v, rf, pf),
+		misc.NewCmdLsp(fSys, os.Stdin, stdOut),
+		misc.NewCmdOpenAPI(stdOut, fSys),
 		misc.NewCmdVersion(stdOut),
+		patch.NewCmdPatch(stdOut, fSys, rf.RF()),
+		promote.NewCmdPromote(stdOut, fSys),
+		resolve.NewCmdResolve(stdOut, fSys, v, rf, pf),
+		serve.NewCmdServe(stdOut, fSys, v, rf, pf),
+		test.NewCmdTest(stdOut, fSys, v, rf, pf),
+		upgradebase.NewCmdUpgradeBase(stdOut, fSys, v, rf, pf),
+		vendoring.NewCmdVendor(stdOut, fSys),
+		verify.NewCmdVerify(stdOut, fSys, v, rf, pf),
 	)
+	c.PersistentFlags().StringVar(
+		&tmpDir, "tmp-dir", "",
+		"Directory for temporary files, e.g. clones of remote bases, "+
+			"instead of $"+fs.TmpBaseEnv+" or the system's.")
+	addFlagErrorFormat(c.PersistentFlags())
 	c.PersistentFlags().AddGoFlagSet(flag.CommandLine)
+	c.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return kusterr.WithClass(kusterr.ClassUsage,
+			messages.WithID(messages.BadFlag, err))
+	})
 
 	// Workaround for this issue:
 	// https://github.com/kubernetes/kubernetes/issues/17162
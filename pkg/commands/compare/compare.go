@@ -0,0 +1,348 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package compare reports how the outputs of
+// two kustomizations differ.
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/render"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultIgnored are fields expected to differ
+// between environments.
+var defaultIgnored = []string{
+	"metadata.namespace", "metadata.name", "spec.replicas"}
+
+// Options contain the options for running compare.
+type Options struct {
+	left           string
+	right          string
+	ignore         []string
+	output         string
+	loadRestrictor loader.LoadRestrictorFunc
+	loader         loader.Options
+}
+
+var examples = `
+To see how the output of the prod overlay differs from
+that of the dev overlay, run
+
+  kustomize compare overlays/dev overlays/prod
+
+Resources are matched by kind and name, before name
+prefixes, suffixes and hashes.  The report lists the
+resources only one overlay has, and the fields that
+differ in those both have, except for fields expected to
+differ, by default ` + strings.Join(defaultIgnored, ", ") + `.
+--ignore replaces these, e.g.
+
+  kustomize compare overlays/dev overlays/prod \
+      --ignore metadata.namespace,metadata.name,metadata.labels.env
+
+A field ignored ignores the fields in it too.  If the
+outputs differ, the exit code is that of a failed
+validation.  To get the report as data, add -o json or
+-o yaml.
+`
+
+// NewCmdCompare creates a new compare command.
+func NewCmdCompare(
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, rf *resmap.Factory,
+	ptf resmap.PatchFactory) *cobra.Command {
+	var o Options
+
+	pluginConfig := plugins.DefaultPluginConfig()
+	pl := plugins.NewLoader(pluginConfig, rf)
+
+	cmd := &cobra.Command{
+		Use:          "compare {path} {path}",
+		Short:        "Report how the outputs of two kustomizations differ",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			diffs, err := o.RunCompare(v, fSys, rf, ptf, pl)
+			if err != nil {
+				return err
+			}
+			err = o.emit(out, diffs)
+			if err != nil {
+				return err
+			}
+			if len(diffs) > 0 {
+				return kusterr.WithClass(kusterr.ClassValidation, messages.Errorf(
+					messages.ResourcesDiffer, len(diffs), o.left, o.right))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(
+		&o.ignore,
+		"ignore", defaultIgnored,
+		"Fields not compared, as paths from the top of a resource.")
+	cmd.Flags().StringVarP(
+		&o.output,
+		"output", "o", "",
+		"One of 'json' or 'yaml'.  If unspecified, print "+
+			"each resource that differs, then its fields.")
+	loader.AddFlagLoadRestrictor(cmd.Flags())
+	o.loader.AddFlags(cmd.Flags())
+	plugins.AddFlagEnablePlugins(
+		cmd.Flags(), &pluginConfig.Enabled)
+	return cmd
+}
+
+// Validate validates compare command.
+func (o *Options) Validate(args []string) (err error) {
+	if len(args) != 2 {
+		return errors.New("specify two kustomizations to compare")
+	}
+	o.left, o.right = args[0], args[1]
+	switch o.output {
+	case "", "json", "yaml":
+	default:
+		return fmt.Errorf("--output must be 'json' or 'yaml'")
+	}
+	o.loadRestrictor, err = loader.ValidateFlagLoadRestrictor()
+	return err
+}
+
+// Difference is how a resource differs
+// between the outputs.
+type Difference struct {
+	// Resource is the kind and name, before
+	// prefixes, suffixes and hashes, of the resource.
+	Resource string `json:"resource"`
+	// OnlyIn is the kustomization with the
+	// resource, if only one has it.
+	OnlyIn string  `json:"onlyIn,omitempty"`
+	Fields []Field `json:"fields,omitempty"`
+}
+
+// Field is a field that differs, with its value
+// in each output, nil if unset.
+type Field struct {
+	Path  string      `json:"path"`
+	Left  interface{} `json:"left"`
+	Right interface{} `json:"right"`
+}
+
+// RunCompare builds the kustomizations, and
+// returns the resources that differ.
+func (o *Options) RunCompare(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader) ([]Difference, error) {
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
+		return nil, err
+	}
+	left, err := o.build(v, fSys, rf, ptf, pl, o.left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := o.build(v, fSys, rf, ptf, pl, o.right)
+	if err != nil {
+		return nil, err
+	}
+	return o.compare(left, right), nil
+}
+
+// Diff returns the resources that differ between two
+// outputs, named left and right, ignoring the fields
+// of ignore, as compare does.
+func Diff(
+	left, right resmap.ResMap,
+	leftName, rightName string, ignore []string) []Difference {
+	o := &Options{left: leftName, right: rightName, ignore: ignore}
+	return o.compare(left, right)
+}
+
+// Write writes the differences as output, one of "",
+// "json" or "yaml", as compare does.
+func Write(out io.Writer, diffs []Difference, output string) error {
+	o := &Options{output: output}
+	return o.emit(out, diffs)
+}
+
+func (o *Options) build(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader, path string) (resmap.ResMap, error) {
+	m, err := render.NewRendererWith(fSys, v, rf, ptf, pl).
+		WithLoadRestrictor(o.loadRestrictor).
+		WithLoaderOptions(o.loader).
+		Render(path, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "building %s", path)
+	}
+	return m, nil
+}
+
+// key names a resource the same way in both
+// outputs, whatever the names it gets in each.
+func key(r *resource.Resource) string {
+	id := r.OrgId()
+	if id.Group == "" {
+		return id.Kind + "/" + r.GetOriginalName()
+	}
+	return id.Kind + "." + id.Group + "/" + r.GetOriginalName()
+}
+
+// compare returns the resources that differ, those
+// of left in their order, then those only in right.
+func (o *Options) compare(left, right resmap.ResMap) []Difference {
+	rights := make(map[string]*resource.Resource)
+	for _, r := range right.Resources() {
+		rights[key(r)] = r
+	}
+	var result []Difference
+	seen := make(map[string]bool)
+	for _, l := range left.Resources() {
+		k := key(l)
+		seen[k] = true
+		r, ok := rights[k]
+		if !ok {
+			result = append(result, Difference{Resource: k, OnlyIn: o.left})
+			continue
+		}
+		var fields []Field
+		o.diff("", l.Map(), r.Map(), &fields)
+		if len(fields) > 0 {
+			result = append(result, Difference{Resource: k, Fields: fields})
+		}
+	}
+	for _, r := range right.Resources() {
+		if k := key(r); !seen[k] {
+			result = append(result, Difference{Resource: k, OnlyIn: o.right})
+		}
+	}
+	return result
+}
+
+func (o *Options) ignored(path string) bool {
+	for _, p := range o.ignore {
+		if path == p ||
+			strings.HasPrefix(path, p+".") ||
+			strings.HasPrefix(path, p+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// diff appends the leaf fields, of either value,
+// whose values differ in the other.
+func (o *Options) diff(
+	path string, left, right interface{}, fields *[]Field) {
+	if o.ignored(path) {
+		return
+	}
+	switch l := left.(type) {
+	case map[string]interface{}:
+		r, ok := right.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make(map[string]bool)
+		for k := range l {
+			keys[k] = true
+		}
+		for k := range r {
+			keys[k] = true
+		}
+		var sorted []string
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			o.diff(p, l[k], r[k], fields)
+		}
+		return
+	case []interface{}:
+		r, ok := right.([]interface{})
+		if !ok || len(r) != len(l) {
+			break
+		}
+		for i := range l {
+			o.diff(fmt.Sprintf("%s[%d]", path, i), l[i], r[i], fields)
+		}
+		return
+	}
+	// Numbers may be decoded as ints or floats.
+	if fmt.Sprint(left) != fmt.Sprint(right) {
+		*fields = append(*fields, Field{Path: path, Left: left, Right: right})
+	}
+}
+
+func (o *Options) emit(out io.Writer, diffs []Difference) error {
+	var b []byte
+	var err error
+	switch o.output {
+	case "json":
+		b, err = json.MarshalIndent(diffs, "", "  ")
+		b = append(b, '\n')
+	case "yaml":
+		b, err = yaml.Marshal(diffs)
+	default:
+		var buf strings.Builder
+		for _, d := range diffs {
+			if d.OnlyIn != "" {
+				fmt.Fprintf(&buf, "%s: only in %s\n", d.Resource, d.OnlyIn)
+				continue
+			}
+			fmt.Fprintf(&buf, "%s:\n", d.Resource)
+			for _, f := range d.Fields {
+				fmt.Fprintf(&buf, "  %s: %s | %s\n",
+					f.Path, format(f.Left), format(f.Right))
+			}
+		}
+		b = []byte(buf.String())
+	}
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
+
+// format writes a value on one line.
+func format(v interface{}) string {
+	if v == nil {
+		return "<unset>"
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,128 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package compare
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func writeOverlays(fSys fs.FileSystem) {
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(`
+resources:
+- deployment.yaml
+configMapGenerator:
+- name: config
+  literals:
+  - LOG=info
+`))
+	fSys.WriteFile("/app/base/deployment.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: my-app
+        envFrom:
+        - configMapRef:
+            name: config
+`))
+	fSys.WriteFile("/app/dev/kustomization.yaml", []byte(`
+namespace: dev
+namePrefix: dev-
+resources:
+- ../base
+- debug.yaml
+images:
+- name: my-app
+  newTag: v2
+`))
+	fSys.WriteFile("/app/dev/debug.yaml", []byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+`))
+	fSys.WriteFile("/app/prod/kustomization.yaml", []byte(`
+namespace: prod
+resources:
+- ../base
+images:
+- name: my-app
+  newTag: v1
+replicas:
+- name: my-app
+  count: 3
+`))
+}
+
+func runCompare(t *testing.T, o Options) string {
+	fSys := fs.MakeFakeFS()
+	writeOverlays(fSys)
+	if o.ignore == nil {
+		o.ignore = defaultIgnored
+	}
+	o.left, o.right = "/app/dev", "/app/prod"
+	o.loadRestrictor = loader.RestrictionRootOnly
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	diffs, err := o.RunCompare(
+		validators.MakeFakeValidator(), fSys, rf, pf,
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := o.emit(&out, diffs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return out.String()
+}
+
+func TestCompare(t *testing.T) {
+	actual := runCompare(t, Options{})
+	// The name of the ConfigMap differs in its
+	// prefix, and so in the reference to it.
+	expected := `Deployment.apps/my-app:
+  spec.template.spec.containers[0].envFrom[0].configMapRef.name: dev-config-527kbgg574 | config-t8k872fkhh
+  spec.template.spec.containers[0].image: my-app:v2 | my-app:v1
+Pod/debug: only in /app/dev
+`
+	if actual != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestCompareIgnore(t *testing.T) {
+	actual := runCompare(t, Options{
+		ignore: []string{"metadata", "spec.template"},
+		output: "yaml",
+	})
+	expected := `- fields:
+  - left: 1
+    path: spec.replicas
+    right: 3
+  resource: Deployment.apps/my-app
+- onlyIn: /app/dev
+  resource: Pod/debug
+`
+	if actual != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
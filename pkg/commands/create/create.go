@@ -0,0 +1,30 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package create scaffolds new kustomizations.
+package create
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+// NewCmdCreate returns an instance of 'create' subcommand.
+func NewCmdCreate(out io.Writer, fSys fs.FileSystem) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "create",
+		Short: "Scaffolds a new kustomization",
+		Long:  "",
+		Example: `
+	# Creates an overlay of base in overlays/prod
+	kustomize create overlay overlays/prod --from base --namespace prod
+`,
+		Args: cobra.MinimumNArgs(1),
+	}
+	c.AddCommand(
+		newCmdCreateOverlay(out, fSys),
+	)
+	return c
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package create
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+)
+
+type overlayOptions struct {
+	dir        string
+	from       string
+	namespace  string
+	namePrefix string
+	nameSuffix string
+}
+
+// newCmdCreateOverlay creates an overlay of a base.
+func newCmdCreateOverlay(out io.Writer, fSys fs.FileSystem) *cobra.Command {
+	var o overlayOptions
+
+	cmd := &cobra.Command{
+		Use:   "overlay {dir} --from {base}",
+		Short: "Creates a directory with a kustomization using a base",
+		Example: `
+	# Creates overlays/prod/kustomization.yaml, using base,
+	# with the prod namespace and a -prod name suffix; it
+	# fails if overlays/prod has a kustomization, or base hasn't
+	create overlay overlays/prod --from base --namespace prod --name-suffix -prod
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			return o.RunCreateOverlay(out, fSys)
+		},
+	}
+	cmd.Flags().StringVar(
+		&o.from,
+		"from", "",
+		"Directory of the base the overlay uses.")
+	cmd.Flags().StringVar(
+		&o.namespace,
+		"namespace", "",
+		"Namespace of the overlay's resources.")
+	cmd.Flags().StringVar(
+		&o.namePrefix,
+		"name-prefix", "",
+		"Prefix of the names of the overlay's resources.")
+	cmd.Flags().StringVar(
+		&o.nameSuffix,
+		"name-suffix", "",
+		"Suffix of the names of the overlay's resources.")
+	return cmd
+}
+
+// Validate validates create overlay command.
+func (o *overlayOptions) Validate(args []string) error {
+	if len(args) != 1 {
+		return errors.New("specify the directory of the overlay")
+	}
+	o.dir = args[0]
+	if o.from == "" {
+		return errors.New("specify the base with --from")
+	}
+	return nil
+}
+
+// RunCreateOverlay writes the kustomization of the
+// overlay, making its directory if needed.
+func (o *overlayOptions) RunCreateOverlay(
+	out io.Writer, fSys fs.FileSystem) error {
+	if _, err := kustfile.NewKustomizationFileIn(fSys, o.from); err != nil {
+		return kusterr.WithClass(kusterr.ClassUsage, fmt.Errorf(
+			"--from %s isn't a kustomization directory", o.from))
+	}
+	for _, n := range pgmconfig.KustomizationFileNames {
+		if fSys.Exists(filepath.Join(o.dir, n)) {
+			return kusterr.WithClass(kusterr.ClassUsage, fmt.Errorf(
+				"%s already has a kustomization file", o.dir))
+		}
+	}
+	base, err := relativePath(o.dir, o.from)
+	if err != nil {
+		return err
+	}
+	if err := fSys.MkdirAll(o.dir); err != nil {
+		return err
+	}
+	path := filepath.Join(o.dir, pgmconfig.KustomizationFileNames[0])
+	if err := fSys.WriteFile(path, []byte{}); err != nil {
+		return err
+	}
+	mf, err := kustfile.NewKustomizationFileIn(fSys, o.dir)
+	if err != nil {
+		return err
+	}
+	k, err := mf.Read()
+	if err != nil {
+		return err
+	}
+	k.Resources = []string{base}
+	k.Namespace = o.namespace
+	k.NamePrefix = o.namePrefix
+	k.NameSuffix = o.nameSuffix
+	if err := mf.Write(k); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "created %s\n", path)
+	return err
+}
+
+// relativePath returns the path of target
+// relative to the directory dir.
+func relativePath(dir, target string) (string, error) {
+	if filepath.IsAbs(dir) != filepath.IsAbs(target) {
+		var err error
+		if dir, err = filepath.Abs(dir); err != nil {
+			return "", err
+		}
+		if target, err = filepath.Abs(target); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Rel(dir, target)
+}
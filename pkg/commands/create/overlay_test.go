@@ -0,0 +1,76 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package create
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+func TestRunCreateOverlay(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(`
+resources:
+- deployment.yaml
+`))
+	o := overlayOptions{
+		dir:        "/app/overlays/prod",
+		from:       "/app/base",
+		namespace:  "prod",
+		nameSuffix: "-prod",
+	}
+	var out bytes.Buffer
+	if err := o.RunCreateOverlay(&out, fSys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "created /app/overlays/prod/kustomization.yaml\n" {
+		t.Fatalf("unexpected output %s", out.String())
+	}
+	data, err := fSys.ReadFile("/app/overlays/prod/kustomization.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../../base
+nameSuffix: -prod
+namespace: prod
+`
+	if string(data) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	err = o.RunCreateOverlay(&out, fSys)
+	if err == nil || !strings.Contains(err.Error(), "already has") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRunCreateOverlayNeedsBase(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.Mkdir("/app/base")
+	o := overlayOptions{dir: "/app/prod", from: "/app/base"}
+	err := o.RunCreateOverlay(nil, fSys)
+	if err == nil || !strings.Contains(err.Error(), "isn't a kustomization") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fSys.Exists("/app/prod") {
+		t.Fatalf("created the overlay without a base")
+	}
+}
+
+func TestValidateCreateOverlay(t *testing.T) {
+	o := overlayOptions{}
+	if err := o.Validate([]string{"prod"}); err == nil {
+		t.Fatalf("expected error without --from")
+	}
+	o.from = "base"
+	if err := o.Validate([]string{"prod"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
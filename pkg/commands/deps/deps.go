@@ -0,0 +1,146 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package deps lists the inputs of a kustomization.
+package deps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/render"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/yaml"
+)
+
+// Options contain the options for running deps.
+type Options struct {
+	kustomizationPath string
+	output            string
+	loadRestrictor    loader.LoadRestrictorFunc
+	loader            loader.Options
+}
+
+var examples = `
+To list the files, directories and remote bases that
+'someDir/kustomization.yaml' depends on, run
+
+  kustomize deps someDir
+
+To get the same as JSON, run
+
+  kustomize deps someDir -o json
+`
+
+// NewCmdDeps creates a new deps command.
+func NewCmdDeps(
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, rf *resmap.Factory,
+	ptf resmap.PatchFactory) *cobra.Command {
+	var o Options
+
+	pluginConfig := plugins.DefaultPluginConfig()
+	pl := plugins.NewLoader(pluginConfig, rf)
+
+	cmd := &cobra.Command{
+		Use: "deps {path}",
+		Short: "Print the files, directories and remote bases read by " +
+			pgmconfig.KustomizationFileNames[0],
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			d, err := o.RunDeps(v, fSys, rf, ptf, pl)
+			if err != nil {
+				return err
+			}
+			return o.emit(out, d)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&o.output,
+		"output", "o", "",
+		"One of 'json' or 'yaml'.  If unspecified, print one path per line.")
+	loader.AddFlagLoadRestrictor(cmd.Flags())
+	o.loader.AddFlags(cmd.Flags())
+	plugins.AddFlagEnablePlugins(
+		cmd.Flags(), &pluginConfig.Enabled)
+	return cmd
+}
+
+// Validate validates deps command.
+func (o *Options) Validate(args []string) (err error) {
+	if len(args) > 1 {
+		return errors.New(
+			"specify one path to " + pgmconfig.KustomizationFileNames[0])
+	}
+	if len(args) == 0 {
+		o.kustomizationPath = loader.CWD
+	} else {
+		o.kustomizationPath = args[0]
+	}
+	switch o.output {
+	case "", "json", "yaml":
+	default:
+		return fmt.Errorf("--output must be 'json' or 'yaml'")
+	}
+	o.loadRestrictor, err = loader.ValidateFlagLoadRestrictor()
+	return err
+}
+
+// RunDeps performs a full build, recording everything
+// the loader reads along the way.
+func (o *Options) RunDeps(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader) (loader.Dependencies, error) {
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
+		return loader.Dependencies{}, err
+	}
+	rec := loader.NewDepRecorder()
+	_, err := render.NewRendererWith(fSys, v, rf, ptf, pl).
+		WithLoadRestrictor(o.loadRestrictor).
+		WithLoaderOptions(o.loader).
+		WithTracer(rec).
+		Render(o.kustomizationPath, nil)
+	if err != nil {
+		return loader.Dependencies{}, err
+	}
+	return rec.Dependencies(), nil
+}
+
+func (o *Options) emit(out io.Writer, d loader.Dependencies) error {
+	var b []byte
+	var err error
+	switch o.output {
+	case "json":
+		b, err = json.MarshalIndent(d, "", "  ")
+		b = append(b, '\n')
+	case "yaml":
+		b, err = yaml.Marshal(d)
+	default:
+		for _, l := range [][]string{d.Directories, d.Files, d.Remotes} {
+			for _, p := range l {
+				b = append(b, p...)
+				b = append(b, '\n')
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
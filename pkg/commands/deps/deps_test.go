@@ -0,0 +1,84 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package deps
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestRunDeps(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(`
+resources:
+- service.yaml
+configMapGenerator:
+- name: cm
+  envs:
+  - app.env
+`))
+	fSys.WriteFile("/app/base/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`))
+	fSys.WriteFile("/app/base/app.env", []byte("A=B\n"))
+	fSys.WriteFile("/app/overlay/kustomization.yaml", []byte(`
+resources:
+- ../base
+namePrefix: p-
+`))
+
+	o := Options{
+		kustomizationPath: "/app/overlay",
+		loadRestrictor:    loader.RestrictionRootOnly,
+	}
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	d, err := o.RunDeps(
+		validators.MakeFakeValidator(), fSys, rf,
+		transformer.NewFactoryImpl(),
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := loader.Dependencies{
+		Files: []string{
+			"/app/base/app.env",
+			"/app/base/kustomization.yaml",
+			"/app/base/service.yaml",
+			"/app/overlay/kustomization.yaml",
+		},
+		Directories: []string{"/app/base", "/app/overlay"},
+		Remotes:     []string{},
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected %v, got %v", expected, d)
+	}
+
+	var buf bytes.Buffer
+	if err = o.emit(&buf, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != `/app/base
+/app/overlay
+/app/base/app.env
+/app/base/kustomization.yaml
+/app/base/service.yaml
+/app/overlay/kustomization.yaml
+` {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package diff summarizes, by risk, what applying
+// a kustomization would change in a cluster.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/cluster"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/build"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+// Options contain the options for running diff.
+type Options struct {
+	build *build.Options
+}
+
+var examples = `
+To summarize what applying 'someDir/kustomization.yaml'
+would change in the cluster of the current kubeconfig
+context, run
+
+  kustomize diff someDir
+
+Each changed object is listed with one of
+
+  delete    pruned, per the kustomization's inventory object
+  recreate  immutable fields change, e.g. a Deployment's
+            selector, so the object must be replaced
+  new       not yet in the cluster
+  update    changed in place
+  metadata  only labels, annotations or such change
+
+The flags of 'kustomize build' saying how to build,
+e.g. --kube-version, --set or --context, work the same
+here as they do for 'kustomize apply'.
+
+The cluster is reached as kubectl would reach it,
+per $KUBECONFIG or ~/.kube/config.
+`
+
+// NewCmdDiff creates a new diff command.
+func NewCmdDiff(
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, rf *resmap.Factory,
+	ptf resmap.PatchFactory) *cobra.Command {
+	o := Options{build: build.NewOptions("", "")}
+
+	pluginConfig := plugins.DefaultPluginConfig()
+	pl := plugins.NewLoader(pluginConfig, rf)
+
+	cmd := &cobra.Command{
+		Use: "diff {path}",
+		Short: "Summarize the changes to a cluster of applying " +
+			pgmconfig.KustomizationFileNames[0],
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			k, err := cluster.NewClient(o.build.ContextName())
+			if err != nil {
+				return err
+			}
+			changes, err := o.RunDiff(v, fSys, rf, ptf, pl, k)
+			if err != nil {
+				return err
+			}
+			return emit(out, changes)
+		},
+	}
+	o.build.AddClusterFlags(cmd.Flags(), pluginConfig)
+	return cmd
+}
+
+// Validate validates diff command.
+func (o *Options) Validate(args []string) error {
+	return o.build.Validate(args)
+}
+
+// Change is the change to one object.
+type Change struct {
+	Kind   cluster.ChangeKind
+	Id     resid.ResId
+	Fields []string
+}
+
+// RunDiff builds the kustomization as build does, and
+// compares each object with the one in the cluster.  If
+// the build has an inventory, the objects it would prune
+// are included.
+func (o *Options) RunDiff(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader, k *cluster.Client) ([]Change, error) {
+	objects, err := o.build.Render(v, fSys, rf, ptf, pl)
+	if err != nil {
+		return nil, err
+	}
+	m, err := rf.NewResMapFromBytes(objects)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	if cluster.FindInventory(m) != nil {
+		pruned, err := cluster.Prunable(m, rf, k)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range pruned {
+			changes = append(changes, Change{Kind: cluster.ChangeDelete, Id: id})
+		}
+	}
+	for _, r := range m.Resources() {
+		data, err := k.Get(r.CurId())
+		if err != nil {
+			return nil, err
+		}
+		var live map[string]interface{}
+		if data != nil {
+			l, err := rf.RF().FromBytes(data)
+			if err != nil {
+				return nil, err
+			}
+			live = l.Map()
+		}
+		kind, fields := cluster.Classify(r.GetKind(), r.Map(), live)
+		changes = append(changes, Change{Kind: kind, Id: r.CurId(), Fields: fields})
+	}
+	return changes, nil
+}
+
+// emit writes a table of the changes, riskiest first,
+// and the number of each kind of change.
+func emit(out io.Writer, changes []Change) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CHANGE\tKIND\tNAMESPACE\tNAME\tFIELDS")
+	counts := map[cluster.ChangeKind]int{}
+	for _, c := range changes {
+		counts[c.Kind]++
+	}
+	for kind := cluster.ChangeDelete; kind > cluster.ChangeNone; kind-- {
+		for _, c := range changes {
+			if c.Kind != kind {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				c.Kind, c.Id.Kind, c.Id.Namespace, c.Id.Name,
+				strings.Join(c.Fields, ","))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	var summary []string
+	for kind := cluster.ChangeDelete; kind > cluster.ChangeNone; kind-- {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[kind], kind))
+	}
+	summary = append(summary, fmt.Sprintf(
+		"%d unchanged", counts[cluster.ChangeNone]))
+	_, err := fmt.Fprintln(out, strings.Join(summary, ", "))
+	return err
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/cluster"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/build"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+var live = []string{`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  selector:
+    matchLabels:
+      app: web
+status: {}
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+spec:
+  clusterIP: 10.0.0.1
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inv
+  namespace: prod
+  annotations:
+    kustomize.config.k8s.io/Inventory: '{"current":{"~G_v1_Secret|prod|old":null}}'
+`,
+}
+
+func TestRunDiff(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+namespace: prod
+resources:
+- resources.yaml
+inventory:
+  type: ConfigMap
+  configMap:
+    name: inv
+    namespace: prod
+`))
+	fSys.WriteFile("/app/resources.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web2
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  labels:
+    app: web
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: web
+`))
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	pl := plugins.NewLoader(plugins.DefaultPluginConfig(), rf)
+	k, _, err := cluster.NewFakeClient(live...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	o := Options{build: build.NewOptions("", "")}
+	if err := o.Validate([]string{"/app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	changes, err := o.RunDiff(
+		validator.NewKustValidator(), fSys, rf, pf, pl, k)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := emit(&out, changes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `CHANGE    KIND            NAMESPACE  NAME  FIELDS
+delete    Secret          prod       old   
+recreate  Deployment      prod       web   spec.selector.matchLabels.app
+new       ServiceAccount  prod       web   
+metadata  ConfigMap       prod       inv   metadata.annotations.kustomize.config.k8s.io/Inventory,metadata.annotations.kustomize.config.k8s.io/InventoryHash
+metadata  Service         prod       web   metadata.labels
+1 delete, 1 recreate, 1 new, 0 update, 2 metadata, 0 unchanged
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestNewCmdDiffHasBuildFlags(t *testing.T) {
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	cmd := NewCmdDiff(
+		nil, fs.MakeFakeFS(), validator.NewKustValidator(), rf, pf)
+	for _, name := range []string{
+		"kube-version", "set", "strict", "context",
+		"deprecated-apis", "enable-post-renderers", "load_restrictor"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Fatalf("no flag --%s", name)
+		}
+	}
+}
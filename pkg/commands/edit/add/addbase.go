@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 )
@@ -31,7 +32,7 @@ type addBaseOptions struct {
 }
 
 // newCmdAddBase adds the file path of the kustomize base to the kustomization file.
-func newCmdAddBase(fsys fs.FileSystem) *cobra.Command {
+func newCmdAddBase(fsys fs.FileSystem, dryRun *kustfile.DryRunOptions) *cobra.Command {
 	var o addBaseOptions
 
 	cmd := &cobra.Command{
@@ -42,13 +43,13 @@ func newCmdAddBase(fsys fs.FileSystem) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
 			if err != nil {
-				return err
+				return kusterr.WithClass(kusterr.ClassUsage, err)
 			}
 			err = o.Complete(cmd, args)
 			if err != nil {
 				return err
 			}
-			return o.RunAddBase(fsys)
+			return o.RunAddBase(fsys, dryRun)
 		},
 	}
 	return cmd
@@ -69,8 +70,8 @@ func (o *addBaseOptions) Complete(cmd *cobra.Command, args []string) error {
 }
 
 // RunAddBase runs addBase command (do real work).
-func (o *addBaseOptions) RunAddBase(fSys fs.FileSystem) error {
-	mf, err := kustfile.NewKustomizationFile(fSys)
+func (o *addBaseOptions) RunAddBase(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions) error {
+	mf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 	if err != nil {
 		return err
 	}
@@ -36,7 +36,7 @@ func TestAddBaseHappyPath(t *testing.T) {
 	}
 	fakeFS.WriteTestKustomization()
 
-	cmd := newCmdAddBase(fakeFS)
+	cmd := newCmdAddBase(fakeFS, nil)
 	args := []string{baseDirectoryPaths}
 	err := cmd.RunE(cmd, args)
 	if err != nil {
@@ -63,7 +63,7 @@ func TestAddBaseAlreadyThere(t *testing.T) {
 	}
 	fakeFS.WriteTestKustomization()
 
-	cmd := newCmdAddBase(fakeFS)
+	cmd := newCmdAddBase(fakeFS, nil)
 	args := []string{baseDirectoryPaths}
 	err := cmd.RunE(cmd, args)
 	if err != nil {
@@ -88,7 +88,7 @@ func TestAddBaseAlreadyThere(t *testing.T) {
 func TestAddBaseNoArgs(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 
-	cmd := newCmdAddBase(fakeFS)
+	cmd := newCmdAddBase(fakeFS, nil)
 	err := cmd.Execute()
 	if err == nil {
 		t.Errorf("expected error: %v", err)
@@ -54,7 +54,7 @@ type addMetadataOptions struct {
 }
 
 // newCmdAddAnnotation adds one or more commonAnnotations to the kustomization file.
-func newCmdAddAnnotation(fSys fs.FileSystem, v func(map[string]string) error) *cobra.Command {
+func newCmdAddAnnotation(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions, v func(map[string]string) error) *cobra.Command {
 	var o addMetadataOptions
 	o.kind = annotation
 	o.mapValidator = v
@@ -64,7 +64,7 @@ func newCmdAddAnnotation(fSys fs.FileSystem, v func(map[string]string) error) *c
 		Example: `
 		add annotation {annotationKey1:annotationValue1},{annotationKey2:annotationValue2}`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return o.runE(args, fSys, o.addAnnotations)
+			return o.runE(args, fSys, dryRun, o.addAnnotations)
 		},
 	}
 	cmd.Flags().BoolVarP(&o.force, "force", "f", false,
@@ -74,7 +74,7 @@ func newCmdAddAnnotation(fSys fs.FileSystem, v func(map[string]string) error) *c
 }
 
 // newCmdAddLabel adds one or more commonLabels to the kustomization file.
-func newCmdAddLabel(fSys fs.FileSystem, v func(map[string]string) error) *cobra.Command {
+func newCmdAddLabel(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions, v func(map[string]string) error) *cobra.Command {
 	var o addMetadataOptions
 	o.kind = label
 	o.mapValidator = v
@@ -84,7 +84,7 @@ func newCmdAddLabel(fSys fs.FileSystem, v func(map[string]string) error) *cobra.
 		Example: `
 		add label {labelKey1:labelValue1},{labelKey2:labelValue2}`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return o.runE(args, fSys, o.addLabels)
+			return o.runE(args, fSys, dryRun, o.addLabels)
 		},
 	}
 	cmd.Flags().BoolVarP(&o.force, "force", "f", false,
@@ -94,12 +94,12 @@ func newCmdAddLabel(fSys fs.FileSystem, v func(map[string]string) error) *cobra.
 }
 
 func (o *addMetadataOptions) runE(
-	args []string, fSys fs.FileSystem, adder func(*types.Kustomization) error) error {
+	args []string, fSys fs.FileSystem, dryRun *kustfile.DryRunOptions, adder func(*types.Kustomization) error) error {
 	err := o.validateAndParse(args)
 	if err != nil {
 		return err
 	}
-	kf, err := kustfile.NewKustomizationFile(fSys)
+	kf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 	if err != nil {
 		return err
 	}
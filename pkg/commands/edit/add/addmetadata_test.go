@@ -65,7 +65,7 @@ func TestRunAddAnnotation(t *testing.T) {
 func TestAddAnnotationNoArgs(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd := newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	err := cmd.Execute()
 	v.VerifyNoCall()
 	if err == nil {
@@ -79,7 +79,7 @@ func TestAddAnnotationNoArgs(t *testing.T) {
 func TestAddAnnotationInvalidFormat(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	v := validators.MakeSadMapValidator(t)
-	cmd := newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd := newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	args := []string{"whatever:whatever"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -95,7 +95,7 @@ func TestAddAnnotationManyArgs(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd := newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	args := []string{"k1:v1,k2:v2,k3:v3,k4:v5"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -108,7 +108,7 @@ func TestAddAnnotationValueQuoted(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd := newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	args := []string{"k1:\"v1\""}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -121,7 +121,7 @@ func TestAddAnnotationValueWithColon(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd := newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	args := []string{"k1:\"v1:v2\""}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -133,7 +133,7 @@ func TestAddAnnotationValueWithColon(t *testing.T) {
 func TestAddAnnotationNoKey(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd := newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	args := []string{":nokey"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyNoCall()
@@ -149,7 +149,7 @@ func TestAddAnnotationTooManyColons(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd := newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	args := []string{"key:v1:v2"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -162,7 +162,7 @@ func TestAddAnnotationNoValue(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd := newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	args := []string{"no:,value"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -175,7 +175,7 @@ func TestAddAnnotationMultipleArgs(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd := newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	args := []string{"this:annotation", "has:spaces"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyNoCall()
@@ -191,7 +191,7 @@ func TestAddAnnotationForce(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd := newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	args := []string{"key:foo"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -201,7 +201,7 @@ func TestAddAnnotationForce(t *testing.T) {
 	// trying to add the same annotation again should not work
 	args = []string{"key:bar"}
 	v = validators.MakeHappyMapValidator(t)
-	cmd = newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd = newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	err = cmd.RunE(cmd, args)
 	v.VerifyCall()
 	if err == nil {
@@ -212,7 +212,7 @@ func TestAddAnnotationForce(t *testing.T) {
 	}
 	// but trying to add it with --force should
 	v = validators.MakeHappyMapValidator(t)
-	cmd = newCmdAddAnnotation(fakeFS, v.Validator)
+	cmd = newCmdAddAnnotation(fakeFS, nil, v.Validator)
 	cmd.Flag("force").Value.Set("true")
 	err = cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -246,7 +246,7 @@ func TestRunAddLabel(t *testing.T) {
 func TestAddLabelNoArgs(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddLabel(fakeFS, v.Validator)
+	cmd := newCmdAddLabel(fakeFS, nil, v.Validator)
 	err := cmd.Execute()
 	v.VerifyNoCall()
 	if err == nil {
@@ -260,7 +260,7 @@ func TestAddLabelNoArgs(t *testing.T) {
 func TestAddLabelInvalidFormat(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	v := validators.MakeSadMapValidator(t)
-	cmd := newCmdAddLabel(fakeFS, v.Validator)
+	cmd := newCmdAddLabel(fakeFS, nil, v.Validator)
 	args := []string{"exclamation!:point"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -275,7 +275,7 @@ func TestAddLabelInvalidFormat(t *testing.T) {
 func TestAddLabelNoKey(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddLabel(fakeFS, v.Validator)
+	cmd := newCmdAddLabel(fakeFS, nil, v.Validator)
 	args := []string{":nokey"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyNoCall()
@@ -291,7 +291,7 @@ func TestAddLabelTooManyColons(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddLabel(fakeFS, v.Validator)
+	cmd := newCmdAddLabel(fakeFS, nil, v.Validator)
 	args := []string{"key:v1:v2"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -304,7 +304,7 @@ func TestAddLabelNoValue(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddLabel(fakeFS, v.Validator)
+	cmd := newCmdAddLabel(fakeFS, nil, v.Validator)
 	args := []string{"no,value:"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -317,7 +317,7 @@ func TestAddLabelMultipleArgs(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddLabel(fakeFS, v.Validator)
+	cmd := newCmdAddLabel(fakeFS, nil, v.Validator)
 	args := []string{"this:input", "has:spaces"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyNoCall()
@@ -333,7 +333,7 @@ func TestAddLabelForce(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdAddLabel(fakeFS, v.Validator)
+	cmd := newCmdAddLabel(fakeFS, nil, v.Validator)
 	args := []string{"key:foo"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -343,7 +343,7 @@ func TestAddLabelForce(t *testing.T) {
 	// trying to add the same label again should not work
 	args = []string{"key:bar"}
 	v = validators.MakeHappyMapValidator(t)
-	cmd = newCmdAddLabel(fakeFS, v.Validator)
+	cmd = newCmdAddLabel(fakeFS, nil, v.Validator)
 	err = cmd.RunE(cmd, args)
 	v.VerifyCall()
 	if err == nil {
@@ -354,7 +354,7 @@ func TestAddLabelForce(t *testing.T) {
 	}
 	// but trying to add it with --force should
 	v = validators.MakeHappyMapValidator(t)
-	cmd = newCmdAddLabel(fakeFS, v.Validator)
+	cmd = newCmdAddLabel(fakeFS, nil, v.Validator)
 	cmd.Flag("force").Value.Set("true")
 	err = cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -21,6 +21,7 @@ import (
 	"log"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/edit/util"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
@@ -32,7 +33,7 @@ type addPatchOptions struct {
 }
 
 // newCmdAddPatch adds the name of a file containing a patch to the kustomization file.
-func newCmdAddPatch(fsys fs.FileSystem) *cobra.Command {
+func newCmdAddPatch(fsys fs.FileSystem, dryRun *kustfile.DryRunOptions) *cobra.Command {
 	var o addPatchOptions
 
 	cmd := &cobra.Command{
@@ -43,13 +44,13 @@ func newCmdAddPatch(fsys fs.FileSystem) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
 			if err != nil {
-				return err
+				return kusterr.WithClass(kusterr.ClassUsage, err)
 			}
 			err = o.Complete(cmd, args)
 			if err != nil {
 				return err
 			}
-			return o.RunAddPatch(fsys)
+			return o.RunAddPatch(fsys, dryRun)
 		},
 	}
 	return cmd
@@ -70,7 +71,7 @@ func (o *addPatchOptions) Complete(cmd *cobra.Command, args []string) error {
 }
 
 // RunAddPatch runs addPatch command (do real work).
-func (o *addPatchOptions) RunAddPatch(fSys fs.FileSystem) error {
+func (o *addPatchOptions) RunAddPatch(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions) error {
 	patches, err := util.GlobPatterns(fSys, o.patchFilePaths)
 	if err != nil {
 		return err
@@ -79,7 +80,7 @@ func (o *addPatchOptions) RunAddPatch(fSys fs.FileSystem) error {
 		return nil
 	}
 
-	mf, err := kustfile.NewKustomizationFile(fSys)
+	mf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 	if err != nil {
 		return err
 	}
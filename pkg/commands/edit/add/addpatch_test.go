@@ -38,7 +38,7 @@ func TestAddPatchHappyPath(t *testing.T) {
 	fakeFS.WriteFile(patchFileName+"another", []byte(patchFileContent))
 	fakeFS.WriteTestKustomization()
 
-	cmd := newCmdAddPatch(fakeFS)
+	cmd := newCmdAddPatch(fakeFS, nil)
 	args := []string{patchFileName + "*"}
 	err := cmd.RunE(cmd, args)
 	if err != nil {
@@ -61,7 +61,7 @@ func TestAddPatchAlreadyThere(t *testing.T) {
 	fakeFS.WriteFile(patchFileName, []byte(patchFileContent))
 	fakeFS.WriteTestKustomization()
 
-	cmd := newCmdAddPatch(fakeFS)
+	cmd := newCmdAddPatch(fakeFS, nil)
 	args := []string{patchFileName}
 	err := cmd.RunE(cmd, args)
 	if err != nil {
@@ -78,7 +78,7 @@ func TestAddPatchAlreadyThere(t *testing.T) {
 func TestAddPatchNoArgs(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 
-	cmd := newCmdAddPatch(fakeFS)
+	cmd := newCmdAddPatch(fakeFS, nil)
 	err := cmd.Execute()
 	if err == nil {
 		t.Errorf("expected error: %v", err)
@@ -21,6 +21,7 @@ import (
 	"log"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/edit/util"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
@@ -31,7 +32,7 @@ type addResourceOptions struct {
 }
 
 // newCmdAddResource adds the name of a file containing a resource to the kustomization file.
-func newCmdAddResource(fsys fs.FileSystem) *cobra.Command {
+func newCmdAddResource(fsys fs.FileSystem, dryRun *kustfile.DryRunOptions) *cobra.Command {
 	var o addResourceOptions
 
 	cmd := &cobra.Command{
@@ -42,13 +43,13 @@ func newCmdAddResource(fsys fs.FileSystem) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
 			if err != nil {
-				return err
+				return kusterr.WithClass(kusterr.ClassUsage, err)
 			}
 			err = o.Complete(cmd, args)
 			if err != nil {
 				return err
 			}
-			return o.RunAddResource(fsys)
+			return o.RunAddResource(fsys, dryRun)
 		},
 	}
 	return cmd
@@ -69,7 +70,7 @@ func (o *addResourceOptions) Complete(cmd *cobra.Command, args []string) error {
 }
 
 // RunAddResource runs addResource command (do real work).
-func (o *addResourceOptions) RunAddResource(fSys fs.FileSystem) error {
+func (o *addResourceOptions) RunAddResource(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions) error {
 	resources, err := util.GlobPatterns(fSys, o.resourceFilePaths)
 	if err != nil {
 		return err
@@ -78,7 +79,7 @@ func (o *addResourceOptions) RunAddResource(fSys fs.FileSystem) error {
 		return nil
 	}
 
-	mf, err := kustfile.NewKustomizationFile(fSys)
+	mf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 	if err != nil {
 		return err
 	}
@@ -37,7 +37,7 @@ func TestAddResourceHappyPath(t *testing.T) {
 	fakeFS.WriteFile(resourceFileName+"another", []byte(resourceFileContent))
 	fakeFS.WriteTestKustomization()
 
-	cmd := newCmdAddResource(fakeFS)
+	cmd := newCmdAddResource(fakeFS, nil)
 	args := []string{resourceFileName + "*"}
 	err := cmd.RunE(cmd, args)
 	if err != nil {
@@ -60,7 +60,7 @@ func TestAddResourceAlreadyThere(t *testing.T) {
 	fakeFS.WriteFile(resourceFileName, []byte(resourceFileContent))
 	fakeFS.WriteTestKustomization()
 
-	cmd := newCmdAddResource(fakeFS)
+	cmd := newCmdAddResource(fakeFS, nil)
 	args := []string{resourceFileName}
 	err := cmd.RunE(cmd, args)
 	if err != nil {
@@ -77,7 +77,7 @@ func TestAddResourceAlreadyThere(t *testing.T) {
 func TestAddResourceNoArgs(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 
-	cmd := newCmdAddResource(fakeFS)
+	cmd := newCmdAddResource(fakeFS, nil)
 	err := cmd.Execute()
 	if err == nil {
 		t.Errorf("expected error: %v", err)
@@ -5,6 +5,7 @@ package add
 
 import (
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 )
@@ -12,6 +13,7 @@ import (
 // NewCmdAdd returns an instance of 'add' subcommand.
 func NewCmdAdd(
 	fSys fs.FileSystem,
+	dryRun *kustfile.DryRunOptions,
 	ldr ifc.Loader,
 	kf ifc.KunstructuredFactory) *cobra.Command {
 	c := &cobra.Command{
@@ -44,13 +46,13 @@ func NewCmdAdd(
 		Args: cobra.MinimumNArgs(1),
 	}
 	c.AddCommand(
-		newCmdAddResource(fSys),
-		newCmdAddPatch(fSys),
-		newCmdAddSecret(fSys, ldr, kf),
-		newCmdAddConfigMap(fSys, ldr, kf),
-		newCmdAddBase(fSys),
-		newCmdAddLabel(fSys, ldr.Validator().MakeLabelValidator()),
-		newCmdAddAnnotation(fSys, ldr.Validator().MakeAnnotationValidator()),
+		newCmdAddResource(fSys, dryRun),
+		newCmdAddPatch(fSys, dryRun),
+		newCmdAddSecret(fSys, dryRun, ldr, kf),
+		newCmdAddConfigMap(fSys, dryRun, ldr, kf),
+		newCmdAddBase(fSys, dryRun),
+		newCmdAddLabel(fSys, dryRun, ldr.Validator().MakeLabelValidator()),
+		newCmdAddAnnotation(fSys, dryRun, ldr.Validator().MakeAnnotationValidator()),
 	)
 	return c
 }
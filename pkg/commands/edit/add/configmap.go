@@ -5,6 +5,7 @@ package add
 
 import (
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
@@ -14,13 +15,15 @@ import (
 // newCmdAddConfigMap returns a new command.
 func newCmdAddConfigMap(
 	fSys fs.FileSystem,
+	dryRun *kustfile.DryRunOptions,
 	ldr ifc.Loader,
 	kf ifc.KunstructuredFactory) *cobra.Command {
 	var flags flagsAndArgs
 	cmd := &cobra.Command{
-		Use:   "configmap NAME [--from-file=[key=]source] [--from-literal=key1=value1]",
+		Use:   "configmap NAME [--from-file=[key=]source] [--from-literal=key1=value1] [--from-env-file=source] [--disableNameSuffixHash]",
 		Short: "Adds a configmap to the kustomization file.",
-		Long:  "",
+		Long: `Adds a configmap to the kustomization file, or, if it has one of
+that name, adds the keys to it, those it has replacing its own.`,
 		Example: `
 	# Adds a configmap to the kustomization file (with a specified key)
 	kustomize edit add configmap my-configmap --from-file=my-key=file/path --from-literal=my-literal=12345
@@ -30,8 +33,12 @@ func newCmdAddConfigMap(
 
 	# Adds a configmap from env-file
 	kustomize edit add configmap my-configmap --from-env-file=env/path.env
+
+	# Adds a configmap whose name has no hash suffix
+	kustomize edit add configmap my-configmap --from-literal=my-literal=12345 --disableNameSuffixHash
 `,
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(c *cobra.Command, args []string) error {
+			flags.setFlagsChanged(c.Flags())
 			err := flags.ExpandFileSource(fSys)
 			if err != nil {
 				return err
@@ -39,11 +46,11 @@ func newCmdAddConfigMap(
 
 			err = flags.Validate(args)
 			if err != nil {
-				return err
+				return kusterr.WithClass(kusterr.ClassUsage, err)
 			}
 
 			// Load the kustomization file.
-			mf, err := kustfile.NewKustomizationFile(fSys)
+			mf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 			if err != nil {
 				return err
 			}
@@ -81,6 +88,7 @@ func newCmdAddConfigMap(
 		"from-env-file",
 		"",
 		"Specify the path to a file to read lines of key=val pairs to create a configmap (i.e. a Docker .env file).")
+	flags.addFlagDisableNameSuffixHash(cmd.Flags(), "configmap")
 
 	return cmd
 }
@@ -93,7 +101,7 @@ func addConfigMap(
 	k *types.Kustomization,
 	flags flagsAndArgs, kf ifc.KunstructuredFactory) error {
 	args := findOrMakeConfigMapArgs(k, flags.Name)
-	mergeFlagsIntoCmArgs(args, flags)
+	mergeFlagsIntoGeneratorArgs(&args.GeneratorArgs, flags)
 	// Validate by trying to create corev1.configmap.
 	_, err := kf.MakeConfigMap(ldr, k.GeneratorOptions, args)
 	if err != nil {
@@ -113,18 +121,3 @@ func findOrMakeConfigMapArgs(m *types.Kustomization, name string) *types.ConfigM
 	m.ConfigMapGenerator = append(m.ConfigMapGenerator, *cm)
 	return &m.ConfigMapGenerator[len(m.ConfigMapGenerator)-1]
 }
-
-func mergeFlagsIntoCmArgs(args *types.ConfigMapArgs, flags flagsAndArgs) {
-	if len(flags.LiteralSources) > 0 {
-		args.LiteralSources = append(
-			args.LiteralSources, flags.LiteralSources...)
-	}
-	if len(flags.FileSources) > 0 {
-		args.FileSources = append(
-			args.FileSources, flags.FileSources...)
-	}
-	if flags.EnvFileSource != "" {
-		args.EnvSources = append(
-			args.EnvSources, flags.EnvFileSource)
-	}
-}
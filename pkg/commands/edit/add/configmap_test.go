@@ -4,8 +4,11 @@
 package add
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/loader"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
@@ -15,7 +18,7 @@ import (
 func TestNewAddConfigMapIsNotNil(t *testing.T) {
 	fSys := fs.MakeFakeFS()
 	ldr := loader.NewFileLoaderAtCwd(validators.MakeFakeValidator(), fSys)
-	if newCmdAddConfigMap(fSys, ldr, nil) == nil {
+	if newCmdAddConfigMap(fSys, nil, ldr, nil) == nil {
 		t.Fatal("newCmdAddConfigMap shouldn't be nil")
 	}
 }
@@ -105,3 +108,84 @@ func TestMergeFlagsIntoConfigMapArgs_EnvSource(t *testing.T) {
 		t.Fatalf("expected env2")
 	}
 }
+
+func TestMergeFlagsIntoConfigMapArgs_ReplacesKeys(t *testing.T) {
+	k := &types.Kustomization{}
+	args := findOrMakeConfigMapArgs(k, "foo")
+	mergeFlagsIntoGeneratorArgs(
+		&args.GeneratorArgs,
+		flagsAndArgs{
+			LiteralSources: []string{"k1=v1", "k2=v2"},
+			FileSources:    []string{"dir/app.properties", "k3=file3"}})
+	mergeFlagsIntoGeneratorArgs(
+		&args.GeneratorArgs,
+		flagsAndArgs{
+			LiteralSources: []string{"k1=v1b", "app.properties=inline"},
+			FileSources:    []string{"k2=file2"},
+			EnvFileSource:  "env1"})
+	mergeFlagsIntoGeneratorArgs(
+		&args.GeneratorArgs,
+		flagsAndArgs{EnvFileSource: "env1"})
+	if !reflect.DeepEqual(args.LiteralSources,
+		[]string{"k1=v1b", "app.properties=inline"}) {
+		t.Fatalf("unexpected literals %v", args.LiteralSources)
+	}
+	if !reflect.DeepEqual(args.FileSources, []string{"k3=file3", "k2=file2"}) {
+		t.Fatalf("unexpected files %v", args.FileSources)
+	}
+	if !reflect.DeepEqual(args.EnvSources, []string{"env1"}) {
+		t.Fatalf("unexpected env files %v", args.EnvSources)
+	}
+}
+
+func TestMergeFlagsIntoConfigMapArgs_DisableNameSuffixHash(t *testing.T) {
+	k := &types.Kustomization{}
+	args := findOrMakeConfigMapArgs(k, "foo")
+	disable := true
+	mergeFlagsIntoGeneratorArgs(
+		&args.GeneratorArgs,
+		flagsAndArgs{DisableNameSuffixHash: &disable})
+	if args.Options == nil || !args.Options.DisableNameSuffixHash {
+		t.Fatalf("expected disableNameSuffixHash, got %+v", args.Options)
+	}
+	mergeFlagsIntoGeneratorArgs(
+		&args.GeneratorArgs,
+		flagsAndArgs{LiteralSources: []string{"k1=v1"}})
+	if args.Options == nil || !args.Options.DisableNameSuffixHash {
+		t.Fatalf("expected disableNameSuffixHash kept, got %+v", args.Options)
+	}
+	disable = false
+	mergeFlagsIntoGeneratorArgs(
+		&args.GeneratorArgs,
+		flagsAndArgs{DisableNameSuffixHash: &disable})
+	if args.Options != nil {
+		t.Fatalf("expected no options, got %+v", args.Options)
+	}
+}
+
+func TestAddConfigMapMergesExisting(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("kustomization.yaml", []byte(`configMapGenerator:
+- name: foo
+  literals:
+  - k1=v1
+`))
+	ldr := loader.NewFileLoaderAtCwd(validators.MakeFakeValidator(), fSys)
+	cmd := newCmdAddConfigMap(fSys, nil, ldr, kunstruct.NewKunstructuredFactoryImpl())
+	cmd.SetArgs([]string{
+		"foo", "--from-literal=k1=v2", "--from-literal=k2=v2",
+		"--disableNameSuffixHash"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := fSys.ReadFile("kustomization.yaml")
+	for _, expected := range []string{
+		"- k1=v2\n", "- k2=v2\n", "disableNameSuffixHash: true\n"} {
+		if !strings.Contains(string(b), expected) {
+			t.Fatalf("expected %q in:\n%s", expected, b)
+		}
+	}
+	if strings.Contains(string(b), "k1=v1") {
+		t.Fatalf("expected k1=v1 replaced in:\n%s", b)
+	}
+}
@@ -18,10 +18,14 @@ package add
 
 import (
 	"fmt"
+	"path"
+	"reflect"
 	"strings"
 
+	"github.com/spf13/pflag"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/edit/util"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 )
 
 // flagsAndArgs encapsulates the options for add secret/configmap commands.
@@ -37,6 +41,15 @@ type flagsAndArgs struct {
 	EnvFileSource string
 	// Type of secret to create
 	Type string
+	// typeGiven is true if the Type flag was given,
+	// so it replaces that of an existing secret.
+	typeGiven bool
+	// DisableNameSuffixHash, if not nil, says whether
+	// the generated name lacks a hash suffix.
+	DisableNameSuffixHash *bool
+	// disableNameSuffixHash is the value of the flag,
+	// which DisableNameSuffixHash points to if given.
+	disableNameSuffixHash bool
 }
 
 // Validate validates required fields are set to support structured generation.
@@ -55,6 +68,106 @@ func (a *flagsAndArgs) Validate(args []string) error {
 	return nil
 }
 
+const (
+	flagDisableNameSuffixHash = "disableNameSuffixHash"
+	flagType                  = "type"
+)
+
+// addFlagDisableNameSuffixHash adds the flag setting
+// DisableNameSuffixHash, as setFlagsChanged finds it.
+func (a *flagsAndArgs) addFlagDisableNameSuffixHash(set *pflag.FlagSet, kind string) {
+	set.BoolVar(
+		&a.disableNameSuffixHash,
+		flagDisableNameSuffixHash,
+		false,
+		"Don't append a hash of the "+kind+"'s content to its name, "+
+			"setting the generator's disableNameSuffixHash option.")
+}
+
+// setFlagsChanged sets the fields of the flags that
+// are only set if given.
+func (a *flagsAndArgs) setFlagsChanged(set *pflag.FlagSet) {
+	if set.Changed(flagDisableNameSuffixHash) {
+		a.DisableNameSuffixHash = &a.disableNameSuffixHash
+	}
+	a.typeGiven = set.Changed(flagType)
+}
+
+// mergeFlagsIntoGeneratorArgs adds the sources of the
+// flags to args, those with a key args has replacing
+// its own, and an env file it has already not added
+// again, and sets its options.
+func mergeFlagsIntoGeneratorArgs(args *types.GeneratorArgs, flags flagsAndArgs) {
+	for _, s := range flags.LiteralSources {
+		dropKey(args, literalSourceKey(s))
+		args.LiteralSources = append(args.LiteralSources, s)
+	}
+	for _, s := range flags.FileSources {
+		dropKey(args, fileSourceKey(s))
+		args.FileSources = append(args.FileSources, s)
+	}
+	if flags.EnvFileSource != "" && !contains(args.EnvSources, flags.EnvFileSource) {
+		args.EnvSources = append(args.EnvSources, flags.EnvFileSource)
+	}
+	if flags.DisableNameSuffixHash != nil {
+		if args.Options == nil {
+			args.Options = &types.GeneratorOptions{}
+		}
+		args.Options.DisableNameSuffixHash = *flags.DisableNameSuffixHash
+		if reflect.DeepEqual(*args.Options, types.GeneratorOptions{}) {
+			args.Options = nil
+		}
+	}
+}
+
+// dropKey removes the literal and file sources of
+// key from args.
+func dropKey(args *types.GeneratorArgs, key string) {
+	if key == "" {
+		return
+	}
+	var literals, files []string
+	for _, s := range args.LiteralSources {
+		if literalSourceKey(s) != key {
+			literals = append(literals, s)
+		}
+	}
+	for _, s := range args.FileSources {
+		if fileSourceKey(s) != key {
+			files = append(files, s)
+		}
+	}
+	args.LiteralSources, args.FileSources = literals, files
+}
+
+// literalSourceKey returns the key of a key=value
+// source, or empty if it has none.
+func literalSourceKey(s string) string {
+	i := strings.Index(s, "=")
+	if i < 0 {
+		return ""
+	}
+	return s[:i]
+}
+
+// fileSourceKey returns the key of a [key=]path
+// source, the basename of the path if it has none.
+func fileSourceKey(s string) string {
+	if i := strings.Index(s, "="); i >= 0 {
+		return s[:i]
+	}
+	return path.Base(s)
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
 // ExpandFileSource normalizes a string list, possibly
 // containing globs, into a validated, globless list.
 // For example, this list:
@@ -5,6 +5,7 @@ package add
 
 import (
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
@@ -14,13 +15,16 @@ import (
 // newCmdAddSecret returns a new command.
 func newCmdAddSecret(
 	fSys fs.FileSystem,
+	dryRun *kustfile.DryRunOptions,
 	ldr ifc.Loader,
 	kf ifc.KunstructuredFactory) *cobra.Command {
 	var flags flagsAndArgs
 	cmd := &cobra.Command{
-		Use:   "secret NAME [--from-file=[key=]source] [--from-literal=key1=value1] [--type=Opaque|kubernetes.io/tls]",
+		Use:   "secret NAME [--from-file=[key=]source] [--from-literal=key1=value1] [--from-env-file=source] [--type=Opaque|kubernetes.io/tls] [--disableNameSuffixHash]",
 		Short: "Adds a secret to the kustomization file.",
-		Long:  "",
+		Long: `Adds a secret to the kustomization file, or, if it has one of
+that name, adds the keys to it, those it has replacing its own, and
+sets its type if --type is given.`,
 		Example: `
 	# Adds a secret to the kustomization file (with a specified key)
 	kustomize edit add secret my-secret --from-file=my-key=file/path --from-literal=my-literal=12345
@@ -30,8 +34,12 @@ func newCmdAddSecret(
 
 	# Adds a secret from env-file
 	kustomize edit add secret my-secret --from-env-file=env/path.env
+
+	# Adds a TLS secret whose name has no hash suffix
+	kustomize edit add secret my-tls --type=kubernetes.io/tls --from-file=tls.crt --from-file=tls.key --disableNameSuffixHash
 `,
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(c *cobra.Command, args []string) error {
+			flags.setFlagsChanged(c.Flags())
 			err := flags.ExpandFileSource(fSys)
 			if err != nil {
 				return err
@@ -39,11 +47,11 @@ func newCmdAddSecret(
 
 			err = flags.Validate(args)
 			if err != nil {
-				return err
+				return kusterr.WithClass(kusterr.ClassUsage, err)
 			}
 
 			// Load the kustomization file.
-			mf, err := kustfile.NewKustomizationFile(fSys)
+			mf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 			if err != nil {
 				return err
 			}
@@ -83,9 +91,10 @@ func newCmdAddSecret(
 		"Specify the path to a file to read lines of key=val pairs to create a secret (i.e. a Docker .env file).")
 	cmd.Flags().StringVar(
 		&flags.Type,
-		"type",
+		flagType,
 		"Opaque",
 		"Specify the secret type this can be 'Opaque' (default), or 'kubernetes.io/tls'")
+	flags.addFlagDisableNameSuffixHash(cmd.Flags(), "secret")
 
 	return cmd
 }
@@ -98,6 +107,9 @@ func addSecret(
 	k *types.Kustomization,
 	flags flagsAndArgs, kf ifc.KunstructuredFactory) error {
 	args := findOrMakeSecretArgs(k, flags.Name, flags.Type)
+	if flags.typeGiven {
+		args.Type = flags.Type
+	}
 	mergeFlagsIntoGeneratorArgs(&args.GeneratorArgs, flags)
 	// Validate by trying to create corev1.secret.
 	_, err := kf.MakeSecret(ldr, k.GeneratorOptions, args)
@@ -120,18 +132,3 @@ func findOrMakeSecretArgs(m *types.Kustomization, name, secretType string) *type
 	m.SecretGenerator = append(m.SecretGenerator, *secret)
 	return &m.SecretGenerator[len(m.SecretGenerator)-1]
 }
-
-func mergeFlagsIntoGeneratorArgs(args *types.GeneratorArgs, flags flagsAndArgs) {
-	if len(flags.LiteralSources) > 0 {
-		args.LiteralSources = append(
-			args.LiteralSources, flags.LiteralSources...)
-	}
-	if len(flags.FileSources) > 0 {
-		args.FileSources = append(
-			args.FileSources, flags.FileSources...)
-	}
-	if flags.EnvFileSource != "" {
-		args.EnvSources = append(
-			args.EnvSources, flags.EnvFileSource)
-	}
-}
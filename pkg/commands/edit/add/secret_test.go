@@ -4,8 +4,10 @@
 package add
 
 import (
+	"strings"
 	"testing"
 
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/loader"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
@@ -15,7 +17,7 @@ import (
 func TestNewCmdAddSecretIsNotNil(t *testing.T) {
 	fSys := fs.MakeFakeFS()
 	ldr := loader.NewFileLoaderAtCwd(validators.MakeFakeValidator(), fSys)
-	if newCmdAddSecret(fSys, ldr, nil) == nil {
+	if newCmdAddSecret(fSys, nil, ldr, nil) == nil {
 		t.Fatal("newCmdAddSecret shouldn't be nil")
 	}
 }
@@ -107,3 +109,31 @@ func TestMergeFlagsIntoSecretArgs_EnvSource(t *testing.T) {
 		t.Fatalf("expected env2")
 	}
 }
+
+func TestAddSecretSetsGivenType(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("kustomization.yaml", []byte(`secretGenerator:
+- name: foo
+  literals:
+  - k1=v1
+  type: kubernetes.io/basic-auth
+`))
+	ldr := loader.NewFileLoaderAtCwd(validators.MakeFakeValidator(), fSys)
+	for _, tc := range []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"foo", "--from-literal=k2=v2"}, "type: kubernetes.io/basic-auth\n"},
+		{[]string{"foo", "--from-literal=k3=v3", "--type=Opaque"}, "type: Opaque\n"},
+	} {
+		cmd := newCmdAddSecret(fSys, nil, ldr, kunstruct.NewKunstructuredFactoryImpl())
+		cmd.SetArgs(tc.args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.args, err)
+		}
+		b, _ := fSys.ReadFile("kustomization.yaml")
+		if !strings.Contains(string(b), tc.expected) {
+			t.Fatalf("%v: expected %q in:\n%s", tc.args, tc.expected, b)
+		}
+	}
+}
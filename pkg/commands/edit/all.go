@@ -4,11 +4,15 @@
 package edit
 
 import (
+	"io"
+
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/edit/add"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/edit/fix"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/edit/remove"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/edit/set"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/loader"
@@ -16,8 +20,11 @@ import (
 
 // NewCmdEdit returns an instance of 'edit' subcommand.
 func NewCmdEdit(
-	fSys fs.FileSystem, v ifc.Validator, kf ifc.KunstructuredFactory) *cobra.Command {
-	c := &cobra.Command{
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, kf ifc.KunstructuredFactory) *cobra.Command {
+	var dryRun kustfile.DryRunOptions
+	var c *cobra.Command
+	c = &cobra.Command{
 		Use:   "edit",
 		Short: "Edits a kustomization file",
 		Long:  "",
@@ -30,15 +37,29 @@ func NewCmdEdit(
 
 	# Sets the namesuffix field
 	kustomize edit set namesuffix <suffix-value>
+
+	# Shows what adding a resource would do, without doing it
+	kustomize edit add resource deployment.yaml --dry-run -o json
 `,
 		Args: cobra.MinimumNArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Cobra runs only this, not that of the root.
+			if r := cmd.Root(); r != c && r.PersistentPreRunE != nil {
+				if err := r.PersistentPreRunE(cmd, args); err != nil {
+					return err
+				}
+			}
+			return kusterr.WithClass(
+				kusterr.ClassUsage, dryRun.Validate(out))
+		},
 	}
+	dryRun.AddFlags(c.PersistentFlags())
 
 	c.AddCommand(
-		add.NewCmdAdd(fSys, loader.NewFileLoaderAtCwd(v, fSys), kf),
-		set.NewCmdSet(fSys, v),
-		fix.NewCmdFix(fSys),
-		remove.NewCmdRemove(fSys, loader.NewFileLoaderAtCwd(v, fSys)),
+		add.NewCmdAdd(fSys, &dryRun, loader.NewFileLoaderAtCwd(v, fSys), kf),
+		set.NewCmdSet(fSys, &dryRun, v),
+		fix.NewCmdFix(fSys, &dryRun),
+		remove.NewCmdRemove(fSys, &dryRun, loader.NewFileLoaderAtCwd(v, fSys)),
 	)
 	return c
 }
@@ -23,7 +23,7 @@ import (
 )
 
 // NewCmdFix returns an instance of 'fix' subcommand.
-func NewCmdFix(fSys fs.FileSystem) *cobra.Command {
+func NewCmdFix(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "fix",
 		Short: "Fix the missing fields in kustomization file",
@@ -34,15 +34,15 @@ func NewCmdFix(fSys fs.FileSystem) *cobra.Command {
 
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return RunFix(fSys)
+			return RunFix(fSys, dryRun)
 		},
 	}
 	return cmd
 }
 
 // RunFix runs `fix` command
-func RunFix(fSys fs.FileSystem) error {
-	mf, err := kustfile.NewKustomizationFile(fSys)
+func RunFix(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions) error {
+	mf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 	if err != nil {
 		return err
 	}
@@ -27,7 +27,7 @@ func TestFix(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomizationWith([]byte(`nameprefix: some-prefix-`))
 
-	cmd := NewCmdFix(fakeFS)
+	cmd := NewCmdFix(fakeFS, nil)
 	err := cmd.RunE(cmd, nil)
 	if err != nil {
 		t.Errorf("unexpected cmd error: %v", err)
@@ -18,6 +18,7 @@ package remove
 
 import (
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 )
@@ -25,6 +26,7 @@ import (
 // NewCmdRemove returns an instance of 'remove' subcommand.
 func NewCmdRemove(
 	fsys fs.FileSystem,
+	dryRun *kustfile.DryRunOptions,
 	ldr ifc.Loader) *cobra.Command {
 	c := &cobra.Command{
 		Use:   "remove",
@@ -47,10 +49,10 @@ func NewCmdRemove(
 		Args: cobra.MinimumNArgs(1),
 	}
 	c.AddCommand(
-		newCmdRemoveResource(fsys),
-		newCmdRemoveLabel(fsys, ldr.Validator().MakeLabelNameValidator()),
-		newCmdRemoveAnnotation(fsys, ldr.Validator().MakeAnnotationNameValidator()),
-		newCmdRemovePatch(fsys),
+		newCmdRemoveResource(fsys, dryRun),
+		newCmdRemoveLabel(fsys, dryRun, ldr.Validator().MakeLabelNameValidator()),
+		newCmdRemoveAnnotation(fsys, dryRun, ldr.Validator().MakeAnnotationNameValidator()),
+		newCmdRemovePatch(fsys, dryRun),
 	)
 	return c
 }
@@ -53,7 +53,7 @@ type removeMetadataOptions struct {
 }
 
 // newCmdRemoveLabel removes one or more commonAnnotations from the kustomization file.
-func newCmdRemoveAnnotation(fSys fs.FileSystem, v func([]string) error) *cobra.Command {
+func newCmdRemoveAnnotation(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions, v func([]string) error) *cobra.Command {
 	var o removeMetadataOptions
 	o.kind = label
 	o.arrayValidator = v
@@ -63,7 +63,7 @@ func newCmdRemoveAnnotation(fSys fs.FileSystem, v func([]string) error) *cobra.C
 		Example: `
 		remove annotation {annotationKey1},{annotationKey2}`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return o.runE(args, fSys, o.removeAnnotations)
+			return o.runE(args, fSys, dryRun, o.removeAnnotations)
 		},
 	}
 	cmd.Flags().BoolVarP(&o.ignore, "ignore-non-existence", "i", false,
@@ -73,7 +73,7 @@ func newCmdRemoveAnnotation(fSys fs.FileSystem, v func([]string) error) *cobra.C
 }
 
 // newCmdRemoveLabel removes one or more commonLabels from the kustomization file.
-func newCmdRemoveLabel(fSys fs.FileSystem, v func([]string) error) *cobra.Command {
+func newCmdRemoveLabel(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions, v func([]string) error) *cobra.Command {
 	var o removeMetadataOptions
 	o.kind = label
 	o.arrayValidator = v
@@ -83,7 +83,7 @@ func newCmdRemoveLabel(fSys fs.FileSystem, v func([]string) error) *cobra.Comman
 		Example: `
 		remove label {labelKey1},{labelKey2}`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return o.runE(args, fSys, o.removeLabels)
+			return o.runE(args, fSys, dryRun, o.removeLabels)
 		},
 	}
 	cmd.Flags().BoolVarP(&o.ignore, "ignore-non-existence", "i", false,
@@ -93,12 +93,12 @@ func newCmdRemoveLabel(fSys fs.FileSystem, v func([]string) error) *cobra.Comman
 }
 
 func (o *removeMetadataOptions) runE(
-	args []string, fSys fs.FileSystem, remover func(*types.Kustomization) error) error {
+	args []string, fSys fs.FileSystem, dryRun *kustfile.DryRunOptions, remover func(*types.Kustomization) error) error {
 	err := o.validateAndParse(args)
 	if err != nil {
 		return err
 	}
-	kf, err := kustfile.NewKustomizationFile(fSys)
+	kf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 	if err != nil {
 		return err
 	}
@@ -85,7 +85,7 @@ func TestRemoveAnnotationIgnore(t *testing.T) {
 	fakeFS := makeKustomizationFS()
 
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdRemoveAnnotation(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveAnnotation(fakeFS, nil, v.ValidatorArray)
 	cmd.Flag("ignore-non-existence").Value.Set("true")
 	args := []string{"annotation3"}
 	err := cmd.RunE(cmd, args)
@@ -101,7 +101,7 @@ func TestRemoveAnnotationNoDefinition(t *testing.T) {
 	fakeFS.WriteTestKustomizationWith([]byte(""))
 
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdRemoveAnnotation(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveAnnotation(fakeFS, nil, v.ValidatorArray)
 	args := []string{"annotation1,annotation2"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -119,7 +119,7 @@ func TestRemoveAnnotationNoDefinitionIgnore(t *testing.T) {
 	fakeFS.WriteTestKustomizationWith([]byte(""))
 
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdRemoveLabel(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveLabel(fakeFS, nil, v.ValidatorArray)
 	cmd.Flag("ignore-non-existence").Value.Set("true")
 	args := []string{"annotation1,annotation2"}
 	err := cmd.RunE(cmd, args)
@@ -134,7 +134,7 @@ func TestRemoveAnnotationNoArgs(t *testing.T) {
 	fakeFS := makeKustomizationFS()
 
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdRemoveAnnotation(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveAnnotation(fakeFS, nil, v.ValidatorArray)
 	err := cmd.Execute()
 	v.VerifyNoCall()
 
@@ -150,7 +150,7 @@ func TestRemoveAnnotationInvalidFormat(t *testing.T) {
 	fakeFS := makeKustomizationFS()
 
 	v := validators.MakeSadMapValidator(t)
-	cmd := newCmdRemoveAnnotation(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveAnnotation(fakeFS, nil, v.ValidatorArray)
 	args := []string{"nospecialchars%^=@"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -167,7 +167,7 @@ func TestRemoveAnnotationMultipleArgs(t *testing.T) {
 	fakeFS := makeKustomizationFS()
 
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdRemoveAnnotation(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveAnnotation(fakeFS, nil, v.ValidatorArray)
 	args := []string{"annotation1,annotation2"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -189,7 +189,7 @@ func TestRemoveAnnotationMultipleArgsInvalidFormat(t *testing.T) {
 	fakeFS := makeKustomizationFS()
 
 	v := validators.MakeSadMapValidator(t)
-	cmd := newCmdRemoveAnnotation(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveAnnotation(fakeFS, nil, v.ValidatorArray)
 	args := []string{"annotation1", "annotation2"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyNoCall()
@@ -233,7 +233,7 @@ func TestRemoveLabelIgnore(t *testing.T) {
 	fakeFS := makeKustomizationFS()
 
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdRemoveLabel(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveLabel(fakeFS, nil, v.ValidatorArray)
 	cmd.Flag("ignore-non-existence").Value.Set("true")
 	args := []string{"label3"}
 	err := cmd.RunE(cmd, args)
@@ -249,7 +249,7 @@ func TestRemoveLabelNoDefinition(t *testing.T) {
 	fakeFS.WriteTestKustomizationWith([]byte(""))
 
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdRemoveLabel(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveLabel(fakeFS, nil, v.ValidatorArray)
 	args := []string{"label1,label2"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -267,7 +267,7 @@ func TestRemoveLabelNoDefinitionIgnore(t *testing.T) {
 	fakeFS.WriteTestKustomizationWith([]byte(""))
 
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdRemoveLabel(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveLabel(fakeFS, nil, v.ValidatorArray)
 	cmd.Flag("ignore-non-existence").Value.Set("true")
 	args := []string{"label1,label2"}
 	err := cmd.RunE(cmd, args)
@@ -282,7 +282,7 @@ func TestRemoveLabelNoArgs(t *testing.T) {
 	fakeFS := makeKustomizationFS()
 
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdRemoveLabel(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveLabel(fakeFS, nil, v.ValidatorArray)
 	err := cmd.Execute()
 	v.VerifyNoCall()
 
@@ -298,7 +298,7 @@ func TestRemoveLabelInvalidFormat(t *testing.T) {
 	fakeFS := makeKustomizationFS()
 
 	v := validators.MakeSadMapValidator(t)
-	cmd := newCmdRemoveLabel(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveLabel(fakeFS, nil, v.ValidatorArray)
 	args := []string{"exclamation!"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -315,7 +315,7 @@ func TestRemoveLabelMultipleArgs(t *testing.T) {
 	fakeFS := makeKustomizationFS()
 
 	v := validators.MakeHappyMapValidator(t)
-	cmd := newCmdRemoveLabel(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveLabel(fakeFS, nil, v.ValidatorArray)
 	args := []string{"label1,label2"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyCall()
@@ -337,7 +337,7 @@ func TestRemoveLabelMultipleArgsInvalidFormat(t *testing.T) {
 	fakeFS := makeKustomizationFS()
 
 	v := validators.MakeSadMapValidator(t)
-	cmd := newCmdRemoveLabel(fakeFS, v.ValidatorArray)
+	cmd := newCmdRemoveLabel(fakeFS, nil, v.ValidatorArray)
 	args := []string{"label1", "label2"}
 	err := cmd.RunE(cmd, args)
 	v.VerifyNoCall()
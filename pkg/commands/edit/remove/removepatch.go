@@ -8,6 +8,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/edit/util"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
@@ -20,7 +21,7 @@ type removePatchOptions struct {
 }
 
 // newCmdRemovePatch removes the name of a file containing a patch from the kustomization file.
-func newCmdRemovePatch(fsys fs.FileSystem) *cobra.Command {
+func newCmdRemovePatch(fsys fs.FileSystem, dryRun *kustfile.DryRunOptions) *cobra.Command {
 	var o removePatchOptions
 
 	cmd := &cobra.Command{
@@ -31,13 +32,13 @@ func newCmdRemovePatch(fsys fs.FileSystem) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
 			if err != nil {
-				return err
+				return kusterr.WithClass(kusterr.ClassUsage, err)
 			}
 			err = o.Complete(cmd, args)
 			if err != nil {
 				return err
 			}
-			return o.RunRemovePatch(fsys)
+			return o.RunRemovePatch(fsys, dryRun)
 		},
 	}
 	return cmd
@@ -58,7 +59,7 @@ func (o *removePatchOptions) Complete(cmd *cobra.Command, args []string) error {
 }
 
 // RunRemovePatch runs removePatch command (do real work).
-func (o *removePatchOptions) RunRemovePatch(fSys fs.FileSystem) error {
+func (o *removePatchOptions) RunRemovePatch(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions) error {
 	patches, err := util.GlobPatterns(fSys, o.patchFilePaths)
 	if err != nil {
 		return err
@@ -67,7 +68,7 @@ func (o *removePatchOptions) RunRemovePatch(fSys fs.FileSystem) error {
 		return nil
 	}
 
-	mf, err := kustfile.NewKustomizationFile(fSys)
+	mf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 	if err != nil {
 		return err
 	}
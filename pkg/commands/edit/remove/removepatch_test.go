@@ -36,7 +36,7 @@ func makeKustomizationPatchFS() fs.FileSystem {
 
 func TestRemovePatch(t *testing.T) {
 	fakeFS := makeKustomizationPatchFS()
-	cmd := newCmdRemovePatch(fakeFS)
+	cmd := newCmdRemovePatch(fakeFS, nil)
 	args := []string{"patch1.yaml"}
 	err := cmd.RunE(cmd, args)
 
@@ -54,7 +54,7 @@ func TestRemovePatch(t *testing.T) {
 
 func TestRemovePatchMultipleArgs(t *testing.T) {
 	fakeFS := makeKustomizationPatchFS()
-	cmd := newCmdRemovePatch(fakeFS)
+	cmd := newCmdRemovePatch(fakeFS, nil)
 	args := []string{"patch1.yaml", "patch2.yaml"}
 	err := cmd.RunE(cmd, args)
 
@@ -72,7 +72,7 @@ func TestRemovePatchMultipleArgs(t *testing.T) {
 
 func TestRemovePatchGlob(t *testing.T) {
 	fakeFS := makeKustomizationPatchFS()
-	cmd := newCmdRemovePatch(fakeFS)
+	cmd := newCmdRemovePatch(fakeFS, nil)
 	args := []string{"patch*.yaml"}
 	err := cmd.RunE(cmd, args)
 
@@ -88,7 +88,7 @@ func TestRemovePatchGlob(t *testing.T) {
 
 func TestRemovePatchNotDefinedInKustomization(t *testing.T) {
 	fakeFS := makeKustomizationPatchFS()
-	cmd := newCmdRemovePatch(fakeFS)
+	cmd := newCmdRemovePatch(fakeFS, nil)
 	args := []string{"patch3.yaml"}
 	err := cmd.RunE(cmd, args)
 
@@ -106,7 +106,7 @@ func TestRemovePatchNotDefinedInKustomization(t *testing.T) {
 
 func TestRemovePatchNotExist(t *testing.T) {
 	fakeFS := makeKustomizationPatchFS()
-	cmd := newCmdRemovePatch(fakeFS)
+	cmd := newCmdRemovePatch(fakeFS, nil)
 	args := []string{"patch4.yaml"}
 	err := cmd.RunE(cmd, args)
 
@@ -124,7 +124,7 @@ func TestRemovePatchNotExist(t *testing.T) {
 
 func TestRemovePatchNoArgs(t *testing.T) {
 	fakeFS := makeKustomizationPatchFS()
-	cmd := newCmdRemovePatch(fakeFS)
+	cmd := newCmdRemovePatch(fakeFS, nil)
 	err := cmd.RunE(cmd, nil)
 
 	if err == nil {
@@ -19,6 +19,7 @@ package remove
 import (
 	"errors"
 	"path/filepath"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
 
 	"github.com/spf13/cobra"
@@ -31,7 +32,7 @@ type removeResourceOptions struct {
 }
 
 // newCmdRemoveResource remove the name of a file containing a resource to the kustomization file.
-func newCmdRemoveResource(fsys fs.FileSystem) *cobra.Command {
+func newCmdRemoveResource(fsys fs.FileSystem, dryRun *kustfile.DryRunOptions) *cobra.Command {
 	var o removeResourceOptions
 
 	cmd := &cobra.Command{
@@ -45,13 +46,13 @@ func newCmdRemoveResource(fsys fs.FileSystem) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
 			if err != nil {
-				return err
+				return kusterr.WithClass(kusterr.ClassUsage, err)
 			}
 			err = o.Complete(cmd, args)
 			if err != nil {
 				return err
 			}
-			return o.RunRemoveResource(fsys)
+			return o.RunRemoveResource(fsys, dryRun)
 		},
 	}
 	return cmd
@@ -72,9 +73,9 @@ func (o *removeResourceOptions) Complete(cmd *cobra.Command, args []string) erro
 }
 
 // RunRemoveResource runs Resource command (do real work).
-func (o *removeResourceOptions) RunRemoveResource(fSys fs.FileSystem) error {
+func (o *removeResourceOptions) RunRemoveResource(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions) error {
 
-	mf, err := kustfile.NewKustomizationFile(fSys)
+	mf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 	if err != nil {
 		return err
 	}
@@ -139,7 +139,7 @@ func TestRemoveResources(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			fakeFS := fs.MakeFakeFS()
 			fakeFS.WriteTestKustomizationWith([]byte(fmt.Sprintf("resources:\n  - %s", strings.Join(tc.given.resources, "\n  - "))))
-			cmd := newCmdRemoveResource(fakeFS)
+			cmd := newCmdRemoveResource(fakeFS, nil)
 			err := cmd.RunE(cmd, tc.given.removeArgs)
 			if err != nil && tc.expected.err == nil {
 
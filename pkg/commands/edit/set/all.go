@@ -18,12 +18,13 @@ package set
 
 import (
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 )
 
 // NewCmdSet returns an instance of 'set' subcommand.
-func NewCmdSet(fsys fs.FileSystem, v ifc.Validator) *cobra.Command {
+func NewCmdSet(fsys fs.FileSystem, dryRun *kustfile.DryRunOptions, v ifc.Validator) *cobra.Command {
 	c := &cobra.Command{
 		Use:   "set",
 		Short: "Sets the value of different fields in kustomization file.",
@@ -39,10 +40,10 @@ func NewCmdSet(fsys fs.FileSystem, v ifc.Validator) *cobra.Command {
 	}
 
 	c.AddCommand(
-		newCmdSetNamePrefix(fsys),
-		newCmdSetNameSuffix(fsys),
-		newCmdSetNamespace(fsys, v),
-		newCmdSetImage(fsys),
+		newCmdSetNamePrefix(fsys, dryRun),
+		newCmdSetNameSuffix(fsys, dryRun),
+		newCmdSetNamespace(fsys, dryRun, v),
+		newCmdSetImage(fsys, dryRun),
 	)
 	return c
 }
@@ -20,6 +20,7 @@ import (
 	"errors"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 )
@@ -29,7 +30,7 @@ type setNamePrefixOptions struct {
 }
 
 // newCmdSetNamePrefix sets the value of the namePrefix field in the kustomization.
-func newCmdSetNamePrefix(fsys fs.FileSystem) *cobra.Command {
+func newCmdSetNamePrefix(fsys fs.FileSystem, dryRun *kustfile.DryRunOptions) *cobra.Command {
 	var o setNamePrefixOptions
 
 	cmd := &cobra.Command{
@@ -44,13 +45,13 @@ and overwrite the value with "acme-" if the field does exist.
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
 			if err != nil {
-				return err
+				return kusterr.WithClass(kusterr.ClassUsage, err)
 			}
 			err = o.Complete(cmd, args)
 			if err != nil {
 				return err
 			}
-			return o.RunSetNamePrefix(fsys)
+			return o.RunSetNamePrefix(fsys, dryRun)
 		},
 	}
 	return cmd
@@ -72,8 +73,8 @@ func (o *setNamePrefixOptions) Complete(cmd *cobra.Command, args []string) error
 }
 
 // RunSetNamePrefix runs setNamePrefix command (does real work).
-func (o *setNamePrefixOptions) RunSetNamePrefix(fSys fs.FileSystem) error {
-	mf, err := kustfile.NewKustomizationFile(fSys)
+func (o *setNamePrefixOptions) RunSetNamePrefix(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions) error {
+	mf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 	if err != nil {
 		return err
 	}
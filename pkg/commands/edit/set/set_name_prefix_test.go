@@ -31,7 +31,7 @@ func TestSetNamePrefixHappyPath(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 
-	cmd := newCmdSetNamePrefix(fakeFS)
+	cmd := newCmdSetNamePrefix(fakeFS, nil)
 	args := []string{goodPrefixValue}
 	err := cmd.RunE(cmd, args)
 	if err != nil {
@@ -49,7 +49,7 @@ func TestSetNamePrefixHappyPath(t *testing.T) {
 func TestSetNamePrefixNoArgs(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 
-	cmd := newCmdSetNamePrefix(fakeFS)
+	cmd := newCmdSetNamePrefix(fakeFS, nil)
 	err := cmd.Execute()
 	if err == nil {
 		t.Errorf("expected error: %v", err)
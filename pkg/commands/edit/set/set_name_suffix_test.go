@@ -31,7 +31,7 @@ func TestSetNameSuffixHappyPath(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 
-	cmd := newCmdSetNameSuffix(fakeFS)
+	cmd := newCmdSetNameSuffix(fakeFS, nil)
 	args := []string{goodSuffixValue}
 	err := cmd.RunE(cmd, args)
 	if err != nil {
@@ -49,7 +49,7 @@ func TestSetNameSuffixHappyPath(t *testing.T) {
 func TestSetNameSuffixNoArgs(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 
-	cmd := newCmdSetNameSuffix(fakeFS)
+	cmd := newCmdSetNameSuffix(fakeFS, nil)
 	err := cmd.Execute()
 	if err == nil {
 		t.Errorf("expected error: %v", err)
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/image"
@@ -49,7 +50,7 @@ var (
 const separator = "="
 
 // newCmdSetImage sets the new names, tags or digests for images in the kustomization.
-func newCmdSetImage(fsys fs.FileSystem) *cobra.Command {
+func newCmdSetImage(fsys fs.FileSystem, dryRun *kustfile.DryRunOptions) *cobra.Command {
 	var o setImageOptions
 
 	cmd := &cobra.Command{
@@ -89,9 +90,9 @@ and overwrite the previous ones if the image name exists.
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
 			if err != nil {
-				return err
+				return kusterr.WithClass(kusterr.ClassUsage, err)
 			}
-			return o.RunSetImage(fsys)
+			return o.RunSetImage(fsys, dryRun)
 		},
 	}
 	return cmd
@@ -123,8 +124,8 @@ func (o *setImageOptions) Validate(args []string) error {
 }
 
 // RunSetImage runs setImage command.
-func (o *setImageOptions) RunSetImage(fSys fs.FileSystem) error {
-	mf, err := kustfile.NewKustomizationFile(fSys)
+func (o *setImageOptions) RunSetImage(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions) error {
+	mf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 	if err != nil {
 		return err
 	}
@@ -21,6 +21,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 )
 
@@ -204,7 +205,7 @@ func TestSetImage(t *testing.T) {
 		t.Run(fmt.Sprintf("%s%v", tc.description, tc.given.args), func(t *testing.T) {
 			// arrange
 			fakeFS := fs.MakeFakeFS()
-			cmd := newCmdSetImage(fakeFS)
+			cmd := newCmdSetImage(fakeFS, nil)
 
 			if len(tc.given.infileImages) > 0 {
 				// write file with infileImages
@@ -218,7 +219,7 @@ func TestSetImage(t *testing.T) {
 			err := cmd.RunE(cmd, tc.given.args)
 
 			// assert
-			if err != tc.expected.err {
+			if errors.Cause(err) != tc.expected.err {
 				t.Errorf("Unexpedted error from set image command. Actual: %v\nExpected: %v", err, tc.expected.err)
 				t.FailNow()
 			}
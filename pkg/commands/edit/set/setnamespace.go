@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
@@ -33,7 +34,7 @@ type setNamespaceOptions struct {
 }
 
 // newCmdSetNamespace sets the value of the namespace field in the kustomization.
-func newCmdSetNamespace(fsys fs.FileSystem, v ifc.Validator) *cobra.Command {
+func newCmdSetNamespace(fsys fs.FileSystem, dryRun *kustfile.DryRunOptions, v ifc.Validator) *cobra.Command {
 	var o setNamespaceOptions
 
 	cmd := &cobra.Command{
@@ -49,9 +50,9 @@ and overwrite the value with "staging" if the field does exist.
 			o.validator = v
 			err := o.Validate(args)
 			if err != nil {
-				return err
+				return kusterr.WithClass(kusterr.ClassUsage, err)
 			}
-			return o.RunSetNamespace(fsys)
+			return o.RunSetNamespace(fsys, dryRun)
 		},
 	}
 	return cmd
@@ -71,8 +72,8 @@ func (o *setNamespaceOptions) Validate(args []string) error {
 }
 
 // RunSetNamespace runs setNamespace command (does real work).
-func (o *setNamespaceOptions) RunSetNamespace(fSys fs.FileSystem) error {
-	mf, err := kustfile.NewKustomizationFile(fSys)
+func (o *setNamespaceOptions) RunSetNamespace(fSys fs.FileSystem, dryRun *kustfile.DryRunOptions) error {
+	mf, err := kustfile.NewKustomizationFileDryRun(fSys, dryRun)
 	if err != nil {
 		return err
 	}
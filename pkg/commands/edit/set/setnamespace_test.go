@@ -33,7 +33,7 @@ func TestSetNamespaceHappyPath(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 
-	cmd := newCmdSetNamespace(fakeFS, validators.MakeFakeValidator())
+	cmd := newCmdSetNamespace(fakeFS, nil, validators.MakeFakeValidator())
 	args := []string{goodNamespaceValue}
 	err := cmd.RunE(cmd, args)
 	if err != nil {
@@ -53,7 +53,7 @@ func TestSetNamespaceOverride(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 	fakeFS.WriteTestKustomization()
 
-	cmd := newCmdSetNamespace(fakeFS, validators.MakeFakeValidator())
+	cmd := newCmdSetNamespace(fakeFS, nil, validators.MakeFakeValidator())
 	args := []string{goodNamespaceValue}
 	err := cmd.RunE(cmd, args)
 	if err != nil {
@@ -77,7 +77,7 @@ func TestSetNamespaceOverride(t *testing.T) {
 func TestSetNamespaceNoArgs(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 
-	cmd := newCmdSetNamespace(fakeFS, validators.MakeFakeValidator())
+	cmd := newCmdSetNamespace(fakeFS, nil, validators.MakeFakeValidator())
 	err := cmd.Execute()
 	if err == nil {
 		t.Errorf("expected error: %v", err)
@@ -90,7 +90,7 @@ func TestSetNamespaceNoArgs(t *testing.T) {
 func TestSetNamespaceInvalid(t *testing.T) {
 	fakeFS := fs.MakeFakeFS()
 
-	cmd := newCmdSetNamespace(fakeFS, validators.MakeFakeValidator())
+	cmd := newCmdSetNamespace(fakeFS, nil, validators.MakeFakeValidator())
 	args := []string{"/badnamespace/"}
 	err := cmd.RunE(cmd, args)
 	if err == nil {
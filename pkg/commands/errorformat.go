@@ -0,0 +1,72 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+)
+
+const (
+	flagErrorFormatName = "error-format"
+	errorFormatText     = "text"
+	errorFormatJSON     = "json"
+)
+
+var (
+	flagErrorFormatValue = errorFormatText
+	flagErrorFormatHelp  = "How to print the error a command fails with: '" +
+		errorFormatText + "', or '" + errorFormatJSON + "', a line of " +
+		"JSON with the message ID, class, exit code and message, " +
+		"for scripts to key off the ID rather than the message."
+)
+
+func addFlagErrorFormat(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagErrorFormatValue, flagErrorFormatName,
+		errorFormatText, flagErrorFormatHelp)
+}
+
+func validateFlagErrorFormat() error {
+	switch flagErrorFormatValue {
+	case errorFormatText, errorFormatJSON:
+		return nil
+	default:
+		return kusterr.WithClass(kusterr.ClassUsage, messages.Errorf(
+			messages.FlagIllegalValue,
+			flagErrorFormatName, flagErrorFormatValue,
+			[]string{errorFormatText, errorFormatJSON}))
+	}
+}
+
+// jsonError is an error as --error-format=json prints it.
+type jsonError struct {
+	ID       messages.ID `json:"id"`
+	Class    string      `json:"class"`
+	ExitCode int         `json:"exitCode"`
+	Message  string      `json:"message"`
+}
+
+// PrintError writes the error a command failed with
+// to w, as --error-format says.
+func PrintError(w io.Writer, err error) {
+	if flagErrorFormatValue == errorFormatJSON {
+		b, jerr := json.Marshal(jsonError{
+			ID:       messages.IDOf(err),
+			Class:    kusterr.ClassOf(err).String(),
+			ExitCode: kusterr.ExitCode(err),
+			Message:  err.Error(),
+		})
+		if jerr == nil {
+			fmt.Fprintln(w, string(b))
+			return
+		}
+	}
+	fmt.Fprintln(w, messages.Sprintf(messages.Error, err.Error()))
+}
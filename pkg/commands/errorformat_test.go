@@ -0,0 +1,50 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+)
+
+func TestPrintError(t *testing.T) {
+	defer func() { flagErrorFormatValue = errorFormatText }()
+	err := kusterr.WithClass(kusterr.ClassUsage, errors.Wrap(
+		messages.Errorf(messages.FlagNegative, "max-procs"), "build"))
+	testCases := map[string]struct {
+		format   string
+		err      error
+		expected string
+	}{
+		"text": {
+			format:   errorFormatText,
+			err:      err,
+			expected: "Error: build: --max-procs can't be negative\n",
+		},
+		"json": {
+			format: errorFormatJSON,
+			err:    err,
+			expected: `{"id":"flag-negative","class":"usage","exitCode":2,` +
+				`"message":"build: --max-procs can't be negative"}` + "\n",
+		},
+		"jsonUnknown": {
+			format: errorFormatJSON,
+			err:    errors.New("boom"),
+			expected: `{"id":"unknown","class":"unknown","exitCode":1,` +
+				`"message":"boom"}` + "\n",
+		},
+	}
+	for n, tc := range testCases {
+		flagErrorFormatValue = tc.format
+		var out bytes.Buffer
+		PrintError(&out, tc.err)
+		if actual := out.String(); actual != tc.expected {
+			t.Errorf("%s: expected %q, got %q", n, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+)
+
+// TestBuildExitCodes checks the exit code of each kind
+// of failure, and that remote, validation and plugin
+// failures have a message ID, for --error-format=json.
+func TestBuildExitCodes(t *testing.T) {
+	const configMap = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+`
+	testCases := map[string]struct {
+		files    map[string]string
+		args     []string
+		expected int
+		id       messages.ID
+	}{
+		"badFlag": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n- cm.yaml\n",
+				"cm.yaml":            configMap,
+			},
+			args:     []string{"--duplicate-keys", "nope"},
+			expected: kusterr.ExitUsage,
+			id:       messages.FlagIllegalValue,
+		},
+		"missingKustomization": {
+			files:    map[string]string{"cm.yaml": configMap},
+			expected: kusterr.ExitLoad,
+			id:       messages.NotKustomization,
+		},
+		"yamlFormatError": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n- cm.yaml\n",
+				"cm.yaml":            configMap + "data:\n  x: [\n",
+			},
+			expected: kusterr.ExitLoad,
+			id:       messages.Unknown,
+		},
+		"patchWithNoTarget": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n- cm.yaml\n" +
+					"patchesStrategicMerge:\n- patch.yaml\n",
+				"cm.yaml": configMap,
+				"patch.yaml": "apiVersion: v1\nkind: ConfigMap\n" +
+					"metadata:\n  name: nope\ndata:\n  a: b\n",
+			},
+			expected: kusterr.ExitValidation,
+			id:       messages.PatchTargetNotFound,
+		},
+		"duplicateKeys": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n- cm.yaml\n",
+				"cm.yaml":            configMap + "data:\n  a: b\n  a: c\n",
+			},
+			args:     []string{"--duplicate-keys", "error"},
+			expected: kusterr.ExitValidation,
+			id:       messages.DuplicateKeys,
+		},
+		"badField": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n- cm.yaml\nnope: x\n",
+				"cm.yaml":            configMap,
+			},
+			expected: kusterr.ExitValidation,
+			id:       messages.KustomizationInvalid,
+		},
+		"offline": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n" +
+					"- https://example.com/cm.yaml\n",
+			},
+			args:     []string{"--network=false"},
+			expected: kusterr.ExitRemote,
+			id:       messages.NetworkDisabled,
+		},
+		"postRendererFails": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n- cm.yaml\n" +
+					"postRenderers:\n- command: \"false\"\n",
+				"cm.yaml": configMap,
+			},
+			args:     []string{"--enable-post-renderers"},
+			expected: kusterr.ExitPlugin,
+			id:       messages.PostRendererFailed,
+		},
+	}
+	for n, tc := range testCases {
+		dir, err := ioutil.TempDir("", "kustomize-exit-codes-")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		for name, content := range tc.files {
+			err := ioutil.WriteFile(
+				filepath.Join(dir, name), []byte(content), 0644)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		c := NewDefaultCommand()
+		c.SetArgs(append([]string{"build", dir}, tc.args...))
+		c.SetOutput(ioutil.Discard)
+		err = c.Execute()
+		if actual := kusterr.ExitCode(err); actual != tc.expected {
+			t.Errorf("%s: expected exit code %d, got %d: %v",
+				n, tc.expected, actual, err)
+		}
+		var printed jsonError
+		flagErrorFormatValue = errorFormatJSON
+		var out bytes.Buffer
+		PrintError(&out, err)
+		flagErrorFormatValue = errorFormatText
+		if err := json.Unmarshal(out.Bytes(), &printed); err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		switch kusterr.ClassOf(err) {
+		case kusterr.ClassRemote, kusterr.ClassValidation, kusterr.ClassPlugin:
+			if printed.ID == messages.Unknown {
+				t.Errorf("%s: %s error has no message ID: %v",
+					n, printed.Class, err)
+			}
+		}
+		if printed.ID != tc.id {
+			t.Errorf("%s: expected message ID %s, got %s",
+				n, tc.id, printed.ID)
+		}
+	}
+}
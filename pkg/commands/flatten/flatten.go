@@ -0,0 +1,214 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package flatten writes the output of a build as a
+// directory of plain resources, with a kustomization
+// listing them.
+package flatten
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/render"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/plugin/builtin"
+	"sigs.k8s.io/yaml"
+)
+
+// Options contain the options for running flatten.
+type Options struct {
+	kustomizationPath string
+	outputDir         string
+	loadRestrictor    loader.LoadRestrictorFunc
+	loader            loader.Options
+	resource          resource.Options
+}
+
+var examples = `
+To snapshot the build of an overlay, e.g. of a release,
+as a base of plain resources, with no bases, patches or
+generators of its own, run
+
+  kustomize flatten overlays/prod -o releases/v1.2.0
+
+The directory must not exist, or be empty.  It gets a
+file per resource, e.g. deployment_prod-web.yaml, and a
+` + pgmconfig.KustomizationFileNames[0] + ` listing them, whose build is that of
+the overlay.
+`
+
+// NewCmdFlatten creates a new flatten command.
+func NewCmdFlatten(
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, rf *resmap.Factory,
+	ptf resmap.PatchFactory) *cobra.Command {
+	var o Options
+
+	pluginConfig := plugins.DefaultPluginConfig()
+	pl := plugins.NewLoader(pluginConfig, rf)
+
+	cmd := &cobra.Command{
+		Use:          "flatten {path} -o {dir}",
+		Short:        "Write the build of a kustomization as a base of plain resources",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			err = o.RunFlatten(v, fSys, rf, ptf, pl)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(out, "wrote %s\n", o.outputDir)
+			return err
+		},
+	}
+	cmd.Flags().StringVarP(
+		&o.outputDir,
+		"output", "o", "",
+		"Directory to write the resources and kustomization to.")
+	loader.AddFlagLoadRestrictor(cmd.Flags())
+	o.loader.AddFlags(cmd.Flags())
+	o.resource.AddFlagPreserveUntouched(cmd.Flags())
+	plugins.AddFlagEnablePlugins(
+		cmd.Flags(), &pluginConfig.Enabled)
+	return cmd
+}
+
+// Validate validates flatten command.
+func (o *Options) Validate(args []string) (err error) {
+	if len(args) > 1 {
+		return errors.New(
+			"specify one path to " + pgmconfig.KustomizationFileNames[0])
+	}
+	if len(args) == 0 {
+		o.kustomizationPath = loader.CWD
+	} else {
+		o.kustomizationPath = args[0]
+	}
+	if o.outputDir == "" {
+		return errors.New("specify the directory to write with --output")
+	}
+	o.loadRestrictor, err = loader.ValidateFlagLoadRestrictor()
+	return err
+}
+
+// RunFlatten builds the kustomization, and writes its
+// resources, and a kustomization listing them, to the
+// output directory.
+func (o *Options) RunFlatten(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader) error {
+	if err := checkOutputDir(fSys, o.outputDir); err != nil {
+		return kusterr.WithClass(kusterr.ClassUsage, err)
+	}
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
+		return err
+	}
+	m, err := render.NewRendererWith(fSys, v, rf, ptf, pl).
+		WithLoadRestrictor(o.loadRestrictor).
+		WithLoaderOptions(o.loader).
+		WithResourceOptions(o.resource).
+		Render(o.kustomizationPath, nil)
+	if err != nil {
+		return err
+	}
+	// Keep the order a build outputs.
+	builtin.NewLegacyOrderTransformerPlugin().Transform(m)
+	return write(fSys, o.outputDir, m)
+}
+
+// checkOutputDir fails if dir has files, which
+// flatten might overwrite, or mix with its own.
+func checkOutputDir(fSys fs.FileSystem, dir string) error {
+	if !fSys.IsDir(dir) {
+		if fSys.Exists(dir) {
+			return fmt.Errorf("%s isn't a directory", dir)
+		}
+		return nil
+	}
+	files, err := fSys.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		return fmt.Errorf("%s isn't empty", dir)
+	}
+	return nil
+}
+
+// write writes a file per resource to dir, and a
+// kustomization listing them in order.
+func write(fSys fs.FileSystem, dir string, m resmap.ResMap) error {
+	if err := fSys.MkdirAll(dir); err != nil {
+		return err
+	}
+	k := types.Kustomization{
+		TypeMeta: types.TypeMeta{
+			APIVersion: types.KustomizationVersion,
+			Kind:       types.KustomizationKind,
+		},
+	}
+	names := fileNames(m.Resources())
+	for i, r := range m.Resources() {
+		data, err := r.AsYAML()
+		if err != nil {
+			return err
+		}
+		err = fSys.WriteFile(filepath.Join(dir, names[i]), data)
+		if err != nil {
+			return err
+		}
+		k.Resources = append(k.Resources, names[i])
+	}
+	data, err := yaml.Marshal(k)
+	if err != nil {
+		return err
+	}
+	return fSys.WriteFile(
+		filepath.Join(dir, pgmconfig.KustomizationFileNames[0]), data)
+}
+
+// fileNames returns the names of the files of the
+// resources, e.g. deployment_web.yaml, prefixed by
+// their namespaces if they have several, and numbered
+// if they'd otherwise be the same.
+func fileNames(resources []*resource.Resource) []string {
+	namespaces := make(map[string]bool)
+	for _, r := range resources {
+		namespaces[r.GetNamespace()] = true
+	}
+	var result []string
+	seen := make(map[string]bool)
+	for _, r := range resources {
+		name := r.GetKind() + "_" + r.GetName()
+		if len(namespaces) > 1 && r.GetNamespace() != "" {
+			name = r.GetNamespace() + "_" + name
+		}
+		name = strings.ToLower(name)
+		unique := name
+		for i := 2; seen[unique]; i++ {
+			unique = name + "-" + strconv.Itoa(i)
+		}
+		seen[unique] = true
+		result = append(result, unique+".yaml")
+	}
+	return result
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package flatten
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+	"sigs.k8s.io/kustomize/v3/plugin/builtin"
+)
+
+func writeOverlay(fSys fs.FileSystem) {
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(`
+resources:
+- deployment.yaml
+configMapGenerator:
+- name: config
+  literals:
+  - color=blue
+`))
+	fSys.WriteFile("/app/base/deployment.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:v1
+        envFrom:
+        - configMapRef:
+            name: config
+`))
+	fSys.WriteFile("/app/prod/kustomization.yaml", []byte(`
+namePrefix: prod-
+namespace: prod
+resources:
+- ../base
+- service.yaml
+`))
+	fSys.WriteFile("/app/prod/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`))
+}
+
+func build(t *testing.T, fSys fs.FileSystem, path string) string {
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	ldr, err := loader.NewLoader(
+		loader.RestrictionRootOnly, validators.MakeFakeValidator(),
+		path, fSys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kt, err := target.NewKustTarget(
+		ldr, rf, transformer.NewFactoryImpl(),
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// As kustomize build orders them.
+	builtin.NewLegacyOrderTransformerPlugin().Transform(m)
+	out, err := m.AsYaml()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(out)
+}
+
+func flatten(fSys fs.FileSystem, dir string) error {
+	o := Options{
+		kustomizationPath: "/app/prod",
+		outputDir:         dir,
+		loadRestrictor:    loader.RestrictionRootOnly,
+	}
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	return o.RunFlatten(
+		validators.MakeFakeValidator(), fSys, rf,
+		transformer.NewFactoryImpl(),
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+}
+
+func TestRunFlatten(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeOverlay(fSys)
+	if err := flatten(fSys, "/release"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := fSys.ReadFile("/release/kustomization.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- configmap_prod-config-gkk6dmgk42.yaml
+- service_prod-web.yaml
+- deployment_prod-web.yaml
+`
+	if string(data) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+	// The flattened base builds as the overlay does.
+	if actual, expected := build(t, fSys, "/release"),
+		build(t, fSys, "/app/prod"); actual != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestRunFlattenRefusesNonEmptyDir(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeOverlay(fSys)
+	fSys.WriteFile("/release/README.md", []byte("notes"))
+	err := flatten(fSys, "/release")
+	if err == nil || !strings.Contains(err.Error(), "/release isn't empty") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFileNames(t *testing.T) {
+	rf := resource.NewFactory(kunstruct.NewKunstructuredFactoryImpl())
+	var resources []*resource.Resource
+	for _, ns := range []string{"a", "a", "b", ""} {
+		resources = append(resources, rf.FromMap(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name":      "Web",
+				"namespace": ns,
+			},
+		}))
+	}
+	actual := strings.Join(fileNames(resources), " ")
+	expected := "a_service_web.yaml a_service_web-2.yaml " +
+		"b_service_web.yaml service_web.yaml"
+	if actual != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+}
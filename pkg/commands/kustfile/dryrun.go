@@ -0,0 +1,177 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kustfile
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	flagDryRunName = "dry-run"
+	flagDryRunHelp = "if true, print the kustomization file " +
+		"that would be written instead of writing it."
+	flagOutputName = "output"
+	flagOutputHelp = "with --dry-run, one of 'json' or 'yaml' to " +
+		"print the would-be file and a summary of changed fields."
+)
+
+// DryRunOptions say whether an edit command writes the
+// kustomization file or prints what it would write.
+// The edit command holds them, for its subcommands.
+type DryRunOptions struct {
+	enabled bool
+	output  string
+	out     io.Writer
+}
+
+// AddFlags adds --dry-run and --output flags.
+func (o *DryRunOptions) AddFlags(set *pflag.FlagSet) {
+	set.BoolVar(
+		&o.enabled, flagDryRunName, false, flagDryRunHelp)
+	set.StringVarP(
+		&o.output, flagOutputName, "o", "", flagOutputHelp)
+}
+
+// Validate checks the flags added by AddFlags,
+// and directs dry-run output to the given writer.
+func (o *DryRunOptions) Validate(out io.Writer) error {
+	switch o.output {
+	case "", "json", "yaml":
+	default:
+		return messages.Errorf(messages.FlagIllegalValue,
+			flagOutputName, o.output, []string{"json", "yaml"})
+	}
+	if o.output != "" && !o.enabled {
+		return messages.Errorf(
+			messages.FlagRequires, flagOutputName, flagDryRunName)
+	}
+	o.out = out
+	return nil
+}
+
+// FieldChange describes how one top level
+// field of a kustomization file would change.
+type FieldChange struct {
+	// Field is the field name as it appears in the file.
+	Field string `json:"field" yaml:"field"`
+	// Action is one of "added", "removed" or "modified".
+	Action string      `json:"action" yaml:"action"`
+	Before interface{} `json:"before,omitempty" yaml:"before,omitempty"`
+	After  interface{} `json:"after,omitempty" yaml:"after,omitempty"`
+}
+
+// DryRunResult is what an edit command would have done.
+type DryRunResult struct {
+	Path          string        `json:"path" yaml:"path"`
+	Kustomization string        `json:"kustomization" yaml:"kustomization"`
+	Changes       []FieldChange `json:"changes" yaml:"changes"`
+}
+
+func (mf *kustomizationFile) writeDryRun(
+	kustomization *types.Kustomization, data []byte) error {
+	if mf.dryRun.output == "" {
+		_, err := mf.dryRun.out.Write(data)
+		return err
+	}
+	original, err := mf.readUnmodified()
+	if err != nil {
+		return err
+	}
+	changes, err := diffFields(original, kustomization)
+	if err != nil {
+		return err
+	}
+	result := DryRunResult{
+		Path:          mf.path,
+		Kustomization: string(data),
+		Changes:       changes,
+	}
+	var out []byte
+	if mf.dryRun.output == "json" {
+		out, err = json.MarshalIndent(result, "", "  ")
+		out = append(out, '\n')
+	} else {
+		out, err = yaml.Marshal(result)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = mf.dryRun.out.Write(out)
+	return err
+}
+
+// readUnmodified reads the file as it is on disk,
+// ignoring any changes made to values returned by Read.
+func (mf *kustomizationFile) readUnmodified() (*types.Kustomization, error) {
+	data, err := mf.fSys.ReadFile(mf.path)
+	if err != nil {
+		return nil, err
+	}
+	data = types.FixKustomizationPreUnmarshalling(data)
+	var k types.Kustomization
+	err = yaml.Unmarshal(data, &k)
+	if err != nil {
+		return nil, err
+	}
+	k.FixKustomizationPostUnmarshalling()
+	return &k, nil
+}
+
+// diffFields compares two kustomizations field by
+// field, reporting changes in field name order.
+func diffFields(before, after *types.Kustomization) ([]FieldChange, error) {
+	b, err := asMap(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := asMap(after)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for n := range b {
+		names = append(names, n)
+	}
+	for n := range a {
+		if _, ok := b[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	changes := []FieldChange{}
+	for _, n := range names {
+		bv, inBefore := b[n]
+		av, inAfter := a[n]
+		switch {
+		case !inBefore:
+			changes = append(changes, FieldChange{
+				Field: n, Action: "added", After: av})
+		case !inAfter:
+			changes = append(changes, FieldChange{
+				Field: n, Action: "removed", Before: bv})
+		case !reflect.DeepEqual(bv, av):
+			changes = append(changes, FieldChange{
+				Field: n, Action: "modified", Before: bv, After: av})
+		}
+	}
+	return changes, nil
+}
+
+func asMap(k *types.Kustomization) (map[string]interface{}, error) {
+	j, err := json.Marshal(k)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	err = json.Unmarshal(j, &m)
+	return m, err
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kustfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+)
+
+func makeDryRun(t *testing.T, output string) (*DryRunOptions, *bytes.Buffer) {
+	var buf bytes.Buffer
+	o := &DryRunOptions{enabled: true, output: output}
+	if err := o.Validate(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return o, &buf
+}
+
+func TestValidateDryRun(t *testing.T) {
+	o := &DryRunOptions{output: "json"}
+	if err := o.Validate(nil); err == nil {
+		t.Fatalf("expected error for --output without --dry-run")
+	}
+	o = &DryRunOptions{enabled: true, output: "xml"}
+	if err := o.Validate(nil); err == nil {
+		t.Fatalf("expected error for bad --output")
+	}
+}
+
+func TestWriteDryRunJson(t *testing.T) {
+	original := []byte(`namePrefix: old-
+resources:
+- a.yaml
+`)
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile(pgmconfig.KustomizationFileNames[0], original)
+	dryRun, buf := makeDryRun(t, "json")
+	mf, err := NewKustomizationFileDryRun(fSys, dryRun)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	k, err := mf.Read()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	k.NamePrefix = "new-"
+	k.NameSuffix = "-x"
+	k.Resources = nil
+
+	if err = mf.Write(k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, _ := fSys.ReadFile(pgmconfig.KustomizationFileNames[0])
+	if !bytes.Equal(content, original) {
+		t.Fatalf("dry run modified file:\n%s", content)
+	}
+	var result DryRunResult
+	if err = json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, buf.String())
+	}
+	expected := map[string]string{
+		"namePrefix": "modified",
+		"nameSuffix": "added",
+		"resources":  "removed",
+	}
+	if len(result.Changes) != len(expected) {
+		t.Fatalf("unexpected changes %v", result.Changes)
+	}
+	for _, c := range result.Changes {
+		if expected[c.Field] != c.Action {
+			t.Fatalf("unexpected change %v", c)
+		}
+	}
+	if result.Path != pgmconfig.KustomizationFileNames[0] {
+		t.Fatalf("unexpected path %s", result.Path)
+	}
+}
+
+func TestWriteDryRunPlain(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteTestKustomization()
+	dryRun, buf := makeDryRun(t, "")
+	mf, err := NewKustomizationFileDryRun(fSys, dryRun)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	k, err := mf.Read()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	k.Namespace = "ns"
+	if err = mf.Write(k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("namespace: ns")) {
+		t.Fatalf("unexpected output %s", buf.String())
+	}
+}
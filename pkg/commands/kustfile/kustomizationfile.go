@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
@@ -55,23 +56,37 @@ func determineFieldOrder() []string {
 		"Bases",
 		"NamePrefix",
 		"NameSuffix",
+		"GenerateNameSuffix",
 		"Namespace",
+		"NamespaceFrom",
 		"Crds",
 		"CommonLabels",
 		"CommonAnnotations",
 		"PatchesStrategicMerge",
 		"PatchesJson6902",
+		"PatchesJsonPath",
 		"Patches",
+		"IgnoreFields",
 		"ConfigMapGenerator",
 		"SecretGenerator",
+		"Templates",
 		"GeneratorOptions",
 		"Vars",
+		"ValuesFrom",
 		"Images",
 		"Replicas",
 		"Configurations",
 		"Generators",
 		"Transformers",
+		"Exporters",
+		"Expectations",
+		"PostRenderers",
+		"Clusters",
 		"Inventory",
+		"OpenAPI",
+		"BuildMetadata",
+		"Tenancy",
+		"DependsOn",
 	}
 
 	// Add deprecated fields here.
@@ -114,14 +129,36 @@ func squash(x [][]byte) []byte {
 }
 
 type kustomizationFile struct {
+	dir            string
 	path           string
 	fSys           fs.FileSystem
 	originalFields []*commentedField
+	// dryRun, if enabled, has Write print the
+	// file rather than write it.
+	dryRun *DryRunOptions
 }
 
 // NewKustomizationFile returns a new instance.
 func NewKustomizationFile(fSys fs.FileSystem) (*kustomizationFile, error) { // nolint
-	mf := &kustomizationFile{fSys: fSys}
+	return NewKustomizationFileIn(fSys, "")
+}
+
+// NewKustomizationFileDryRun returns a new instance
+// whose Write prints the file, rather than writes it,
+// if dryRun is enabled.
+func NewKustomizationFileDryRun(fSys fs.FileSystem, dryRun *DryRunOptions) (*kustomizationFile, error) { // nolint
+	mf, err := NewKustomizationFile(fSys)
+	if err != nil {
+		return nil, err
+	}
+	mf.dryRun = dryRun
+	return mf, nil
+}
+
+// NewKustomizationFileIn returns a new instance
+// for the kustomization file in dir.
+func NewKustomizationFileIn(fSys fs.FileSystem, dir string) (*kustomizationFile, error) { // nolint
+	mf := &kustomizationFile{fSys: fSys, dir: dir}
 	err := mf.validate()
 	if err != nil {
 		return nil, err
@@ -133,6 +170,7 @@ func (mf *kustomizationFile) validate() error {
 	match := 0
 	var path []string
 	for _, kfilename := range pgmconfig.KustomizationFileNames {
+		kfilename = filepath.Join(mf.dir, kfilename)
 		if mf.fSys.Exists(kfilename) {
 			match += 1
 			path = append(path, kfilename)
@@ -181,6 +219,9 @@ func (mf *kustomizationFile) Write(kustomization *types.Kustomization) error {
 	if err != nil {
 		return err
 	}
+	if mf.dryRun != nil && mf.dryRun.enabled {
+		return mf.writeDryRun(kustomization, data)
+	}
 	return mf.fSys.WriteFile(mf.path, data)
 }
 
@@ -34,23 +34,37 @@ func TestFieldOrder(t *testing.T) {
 		"Bases",
 		"NamePrefix",
 		"NameSuffix",
+		"GenerateNameSuffix",
 		"Namespace",
+		"NamespaceFrom",
 		"Crds",
 		"CommonLabels",
 		"CommonAnnotations",
 		"PatchesStrategicMerge",
 		"PatchesJson6902",
+		"PatchesJsonPath",
 		"Patches",
+		"IgnoreFields",
 		"ConfigMapGenerator",
 		"SecretGenerator",
+		"Templates",
 		"GeneratorOptions",
 		"Vars",
+		"ValuesFrom",
 		"Images",
 		"Replicas",
 		"Configurations",
 		"Generators",
 		"Transformers",
+		"Exporters",
+		"Expectations",
+		"PostRenderers",
+		"Clusters",
 		"Inventory",
+		"OpenAPI",
+		"BuildMetadata",
+		"Tenancy",
+		"DependsOn",
 	}
 	actual := determineFieldOrder()
 	if len(expected) != len(actual) {
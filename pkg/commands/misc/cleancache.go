@@ -0,0 +1,101 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package misc
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+)
+
+type cleanCacheOptions struct {
+	olderThan         time.Duration
+	repoCacheDir      string
+	generatorCacheDir string
+}
+
+// NewCmdCleanCache returns an instance of 'clean-cache' subcommand.
+func NewCmdCleanCache(out io.Writer, fSys fs.FileSystem) *cobra.Command {
+	var o cleanCacheOptions
+	c := &cobra.Command{
+		Use:   "clean-cache",
+		Short: "Remove temporary files and cached clones and ConfigMaps",
+		Long: `Remove the temporary files and directories, e.g. clones
+of remote bases, that kustomize failed to remove, say
+because it crashed.  They are in $` + fs.TmpBaseEnv + `, or the
+directory given by --tmp-dir, else the system's temporary
+directory, and their names start with '` + fs.TmpPrefix + `'.
+
+Also remove the clones of the repo cache, in the directory
+given by --repo-cache-dir, else $` + pgmconfig.XDG_CACHE_HOME + `/kustomize/repos,
+and, if --generator-cache-dir is given, the ConfigMaps
+cached in it.  Clones a running build is reading are kept,
+whatever their age.`,
+		Example: `
+	# Remove leftovers and cache entries unused for an hour
+	kustomize clean-cache
+
+	# Remove all of them, even those of running builds,
+	# but for clones in use
+	kustomize clean-cache --older-than 0
+
+	# Also remove the ConfigMaps a build cached
+	kustomize clean-cache --generator-cache-dir ~/.cache/kustomize/generated
+`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.RunCleanCache(out, fSys)
+		},
+	}
+	c.Flags().DurationVar(
+		&o.olderThan, "older-than", time.Hour,
+		"Remove only what was last modified, or used, longer ago "+
+			"than this, to spare builds still running.")
+	c.Flags().StringVar(
+		&o.repoCacheDir, "repo-cache-dir", "",
+		"Directory of the repo cache; if unspecified, "+
+			"$"+pgmconfig.XDG_CACHE_HOME+"/kustomize/repos.")
+	c.Flags().StringVar(
+		&o.generatorCacheDir, "generator-cache-dir", "",
+		"Directory builds cached generated ConfigMaps in, "+
+			"as given to 'kustomize build'; if unspecified, none.")
+	return c
+}
+
+// RunCleanCache removes stale temporary files, and
+// stale entries of the caches.
+func (o *cleanCacheOptions) RunCleanCache(
+	out io.Writer, fSys fs.FileSystem) error {
+	removed, err := fs.RemoveStaleTmp(fSys, o.olderThan)
+	o.report(out, removed)
+	if err != nil {
+		return err
+	}
+	dir := o.repoCacheDir
+	if dir == "" {
+		dir = git.DefaultRepoCacheDir()
+	}
+	removed, err = git.CleanRepoCache(dir, o.olderThan)
+	o.report(out, removed)
+	if err != nil || o.generatorCacheDir == "" {
+		return err
+	}
+	removed, err = kunstruct.CleanGeneratorCache(
+		o.generatorCacheDir, o.olderThan)
+	o.report(out, removed)
+	return err
+}
+
+func (o *cleanCacheOptions) report(out io.Writer, removed []string) {
+	for _, p := range removed {
+		fmt.Fprintln(out, "removed", p)
+	}
+}
@@ -18,16 +18,29 @@ package misc
 
 import (
 	"fmt"
+	"io"
 	"path/filepath"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/pkg/render"
 
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config/defaultconfig"
+	"sigs.k8s.io/yaml"
 )
 
 // NewCmdConfig returns an instance of 'config' subcommand.
-func NewCmdConfig(fsys fs.FileSystem) *cobra.Command {
+func NewCmdConfig(
+	out io.Writer, fsys fs.FileSystem, v ifc.Validator,
+	rf *resmap.Factory, ptf resmap.PatchFactory) *cobra.Command {
 	c := &cobra.Command{
 		Use:   "config",
 		Short: "Config Kustomize transformers",
@@ -35,11 +48,15 @@ func NewCmdConfig(fsys fs.FileSystem) *cobra.Command {
 		Example: `
 	# Save the default transformer configurations to a local directory
 	kustomize config save -d ~/.kustomize/config
+
+	# Print the transformer configuration used to build a kustomization
+	kustomize config view-builtins someDir
 `,
 		Args: cobra.MinimumNArgs(1),
 	}
 	c.AddCommand(
 		newCmdSave(fsys),
+		newCmdViewBuiltins(out, fsys, v, rf, ptf),
 	)
 	return c
 }
@@ -112,3 +129,115 @@ func (o *saveOptions) RunSave(fsys fs.FileSystem) error {
 	}
 	return nil
 }
+
+type viewBuiltinsOptions struct {
+	kustomizationPath string
+	kind              string
+	loadRestrictor    loader.LoadRestrictorFunc
+}
+
+func newCmdViewBuiltins(
+	out io.Writer, fsys fs.FileSystem, v ifc.Validator,
+	rf *resmap.Factory, ptf resmap.PatchFactory) *cobra.Command {
+	var o viewBuiltinsOptions
+	pluginConfig := plugins.DefaultPluginConfig()
+	pl := plugins.NewLoader(pluginConfig, rf)
+
+	c := &cobra.Command{
+		Use:   "view-builtins [path]",
+		Short: "Print the transformer configuration in effect",
+		Long: `Print the built-in transformer configuration as YAML.
+
+Given the path to a kustomization, print the configuration
+used to build it: the defaults as modified by its
+configurations and crds, and those of its bases.`,
+		Example: `
+	# Print the default transformer configurations
+	kustomize config view-builtins
+
+	# Print the name reference configuration used by someDir
+	kustomize config view-builtins someDir --kind nameReference
+`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			tc, err := o.effectiveConfig(fsys, v, rf, ptf, pl)
+			if err != nil {
+				return err
+			}
+			return o.RunViewBuiltins(out, tc)
+		},
+	}
+	c.Flags().StringVar(&o.kind, "kind", "",
+		"Print only this configuration, e.g. nameReference or commonLabels.")
+	loader.AddFlagLoadRestrictor(c.Flags())
+	plugins.AddFlagEnablePlugins(c.Flags(), &pluginConfig.Enabled)
+	return c
+}
+
+// Validate validates view-builtins command.
+func (o *viewBuiltinsOptions) Validate(args []string) (err error) {
+	if len(args) > 1 {
+		return errors.New(
+			"specify at most one path to " + pgmconfig.KustomizationFileNames[0])
+	}
+	if len(args) == 1 {
+		o.kustomizationPath = args[0]
+	}
+	o.loadRestrictor, err = loader.ValidateFlagLoadRestrictor()
+	return err
+}
+
+func (o *viewBuiltinsOptions) effectiveConfig(
+	fsys fs.FileSystem, v ifc.Validator, rf *resmap.Factory,
+	ptf resmap.PatchFactory,
+	pl *plugins.Loader) (*config.TransformerConfig, error) {
+	if o.kustomizationPath == "" {
+		return config.MakeDefaultConfig(), nil
+	}
+	kt, cleanup, err := render.NewRendererWith(fsys, v, rf, ptf, pl).
+		WithLoadRestrictor(o.loadRestrictor).
+		Target(o.kustomizationPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	ra, err := kt.AccumulateTarget()
+	if err != nil {
+		return nil, err
+	}
+	return ra.GetTransformerConfig(), nil
+}
+
+// RunViewBuiltins prints the given configuration,
+// or just the list named by --kind.
+func (o *viewBuiltinsOptions) RunViewBuiltins(
+	out io.Writer, tc *config.TransformerConfig) error {
+	b, err := yaml.Marshal(tc)
+	if err != nil {
+		return err
+	}
+	if o.kind != "" {
+		var m map[string]interface{}
+		if err = yaml.Unmarshal(b, &m); err != nil {
+			return err
+		}
+		name, err := config.ListName(o.kind)
+		if err != nil {
+			return kusterr.WithClass(kusterr.ClassUsage, err)
+		}
+		list, ok := m[name]
+		if !ok {
+			list = []interface{}{}
+		}
+		b, err = yaml.Marshal(map[string]interface{}{name: list})
+		if err != nil {
+			return err
+		}
+	}
+	_, err = out.Write(b)
+	return err
+}
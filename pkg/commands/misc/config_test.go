@@ -17,12 +17,21 @@ limitations under the License.
 package misc
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
 	"strings"
 	"testing"
 
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
 )
 
 func TestValidate(t *testing.T) {
@@ -85,3 +94,73 @@ func TestRunSave(t *testing.T) {
 		t.Fatal("default configurations are not successfully save.")
 	}
 }
+
+func TestRunViewBuiltins(t *testing.T) {
+	fsys := fs.MakeFakeFS()
+	fsys.WriteFile("/app/kustomization.yaml", []byte(`
+configurations:
+- config.yaml
+`))
+	fsys.WriteFile("/app/config.yaml", []byte(`
+behaviors:
+  nameReference: replace
+nameReference:
+- kind: Gorilla
+  FieldSpecs:
+  - kind: AnimalPark
+    path: spec/gorillaRef/name
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	o := viewBuiltinsOptions{
+		kustomizationPath: "/app",
+		kind:              "namereference",
+		loadRestrictor:    loader.RestrictionRootOnly,
+	}
+	tc, err := o.effectiveConfig(
+		fsys, validators.MakeFakeValidator(), rf,
+		transformer.NewFactoryImpl(),
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var out bytes.Buffer
+	err = o.RunViewBuiltins(&out, tc)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := `nameReference:
+- FieldSpecs:
+  - kind: AnimalPark
+    path: spec/gorillaRef/name
+  kind: Gorilla
+`
+	if out.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, out.String())
+	}
+
+	o = viewBuiltinsOptions{kind: "NAMEPREFIX"}
+	out.Reset()
+	err = o.RunViewBuiltins(&out, config.MakeDefaultConfig())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if out.String() != "namePrefix:\n- path: metadata/name\n" {
+		t.Fatalf("unexpected default namePrefix config\n%s", out.String())
+	}
+
+	o.kind = "images"
+	out.Reset()
+	err = o.RunViewBuiltins(&out, config.MakeDefaultConfig())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if out.String() != "images: []\n" {
+		t.Fatalf("unexpected default images config\n%s", out.String())
+	}
+
+	o.kind = "labels"
+	if err = o.RunViewBuiltins(&out, tc); err == nil {
+		t.Fatalf("expected error for unknown kind")
+	}
+}
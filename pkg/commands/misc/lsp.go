@@ -0,0 +1,30 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package misc
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/lsp"
+)
+
+// NewCmdLsp returns an instance of 'lsp' subcommand.
+func NewCmdLsp(fSys fs.FileSystem, in io.Reader, out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lsp",
+		Short: "Run a language server for kustomization files over stdio",
+		Long: `Run a Language Server Protocol server on stdin and stdout.
+
+Editors using it get diagnostics for syntax errors, unknown
+fields and missing referenced files, go to definition for
+resources, bases and patches, and hover docs for fields.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return lsp.NewServer(fSys, in, out).Serve()
+		},
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package misc
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/openapi"
+)
+
+// NewCmdOpenAPI returns an instance of 'openapi' subcommand.
+func NewCmdOpenAPI(out io.Writer, fSys fs.FileSystem) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "openapi",
+		Short: "Manage the OpenAPI schemas used by strategic merge patches",
+		Example: `
+	# Save the schema of the current kubectl context's cluster
+	kustomize openapi fetch
+
+	# Build with that schema, assuming the cluster runs 1.16
+	kustomize build someDir --kube-version 1.16
+`,
+		Args: cobra.MinimumNArgs(1),
+	}
+	c.AddCommand(newCmdOpenAPIFetch(out, fSys))
+	return c
+}
+
+type fetchOptions struct {
+	kubeconfig string
+	context    string
+	version    string
+}
+
+func newCmdOpenAPIFetch(out io.Writer, fSys fs.FileSystem) *cobra.Command {
+	var o fetchOptions
+	c := &cobra.Command{
+		Use:   "fetch",
+		Short: "Save a cluster's OpenAPI schema, including its custom resources",
+		Long: `Download the OpenAPI document of a cluster and save it as
+the schema of the cluster's Kubernetes version, for use by
+'kustomize build --kube-version' and the openapi field.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.RunFetch(out, fSys)
+		},
+	}
+	c.Flags().StringVar(
+		&o.kubeconfig, "kubeconfig", openapi.DefaultKubeconfig(),
+		"Path to the kubeconfig file naming the cluster.")
+	c.Flags().StringVar(
+		&o.context, "context", "",
+		"Kubeconfig context to use, instead of the current one.")
+	c.Flags().StringVar(
+		&o.version, "version", "",
+		"Save the schema under this Kubernetes version, "+
+			"instead of the one the cluster reports.")
+	return c
+}
+
+// RunFetch saves the schema of the cluster.
+func (o *fetchOptions) RunFetch(out io.Writer, fSys fs.FileSystem) error {
+	c, err := openapi.ClusterFromKubeconfig(fSys, o.kubeconfig, o.context)
+	if err != nil {
+		return err
+	}
+	v := o.version
+	if v == "" {
+		v, err = c.Version()
+		if err != nil {
+			return err
+		}
+	}
+	path, err := c.Fetch(fSys, v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out,
+		"saved the openapi schema of %s as version %s in %s\n",
+		c.Server, v, path)
+	return err
+}
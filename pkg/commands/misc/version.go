@@ -17,60 +17,79 @@ limitations under the License.
 package misc
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
-	"runtime"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/version"
+	"sigs.k8s.io/yaml"
 )
 
-var (
-	kustomizeVersion = "unknown"
-	goos             = runtime.GOOS
-	goarch           = runtime.GOARCH
-	gitCommit        = "$Format:%H$" // sha1 from git, output of $(git rev-parse HEAD)
-
-	buildDate = "1970-01-01T00:00:00Z" // build date in ISO8601 format, output of $(date -u +'%Y-%m-%dT%H:%M:%SZ')
-)
-
-// version returns the version of kustomize.
-type version struct {
-	// KustomizeVersion is a kustomize binary version.
-	KustomizeVersion string `json:"kustomizeVersion"`
-	// GitCommit is a git commit
-	GitCommit string `json:"gitCommit"`
-	// BuildDate is a build date of the binary.
-	BuildDate string `json:"buildDate"`
-	// GoOs holds OS name.
-	GoOs string `json:"goOs"`
-	// GoArch holds architecture name.
-	GoArch string `json:"goArch"`
+type versionOptions struct {
+	// client is accepted for kubectl compatibility;
+	// kustomize has no server component.
+	client bool
+	short  bool
+	output string
 }
 
-// getVersion returns version.
-func getVersion() version {
-	return version{
-		kustomizeVersion,
-		gitCommit,
-		buildDate,
-		goos,
-		goarch,
+// NewCmdVersion makes version command.
+func NewCmdVersion(w io.Writer) *cobra.Command {
+	var o versionOptions
+	c := &cobra.Command{
+		Use:   "version",
+		Short: "Prints the kustomize version",
+		Example: `kustomize version
+kustomize version --client -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			return o.Run(w, version.Get())
+		},
 	}
+	c.Flags().BoolVar(&o.client, "client", false,
+		"Print only the client version (the default; kustomize has no server).")
+	c.Flags().BoolVar(&o.short, "short", false,
+		"Print just the version number.")
+	c.Flags().StringVarP(&o.output, "output", "o", "",
+		"One of 'yaml' or 'json'.")
+	return c
 }
 
-// Print prints version.
-func (v version) Print(w io.Writer) {
-	fmt.Fprintf(w, "Version: %+v\n", v)
+// Validate validates version command.
+func (o *versionOptions) Validate() error {
+	switch o.output {
+	case "", "json", "yaml":
+		return nil
+	}
+	return fmt.Errorf("--output must be 'yaml' or 'json'")
 }
 
-// NewCmdVersion makes version command.
-func NewCmdVersion(w io.Writer) *cobra.Command {
-	return &cobra.Command{
-		Use:     "version",
-		Short:   "Prints the kustomize version",
-		Example: `kustomize version`,
-		Run: func(cmd *cobra.Command, args []string) {
-			getVersion().Print(w)
-		},
+// Run prints the given version info.
+func (o *versionOptions) Run(w io.Writer, v version.Info) error {
+	switch o.output {
+	case "json":
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	case "yaml":
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	}
+	if o.short {
+		_, err := fmt.Fprintln(w, v.KustomizeVersion)
+		return err
 	}
+	_, err := fmt.Fprintf(w, "Version: %+v\n", v)
+	return err
 }
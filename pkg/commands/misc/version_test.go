@@ -0,0 +1,54 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package misc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/version"
+)
+
+func TestVersionValidate(t *testing.T) {
+	for _, o := range []string{"", "json", "yaml"} {
+		if err := (&versionOptions{output: o}).Validate(); err != nil {
+			t.Fatalf("unexpected error for %q: %v", o, err)
+		}
+	}
+	if err := (&versionOptions{output: "xml"}).Validate(); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestVersionRunJson(t *testing.T) {
+	var buf bytes.Buffer
+	o := versionOptions{client: true, output: "json"}
+	if err := o.Run(&buf, version.Get()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var v version.Info
+	if err := json.Unmarshal(buf.Bytes(), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.PluginABIVersion != version.PluginABIVersion {
+		t.Fatalf("unexpected plugin ABI version %q", v.PluginABIVersion)
+	}
+	if !v.Supports(types.KustomizationVersion) {
+		t.Fatalf("expected support for %s", types.KustomizationVersion)
+	}
+}
+
+func TestVersionRunShort(t *testing.T) {
+	var buf bytes.Buffer
+	o := versionOptions{short: true}
+	if err := o.Run(&buf, version.Info{KustomizeVersion: "v3.1.0"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "v3.1.0" {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
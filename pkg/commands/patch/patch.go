@@ -0,0 +1,108 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package patch holds commands for trying out patches.
+package patch
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/patch"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// NewCmdPatch returns an instance of 'patch' subcommand.
+func NewCmdPatch(
+	out io.Writer, fSys fs.FileSystem, rf *resource.Factory) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "patch",
+		Short: "Work with individual patches",
+		Args:  cobra.MinimumNArgs(1),
+	}
+	c.AddCommand(newCmdApply(out, fSys, rf))
+	return c
+}
+
+type applyOptions struct {
+	targetPath string
+	patchPath  string
+}
+
+func newCmdApply(
+	out io.Writer, fSys fs.FileSystem, rf *resource.Factory) *cobra.Command {
+	var o applyOptions
+	c := &cobra.Command{
+		Use:   "apply",
+		Short: "Print a resource with a single patch applied",
+		Long: `Apply one strategic merge or JSON 6902 patch to one
+resource and print the result, without writing anything.
+
+A strategic merge patch is applied whatever its name
+and kind, as if the resource were its target.`,
+		Example: `
+	# Try a patch out on a deployment
+	kustomize patch apply --target deployment.yaml --patch patch.yaml
+`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			return o.RunApply(out, fSys, rf)
+		},
+	}
+	c.Flags().StringVar(&o.targetPath, "target", "",
+		"File holding the one resource to patch.")
+	c.Flags().StringVar(&o.patchPath, "patch", "",
+		"File holding the patch.")
+	return c
+}
+
+// Validate validates apply command.
+func (o *applyOptions) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("patch apply takes no arguments")
+	}
+	if o.targetPath == "" || o.patchPath == "" {
+		return errors.New("both --target and --patch must be specified")
+	}
+	return nil
+}
+
+// RunApply prints the patched resource as yaml.
+func (o *applyOptions) RunApply(
+	out io.Writer, fSys fs.FileSystem, rf *resource.Factory) error {
+	content, err := fSys.ReadFile(o.targetPath)
+	if err != nil {
+		return kusterr.WithClass(kusterr.ClassLoad, err)
+	}
+	resources, err := rf.SliceFromBytes(content)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", o.targetPath)
+	}
+	if len(resources) != 1 {
+		return fmt.Errorf(
+			"%s must hold exactly one resource, found %d",
+			o.targetPath, len(resources))
+	}
+	p, err := fSys.ReadFile(o.patchPath)
+	if err != nil {
+		return kusterr.WithClass(kusterr.ClassLoad, err)
+	}
+	result, _, err := patch.Apply(rf, resources[0], p)
+	if err != nil {
+		return errors.Wrapf(err, "applying %s", o.patchPath)
+	}
+	b, err := result.AsYAML()
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestPatchApply(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("cm.yaml", []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  a: "1"
+`))
+	fSys.WriteFile("patch.yaml", []byte(`- op: add
+  path: /data/b
+  value: "2"
+`))
+	rf := resource.NewFactory(kunstruct.NewKunstructuredFactoryImpl())
+	var out bytes.Buffer
+	cmd := NewCmdPatch(&out, fSys, rf)
+	cmd.SetArgs([]string{
+		"apply", "--target", "cm.yaml", "--patch", "patch.yaml"})
+	err := cmd.Execute()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: v1
+data:
+  a: "1"
+  b: "2"
+kind: ConfigMap
+metadata:
+  name: cm
+`
+	if out.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
+
+func TestPatchApplyValidate(t *testing.T) {
+	o := applyOptions{targetPath: "cm.yaml"}
+	if err := o.Validate(nil); err == nil {
+		t.Errorf("expected error for missing --patch")
+	}
+	o.patchPath = "patch.yaml"
+	if err := o.Validate([]string{"extra"}); err == nil {
+		t.Errorf("expected error for extra argument")
+	}
+	if err := o.Validate(nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
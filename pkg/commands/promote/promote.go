@@ -0,0 +1,353 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package promote copies fields of one overlay's
+// kustomization to another's.
+package promote
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/image"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+const (
+	fieldImages                = "images"
+	fieldReplicas              = "replicas"
+	fieldPatchesStrategicMerge = "patchesStrategicMerge"
+)
+
+var promotableFields = []string{
+	fieldImages, fieldReplicas, fieldPatchesStrategicMerge}
+
+// Options contain the options for running promote.
+type Options struct {
+	from    string
+	to      string
+	fields  []string
+	patches []string
+	dryRun  bool
+}
+
+var examples = `
+To promote the images of the staging overlay to the
+prod overlay, run
+
+  kustomize promote overlays/staging overlays/prod
+
+Each entry of the images field of staging replaces the
+entry of the same name in prod, or is added to it.
+Entries of prod that staging hasn't are kept.
+
+To also promote replicas, and the strategic merge
+patches of staging, copying their files to prod, run
+
+  kustomize promote overlays/staging overlays/prod \
+      --fields images,replicas,patchesStrategicMerge
+
+--patches selects the patches promoted, e.g.
+
+  --fields patchesStrategicMerge --patches resources.yaml
+
+With --dry-run, the changes are listed, but nothing
+is written.
+`
+
+// NewCmdPromote creates a new promote command.
+func NewCmdPromote(out io.Writer, fSys fs.FileSystem) *cobra.Command {
+	var o Options
+
+	cmd := &cobra.Command{
+		Use:          "promote {from} {to}",
+		Short:        "Copy fields of one overlay's kustomization to another's",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			changes, err := o.RunPromote(fSys)
+			if err != nil {
+				return err
+			}
+			return o.emit(out, changes)
+		},
+	}
+	cmd.Flags().StringSliceVar(
+		&o.fields,
+		"fields", []string{fieldImages},
+		"The fields to promote, of "+
+			strings.Join(promotableFields, ", ")+".")
+	cmd.Flags().StringSliceVar(
+		&o.patches,
+		"patches", nil,
+		"The "+fieldPatchesStrategicMerge+" entries to promote; "+
+			"if unspecified, all of them.")
+	cmd.Flags().BoolVar(
+		&o.dryRun,
+		"dry-run", false,
+		"List the changes, without writing them.")
+	return cmd
+}
+
+// Validate validates promote command.
+func (o *Options) Validate(args []string) error {
+	if len(args) != 2 {
+		return errors.New("specify the overlays to promote from and to")
+	}
+	o.from, o.to = args[0], args[1]
+	if len(o.fields) == 0 {
+		o.fields = []string{fieldImages}
+	}
+	for _, f := range o.fields {
+		if !contains(promotableFields, f) {
+			return fmt.Errorf("can't promote '%s'; promotable fields: %v",
+				f, promotableFields)
+		}
+	}
+	if len(o.patches) > 0 && !contains(o.fields, fieldPatchesStrategicMerge) {
+		return fmt.Errorf("--patches requires --fields %s",
+			fieldPatchesStrategicMerge)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Change is the change to one entry of a field
+// of the kustomization promoted to.
+type Change struct {
+	Field string
+	// Name is that of the image, the resource
+	// whose replicas are set, or the patch.
+	Name string
+	// Before and After describe the entry,
+	// Before being "" if it's added.
+	Before string
+	After  string
+}
+
+// file is a file to write.
+type file struct {
+	path string
+	data []byte
+}
+
+// RunPromote copies the fields of the kustomization
+// promoted from to that promoted to, and, unless in a
+// dry run, writes it and the patch files copied.
+func (o *Options) RunPromote(fSys fs.FileSystem) ([]Change, error) {
+	from, err := read(fSys, o.from)
+	if err != nil {
+		return nil, err
+	}
+	mf, err := kustfile.NewKustomizationFileIn(fSys, o.to)
+	if err != nil {
+		return nil, err
+	}
+	to, err := mf.Read()
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	var files []file
+	for _, f := range o.fields {
+		switch f {
+		case fieldImages:
+			changes = append(changes, promoteImages(from, to)...)
+		case fieldReplicas:
+			changes = append(changes, promoteReplicas(from, to)...)
+		case fieldPatchesStrategicMerge:
+			c, pf, err := o.promotePatches(fSys, from, to)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, c...)
+			files = append(files, pf...)
+		}
+	}
+	if o.dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+	for _, f := range files {
+		if err := fSys.WriteFile(f.path, f.data); err != nil {
+			return nil, err
+		}
+	}
+	return changes, mf.Write(to)
+}
+
+func read(fSys fs.FileSystem, dir string) (*types.Kustomization, error) {
+	mf, err := kustfile.NewKustomizationFileIn(fSys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return mf.Read()
+}
+
+func promoteImages(from, to *types.Kustomization) []Change {
+	var changes []Change
+	for _, img := range from.Images {
+		c := Change{Field: fieldImages, Name: img.Name, After: describe(img)}
+		found := false
+		for i := range to.Images {
+			if to.Images[i].Name == img.Name {
+				found = true
+				c.Before = describe(to.Images[i])
+				to.Images[i] = img
+			}
+		}
+		if !found {
+			to.Images = append(to.Images, img)
+		}
+		if c.Before != c.After {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// describe writes an images entry as the image
+// it makes, e.g. my-registry/my-app:v2.
+func describe(img image.Image) string {
+	s := img.Name
+	if img.NewName != "" {
+		s = img.NewName
+	}
+	switch {
+	case img.Digest != "":
+		s += "@" + img.Digest
+	case img.NewTag != "":
+		s += ":" + img.NewTag
+	}
+	return s
+}
+
+func promoteReplicas(from, to *types.Kustomization) []Change {
+	var changes []Change
+	for _, r := range from.Replicas {
+		c := Change{
+			Field: fieldReplicas, Name: r.Name,
+			After: strconv.FormatInt(r.Count, 10)}
+		found := false
+		for i := range to.Replicas {
+			if to.Replicas[i].Name == r.Name {
+				found = true
+				c.Before = strconv.FormatInt(to.Replicas[i].Count, 10)
+				to.Replicas[i] = r
+			}
+		}
+		if !found {
+			to.Replicas = append(to.Replicas, r)
+		}
+		if c.Before != c.After {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// promotePatches adds the selected strategic merge
+// patches of from to to, returning the patch files
+// to copy.  Inline patches are copied as they are.
+func (o *Options) promotePatches(
+	fSys fs.FileSystem, from, to *types.Kustomization) (
+	[]Change, []file, error) {
+	for _, p := range o.patches {
+		if !containsPatch(from.PatchesStrategicMerge, p) {
+			return nil, nil, fmt.Errorf(
+				"%s has no %s %s", o.from, fieldPatchesStrategicMerge, p)
+		}
+	}
+	var changes []Change
+	var files []file
+	for _, p := range from.PatchesStrategicMerge {
+		name := string(p)
+		if len(o.patches) > 0 && !contains(o.patches, name) {
+			continue
+		}
+		c := Change{Field: fieldPatchesStrategicMerge, Name: name}
+		if strings.Contains(name, "\n") {
+			name = "inline patch"
+			c.Name = name
+			c.After = "added"
+			if containsPatch(to.PatchesStrategicMerge, string(p)) {
+				continue
+			}
+		} else {
+			if filepath.IsAbs(name) ||
+				strings.HasPrefix(filepath.Clean(name), "..") {
+				return nil, nil, fmt.Errorf(
+					"can't promote %s, as it's outside %s", name, o.from)
+			}
+			data, err := fSys.ReadFile(filepath.Join(o.from, name))
+			if err != nil {
+				return nil, nil, err
+			}
+			path := filepath.Join(o.to, name)
+			c.After = "copied"
+			if fSys.Exists(path) {
+				old, err := fSys.ReadFile(path)
+				if err != nil {
+					return nil, nil, err
+				}
+				if string(old) == string(data) &&
+					containsPatch(to.PatchesStrategicMerge, name) {
+					continue
+				}
+				c.Before = "differs"
+			}
+			files = append(files, file{path, data})
+		}
+		if !containsPatch(to.PatchesStrategicMerge, string(p)) {
+			to.PatchesStrategicMerge = append(to.PatchesStrategicMerge, p)
+		}
+		changes = append(changes, c)
+	}
+	return changes, files, nil
+}
+
+func containsPatch(list []types.PatchStrategicMerge, p string) bool {
+	for _, x := range list {
+		if string(x) == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Options) emit(out io.Writer, changes []Change) error {
+	for _, c := range changes {
+		before := c.Before
+		if before == "" {
+			before = "<unset>"
+		}
+		fmt.Fprintf(out, "%s %s: %s -> %s\n",
+			c.Field, c.Name, before, c.After)
+	}
+	verb := "promoted"
+	if o.dryRun {
+		verb = "would promote"
+	}
+	_, err := fmt.Fprintf(out, "%s %d changes from %s to %s\n",
+		verb, len(changes), o.from, o.to)
+	return err
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package promote
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+func writeOverlays(fSys fs.FileSystem) {
+	fSys.WriteFile("/app/staging/kustomization.yaml", []byte(`resources:
+- ../base
+images:
+- name: my-app
+  newTag: v2
+- name: sidecar
+  newName: registry/sidecar
+  newTag: "1.1"
+replicas:
+- name: my-app
+  count: 2
+patchesStrategicMerge:
+- resources.yaml
+- debug.yaml
+`))
+	fSys.WriteFile("/app/staging/resources.yaml", []byte(`kind: Deployment
+metadata:
+  name: my-app
+`))
+	fSys.WriteFile("/app/staging/debug.yaml", []byte(`kind: Deployment
+`))
+	fSys.WriteFile("/app/prod/kustomization.yaml", []byte(`# prod
+resources:
+- ../base
+images:
+- name: my-app
+  newTag: v1
+- name: db
+  newTag: "9"
+replicas:
+- name: my-app
+  count: 5
+`))
+}
+
+func runPromote(t *testing.T, fSys fs.FileSystem, o Options) string {
+	if err := o.Validate([]string{"/app/staging", "/app/prod"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	changes, err := o.RunPromote(fSys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := o.emit(&out, changes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return out.String()
+}
+
+func TestPromoteImages(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeOverlays(fSys)
+	out := runPromote(t, fSys, Options{})
+	expected := `images my-app: my-app:v1 -> my-app:v2
+images sidecar: <unset> -> registry/sidecar:1.1
+promoted 2 changes from /app/staging to /app/prod
+`
+	if out != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+	content, _ := fSys.ReadFile("/app/prod/kustomization.yaml")
+	expected = `# prod
+resources:
+- ../base
+images:
+- name: my-app
+  newTag: v2
+- name: db
+  newTag: "9"
+- name: sidecar
+  newName: registry/sidecar
+  newTag: "1.1"
+replicas:
+- count: 5
+  name: my-app
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+`
+	if string(content) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, content)
+	}
+	out = runPromote(t, fSys, Options{})
+	if out != "promoted 0 changes from /app/staging to /app/prod\n" {
+		t.Fatalf("unexpected output %s", out)
+	}
+}
+
+func TestPromoteReplicasAndPatches(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeOverlays(fSys)
+	out := runPromote(t, fSys, Options{
+		fields:  []string{"replicas", "patchesStrategicMerge"},
+		patches: []string{"resources.yaml"},
+		dryRun:  true,
+	})
+	expected := `replicas my-app: 5 -> 2
+patchesStrategicMerge resources.yaml: <unset> -> copied
+would promote 2 changes from /app/staging to /app/prod
+`
+	if out != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+	if fSys.Exists("/app/prod/resources.yaml") {
+		t.Fatalf("dry run copied a patch")
+	}
+	runPromote(t, fSys, Options{
+		fields:  []string{"replicas", "patchesStrategicMerge"},
+		patches: []string{"resources.yaml"},
+	})
+	content, _ := fSys.ReadFile("/app/prod/kustomization.yaml")
+	expected = `# prod
+resources:
+- ../base
+images:
+- name: my-app
+  newTag: v1
+- name: db
+  newTag: "9"
+replicas:
+- count: 2
+  name: my-app
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+patchesStrategicMerge:
+- resources.yaml
+`
+	if string(content) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, content)
+	}
+	if !fSys.Exists("/app/prod/resources.yaml") {
+		t.Fatalf("patch not copied")
+	}
+}
+
+func TestPromoteValidate(t *testing.T) {
+	for _, o := range []Options{
+		{fields: []string{"namespace"}},
+		{fields: []string{"images"}, patches: []string{"resources.yaml"}},
+	} {
+		if err := o.Validate([]string{"a", "b"}); err == nil {
+			t.Fatalf("%v: expected error", o)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package resolve shows the effective value of a
+// kustomization field across bases and overlays.
+package resolve
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/render"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+	"sigs.k8s.io/yaml"
+)
+
+// Options contain the options for running resolve.
+type Options struct {
+	field             string
+	kustomizationPath string
+	output            string
+	loadRestrictor    loader.LoadRestrictorFunc
+	loader            loader.Options
+}
+
+var examples = `
+To see the name prefix the resources of each kustomization
+in the build of someDir get, and which kustomization files
+make it up, run
+
+  kustomize resolve namePrefix someDir
+
+The fields resolved are ` + strings.Join(target.ResolvableFields(), ", ") + `.
+
+To get the same as JSON, run
+
+  kustomize resolve namePrefix someDir -o json
+`
+
+// NewCmdResolve creates a new resolve command.
+func NewCmdResolve(
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, rf *resmap.Factory,
+	ptf resmap.PatchFactory) *cobra.Command {
+	var o Options
+
+	pluginConfig := plugins.DefaultPluginConfig()
+	pl := plugins.NewLoader(pluginConfig, rf)
+
+	cmd := &cobra.Command{
+		Use:          "resolve {field} {path}",
+		Short:        "Print the effective value of a field across bases and overlays",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			r, err := o.RunResolve(v, fSys, rf, ptf, pl)
+			if err != nil {
+				return err
+			}
+			return o.emit(out, r)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&o.output,
+		"output", "o", "",
+		"One of 'json' or 'yaml'.  If unspecified, print "+
+			"each kustomization's value, then its contributions.")
+	loader.AddFlagLoadRestrictor(cmd.Flags())
+	o.loader.AddFlags(cmd.Flags())
+	plugins.AddFlagEnablePlugins(
+		cmd.Flags(), &pluginConfig.Enabled)
+	return cmd
+}
+
+// Validate validates resolve command.
+func (o *Options) Validate(args []string) (err error) {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New("specify a field, and optionally a path")
+	}
+	o.field = args[0]
+	if !isResolvable(o.field) {
+		return fmt.Errorf("can't resolve '%s'; resolvable fields: %v",
+			o.field, target.ResolvableFields())
+	}
+	if len(args) == 1 {
+		o.kustomizationPath = loader.CWD
+	} else {
+		o.kustomizationPath = args[1]
+	}
+	switch o.output {
+	case "", "json", "yaml":
+	default:
+		return fmt.Errorf("--output must be 'json' or 'yaml'")
+	}
+	o.loadRestrictor, err = loader.ValidateFlagLoadRestrictor()
+	return err
+}
+
+func isResolvable(field string) bool {
+	for _, f := range target.ResolvableFields() {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// RunResolve resolves the field through the
+// kustomizations of the build, without building.
+func (o *Options) RunResolve(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader) ([]target.Resolution, error) {
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
+		return nil, err
+	}
+	kt, cleanup, err := render.NewRendererWith(fSys, v, rf, ptf, pl).
+		WithLoadRestrictor(o.loadRestrictor).
+		WithLoaderOptions(o.loader).
+		Target(o.kustomizationPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	return kt.Resolve(o.field)
+}
+
+func (o *Options) emit(out io.Writer, r []target.Resolution) error {
+	var b []byte
+	var err error
+	switch o.output {
+	case "json":
+		b, err = json.MarshalIndent(r, "", "  ")
+		b = append(b, '\n')
+	case "yaml":
+		b, err = yaml.Marshal(r)
+	default:
+		var buf strings.Builder
+		for _, x := range r {
+			fmt.Fprintf(&buf, "%s: %s\n", x.Path, format(x.Value))
+			for _, c := range x.Contributions {
+				fmt.Fprintf(&buf, "  %s: %s\n", c.File, format(c.Value))
+			}
+		}
+		b = []byte(buf.String())
+	}
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
+
+// format writes a value on one line, maps as
+// comma separated key=value pairs.
+func format(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "<unset>"
+	case map[string]string:
+		var pairs []string
+		for k, v := range x {
+			pairs = append(pairs, k+"="+v)
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
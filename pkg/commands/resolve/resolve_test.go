@@ -0,0 +1,149 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolve
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func writeLayers(fSys fs.FileSystem) {
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(`
+namePrefix: app-
+nameSuffix: -v1
+namespace: base
+commonLabels:
+  app: web
+  tier: backend
+resources:
+- service.yaml
+`))
+	fSys.WriteFile("/app/base/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`))
+	fSys.WriteFile("/app/shared/kustomization.yaml", []byte(`
+resources:
+- ../base
+`))
+	fSys.WriteFile("/app/overlay/kustomization.yaml", []byte(`
+namePrefix: prod-
+nameSuffix: -east
+namespace: prod
+commonLabels:
+  tier: frontend
+resources:
+- ../shared
+`))
+}
+
+func resolve(t *testing.T, field, output string) string {
+	fSys := fs.MakeFakeFS()
+	writeLayers(fSys)
+	o := Options{
+		field:             field,
+		kustomizationPath: "/app/overlay",
+		output:            output,
+		loadRestrictor:    loader.RestrictionRootOnly,
+	}
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	r, err := o.RunResolve(
+		validators.MakeFakeValidator(), fSys, rf,
+		transformer.NewFactoryImpl(),
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err = o.emit(&buf, r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestResolve(t *testing.T) {
+	for field, expected := range map[string]string{
+		"namePrefix": `.: prod-
+  kustomization.yaml: prod-
+../shared: prod-
+  kustomization.yaml: prod-
+../base: prod-app-
+  kustomization.yaml: prod-
+  ../base/kustomization.yaml: app-
+`,
+		"nameSuffix": `.: -east
+  kustomization.yaml: -east
+../shared: -east
+  kustomization.yaml: -east
+../base: -v1-east
+  kustomization.yaml: -east
+  ../base/kustomization.yaml: -v1
+`,
+		"namespace": `.: prod
+  kustomization.yaml: prod
+../shared: prod
+  kustomization.yaml: prod
+../base: prod
+  kustomization.yaml: prod
+  ../base/kustomization.yaml: base
+`,
+		"commonLabels": `.: tier=frontend
+  kustomization.yaml: tier=frontend
+../shared: tier=frontend
+  kustomization.yaml: tier=frontend
+../base: app=web,tier=frontend
+  kustomization.yaml: tier=frontend
+  ../base/kustomization.yaml: app=web,tier=backend
+`,
+		"commonAnnotations": `.: <unset>
+../shared: <unset>
+../base: <unset>
+`,
+	} {
+		if actual := resolve(t, field, ""); actual != expected {
+			t.Errorf("%s: expected:\n%s\ngot:\n%s", field, expected, actual)
+		}
+	}
+}
+
+func TestResolveYaml(t *testing.T) {
+	expected := `- contributions:
+  - file: kustomization.yaml
+    value: prod
+  path: .
+  value: prod
+`
+	actual := resolve(t, "namespace", "yaml")
+	if !strings.HasPrefix(actual, expected) {
+		t.Fatalf("expected prefix:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var o Options
+	if err := o.Validate(nil); err == nil {
+		t.Fatalf("expected error without a field")
+	}
+	if err := o.Validate([]string{"namespace"}); err != nil ||
+		o.kustomizationPath != loader.CWD {
+		t.Fatalf("unexpected %v, %s", err, o.kustomizationPath)
+	}
+	err := o.Validate([]string{"images"})
+	if err == nil || !strings.Contains(err.Error(), "resolvable fields") {
+		t.Fatalf("expected unresolvable field error, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+const (
+	paramReorder       = "reorder"
+	paramRedactSecrets = "redact-secrets"
+)
+
+// renderParams are the build flags a request can set,
+// named and valued as the build command's are.
+type renderParams struct {
+	// reorder is true for the legacy order, the default.
+	reorder   bool
+	redaction resource.Redaction
+}
+
+func parseParams(params map[string]string) (renderParams, error) {
+	p := renderParams{reorder: true}
+	for k, v := range params {
+		switch k {
+		case paramReorder:
+			switch v {
+			case "legacy":
+				p.reorder = true
+			case "none":
+				p.reorder = false
+			default:
+				return p, fmt.Errorf(
+					"illegal %s %q; legal values: %v",
+					k, v, []string{"legacy", "none"})
+			}
+		case paramRedactSecrets:
+			switch v {
+			case "", "mask":
+				p.redaction = resource.RedactMask
+			case "hash":
+				p.redaction = resource.RedactHash
+			default:
+				return p, fmt.Errorf(
+					"illegal %s %q; legal values: %v",
+					k, v, []string{"mask", "hash"})
+			}
+		default:
+			return p, fmt.Errorf(
+				"unknown param %q; known params: %v",
+				k, []string{paramReorder, paramRedactSecrets})
+		}
+	}
+	return p, nil
+}
+
+// String is unique to the parameters, to key renders by.
+func (p renderParams) String() string {
+	return fmt.Sprintf("%s=%t&%s=%d",
+		paramReorder, p.reorder, paramRedactSecrets, p.redaction)
+}
@@ -0,0 +1,199 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+// The types and service description below are what
+// protoc would make of render.proto, kept by hand so
+// building kustomize doesn't need protoc.
+
+// RenderRequest asks for the render of a kustomization.
+type RenderRequest struct {
+	Path   string            `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Params map[string]string `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Root   string            `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
+}
+
+func (m *RenderRequest) Reset()         { *m = RenderRequest{} }
+func (m *RenderRequest) String() string { return proto.CompactTextString(m) }
+func (*RenderRequest) ProtoMessage()    {}
+
+// RenderedResource is one resource of a render.
+type RenderedResource struct {
+	ApiVersion string `protobuf:"bytes,1,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	Kind       string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Namespace  string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name       string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Yaml       []byte `protobuf:"bytes,5,opt,name=yaml,proto3" json:"yaml,omitempty"`
+}
+
+func (m *RenderedResource) Reset()         { *m = RenderedResource{} }
+func (m *RenderedResource) String() string { return proto.CompactTextString(m) }
+func (*RenderedResource) ProtoMessage()    {}
+
+// renderedResources splits a render into its resources.
+func renderedResources(m resmap.ResMap) ([]*RenderedResource, error) {
+	var result []*RenderedResource
+	for _, r := range m.Resources() {
+		y, err := r.AsYAML()
+		if err != nil {
+			return nil, err
+		}
+		apiVersion, _ := r.GetString("apiVersion")
+		result = append(result, &RenderedResource{
+			ApiVersion: apiVersion,
+			Kind:       r.GetKind(),
+			Namespace:  r.GetNamespace(),
+			Name:       r.GetName(),
+			Yaml:       y,
+		})
+	}
+	return result, nil
+}
+
+// RendererServer is the server API for the Renderer service.
+type RendererServer interface {
+	Render(*RenderRequest, Renderer_RenderServer) error
+}
+
+// Renderer_RenderServer streams a render to a client.
+type Renderer_RenderServer interface {
+	Send(*RenderedResource) error
+	grpc.ServerStream
+}
+
+type rendererRenderServer struct {
+	grpc.ServerStream
+}
+
+func (x *rendererRenderServer) Send(m *RenderedResource) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func rendererRenderHandler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RenderRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RendererServer).Render(m, &rendererRenderServer{stream})
+}
+
+var rendererServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kustomize.serve.v1.Renderer",
+	HandlerType: (*RendererServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Render",
+			Handler:       rendererRenderHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "render.proto",
+}
+
+// RegisterRendererServer registers srv with s.
+func RegisterRendererServer(s *grpc.Server, srv RendererServer) {
+	s.RegisterService(&rendererServiceDesc, srv)
+}
+
+// RendererClient is the client API for the Renderer service.
+type RendererClient interface {
+	Render(ctx context.Context, in *RenderRequest,
+		opts ...grpc.CallOption) (Renderer_RenderClient, error)
+}
+
+// Renderer_RenderClient streams a render from a server.
+type Renderer_RenderClient interface {
+	Recv() (*RenderedResource, error)
+	grpc.ClientStream
+}
+
+type rendererClient struct {
+	cc *grpc.ClientConn
+}
+
+// NewRendererClient returns a client of the Renderer service on cc.
+func NewRendererClient(cc *grpc.ClientConn) RendererClient {
+	return &rendererClient{cc}
+}
+
+func (c *rendererClient) Render(
+	ctx context.Context, in *RenderRequest,
+	opts ...grpc.CallOption) (Renderer_RenderClient, error) {
+	stream, err := c.cc.NewStream(
+		ctx, &rendererServiceDesc.Streams[0],
+		"/kustomize.serve.v1.Renderer/Render", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rendererRenderClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type rendererRenderClient struct {
+	grpc.ClientStream
+}
+
+func (x *rendererRenderClient) Recv() (*RenderedResource, error) {
+	m := new(RenderedResource)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// grpcRenderer serves the Renderer service from a Server.
+type grpcRenderer struct {
+	s *Server
+}
+
+// Render implements RendererServer.  Whether the render
+// was kept is sent as the x-kustomize-render header, as
+// over HTTP.
+func (g grpcRenderer) Render(
+	in *RenderRequest, stream Renderer_RenderServer) error {
+	r, kept, err := g.s.rendered(in.Root, in.Path, in.Params)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	how := "fresh"
+	if kept {
+		how = "kept"
+	}
+	err = stream.SendHeader(metadata.Pairs("x-kustomize-render", how))
+	if err != nil {
+		return err
+	}
+	for _, res := range r.resources {
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewGRPCServer returns a gRPC server offering
+// the Renderer service from s.
+func NewGRPCServer(s *Server) *grpc.Server {
+	gs := grpc.NewServer()
+	RegisterRendererServer(gs, grpcRenderer{s})
+	return gs
+}
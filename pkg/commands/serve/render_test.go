@@ -0,0 +1,146 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestGRPCRender(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+namespace: prod
+resources:
+- config.yaml
+- secret.yaml
+`))
+	fSys.WriteFile("/app/config.yaml",
+		[]byte(strings.Replace(configMap, "%s", "red", 1)))
+	fSys.WriteFile("/app/secret.yaml", []byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: password
+data:
+  password: c2VjcmV0
+`))
+	s := makeServer(t, fSys)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := NewGRPCServer(s)
+	defer gs.Stop()
+	go gs.Serve(l)
+	cc, err := grpc.Dial(l.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	client := NewRendererClient(cc)
+
+	render := func(in *RenderRequest) (
+		[]*RenderedResource, metadata.MD, error) {
+		var header metadata.MD
+		stream, err := client.Render(
+			context.Background(), in, grpc.Header(&header))
+		if err != nil {
+			return nil, nil, err
+		}
+		var result []*RenderedResource
+		for {
+			r, err := stream.Recv()
+			if err == io.EOF {
+				return result, header, nil
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			result = append(result, r)
+		}
+	}
+
+	result, header, err := render(&RenderRequest{
+		Path:   "/",
+		Params: map[string]string{"redact-secrets": "mask"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 resources, got %v", result)
+	}
+	cm, secret := result[0], result[1]
+	if cm.ApiVersion != "v1" || cm.Kind != "ConfigMap" ||
+		cm.Namespace != "prod" || cm.Name != "config" ||
+		!strings.Contains(string(cm.Yaml), "color: red") {
+		t.Fatalf("unexpected ConfigMap %v", cm)
+	}
+	if secret.Kind != "Secret" ||
+		!strings.Contains(string(secret.Yaml), "password: <redacted>") {
+		t.Fatalf("expected redacted Secret, got %v", secret)
+	}
+	if h := header.Get("x-kustomize-render"); len(h) != 1 || h[0] != "fresh" {
+		t.Fatalf("expected a fresh render, got %v", h)
+	}
+
+	// Kept, but only for the same params.
+	_, header, err = render(&RenderRequest{
+		Path:   "/",
+		Params: map[string]string{"redact-secrets": "mask"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h := header.Get("x-kustomize-render"); len(h) != 1 || h[0] != "kept" {
+		t.Fatalf("expected a kept render, got %v", h)
+	}
+	result, header, err = render(&RenderRequest{Path: "/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h := header.Get("x-kustomize-render"); len(h) != 1 || h[0] != "fresh" {
+		t.Fatalf("expected a fresh render, got %v", h)
+	}
+	if !strings.Contains(string(result[1].Yaml), "password: c2VjcmV0") {
+		t.Fatalf("expected unredacted Secret, got %v", result[1])
+	}
+
+	_, _, err = render(&RenderRequest{
+		Path:   "/",
+		Params: map[string]string{"color": "blue"},
+	})
+	if status.Code(err) != codes.InvalidArgument ||
+		!strings.Contains(err.Error(), "unknown param") {
+		t.Fatalf("expected unknown param error, got %v", err)
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	p, err := parseParams(nil)
+	if err != nil || !p.reorder || p.redaction != resource.RedactNone {
+		t.Fatalf("unexpected defaults %v, %v", p, err)
+	}
+	p, err = parseParams(map[string]string{
+		"reorder": "none", "redact-secrets": "hash"})
+	if err != nil || p.reorder || p.redaction != resource.RedactHash {
+		t.Fatalf("unexpected params %v, %v", p, err)
+	}
+	_, err = parseParams(map[string]string{"reorder": "random"})
+	if err == nil || !strings.Contains(err.Error(), "legal values") {
+		t.Fatalf("expected illegal value error, got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package serve renders kustomizations on request over
+// HTTP and gRPC, keeping what it can warm between renders.
+package serve
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/openapi"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// Options contain the options for running serve.
+type Options struct {
+	root           string
+	address        string
+	grpcAddress    string
+	kubeVersion    string
+	pollInterval   time.Duration
+	cloneTTL       time.Duration
+	virtualRoots   bool
+	loadRestrictor loader.LoadRestrictorFunc
+	loader         loader.Options
+}
+
+var examples = `
+To render the kustomizations under someDir on request, run
+
+  kustomize serve someDir --address localhost:8080
+
+then, e.g. for someDir/overlays/prod,
+
+  curl 'http://localhost:8080/render?path=overlays/prod'
+
+Build flags go in the query, e.g.
+
+  curl 'http://localhost:8080/render?path=overlays/prod&reorder=none'
+
+takes --reorder none; redact-secrets is the other.
+
+To keep tenants sharing a server apart, run
+
+  kustomize serve someDir --virtual-roots
+
+so each request names, with root, a directory under
+someDir that its render sees as the whole file system,
+e.g. for someDir/teamA/overlays/prod,
+
+  curl 'http://localhost:8080/render?root=teamA&path=overlays/prod'
+
+To serve the Renderer service of render.proto too, which
+streams the resources of each render, run
+
+  kustomize serve someDir --grpc-address localhost:8081
+
+Renders are kept, and served again until a file they
+read changes; they're redone in the background when
+one does.  Clones of remote bases are kept for
+--clone-ttl, and the --kube-version schema for good.
+`
+
+// NewCmdServe creates a new serve command.
+func NewCmdServe(
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, rf *resmap.Factory,
+	ptf resmap.PatchFactory) *cobra.Command {
+	var o Options
+
+	pluginConfig := plugins.DefaultPluginConfig()
+	pl := plugins.NewLoader(pluginConfig, rf)
+
+	cmd := &cobra.Command{
+		Use:          "serve {dir}",
+		Short:        "Render the kustomizations in a directory over HTTP",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args, fSys)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			return o.RunServe(out, v, fSys, rf, ptf, pl)
+		},
+	}
+	cmd.Flags().StringVar(
+		&o.address,
+		"address", "localhost:8080",
+		"Address to listen on.")
+	cmd.Flags().StringVar(
+		&o.grpcAddress,
+		"grpc-address", "",
+		"Address to serve the gRPC Renderer service on, if any.")
+	cmd.Flags().StringVar(
+		&o.kubeVersion,
+		"kube-version", "",
+		"Kubernetes version whose API schema guides strategic merge "+
+			"patches, loaded once for all renders.")
+	cmd.Flags().DurationVar(
+		&o.pollInterval,
+		"poll-interval", 2*time.Second,
+		"How often to check the files of kept renders for changes.")
+	cmd.Flags().DurationVar(
+		&o.cloneTTL,
+		"clone-ttl", 10*time.Minute,
+		"How long to keep clones of remote bases, and the "+
+			"renders using them.")
+	cmd.Flags().BoolVar(
+		&o.virtualRoots,
+		"virtual-roots", false,
+		"Require each request to name a directory to render in, "+
+			"that can't be read outside of.  Remote bases aren't "+
+			"read under virtual roots.")
+	loader.AddFlagLoadRestrictor(cmd.Flags())
+	o.loader.AddFlags(cmd.Flags())
+	plugins.AddFlagEnablePlugins(
+		cmd.Flags(), &pluginConfig.Enabled)
+	return cmd
+}
+
+// Validate validates serve command.
+func (o *Options) Validate(args []string, fSys fs.FileSystem) (err error) {
+	if len(args) > 1 {
+		return errors.New("specify one directory to serve")
+	}
+	if len(args) == 0 {
+		o.root = loader.CWD
+	} else {
+		o.root = args[0]
+	}
+	if !fSys.IsDir(o.root) {
+		return fmt.Errorf("'%s' must be a local directory", o.root)
+	}
+	if o.pollInterval <= 0 || o.cloneTTL <= 0 {
+		return errors.New(
+			"--poll-interval and --clone-ttl must be positive")
+	}
+	o.loadRestrictor, err = loader.ValidateFlagLoadRestrictor()
+	return err
+}
+
+// RunServe serves renders until a listener fails.
+func (o *Options) RunServe(
+	out io.Writer, v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader) error {
+	if o.kubeVersion != "" {
+		s, err := openapi.ForVersion(fSys, o.kubeVersion)
+		if err != nil {
+			return err
+		}
+		rf = rf.WithOptions(resource.Options{Schema: s})
+		pl = pl.WithFactory(rf)
+	}
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
+		return err
+	}
+	cloner := git.NewCachingCloner(o.loader.Git.GitCloner(), o.cloneTTL)
+	defer cloner.Cleanup()
+	s, err := NewServer(o, v, fSys, rf, ptf, pl, cloner)
+	if err != nil {
+		return err
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.Watch(o.pollInterval, stop)
+	errs := make(chan error, 2)
+	if o.grpcAddress != "" {
+		l, err := net.Listen("tcp", o.grpcAddress)
+		if err != nil {
+			return err
+		}
+		gs := NewGRPCServer(s)
+		defer gs.Stop()
+		go func() { errs <- gs.Serve(l) }()
+		fmt.Fprintf(out, "serving %s on grpc://%s\n", s.root, o.grpcAddress)
+	}
+	go func() { errs <- http.ListenAndServe(o.address, s) }()
+	fmt.Fprintf(out, "serving %s on http://%s\n", s.root, o.address)
+	return <-errs
+}
@@ -0,0 +1,322 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/render"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/plugin/builtin"
+)
+
+// Server renders the kustomizations under a root
+// directory on request.  It keeps each render, with the
+// hashes of the files it read, and serves it again while
+// they're unchanged.  Renders run at once; each has
+// its own loader, and factories if its params need them.
+//
+// A request may name a virtual root, a directory under
+// the root that the render sees as the whole file
+// system, so tenants sharing a Server can't read each
+// other's files.
+type Server struct {
+	root fs.ConfirmedDir
+	// virtual roots are required
+	virtualRoots bool
+	lr           loader.LoadRestrictorFunc
+	ttl          time.Duration
+	v            ifc.Validator
+	fSys         fs.FileSystem
+	rf           *resmap.Factory
+	ptf          resmap.PatchFactory
+	pl           *plugins.Loader
+	opts         loader.Options
+	// the HMAC key of redact-secrets=hash, the
+	// same for all renders of the Server
+	redactionKey []byte
+	mux          *http.ServeMux
+
+	// mu guards renders, not the renders themselves.
+	mu      sync.Mutex
+	renders map[string]*rendering
+}
+
+// rendering is a kept render of a kustomization.
+type rendering struct {
+	// the file system the render saw, and its
+	// virtual root in the Server's, if any
+	fSys      fs.FileSystem
+	root      string
+	dir       string
+	params    renderParams
+	out       []byte
+	resources []*RenderedResource
+	// hashes of the local files read
+	files map[string][sha256.Size]byte
+	// remote bases were read
+	remote bool
+	at     time.Time
+}
+
+// NewServer returns a Server of the kustomizations
+// under o's root, loading as o's loader options say,
+// but getting remote bases with cloner.
+func NewServer(
+	o *Options, v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader, cloner *git.CachingCloner) (*Server, error) {
+	root, _, err := fSys.CleanedAbs(o.root)
+	if err != nil {
+		return nil, err
+	}
+	opts := o.loader
+	opts.Cloner = cloner.Clone
+	key, err := resource.NewRedactionKey()
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		root:         root,
+		virtualRoots: o.virtualRoots,
+		lr:           o.loadRestrictor,
+		ttl:          o.cloneTTL,
+		v:            v,
+		fSys:         fSys,
+		rf:           rf,
+		ptf:          ptf,
+		pl:           pl,
+		opts:         opts,
+		redactionKey: key,
+		mux:          http.NewServeMux(),
+		renders:      make(map[string]*rendering),
+	}
+	s.mux.HandleFunc("/render", s.handleRender)
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleRender(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "use GET", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	params := make(map[string]string)
+	for k := range query {
+		if k != "root" && k != "path" {
+			params[k] = query.Get(k)
+		}
+	}
+	out, kept, err := s.Render(
+		query.Get("root"), query.Get("path"), params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	if kept {
+		w.Header().Set("X-Kustomize-Render", "kept")
+	} else {
+		w.Header().Set("X-Kustomize-Render", "fresh")
+	}
+	w.Write(out)
+}
+
+// dir returns the directory of the kustomization at
+// path, relative to the root, which it can't escape.
+func (s *Server) dir(path string) string {
+	return s.root.Join(filepath.Clean(string(filepath.Separator) + path))
+}
+
+// mount returns the file system a render under the
+// virtual root root, if any, sees, and the directory of
+// the kustomization at path, relative to root, in it.
+func (s *Server) mount(root, path string) (fs.FileSystem, string, error) {
+	if root == "" {
+		if s.virtualRoots {
+			return nil, "", errors.New("specify a virtual root")
+		}
+		return s.fSys, s.dir(path), nil
+	}
+	d, f, err := s.fSys.CleanedAbs(s.dir(root))
+	if err != nil || f != "" || !d.HasPrefix(s.root) {
+		return nil, "", fmt.Errorf("root '%s' isn't a directory", root)
+	}
+	return fs.MakeChrootFS(s.fSys, d),
+		filepath.Clean(string(filepath.Separator) + path), nil
+}
+
+// Render returns the output of the kustomization at path,
+// relative to root, a virtual root under the Server's root
+// or "" for none, built with params, and whether it was
+// kept from an earlier render.
+func (s *Server) Render(
+	root, path string, params map[string]string) ([]byte, bool, error) {
+	r, kept, err := s.rendered(root, path, params)
+	if err != nil {
+		return nil, false, err
+	}
+	return r.out, kept, nil
+}
+
+func (s *Server) rendered(
+	root, path string, params map[string]string) (*rendering, bool, error) {
+	p, err := parseParams(params)
+	if err != nil {
+		return nil, false, err
+	}
+	fSys, dir, err := s.mount(root, path)
+	if err != nil {
+		return nil, false, err
+	}
+	key := root + ":" + dir + "?" + p.String()
+	s.mu.Lock()
+	r, ok := s.renders[key]
+	s.mu.Unlock()
+	if ok && s.current(r) {
+		return r, true, nil
+	}
+	r, err = s.render(fSys, root, dir, p)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		delete(s.renders, key)
+		return nil, false, err
+	}
+	s.renders[key] = r
+	return r, false, nil
+}
+
+// Watch redoes the kept renders whose files have
+// changed every interval, until stop is closed, so
+// they're ready for the next request.
+func (s *Server) Watch(interval time.Duration, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			s.refresh()
+		}
+	}
+}
+
+func (s *Server) refresh() {
+	s.mu.Lock()
+	kept := make(map[string]*rendering, len(s.renders))
+	for key, r := range s.renders {
+		kept[key] = r
+	}
+	s.mu.Unlock()
+	for key, r := range kept {
+		if s.current(r) {
+			continue
+		}
+		fresh, err := s.render(r.fSys, r.root, r.dir, r.params)
+		s.mu.Lock()
+		if err != nil {
+			log.Printf("rendering %s: %v", key, err)
+			delete(s.renders, key)
+		} else {
+			s.renders[key] = fresh
+		}
+		s.mu.Unlock()
+	}
+}
+
+// current is true if r's files are unchanged, and
+// the clones of any remote bases it read are too.
+func (s *Server) current(r *rendering) bool {
+	if r.remote && time.Since(r.at) >= s.ttl {
+		return false
+	}
+	for path, sum := range r.files {
+		content, err := r.fSys.ReadFile(path)
+		if err != nil || sha256.Sum256(content) != sum {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) render(
+	fSys fs.FileSystem, root, dir string,
+	p renderParams) (*rendering, error) {
+	rec := loader.NewDepRecorder()
+	opts := s.opts
+	if root != "" {
+		// Clones are made outside any virtual root.
+		opts.Cloner = func(*git.RepoSpec) error {
+			return errors.New("remote bases can't be read under a virtual root")
+		}
+	}
+	rdr := render.NewRendererWith(fSys, s.v, s.rf, s.ptf, s.pl).
+		WithLoadRestrictor(s.lr).
+		WithLoaderOptions(opts).
+		WithTracer(rec)
+	if p.redaction != resource.RedactNone {
+		ro := s.rf.RF().Options()
+		ro.Redaction = p.redaction
+		ro.RedactionKey = s.redactionKey
+		rdr = rdr.WithResourceOptions(ro)
+	}
+	m, err := rdr.Render(dir, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range m.Resources() {
+		r.Redact(p.redaction, s.redactionKey)
+	}
+	if p.reorder {
+		builtin.NewLegacyOrderTransformerPlugin().Transform(m)
+	}
+	out, err := m.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+	resources, err := renderedResources(m)
+	if err != nil {
+		return nil, err
+	}
+	deps := rec.Dependencies()
+	r := &rendering{
+		fSys:      fSys,
+		root:      root,
+		dir:       dir,
+		params:    p,
+		out:       out,
+		resources: resources,
+		files:     make(map[string][sha256.Size]byte),
+		remote:    len(deps.Remotes) > 0,
+		at:        time.Now(),
+	}
+	for _, path := range deps.Files {
+		content, err := fSys.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		r.files[path] = sha256.Sum256(content)
+	}
+	return r, nil
+}
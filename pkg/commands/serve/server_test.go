@@ -0,0 +1,228 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+const configMap = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  color: %s
+`
+
+func makeServer(t *testing.T, fSys fs.FileSystem) *Server {
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	o := &Options{
+		root:           "/app",
+		loadRestrictor: loader.RestrictionRootOnly,
+		cloneTTL:       time.Minute,
+	}
+	s, err := NewServer(
+		o, validator.NewKustValidator(), fSys,
+		rf, transformer.NewFactoryImpl(),
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf),
+		git.NewCachingCloner(git.ClonerUsingGitExec, time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return s
+}
+
+func get(s *Server, url string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	return w
+}
+
+func TestServerRender(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(`
+resources:
+- config.yaml
+`))
+	fSys.WriteFile("/app/base/config.yaml",
+		[]byte(strings.Replace(configMap, "%s", "red", 1)))
+	fSys.WriteFile("/app/overlays/prod/kustomization.yaml", []byte(`
+namePrefix: prod-
+resources:
+- ../../base
+`))
+	s := makeServer(t, fSys)
+
+	w := get(s, "/render?path=overlays/prod")
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+	expected := `apiVersion: v1
+data:
+  color: red
+kind: ConfigMap
+metadata:
+  name: prod-config
+`
+	if w.Body.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, w.Body.String())
+	}
+	if h := w.Header().Get("X-Kustomize-Render"); h != "fresh" {
+		t.Fatalf("expected a fresh render, got %q", h)
+	}
+	w = get(s, "/render?path=overlays/prod")
+	if h := w.Header().Get("X-Kustomize-Render"); h != "kept" {
+		t.Fatalf("expected a kept render, got %q", h)
+	}
+
+	// A change to a base is rendered in the background.
+	fSys.WriteFile("/app/base/config.yaml",
+		[]byte(strings.Replace(configMap, "%s", "blue", 1)))
+	s.refresh()
+	w = get(s, "/render?path=overlays/prod")
+	if h := w.Header().Get("X-Kustomize-Render"); h != "kept" {
+		t.Fatalf("expected a kept render, got %q", h)
+	}
+	if !strings.Contains(w.Body.String(), "color: blue") {
+		t.Fatalf("expected changed render, got:\n%s", w.Body.String())
+	}
+
+	// And on request.
+	fSys.WriteFile("/app/overlays/prod/kustomization.yaml", []byte(`
+namePrefix: production-
+resources:
+- ../../base
+`))
+	w = get(s, "/render?path=overlays/prod")
+	if h := w.Header().Get("X-Kustomize-Render"); h != "fresh" {
+		t.Fatalf("expected a fresh render, got %q", h)
+	}
+	if !strings.Contains(w.Body.String(), "name: production-config") {
+		t.Fatalf("expected changed render, got:\n%s", w.Body.String())
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/secret/kustomization.yaml", []byte(`
+resources:
+- secret.yaml
+`))
+	fSys.Mkdir("/app")
+	s := makeServer(t, fSys)
+
+	w := get(s, "/render?path=../secret")
+	if w.Code != http.StatusUnprocessableEntity ||
+		!strings.Contains(w.Body.String(), "/app/secret") {
+		t.Fatalf("expected path kept under root, got %d: %s",
+			w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(
+		http.MethodPost, "/render?path=.", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+}
+
+func TestServerConcurrentRenders(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	colors := []string{"red", "green", "blue", "black"}
+	for _, c := range colors {
+		fSys.WriteFile("/app/"+c+"/kustomization.yaml", []byte(`
+resources:
+- config.yaml
+`))
+		fSys.WriteFile("/app/"+c+"/config.yaml",
+			[]byte(strings.Replace(configMap, "%s", c, 1)))
+	}
+	s := makeServer(t, fSys)
+
+	// Each render gets its own output,
+	// whatever renders at once.
+	var wg sync.WaitGroup
+	errs := make(chan string, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(c string) {
+			defer wg.Done()
+			out, _, err := s.Render("", c, nil)
+			if err != nil {
+				errs <- err.Error()
+			} else if !strings.Contains(string(out), "color: "+c) {
+				errs <- "expected color " + c + " in\n" + string(out)
+			}
+		}(colors[i%len(colors)])
+	}
+	wg.Wait()
+	close(errs)
+	for e := range errs {
+		t.Error(e)
+	}
+}
+
+func TestServerVirtualRoots(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/teamA/base/kustomization.yaml", []byte(`
+resources:
+- config.yaml
+`))
+	fSys.WriteFile("/app/teamA/base/config.yaml",
+		[]byte(strings.Replace(configMap, "%s", "red", 1)))
+	fSys.WriteFile("/app/teamA/prod/kustomization.yaml", []byte(`
+namePrefix: prod-
+resources:
+- ../base
+`))
+	fSys.WriteFile("/app/teamB/prod/kustomization.yaml", []byte(`
+resources:
+- ../../teamA/base
+`))
+	s := makeServer(t, fSys)
+	s.virtualRoots = true
+
+	w := get(s, "/render?root=teamA&path=prod")
+	if w.Code != http.StatusOK ||
+		!strings.Contains(w.Body.String(), "name: prod-config") {
+		t.Fatalf("unexpected render %d: %s", w.Code, w.Body.String())
+	}
+	w = get(s, "/render?root=teamA&path=prod")
+	if h := w.Header().Get("X-Kustomize-Render"); h != "kept" {
+		t.Fatalf("expected a kept render, got %q", h)
+	}
+	// teamB's render can't see teamA's files.
+	w = get(s, "/render?root=teamB&path=prod")
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected teamA's files hidden, got %d: %s",
+			w.Code, w.Body.String())
+	}
+	w = get(s, "/render?root=../app&path=teamB/prod")
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected root kept under the root, got %d: %s",
+			w.Code, w.Body.String())
+	}
+	w = get(s, "/render?path=teamA/prod")
+	if w.Code != http.StatusUnprocessableEntity ||
+		!strings.Contains(w.Body.String(), "specify a virtual root") {
+		t.Fatalf("expected a virtual root required, got %d: %s",
+			w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/plugin/builtin"
+)
+
+// snapshotSuffix ends the default names of snapshots,
+// in place of TestFileSuffix.
+const snapshotSuffix = ".snapshot.yaml"
+
+// Snapshot is a golden file holding the output of the
+// build, so that any change to it fails the test until
+// the snapshot is updated, and so reviewed.
+type Snapshot struct {
+	// File is the golden file, relative to the test
+	// file's directory.  By default it's named after the
+	// test file, e.g. prod.snapshot.yaml for prod_test.yaml.
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+
+	// Sort puts the resources in the order of
+	// 'kustomize build', by kind then name, rather than
+	// that of the kustomization.
+	Sort bool `json:"sort,omitempty" yaml:"sort,omitempty"`
+
+	// StripMetadata lists fields of the resources'
+	// metadata left out of the snapshot, e.g. annotations
+	// that change with every build.
+	StripMetadata []string `json:"stripMetadata,omitempty" yaml:"stripMetadata,omitempty"`
+}
+
+// path returns the path of the snapshot of the
+// test file at testPath.
+func (s *Snapshot) path(testPath string) string {
+	if s.File != "" {
+		return filepath.Join(filepath.Dir(testPath), s.File)
+	}
+	return strings.TrimSuffix(testPath, TestFileSuffix) + snapshotSuffix
+}
+
+// render returns the normalized YAML of m,
+// leaving m as it was.
+func (s *Snapshot) render(m resmap.ResMap) ([]byte, error) {
+	m = m.DeepCopy()
+	if s.Sort {
+		err := builtin.NewLegacyOrderTransformerPlugin().Transform(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range m.Resources() {
+		obj := r.Map()
+		if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+			for _, f := range s.StripMetadata {
+				delete(meta, f)
+			}
+		}
+		r.SetMap(obj)
+	}
+	return m.AsYaml()
+}
+
+// check compares the normalized output of the build
+// with the snapshot at path, or with update, writes it
+// there.  It returns why they differ, or "" if they
+// don't.
+func (s *Snapshot) check(
+	fSys fs.FileSystem, path string,
+	m resmap.ResMap, update bool) (string, error) {
+	got, err := s.render(m)
+	if err != nil {
+		return "", err
+	}
+	if update {
+		return "", fSys.WriteFile(path, got)
+	}
+	if !fSys.Exists(path) {
+		return fmt.Sprintf(
+			"no snapshot %s; run with --%s to write it",
+			path, flagUpdateSnapshots), nil
+	}
+	expected, err := fSys.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if string(expected) == string(got) {
+		return "", nil
+	}
+	return fmt.Sprintf(
+		"output differs from the snapshot; run with --%s "+
+			"to accept it\n%s", flagUpdateSnapshots,
+		diffLines(string(expected), string(got))), nil
+}
+
+// diffLines returns the lines of a missing from b,
+// prefixed '-', and those of b missing from a,
+// prefixed '+', in the order of a longest common
+// subsequence of the two.
+func diffLines(a, b string) string {
+	x := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	y := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+	// lcs[i][j] is the length of a longest common
+	// subsequence of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			switch {
+			case x[i] == y[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			i++
+			j++
+		case j == len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&out, "  -%s\n", x[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "  +%s\n", y[j])
+			j++
+		}
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
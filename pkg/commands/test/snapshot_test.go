@@ -0,0 +1,96 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+func TestSnapshot(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeOverlay(fSys)
+	fSys.WriteFile("/app/prod/prod_test.yaml", []byte(`
+snapshot:
+  sort: true
+  stripMetadata: [namespace]
+`))
+	results := runTest(t, fSys, "/app/prod")
+	if len(results) != 1 || results[0].Failure !=
+		"no snapshot /app/prod/prod.snapshot.yaml; "+
+			"run with --update-snapshots to write it" {
+		t.Fatalf("unexpected results %v", results)
+	}
+
+	results = runOptions(t, fSys, Options{
+		paths: []string{"/app/prod"}, updateSnapshots: true})
+	if len(results) != 1 ||
+		results[0].Assertion != "updated snapshot /app/prod/prod.snapshot.yaml" ||
+		results[0].Failure != "" {
+		t.Fatalf("unexpected results %v", results)
+	}
+	content, err := fSys.ReadFile("/app/prod/prod.snapshot.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Sorted, Service before Deployment, and
+	// without namespaces.
+	expected := `apiVersion: v1
+kind: Service
+metadata:
+  name: my-app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - image: my-app:v1
+        name: app
+`
+	if string(content) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, content)
+	}
+	results = runTest(t, fSys, "/app/prod")
+	if len(results) != 1 || results[0].Failure != "" {
+		t.Fatalf("unexpected results %v", results)
+	}
+
+	fSys.WriteFile("/app/prod/replicas.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app
+spec:
+  replicas: 5
+`))
+	results = runTest(t, fSys, "/app/prod")
+	if len(results) != 1 || results[0].Failure !=
+		`output differs from the snapshot; run with --update-snapshots to accept it
+  -  replicas: 3
+  +  replicas: 5` {
+		t.Fatalf("unexpected results %v", results)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	for _, tc := range []struct {
+		a, b, expected string
+	}{
+		{"a\nb\nc\n", "a\nb\nc\n", ""},
+		{"a\nb\nc\n", "a\nc\n", "  -b"},
+		{"a\nc\n", "a\nb\nc\nd\n", "  +b\n  +d"},
+		{"a\nb\n", "b\nx\n", "  -a\n  +x"},
+	} {
+		if actual := diffLines(tc.a, tc.b); actual != tc.expected {
+			t.Errorf("diffLines(%q, %q): expected %q, got %q",
+				tc.a, tc.b, tc.expected, actual)
+		}
+	}
+}
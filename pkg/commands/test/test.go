@@ -0,0 +1,275 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package test runs the assertions of test files
+// on the output of the kustomizations they name.
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/render"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/yaml"
+)
+
+// Options contain the options for running test.
+type Options struct {
+	paths           []string
+	updateSnapshots bool
+	loadRestrictor  loader.LoadRestrictorFunc
+	loader          loader.Options
+}
+
+const flagUpdateSnapshots = "update-snapshots"
+
+var examples = `
+To check that the prod overlay runs three replicas of
+my-app, write someDir/overlays/prod/prod_test.yaml,
+
+  target: .
+  assertions:
+  - resource: Deployment/my-app
+    field: spec.replicas
+    equals: 3
+  - kind: Service
+    count: 1
+
+then run
+
+  kustomize test someDir/overlays/prod
+
+to run the assertions of every ` + TestFileSuffix + ` file
+in the directory.  A test can also apply overrides to the
+kustomization, as those of its clusters field, e.g.
+
+  overrides:
+    images:
+    - name: my-app
+      newTag: v2
+
+Test files or directories, e.g. from a shell glob, can be
+given together:
+
+  kustomize test someDir/overlays/*
+
+To also compare the whole output with a golden file, by
+default prod.snapshot.yaml, add to the test file
+
+  snapshot:
+    sort: true
+    stripMetadata: [annotations]
+
+and write, or after a reviewed change rewrite, the
+golden file by running
+
+  kustomize test someDir/overlays/prod --update-snapshots
+`
+
+// NewCmdTest creates a new test command.
+func NewCmdTest(
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, rf *resmap.Factory,
+	ptf resmap.PatchFactory) *cobra.Command {
+	var o Options
+
+	pluginConfig := plugins.DefaultPluginConfig()
+	pl := plugins.NewLoader(pluginConfig, rf)
+
+	cmd := &cobra.Command{
+		Use:          "test {path}...",
+		Short:        "Check the output of kustomizations against test files",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			results, err := o.RunTest(v, fSys, rf, ptf, pl)
+			if err != nil {
+				return err
+			}
+			return report(out, results)
+		},
+	}
+	cmd.Flags().BoolVar(
+		&o.updateSnapshots,
+		flagUpdateSnapshots, false,
+		"Write the output of each test with a snapshot to its "+
+			"golden file, rather than compare them.")
+	loader.AddFlagLoadRestrictor(cmd.Flags())
+	o.loader.AddFlags(cmd.Flags())
+	plugins.AddFlagEnablePlugins(
+		cmd.Flags(), &pluginConfig.Enabled)
+	return cmd
+}
+
+// Validate validates test command.
+func (o *Options) Validate(args []string) (err error) {
+	o.paths = args
+	if len(o.paths) == 0 {
+		o.paths = []string{loader.CWD}
+	}
+	o.loadRestrictor, err = loader.ValidateFlagLoadRestrictor()
+	return err
+}
+
+// Result is the outcome of an assertion of a test file.
+type Result struct {
+	File      string
+	Assertion string
+	// Failure says why the assertion failed,
+	// and is empty if it held.
+	Failure string
+}
+
+// RunTest runs the test files at, or directly in,
+// the paths.
+func (o *Options) RunTest(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader) ([]Result, error) {
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
+		return nil, err
+	}
+	var results []Result
+	for _, path := range o.paths {
+		files, err := testFiles(fSys, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			results = append(results, o.runFile(v, fSys, rf, ptf, pl, f)...)
+		}
+	}
+	return results, nil
+}
+
+// testFiles returns path, or the test files in it.
+func testFiles(fSys fs.FileSystem, path string) ([]string, error) {
+	if !fSys.IsDir(path) {
+		if !fSys.Exists(path) {
+			return nil, kusterr.WithClass(kusterr.ClassUsage,
+				fmt.Errorf("no test file or directory %s", path))
+		}
+		return []string{path}, nil
+	}
+	return fSys.Glob(filepath.Join(path, "*"+TestFileSuffix))
+}
+
+func (o *Options) runFile(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader, path string) []Result {
+	failed := func(err error) []Result {
+		return []Result{{File: path, Assertion: "build", Failure: err.Error()}}
+	}
+	t, err := readTestFile(fSys, path)
+	if err != nil {
+		return failed(err)
+	}
+	m, err := o.build(v, fSys, rf, ptf, pl, t, path)
+	if err != nil {
+		return failed(err)
+	}
+	var results []Result
+	for _, a := range t.Assertions {
+		results = append(results, Result{
+			File:      path,
+			Assertion: a.String(),
+			Failure:   a.check(m),
+		})
+	}
+	if t.Snapshot != nil {
+		results = append(results, o.checkSnapshot(fSys, t.Snapshot, path, m))
+	}
+	return results
+}
+
+func (o *Options) checkSnapshot(
+	fSys fs.FileSystem, s *Snapshot,
+	path string, m resmap.ResMap) Result {
+	r := Result{File: path, Assertion: "snapshot " + s.path(path)}
+	if o.updateSnapshots {
+		r.Assertion = "updated " + r.Assertion
+	}
+	failure, err := s.check(fSys, s.path(path), m, o.updateSnapshots)
+	if err != nil {
+		failure = err.Error()
+	}
+	r.Failure = failure
+	return r
+}
+
+func readTestFile(fSys fs.FileSystem, path string) (*TestFile, error) {
+	content, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	j, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, err
+	}
+	var t TestFile
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&t); err != nil {
+		return nil, err
+	}
+	for _, a := range t.Assertions {
+		if err := a.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+// build builds the target of the test file at path.
+func (o *Options) build(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader, t *TestFile, path string) (resmap.ResMap, error) {
+	kt, cleanup, err := render.NewRendererWith(fSys, v, rf, ptf, pl).
+		WithLoadRestrictor(o.loadRestrictor).
+		WithLoaderOptions(o.loader).
+		Target(t.target(path))
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	if t.Overrides != nil {
+		kt = kt.WithOverrides(*t.Overrides)
+	}
+	return kt.MakeCustomizedResMap()
+}
+
+// report writes a line per result, and returns
+// an error if any failed.
+func report(out io.Writer, results []Result) error {
+	failures := 0
+	for _, r := range results {
+		if r.Failure == "" {
+			fmt.Fprintf(out, "ok   %s: %s\n", r.File, r.Assertion)
+			continue
+		}
+		failures++
+		fmt.Fprintf(out, "FAIL %s: %s: %s\n", r.File, r.Assertion, r.Failure)
+	}
+	if failures > 0 {
+		return kusterr.WithClass(kusterr.ClassValidation, messages.Errorf(
+			messages.AssertionsFailed, failures, len(results)))
+	}
+	return nil
+}
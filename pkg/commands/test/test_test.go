@@ -0,0 +1,138 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func writeOverlay(fSys fs.FileSystem) {
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(`
+resources:
+- deployment.yaml
+- service.yaml
+`))
+	fSys.WriteFile("/app/base/deployment.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: my-app:v1
+`))
+	fSys.WriteFile("/app/base/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-app
+`))
+	fSys.WriteFile("/app/prod/kustomization.yaml", []byte(`
+namespace: prod
+resources:
+- ../base
+patchesStrategicMerge:
+- replicas.yaml
+`))
+	fSys.WriteFile("/app/prod/replicas.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app
+spec:
+  replicas: 3
+`))
+}
+
+func runTest(t *testing.T, fSys fs.FileSystem, paths ...string) []Result {
+	return runOptions(t, fSys, Options{paths: paths})
+}
+
+func runOptions(t *testing.T, fSys fs.FileSystem, o Options) []Result {
+	o.loadRestrictor = loader.RestrictionRootOnly
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	results, err := o.RunTest(
+		validators.MakeFakeValidator(), fSys, rf, pf,
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return results
+}
+
+func TestRunTest(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeOverlay(fSys)
+	fSys.WriteFile("/app/prod/prod_test.yaml", []byte(`
+assertions:
+- resource: Deployment/my-app
+- resource: Deployment/my-app
+  field: spec.replicas
+  equals: 3
+- name: namespaced
+  resource: Service/my-app
+  field: metadata.namespace
+  equals: prod
+- kind: Service
+  count: 2
+- resource: ConfigMap/missing
+`))
+	fSys.WriteFile("/app/prod/images_test.yaml", []byte(`
+target: .
+overrides:
+  images:
+  - name: my-app
+    newTag: v2
+assertions:
+- resource: Deployment/my-app
+  field: spec.template.spec.containers[0].image
+  equals: my-app:v2
+`))
+	var out bytes.Buffer
+	err := report(&out, runTest(t, fSys, "/app/prod"))
+	if err == nil || err.Error() != "2 of 6 assertions failed" {
+		t.Fatalf("unexpected error %v\n%s", err, out.String())
+	}
+	expected := `ok   /app/prod/images_test.yaml: Deployment/my-app spec.template.spec.containers[0].image
+ok   /app/prod/prod_test.yaml: Deployment/my-app
+ok   /app/prod/prod_test.yaml: Deployment/my-app spec.replicas
+ok   /app/prod/prod_test.yaml: namespaced
+FAIL /app/prod/prod_test.yaml: count of Service: expected 2 Service, got 1
+FAIL /app/prod/prod_test.yaml: ConfigMap/missing: no ConfigMap/missing in the output
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestRunTestBadFile(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeOverlay(fSys)
+	fSys.WriteFile("/app/prod/bad_test.yaml", []byte(`
+assertions:
+- resource: Deployment/my-app
+  field: spec.replicas
+`))
+	results := runTest(t, fSys, "/app/prod/bad_test.yaml")
+	if len(results) != 1 ||
+		results[0].Failure != "Deployment/my-app spec.replicas: field and equals go together" {
+		t.Fatalf("unexpected results %v", results)
+	}
+}
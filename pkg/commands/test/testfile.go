@@ -0,0 +1,156 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// TestFileSuffix ends the names of test files.
+const TestFileSuffix = "_test.yaml"
+
+// TestFile declares a kustomization to build, and
+// assertions on its output.
+type TestFile struct {
+	// Target is the directory of the kustomization,
+	// relative to the test file's, by default its own.
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Overrides apply to the kustomization as those
+	// of one of its clusters would.
+	Overrides *types.Cluster `json:"overrides,omitempty" yaml:"overrides,omitempty"`
+
+	// Snapshot, if set, compares the whole output
+	// with a golden file.
+	Snapshot *Snapshot `json:"snapshot,omitempty" yaml:"snapshot,omitempty"`
+
+	Assertions []Assertion `json:"assertions,omitempty" yaml:"assertions,omitempty"`
+}
+
+// Assertion is a claim about the output of a build.
+// With Resource alone, it claims the resource is in the
+// output; with Field and Equals too, that the resource's
+// field has the value.  With Kind and Count, it claims
+// the output has that many resources of the kind.
+type Assertion struct {
+	// Name describes the assertion in reports.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Resource is the Kind/name of a resource.
+	Resource string `json:"resource,omitempty" yaml:"resource,omitempty"`
+
+	// Field is the path of a field of Resource,
+	// e.g. spec.replicas.
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
+
+	Equals interface{} `json:"equals,omitempty" yaml:"equals,omitempty"`
+
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+
+	Count *int `json:"count,omitempty" yaml:"count,omitempty"`
+}
+
+// String names the assertion, by its Name if it has one.
+func (a Assertion) String() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	switch {
+	case a.Kind != "":
+		return fmt.Sprintf("count of %s", a.Kind)
+	case a.Field != "":
+		return fmt.Sprintf("%s %s", a.Resource, a.Field)
+	default:
+		return a.Resource
+	}
+}
+
+func (a Assertion) validate() error {
+	switch {
+	case a.Resource != "" && a.Kind == "" && a.Count == nil:
+		if (a.Field == "") != (a.Equals == nil) {
+			return fmt.Errorf("%s: field and equals go together", a)
+		}
+		_, _, err := parseResource(a.Resource)
+		return err
+	case a.Kind != "" && a.Count != nil &&
+		a.Resource == "" && a.Field == "" && a.Equals == nil:
+		return nil
+	default:
+		return fmt.Errorf(
+			"%s: assert on a resource, a resource's field, "+
+				"or the count of a kind", a)
+	}
+}
+
+func parseResource(s string) (string, string, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("resource %s should be Kind/name", s)
+	}
+	return parts[0], parts[1], nil
+}
+
+// check returns why the assertion fails on m,
+// or "" if it holds.
+func (a Assertion) check(m resmap.ResMap) string {
+	if a.Kind != "" {
+		n := 0
+		for _, r := range m.Resources() {
+			if r.GetKind() == a.Kind {
+				n++
+			}
+		}
+		if n != *a.Count {
+			return fmt.Sprintf("expected %d %s, got %d", *a.Count, a.Kind, n)
+		}
+		return ""
+	}
+	kind, name, _ := parseResource(a.Resource)
+	var found *resource.Resource
+	for _, r := range m.Resources() {
+		if r.GetKind() == kind && r.GetName() == name {
+			found = r
+			break
+		}
+	}
+	if found == nil {
+		return fmt.Sprintf("no %s in the output", a.Resource)
+	}
+	if a.Field == "" {
+		return ""
+	}
+	v, err := found.GetFieldValue(a.Field)
+	if err != nil {
+		return fmt.Sprintf("%s has no %s", a.Resource, a.Field)
+	}
+	// Compare as JSON, as the numbers of the
+	// test file and the resource differ in type.
+	got, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+	expected, err := json.Marshal(a.Equals)
+	if err != nil {
+		return err.Error()
+	}
+	if string(got) != string(expected) {
+		return fmt.Sprintf("expected %s %s to be %s, got %s",
+			a.Resource, a.Field, expected, got)
+	}
+	return ""
+}
+
+// target returns the directory of the kustomization
+// the test file at path builds.
+func (t *TestFile) target(path string) string {
+	return filepath.Join(filepath.Dir(path), t.Target)
+}
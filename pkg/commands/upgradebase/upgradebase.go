@@ -0,0 +1,294 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package upgradebase shows how moving the remote bases
+// of a kustomization from their locked commits to those
+// their refs name upstream changes its output.
+package upgradebase
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/compare"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/render"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	nameLocked   = "locked"
+	nameUpstream = "upstream"
+)
+
+// Options contain the options for running upgrade-base.
+type Options struct {
+	kustomizationPath string
+	update            bool
+	ignore            []string
+	output            string
+	loadRestrictor    loader.LoadRestrictorFunc
+	loader            loader.Options
+}
+
+var examples = `
+To see how the output of 'someDir/kustomization.yaml'
+would change if its remote bases moved from the commits
+locked in someDir/` + git.LockfileName + ` to those their
+refs name upstream now, run
+
+  kustomize upgrade-base someDir
+
+The kustomization is built twice, once at the locked
+commits, once at the upstream ones.  The report lists the
+commits of each remote base, then the resources only one
+build has, and the fields that differ in those both have.
+--ignore leaves out fields, e.g.
+
+  kustomize upgrade-base someDir --ignore metadata.annotations
+
+Once the changes are reviewed, lock the upstream commits
+by running
+
+  kustomize upgrade-base someDir --update
+
+To get the report as data, add -o json or -o yaml.
+`
+
+// NewCmdUpgradeBase creates a new upgrade-base command.
+func NewCmdUpgradeBase(
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, rf *resmap.Factory,
+	ptf resmap.PatchFactory) *cobra.Command {
+	var o Options
+
+	pluginConfig := plugins.DefaultPluginConfig()
+	pl := plugins.NewLoader(pluginConfig, rf)
+
+	cmd := &cobra.Command{
+		Use:          "upgrade-base {path}",
+		Short:        "Show how moving remote bases upstream changes the output",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args, fSys)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			u, err := o.RunUpgradeBase(
+				v, fSys, rf, ptf, pl,
+				o.loader.Git.Cloner(), git.ResolverUsingGitExec)
+			if err != nil {
+				return err
+			}
+			return o.emit(out, u)
+		},
+	}
+	cmd.Flags().BoolVar(
+		&o.update,
+		"update", false,
+		"Write "+git.LockfileName+" pinning the remote bases "+
+			"to the upstream commits.")
+	cmd.Flags().StringSliceVar(
+		&o.ignore,
+		"ignore", nil,
+		"Fields not compared, as paths from the top of a resource.")
+	cmd.Flags().StringVarP(
+		&o.output,
+		"output", "o", "",
+		"One of 'json' or 'yaml'.  If unspecified, print "+
+			"each remote base, then each resource that differs.")
+	loader.AddFlagLoadRestrictor(cmd.Flags())
+	o.loader.AddFlags(cmd.Flags())
+	plugins.AddFlagEnablePlugins(
+		cmd.Flags(), &pluginConfig.Enabled)
+	return cmd
+}
+
+// Validate validates upgrade-base command.
+func (o *Options) Validate(args []string, fSys fs.FileSystem) (err error) {
+	if len(args) > 1 {
+		return errors.New(
+			"specify one path to " + pgmconfig.KustomizationFileNames[0])
+	}
+	if len(args) == 0 {
+		o.kustomizationPath = loader.CWD
+	} else {
+		o.kustomizationPath = args[0]
+	}
+	if !fSys.IsDir(o.kustomizationPath) {
+		return fmt.Errorf(
+			"'%s' must be a local directory", o.kustomizationPath)
+	}
+	switch o.output {
+	case "", "json", "yaml":
+	default:
+		return fmt.Errorf("--output must be 'json' or 'yaml'")
+	}
+	o.loadRestrictor, err = loader.ValidateFlagLoadRestrictor()
+	return err
+}
+
+// Upgrade is the effect of moving the remote bases
+// to their upstream commits.
+type Upgrade struct {
+	Remotes     []Remote             `json:"remotes"`
+	Differences []compare.Difference `json:"differences,omitempty"`
+	// Updated is true if the lockfile was written.
+	Updated bool `json:"updated,omitempty"`
+}
+
+// Remote is the locked and upstream commits of
+// a remote base, Locked being "" if it's not in
+// the lockfile.
+type Remote struct {
+	Url      string `json:"url"`
+	Locked   string `json:"locked"`
+	Upstream string `json:"upstream"`
+}
+
+// Moved is true if the ref names another commit
+// than that locked.
+func (r Remote) Moved() bool {
+	return r.Locked != r.Upstream
+}
+
+// RunUpgradeBase builds the kustomization with its remote
+// bases at their locked commits, then at their upstream
+// ones, and compares the outputs.  With update, it locks
+// the upstream commits.
+func (o *Options) RunUpgradeBase(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader,
+	clone git.Cloner, resolve git.Resolver) (*Upgrade, error) {
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
+		return nil, err
+	}
+	l, err := git.ReadLockfile(fSys, o.kustomizationPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(l.Remotes) == 0 {
+		return nil, fmt.Errorf(
+			"no remote bases in %s; use kustomize verify --update "+
+				"to lock them", git.LockfileName)
+	}
+	locked := make(map[string]string)
+	for _, r := range l.Remotes {
+		locked[r.Url] = r.Commit
+	}
+	before, err := o.build(v, fSys, rf, ptf, pl,
+		func(repoSpec *git.RepoSpec) error {
+			if commit, ok := locked[repoSpec.Raw()]; ok {
+				repoSpec.Ref = commit
+			}
+			return clone(repoSpec)
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "building at the locked commits")
+	}
+	upstream := make(map[string]string)
+	// Remote bases may be cloned at once.
+	var mu sync.Mutex
+	after, err := o.build(v, fSys, rf, ptf, pl,
+		func(repoSpec *git.RepoSpec) error {
+			commit, err := resolve(repoSpec)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			upstream[repoSpec.Raw()] = commit
+			mu.Unlock()
+			repoSpec.Ref = commit
+			return clone(repoSpec)
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "building at the upstream commits")
+	}
+	u := &Upgrade{
+		Differences: compare.Diff(
+			before, after, nameLocked, nameUpstream, o.ignore),
+	}
+	updated := &git.Lockfile{}
+	for url, commit := range upstream {
+		u.Remotes = append(u.Remotes,
+			Remote{Url: url, Locked: locked[url], Upstream: commit})
+		updated.Remotes = append(updated.Remotes,
+			git.LockedRemote{Url: url, Commit: commit})
+	}
+	sort.Slice(u.Remotes, func(i, j int) bool {
+		return u.Remotes[i].Url < u.Remotes[j].Url
+	})
+	if o.update {
+		if err := updated.Write(fSys, o.kustomizationPath); err != nil {
+			return nil, err
+		}
+		u.Updated = true
+	}
+	return u, nil
+}
+
+// build builds the kustomization, getting
+// remote bases with the cloner.
+func (o *Options) build(
+	v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader, clone git.Cloner) (resmap.ResMap, error) {
+	opts := o.loader
+	opts.Cloner = clone
+	return render.NewRendererWith(fSys, v, rf, ptf, pl).
+		WithLoadRestrictor(o.loadRestrictor).
+		WithLoaderOptions(opts).
+		Render(o.kustomizationPath, nil)
+}
+
+func (o *Options) emit(out io.Writer, u *Upgrade) error {
+	switch o.output {
+	case "json":
+		b, err := json.MarshalIndent(u, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(append(b, '\n'))
+		return err
+	case "yaml":
+		b, err := yaml.Marshal(u)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(b)
+		return err
+	}
+	for _, r := range u.Remotes {
+		switch {
+		case r.Locked == "":
+			fmt.Fprintf(out, "%s: unlocked -> %s\n", r.Url, r.Upstream)
+		case r.Moved():
+			fmt.Fprintf(out, "%s: %s -> %s\n", r.Url, r.Locked, r.Upstream)
+		default:
+			fmt.Fprintf(out, "%s: %s unchanged\n", r.Url, r.Locked)
+		}
+	}
+	if err := compare.Write(out, u.Differences, ""); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%d resources differ\n", len(u.Differences))
+	if u.Updated {
+		_, err := fmt.Fprintf(out, "updated %s\n", git.LockfileName)
+		return err
+	}
+	return nil
+}
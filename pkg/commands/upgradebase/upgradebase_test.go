@@ -0,0 +1,149 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package upgradebase
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+const (
+	remote        = "github.com/org/repo//base?ref=v1"
+	commitLocked  = "1111111111111111111111111111111111111111"
+	commitUpdated = "2222222222222222222222222222222222222222"
+)
+
+// fakeClone writes the base, as of the commit
+// named by the ref, into a directory of fSys.
+func fakeClone(fSys fs.FileSystem) git.Cloner {
+	replicas := map[string]string{commitLocked: "1", commitUpdated: "2"}
+	return func(repoSpec *git.RepoSpec) error {
+		dir := "/clones/" + repoSpec.Ref
+		fSys.WriteFile(dir+"/base/kustomization.yaml", []byte(`
+resources:
+- deployment.yaml
+`))
+		fSys.WriteFile(dir+"/base/deployment.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: `+replicas[repoSpec.Ref]+`
+`))
+		repoSpec.Dir = fs.ConfirmedDir(dir)
+		return nil
+	}
+}
+
+func runUpgradeBase(
+	t *testing.T, fSys fs.FileSystem, o *Options) (*Upgrade, error) {
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(
+		resource.NewFactory(kunstruct.NewKunstructuredFactoryImpl()), pf)
+	o.loadRestrictor = loader.RestrictionRootOnly
+	resolve := func(repoSpec *git.RepoSpec) (string, error) {
+		if repoSpec.Ref != "v1" {
+			t.Fatalf("unexpected ref %s", repoSpec.Ref)
+		}
+		return commitUpdated, nil
+	}
+	return o.RunUpgradeBase(
+		validators.MakeFakeValidator(), fSys, rf, pf,
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf),
+		fakeClone(fSys), resolve)
+}
+
+func writeApp(fSys fs.FileSystem) {
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+namePrefix: my-
+resources:
+- `+remote+`
+`))
+	fSys.WriteFile("/app/"+git.LockfileName, []byte(`
+remotes:
+- url: `+remote+`
+  commit: "`+commitLocked+`"
+`))
+}
+
+func TestRunUpgradeBase(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeApp(fSys)
+	o := &Options{kustomizationPath: "/app"}
+	u, err := runUpgradeBase(t, fSys, o)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := o.emit(&out, u); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := remote + `: ` + commitLocked + ` -> ` + commitUpdated + `
+Deployment.apps/app:
+  spec.replicas: 1 | 2
+1 resources differ
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+	l, err := git.ReadLockfile(fSys, "/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l.Remotes[0].Commit != commitLocked {
+		t.Fatalf("lockfile changed without --update: %v", l)
+	}
+}
+
+func TestRunUpgradeBaseUpdate(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeApp(fSys)
+	o := &Options{kustomizationPath: "/app", update: true}
+	u, err := runUpgradeBase(t, fSys, o)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !u.Updated || len(u.Remotes) != 1 || !u.Remotes[0].Moved() {
+		t.Fatalf("unexpected upgrade %v", u)
+	}
+	l, err := git.ReadLockfile(fSys, "/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(l.Remotes) != 1 ||
+		l.Remotes[0].Url != remote || l.Remotes[0].Commit != commitUpdated {
+		t.Fatalf("unexpected lockfile %v", l)
+	}
+	// Now locked upstream, the output doesn't change.
+	o.update = false
+	u, err = runUpgradeBase(t, fSys, o)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(u.Differences) != 0 || u.Remotes[0].Moved() {
+		t.Fatalf("unexpected upgrade %v", u)
+	}
+}
+
+func TestRunUpgradeBaseNeedsLockfile(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.Mkdir("/app")
+	o := &Options{kustomizationPath: "/app"}
+	_, err := runUpgradeBase(t, fSys, o)
+	if err == nil || !strings.Contains(err.Error(), "verify --update") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,384 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package vendoring copies the remote bases and files
+// of a kustomization into it, and refers to the copies,
+// so that it builds without the network.
+package vendoring
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/commands/kustfile"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/oci"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+)
+
+// vendorDir is the directory, beside a kustomization
+// file, the copies are written to.
+const vendorDir = "vendor"
+
+// Options contain the options for running vendor.
+type Options struct {
+	kustomizationPath string
+	loader            loader.Options
+}
+
+var examples = `
+To copy the remote bases, and files fetched over http(s),
+of 'someDir/kustomization.yaml', and of its bases, into
+someDir/` + vendorDir + `, and list the copies in their place, run
+
+  kustomize vendor someDir
+
+A base is copied as its whole repository, less .git, e.g.
+to ` + vendorDir + `/github.com/someOrg/someRepo@v1.0.0, at the commit
+locked in someDir/` + git.LockfileName + `, if any.  A file is
+copied into a ` + vendorDir + ` directory beside the kustomization
+listing it, e.g. ` + vendorDir + `/example.com/crds/app.yaml.
+
+The build of someDir then uses neither git nor the
+network, and commits of the copies show upstream changes
+as diffs.  To move to other versions, restore the URLs,
+and vendor again.
+`
+
+// NewCmdVendor creates a new vendor command.
+func NewCmdVendor(out io.Writer, fSys fs.FileSystem) *cobra.Command {
+	var o Options
+
+	cmd := &cobra.Command{
+		Use:          "vendor [path]",
+		Short:        "Copy remote bases and files into " + vendorDir + ", and refer to the copies",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args, fSys)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			return o.RunVendor(out, fSys, o.loader.Git.Cloner())
+		},
+	}
+	o.loader.AddFlagsRemoteLimits(cmd.Flags())
+	o.loader.AddFlagsHTTP(cmd.Flags())
+	o.loader.Git.AddFlagsRepoCache(cmd.Flags())
+	o.loader.Git.AddFlagNetwork(cmd.Flags())
+	o.loader.Git.AddFlagRemoteRetries(cmd.Flags())
+	o.loader.Git.AddFlagsCloneDepth(cmd.Flags())
+	return cmd
+}
+
+// Validate validates vendor command.
+func (o *Options) Validate(args []string, fSys fs.FileSystem) error {
+	if len(args) > 1 {
+		return errors.New(
+			"specify one path to " + pgmconfig.KustomizationFileNames[0])
+	}
+	if len(args) == 0 {
+		o.kustomizationPath = loader.CWD
+	} else {
+		o.kustomizationPath = args[0]
+	}
+	if !fSys.IsDir(o.kustomizationPath) {
+		return fmt.Errorf(
+			"'%s' must be a local directory", o.kustomizationPath)
+	}
+	return nil
+}
+
+// vendorer holds the state of one run of vendor.
+type vendorer struct {
+	out    io.Writer
+	fSys   fs.FileSystem
+	opts   loader.Options
+	clone  git.Cloner
+	root   string
+	locked map[string]string
+	// clones are the repos cloned so far, by the
+	// directories of their copies.
+	clones  map[string]*git.RepoSpec
+	visited map[string]bool
+	copied  int
+}
+
+// RunVendor copies the remote bases and files of the
+// kustomization, and of its bases under its directory,
+// into it, and rewrites their kustomization files to
+// list the copies instead.
+func (o *Options) RunVendor(
+	out io.Writer, fSys fs.FileSystem, clone git.Cloner) error {
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
+		return err
+	}
+	root, _, err := fSys.CleanedAbs(o.kustomizationPath)
+	if err != nil {
+		return err
+	}
+	l, err := git.ReadLockfile(fSys, root.String())
+	if err != nil {
+		return err
+	}
+	v := &vendorer{
+		out:     out,
+		fSys:    fSys,
+		opts:    o.loader,
+		clone:   clone,
+		root:    root.String(),
+		locked:  make(map[string]string),
+		clones:  make(map[string]*git.RepoSpec),
+		visited: make(map[string]bool),
+	}
+	for _, r := range l.Remotes {
+		v.locked[r.Url] = r.Commit
+	}
+	defer func() {
+		for _, repoSpec := range v.clones {
+			repoSpec.Cleaner(fSys)()
+		}
+	}()
+	if err := v.walk(v.root); err != nil {
+		return err
+	}
+	if v.copied == 0 {
+		_, err = fmt.Fprintf(
+			out, "no remote bases or files in %s\n", o.kustomizationPath)
+	}
+	return err
+}
+
+// inTree is true if dir is the root or under it.
+func (v *vendorer) inTree(dir string) bool {
+	return dir == v.root ||
+		strings.HasPrefix(dir, v.root+string(filepath.Separator))
+}
+
+// walk vendors the remote resources of the kustomization
+// in dir, then walks its bases, local and vendored.
+func (v *vendorer) walk(dir string) error {
+	if v.visited[dir] {
+		return nil
+	}
+	v.visited[dir] = true
+	mf, err := kustfile.NewKustomizationFileIn(v.fSys, dir)
+	if err != nil {
+		return err
+	}
+	k, err := mf.Read()
+	if err != nil {
+		return errors.Wrapf(err, "reading kustomization in %s", dir)
+	}
+	var bases []string
+	changed := false
+	for i, r := range k.Resources {
+		local := filepath.Join(dir, r)
+		if v.fSys.Exists(local) || v.fSys.IsDir(local) {
+			if v.fSys.IsDir(local) {
+				bases = append(bases, local)
+			}
+			continue
+		}
+		if oci.IsReference(r) || loader.IsArchiveURL(r) || !v.isRemote(r) {
+			continue
+		}
+		if !v.inTree(dir) {
+			log.Printf(
+				"warning: %s is outside %s, so its remote %s isn't vendored",
+				dir, v.root, r)
+			continue
+		}
+		var path string
+		if loader.IsFileURL(r) {
+			path, err = v.vendorFile(dir, r)
+		} else {
+			path, err = v.vendorBase(r)
+			bases = append(bases, path)
+		}
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		k.Resources[i] = filepath.ToSlash(rel)
+		changed = true
+	}
+	if changed {
+		if err := mf.Write(k); err != nil {
+			return err
+		}
+	}
+	for _, b := range bases {
+		if err := v.walk(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isRemote is true if r is the URL of a file
+// or of a git repo.
+func (v *vendorer) isRemote(r string) bool {
+	if loader.IsFileURL(r) {
+		return true
+	}
+	_, err := v.opts.Git.NewRepoSpecFromUrl(r)
+	return err == nil
+}
+
+// vendorBase copies the repo of the remote base at u,
+// unless already copied, returning the path of the
+// base in the copy.
+func (v *vendorer) vendorBase(u string) (string, error) {
+	repoSpec, err := v.opts.Git.NewRepoSpecFromUrl(u)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(v.root, vendorDir, repoDirName(repoSpec))
+	cloned, ok := v.clones[dir]
+	if !ok {
+		if commit, ok := v.locked[u]; ok {
+			repoSpec.Ref = commit
+		}
+		if err := v.clone(repoSpec); err != nil {
+			return "", kusterr.WithClass(kusterr.ClassRemote,
+				messages.WithID(messages.RepoCloneFailed, err))
+		}
+		cloned = repoSpec
+		v.clones[dir] = cloned
+		if err := v.fSys.RemoveAll(dir); err != nil {
+			return "", err
+		}
+		if err := copyTree(v.fSys, cloned.CloneDir().String(), dir); err != nil {
+			return "", err
+		}
+		v.report(u, dir)
+	}
+	if repoSpec.Sha256 != "" {
+		// The copy flattens symlinks, so it's the
+		// clone whose digest the URL pins.
+		sum, err := git.TreeDigest(cloned.CloneDir().Join(repoSpec.Path))
+		if err != nil {
+			return "", err
+		}
+		if sum != repoSpec.Sha256 {
+			return "", kusterr.WithClass(kusterr.ClassRemote, messages.Errorf(
+				messages.RepoDigestMismatch, u, sum, repoSpec.Sha256))
+		}
+	}
+	return filepath.Join(dir, repoSpec.Path), nil
+}
+
+// vendorFile copies the file at u into the vendor
+// directory beside the kustomization in dir, as a
+// kustomization may only load files under its own
+// directory, returning the path of the copy.
+func (v *vendorer) vendorFile(dir, u string) (string, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(
+		dir, vendorDir, safePath(parsed.Host+"/"+parsed.Path))
+	b, err := v.opts.FetchURL(u)
+	if err != nil {
+		return "", kusterr.WithClass(kusterr.ClassRemote,
+			messages.WithID(messages.URLFetchFailed, err))
+	}
+	if err := v.fSys.MkdirAll(filepath.Dir(path)); err != nil {
+		return "", err
+	}
+	if err := v.fSys.WriteFile(path, b); err != nil {
+		return "", err
+	}
+	v.report(u, path)
+	return path, nil
+}
+
+func (v *vendorer) report(u, path string) {
+	v.copied++
+	if rel, err := filepath.Rel(v.root, path); err == nil {
+		path = rel
+	}
+	fmt.Fprintf(v.out, "vendored %s as %s\n", u, filepath.ToSlash(path))
+}
+
+// repoDirName returns the directory, under the vendor
+// directory, of the copy of a repo, e.g.
+// github.com/someOrg/someRepo@v1.0.0.
+func repoDirName(repoSpec *git.RepoSpec) string {
+	host := repoSpec.Host
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+len("://"):]
+	}
+	if i := strings.Index(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	// As in git@github.com:someOrg/someRepo.
+	host = strings.Replace(host, ":", "/", -1)
+	name := host + "/" + repoSpec.OrgRepo
+	if repoSpec.Ref != "" {
+		name += "@" + strings.Replace(repoSpec.Ref, "/", "-", -1)
+	}
+	return safePath(name)
+}
+
+var notInPath = regexp.MustCompile(`[^A-Za-z0-9._@/-]+`)
+
+// safePath returns p, a slash separated path from a
+// URL, as a relative path that stays in its directory.
+func safePath(p string) string {
+	var parts []string
+	for _, s := range strings.Split(notInPath.ReplaceAllString(p, "_"), "/") {
+		if s != "" && s != "." && s != ".." {
+			parts = append(parts, s)
+		}
+	}
+	return filepath.Join(parts...)
+}
+
+// copyTree copies the files under from to to,
+// skipping .git directories.
+func copyTree(fSys fs.FileSystem, from, to string) error {
+	if err := fSys.MkdirAll(to); err != nil {
+		return err
+	}
+	entries, err := fSys.Glob(filepath.Join(from, "*"))
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := filepath.Base(e)
+		if name == ".git" {
+			continue
+		}
+		if fSys.IsDir(e) {
+			if err := copyTree(fSys, e, filepath.Join(to, name)); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := fSys.ReadFile(e)
+		if err != nil {
+			return err
+		}
+		if err := fSys.WriteFile(filepath.Join(to, name), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
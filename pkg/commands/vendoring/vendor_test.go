@@ -0,0 +1,263 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package vendoring
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+// fakeClone writes the repos the tests refer to
+// into directories of fSys.
+func fakeClone(fSys fs.FileSystem) git.Cloner {
+	return func(repoSpec *git.RepoSpec) error {
+		dir := "/clones/" + repoSpec.OrgRepo + "/" + repoSpec.Ref
+		switch repoSpec.OrgRepo {
+		case "org/repo":
+			fSys.Mkdir(dir + "/base")
+			fSys.Mkdir(dir + "/common")
+			fSys.Mkdir(dir + "/.git")
+			fSys.WriteFile(dir+"/.git/HEAD", []byte("ref: refs/heads/master"))
+			fSys.WriteFile(dir+"/base/kustomization.yaml", []byte(`
+resources:
+- ../common
+- github.com/org/other?ref=v2
+`))
+			fSys.WriteFile(dir+"/common/kustomization.yaml", []byte(`
+resources:
+- service.yaml
+`))
+			fSys.WriteFile(dir+"/common/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`))
+		case "org/other":
+			fSys.Mkdir(dir)
+			fSys.WriteFile(dir+"/kustomization.yaml", []byte(`
+configMapGenerator:
+- name: other
+  literals:
+  - ref=`+repoSpec.Ref+`
+`))
+		}
+		repoSpec.Dir = fs.ConfirmedDir(dir)
+		return nil
+	}
+}
+
+func makeServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + strings.TrimSuffix(r.URL.Path[1:], ".yaml") + `
+`))
+		}))
+}
+
+func build(
+	t *testing.T, fSys fs.FileSystem, o loader.Options) string {
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	ldr, err := loader.NewLoaderWithOptions(
+		loader.RestrictionRootOnly, validators.MakeFakeValidator(),
+		"/app", fSys, nil, o)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ldr.Cleanup()
+	kt, err := target.NewKustTarget(
+		ldr, rf, transformer.NewFactoryImpl(),
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := m.AsYaml()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(out)
+}
+
+func TestRunVendor(t *testing.T) {
+	srv := makeServer()
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := strings.Replace(u.Host, ":", "_", -1)
+
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+# The app and its database.
+namePrefix: my-
+resources:
+- github.com/org/repo//base?ref=v1
+- `+srv.URL+`/crds.yaml
+- db
+`))
+	fSys.WriteFile("/app/db/kustomization.yaml", []byte(`
+resources:
+- `+srv.URL+`/schema.yaml
+`))
+	lo := loader.DefaultOptions()
+	lo.Cloner = fakeClone(fSys)
+	before := build(t, fSys, lo)
+
+	var out bytes.Buffer
+	o := Options{
+		kustomizationPath: "/app",
+		loader:            loader.DefaultOptions(),
+	}
+	if err := o.RunVendor(&out, fSys, fakeClone(fSys)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `vendored github.com/org/repo//base?ref=v1 as vendor/github.com/org/repo@v1
+vendored ` + srv.URL + `/crds.yaml as vendor/` + host + `/crds.yaml
+vendored github.com/org/other?ref=v2 as vendor/github.com/org/other@v2
+vendored ` + srv.URL + `/schema.yaml as db/vendor/` + host + `/schema.yaml
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+	// As the edit commands, vendor adds apiVersion and kind.
+	typeMeta := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+`
+	for path, expected := range map[string]string{
+		"/app/kustomization.yaml": `
+# The app and its database.
+namePrefix: my-
+resources:
+- vendor/github.com/org/repo@v1/base
+- vendor/` + host + `/crds.yaml
+- db
+` + typeMeta,
+		"/app/db/kustomization.yaml": `
+resources:
+- vendor/` + host + `/schema.yaml
+` + typeMeta,
+		"/app/vendor/github.com/org/repo@v1/base/kustomization.yaml": `
+resources:
+- ../common
+- ../../other@v2
+` + typeMeta,
+	} {
+		data, err := fSys.ReadFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != expected {
+			t.Fatalf("%s: expected:\n%s\ngot:\n%s", path, expected, data)
+		}
+	}
+	if fSys.Exists("/app/vendor/github.com/org/repo@v1/.git/HEAD") {
+		t.Fatalf("expected .git not to be vendored")
+	}
+	if fSys.IsDir("/clones") {
+		t.Fatalf("expected the clones to be cleaned up")
+	}
+
+	// The vendored kustomization builds as before,
+	// without cloning or fetching anything.
+	lo.Git.Network = false
+	lo.Cloner = func(repoSpec *git.RepoSpec) error {
+		t.Fatalf("unexpected clone of %s", repoSpec.Raw())
+		return nil
+	}
+	after := build(t, fSys, lo)
+	if after != before {
+		t.Fatalf("expected:\n%s\ngot:\n%s", before, after)
+	}
+
+	out.Reset()
+	if err := o.RunVendor(&out, fSys, fakeClone(fSys)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "no remote bases or files in /app\n" {
+		t.Fatalf("unexpected output %s", out.String())
+	}
+}
+
+func TestRunVendorAtLockedCommit(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- github.com/org/other?ref=v2
+`))
+	fSys.WriteFile("/app/"+git.LockfileName, []byte(`
+remotes:
+- url: github.com/org/other?ref=v2
+  commit: "1111111111111111111111111111111111111111"
+`))
+	o := Options{kustomizationPath: "/app"}
+	if err := o.RunVendor(
+		&bytes.Buffer{}, fSys, fakeClone(fSys)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := fSys.ReadFile(
+		"/app/vendor/github.com/org/other@v2/kustomization.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(
+		string(data), "ref=1111111111111111111111111111111111111111") {
+		t.Fatalf("expected the locked commit, got\n%s", data)
+	}
+}
+
+func TestRepoDirName(t *testing.T) {
+	tests := map[string]string{
+		"github.com/org/repo//base?ref=v1.0.0":         "github.com/org/repo@v1.0.0",
+		"https://example.com:8443/org/repo?ref=v1":     "example.com/8443/org/repo@v1",
+		"git@gitlab.com:org/repo.git":                  "gitlab.com/org/repo",
+		"ssh://git@github.com/org/repo.git/base?ref=a": "github.com/org/repo@a",
+	}
+	for u, expected := range tests {
+		repoSpec, err := git.NewRepoSpecFromUrl(u)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", u, err)
+		}
+		if actual := repoDirName(repoSpec); actual != expected {
+			t.Errorf("%s: expected %s, got %s", u, expected, actual)
+		}
+	}
+}
+
+func TestSafePath(t *testing.T) {
+	tests := map[string]string{
+		"example.com/crds/app.yaml":    "example.com/crds/app.yaml",
+		"example.com/../../etc/x.yaml": "example.com/etc/x.yaml",
+		"example.com//a b/./x.yaml":    "example.com/a_b/x.yaml",
+	}
+	for p, expected := range tests {
+		if actual := safePath(p); actual != expected {
+			t.Errorf("%s: expected %s, got %s", p, expected, actual)
+		}
+	}
+}
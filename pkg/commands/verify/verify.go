@@ -0,0 +1,197 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package verify checks that the remote bases of a
+// kustomization still name the commits it locked.
+package verify
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/oci"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/render"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+// Options contain the options for running verify.
+type Options struct {
+	kustomizationPath string
+	update            bool
+	loadRestrictor    loader.LoadRestrictorFunc
+	loader            loader.Options
+}
+
+var examples = `
+To pin the remote bases of 'someDir/kustomization.yaml',
+including those of other remote bases, to the commits
+their refs name now, writing someDir/` + git.LockfileName + `, run
+
+  kustomize verify someDir --update
+
+To then check that their refs still name those commits,
+e.g. that no tag was moved nor branch force-pushed, run
+
+  kustomize verify someDir
+`
+
+// NewCmdVerify creates a new verify command.
+func NewCmdVerify(
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, rf *resmap.Factory,
+	ptf resmap.PatchFactory) *cobra.Command {
+	var o Options
+
+	pluginConfig := plugins.DefaultPluginConfig()
+	pl := plugins.NewLoader(pluginConfig, rf)
+
+	cmd := &cobra.Command{
+		Use:          "verify {path}",
+		Short:        "Check the remote bases against " + git.LockfileName,
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args, fSys)
+			if err != nil {
+				return kusterr.WithClass(kusterr.ClassUsage, err)
+			}
+			if o.update {
+				return o.RunUpdate(
+					out, v, fSys, rf, ptf, pl, git.ResolverUsingGitExec)
+			}
+			return o.RunVerify(out, fSys, git.ResolverUsingGitExec)
+		},
+	}
+	cmd.Flags().BoolVar(
+		&o.update,
+		"update", false,
+		"Build the kustomization, and write "+git.LockfileName+
+			" pinning the remote bases read.")
+	loader.AddFlagLoadRestrictor(cmd.Flags())
+	o.loader.AddFlags(cmd.Flags())
+	plugins.AddFlagEnablePlugins(
+		cmd.Flags(), &pluginConfig.Enabled)
+	return cmd
+}
+
+// Validate validates verify command.
+func (o *Options) Validate(args []string, fSys fs.FileSystem) (err error) {
+	if len(args) > 1 {
+		return errors.New(
+			"specify one path to " + pgmconfig.KustomizationFileNames[0])
+	}
+	if len(args) == 0 {
+		o.kustomizationPath = loader.CWD
+	} else {
+		o.kustomizationPath = args[0]
+	}
+	if !fSys.IsDir(o.kustomizationPath) {
+		return fmt.Errorf(
+			"'%s' must be a local directory", o.kustomizationPath)
+	}
+	o.loadRestrictor, err = loader.ValidateFlagLoadRestrictor()
+	return err
+}
+
+// RunUpdate builds the kustomization, recording the
+// remote bases read, and locks each to the commit
+// its ref names.
+func (o *Options) RunUpdate(
+	out io.Writer, v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader, resolve git.Resolver) error {
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
+		return err
+	}
+	rec := loader.NewDepRecorder()
+	_, err := render.NewRendererWith(fSys, v, rf, ptf, pl).
+		WithLoadRestrictor(o.loadRestrictor).
+		WithLoaderOptions(o.loader).
+		WithTracer(rec).
+		Render(o.kustomizationPath, nil)
+	if err != nil {
+		return err
+	}
+	l := &git.Lockfile{}
+	for _, url := range rec.Dependencies().Remotes {
+		if loader.IsFileURL(url) {
+			// Files fetched over http(s) have no commit.
+			continue
+		}
+		commit, err := o.resolveUrl(resolve, url)
+		if err != nil {
+			return err
+		}
+		l.Remotes = append(l.Remotes, git.LockedRemote{Url: url, Commit: commit})
+		fmt.Fprintf(out, "locked %s at %s\n", url, commit)
+	}
+	return l.Write(fSys, o.kustomizationPath)
+}
+
+// RunVerify resolves the ref of each remote base in
+// the lockfile, and fails if any names another commit.
+func (o *Options) RunVerify(
+	out io.Writer, fSys fs.FileSystem, resolve git.Resolver) error {
+	if err := o.loader.LoadRewriteRules(fSys); err != nil {
+		return err
+	}
+	l, err := git.ReadLockfile(fSys, o.kustomizationPath)
+	if err != nil {
+		return err
+	}
+	if len(l.Remotes) == 0 {
+		return fmt.Errorf(
+			"no remote bases in %s; use --update to lock them",
+			git.LockfileName)
+	}
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tURL\tLOCKED\tUPSTREAM")
+	failed := 0
+	for _, r := range l.Remotes {
+		status := "ok"
+		commit, err := o.resolveUrl(resolve, r.Url)
+		if err != nil {
+			status = "error"
+			commit = err.Error()
+		} else if commit != r.Commit {
+			status = "moved"
+		}
+		if status != "ok" {
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", status, r.Url, r.Commit, commit)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf(
+			"%d of %d remote bases don't match %s",
+			failed, len(l.Remotes), git.LockfileName)
+	}
+	return nil
+}
+
+// resolveUrl returns the commit the ref of a remote
+// base names, or the digest an OCI artifact's tag does.
+func (o *Options) resolveUrl(
+	resolve git.Resolver, url string) (string, error) {
+	if oci.IsReference(url) {
+		return o.loader.ResolveArtifact(url)
+	}
+	repoSpec, err := o.loader.Git.NewRepoSpecFromUrl(url)
+	if err != nil {
+		return "", err
+	}
+	return resolve(repoSpec)
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+)
+
+func TestRunVerify(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.lock", []byte(`
+remotes:
+- url: github.com/org/repo//base?ref=v1
+  commit: "1111111111111111111111111111111111111111"
+- url: github.com/org/other?ref=v2
+  commit: "2222222222222222222222222222222222222222"
+`))
+	upstream := map[string]string{
+		"v1": "1111111111111111111111111111111111111111",
+		"v2": "3333333333333333333333333333333333333333",
+	}
+	resolve := func(repoSpec *git.RepoSpec) (string, error) {
+		return upstream[repoSpec.Ref], nil
+	}
+
+	var out bytes.Buffer
+	o := Options{kustomizationPath: "/app"}
+	err := o.RunVerify(&out, fSys, resolve)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `STATUS  URL                               LOCKED                                    UPSTREAM
+ok      github.com/org/repo//base?ref=v1  1111111111111111111111111111111111111111  1111111111111111111111111111111111111111
+moved   github.com/org/other?ref=v2       2222222222222222222222222222222222222222  3333333333333333333333333333333333333333
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	upstream["v2"] = "2222222222222222222222222222222222222222"
+	if err := o.RunVerify(&out, fSys, resolve); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRunVerifyArtifact(t *testing.T) {
+	manifest := `{"schemaVersion":2,"layers":[]}`
+	s := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v2/org/bases/manifests/v1" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(manifest))
+		}))
+	defer s.Close()
+	sum := sha256.Sum256([]byte(manifest))
+	url := "oci://" + strings.TrimPrefix(s.URL, "http://") +
+		"/org/bases:v1//prod"
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.lock", []byte(`
+remotes:
+- url: `+url+`
+  commit: sha256:`+hex.EncodeToString(sum[:])+`
+`))
+
+	var out bytes.Buffer
+	o := Options{kustomizationPath: "/app", loader: loader.DefaultOptions()}
+	if err := o.RunVerify(&out, fSys, nil); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+
+	manifest = `{"schemaVersion":2,"layers":[{}]}`
+	err := o.RunVerify(&out, fSys, nil)
+	if err == nil || !strings.Contains(out.String(), "moved   "+url) {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+}
+
+func TestRunVerifyNeedsLockfile(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.Mkdir("/app")
+	o := Options{kustomizationPath: "/app"}
+	err := o.RunVerify(nil, fSys, nil)
+	if err == nil || !strings.Contains(err.Error(), "--update") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLockfileRoundTrip(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.Mkdir("/app")
+	l := &git.Lockfile{Remotes: []git.LockedRemote{
+		{Url: "github.com/org/b", Commit: "b"},
+		{Url: "github.com/org/a", Commit: "a"},
+	}}
+	if err := l.Write(fSys, "/app"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual, err := git.ReadLockfile(fSys, "/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(actual.Remotes) != 2 || actual.Remotes[0].Url != "github.com/org/a" {
+		t.Fatalf("unexpected lockfile %v", actual)
+	}
+}
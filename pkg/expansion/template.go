@@ -0,0 +1,60 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package expansion
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// placeholder matches ${name}, and $${ which escapes ${.
+var placeholder = regexp.MustCompile(`\$\$\{|\$\{([^}]*)\}`)
+
+var paramName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// IsParamName is true if ${name} is a placeholder.
+func IsParamName(name string) bool {
+	return paramName.MatchString(name)
+}
+
+// ExpandTemplate replaces each ${name} in the input with
+// the named param, as envsubst would.  Unlike envsubst,
+// it fails on placeholders without a param; write $${
+// for a literal ${.  There are no defaults, conditionals
+// or loops.
+func ExpandTemplate(input string, params map[string]string) (string, error) {
+	missing := make(map[string]bool)
+	var bad []string
+	result := placeholder.ReplaceAllStringFunc(input, func(s string) string {
+		if s == "$${" {
+			return "${"
+		}
+		name := s[2 : len(s)-1]
+		if !paramName.MatchString(name) {
+			bad = append(bad, s)
+			return s
+		}
+		v, ok := params[name]
+		if !ok {
+			missing[name] = true
+		}
+		return v
+	})
+	if len(bad) > 0 {
+		return "", fmt.Errorf(
+			"bad placeholders %s", strings.Join(bad, ", "))
+	}
+	if len(missing) > 0 {
+		var names []string
+		for n := range missing {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf(
+			"no params for placeholders %s", strings.Join(names, ", "))
+	}
+	return result, nil
+}
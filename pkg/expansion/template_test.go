@@ -0,0 +1,51 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package expansion_test
+
+import (
+	"testing"
+
+	. "sigs.k8s.io/kustomize/v3/pkg/expansion"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	params := map[string]string{"name": "app", "TAG_1": "v1"}
+	testCases := map[string]struct {
+		input    string
+		expected string
+		err      string
+	}{
+		"params": {
+			input:    "image: ${name}:${TAG_1}",
+			expected: "image: app:v1",
+		},
+		"escaped": {
+			input:    "cmd: echo $${HOME} $HOME $(VAR)",
+			expected: "cmd: echo ${HOME} $HOME $(VAR)",
+		},
+		"missing": {
+			input: "${a} ${name} ${b} ${a}",
+			err:   "no params for placeholders a, b",
+		},
+		"bad": {
+			input: "${} ${a-b}",
+			err:   "bad placeholders ${}, ${a-b}",
+		},
+	}
+	for n, tc := range testCases {
+		actual, err := ExpandTemplate(tc.input, params)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("%s: expected error %q, got %v", n, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", n, err)
+		}
+		if actual != tc.expected {
+			t.Fatalf("%s: expected %q, got %q", n, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package filesys has the names, of the file systems
+// kustomizations are read from, that code written
+// against sigs.k8s.io/kustomize/kyaml/filesys uses,
+// for the krusty package.
+//
+// They're those of package fs, so a FileSystem lacks
+// the ReadDir and Walk methods of the kyaml one.
+package filesys
+
+import (
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+const (
+	Separator = "/"
+	SelfDir   = "."
+	ParentDir = ".."
+)
+
+// FileSystem groups basic os filesystem methods.
+type FileSystem = fs.FileSystem
+
+// File groups the basic os.File methods.
+type File = fs.File
+
+// ConfirmedDir is a clean, absolute, delinkified
+// path that was confirmed to point to an existing
+// directory.
+type ConfirmedDir = fs.ConfirmedDir
+
+// MakeFsOnDisk returns a FileSystem reading and
+// writing the local disk.
+func MakeFsOnDisk() FileSystem {
+	return fs.MakeRealFS()
+}
+
+// MakeFsInMemory returns an empty FileSystem
+// held in memory, e.g. for tests.
+func MakeFsInMemory() FileSystem {
+	return fs.MakeFakeFS()
+}
+
+// NewTmpConfirmedDir returns a new temporary
+// directory on disk.
+func NewTmpConfirmedDir() (ConfirmedDir, error) {
+	return fs.NewTmpConfirmedDir()
+}
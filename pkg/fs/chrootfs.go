@@ -0,0 +1,138 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fs
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+var _ FileSystem = chrootFs{}
+
+// chrootFs implements a read only FileSystem of a
+// directory of another FileSystem, shown as the root.
+// Paths can't leave the directory, by ".." or by
+// symbolic link.
+type chrootFs struct {
+	fSys FileSystem
+	root ConfirmedDir
+}
+
+// MakeChrootFS returns a read only FileSystem of the
+// root directory of fSys.  Relative paths are relative
+// to the root.
+func MakeChrootFS(fSys FileSystem, root ConfirmedDir) FileSystem {
+	return chrootFs{fSys: fSys, root: root}
+}
+
+// outer returns the path in the underlying
+// file system of name, without following links.
+func (c chrootFs) outer(name string) string {
+	return c.root.Join(
+		filepath.Clean(string(filepath.Separator) + name))
+}
+
+// inner returns the path in c of outer, a
+// path in the underlying file system in c.root.
+func (c chrootFs) inner(outer string) string {
+	rel, err := filepath.Rel(c.root.String(), outer)
+	if err != nil {
+		// Programmer/assumption error.
+		panic(err)
+	}
+	return filepath.Join(string(filepath.Separator), rel)
+}
+
+// confined returns the path in the underlying file
+// system of name, following links, if it's in c.root.
+func (c chrootFs) confined(name string) (ConfirmedDir, string, error) {
+	d, f, err := c.fSys.CleanedAbs(c.outer(name))
+	if err != nil {
+		return "", "", fmt.Errorf("'%s' doesn't exist", name)
+	}
+	if !d.HasPrefix(c.root) {
+		return "", "", fmt.Errorf("'%s' is outside the root", name)
+	}
+	return d, f, nil
+}
+
+func (c chrootFs) readOnly(name string) error {
+	return fmt.Errorf("can't write '%s' in a read only file system", name)
+}
+
+// Create fails.
+func (c chrootFs) Create(name string) (File, error) {
+	return nil, c.readOnly(name)
+}
+
+// Mkdir fails.
+func (c chrootFs) Mkdir(name string) error { return c.readOnly(name) }
+
+// MkdirAll fails.
+func (c chrootFs) MkdirAll(name string) error { return c.readOnly(name) }
+
+// RemoveAll fails.
+func (c chrootFs) RemoveAll(name string) error { return c.readOnly(name) }
+
+// WriteFile fails.
+func (c chrootFs) WriteFile(name string, _ []byte) error {
+	return c.readOnly(name)
+}
+
+// Open opens name, if it's in the root.
+func (c chrootFs) Open(name string) (File, error) {
+	d, f, err := c.confined(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.fSys.Open(d.Join(f))
+}
+
+// IsDir returns true if name is a directory in the root.
+func (c chrootFs) IsDir(name string) bool {
+	d, f, err := c.confined(name)
+	return err == nil && c.fSys.IsDir(d.Join(f))
+}
+
+// CleanedAbs returns the cleaned, absolute path of
+// path in the root, with no symbolic links, split
+// into directory and file components.
+func (c chrootFs) CleanedAbs(path string) (ConfirmedDir, string, error) {
+	d, f, err := c.confined(path)
+	if err != nil {
+		return "", "", err
+	}
+	return ConfirmedDir(c.inner(d.String())), f, nil
+}
+
+// Exists returns true if name exists in the root.
+func (c chrootFs) Exists(name string) bool {
+	d, f, err := c.confined(name)
+	return err == nil && c.fSys.Exists(d.Join(f))
+}
+
+// Glob returns the files in the root matching pattern.
+func (c chrootFs) Glob(pattern string) ([]string, error) {
+	matches, err := c.fSys.Glob(c.outer(pattern))
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, m := range matches {
+		name := c.inner(m)
+		if _, _, err := c.confined(name); err == nil {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// ReadFile reads name, if it's in the root.
+func (c chrootFs) ReadFile(name string) ([]byte, error) {
+	d, f, err := c.confined(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.fSys.ReadFile(d.Join(f))
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestChrootFS(t *testing.T) {
+	x, testDir := makeTestDir(t)
+	defer os.RemoveAll(testDir)
+	root := filepath.Join(testDir, "tenant")
+	for path, content := range map[string]string{
+		"tenant/app/kustomization.yaml": "resources: []",
+		"tenant/app/config.yaml":        "config",
+		"other/secret.yaml":             "secret",
+	} {
+		p := filepath.Join(testDir, path)
+		if err := x.MkdirAll(filepath.Dir(p)); err != nil {
+			t.Fatal(err)
+		}
+		if err := x.WriteFile(p, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := os.Symlink(
+		filepath.Join(testDir, "other"), filepath.Join(root, "other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Symlink("config.yaml", filepath.Join(root, "app", "link.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := MakeChrootFS(x, ConfirmedDir(root))
+
+	d, f, err := c.CleanedAbs("/app/../app/link.yaml")
+	if err != nil || d != "/app" || f != "config.yaml" {
+		t.Fatalf("unexpected %s, %s, %v", d, f, err)
+	}
+	b, err := c.ReadFile("app/config.yaml")
+	if err != nil || string(b) != "config" {
+		t.Fatalf("unexpected %s, %v", b, err)
+	}
+	if !c.IsDir("/") || !c.Exists("/app/kustomization.yaml") {
+		t.Fatalf("expected the root's files")
+	}
+	// ".." stops at the root.
+	if _, err := c.ReadFile("../other/secret.yaml"); err == nil {
+		t.Fatalf("expected error reading above the root")
+	}
+	// Links can't leave it.
+	if _, err := c.ReadFile("/other/secret.yaml"); err == nil {
+		t.Fatalf("expected error following link out of the root")
+	}
+	if c.Exists("/other") || c.IsDir("/other") {
+		t.Fatalf("expected link out of the root to be hidden")
+	}
+	matches, err := c.Glob("/*")
+	if err != nil || !reflect.DeepEqual(matches, []string{"/app"}) {
+		t.Fatalf("unexpected %v, %v", matches, err)
+	}
+	if err := c.WriteFile("/app/new.yaml", nil); err == nil {
+		t.Fatalf("expected error writing a read only file system")
+	}
+}
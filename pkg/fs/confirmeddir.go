@@ -17,7 +17,6 @@ limitations under the License.
 package fs
 
 import (
-	"io/ioutil"
 	"path/filepath"
 	"strings"
 )
@@ -26,25 +25,6 @@ import (
 // that was confirmed to point to an existing directory.
 type ConfirmedDir string
 
-// NewTmpConfirmedDir returns a temporary dir, else error.
-// The directory is cleaned, no symlinks, etc. so its
-// returned as a ConfirmedDir.
-func NewTmpConfirmedDir() (ConfirmedDir, error) {
-	n, err := ioutil.TempDir("", "kustomize-")
-	if err != nil {
-		return "", err
-	}
-
-	// In MacOs `ioutil.TempDir` creates a directory
-	// with root in the `/var` folder, which is in turn a symlinked path
-	// to `/private/var`.
-	// Function `filepath.EvalSymlinks`is used to
-	// resolve the real absolute path.
-	deLinked, err := filepath.EvalSymlinks(n)
-	return ConfirmedDir(deLinked), err
-
-}
-
 // HasPrefix returns true if the directory argument
 // is a prefix of self (d) from the point of view of
 // a file system.
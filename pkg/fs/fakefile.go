@@ -18,6 +18,7 @@ package fs
 
 import (
 	"bytes"
+	"io"
 	"os"
 )
 
@@ -29,6 +30,7 @@ type FakeFile struct {
 	content []byte
 	dir     bool
 	open    bool
+	offset  int
 }
 
 // makeDir makes a fake directory.
@@ -42,9 +44,14 @@ func (f *FakeFile) Close() error {
 	return nil
 }
 
-// Read never fails, and doesn't mutate p.
+// Read reads the content, from where the last Read stopped.
 func (f *FakeFile) Read(p []byte) (n int, err error) {
-	return len(p), nil
+	if f.offset >= len(f.content) {
+		return 0, io.EOF
+	}
+	n = copy(p, f.content[f.offset:])
+	f.offset += n
+	return n, nil
 }
 
 // Write saves the contents of the argument to memory.
@@ -63,7 +70,7 @@ func (f *FakeFile) GetContent() []byte {
 	return f.content
 }
 
-// Stat returns nil.
+// Stat returns the info of the fake file.
 func (f *FakeFile) Stat() (os.FileInfo, error) {
-	return nil, nil
+	return &Fakefileinfo{f}, nil
 }
@@ -94,12 +94,16 @@ func (fs *fakeFs) RemoveAll(name string) error {
 	return nil
 }
 
-// Open returns a fake file in the open state.
+// Open returns a fake file in the open state, to be
+// read from the start.
 func (fs *fakeFs) Open(name string) (File, error) {
 	if _, found := fs.m[name]; !found {
-		return nil, fmt.Errorf("file %q cannot be opened", name)
+		return nil, fmt.Errorf("cannot read file %q", name)
 	}
-	return fs.m[name], nil
+	f := *fs.m[name]
+	f.open = true
+	f.offset = 0
+	return &f, nil
 }
 
 // CleanedAbs cannot fail.
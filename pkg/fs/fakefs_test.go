@@ -18,6 +18,7 @@ package fs
 
 import (
 	"bytes"
+	"io/ioutil"
 	"reflect"
 	"testing"
 )
@@ -126,6 +127,25 @@ func TestReadFile(t *testing.T) {
 	}
 }
 
+func TestOpen(t *testing.T) {
+	x := MakeFakeFS()
+	x.WriteFile("foo", []byte("some content"))
+	for i := 0; i < 2; i++ {
+		f, err := x.Open("foo")
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		content, err := ioutil.ReadAll(f)
+		f.Close()
+		if err != nil || string(content) != "some content" {
+			t.Fatalf("unexpected content '%s', %v", content, err)
+		}
+	}
+	if _, err := x.Open("bar"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
 func TestWriteFile(t *testing.T) {
 	x := MakeFakeFS()
 	c := []byte("heybuddy")
@@ -0,0 +1,170 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Temporary files and directories, e.g. git clones, are
+// made in one base directory, with names starting with
+// TmpPrefix, and are tracked so they can be removed when
+// kustomize exits, even if it's interrupted or panics.
+// Those left by a crash can be found and removed later.
+
+const (
+	// TmpPrefix starts the names of temporary
+	// files and directories.
+	TmpPrefix = "kustomize-"
+
+	// TmpBaseEnv names an environment variable holding
+	// the directory to make temporary files in.
+	TmpBaseEnv = "KUSTOMIZE_TMPDIR"
+)
+
+var tmp = struct {
+	sync.Mutex
+	base  string
+	paths map[string]bool
+}{paths: make(map[string]bool)}
+
+// SetTmpBase sets the directory to make temporary files
+// in, overriding $KUSTOMIZE_TMPDIR.  An empty dir restores
+// the default.
+func SetTmpBase(dir string) {
+	tmp.Lock()
+	defer tmp.Unlock()
+	tmp.base = dir
+}
+
+// TmpBase returns the directory temporary files are made in:
+// that given to SetTmpBase, else $KUSTOMIZE_TMPDIR, else the
+// system's temporary directory.
+func TmpBase() string {
+	tmp.Lock()
+	defer tmp.Unlock()
+	return tmpBase()
+}
+
+func tmpBase() string {
+	if tmp.base != "" {
+		return tmp.base
+	}
+	if dir := os.Getenv(TmpBaseEnv); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
+
+// NewTmpConfirmedDir returns a temporary dir, else error.
+// The directory is cleaned, no symlinks, etc. so its
+// returned as a ConfirmedDir.
+func NewTmpConfirmedDir() (ConfirmedDir, error) {
+	tmp.Lock()
+	defer tmp.Unlock()
+	n, err := ioutil.TempDir(tmpBase(), TmpPrefix)
+	if err != nil {
+		return "", err
+	}
+	tmp.paths[n] = true
+
+	// In MacOs `ioutil.TempDir` creates a directory
+	// with root in the `/var` folder, which is in turn a symlinked path
+	// to `/private/var`.
+	// Function `filepath.EvalSymlinks`is used to
+	// resolve the real absolute path.
+	deLinked, err := filepath.EvalSymlinks(n)
+	if err == nil {
+		delete(tmp.paths, n)
+		tmp.paths[deLinked] = true
+	}
+	return ConfirmedDir(deLinked), err
+}
+
+// NewTmpFile returns a new, open temporary file
+// whose name starts with TmpPrefix and then name.
+func NewTmpFile(name string) (*os.File, error) {
+	tmp.Lock()
+	defer tmp.Unlock()
+	f, err := ioutil.TempFile(tmpBase(), TmpPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	tmp.paths[f.Name()] = true
+	return f, nil
+}
+
+// RemoveTmp removes a temporary file or
+// directory, and stops tracking it.
+func RemoveTmp(path string) error {
+	tmp.Lock()
+	defer tmp.Unlock()
+	delete(tmp.paths, path)
+	return os.RemoveAll(path)
+}
+
+// RemoveAllTmp removes all the temporary files
+// and directories made by this process.
+func RemoveAllTmp() {
+	tmp.Lock()
+	defer tmp.Unlock()
+	for p := range tmp.paths {
+		os.RemoveAll(p)
+		delete(tmp.paths, p)
+	}
+}
+
+// RemoveAllTmpOnSignal calls RemoveAllTmp, then exits,
+// when the process gets an interrupt or termination signal.
+func RemoveAllTmpOnSignal() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		s := <-c
+		RemoveAllTmp()
+		code := 1
+		if n, ok := s.(syscall.Signal); ok {
+			code = 128 + int(n)
+		}
+		os.Exit(code)
+	}()
+}
+
+// RemoveStaleTmp removes the temporary files and
+// directories in TmpBase older than the given age,
+// e.g. those left by a crash, returning their paths.
+func RemoveStaleTmp(fSys FileSystem, age time.Duration) ([]string, error) {
+	paths, err := fSys.Glob(filepath.Join(TmpBase(), TmpPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var result []string
+	for _, p := range paths {
+		f, err := fSys.Open(p)
+		if err != nil {
+			return result, err
+		}
+		info, err := f.Stat()
+		f.Close()
+		if err != nil {
+			return result, err
+		}
+		if time.Since(info.ModTime()) < age {
+			continue
+		}
+		if err := fSys.RemoveAll(p); err != nil {
+			return result, err
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setTmpBaseForTest(t *testing.T) (string, func()) {
+	base, err := ioutil.TempDir("", "tmp-test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base, _ = filepath.EvalSymlinks(base)
+	SetTmpBase(base)
+	return base, func() {
+		SetTmpBase("")
+		os.RemoveAll(base)
+	}
+}
+
+func TestTmpBase(t *testing.T) {
+	os.Setenv(TmpBaseEnv, "/from/env")
+	defer os.Unsetenv(TmpBaseEnv)
+	if TmpBase() != "/from/env" {
+		t.Fatalf("unexpected base %s", TmpBase())
+	}
+	SetTmpBase("/from/flag")
+	defer SetTmpBase("")
+	if TmpBase() != "/from/flag" {
+		t.Fatalf("unexpected base %s", TmpBase())
+	}
+}
+
+func TestRemoveAllTmp(t *testing.T) {
+	base, cleanup := setTmpBaseForTest(t)
+	defer cleanup()
+	d, err := NewTmpConfirmedDir()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.Dir(d.String()) != base ||
+		!strings.HasPrefix(filepath.Base(d.String()), TmpPrefix) {
+		t.Fatalf("unexpected dir %s", d)
+	}
+	f, err := NewTmpFile("pipe-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.Close()
+	kept, err := NewTmpFile("kept-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kept.Close()
+	if err := RemoveTmp(kept.Name()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	RemoveAllTmp()
+	for _, p := range []string{d.String(), f.Name(), kept.Name()} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed", p)
+		}
+	}
+}
+
+func TestRemoveStaleTmp(t *testing.T) {
+	base, cleanup := setTmpBaseForTest(t)
+	defer cleanup()
+	fSys := MakeRealFS()
+	fSys.Mkdir(filepath.Join(base, TmpPrefix+"old"))
+	fSys.WriteFile(filepath.Join(base, TmpPrefix+"pipe-old"), nil)
+	fSys.Mkdir(filepath.Join(base, "other"))
+
+	removed, err := RemoveStaleTmp(fSys, time.Hour)
+	if err != nil || len(removed) != 0 {
+		t.Fatalf("expected nothing removed, got %v, %v", removed, err)
+	}
+	removed, err = RemoveStaleTmp(fSys, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(removed) != 2 || !fSys.Exists(filepath.Join(base, "other")) {
+		t.Fatalf("unexpected removals %v", removed)
+	}
+}
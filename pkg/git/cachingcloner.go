@@ -0,0 +1,77 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+// CachingCloner clones each repo and ref once with another
+// Cloner, handing out the same clone until it's older than
+// a time to live, for a long running process that builds
+// the same remote bases again and again.
+type CachingCloner struct {
+	clone  Cloner
+	ttl    time.Duration
+	mu     sync.Mutex
+	clones map[string]cachedClone
+}
+
+type cachedClone struct {
+	dir    fs.ConfirmedDir
+	ref    string
+	commit string
+	at     time.Time
+}
+
+// NewCachingCloner returns a CachingCloner using clone.
+func NewCachingCloner(clone Cloner, ttl time.Duration) *CachingCloner {
+	return &CachingCloner{
+		clone:  clone,
+		ttl:    ttl,
+		clones: make(map[string]cachedClone),
+	}
+}
+
+// Clone is a Cloner.
+func (c *CachingCloner) Clone(repoSpec *RepoSpec) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := repoSpec.cacheKey()
+	if cc, ok := c.clones[key]; ok {
+		if time.Since(cc.at) < c.ttl {
+			repoSpec.Dir = cc.dir
+			repoSpec.Ref = cc.ref
+			repoSpec.Commit = cc.commit
+			repoSpec.Cached = true
+			return nil
+		}
+		fs.RemoveTmp(cc.dir.String())
+		delete(c.clones, key)
+	}
+	if err := c.clone(repoSpec); err != nil {
+		return err
+	}
+	c.clones[key] = cachedClone{
+		dir:    repoSpec.Dir,
+		ref:    repoSpec.Ref,
+		commit: repoSpec.Commit,
+		at:     time.Now(),
+	}
+	repoSpec.Cached = true
+	return nil
+}
+
+// Cleanup removes all the clones.
+func (c *CachingCloner) Cleanup() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, cc := range c.clones {
+		fs.RemoveTmp(cc.dir.String())
+		delete(c.clones, key)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+func TestCachingCloner(t *testing.T) {
+	clones := 0
+	clone := func(rs *RepoSpec) error {
+		clones++
+		rs.Dir = fs.ConfirmedDir("/no/such/clone")
+		rs.Commit = "1111111111111111111111111111111111111111"
+		return nil
+	}
+	c := NewCachingCloner(clone, time.Hour)
+	for _, url := range []string{
+		"github.com/org/repo//base?ref=v1",
+		"github.com/org/repo//overlay?ref=v1",
+		"github.com/org/repo//base?ref=v2",
+		"github.com/org/repo//base?ref=v1&depth=1",
+		"github.com/org/repo//base?depth=5&ref=v1",
+	} {
+		rs, err := NewRepoSpecFromUrl(url)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := c.Clone(rs); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !rs.Cached || rs.Dir != "/no/such/clone" ||
+			rs.Commit != "1111111111111111111111111111111111111111" {
+			t.Fatalf("unexpected repoSpec %+v", rs)
+		}
+		if err := rs.Cleaner(fs.MakeFakeFS())(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if clones != 3 {
+		t.Fatalf("expected one clone per ref and depth, got %d", clones)
+	}
+
+	c = NewCachingCloner(clone, 0)
+	rs, _ := NewRepoSpecFromUrl("github.com/org/repo//base?ref=v1")
+	c.Clone(rs)
+	c.Clone(rs)
+	if clones != 5 {
+		t.Fatalf("expected expired clones to be redone, got %d", clones-3)
+	}
+}
@@ -18,22 +18,81 @@ package git
 
 import (
 	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 )
 
 // Cloner is a function that can clone a git repo.
 type Cloner func(repoSpec *RepoSpec) error
 
+// ErrRepoCloneFailed is the kind of the errors of
+// remote bases that couldn't be cloned, for programs
+// building kustomizations to test for with errors.Is.
+var ErrRepoCloneFailed = errors.New("repo clone failed")
+
+// DefaultCloneDepth is the commits of history cloned,
+// as building a base only needs its files.
+const DefaultCloneDepth = 1
+
+const (
+	flagCloneDepth  = "clone-depth"
+	flagSparseClone = "enable-sparse-clone"
+)
+
+// AddFlagsCloneDepth adds flags setting the clone depth,
+// and whether clones are sparse.
+func (o *Options) AddFlagsCloneDepth(set *pflag.FlagSet) {
+	set.IntVar(
+		&o.CloneDepth, flagCloneDepth, DefaultCloneDepth,
+		"Commits of history to clone of remote bases whose URLs "+
+			"have no depth parameter; 0 for all of it.")
+	set.BoolVar(
+		&o.SparseClone, flagSparseClone, false,
+		"Check out only the directory of a remote base, and the "+
+			"files of its parent directories, e.g. of a monorepo; "+
+			"the base can't then refer to its repo's other directories.")
+}
+
+// sparseCheckoutPatterns returns the patterns, for
+// .git/info/sparse-checkout, of the directory path, all
+// of it, and of the files, but not directories, in each
+// of its ancestors, e.g. for a/b, the lines /*, !/*/,
+// /a/, !/a/*/ and /a/b/.  These are the patterns of
+// git's cone mode, which older versions of git take as
+// plain patterns.
+func sparseCheckoutPatterns(path string) string {
+	var b strings.Builder
+	b.WriteString("/*\n!/*/\n")
+	dirs := strings.Split(path, "/")
+	for i := range dirs {
+		dir := strings.Join(dirs[:i+1], "/")
+		b.WriteString("/" + dir + "/\n")
+		if i < len(dirs)-1 {
+			b.WriteString("!/" + dir + "/*/\n")
+		}
+	}
+	return b.String()
+}
+
 // ClonerUsingGitExec uses a local git install, as opposed
 // to say, some remote API, to obtain a local clone of
-// a remote repo.
+// a remote repo, as the Options of the repoSpec say.
 func ClonerUsingGitExec(repoSpec *RepoSpec) error {
+	err := repoSpec.options().ErrIfOffline("clone", repoSpec.CloneSpec())
+	if err != nil {
+		return err
+	}
 	gitProgram, err := exec.LookPath("git")
 	if err != nil {
-		return errors.Wrap(err, "no 'git' program on path")
+		return Permanent(errors.Wrap(err, "no 'git' program on path"))
 	}
 	repoSpec.Dir, err = fs.NewTmpConfirmedDir()
 	if err != nil {
@@ -71,14 +130,44 @@ func ClonerUsingGitExec(repoSpec *RepoSpec) error {
 	if repoSpec.Ref == "" {
 		repoSpec.Ref = "master"
 	}
+	cred, err := repoSpec.credential()
+	if err != nil {
+		return err
+	}
+	authArgs, authEnv := cred.gitExec()
+	args := append(authArgs, "fetch")
+	if depth := repoSpec.CloneDepth(); depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", depth))
+	}
+	if path := repoSpec.sparsePath(); path != "" {
+		cmd = exec.Command(
+			gitProgram,
+			"config",
+			"core.sparseCheckout",
+			"true")
+		cmd.Stdout = &out
+		cmd.Dir = repoSpec.Dir.String()
+		err = cmd.Run()
+		if err == nil {
+			err = ioutil.WriteFile(
+				filepath.Join(repoSpec.Dir.String(), ".git", "info", "sparse-checkout"),
+				[]byte(sparseCheckoutPatterns(path)), 0644)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "trouble making %s sparse", repoSpec.Dir.String())
+		}
+		// Fetch the content of files only as they're
+		// checked out; servers that can't filter send all.
+		args = append(args, "--filter=blob:none")
+	}
 	cmd = exec.Command(
 		gitProgram,
-		"fetch",
-		"--depth=1",
-		"origin",
-		repoSpec.Ref)
+		append(args, "origin", repoSpec.Ref)...)
 	cmd.Stdout = &out
 	cmd.Dir = repoSpec.Dir.String()
+	if authEnv != nil {
+		cmd.Env = append(os.Environ(), authEnv...)
+	}
 	err = cmd.Run()
 	if err != nil {
 		return errors.Wrapf(err, "trouble fetching %s", repoSpec.Ref)
@@ -96,6 +185,37 @@ func ClonerUsingGitExec(repoSpec *RepoSpec) error {
 		return errors.Wrapf(
 			err, "trouble hard resetting empty repository to %s", repoSpec.Ref)
 	}
+
+	if repoSpec.Submodules {
+		// Submodules are fetched in full, as a shallow
+		// fetch needn't reach the commits they're at.
+		cmd = exec.Command(
+			gitProgram,
+			append(authArgs, "submodule", "update", "--init", "--recursive")...)
+		cmd.Stdout = &out
+		cmd.Dir = repoSpec.Dir.String()
+		if authEnv != nil {
+			cmd.Env = append(os.Environ(), authEnv...)
+		}
+		err = cmd.Run()
+		if err != nil {
+			return errors.Wrapf(
+				err, "trouble updating submodules of %s", repoSpec.Ref)
+		}
+	}
+
+	cmd = exec.Command(
+		gitProgram,
+		"rev-parse",
+		"HEAD")
+	var head bytes.Buffer
+	cmd.Stdout = &head
+	cmd.Dir = repoSpec.Dir.String()
+	err = cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "trouble reading commit of %s", repoSpec.Ref)
+	}
+	repoSpec.Commit = strings.TrimSpace(head.String())
 	return nil
 }
 
@@ -15,3 +15,129 @@ limitations under the License.
 */
 
 package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+func TestSparseCheckoutPatterns(t *testing.T) {
+	tests := map[string]string{
+		"base": "/*\n!/*/\n/base/\n",
+		"apps/web/overlays/prod": "/*\n!/*/\n" +
+			"/apps/\n!/apps/*/\n" +
+			"/apps/web/\n!/apps/web/*/\n" +
+			"/apps/web/overlays/\n!/apps/web/overlays/*/\n" +
+			"/apps/web/overlays/prod/\n",
+	}
+	for path, expected := range tests {
+		if actual := sparseCheckoutPatterns(path); actual != expected {
+			t.Errorf("%s: expected %q, got %q", path, expected, actual)
+		}
+	}
+}
+
+func TestSparsePath(t *testing.T) {
+	rs := &RepoSpec{Path: "/apps/web/"}
+	if p := rs.sparsePath(); p != "" {
+		t.Fatalf("expected a full clone, got sparse path %s", p)
+	}
+	sparse := DefaultOptions()
+	sparse.SparseClone = true
+	tests := map[string]string{
+		"/apps/web/":   "apps/web",
+		"apps/./web":   "apps/web",
+		"":             "",
+		"/":            "",
+		"/../../apps":  "apps",
+		"/apps/[a-z]*": "",
+	}
+	for path, expected := range tests {
+		rs := &RepoSpec{Path: path, opts: &sparse}
+		if actual := rs.sparsePath(); actual != expected {
+			t.Errorf("%s: expected %q, got %q", path, expected, actual)
+		}
+	}
+	rs = &RepoSpec{
+		Host: "github.com/", OrgRepo: "org/repo", Path: "/apps", opts: &sparse}
+	if key := rs.cacheKey(); key != "github.com/org/repo?ref=&depth=1&sparse=apps" {
+		t.Fatalf("unexpected cache key %s", key)
+	}
+}
+
+func TestSparseClone(t *testing.T) {
+	gitProgram, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("no git program on path")
+	}
+	dir, err := ioutil.TempDir("", "kustomize-sparse-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range []string{
+		"README.md", "apps/common.yaml", "apps/web/kustomization.yaml",
+		"apps/web/base/deployment.yaml", "apps/db/kustomization.yaml",
+		"docs/index.md",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"checkout", "-q", "-b", "master"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com",
+			"commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command(gitProgram, args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	sparse := DefaultOptions()
+	sparse.SparseClone = true
+	rs := &RepoSpec{
+		Host: "file://", OrgRepo: dir, Path: "/apps/web", opts: &sparse}
+	if err := ClonerUsingGitExec(rs); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	defer fs.RemoveTmp(rs.Dir.String())
+	var files []string
+	filepath.Walk(rs.Dir.String(), func(
+		path string, info os.FileInfo, err error) error {
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() {
+			rel, _ := filepath.Rel(rs.Dir.String(), path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	sort.Strings(files)
+	expected := []string{
+		"README.md", "apps/common.yaml",
+		"apps/web/base/deployment.yaml", "apps/web/kustomization.yaml",
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Fatalf("expected %v, got %v", expected, files)
+	}
+	if len(rs.Commit) != 40 {
+		t.Fatalf("unexpected commit %s", rs.Commit)
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	githttp "gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	gitssh "gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+)
+
+// Credentials authenticate the clones of private repos,
+// e.g. in CI, where git's own config has none.  Clones
+// over https take a token from the environment, else the
+// login of the repo's host in a netrc file; clones over
+// ssh take the SSHKey.  With none of these, clones
+// authenticate as git's own config says.
+type Credentials struct {
+	// SSHKey is the path of the private key clones over
+	// ssh authenticate with.  Keys with a passphrase need
+	// an ssh-agent instead.
+	SSHKey string
+	// Netrc is the path of a netrc file; empty means
+	// $NETRC, else .netrc in the home directory.
+	Netrc string
+}
+
+const (
+	// SSHKeyEnv is the private key file, for when
+	// --git-ssh-key isn't given.
+	SSHKeyEnv = "KUSTOMIZE_GIT_SSH_KEY"
+	// TokenEnv is the token clones over https authenticate
+	// with.  TokenEnv, "_", then the repo's host, upper
+	// cased, with '_' for what isn't a letter or digit,
+	// e.g. KUSTOMIZE_GIT_TOKEN_GITHUB_COM, is that of
+	// one host, which wins over TokenEnv.
+	TokenEnv = "KUSTOMIZE_GIT_TOKEN"
+	// TokenUsernameEnv is the username a token is given
+	// with, for hosts that check it; if unset,
+	// DefaultTokenUsername.
+	TokenUsernameEnv = "KUSTOMIZE_GIT_USERNAME"
+	// DefaultTokenUsername is the username a token is
+	// given with if $KUSTOMIZE_GIT_USERNAME is unset.
+	DefaultTokenUsername = "x-access-token"
+	// NetrcEnv is the netrc file, for when --git-netrc
+	// isn't given, as curl and git take it.
+	NetrcEnv = "NETRC"
+)
+
+const (
+	flagSSHKey = "git-ssh-key"
+	flagNetrc  = "git-netrc"
+)
+
+// AddFlagsCredentials adds the flags setting the
+// Credentials.  Tokens have no flag, as arguments
+// are visible to other users.
+func (o *Options) AddFlagsCredentials(set *pflag.FlagSet) {
+	set.StringVar(
+		&o.Credentials.SSHKey, flagSSHKey, "",
+		"Private key file with which to clone remote bases over ssh; "+
+			"if unspecified, $"+SSHKeyEnv+".")
+	set.StringVar(
+		&o.Credentials.Netrc, flagNetrc, "",
+		"Netrc file of the logins with which to clone remote bases "+
+			"over https, but for those of hosts with a token in $"+
+			TokenEnv+"; if unspecified, $"+NetrcEnv+
+			", else ~/.netrc.")
+}
+
+// credential is what a clone authenticates with:
+// a username and password, or token, or a private key.
+type credential struct {
+	username string
+	password string
+	sshKey   string
+	// url is the scheme and host a username and
+	// password are given to, e.g. https://github.com.
+	url string
+}
+
+// credential returns what the clone of the spec
+// authenticates with, if anything, as its Options'
+// Credentials, and the environment, say.
+func (x *RepoSpec) credential() (credential, error) {
+	spec := x.CloneSpec()
+	if isSSH(spec) {
+		key := x.options().Credentials.SSHKey
+		if key == "" {
+			key = os.Getenv(SSHKeyEnv)
+		}
+		return credential{sshKey: key}, nil
+	}
+	u, err := url.Parse(spec)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return credential{}, nil
+	}
+	host := u.Hostname()
+	if token := tokenOf(host); token != "" {
+		username := os.Getenv(TokenUsernameEnv)
+		if username == "" {
+			username = DefaultTokenUsername
+		}
+		return credential{
+			username: username, password: token,
+			url: u.Scheme + "://" + u.Host}, nil
+	}
+	login, password, err := x.options().Credentials.netrcLogin(host)
+	if err != nil {
+		return credential{}, Permanent(err)
+	}
+	if login == "" && password == "" {
+		return credential{}, nil
+	}
+	return credential{
+		username: login, password: password,
+		url: u.Scheme + "://" + u.Host}, nil
+}
+
+// isSSH is true if spec is cloned over ssh, e.g.
+// git@github.com:org/repo.git or ssh://host/repo.
+func isSSH(spec string) bool {
+	if strings.HasPrefix(spec, "ssh://") {
+		return true
+	}
+	i := strings.Index(spec, "@")
+	return i > 0 && !strings.Contains(spec[:i], "/") &&
+		!strings.Contains(spec[:i], ":")
+}
+
+// tokenOf returns the token of host from
+// the environment, if any.
+func tokenOf(host string) string {
+	name := strings.Map(func(r rune) rune {
+		if ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') ||
+			('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(host))
+	if token := os.Getenv(TokenEnv + "_" + name); token != "" {
+		return token
+	}
+	return os.Getenv(TokenEnv)
+}
+
+// netrcLogin returns the login and password of host
+// in the netrc file, or empty if it has none.  Only
+// a netrc file named by --git-netrc or $NETRC must
+// exist.
+func (c Credentials) netrcLogin(host string) (string, string, error) {
+	path := c.Netrc
+	if path == "" {
+		path = os.Getenv(NetrcEnv)
+	}
+	named := path != ""
+	if !named {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", nil
+		}
+		name := ".netrc"
+		if runtime.GOOS == "windows" {
+			name = "_netrc"
+		}
+		path = filepath.Join(home, name)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if !named && os.IsNotExist(err) {
+			return "", "", nil
+		}
+		return "", "", errors.Wrap(err, "reading netrc")
+	}
+	defer f.Close()
+	login, password, err := parseNetrc(f, host)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "reading netrc %s", path)
+	}
+	return login, password, nil
+}
+
+// parseNetrc returns the login and password of the
+// machine host, else of the default, in a netrc file.
+// Macros, which only ftp uses, are skipped.
+func parseNetrc(r io.Reader, host string) (string, string, error) {
+	var (
+		login, password string
+		// in is "machine" or "default" within an entry
+		// whose login applies, else empty.
+		in, found string
+		skipMacro bool
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if skipMacro {
+			skipMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			value := func() string {
+				if i+1 < len(fields) {
+					i++
+					return fields[i]
+				}
+				return ""
+			}
+			switch fields[i] {
+			case "machine":
+				if found == "machine" {
+					return login, password, nil
+				}
+				in = ""
+				if strings.EqualFold(value(), host) {
+					in, found = "machine", "machine"
+					login, password = "", ""
+				}
+			case "default":
+				if found == "machine" {
+					return login, password, nil
+				}
+				in = ""
+				if found == "" {
+					in, found = "default", "default"
+				}
+			case "login":
+				if v := value(); in != "" {
+					login = v
+				}
+			case "password":
+				if v := value(); in != "" {
+					password = v
+				}
+			case "account":
+				value()
+			case "macdef":
+				skipMacro = true
+				i = len(fields)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", err
+	}
+	if login == "" && password == "" {
+		return "", "", nil
+	}
+	return login, password, nil
+}
+
+// Names of the environment variables in which the
+// credential helper of gitExec finds the credential.
+const (
+	helperUsernameEnv = "KUSTOMIZE_GIT_HELPER_USERNAME"
+	helperPasswordEnv = "KUSTOMIZE_GIT_HELPER_PASSWORD"
+)
+
+// gitExec returns the arguments, before its command,
+// and the environment, with which the git program
+// authenticates with the credential.  A username and
+// password are given by a credential helper, from the
+// environment, rather than in the URL, whence they'd
+// show in errors and the clone's config, and only to
+// their host, not, e.g., that of a submodule.
+func (c credential) gitExec() ([]string, []string) {
+	if c.sshKey != "" {
+		return nil, []string{
+			"GIT_SSH_COMMAND=ssh -o IdentitiesOnly=yes -i " +
+				shellQuote(c.sshKey)}
+	}
+	if c.username == "" && c.password == "" {
+		return nil, nil
+	}
+	helper := fmt.Sprintf(
+		`!f() { test "$1" = get && `+
+			`echo "username=$%s" && echo "password=$%s"; }; f`,
+		helperUsernameEnv, helperPasswordEnv)
+	key := "credential." + c.url + ".helper="
+	return []string{
+			// The empty helper drops those of git's config.
+			"-c", key, "-c", key + helper},
+		[]string{
+			helperUsernameEnv + "=" + c.username,
+			helperPasswordEnv + "=" + c.password}
+}
+
+// shellQuote quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// goGit returns the auth with which go-git authenticates
+// clones of spec with the credential; nil means go-git's
+// own, e.g. an ssh-agent's keys.
+func (c credential) goGit(spec string) (transport.AuthMethod, error) {
+	if c.sshKey != "" {
+		user := "git"
+		if ep, err := transport.NewEndpoint(spec); err == nil && ep.User != "" {
+			user = ep.User
+		}
+		auth, err := gitssh.NewPublicKeysFromFile(user, c.sshKey, "")
+		if err != nil {
+			return nil, Permanent(errors.Wrapf(
+				err, "reading ssh key %s", c.sshKey))
+		}
+		return auth, nil
+	}
+	if c.username == "" && c.password == "" {
+		return nil, nil
+	}
+	return &githttp.BasicAuth{Username: c.username, Password: c.password}, nil
+}
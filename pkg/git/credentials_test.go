@@ -0,0 +1,172 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+func TestParseNetrc(t *testing.T) {
+	netrc := `machine github.com login alice password s3cret
+machine example.com
+  login bob
+  macdef init
+  machine example.com login mallory password nope
+
+  password hunter2
+default login anon password guest
+`
+	tests := map[string][2]string{
+		"github.com":  {"alice", "s3cret"},
+		"EXAMPLE.com": {"bob", "hunter2"},
+		"gitlab.com":  {"anon", "guest"},
+	}
+	for host, expected := range tests {
+		login, password, err := parseNetrc(strings.NewReader(netrc), host)
+		if err != nil {
+			t.Fatalf("%s: unexpected err: %v", host, err)
+		}
+		if login != expected[0] || password != expected[1] {
+			t.Errorf("%s: expected %v, got %s %s", host, expected, login, password)
+		}
+	}
+	login, password, err := parseNetrc(
+		strings.NewReader("machine github.com login alice\n"), "gitlab.com")
+	if err != nil || login != "" || password != "" {
+		t.Fatalf("expected no login, got %q %q %v", login, password, err)
+	}
+}
+
+func TestCredential(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-credentials-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	netrc := filepath.Join(dir, "netrc")
+	err = ioutil.WriteFile(
+		netrc, []byte("machine example.com login bob password hunter2\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, env := range []string{
+		TokenEnv, TokenEnv + "_GITHUB_COM", TokenUsernameEnv, SSHKeyEnv} {
+		defer os.Setenv(env, os.Getenv(env))
+		os.Unsetenv(env)
+	}
+	os.Setenv(TokenEnv, "any")
+	os.Setenv(TokenEnv+"_GITHUB_COM", "gh")
+	os.Setenv(SSHKeyEnv, "/keys/id")
+
+	opts := DefaultOptions()
+	opts.Credentials.Netrc = netrc
+	tests := map[string]credential{
+		"https://github.com/org/repo": {
+			username: DefaultTokenUsername, password: "gh",
+			url: "https://github.com"},
+		"https://gitlab.com/org/repo": {
+			username: DefaultTokenUsername, password: "any",
+			url: "https://gitlab.com"},
+		"git@github.com:org/repo":      {sshKey: "/keys/id"},
+		"ssh://git@example.com/o/repo": {sshKey: "/keys/id"},
+	}
+	for url, expected := range tests {
+		rs, err := opts.NewRepoSpecFromUrl(url)
+		if err != nil {
+			t.Fatalf("%s: unexpected err: %v", url, err)
+		}
+		if c, err := rs.credential(); err != nil || c != expected {
+			t.Errorf("%s: expected %v, got %v %v", url, expected, c, err)
+		}
+	}
+
+	os.Unsetenv(TokenEnv)
+	rs, _ := opts.NewRepoSpecFromUrl("https://example.com/org/repo")
+	c, err := rs.credential()
+	if err != nil || c != (credential{
+		username: "bob", password: "hunter2", url: "https://example.com"}) {
+		t.Fatalf("expected the netrc login, got %v %v", c, err)
+	}
+	opts.Credentials.Netrc = filepath.Join(dir, "missing")
+	rs, _ = opts.NewRepoSpecFromUrl("https://example.com/org/repo")
+	if _, err := rs.credential(); err == nil || !isPermanent(err) {
+		t.Fatalf("expected a permanent error, got %v", err)
+	}
+}
+
+// TestClonePrivateRepo serves a repo over http with
+// git http-backend, as a host asking for a token, and
+// clones it with each client.
+func TestClonePrivateRepo(t *testing.T) {
+	dir, _ := makeTestRepo(t)
+	defer os.RemoveAll(dir)
+	out, err := exec.Command("git", "--exec-path").Output()
+	if err != nil {
+		t.Skip("no git exec path")
+	}
+	backend := filepath.Join(strings.TrimSpace(string(out)), "git-http-backend")
+	if _, err := os.Stat(backend); err != nil {
+		t.Skip("no git http-backend")
+	}
+	cgiHandler := &cgi.Handler{
+		Path: backend,
+		Env: []string{
+			"GIT_PROJECT_ROOT=" + filepath.Dir(dir),
+			"GIT_HTTP_EXPORT_ALL=1",
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if _, password, _ := r.BasicAuth(); password != "t0ken" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			cgiHandler.ServeHTTP(w, r)
+		}))
+	defer server.Close()
+
+	defer os.Setenv(TokenEnv, os.Getenv(TokenEnv))
+	defer os.Setenv("GIT_TERMINAL_PROMPT", os.Getenv("GIT_TERMINAL_PROMPT"))
+	os.Setenv("GIT_TERMINAL_PROMPT", "0")
+	for _, client := range []string{ClientExec, ClientGoGit} {
+		for _, token := range []string{"wrong", "t0ken"} {
+			os.Setenv(TokenEnv, token)
+			opts := DefaultOptions()
+			opts.Client = client
+			opts.Credentials.Netrc = os.DevNull
+			rs := &RepoSpec{
+				Host: server.URL + "/", OrgRepo: filepath.Base(dir),
+				Path: "/base", opts: &opts}
+			err := opts.GitCloner()(rs)
+			if rs.Dir != "" {
+				defer fs.RemoveTmp(rs.Dir.String())
+			}
+			if token == "wrong" {
+				if err == nil {
+					t.Errorf("%s: expected an error with the wrong token", client)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%s: unexpected err: %v", client, err)
+				continue
+			}
+			_, err = os.Stat(filepath.Join(rs.AbsPath(), "kustomization.yaml"))
+			if err != nil {
+				t.Errorf("%s: unexpected err: %v", client, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// TreeDigest returns the hex sha256 of the files under
+// dir, skipping .git directories, and the .git files
+// of submodules.  It hashes a line per
+// file, sorted by slash separated path relative to dir,
+// of the hex sha256 of its content, two spaces and the
+// path; the content of a symlink is its target.  For a
+// directory without symlinks or .git, the digest is what
+// `find . -type f | cut -c3- | LC_ALL=C sort | xargs sha256sum | sha256sum`
+// prints.
+func TreeDigest(dir string) (string, error) {
+	lines := make(map[string]string)
+	err := filepath.Walk(dir, func(
+		path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() == ".git" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		sum, err := fileDigest(path, info)
+		if err != nil {
+			return err
+		}
+		lines[rel] = fmt.Sprintf("%s  %s\n", sum, rel)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	var paths []string
+	for p := range lines {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	h := sha256.New()
+	for _, p := range paths {
+		io.WriteString(h, lines[p])
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func fileDigest(path string, info os.FileInfo) (string, error) {
+	h := sha256.New()
+	if info.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		io.WriteString(h, link)
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
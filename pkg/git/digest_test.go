@@ -0,0 +1,48 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTreeDigest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-digest-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for path, content := range map[string]string{
+		"kustomization.yaml": "a\n",
+		"sub/x.yaml":         "b",
+		".git/HEAD":          "ignored\n",
+	} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sum, err := TreeDigest(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// As printed by sha256sum of the sha256sum of each file.
+	expected := "1da0c4b2d266662903f8fbaedd5bc4d9631988c274b69eca0309a2b413f0b035"
+	if sum != expected {
+		t.Fatalf("expected %s, got %s", expected, sum)
+	}
+	if err := ioutil.WriteFile(
+		filepath.Join(dir, "sub/x.yaml"), []byte("c"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if sum, _ = TreeDigest(dir); sum == expected {
+		t.Fatalf("expected a changed file to change the digest")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"os"
+)
+
+// fileLock is an advisory lock, across processes, on
+// a file, e.g. the lock file of a repo cache entry.
+// Locks of the file opened twice conflict even within
+// a process.
+type fileLock struct {
+	path string
+	f    *os.File
+}
+
+// lockShared waits for a shared lock on the file at
+// path, making it if need be.  Readers hold one.
+func lockShared(path string) (*fileLock, error) {
+	l, ok, err := lockPath(path, false, true)
+	if err == nil && !ok {
+		err = os.ErrExist
+	}
+	return l, err
+}
+
+// tryLockExclusive takes an exclusive lock on the file
+// at path, making it if need be, if it can without
+// waiting, i.e. no other holds a lock on it, returning
+// nil if it can't.
+func tryLockExclusive(path string) *fileLock {
+	l, ok, err := lockPath(path, true, false)
+	if err != nil || !ok {
+		return nil
+	}
+	return l
+}
+
+func lockPath(path string, exclusive, wait bool) (*fileLock, bool, error) {
+	for {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+		if err != nil {
+			return nil, false, err
+		}
+		ok, err := lockFile(f, exclusive, wait)
+		if err != nil || !ok {
+			f.Close()
+			return nil, ok, err
+		}
+		// The holder of an exclusive lock may have removed
+		// the file, and another made it again, while this
+		// waited; its lock is on the file now at path.
+		if sameFile(f, path) {
+			return &fileLock{path: path, f: f}, true, nil
+		}
+		unlockFile(f)
+		f.Close()
+	}
+}
+
+func sameFile(f *os.File, path string) bool {
+	a, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	b, err := os.Stat(path)
+	return err == nil && os.SameFile(a, b)
+}
+
+// downgrade makes an exclusive lock shared, without
+// letting another take an exclusive lock between.
+func (l *fileLock) downgrade() error {
+	return downgradeFile(l.f)
+}
+
+// remove removes the file, while holding an exclusive
+// lock, then releases it.
+func (l *fileLock) remove() {
+	os.Remove(l.path)
+	l.release()
+}
+
+// release unlocks and closes the file.
+func (l *fileLock) release() {
+	unlockFile(l.f)
+	l.f.Close()
+}
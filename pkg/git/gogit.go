@@ -0,0 +1,257 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	gogit "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+const (
+	// ClientExec clones by running the git program.
+	ClientExec = "exec"
+	// ClientGoGit clones with go-git, in process, so
+	// remote bases clone where there's no git program,
+	// e.g. in minimal containers.
+	ClientGoGit = "go-git"
+
+	// GitClientEnv names the environment variable
+	// giving the default of --git-client.
+	GitClientEnv = "KUSTOMIZE_GIT_CLIENT"
+
+	flagGitClient = "git-client"
+)
+
+// defaultGitClient is $KUSTOMIZE_GIT_CLIENT, else ClientExec.
+func defaultGitClient() string {
+	if c := os.Getenv(GitClientEnv); c != "" {
+		return c
+	}
+	return ClientExec
+}
+
+// clientValue is a pflag.Value taking only the
+// names of clients.
+type clientValue struct {
+	client *string
+}
+
+func (v clientValue) String() string {
+	return *v.client
+}
+
+func (v clientValue) Set(s string) error {
+	if s != ClientExec && s != ClientGoGit {
+		return fmt.Errorf("legal values: %s, %s", ClientExec, ClientGoGit)
+	}
+	*v.client = s
+	return nil
+}
+
+func (v clientValue) Type() string {
+	return "string"
+}
+
+// AddFlagGitClient adds the flag setting the git client.
+func (o *Options) AddFlagGitClient(set *pflag.FlagSet) {
+	set.Var(
+		clientValue{&o.Client}, flagGitClient,
+		"How remote bases are cloned: '"+ClientExec+"', by running "+
+			"the git program, or '"+ClientGoGit+"', in process, where "+
+			"there's no git program; if unspecified, $"+GitClientEnv+
+			", else '"+ClientExec+"'.  '"+ClientGoGit+"' makes no sparse "+
+			"clones, and fetches commits only by their full SHA.")
+}
+
+// GitCloner returns the Cloner of the git client the
+// Options name, which doesn't use the repo cache.
+func (o Options) GitCloner() Cloner {
+	switch o.Client {
+	case "", ClientExec:
+		return ClonerUsingGitExec
+	case ClientGoGit:
+		return ClonerUsingGoGit
+	}
+	client := o.Client
+	return func(*RepoSpec) error {
+		return Permanent(fmt.Errorf(
+			"illegal git client %q, from $%s or --%s; legal values: %s, %s",
+			client, GitClientEnv, flagGitClient, ClientExec, ClientGoGit))
+	}
+}
+
+// fullSha matches the full SHA of a commit.
+var fullSha = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// fetchedRef is the ref a branch or tag is fetched into.
+const fetchedRef = "refs/kustomize/fetched"
+
+// ClonerUsingGoGit clones with go-git rather than the
+// git program, as the Options of the repoSpec say, but
+// for SparseClone, checking out the whole tree.
+func ClonerUsingGoGit(repoSpec *RepoSpec) error {
+	err := repoSpec.options().ErrIfOffline("clone", repoSpec.CloneSpec())
+	if err != nil {
+		return err
+	}
+	repoSpec.Dir, err = fs.NewTmpConfirmedDir()
+	if err != nil {
+		return err
+	}
+	repo, err := gogit.PlainInit(repoSpec.Dir.String(), false)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"trouble initializing empty git repo in %s",
+			repoSpec.Dir.String())
+	}
+	_, err = repo.CreateRemote(&config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{repoSpec.CloneSpec()},
+	})
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"trouble adding remote %s",
+			repoSpec.CloneSpec())
+	}
+	if repoSpec.Ref == "" {
+		repoSpec.Ref = "master"
+	}
+	cred, err := repoSpec.credential()
+	if err != nil {
+		return err
+	}
+	auth, err := cred.goGit(repoSpec.CloneSpec())
+	if err != nil {
+		return err
+	}
+	commit, err := fetchGoGit(repo, repoSpec, auth)
+	if err != nil {
+		return errors.Wrapf(err, "trouble fetching %s", repoSpec.Ref)
+	}
+	wt, err := repo.Worktree()
+	if err == nil {
+		err = wt.Checkout(&gogit.CheckoutOptions{Hash: commit, Force: true})
+	}
+	if err != nil {
+		return errors.Wrapf(
+			err, "trouble checking out %s", repoSpec.Ref)
+	}
+	if repoSpec.Submodules {
+		err = updateSubmodulesGoGit(wt, repoSpec.CloneSpec(), auth)
+		if err != nil {
+			return errors.Wrapf(
+				err, "trouble updating submodules of %s", repoSpec.Ref)
+		}
+	}
+	repoSpec.Commit = commit.String()
+	return nil
+}
+
+// fetchGoGit fetches the ref of the spec, as a branch,
+// then a tag, else, if it's the full SHA of a commit,
+// all the branches and tags, returning the commit.
+func fetchGoGit(
+	repo *gogit.Repository, repoSpec *RepoSpec,
+	auth transport.AuthMethod) (plumbing.Hash, error) {
+	ref := repoSpec.Ref
+	for _, src := range []string{"refs/heads/" + ref, "refs/tags/" + ref} {
+		err := repo.Fetch(&gogit.FetchOptions{
+			RefSpecs: []config.RefSpec{
+				config.RefSpec("+" + src + ":" + fetchedRef)},
+			Depth: repoSpec.CloneDepth(),
+			Auth:  auth,
+			Tags:  gogit.NoTags,
+		})
+		if err != nil && err != gogit.NoErrAlreadyUpToDate {
+			if strings.HasPrefix(err.Error(), "couldn't find remote ref") {
+				continue
+			}
+			return plumbing.ZeroHash, err
+		}
+		return resolveGoGit(repo, fetchedRef)
+	}
+	if !fullSha.MatchString(ref) {
+		return plumbing.ZeroHash, fmt.Errorf(
+			"no branch or tag %s; --%s=%s fetches commits "+
+				"only by their full SHA", ref, flagGitClient, ClientGoGit)
+	}
+	// A server needn't send a commit no ref names,
+	// nor may a shallow fetch of the refs reach it.
+	err := repo.Fetch(&gogit.FetchOptions{
+		RefSpecs: []config.RefSpec{
+			"+refs/heads/*:refs/remotes/origin/*",
+			"+refs/tags/*:refs/tags/*",
+		},
+		Auth: auth,
+	})
+	if err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return plumbing.ZeroHash, err
+	}
+	return resolveGoGit(repo, ref)
+}
+
+// updateSubmodulesGoGit initializes and updates the
+// submodules of wt, recursively, giving the auth of the
+// repo spec to those on its host alone, e.g. those of
+// relative URLs.
+func updateSubmodulesGoGit(
+	wt *gogit.Worktree, spec string, auth transport.AuthMethod) error {
+	subs, err := wt.Submodules()
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		a := auth
+		if !sameHost(sub.Config().URL, spec) {
+			a = nil
+		}
+		err = sub.Update(&gogit.SubmoduleUpdateOptions{
+			Init:              true,
+			RecurseSubmodules: gogit.DefaultSubmoduleRecursionDepth,
+			Auth:              a,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameHost is true if url, maybe relative, is of the
+// host of spec.
+func sameHost(url, spec string) bool {
+	if strings.HasPrefix(url, "./") || strings.HasPrefix(url, "../") {
+		return true
+	}
+	u, err := transport.NewEndpoint(url)
+	if err != nil {
+		return false
+	}
+	s, err := transport.NewEndpoint(spec)
+	return err == nil && u.Protocol == s.Protocol &&
+		strings.EqualFold(u.Host, s.Host) && u.Port == s.Port
+}
+
+// resolveGoGit returns the commit rev names,
+// that of the tag if it's an annotated tag.
+func resolveGoGit(
+	repo *gogit.Repository, rev string) (plumbing.Hash, error) {
+	h, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return *h, nil
+}
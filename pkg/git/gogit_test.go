@@ -0,0 +1,180 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+// makeTestRepo makes a repo with a commit on master
+// writing base/kustomization.yaml, tagged v1 with an
+// annotated tag, and a later one on master, returning
+// its dir and the SHA of the first commit.
+func makeTestRepo(t *testing.T) (string, string) {
+	gitProgram, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("no git program on path")
+	}
+	dir, err := ioutil.TempDir("", "kustomize-gogit-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(args ...string) string {
+		cmd := exec.Command(gitProgram, append([]string{
+			"-c", "user.name=test", "-c", "user.email=test@example.com"},
+			args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(content string) {
+		path := filepath.Join(dir, "base", "kustomization.yaml")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "-q")
+	run("checkout", "-q", "-b", "master")
+	write("namePrefix: v1-\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "v1")
+	run("tag", "-a", "-m", "v1", "v1")
+	sha := run("rev-parse", "HEAD")
+	write("namePrefix: v2-\n")
+	run("commit", "-q", "-a", "-m", "v2")
+	return dir, sha
+}
+
+func TestClonerUsingGoGit(t *testing.T) {
+	dir, sha := makeTestRepo(t)
+	defer os.RemoveAll(dir)
+	opts := DefaultOptions()
+	opts.Client = ClientGoGit
+	tests := map[string]string{
+		"":     "namePrefix: v2-\n",
+		"v1":   "namePrefix: v1-\n",
+		sha:    "namePrefix: v1-\n",
+		"nope": "",
+	}
+	for ref, expected := range tests {
+		rs := &RepoSpec{
+			Host: "file://", OrgRepo: dir, Path: "/base", Ref: ref,
+			opts: &opts}
+		err := opts.GitCloner()(rs)
+		if rs.Dir != "" {
+			defer fs.RemoveTmp(rs.Dir.String())
+		}
+		if expected == "" {
+			if err == nil || !strings.Contains(err.Error(), "trouble fetching") {
+				t.Errorf("%s: expected fetch error, got %v", ref, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected err: %v", ref, err)
+			continue
+		}
+		b, err := ioutil.ReadFile(
+			filepath.Join(rs.AbsPath(), "kustomization.yaml"))
+		if err != nil {
+			t.Errorf("%s: unexpected err: %v", ref, err)
+			continue
+		}
+		if string(b) != expected {
+			t.Errorf("%s: expected %q, got %q", ref, expected, b)
+		}
+		if len(rs.Commit) != 40 || (ref != "" && rs.Commit != sha) {
+			t.Errorf("%s: unexpected commit %s", ref, rs.Commit)
+		}
+	}
+}
+
+func TestGitClonerIllegalClient(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Client = "svn"
+	err := opts.GitCloner()(&RepoSpec{})
+	if err == nil || !isPermanent(err) {
+		t.Fatalf("expected a permanent error, got %v", err)
+	}
+}
+
+func TestFlagGitClient(t *testing.T) {
+	opts := DefaultOptions()
+	set := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	opts.AddFlagGitClient(set)
+	if err := set.Parse([]string{"--git-client=go-git"}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if opts.Client != ClientGoGit {
+		t.Fatalf("expected %s, got %s", ClientGoGit, opts.Client)
+	}
+	if err := set.Parse([]string{"--git-client=svn"}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestCloneSubmodules(t *testing.T) {
+	dir, _ := makeTestRepo(t)
+	defer os.RemoveAll(dir)
+	sub, _ := makeTestRepo(t)
+	defer os.RemoveAll(sub)
+	// git no longer clones submodules from local paths
+	// unless told to.
+	for k, v := range map[string]string{
+		"GIT_CONFIG_COUNT":   "1",
+		"GIT_CONFIG_KEY_0":   "protocol.file.allow",
+		"GIT_CONFIG_VALUE_0": "always",
+	} {
+		defer os.Setenv(k, os.Getenv(k))
+		os.Setenv(k, v)
+	}
+	for _, args := range [][]string{
+		{"submodule", "add", "-q", sub, "vendor/sub"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com",
+			"commit", "-q", "-m", "sub"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	subFile := filepath.Join("vendor", "sub", "base", "kustomization.yaml")
+	for _, client := range []string{ClientExec, ClientGoGit} {
+		for _, submodules := range []bool{false, true} {
+			opts := DefaultOptions()
+			opts.Client = client
+			rs := &RepoSpec{
+				Host: "file://", OrgRepo: dir, Submodules: submodules,
+				opts: &opts}
+			err := opts.GitCloner()(rs)
+			if rs.Dir != "" {
+				defer fs.RemoveTmp(rs.Dir.String())
+			}
+			if err != nil {
+				t.Errorf("%s: unexpected err: %v", client, err)
+				continue
+			}
+			_, err = os.Stat(filepath.Join(rs.AbsPath(), subFile))
+			if submodules != (err == nil) {
+				t.Errorf("%s: submodules %v, but stat of %s: %v",
+					client, submodules, subFile, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/yaml"
+)
+
+// LockfileName is the name of the file, beside a
+// kustomization file, pinning the commits of the
+// remote bases of its build.
+const LockfileName = "kustomization.lock"
+
+// Lockfile pins remote bases to commits.
+type Lockfile struct {
+	Remotes []LockedRemote `json:"remotes,omitempty" yaml:"remotes,omitempty"`
+}
+
+// LockedRemote is the commit a remote base's ref
+// named when locked.
+type LockedRemote struct {
+	// Url of the remote base, as written in the
+	// kustomization file.
+	Url string `json:"url" yaml:"url"`
+	// Commit is the SHA of the commit, or, for an
+	// OCI artifact, the digest of its manifest.
+	Commit string `json:"commit" yaml:"commit"`
+}
+
+// ReadLockfile reads the lockfile in the directory,
+// returning an empty one if there's none.
+func ReadLockfile(fSys fs.FileSystem, dir string) (*Lockfile, error) {
+	path := filepath.Join(dir, LockfileName)
+	l := &Lockfile{}
+	if !fSys.Exists(path) {
+		return l, nil
+	}
+	data, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, l)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+	return l, nil
+}
+
+// Write writes the lockfile into the directory,
+// with the remotes sorted by URL.
+func (l *Lockfile) Write(fSys fs.FileSystem, dir string) error {
+	sort.Slice(l.Remotes, func(i, j int) bool {
+		return l.Remotes[i].Url < l.Remotes[j].Url
+	})
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return fSys.WriteFile(filepath.Join(dir, LockfileName), data)
+}
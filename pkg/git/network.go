@@ -0,0 +1,34 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+)
+
+const flagNetwork = "network"
+
+// AddFlagNetwork adds the flag setting whether
+// builds may use the network.
+func (o *Options) AddFlagNetwork(set *pflag.FlagSet) {
+	set.BoolVar(
+		&o.Network, flagNetwork, true,
+		"Allow cloning remote bases, and fetching URLs, OCI "+
+			"artifacts and bucket objects; with --"+flagNetwork+
+			"=false, they fail, "+
+			"e.g. in a hermetic build, though clones in the repo "+
+			"cache are still used.")
+}
+
+// ErrIfOffline returns an error, saying what of s
+// can't be done, if builds may not use the network.
+// It's Permanent, so not tried again.
+func (o Options) ErrIfOffline(what, s string) error {
+	if o.Network {
+		return nil
+	}
+	return Permanent(messages.Errorf(
+		messages.NetworkDisabled, what, s, flagNetwork))
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+func TestNetworkOff(t *testing.T) {
+	o := DefaultOptions()
+	o.Network = false
+	rs, err := o.NewRepoSpecFromUrl("github.com/org/repo//base?ref=v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = ClonerUsingGitExec(rs)
+	if err == nil || !strings.Contains(err.Error(),
+		"can't clone https://github.com/org/repo.git, as --network=false") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = ResolverUsingGitExec(rs)
+	if err == nil || !strings.Contains(err.Error(), "--network=false") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A commit resolves to itself.
+	rs.Ref = "1111111111111111111111111111111111111111"
+	if sha, err := ResolverUsingGitExec(rs); err != nil || sha != rs.Ref {
+		t.Fatalf("unexpected commit %s: %v", sha, err)
+	}
+}
+
+func TestRepoCacheNetworkOff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repocache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer fs.RemoveAllTmp()
+
+	clones := 0
+	c := NewRepoCache(dir, makeClones(t, &clones), time.Hour, 0)
+	cloneInto(t, c, "github.com/org/repo//base?ref=v1")
+
+	// Expired clones are used, as they can't be made again.
+	o := DefaultOptions()
+	o.Network = false
+	c.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	cloneIntoWith(t, c, o, "github.com/org/repo//base?ref=v1")
+	if clones != 1 {
+		t.Fatalf("expected the expired clone to be used, got %d", clones)
+	}
+}
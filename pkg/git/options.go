@@ -0,0 +1,48 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+// Options say how a build clones remote bases, and
+// whether it may use the network at all.  A RepoSpec
+// keeps the Options it was made with, for the Cloner
+// and Resolver given it to follow.
+type Options struct {
+	// Network is false if builds may not use the network,
+	// e.g. a hermetic build, so that cloning remote bases,
+	// and fetching URLs and artifacts, fails fast.
+	Network bool
+	// Retries is how many times a failed clone of a
+	// remote base, or fetch of a URL, is tried again.
+	Retries int
+	// CloneDepth is the commits of history cloned of repos
+	// whose URLs don't specify a depth; zero means all of it.
+	CloneDepth int
+	// SparseClone makes clones of repos check out only the
+	// directory of the base, and the files in its ancestors,
+	// fetching the content of no other files.
+	SparseClone bool
+	// RewriteRules redirect the repositories of remote
+	// bases, e.g. to mirrors.
+	RewriteRules RewriteRules
+	// RepoCache is the cache of clones builds share.
+	RepoCache RepoCacheOptions
+	// Client is the git client that clones, ClientExec
+	// or ClientGoGit; empty means ClientExec.
+	Client string
+	// Credentials authenticate clones of private repos.
+	Credentials Credentials
+}
+
+// DefaultOptions allow the network, and make shallow,
+// uncached clones, tried once, with the git client
+// $KUSTOMIZE_GIT_CLIENT names, else the git program.
+func DefaultOptions() Options {
+	return Options{
+		Network:    true,
+		Retries:    DefaultRemoteRetries,
+		CloneDepth: DefaultCloneDepth,
+		RepoCache:  DefaultRepoCacheOptions,
+		Client:     defaultGitClient(),
+	}
+}
@@ -0,0 +1,449 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+)
+
+// RepoCacheOptions configure the cache of clones
+// that builds share.
+type RepoCacheOptions struct {
+	// Enabled makes builds clone remote bases into,
+	// and reuse them from, the cache.
+	Enabled bool
+	// Dir holds the clones; if empty, DefaultRepoCacheDir.
+	Dir string
+	// TTL is how long the clone of a ref is reused, e.g.
+	// before a branch is fetched again.  Zero means forever.
+	TTL time.Duration
+	// MaxEntries bounds the clones kept, evicting the
+	// least recently used.  Zero means no bound.
+	MaxEntries int
+}
+
+// DefaultRepoCacheOptions leave the cache off.
+var DefaultRepoCacheOptions = RepoCacheOptions{
+	TTL:        time.Hour,
+	MaxEntries: 100,
+}
+
+// DefaultRepoCacheDir is $XDG_CACHE_HOME/kustomize/repos.
+func DefaultRepoCacheDir() string {
+	return filepath.Join(pgmconfig.CacheRoot(), "repos")
+}
+
+const (
+	flagRepoCacheEnable     = "enable-repo-cache"
+	flagRepoCacheDir        = "repo-cache-dir"
+	flagRepoCacheTTL        = "repo-cache-ttl"
+	flagRepoCacheMaxEntries = "repo-cache-max-entries"
+)
+
+// AddFlagsRepoCache adds flags setting the RepoCacheOptions.
+func (o *Options) AddFlagsRepoCache(set *pflag.FlagSet) {
+	set.BoolVar(
+		&o.RepoCache.Enabled, flagRepoCacheEnable,
+		DefaultRepoCacheOptions.Enabled,
+		"Keep clones of remote bases, by URL and ref, to reuse "+
+			"in later builds.")
+	set.StringVar(
+		&o.RepoCache.Dir, flagRepoCacheDir,
+		DefaultRepoCacheOptions.Dir,
+		"Directory of the repo cache; if unspecified, "+
+			"$"+pgmconfig.XDG_CACHE_HOME+"/kustomize/repos.")
+	set.DurationVar(
+		&o.RepoCache.TTL, flagRepoCacheTTL,
+		DefaultRepoCacheOptions.TTL,
+		"How long a cached clone of a ref is reused before it's "+
+			"cloned again; 0 to reuse it until evicted.")
+	set.IntVar(
+		&o.RepoCache.MaxEntries, flagRepoCacheMaxEntries,
+		DefaultRepoCacheOptions.MaxEntries,
+		"Most clones the repo cache keeps, evicting the least "+
+			"recently used; 0 for no limit.")
+}
+
+// Cloner clones with the GitCloner, into the repo
+// cache if it's enabled.
+func (o Options) Cloner() Cloner {
+	c := o.RepoCache
+	if !c.Enabled {
+		return o.GitCloner()
+	}
+	dir := c.Dir
+	if dir == "" {
+		dir = DefaultRepoCacheDir()
+	}
+	return NewRepoCache(
+		dir, o.GitCloner(), c.TTL, c.MaxEntries).Clone
+}
+
+// RepoCache keeps the clones another Cloner makes in
+// a directory, keyed by repo and ref, for builds in
+// other processes to reuse.  Each clone is a directory
+// named by a hash of its key, next to a file of the
+// same name, with a .json extension, recording it, and
+// one with a .lock extension, locking it.
+// Unlike a CachingCloner's, the clones outlive the
+// process, and are only removed once expired or evicted.
+// Clones of the same key, e.g. of two bases in one repo
+// at one ref, are made one at a time, so the later finds
+// the clone of the earlier.
+//
+// A build reading a clone holds a shared lock on it,
+// until the Cleaner of its RepoSpec runs.  A clone is
+// only replaced or removed under an exclusive lock, so
+// never while a build, in any process, reads it; a build
+// finding the clone it would replace in use keeps its own,
+// and eviction passes over it.
+type RepoCache struct {
+	dir        string
+	clone      Cloner
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu   sync.Mutex
+	keys map[string]*sync.Mutex
+}
+
+// repoCacheEntry records a clone.
+type repoCacheEntry struct {
+	Url      string    `json:"url"`
+	Ref      string    `json:"ref"`
+	Commit   string    `json:"commit"`
+	ClonedAt time.Time `json:"clonedAt"`
+}
+
+// NewRepoCache returns a RepoCache in dir using clone.
+func NewRepoCache(
+	dir string, clone Cloner,
+	ttl time.Duration, maxEntries int) *RepoCache {
+	return &RepoCache{
+		dir:        dir,
+		clone:      clone,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		keys:       make(map[string]*sync.Mutex),
+	}
+}
+
+// lock locks the key, returning the func unlocking it.
+func (c *RepoCache) lock(key string) func() {
+	c.mu.Lock()
+	l, ok := c.keys[key]
+	if !ok {
+		l = &sync.Mutex{}
+		c.keys[key] = l
+	}
+	c.mu.Unlock()
+	l.Lock()
+	return l.Unlock
+}
+
+// Clone is a Cloner.
+func (c *RepoCache) Clone(repoSpec *RepoSpec) error {
+	root, err := c.root()
+	if err != nil {
+		return err
+	}
+	key := repoSpec.cacheKey()
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:16])
+	dir := filepath.Join(root, name)
+	defer c.lock(key)()
+	network := repoSpec.options().Network
+	l, err := lockShared(dir + ".lock")
+	if err != nil {
+		return err
+	}
+	if e, ok := c.read(dir, network); ok {
+		now := c.now()
+		os.Chtimes(dir+".json", now, now)
+		repoSpec.Dir = fs.ConfirmedDir(dir)
+		repoSpec.Ref = e.Ref
+		repoSpec.Commit = e.Commit
+		repoSpec.Cached = true
+		repoSpec.unlock = l.release
+		return nil
+	}
+	l.release()
+	if err := c.clone(repoSpec); err != nil {
+		return err
+	}
+	l = tryLockExclusive(dir + ".lock")
+	if l == nil {
+		// Another build reads the stale clone;
+		// this build uses its own.
+		return nil
+	}
+	if _, ok := c.read(dir, network); ok {
+		// Another build cached it meanwhile.
+		l.release()
+		return nil
+	}
+	os.Remove(dir + ".json")
+	os.RemoveAll(dir)
+	if err := moveDir(repoSpec.Dir.String(), dir); err != nil {
+		l.release()
+		return nil
+	}
+	fs.RemoveTmp(repoSpec.Dir.String())
+	repoSpec.Dir = fs.ConfirmedDir(dir)
+	repoSpec.Cached = true
+	now := c.now()
+	data, err := json.Marshal(repoCacheEntry{
+		Url:      repoSpec.CloneSpec(),
+		Ref:      repoSpec.Ref,
+		Commit:   repoSpec.Commit,
+		ClonedAt: now,
+	})
+	if err == nil {
+		err = writeAtomically(dir+".json", data)
+	}
+	if err == nil {
+		err = l.downgrade()
+	}
+	if err != nil {
+		l.release()
+		return err
+	}
+	repoSpec.unlock = l.release
+	// The record's time is when the clone was last used.
+	os.Chtimes(dir+".json", now, now)
+	return c.evict(root)
+}
+
+// root makes the cache's directory, returning
+// it as an absolute path without symlinks.
+func (c *RepoCache) root() (string, error) {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return "", err
+	}
+	dir, err := filepath.Abs(c.dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(dir)
+}
+
+// read returns the record of the clone in dir, if any,
+// and whether it's usable: the clone is there and hasn't
+// expired, or, as it can't be cloned again, network is
+// false.  A record that isn't usable is stale.
+func (c *RepoCache) read(dir string, network bool) (*repoCacheEntry, bool) {
+	data, err := ioutil.ReadFile(dir + ".json")
+	if err != nil {
+		return nil, false
+	}
+	var e repoCacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return &e, false
+	}
+	if network && c.ttl > 0 && c.now().Sub(e.ClonedAt) >= c.ttl {
+		return &e, false
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return &e, false
+	}
+	return &e, true
+}
+
+// evict removes the expired clones, and the lock files
+// of clones that are gone, then the least recently used
+// clones beyond the most the cache may keep, passing
+// over those in use.
+func (c *RepoCache) evict(root string) error {
+	entries, err := c.entries(root)
+	if err != nil {
+		return err
+	}
+	var kept []repoCacheUse
+	for _, u := range entries {
+		if _, ok := c.read(u.dir, true); !ok {
+			c.remove(u.dir)
+			continue
+		}
+		kept = append(kept, u)
+	}
+	if c.maxEntries <= 0 || len(kept) <= c.maxEntries {
+		return nil
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].at.Before(kept[j].at)
+	})
+	// Those in use, e.g. the clone just made, are
+	// passed over for the next least recently used.
+	excess := len(kept) - c.maxEntries
+	for _, u := range kept {
+		if excess == 0 {
+			break
+		}
+		if c.remove(u.dir) {
+			excess--
+		}
+	}
+	return nil
+}
+
+// repoCacheUse is when a clone, named by its
+// directory, was last used.
+type repoCacheUse struct {
+	dir string
+	at  time.Time
+}
+
+// entries returns the clones in the cache, and those
+// with only a lock file left, as used when last locked.
+func (c *RepoCache) entries(root string) ([]repoCacheUse, error) {
+	files, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	at := make(map[string]time.Time)
+	for _, f := range files {
+		ext := filepath.Ext(f.Name())
+		if ext != ".json" && ext != ".lock" {
+			continue
+		}
+		dir := filepath.Join(root, strings.TrimSuffix(f.Name(), ext))
+		if _, ok := at[dir]; !ok || ext == ".json" {
+			at[dir] = f.ModTime()
+		}
+	}
+	var uses []repoCacheUse
+	for dir, t := range at {
+		uses = append(uses, repoCacheUse{dir, t})
+	}
+	return uses, nil
+}
+
+// remove removes the clone in dir, its record and lock
+// file, unless a build is using it, saying if it did.
+func (c *RepoCache) remove(dir string) bool {
+	l := tryLockExclusive(dir + ".lock")
+	if l == nil {
+		return false
+	}
+	os.Remove(dir + ".json")
+	os.RemoveAll(dir)
+	l.remove()
+	return true
+}
+
+// CleanRepoCache removes the clones of the repo cache
+// in dir last used longer ago than olderThan, but for
+// those builds are using, returning their directories.
+func CleanRepoCache(dir string, olderThan time.Duration) ([]string, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+	c := NewRepoCache(dir, nil, 0, 0)
+	root, err := c.root()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := c.entries(root)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, u := range entries {
+		if c.now().Sub(u.at) >= olderThan && c.remove(u.dir) {
+			removed = append(removed, u.dir)
+		}
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
+
+func writeAtomically(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// moveDir moves the directory from to the absent
+// directory to, copying it if it's on another device.
+func moveDir(from, to string) error {
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+	if _, err := os.Lstat(to); err == nil {
+		return os.ErrExist
+	}
+	err := copyDir(from, to)
+	if err != nil {
+		os.RemoveAll(to)
+	}
+	return err
+}
+
+func copyDir(from, to string) error {
+	return filepath.Walk(from, func(
+		path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(from, to string, mode os.FileMode) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
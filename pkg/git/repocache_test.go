@@ -0,0 +1,241 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+// makeClones returns a cloner that writes a file in a
+// new temporary directory, counting the clones.
+func makeClones(t *testing.T, clones *int) Cloner {
+	return func(rs *RepoSpec) error {
+		*clones++
+		dir, err := fs.NewTmpConfirmedDir()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		os.MkdirAll(dir.Join("base"), 0700)
+		ioutil.WriteFile(
+			dir.Join("base/kustomization.yaml"), []byte(rs.Ref), 0600)
+		rs.Dir = dir
+		rs.Commit = "1111111111111111111111111111111111111111"
+		return nil
+	}
+}
+
+// cloneInto clones the url with c, as a build that
+// then ends would, releasing the clone.
+func cloneInto(t *testing.T, c *RepoCache, url string) *RepoSpec {
+	return cloneIntoWith(t, c, DefaultOptions(), url)
+}
+
+func cloneIntoWith(
+	t *testing.T, c *RepoCache, o Options, url string) *RepoSpec {
+	rs := cloneHeld(t, c, o, url)
+	if err := rs.Cleaner(fs.MakeRealFS())(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return rs
+}
+
+// cloneHeld clones the url with c, as a build still
+// reading the clone would.
+func cloneHeld(
+	t *testing.T, c *RepoCache, o Options, url string) *RepoSpec {
+	rs, err := o.NewRepoSpecFromUrl(url)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Clone(rs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rs.Cached ||
+		rs.Commit != "1111111111111111111111111111111111111111" {
+		t.Fatalf("unexpected repoSpec %+v", rs)
+	}
+	data, err := ioutil.ReadFile(rs.AbsPath() + "/kustomization.yaml")
+	if err != nil || string(data) != rs.Ref {
+		t.Fatalf("unexpected clone %s: %v", data, err)
+	}
+	return rs
+}
+
+func TestRepoCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repocache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer fs.RemoveAllTmp()
+
+	clones := 0
+	c := NewRepoCache(dir, makeClones(t, &clones), time.Hour, 0)
+	rs := cloneInto(t, c, "github.com/org/repo//base?ref=v1")
+	if filepath.Dir(rs.Dir.String()) != dir {
+		t.Fatalf("clone %s isn't in the cache", rs.Dir)
+	}
+	if err := rs.Cleaner(fs.MakeRealFS())(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cloneInto(t, c, "github.com/org/repo//base?ref=v2")
+
+	// Another process reuses the clones.
+	c = NewRepoCache(dir, makeClones(t, &clones), time.Hour, 0)
+	cloneInto(t, c, "github.com/org/repo//base?ref=v1")
+	cloneInto(t, c, "github.com/org/repo//base?ref=v2")
+	if clones != 2 {
+		t.Fatalf("expected one clone per ref, got %d", clones)
+	}
+
+	// Expired clones are made again.
+	c.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	cloneInto(t, c, "github.com/org/repo//base?ref=v1")
+	if clones != 3 {
+		t.Fatalf("expected an expired clone to be redone, got %d", clones)
+	}
+}
+
+func TestRepoCacheEvicts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repocache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer fs.RemoveAllTmp()
+
+	clones := 0
+	c := NewRepoCache(dir, makeClones(t, &clones), time.Hour, 2)
+	now := time.Now()
+	for i, ref := range []string{"v1", "v2", "v1", "v3"} {
+		c.now = func() time.Time {
+			return now.Add(time.Duration(i) * time.Minute)
+		}
+		cloneInto(t, c, "github.com/org/repo//base?ref="+ref)
+	}
+	if clones != 3 {
+		t.Fatalf("expected 3 clones, got %d", clones)
+	}
+	// v2 was the least recently used.
+	cloneInto(t, c, "github.com/org/repo//base?ref=v1")
+	cloneInto(t, c, "github.com/org/repo//base?ref=v3")
+	if clones != 3 {
+		t.Fatalf("expected v1 and v3 to be kept, got %d clones", clones)
+	}
+	cloneInto(t, c, "github.com/org/repo//base?ref=v2")
+	if clones != 4 {
+		t.Fatalf("expected v2 to be evicted, got %d clones", clones)
+	}
+	records, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(records) != 2 {
+		t.Fatalf("expected 2 clones in the cache, got %d", len(records))
+	}
+}
+
+func TestRepoCacheClonesKeyOnce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repocache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer fs.RemoveAllTmp()
+
+	// Two bases of a repo at a ref, cloned at once,
+	// share a clone, rather than the later replacing
+	// the clone the earlier is reading.
+	var mu sync.Mutex
+	clones := 0
+	clone := makeClones(t, &clones)
+	c := NewRepoCache(dir, func(rs *RepoSpec) error {
+		mu.Lock()
+		defer mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		return clone(rs)
+	}, time.Hour, 0)
+	specs := make([]*RepoSpec, 2)
+	var wg sync.WaitGroup
+	for i, path := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			rs, err := NewRepoSpecFromUrl(
+				"github.com/org/repo//" + path + "?ref=v1")
+			if err == nil {
+				err = c.Clone(rs)
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			specs[i] = rs
+		}(i, path)
+	}
+	wg.Wait()
+	if clones != 1 {
+		t.Fatalf("expected 1 clone, got %d", clones)
+	}
+	if specs[0].Dir != specs[1].Dir || !specs[1].Cached {
+		t.Fatalf("expected a shared clone, got %s and %s",
+			specs[0].Dir, specs[1].Dir)
+	}
+	if _, err := os.Stat(specs[0].Dir.Join("base")); err != nil {
+		t.Fatalf("expected the clone kept: %v", err)
+	}
+}
+
+func TestRepoCacheSparesClonesInUse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repocache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer fs.RemoveAllTmp()
+
+	clones := 0
+	c := NewRepoCache(dir, makeClones(t, &clones), time.Hour, 1)
+	held := cloneHeld(t, c, DefaultOptions(), "github.com/org/repo//base?ref=v1")
+
+	// Another build, finding the clone it would replace
+	// in use, uses its own; nor is the clone evicted.
+	later := NewRepoCache(dir, makeClones(t, &clones), time.Nanosecond, 1)
+	rs, err := NewRepoSpecFromUrl("github.com/org/repo//base?ref=v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := later.Clone(rs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rs.Cached || rs.Dir == held.Dir {
+		t.Fatalf("expected a clone of its own, got %s", rs.Dir)
+	}
+	rs.Cleaner(fs.MakeRealFS())()
+	cloneInto(t, later, "github.com/org/repo//base?ref=v2")
+	if _, err := os.Stat(held.AbsPath()); err != nil {
+		t.Fatalf("expected the clone in use kept: %v", err)
+	}
+	removed, err := CleanRepoCache(dir, 0)
+	if err != nil || len(removed) != 1 {
+		t.Fatalf("expected v2 alone removed, got %v, %v", removed, err)
+	}
+
+	// Once released, it's replaced.
+	held.Cleaner(fs.MakeRealFS())()
+	cloneInto(t, later, "github.com/org/repo//base?ref=v1")
+	if clones != 4 {
+		t.Fatalf("expected the released clone replaced, got %d clones", clones)
+	}
+	removed, err = CleanRepoCache(dir, 0)
+	if err != nil || len(removed) != 1 {
+		t.Fatalf("expected v1 removed, got %v, %v", removed, err)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Fatalf("expected an empty cache, got %v", files)
+	}
+}
@@ -18,8 +18,10 @@ package git
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
@@ -55,8 +57,45 @@ type RepoSpec struct {
 	// Branch or tag reference.
 	Ref string
 
+	// Depth is the commits of history to clone, from the
+	// URL's depth query parameter; zero means the
+	// CloneDepth of its Options.
+	Depth int
+
+	// Sha256 is the TreeDigest the directory at Path must
+	// have once cloned, from the URL's sha256 query
+	// parameter, or empty if the URL doesn't pin it.
+	Sha256 string
+
+	// Submodules is true if the clone initializes the
+	// repo's submodules, recursively, from the URL's
+	// submodules query parameter.
+	Submodules bool
+
+	// Commit is the SHA of the commit cloned, once cloned.
+	Commit string
+
+	// Cached is true if Dir belongs to a cache outliving
+	// the loader using it, so its Cleaner leaves Dir be.
+	Cached bool
+
+	// unlock, if not nil, releases the lock the cache
+	// holds Dir by while the loader uses it.
+	unlock func()
+
 	// e.g. .git or empty in case of _git is present
 	GitSuffix string
+
+	// opts are the Options the spec was made with;
+	// if nil, the DefaultOptions.
+	opts *Options
+}
+
+func (x *RepoSpec) options() Options {
+	if x.opts == nil {
+		return DefaultOptions()
+	}
+	return *x.opts
 }
 
 // CloneSpec returns a string suitable for "git clone {spec}".
@@ -67,6 +106,43 @@ func (x *RepoSpec) CloneSpec() string {
 	return x.Host + x.OrgRepo + x.GitSuffix
 }
 
+// CloneDepth returns the commits of history to clone,
+// or zero for all of it.
+func (x *RepoSpec) CloneDepth() int {
+	if x.Depth > 0 {
+		return x.Depth
+	}
+	return x.options().CloneDepth
+}
+
+// sparsePath returns the directory a sparse clone
+// checks out, relative to the top of the repo, or
+// empty if the whole repo is checked out.
+func (x *RepoSpec) sparsePath() string {
+	if !x.options().SparseClone {
+		return ""
+	}
+	p := strings.Trim(path.Clean("/"+filepath.ToSlash(x.Path)), "/")
+	if p == "" || strings.ContainsAny(p, "*?[!\\") {
+		return ""
+	}
+	return p
+}
+
+// cacheKey identifies the clones of the spec that
+// caches may share.
+func (x *RepoSpec) cacheKey() string {
+	key := fmt.Sprintf(
+		"%s?ref=%s&depth=%d", x.CloneSpec(), x.Ref, x.CloneDepth())
+	if p := x.sparsePath(); p != "" {
+		key += "&sparse=" + p
+	}
+	if x.Submodules {
+		key += "&submodules=true"
+	}
+	return key
+}
+
 func (x *RepoSpec) CloneDir() fs.ConfirmedDir {
 	return x.Dir
 }
@@ -80,26 +156,56 @@ func (x *RepoSpec) AbsPath() string {
 }
 
 func (x *RepoSpec) Cleaner(fSys fs.FileSystem) func() error {
+	if x.Cached {
+		return func() error {
+			if x.unlock != nil {
+				x.unlock()
+				x.unlock = nil
+			}
+			return nil
+		}
+	}
 	return func() error { return fSys.RemoveAll(x.Dir.String()) }
 }
 
 // From strings like git@github.com:someOrg/someRepo.git or
 // https://github.com/someOrg/someRepo?ref=someHash, extract
-// the parts.
+// the parts, with the DefaultOptions.
 func NewRepoSpecFromUrl(n string) (*RepoSpec, error) {
+	return DefaultOptions().NewRepoSpecFromUrl(n)
+}
+
+// NewRepoSpecFromUrl is like the function of that name,
+// but redirects the repo per the RewriteRules, and keeps
+// the Options for cloning it.
+func (o Options) NewRepoSpecFromUrl(n string) (*RepoSpec, error) {
 	if filepath.IsAbs(n) {
 		return nil, fmt.Errorf("uri looks like abs path: %s", n)
 	}
-	host, orgRepo, path, gitRef, gitSuffix := parseGithubUrl(n)
+	url, depth, err := peelDepth(n)
+	if err != nil {
+		return nil, err
+	}
+	url, sum, err := peelSha256(url)
+	if err != nil {
+		return nil, err
+	}
+	url, submodules, err := peelSubmodules(url)
+	if err != nil {
+		return nil, err
+	}
+	host, orgRepo, path, gitRef, gitSuffix := parseGithubUrl(url)
 	if orgRepo == "" {
 		return nil, fmt.Errorf("url lacks orgRepo: %s", n)
 	}
 	if host == "" {
 		return nil, fmt.Errorf("url lacks host: %s", n)
 	}
+	host, orgRepo = o.RewriteRules.rewrite(host, orgRepo)
 	return &RepoSpec{
 		raw: n, Host: host, OrgRepo: orgRepo,
-		Dir: notCloned, Path: path, Ref: gitRef, GitSuffix: gitSuffix}, nil
+		Dir: notCloned, Path: path, Ref: gitRef, GitSuffix: gitSuffix,
+		Depth: depth, Sha256: sum, Submodules: submodules, opts: &o}, nil
 }
 
 const (
@@ -160,6 +266,72 @@ func peelQuery(arg string) (string, string) {
 	return arg, ""
 }
 
+// peelDepth removes the depth query parameter from
+// the url, e.g. someRepo?ref=v1&depth=5, returning it.
+func peelDepth(n string) (string, int, error) {
+	url, value, ok := peelParam(n, "depth")
+	if !ok {
+		return n, 0, nil
+	}
+	depth, err := strconv.Atoi(value)
+	if err != nil || depth < 1 {
+		return "", 0, fmt.Errorf(
+			"url has depth %q, rather than a positive number: %s",
+			value, n)
+	}
+	return url, depth, nil
+}
+
+var sha256Regex = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// peelSha256 removes the sha256 query parameter from
+// the url, e.g. someRepo?ref=v1&sha256=3d4a..., returning it.
+func peelSha256(n string) (string, string, error) {
+	url, value, ok := peelParam(n, "sha256")
+	if !ok {
+		return n, "", nil
+	}
+	if !sha256Regex.MatchString(value) {
+		return "", "", fmt.Errorf(
+			"url has sha256 %q, rather than 64 hex digits: %s",
+			value, n)
+	}
+	return url, value, nil
+}
+
+// peelSubmodules removes the submodules query parameter
+// from the url, e.g. someRepo?ref=v1&submodules=true,
+// returning it.
+func peelSubmodules(n string) (string, bool, error) {
+	url, value, ok := peelParam(n, "submodules")
+	if !ok {
+		return n, false, nil
+	}
+	submodules, err := strconv.ParseBool(value)
+	if err != nil {
+		return "", false, fmt.Errorf(
+			"url has submodules %q, rather than true or false: %s",
+			value, n)
+	}
+	return url, submodules, nil
+}
+
+// peelParam removes the named query parameter from
+// the url, returning its value, and whether it had one.
+func peelParam(n, name string) (string, string, bool) {
+	r := regexp.MustCompile("[?&]" + name + "=([^&]*)")
+	m := r.FindStringSubmatchIndex(n)
+	if m == nil {
+		return n, "", false
+	}
+	rest := n[m[1]:]
+	if n[m[0]] == '?' && rest != "" {
+		// Keep the query's other parameters.
+		rest = "?" + rest[1:]
+	}
+	return n[:m[0]] + rest, n[m[2]:m[3]], true
+}
+
 func parseHostSpec(n string) (string, string) {
 	var host string
 	// Start accumulating the host part.
@@ -120,6 +120,116 @@ func TestNewRepoSpecFromUrlErrors(t *testing.T) {
 	}
 }
 
+func TestNewRepoSpecFromUrlDepth(t *testing.T) {
+	testcases := []struct {
+		input string
+		path  string
+		ref   string
+		depth int
+	}{
+		{"github.com/org/repo//base", "/base", "", 0},
+		{"github.com/org/repo//base?depth=5", "/base", "", 5},
+		{"github.com/org/repo//base?ref=v1&depth=5", "/base", "v1", 5},
+		{"github.com/org/repo//base?depth=5&ref=v1", "/base", "v1", 5},
+		{"github.com/org/repo.git?version=v1&depth=2", "", "v1", 2},
+	}
+	for _, tc := range testcases {
+		rs, err := NewRepoSpecFromUrl(tc.input)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.input, err)
+		}
+		if rs.Path != tc.path || rs.Ref != tc.ref || rs.Depth != tc.depth ||
+			rs.OrgRepo != "org/repo" || rs.Raw() != tc.input {
+			t.Errorf("%s: unexpected repoSpec %+v", tc.input, rs)
+		}
+	}
+	for _, bad := range []string{
+		"github.com/org/repo?depth=0",
+		"github.com/org/repo?ref=v1&depth=all",
+	} {
+		_, err := NewRepoSpecFromUrl(bad)
+		if err == nil || !strings.Contains(err.Error(), "positive number") {
+			t.Errorf("%s: unexpected error: %v", bad, err)
+		}
+	}
+}
+
+func TestNewRepoSpecFromUrlSubmodules(t *testing.T) {
+	testcases := []struct {
+		input      string
+		ref        string
+		submodules bool
+	}{
+		{"github.com/org/repo//base", "", false},
+		{"github.com/org/repo//base?submodules=true", "", true},
+		{"github.com/org/repo//base?ref=v1&submodules=1", "v1", true},
+		{"github.com/org/repo//base?submodules=false&ref=v1", "v1", false},
+	}
+	for _, tc := range testcases {
+		rs, err := NewRepoSpecFromUrl(tc.input)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.input, err)
+		}
+		if rs.Path != "/base" || rs.Ref != tc.ref ||
+			rs.Submodules != tc.submodules || rs.OrgRepo != "org/repo" {
+			t.Errorf("%s: unexpected repoSpec %+v", tc.input, rs)
+		}
+	}
+	_, err := NewRepoSpecFromUrl("github.com/org/repo?submodules=yes")
+	if err == nil || !strings.Contains(err.Error(), "true or false") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewRepoSpecFromUrlSha256(t *testing.T) {
+	sum := strings.Repeat("3d", 32)
+	testcases := []struct {
+		input string
+		ref   string
+		depth int
+	}{
+		{"github.com/org/repo//base?sha256=" + sum, "", 0},
+		{"github.com/org/repo//base?ref=v1&sha256=" + sum, "v1", 0},
+		{"github.com/org/repo//base?sha256=" + sum + "&ref=v1&depth=2", "v1", 2},
+	}
+	for _, tc := range testcases {
+		rs, err := NewRepoSpecFromUrl(tc.input)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.input, err)
+		}
+		if rs.Path != "/base" || rs.Ref != tc.ref || rs.Depth != tc.depth ||
+			rs.Sha256 != sum || rs.Raw() != tc.input {
+			t.Errorf("%s: unexpected repoSpec %+v", tc.input, rs)
+		}
+	}
+	for _, bad := range []string{
+		"github.com/org/repo?sha256=3d4a",
+		"github.com/org/repo?ref=v1&sha256=" + strings.ToUpper(sum),
+	} {
+		_, err := NewRepoSpecFromUrl(bad)
+		if err == nil || !strings.Contains(err.Error(), "64 hex digits") {
+			t.Errorf("%s: unexpected error: %v", bad, err)
+		}
+	}
+}
+
+func TestCloneDepth(t *testing.T) {
+	rs := &RepoSpec{}
+	if rs.CloneDepth() != 1 {
+		t.Fatalf("expected depth 1, got %d", rs.CloneDepth())
+	}
+	full := DefaultOptions()
+	full.CloneDepth = 0
+	rs.opts = &full
+	if rs.CloneDepth() != 0 {
+		t.Fatalf("expected full history, got depth %d", rs.CloneDepth())
+	}
+	rs.Depth = 5
+	if rs.CloneDepth() != 5 {
+		t.Fatalf("expected the URL's depth 5, got %d", rs.CloneDepth())
+	}
+}
+
 func TestNewRepoSpecFromUrl_CloneSpecs(t *testing.T) {
 	testcases := []struct {
 		input     string
@@ -0,0 +1,78 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Resolver returns the SHA of the commit that the
+// ref of a repo currently names upstream.
+type Resolver func(repoSpec *RepoSpec) (string, error)
+
+var commitSha = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// ResolverUsingGitExec uses a local git install to
+// ask the remote repo, without cloning it.
+func ResolverUsingGitExec(repoSpec *RepoSpec) (string, error) {
+	ref := repoSpec.Ref
+	if ref == "" {
+		ref = "master"
+	}
+	if commitSha.MatchString(ref) {
+		// A commit can't move.
+		return ref, nil
+	}
+	err := repoSpec.options().ErrIfOffline("resolve", repoSpec.CloneSpec())
+	if err != nil {
+		return "", err
+	}
+	gitProgram, err := exec.LookPath("git")
+	if err != nil {
+		return "", errors.Wrap(err, "no 'git' program on path")
+	}
+	cmd := exec.Command(
+		gitProgram,
+		"ls-remote",
+		repoSpec.CloneSpec(),
+		ref,
+		ref+"^{}")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err = cmd.Run()
+	if err != nil {
+		return "", errors.Wrapf(
+			err, "trouble listing refs of %s", repoSpec.CloneSpec())
+	}
+	return parseLsRemote(out.String(), ref)
+}
+
+// parseLsRemote returns the commit of the ref in the
+// output of git ls-remote, preferring that of the
+// peeled ref, i.e. the commit an annotated tag names.
+func parseLsRemote(out, ref string) (string, error) {
+	var sha string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.HasSuffix(fields[1], "^{}") {
+			return fields[0], nil
+		}
+		if sha == "" {
+			sha = fields[0]
+		}
+	}
+	if sha == "" {
+		return "", fmt.Errorf("ref '%s' not found", ref)
+	}
+	return sha, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"testing"
+)
+
+func TestParseLsRemote(t *testing.T) {
+	tests := map[string]struct {
+		out      string
+		expected string
+	}{
+		"branch": {
+			out:      "1111111111111111111111111111111111111111\trefs/heads/master\n",
+			expected: "1111111111111111111111111111111111111111",
+		},
+		"annotated tag": {
+			out: "2222222222222222222222222222222222222222\trefs/tags/v1\n" +
+				"3333333333333333333333333333333333333333\trefs/tags/v1^{}\n",
+			expected: "3333333333333333333333333333333333333333",
+		},
+	}
+	for n, tc := range tests {
+		sha, err := parseLsRemote(tc.out, "v1")
+		if err != nil || sha != tc.expected {
+			t.Fatalf("%s: expected %s, got %s, %v", n, tc.expected, sha, err)
+		}
+	}
+	if _, err := parseLsRemote("", "v1"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestResolverUsingGitExecCommit(t *testing.T) {
+	sha := "4444444444444444444444444444444444444444"
+	actual, err := ResolverUsingGitExec(&RepoSpec{Ref: sha})
+	if err != nil || actual != sha {
+		t.Fatalf("expected %s, got %s, %v", sha, actual, err)
+	}
+}
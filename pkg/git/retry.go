@@ -0,0 +1,80 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"log"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+)
+
+// DefaultRemoteRetries is the retries of a failed clone
+// or fetch; none, so that a build fails as fast as it
+// would without the flag.
+const DefaultRemoteRetries = 0
+
+const (
+	flagRemoteRetries = "remote-retries"
+
+	// maxRetryDelay bounds the wait between tries.
+	maxRetryDelay = 30 * time.Second
+)
+
+// retryDelay is the wait before the first retry, doubled
+// before each after it; tests shorten it.
+var retryDelay = time.Second
+
+// AddFlagRemoteRetries adds the flag setting the retries.
+func (o *Options) AddFlagRemoteRetries(set *pflag.FlagSet) {
+	set.IntVar(
+		&o.Retries, flagRemoteRetries, DefaultRemoteRetries,
+		"Times to try again a failed clone of a remote base, or fetch "+
+			"of a URL, waiting 1s, then twice as long each time after; "+
+			"e.g. for CI builds that git hosts sometimes fail.")
+}
+
+// errPermanent is the kind of error that trying
+// again wouldn't fix.
+var errPermanent = errors.New("permanent failure")
+
+// Permanent marks err as one Retry shouldn't try
+// again after, e.g. a 404.
+func Permanent(err error) error {
+	return kusterr.WithKind(errPermanent, err)
+}
+
+// isPermanent is true if err, or an error it wraps,
+// is marked Permanent.
+func isPermanent(err error) bool {
+	return kusterr.Is(err, errPermanent)
+}
+
+// Retry calls f until it succeeds, fails Permanent, or
+// has failed o.Retries times more than once, waiting
+// longer between each call than before.  The error of
+// the last call is returned, saying, if f was called
+// more than once, how many times.
+func (o Options) Retry(f func() error) error {
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if attempt > o.Retries || isPermanent(err) {
+			if attempt == 1 {
+				return err
+			}
+			return errors.Wrapf(err, "failed after %d attempts", attempt)
+		}
+		log.Printf("warning: %v; trying again in %v", err, delay)
+		time.Sleep(delay)
+		if delay *= 2; delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
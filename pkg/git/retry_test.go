@@ -0,0 +1,63 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestRetry(t *testing.T) {
+	saved := retryDelay
+	retryDelay = time.Millisecond
+	defer func() { retryDelay = saved }()
+	o := DefaultOptions()
+
+	// failing fails the first n calls.
+	failing := func(n int, err error) (func() error, *int) {
+		calls := 0
+		return func() error {
+			calls++
+			if calls <= n {
+				return err
+			}
+			return nil
+		}, &calls
+	}
+	flaky := fmt.Errorf("trouble fetching master")
+
+	f, calls := failing(1, flaky)
+	if err := o.Retry(f); err != flaky || *calls != 1 {
+		t.Fatalf("unexpected error %v after %d calls", err, *calls)
+	}
+
+	o.Retries = 3
+	f, calls = failing(2, flaky)
+	if err := o.Retry(f); err != nil || *calls != 3 {
+		t.Fatalf("unexpected error %v after %d calls", err, *calls)
+	}
+	f, calls = failing(10, flaky)
+	err := o.Retry(f)
+	if err == nil || *calls != 4 ||
+		err.Error() != "failed after 4 attempts: trouble fetching master" {
+		t.Fatalf("unexpected error %v after %d calls", err, *calls)
+	}
+
+	// Permanent errors, even wrapped, aren't tried again.
+	f, calls = failing(10, errors.Wrap(
+		Permanent(fmt.Errorf("404 Not Found")), "fetching app.yaml"))
+	err = o.Retry(f)
+	if err == nil || *calls != 1 ||
+		err.Error() != "fetching app.yaml: 404 Not Found" {
+		t.Fatalf("unexpected error %v after %d calls", err, *calls)
+	}
+	o.Network = false
+	f, calls = failing(10, o.ErrIfOffline("clone", "github.com/org/repo"))
+	if err = o.Retry(f); err == nil || *calls != 1 {
+		t.Fatalf("unexpected error %v after %d calls", err, *calls)
+	}
+}
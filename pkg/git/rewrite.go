@@ -0,0 +1,119 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/yaml"
+)
+
+// RewriteRule redirects the repositories of remote
+// bases, e.g. to a mirror.
+//
+//	from: github.com/someOrg/*
+//	to: git.example.com/mirror/someOrg/*
+//
+// A repository matches if its host and org/repo, less
+// any scheme like https://, start with From, less any
+// trailing *.  The matching part is replaced by To,
+// less any trailing *.  The path in the repository
+// and the ref are kept.  The scheme is kept too,
+// unless To has one.
+type RewriteRule struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// RewriteRules are tried in order; the first match wins.
+type RewriteRules []RewriteRule
+
+// RewriteRulesPath is where LoadRewriteRules looks by default.
+func RewriteRulesPath() string {
+	return filepath.Join(pgmconfig.ConfigRoot(), "urlrewrites.yaml")
+}
+
+// LoadRewriteRules reads rules from a file like
+//
+//	rewrites:
+//	- from: github.com/someOrg/*
+//	  to: git.example.com/mirror/someOrg/*
+//
+// A missing file holds no rules.
+func LoadRewriteRules(fSys fs.FileSystem, path string) (RewriteRules, error) {
+	if !fSys.Exists(path) {
+		return nil, nil
+	}
+	data, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f struct {
+		Rewrites RewriteRules `json:"rewrites" yaml:"rewrites"`
+	}
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, errors.Wrapf(err, "reading url rewrite rules %s", path)
+	}
+	for i, r := range f.Rewrites {
+		if strings.TrimSuffix(r.From, "*") == "" || r.To == "" {
+			return nil, fmt.Errorf(
+				"url rewrite rule %d in %s needs from and to", i, path)
+		}
+	}
+	return f.Rewrites, nil
+}
+
+var rewriteSchemes = []string{"git::", "ssh://", "https://", "http://"}
+
+// Rewrite applies the first matching rule to a
+// repository URL, e.g. https://github.com/someOrg/someRepo.
+func (rules RewriteRules) Rewrite(url string) string {
+	scheme, rest := splitScheme(url)
+	for _, r := range rules {
+		from := strings.TrimSuffix(r.From, "*")
+		if !strings.HasPrefix(rest, from) {
+			continue
+		}
+		to := strings.TrimSuffix(r.To, "*")
+		if s, _ := splitScheme(to); s != "" {
+			scheme = ""
+		}
+		return scheme + to + rest[len(from):]
+	}
+	return url
+}
+
+// rewrite applies the rules to the repository of a
+// RepoSpec, splitting the result into a new host and
+// org/repo, the latter perhaps with more than two parts.
+func (rules RewriteRules) rewrite(host, orgRepo string) (string, string) {
+	url := rules.Rewrite(host + orgRepo)
+	if url == host+orgRepo {
+		return host, orgRepo
+	}
+	scheme, rest := splitScheme(url)
+	i := strings.Index(rest, "/")
+	if i < 0 {
+		return url, ""
+	}
+	return scheme + rest[:i+1], rest[i+1:]
+}
+
+// splitScheme splits prefixes like git::https://
+// from the rest of a URL.
+func splitScheme(url string) (string, string) {
+	scheme := ""
+	for _, s := range rewriteSchemes {
+		if strings.HasPrefix(strings.ToLower(url), s) {
+			scheme += url[:len(s)]
+			url = url[len(s):]
+		}
+	}
+	return scheme, url
+}
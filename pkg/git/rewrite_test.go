@@ -0,0 +1,87 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+var testRules = RewriteRules{
+	{From: "github.com/someOrg/*", To: "git.example.com/mirror/someOrg/*"},
+	{From: "github.com/*", To: "ssh://git.example.com/github/*"},
+}
+
+func TestRewrite(t *testing.T) {
+	for input, expected := range map[string]string{
+		"https://github.com/someOrg/someRepo":  "https://git.example.com/mirror/someOrg/someRepo",
+		"github.com/someOrg/someRepo":          "git.example.com/mirror/someOrg/someRepo",
+		"https://github.com/otherOrg/someRepo": "ssh://git.example.com/github/otherOrg/someRepo",
+		"https://gitlab.com/someOrg/someRepo":  "https://gitlab.com/someOrg/someRepo",
+		"git@github.com:someOrg/someRepo":      "git@github.com:someOrg/someRepo",
+	} {
+		if actual := testRules.Rewrite(input); actual != expected {
+			t.Errorf("Rewrite(%s): expected %s, got %s", input, expected, actual)
+		}
+	}
+}
+
+func TestNewRepoSpecFromUrlRewritten(t *testing.T) {
+	o := DefaultOptions()
+	o.RewriteRules = testRules
+	input := "github.com/someOrg/someRepo/foo/krusty.txt?ref=v0.1.0"
+	rs, err := o.NewRepoSpecFromUrl(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rs.Raw() != input {
+		t.Errorf("expected raw %s, got %s", input, rs.Raw())
+	}
+	if rs.CloneSpec() != "https://git.example.com/mirror/someOrg/someRepo.git" {
+		t.Errorf("unexpected clone spec %s", rs.CloneSpec())
+	}
+	if rs.Path != "foo/krusty.txt" || rs.Ref != "v0.1.0" {
+		t.Errorf("unexpected path %s or ref %s", rs.Path, rs.Ref)
+	}
+	rs, err = o.NewRepoSpecFromUrl("github.com/otherOrg/otherRepo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rs.CloneSpec() != "ssh://git.example.com/github/otherOrg/otherRepo.git" {
+		t.Errorf("unexpected clone spec %s", rs.CloneSpec())
+	}
+}
+
+func TestLoadRewriteRules(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	rules, err := LoadRewriteRules(fSys, "/urlrewrites.yaml")
+	if err != nil || rules != nil {
+		t.Fatalf("expected no rules and no error, got %v, %v", rules, err)
+	}
+	fSys.WriteFile("/urlrewrites.yaml", []byte(`
+rewrites:
+- from: github.com/someOrg/*
+  to: git.example.com/mirror/someOrg/*
+`))
+	rules, err = LoadRewriteRules(fSys, "/urlrewrites.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 1 || rules[0] != testRules[0] {
+		t.Fatalf("unexpected rules %v", rules)
+	}
+	for content, msg := range map[string]string{
+		"rewrites:\n- from: '*'\n  to: x\n": "needs from and to",
+		"rewrites:\n- from: x\n":            "needs from and to",
+		"rewrite: []\n":                     "unknown field",
+	} {
+		fSys.WriteFile("/urlrewrites.yaml", []byte(content))
+		_, err := LoadRewriteRules(fSys, "/urlrewrites.yaml")
+		if err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%q: unexpected error %v", content, err)
+		}
+	}
+}
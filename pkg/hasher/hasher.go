@@ -4,6 +4,7 @@
 package hasher
 
 import (
+	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
@@ -50,3 +51,11 @@ func Encode(hex string) (string, error) {
 func Hash(data string) string {
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(data)))
 }
+
+// HMAC returns the hex form of the HMAC-SHA256,
+// keyed by key, of data.
+func HMAC(key []byte, data string) string {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return fmt.Sprintf("%x", m.Sum(nil))
+}
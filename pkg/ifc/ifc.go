@@ -5,6 +5,8 @@
 package ifc
 
 import (
+	"io"
+
 	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
 )
@@ -16,6 +18,7 @@ type Validator interface {
 	MakeLabelValidator() func(map[string]string) error
 	MakeLabelNameValidator() func([]string) error
 	ValidateNamespace(string) []string
+	ValidateName(gvk.Gvk, string) []string
 	ErrIfInvalidKey(string) error
 	IsEnvVarName(k string) error
 }
@@ -28,6 +31,9 @@ type Loader interface {
 	New(newRoot string) (Loader, error)
 	// Load returns the bytes read from the location or an error.
 	Load(location string) ([]byte, error)
+	// Open returns a reader of what Load would return, for
+	// content too large to hold in memory more than once.
+	Open(location string) (io.ReadCloser, error)
 	// Cleanup cleans the loader
 	Cleanup() error
 	// Validator validates data for use in various k8s fields.
@@ -84,7 +90,8 @@ type KunstructuredFactory interface {
 }
 
 // KunstructuredHasher returns a hash of the argument
-// or an error.
+// or an error.  It names generated resources, and may
+// be replaced, e.g. by a keyed hasher.
 type KunstructuredHasher interface {
 	Hash(Kunstructured) (string, error)
 }
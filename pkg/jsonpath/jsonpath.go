@@ -0,0 +1,326 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package jsonpath sets values in unstructured objects
+// at locations given by a small subset of JSONPath,
+// enough to pick list elements by their fields, e.g.
+//
+//	spec.template.spec.containers[?(@.name=='app')].image
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepWildcard
+	stepFilter
+)
+
+// step is one element of a path.
+type step struct {
+	kind  stepKind
+	name  string
+	index int
+	f     *filter
+}
+
+// filter selects the list elements whose field at path
+// equals, or with op "!=" doesn't equal, the value.
+type filter struct {
+	path   []string
+	op     string
+	value  string
+	quoted bool
+}
+
+// Path is a parsed JSONPath.
+type Path struct {
+	raw   string
+	steps []step
+}
+
+// String returns the path as it was parsed.
+func (p Path) String() string {
+	return p.raw
+}
+
+// Parse parses a path made of
+//
+//	name or .name   a field of a map
+//	['name']        a field, e.g. one with dots in its name
+//	[n]             the nth element of a list; -1 is the last
+//	[*]             all elements of a list, or fields of a map
+//	[?(@.a.b=='v')] the list elements whose field a.b is v;
+//	                != is supported too, and unquoted values
+//	                match numbers and booleans
+//
+// optionally starting with $.
+func Parse(raw string) (Path, error) {
+	p := &parser{s: raw}
+	if strings.HasPrefix(p.s, "$") {
+		p.i = 1
+	}
+	var steps []step
+	for !p.done() {
+		s, err := p.step(len(steps) == 0)
+		if err != nil {
+			return Path{}, fmt.Errorf(
+				"invalid jsonpath '%s' at %d: %v", raw, p.i, err)
+		}
+		steps = append(steps, s)
+	}
+	if len(steps) == 0 {
+		return Path{}, fmt.Errorf("invalid jsonpath '%s': empty", raw)
+	}
+	return Path{raw: raw, steps: steps}, nil
+}
+
+type parser struct {
+	s string
+	i int
+}
+
+func (p *parser) done() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *parser) step(first bool) (step, error) {
+	switch {
+	case p.s[p.i] == '[':
+		return p.bracket()
+	case p.s[p.i] == '.':
+		p.i++
+	case !first:
+		return step{}, fmt.Errorf("expected '.' or '['")
+	}
+	name := p.name()
+	if name == "" {
+		return step{}, fmt.Errorf("expected a field name")
+	}
+	if name == "*" {
+		return step{kind: stepWildcard}, nil
+	}
+	return step{kind: stepField, name: name}, nil
+}
+
+// name reads up to the next '.' or '['.
+func (p *parser) name() string {
+	start := p.i
+	for !p.done() && p.s[p.i] != '.' && p.s[p.i] != '[' {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+func (p *parser) bracket() (step, error) {
+	end := p.closing()
+	if end < 0 {
+		return step{}, fmt.Errorf("missing ']'")
+	}
+	inner := strings.TrimSpace(p.s[p.i+1 : end])
+	p.i = end + 1
+	switch {
+	case inner == "*":
+		return step{kind: stepWildcard}, nil
+	case strings.HasPrefix(inner, "?"):
+		f, err := parseFilter(inner[1:])
+		if err != nil {
+			return step{}, err
+		}
+		return step{kind: stepFilter, f: f}, nil
+	}
+	if name, ok := unquote(inner); ok {
+		return step{kind: stepField, name: name}, nil
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil {
+		return step{}, fmt.Errorf("bad index '%s'", inner)
+	}
+	return step{kind: stepIndex, index: n}, nil
+}
+
+// closing returns the index of the ']' closing the
+// '[' at p.i, skipping quoted strings, or -1.
+func (p *parser) closing() int {
+	var quote byte
+	for j := p.i + 1; j < len(p.s); j++ {
+		c := p.s[j]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ']':
+			return j
+		}
+	}
+	return -1
+}
+
+func parseFilter(s string) (*filter, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return nil, fmt.Errorf("filter '%s' needs parentheses", s)
+	}
+	s = strings.TrimSpace(s[1 : len(s)-1])
+	f := &filter{}
+	var i int
+	if i = strings.Index(s, "=="); i >= 0 {
+		f.op = "=="
+	} else if i = strings.Index(s, "!="); i >= 0 {
+		f.op = "!="
+	} else {
+		return nil, fmt.Errorf("filter '%s' needs == or !=", s)
+	}
+	lhs := strings.TrimSpace(s[:i])
+	if !strings.HasPrefix(lhs, "@.") || len(lhs) < 3 {
+		return nil, fmt.Errorf("filter '%s' must compare @.field", s)
+	}
+	f.path = strings.Split(lhs[2:], ".")
+	rhs := strings.TrimSpace(s[i+2:])
+	if v, ok := unquote(rhs); ok {
+		f.value, f.quoted = v, true
+	} else if rhs == "" {
+		return nil, fmt.Errorf("filter '%s' needs a value", s)
+	} else {
+		f.value = rhs
+	}
+	return f, nil
+}
+
+func unquote(s string) (string, bool) {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], true
+	}
+	return "", false
+}
+
+func (f *filter) matches(node interface{}) bool {
+	for _, name := range f.path {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if node, ok = m[name]; !ok {
+			return false
+		}
+	}
+	var equal bool
+	if f.quoted {
+		s, ok := node.(string)
+		equal = ok && s == f.value
+	} else {
+		switch node.(type) {
+		case map[string]interface{}, []interface{}, string:
+		default:
+			equal = fmt.Sprint(node) == f.value
+		}
+	}
+	return equal == (f.op == "==")
+}
+
+// Set sets the value, a copy of it, at each location the
+// path finds in the object, returning the number of them.
+// Missing fields are added as maps, unless the path
+// goes on to select list elements in them.
+func (p Path) Set(obj map[string]interface{}, value interface{}) int {
+	return set(obj, p.steps, value)
+}
+
+func set(node interface{}, steps []step, value interface{}) int {
+	s, last := steps[0], len(steps) == 1
+	count := 0
+	switch n := node.(type) {
+	case map[string]interface{}:
+		switch s.kind {
+		case stepField:
+			if last {
+				n[s.name] = deepCopy(value)
+				return 1
+			}
+			child, ok := n[s.name]
+			if !ok && onlyFields(steps[1:]) {
+				child = map[string]interface{}{}
+				n[s.name] = child
+			}
+			if child != nil {
+				return set(child, steps[1:], value)
+			}
+		case stepWildcard:
+			for k, child := range n {
+				if last {
+					n[k] = deepCopy(value)
+					count++
+				} else {
+					count += set(child, steps[1:], value)
+				}
+			}
+		}
+	case []interface{}:
+		for i, child := range n {
+			if !s.selects(i, len(n), child) {
+				continue
+			}
+			if last {
+				n[i] = deepCopy(value)
+				count++
+			} else {
+				count += set(child, steps[1:], value)
+			}
+		}
+	}
+	return count
+}
+
+func onlyFields(steps []step) bool {
+	for _, s := range steps {
+		if s.kind != stepField {
+			return false
+		}
+	}
+	return true
+}
+
+// selects returns true if the step selects the
+// ith of n list elements.
+func (s step) selects(i, n int, child interface{}) bool {
+	switch s.kind {
+	case stepIndex:
+		if s.index < 0 {
+			return i == n+s.index
+		}
+		return i == s.index
+	case stepWildcard:
+		return true
+	case stepFilter:
+		return s.f.matches(child)
+	}
+	return false
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(typed))
+		for k, x := range typed {
+			m[k] = deepCopy(x)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(typed))
+		for i, x := range typed {
+			l[i] = deepCopy(x)
+		}
+		return l
+	}
+	return v
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonpath
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+const deployment = `
+metadata:
+  name: web
+  annotations:
+    a.b/c: x
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+        ports:
+        - containerPort: 80
+      - name: sidecar
+        image: sidecar:1
+        ports:
+        - containerPort: 9090
+`
+
+func TestSet(t *testing.T) {
+	testCases := []struct {
+		path     string
+		value    interface{}
+		count    int
+		expected string
+	}{
+		{
+			path:  "spec.template.spec.containers[?(@.name=='app')].image",
+			value: "app:2",
+			count: 1,
+			expected: strings.Replace(
+				deployment, "image: app:1", "image: app:2", 1),
+		},
+		{
+			path:  "$.spec.template.spec.containers[?(@.name!='app')].image",
+			value: "sidecar:2",
+			count: 1,
+			expected: strings.Replace(
+				deployment, "image: sidecar:1", "image: sidecar:2", 1),
+		},
+		{
+			path:  "spec.template.spec.containers[*].ports[?(@.containerPort==80)].name",
+			value: "http",
+			count: 1,
+			expected: strings.Replace(
+				deployment, "- containerPort: 80", "- containerPort: 80\n          name: http", 1),
+		},
+		{
+			path:  "spec.template.spec.containers[-1].image",
+			value: "sidecar:2",
+			count: 1,
+			expected: strings.Replace(
+				deployment, "image: sidecar:1", "image: sidecar:2", 1),
+		},
+		{
+			path:  "metadata.annotations['a.b/c']",
+			value: "z",
+			count: 1,
+			expected: strings.Replace(
+				deployment, "a.b/c: x", "a.b/c: z", 1),
+		},
+		{
+			path:     "spec.template.spec.containers[?(@.name=='nope')].image",
+			value:    "x",
+			count:    0,
+			expected: deployment,
+		},
+		{
+			path:  "spec.strategy.type",
+			value: "Recreate",
+			count: 1,
+			expected: strings.Replace(
+				deployment, "spec:\n  template:", "spec:\n  strategy:\n    type: Recreate\n  template:", 1),
+		},
+		{
+			path:     "spec.missing[0].field",
+			value:    "x",
+			count:    0,
+			expected: deployment,
+		},
+	}
+	for _, tc := range testCases {
+		var obj, expected map[string]interface{}
+		if err := yaml.Unmarshal([]byte(deployment), &obj); err != nil {
+			t.Fatal(err)
+		}
+		if err := yaml.Unmarshal([]byte(tc.expected), &expected); err != nil {
+			t.Fatal(err)
+		}
+		p, err := Parse(tc.path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.path, err)
+		}
+		if n := p.Set(obj, tc.value); n != tc.count {
+			t.Errorf("%s: expected %d, got %d", tc.path, tc.count, n)
+		}
+		if !reflect.DeepEqual(obj, expected) {
+			t.Errorf("%s: expected\n%v\ngot\n%v", tc.path, expected, obj)
+		}
+	}
+}
+
+func TestSetCopiesValue(t *testing.T) {
+	obj := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{}, map[string]interface{}{}},
+	}
+	p, err := Parse("items[*].v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Set(obj, map[string]interface{}{"a": "b"})
+	items := obj["items"].([]interface{})
+	items[0].(map[string]interface{})["v"].(map[string]interface{})["a"] = "c"
+	if items[1].(map[string]interface{})["v"].(map[string]interface{})["a"] != "b" {
+		t.Fatalf("values are shared")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := map[string]string{
+		"":                   "empty",
+		"a[0":                "missing ']'",
+		"a[x]":               "bad index 'x'",
+		"a..b":               "expected a field name",
+		"a[?(@.name)]":       "needs == or !=",
+		"a[?(name=='x')]":    "must compare @.field",
+		"a[?@.name=='x']":    "needs parentheses",
+		"a[0]b":              "expected '.' or '['",
+		"a[?(@.name==)].img": "needs a value",
+	}
+	for path, expected := range testCases {
+		_, err := Parse(path)
+		if err == nil {
+			t.Errorf("%s: expected error", path)
+			continue
+		}
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected '%s' in '%v'", path, expected, err)
+		}
+	}
+}
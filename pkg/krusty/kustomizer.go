@@ -0,0 +1,111 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package krusty has the names, of building kustomizations
+// in process, of sigs.k8s.io/kustomize/api/krusty.  It's a
+// rename shim over package render: the names match, the
+// import paths don't.  Go programs written against
+//
+//	sigs.k8s.io/kustomize/api/krusty
+//	sigs.k8s.io/kustomize/api/resmap
+//	sigs.k8s.io/kustomize/api/types
+//	sigs.k8s.io/kustomize/kyaml/filesys
+//
+// build against this module unchanged by replacing the
+// api and kyaml modules with those in the compat directory,
+// which alias the krusty, resmap, types and filesys
+// packages under sigs.k8s.io/kustomize/v3/pkg at those
+// paths; or, without them, by changing their imports.
+// Only the names krusty.MakeKustomizer,
+// krusty.MakeDefaultOptions, Kustomizer.Run, ResMap.AsYaml,
+// the Options fields, the types.LoadRestrictions values
+// and the filesys constructors are promised to match.
+package krusty
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/v3/pkg/filesys"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/render"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/version"
+	"sigs.k8s.io/kustomize/v3/plugin/builtin"
+)
+
+// managedByLabel is the label AddManagedbyLabel adds.
+const managedByLabel = "app.kubernetes.io/managed-by"
+
+// Kustomizer performs kustomizations.
+//
+// It's meant to behave like 'kustomize build', and
+// can be used instead of running kustomize.
+type Kustomizer struct {
+	options *Options
+}
+
+// MakeKustomizer returns an instance of Kustomizer.
+func MakeKustomizer(o *Options) *Kustomizer {
+	return &Kustomizer{options: o}
+}
+
+// Run builds the kustomization at path, in fSys, and
+// returns the resources it makes.
+//
+// Any files referenced by the kustomization must be in
+// fSys, but for plugins, which are on disk.  Run may be
+// called any number of times, on any paths.
+func (b *Kustomizer) Run(
+	fSys filesys.FileSystem, path string) (resmap.ResMap, error) {
+	pc := b.options.PluginConfig
+	if pc == nil {
+		pc = types.DisabledPluginConfig()
+	}
+	m, err := render.NewRenderer(fSys).
+		WithLoadRestrictor(b.loadRestrictor()).
+		WithPluginConfig(pc).
+		Render(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if b.options.Reorder != ReorderOptionNone {
+		err = builtin.NewLegacyOrderTransformerPlugin().Transform(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if b.options.AddManagedbyLabel {
+		t := builtin.LabelTransformerPlugin{
+			Labels: map[string]string{
+				managedByLabel: fmt.Sprintf(
+					"kustomize-%s", version.Get().KustomizeVersion),
+			},
+			FieldSpecs: []config.FieldSpec{{
+				Path:               "metadata/labels",
+				CreateIfNotPresent: true,
+			}},
+		}
+		if err = t.Transform(m); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// loadRestrictor returns the LoadRestrictorFunc of
+// the options, following symlinks only within the
+// root, as 'kustomize build' does by default.
+func (b *Kustomizer) loadRestrictor() loader.LoadRestrictorFunc {
+	switch b.options.LoadRestrictions {
+	case types.LoadRestrictionsRootOnly:
+		return loader.WithSymlinkPolicy(
+			loader.RestrictionRootOnly, loader.SymlinksWithinRoot)
+	case types.LoadRestrictionsRepoRootOnly:
+		return loader.RestrictionRepoRootOnly
+	default:
+		return loader.WithSymlinkPolicy(
+			loader.RestrictionNone, loader.SymlinksWithinRoot)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/filesys"
+	. "sigs.k8s.io/kustomize/v3/pkg/krusty"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+func writeApp(fSys filesys.FileSystem) {
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+namePrefix: dev-
+resources:
+- service.yaml
+- ../shared/namespace.yaml
+`))
+	fSys.WriteFile("/app/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`))
+	fSys.WriteFile("/shared/namespace.yaml", []byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: web
+`))
+}
+
+func TestRunDefaultOptions(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	writeApp(fSys)
+	_, err := MakeKustomizer(MakeDefaultOptions()).Run(fSys, "/app")
+	if err == nil || !strings.Contains(err.Error(), "security; file") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRunOptions(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	writeApp(fSys)
+	o := MakeDefaultOptions()
+	o.LoadRestrictions = types.LoadRestrictionsNone
+	o.Reorder = ReorderOptionLegacy
+	o.AddManagedbyLabel = true
+	m, err := MakeKustomizer(o).Run(fSys, "/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual, err := m.AsYaml()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: v1
+kind: Namespace
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kustomize-unknown
+  name: dev-web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: kustomize-unknown
+  name: dev-web
+`
+	if string(actual) != expected {
+		t.Fatalf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+
+	// The resources are as listed without reordering.
+	o.Reorder = ReorderOptionNone
+	o.AddManagedbyLabel = false
+	m, err = MakeKustomizer(o).Run(fSys, "/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := m.Resources(); len(r) != 2 || r[0].GetKind() != "Service" {
+		t.Fatalf("unexpected resources %v", r)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// ReorderOption says how to order the resources
+// a Kustomizer returns.
+type ReorderOption string
+
+const (
+	// ReorderOptionLegacy orders them as 'kustomize build'
+	// does, e.g. Namespaces before the resources in them.
+	ReorderOptionLegacy ReorderOption = "legacy"
+	// ReorderOptionNone keeps them in the order the
+	// kustomizations list them, depth first.
+	ReorderOptionNone ReorderOption = "none"
+	// ReorderOptionUnspecified is ReorderOptionLegacy.
+	ReorderOptionUnspecified ReorderOption = "unspecified"
+)
+
+// Options holds high-level kustomize configuration options,
+// e.g. are plugins enabled, should the loader be restricted
+// to the kustomization root, etc.
+type Options struct {
+	// Reorder says how to order the resources.
+	Reorder ReorderOption
+
+	// When true, a label
+	//     app.kubernetes.io/managed-by: kustomize-<version>
+	// is added to all the resources in the build out.
+	AddManagedbyLabel bool
+
+	// LoadRestrictions say what files kustomizations
+	// may refer to.  Any but LoadRestrictionsRootOnly
+	// and LoadRestrictionsRepoRootOnly is
+	// LoadRestrictionsNone, as upstream.
+	LoadRestrictions types.LoadRestrictions
+
+	// PluginConfig says whether, and from where,
+	// to load plugins.
+	PluginConfig *types.PluginConfig
+}
+
+// MakeDefaultOptions returns a default instance of Options.
+func MakeDefaultOptions() *Options {
+	return &Options{
+		Reorder:           ReorderOptionNone,
+		AddManagedbyLabel: false,
+		LoadRestrictions:  types.LoadRestrictionsRootOnly,
+		PluginConfig:      types.DisabledPluginConfig(),
+	}
+}
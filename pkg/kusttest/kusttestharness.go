@@ -57,6 +57,13 @@ func NewKustTestHarnessFull(
 		pl:  plugins.NewLoader(pc, rf)}
 }
 
+// SetResourceOptions makes the resources of the
+// targets made from now on follow the given options.
+func (th *KustTestHarness) SetResourceOptions(o resource.Options) {
+	th.rf = th.rf.WithOptions(o)
+	th.pl = th.pl.WithFactory(th.rf)
+}
+
 func (th *KustTestHarness) MakeKustTarget() *target.KustTarget {
 	kt, err := target.NewKustTarget(
 		th.ldr, th.rf, transformer.NewFactoryImpl(), th.pl)
@@ -159,6 +166,46 @@ func (th *KustTestHarness) RunTransformerFromResMap(
 	return resMap, err
 }
 
+func (th *KustTestHarness) LoadAndRunExporter(
+	config, input string) []byte {
+	resMap, err := th.rf.NewResMapFromBytes([]byte(input))
+	if err != nil {
+		th.t.Fatalf("Err: %v", err)
+	}
+	expConfig, err := th.rf.RF().FromBytes([]byte(config))
+	if err != nil {
+		th.t.Fatalf("Err: %v", err)
+	}
+	e, err := th.pl.LoadExporter(th.ldr, expConfig)
+	if err != nil {
+		th.t.Fatalf("Err: %v", err)
+	}
+	out, err := e.Export(resMap)
+	if err != nil {
+		th.t.Fatalf("Err: %v", err)
+	}
+	return out
+}
+
+// ErrorFromLoadAndCheckExpectation returns why the
+// input fails the expectation, nil if it holds.
+func (th *KustTestHarness) ErrorFromLoadAndCheckExpectation(
+	config, input string) error {
+	resMap, err := th.rf.NewResMapFromBytes([]byte(input))
+	if err != nil {
+		th.t.Fatalf("Err: %v", err)
+	}
+	expConfig, err := th.rf.RF().FromBytes([]byte(config))
+	if err != nil {
+		th.t.Fatalf("Err: %v", err)
+	}
+	e, err := th.pl.LoadExpectation(th.ldr, expConfig)
+	if err != nil {
+		th.t.Fatalf("Err: %v", err)
+	}
+	return e.Check(resMap)
+}
+
 func tabToSpace(input string) string {
 	var result []string
 	for _, i := range input {
@@ -210,6 +257,18 @@ func (th *KustTestHarness) AssertActualEqualsExpected(
 	}
 }
 
+// AssertOutputEqualsExpected is AssertActualEqualsExpected
+// for output that isn't a ResMap, e.g. that of an exporter.
+func (th *KustTestHarness) AssertOutputEqualsExpected(
+	actual []byte, expected string) {
+	if len(expected) > 0 && expected[0] == 10 {
+		expected = expected[1:]
+	}
+	if string(actual) != expected {
+		th.reportDiffAndFail(actual, expected)
+	}
+}
+
 // Pretty printing of file differences.
 func (th *KustTestHarness) reportDiffAndFail(actual []byte, expected string) {
 	sE, maxLen := convertToArray(expected)
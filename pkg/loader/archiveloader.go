@@ -0,0 +1,306 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+)
+
+// archiveExtensions are those of the URLs taken to be
+// archives of bases, rather than git repositories.
+var archiveExtensions = []string{".tar.gz", ".tgz", ".tar", ".zip"}
+
+// IsArchiveURL is true if s is an http(s) URL of a tar,
+// gzipped tar, or zip archive, which New downloads and
+// extracts, e.g. https://example.com/base.tar.gz//prod.
+func IsArchiveURL(s string) bool {
+	archive, _ := splitArchiveURL(s)
+	u, err := url.Parse(archive)
+	if err != nil || u.Host == "" ||
+		(u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	p := strings.ToLower(u.Path)
+	for _, x := range archiveExtensions {
+		if strings.HasSuffix(p, x) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitArchiveURL splits the URL of a base, like those
+// of objects, into the URL of the archive, with any
+// query, and the path of a directory in it, if given
+// after a //, as in
+// https://example.com/base.tar.gz//overlays/prod?sha256=3d4a....
+func splitArchiveURL(s string) (string, string) {
+	rest, query := s, ""
+	if i := strings.Index(rest, "?"); i >= 0 {
+		rest, query = rest[:i], rest[i:]
+	}
+	scheme := ""
+	if i := strings.Index(rest, "://"); i >= 0 {
+		scheme, rest = rest[:i+3], rest[i+3:]
+	}
+	subdir := ""
+	if i := strings.Index(rest, "//"); i >= 0 {
+		rest, subdir = rest[:i], rest[i+2:]
+	}
+	return scheme + rest + query, subdir
+}
+
+var archiveSha256 = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// peelArchiveSha256 removes the sha256 query parameter
+// from the URL of an archive, returning it, or empty
+// if the URL has none.  Other parameters, e.g. those
+// of a signed URL, are kept as they are.
+func peelArchiveSha256(u string) (string, string, error) {
+	i := strings.Index(u, "?")
+	if i < 0 {
+		return u, "", nil
+	}
+	var kept []string
+	sum := ""
+	for _, p := range strings.Split(u[i+1:], "&") {
+		if !strings.HasPrefix(p, "sha256=") {
+			kept = append(kept, p)
+			continue
+		}
+		sum = strings.TrimPrefix(p, "sha256=")
+		if !archiveSha256.MatchString(sum) {
+			return "", "", fmt.Errorf(
+				"url has sha256 %q, rather than 64 hex digits: %s", sum, u)
+		}
+	}
+	if len(kept) == 0 {
+		return u[:i], sum, nil
+	}
+	return u[:i+1] + strings.Join(kept, "&"), sum, nil
+}
+
+// fetchArchive downloads the archive at u, bounded by
+// the most bytes remote bases may have, and checks it
+// has the sha256 u pins, if any.
+func (o *Options) fetchArchive(u string) ([]byte, error) {
+	u, sum, err := peelArchiveSha256(u)
+	if err != nil {
+		return nil, err
+	}
+	max := o.RemoteLimits.MaxTotalBytes
+	var b []byte
+	err = o.Git.Retry(func() (err error) {
+		b, err = fetch(u, max, o.HTTP.Timeout)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if max > 0 && int64(len(b)) > max {
+		return nil, fmt.Errorf(
+			"security; %s has more than --%s %d bytes",
+			u, flagRemoteMaxTotalBytes, max)
+	}
+	if sum != "" {
+		if found := fmt.Sprintf("%x", sha256.Sum256(b)); found != sum {
+			return nil, git.Permanent(messages.Errorf(
+				messages.RepoDigestMismatch, u, found, sum))
+		}
+	}
+	return b, nil
+}
+
+// archiveWriter writes the files of an archive under
+// dir, held to the RemoteLimits.
+type archiveWriter struct {
+	name   string
+	fSys   fs.FileSystem
+	dir    fs.ConfirmedDir
+	limits RemoteLimits
+	files  int
+	total  int64
+}
+
+// write writes the entry of the given name, a path
+// with slashes, and mode from r.  Directories are made
+// as files are written; links aren't followed, so are
+// refused, and other entries, e.g. the pax headers
+// of git archive, are skipped.
+func (w *archiveWriter) write(name string, mode os.FileMode, r io.Reader) error {
+	if mode&os.ModeSymlink != 0 {
+		return kusterr.WithKind(ErrLoadRestricted, messages.Errorf(
+			messages.LinkInArchive, name, w.name))
+	}
+	if !mode.IsRegular() {
+		return nil
+	}
+	path := w.dir.Join(filepath.FromSlash(name))
+	if !strings.HasPrefix(path, w.dir.String()+string(filepath.Separator)) {
+		return kusterr.WithKind(ErrLoadRestricted, messages.Errorf(
+			messages.EntryNotInArchive, name, w.name))
+	}
+	w.files++
+	if max := w.limits.MaxFiles; max > 0 && w.files > max {
+		return fmt.Errorf(
+			"security; %s has more than --%s %d files",
+			w.name, flagRemoteMaxFiles, max)
+	}
+	// Sizes in headers may lie, so what's read is bounded.
+	if max := w.limits.MaxFileSize; max > 0 {
+		r = io.LimitReader(r, max+1)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if max := w.limits.MaxFileSize; max > 0 && int64(len(b)) > max {
+		return fmt.Errorf(
+			"security; %s of %s is larger than --%s %d bytes",
+			name, w.name, flagRemoteMaxFileSize, max)
+	}
+	w.total += int64(len(b))
+	if max := w.limits.MaxTotalBytes; max > 0 && w.total > max {
+		return fmt.Errorf(
+			"security; %s has more than --%s %d bytes",
+			w.name, flagRemoteMaxTotalBytes, max)
+	}
+	if err := w.fSys.MkdirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return w.fSys.WriteFile(path, b)
+}
+
+// extract writes the files of the archive b, a zip,
+// gzipped tar or tar, as its content says.
+func (w *archiveWriter) extract(b []byte) error {
+	if bytes.HasPrefix(b, []byte("PK\x03\x04")) {
+		return w.extractZip(b)
+	}
+	var r io.Reader = bytes.NewReader(b)
+	if bytes.HasPrefix(b, []byte("\x1f\x8b")) {
+		z, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("reading %s: %v", w.name, err)
+		}
+		defer z.Close()
+		r = z
+	}
+	t := tar.NewReader(r)
+	for {
+		h, err := t.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %v", w.name, err)
+		}
+		mode := h.FileInfo().Mode()
+		if h.Typeflag == tar.TypeLink {
+			mode |= os.ModeSymlink
+		}
+		if err := w.write(h.Name, mode, t); err != nil {
+			return err
+		}
+	}
+}
+
+func (w *archiveWriter) extractZip(b []byte) error {
+	z, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return fmt.Errorf("reading %s: %v", w.name, err)
+	}
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("reading %s: %v", w.name, err)
+		}
+		err = w.write(f.Name, f.Mode(), r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newLoaderAtArchive returns a new Loader rooted in a
+// temporary directory holding the files of the archive
+// at the URL s, which, like an artifact, it may not
+// load files from outside of.
+func newLoaderAtArchive(
+	s string, v ifc.Validator, fSys fs.FileSystem,
+	referrer *fileLoader, opts *Options) (*fileLoader, error) {
+	if err := opts.Git.ErrIfOffline("download", s); err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassRemote, err)
+	}
+	archive, subdir := splitArchiveURL(s)
+	b, err := opts.fetchArchive(archive)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassRemote,
+			messages.WithID(messages.ArchiveFetchFailed, err))
+	}
+	dir, err := fs.NewTmpConfirmedDir()
+	if err != nil {
+		return nil, err
+	}
+	cleaner := func() error {
+		err := fSys.RemoveAll(dir.String())
+		fs.RemoveTmp(dir.String())
+		return err
+	}
+	w := &archiveWriter{
+		name: archive, fSys: fSys, dir: dir, limits: opts.RemoteLimits}
+	if err := w.extract(b); err != nil {
+		cleaner()
+		if !kusterr.Is(err, ErrLoadRestricted) {
+			err = kusterr.WithClass(kusterr.ClassRemote,
+				messages.WithID(messages.ArchiveExtractFailed, err))
+		}
+		return nil, err
+	}
+	root, f, err := fSys.CleanedAbs(dir.Join(subdir))
+	if err == nil && f != "" {
+		err = messages.Errorf(messages.ExpectingDirectory, s, f)
+	}
+	if err == nil && !root.HasPrefix(dir) {
+		err = kusterr.WithKind(ErrLoadRestricted, messages.Errorf(
+			messages.PathNotInArchive, subdir))
+	}
+	if err != nil {
+		cleaner()
+		return nil, err
+	}
+	return &fileLoader{
+		// Archives, like clones, are never allowed
+		// to escape root.
+		loadRestrictor: RestrictionRootOnly,
+		validator:      v,
+		root:           root,
+		referrer:       referrer,
+		artifact:       &artifact{ref: s, dir: dir},
+		fSys:           fSys,
+		opts:           opts,
+		cleaner:        cleaner,
+		budget:         newRemoteBudget(opts.RemoteLimits),
+	}, nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestIsArchiveURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://example.com/base.tar.gz":                 true,
+		"https://example.com/base.tar.gz//overlays/prod":  true,
+		"http://example.com/v1/base.TGZ?sha256=3d4a":      true,
+		"https://example.com/base.zip//prod?X-Sig=abc":    true,
+		"https://example.com/base.tar":                    true,
+		"https://example.com/manifests/app.yaml":          false,
+		"https://github.com/someOrg/someRepo/base?ref=v1": false,
+		"https://example.com/base.zip.yaml":               false,
+		"example.com/base.tar.gz":                         false,
+		"s3://someBucket/base.tar.gz":                     false,
+		"base.tar.gz":                                     false,
+	}
+	for u, expected := range tests {
+		if actual := IsArchiveURL(u); actual != expected {
+			t.Errorf("%s: expected %v, got %v", u, expected, actual)
+		}
+	}
+}
+
+// archiveEntry is a file, or, with a link, a symbolic
+// link, of a test archive.
+type archiveEntry struct {
+	name, content, link string
+}
+
+func makeTarGz(t *testing.T, entries []archiveEntry) []byte {
+	var buf bytes.Buffer
+	z := gzip.NewWriter(&buf)
+	w := tar.NewWriter(z)
+	for _, e := range entries {
+		h := &tar.Header{
+			Name: e.name, Mode: 0644, Size: int64(len(e.content)),
+			Typeflag: tar.TypeReg}
+		if e.link != "" {
+			h.Typeflag, h.Linkname, h.Size = tar.TypeSymlink, e.link, 0
+		}
+		if err := w.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func makeZip(t *testing.T, entries []archiveEntry) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, e := range entries {
+		f, err := w.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+var baseEntries = []archiveEntry{
+	{name: "bases/base/kustomization.yaml", content: "resources: [pod.yaml]"},
+	{name: "bases/base/pod.yaml", content: "kind: Pod"},
+	{name: "bases/prod/kustomization.yaml", content: "resources: [../base]"},
+}
+
+// makeArchiveServer serves the archives of the
+// given entries at their paths.
+func makeArchiveServer(archives map[string][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			b, ok := archives[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(b)
+		}))
+}
+
+func TestNewLoaderAtArchive(t *testing.T) {
+	tarGz := makeTarGz(t, baseEntries)
+	s := makeArchiveServer(map[string][]byte{
+		"/base.tar.gz": tarGz,
+		"/base.zip":    makeZip(t, baseEntries),
+	})
+	defer s.Close()
+	sum := fmt.Sprintf("%x", sha256.Sum256(tarGz))
+	for _, u := range []string{
+		s.URL + "/base.tar.gz//bases/prod",
+		s.URL + "/base.zip//bases/prod",
+		s.URL + "/base.tar.gz//bases/prod?sha256=" + sum,
+	} {
+		fSys := fs.MakeFakeFS()
+		recorder := NewDepRecorder()
+		l := newLoaderAtConfirmedDir(
+			RestrictionRootOnly, validators.MakeFakeValidator(),
+			fs.ConfirmedDir("/app"), fSys, nil, testOptions(nil))
+		l.tracer = recorder
+
+		ldr, err := l.New(u)
+		if err != nil {
+			t.Fatalf("%s: unexpected err: %v", u, err)
+		}
+		if !strings.HasSuffix(ldr.Root(), "/bases/prod") {
+			t.Fatalf("%s: unexpected root %s", u, ldr.Root())
+		}
+		base, err := ldr.New("../base")
+		if err != nil {
+			t.Fatalf("%s: unexpected err: %v", u, err)
+		}
+		b, err := base.Load("pod.yaml")
+		if err != nil || string(b) != "kind: Pod" {
+			t.Fatalf("%s: unexpected %s, %v", u, b, err)
+		}
+		_, err = base.New("../../..")
+		if err == nil || !strings.Contains(err.Error(), "security; bases in "+
+			"kustomizations found in archives must be within the archive") {
+			t.Fatalf("%s: unexpected err: %v", u, err)
+		}
+		deps := recorder.Dependencies()
+		if fmt.Sprint(deps.Remotes) != fmt.Sprint([]string{u}) {
+			t.Fatalf("%s: expected remotes %v, got %v", u, u, deps.Remotes)
+		}
+
+		dir := strings.TrimSuffix(ldr.Root(), "/bases/prod")
+		if err := ldr.Cleanup(); err != nil || fSys.Exists(dir) {
+			t.Fatalf("%s: expected %s removed, got %v", u, dir, err)
+		}
+	}
+}
+
+func TestNewLoaderAtArchiveRefuses(t *testing.T) {
+	s := makeArchiveServer(map[string][]byte{
+		"/base.tar.gz": makeTarGz(t, baseEntries),
+		"/escape.tar.gz": makeTarGz(t, []archiveEntry{
+			{name: "../../etc/app.yaml", content: "kind: Pod"}}),
+		"/link.tar.gz": makeTarGz(t, []archiveEntry{
+			{name: "base/app.yaml", link: "/etc/passwd"}}),
+		"/escape.zip": makeZip(t, []archiveEntry{
+			{name: "../app.yaml", content: "kind: Pod"}}),
+	})
+	defer s.Close()
+	tests := map[string]string{
+		s.URL + "/base.tar.gz//../..":      "security; '../..' is outside the archive",
+		s.URL + "/base.tar.gz//bases/nope": "bases/nope",
+		s.URL + "/escape.tar.gz":           "security; entry '../../etc/app.yaml' is outside",
+		s.URL + "/escape.zip":              "security; entry '../app.yaml' is outside",
+		s.URL + "/link.tar.gz":             "security; entry 'base/app.yaml' of ",
+		s.URL + "/missing.tar.gz":          "404 Not Found",
+		s.URL + "/base.tar.gz?sha256=" + strings.Repeat("0", 64): "security; '" +
+			s.URL + "/base.tar.gz' has sha256=",
+		s.URL + "/base.tar.gz?sha256=3d4a": "rather than 64 hex digits",
+	}
+	for u, expected := range tests {
+		fSys := fs.MakeFakeFS()
+		l := newLoaderAtConfirmedDir(
+			RestrictionRootOnly, validators.MakeFakeValidator(),
+			fs.ConfirmedDir("/app"), fSys, nil, testOptions(nil))
+		_, err := l.New(u)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected err containing %q, got %v", u, expected, err)
+		}
+	}
+
+	opts := DefaultOptions()
+	opts.RemoteLimits.MaxFiles = 2
+	l := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		fs.ConfirmedDir("/app"), fs.MakeFakeFS(), nil, opts.complete())
+	_, err := l.New(s.URL + "/base.tar.gz//bases/prod")
+	if err == nil || !strings.Contains(err.Error(), "--remote-max-files 2") ||
+		kusterr.ClassOf(err) != kusterr.ClassRemote {
+		t.Fatalf("expected a remote error of too many files, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// Decryption holds what's needed to decrypt files
+// committed encrypted, so that whole patches, not just
+// secret literals, can be kept encrypted in a repo.
+// Files encrypted with age are decrypted with the age
+// program, and files git-crypt left encrypted, e.g. in
+// a clone that wasn't unlocked, with the git-crypt
+// program.
+type Decryption struct {
+	// AgeIdentities are paths of age identity files.
+	AgeIdentities []string
+	// GitCryptKey is the path of a key exported
+	// with 'git-crypt export-key'.
+	GitCryptKey string
+}
+
+const (
+	// EnvAgeIdentities lists age identity files, separated
+	// as in $PATH, for when --age-identity isn't given.
+	EnvAgeIdentities = "KUSTOMIZE_AGE_IDENTITIES"
+	// EnvGitCryptKey is the git-crypt key file, for
+	// when --git-crypt-key isn't given.
+	EnvGitCryptKey = "KUSTOMIZE_GIT_CRYPT_KEY"
+)
+
+const (
+	flagAgeIdentity = "age-identity"
+	flagGitCryptKey = "git-crypt-key"
+)
+
+// AddFlagsDecryption adds flags setting the decryption.
+func (o *Options) AddFlagsDecryption(set *pflag.FlagSet) {
+	set.StringSliceVar(
+		&o.Decryption.AgeIdentities, flagAgeIdentity, nil,
+		"Age identity file with which to decrypt files encrypted "+
+			"with age; if unspecified, those in $"+EnvAgeIdentities+".")
+	set.StringVar(
+		&o.Decryption.GitCryptKey, flagGitCryptKey, "",
+		"Key file, from 'git-crypt export-key', with which to decrypt "+
+			"files git-crypt left encrypted; if unspecified, $"+
+			EnvGitCryptKey+".")
+}
+
+const (
+	programAge      = "age"
+	programGitCrypt = "git-crypt"
+)
+
+var (
+	ageHeader      = []byte("age-encryption.org/v1\n")
+	ageArmorHeader = []byte("-----BEGIN AGE ENCRYPTED FILE-----")
+	gitCryptHeader = []byte("\x00GITCRYPT\x00")
+)
+
+// encryptionHeaderSize is enough of the start of a
+// file to tell if it's encrypted, allowing for some
+// whitespace before an armored age header.
+const encryptionHeaderSize = 512
+
+// runDecryption runs a decrypting program;
+// tests replace it.
+var runDecryption = runUsingExec
+
+// encryption names the program that encrypted the
+// content, or is "" if it's not encrypted.
+func encryption(content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, ageHeader),
+		bytes.HasPrefix(bytes.TrimSpace(content), ageArmorHeader):
+		return programAge
+	case bytes.HasPrefix(content, gitCryptHeader):
+		return programGitCrypt
+	}
+	return ""
+}
+
+// decrypt returns the content of the file at path,
+// decrypted if it's encrypted.
+func (d Decryption) decrypt(path string, content []byte) ([]byte, error) {
+	var args []string
+	switch encryption(content) {
+	case "":
+		return content, nil
+	case programAge:
+		ids := d.AgeIdentities
+		if len(ids) == 0 {
+			ids = filepath.SplitList(os.Getenv(EnvAgeIdentities))
+		}
+		if len(ids) == 0 {
+			return nil, fmt.Errorf(
+				"'%s' is encrypted with age; give an identity with "+
+					"--%s or $%s", path, flagAgeIdentity, EnvAgeIdentities)
+		}
+		args = []string{programAge, "--decrypt"}
+		for _, id := range ids {
+			args = append(args, "--identity", id)
+		}
+	case programGitCrypt:
+		key := d.GitCryptKey
+		if key == "" {
+			key = os.Getenv(EnvGitCryptKey)
+		}
+		if key == "" {
+			return nil, fmt.Errorf(
+				"'%s' is encrypted with git-crypt; unlock the repo, "+
+					"or give a key with --%s or $%s",
+				path, flagGitCryptKey, EnvGitCryptKey)
+		}
+		args = []string{programGitCrypt, "smudge", "--key-file", key}
+	}
+	plain, err := runDecryption(args, content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decrypting '%s'", path)
+	}
+	return plain, nil
+}
+
+// runUsingExec runs the program args[0], with
+// stdin as its input, returning its output.
+func runUsingExec(args []string, stdin []byte) ([]byte, error) {
+	program, err := exec.LookPath(args[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "no '%s' program on path", args[0])
+	}
+	cmd := exec.Command(program, args[1:]...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.Wrap(err, msg)
+		}
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
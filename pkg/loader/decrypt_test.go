@@ -0,0 +1,88 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestEncryption(t *testing.T) {
+	tests := map[string]string{
+		"kind: Pod\n":                                      "",
+		"age-encryption.org/v1\n-> X25519 abc\n":           programAge,
+		"\n-----BEGIN AGE ENCRYPTED FILE-----\nYWdl\n":     programAge,
+		"\x00GITCRYPT\x00\x01\x02\x03":                     programGitCrypt,
+		"# age-encryption.org/v1 is only mentioned here\n": "",
+	}
+	for content, expected := range tests {
+		if actual := encryption([]byte(content)); actual != expected {
+			t.Errorf("%q: expected '%s', got '%s'", content, expected, actual)
+		}
+	}
+}
+
+func makeDecryptingLoader() *fileLoader {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/plain.yaml", []byte("kind: Pod"))
+	fSys.WriteFile("/app/patch.yaml.age", []byte("age-encryption.org/v1\nciphertext"))
+	fSys.WriteFile("/app/patch.yaml", []byte("\x00GITCRYPT\x00ciphertext"))
+	return newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		fs.ConfirmedDir("/app"), fSys, nil, testOptions(nil))
+}
+
+func TestLoadDecrypts(t *testing.T) {
+	var ran [][]string
+	saved := runDecryption
+	runDecryption = func(args []string, stdin []byte) ([]byte, error) {
+		ran = append(ran, args)
+		return []byte("kind: Secret"), nil
+	}
+	defer func() { runDecryption = saved }()
+
+	l := makeDecryptingLoader()
+	l.opts.Decryption = Decryption{
+		AgeIdentities: []string{"a.txt", "b.txt"},
+		GitCryptKey:   "key",
+	}
+	for path, content := range map[string]string{
+		"plain.yaml":     "kind: Pod",
+		"patch.yaml.age": "kind: Secret",
+		"patch.yaml":     "kind: Secret",
+	} {
+		b, err := l.Load(path)
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if string(b) != content {
+			t.Fatalf("%s: expected '%s', got '%s'", path, content, b)
+		}
+	}
+	expected := [][]string{
+		{"age", "--decrypt", "--identity", "a.txt", "--identity", "b.txt"},
+		{"git-crypt", "smudge", "--key-file", "key"},
+	}
+	sort.Slice(ran, func(i, j int) bool { return ran[i][0] < ran[j][0] })
+	if !reflect.DeepEqual(ran, expected) {
+		t.Fatalf("expected %v, got %v", expected, ran)
+	}
+}
+
+func TestLoadEncryptedNeedsIdentity(t *testing.T) {
+	l := makeDecryptingLoader()
+	_, err := l.Load("patch.yaml.age")
+	if err == nil || !strings.Contains(err.Error(), "--age-identity") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	_, err = l.Load("patch.yaml")
+	if err == nil || !strings.Contains(err.Error(), "--git-crypt-key") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"errors"
+)
+
+// The kinds of the errors of loaders, for programs
+// building kustomizations to test for with errors.Is.
+var (
+	// ErrLoadRestricted is the kind of the errors of files,
+	// or bases, the load restrictor, the symlink policy,
+	// or the bounds of a remote base don't allow.
+	ErrLoadRestricted = errors.New("load restricted")
+
+	// ErrCycleDetected is the kind of the errors of bases
+	// that refer, directly or through others, to themselves.
+	ErrCycleDetected = errors.New("cycle detected")
+)
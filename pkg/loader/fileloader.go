@@ -4,14 +4,22 @@
 package loader
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"path/filepath"
 	"strings"
 
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/git"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/oci"
 )
 
 // fileLoader is a kustomization's interface to files.
@@ -39,11 +47,12 @@ import (
 //
 //   `New` is used to load bases.
 //
-//   A base can be either a remote git repo URL, or
-//   a directory specified relative to the current
-//   root. In the former case, the repo is locally
-//   cloned, and the new loader is rooted on a path
-//   in that clone.
+//   A base can be either a remote git repo URL, an
+//   oci:// reference to an artifact, or a directory
+//   specified relative to the current root. In the
+//   first case, the repo is locally cloned, and the
+//   new loader is rooted on a path in that clone; in
+//   the second, the artifact is pulled and unpacked.
 //
 //   As loaders create new loaders, a root history
 //   is established, and used to disallow:
@@ -86,14 +95,31 @@ type fileLoader struct {
 	// obtained from the given repository.
 	repoSpec *git.RepoSpec
 
+	// If this is non-nil, the files were
+	// pulled from the given OCI artifact.
+	artifact *artifact
+
 	// File system utilities.
 	fSys fs.FileSystem
 
-	// Used to clone repositories.
-	cloner git.Cloner
+	// Say how to clone repositories, fetch URLs, etc.;
+	// shared with the loaders this one makes.
+	opts *Options
 
 	// Used to clean up, as needed.
 	cleaner func() error
+
+	// If non-nil, notified of files read and roots
+	// created by this loader and its descendants.
+	tracer Tracer
+
+	// If non-nil, counts the files loaded from a clone,
+	// by all the loaders in it, to enforce RemoteLimits.
+	budget *remoteBudget
+
+	// Loaders of remote bases cloned by Prefetch,
+	// by path, that New hasn't returned yet.
+	prefetched map[string]*prefetched
 }
 
 const CWD = "."
@@ -118,6 +144,12 @@ func (fl *fileLoader) Root() string {
 	return fl.root.String()
 }
 
+// GeneratorCacheDir returns the directory generated
+// ConfigMaps are cached in across builds, if any.
+func (fl *fileLoader) GeneratorCacheDir() string {
+	return fl.opts.GeneratorCacheDir
+}
+
 func newLoaderOrDie(
 	lr LoadRestrictorFunc, v ifc.Validator,
 	fSys fs.FileSystem, path string) *fileLoader {
@@ -126,7 +158,7 @@ func newLoaderOrDie(
 		log.Fatalf("unable to make loader at '%s'; %v", path, err)
 	}
 	return newLoaderAtConfirmedDir(
-		lr, v, root, fSys, nil, git.ClonerUsingGitExec)
+		lr, v, root, fSys, nil, DefaultOptions().complete())
 }
 
 // newLoaderAtConfirmedDir returns a new fileLoader with given root.
@@ -134,14 +166,14 @@ func newLoaderAtConfirmedDir(
 	lr LoadRestrictorFunc,
 	v ifc.Validator,
 	root fs.ConfirmedDir, fSys fs.FileSystem,
-	referrer *fileLoader, cloner git.Cloner) *fileLoader {
+	referrer *fileLoader, opts *Options) *fileLoader {
 	return &fileLoader{
 		loadRestrictor: lr,
 		validator:      v,
 		root:           root,
 		referrer:       referrer,
 		fSys:           fSys,
-		cloner:         cloner,
+		opts:           opts,
 		cleaner:        func() error { return nil },
 	}
 }
@@ -167,19 +199,65 @@ func demandDirectoryRoot(
 }
 
 // New returns a new Loader, rooted relative to current loader,
-// or rooted in a temp directory holding a git repo clone.
+// or rooted in a temp directory holding a git repo clone, or
+// the files of an OCI artifact.
 func (fl *fileLoader) New(path string) (ifc.Loader, error) {
+	var ldr *fileLoader
+	var err error
+	if p, ok := fl.prefetched[path]; ok {
+		delete(fl.prefetched, path)
+		ldr, err = p.ldr, p.err
+	} else {
+		ldr, err = fl.newLoader(path)
+	}
+	if err != nil {
+		if kusterr.ClassOf(err) == kusterr.ClassUnknown {
+			err = kusterr.WithClass(kusterr.ClassLoad, err)
+		}
+		return nil, err
+	}
+	ldr.tracer = fl.tracer
+	ldr.traceRoot()
+	fl.traceDepends(ldr.node(ldr.root.String(), NodeDirectory))
+	return ldr, nil
+}
+
+func (fl *fileLoader) newLoader(path string) (*fileLoader, error) {
 	if path == "" {
 		return nil, fmt.Errorf("new root cannot be empty")
 	}
-	repoSpec, err := git.NewRepoSpecFromUrl(path)
+	if IsFileURL(path) {
+		return nil, fmt.Errorf("new root '%s' is a file", path)
+	}
+	if oci.IsReference(path) {
+		if err := fl.errIfArtifactCycle(path); err != nil {
+			return nil, err
+		}
+		return newLoaderAtArtifact(
+			path, fl.validator, fl.fSys, fl.referrer, fl.opts)
+	}
+	if IsObjectURL(path) {
+		if err := fl.errIfArtifactCycle(path); err != nil {
+			return nil, err
+		}
+		return newLoaderAtObjects(
+			path, fl.validator, fl.fSys, fl.referrer, fl.opts)
+	}
+	if IsArchiveURL(path) {
+		if err := fl.errIfArtifactCycle(path); err != nil {
+			return nil, err
+		}
+		return newLoaderAtArchive(
+			path, fl.validator, fl.fSys, fl.referrer, fl.opts)
+	}
+	repoSpec, err := fl.opts.Git.NewRepoSpecFromUrl(path)
 	if err == nil {
 		// Treat this as git repo clone request.
 		if err := fl.errIfRepoCycle(repoSpec); err != nil {
 			return nil, err
 		}
 		return newLoaderAtGitClone(
-			repoSpec, fl.validator, fl.fSys, fl.referrer, fl.cloner)
+			repoSpec, fl.validator, fl.fSys, fl.referrer, fl.opts)
 	}
 	if filepath.IsAbs(path) {
 		return nil, fmt.Errorf("new root '%s' cannot be absolute", path)
@@ -191,11 +269,16 @@ func (fl *fileLoader) New(path string) (ifc.Loader, error) {
 	if err := fl.errIfGitContainmentViolation(root); err != nil {
 		return nil, err
 	}
+	if err := fl.errIfArtifactContainmentViolation(root); err != nil {
+		return nil, err
+	}
 	if err := fl.errIfArgEqualOrHigher(root); err != nil {
 		return nil, err
 	}
-	return newLoaderAtConfirmedDir(
-		fl.loadRestrictor, fl.validator, root, fl.fSys, fl, fl.cloner), nil
+	ldr := newLoaderAtConfirmedDir(
+		fl.loadRestrictor, fl.validator, root, fl.fSys, fl, fl.opts)
+	ldr.budget = fl.budget
+	return ldr, nil
 }
 
 // newLoaderAtGitClone returns a new Loader pinned to a temporary
@@ -203,10 +286,21 @@ func (fl *fileLoader) New(path string) (ifc.Loader, error) {
 func newLoaderAtGitClone(
 	repoSpec *git.RepoSpec,
 	v ifc.Validator, fSys fs.FileSystem,
-	referrer *fileLoader, cloner git.Cloner) (ifc.Loader, error) {
-	err := cloner(repoSpec)
+	referrer *fileLoader, opts *Options) (*fileLoader, error) {
+	tried := false
+	err := opts.Git.Retry(func() error {
+		// Drop what a failed try left, to start afresh.
+		if tried && repoSpec.Dir != "" {
+			repoSpec.Cleaner(fSys)()
+			repoSpec.Dir = ""
+		}
+		tried = true
+		return opts.Cloner(repoSpec)
+	})
 	if err != nil {
-		return nil, err
+		return nil, kusterr.WithClass(kusterr.ClassRemote,
+			kusterr.WithKind(git.ErrRepoCloneFailed,
+				messages.WithID(messages.RepoCloneFailed, err)))
 	}
 	root, f, err := fSys.CleanedAbs(repoSpec.AbsPath())
 	if err != nil {
@@ -217,9 +311,11 @@ func newLoaderAtGitClone(
 	// inside.  That just happened, hence the error check
 	// is here.
 	if f != "" {
-		return nil, fmt.Errorf(
-			"'%s' refers to file '%s'; expecting directory",
-			repoSpec.AbsPath(), f)
+		return nil, messages.Errorf(
+			messages.ExpectingDirectory, repoSpec.AbsPath(), f)
+	}
+	if err := verifyTreeDigest(repoSpec, root); err != nil {
+		return nil, err
 	}
 	return &fileLoader{
 		// Clones never allowed to escape root.
@@ -229,37 +325,68 @@ func newLoaderAtGitClone(
 		referrer:       referrer,
 		repoSpec:       repoSpec,
 		fSys:           fSys,
-		cloner:         cloner,
+		opts:           opts,
 		cleaner:        repoSpec.Cleaner(fSys),
+		budget:         newRemoteBudget(opts.RemoteLimits),
 	}, nil
 }
 
+// verifyTreeDigest fails if the URL of the repo pins the
+// digest of the directory cloned, root, to another.
+func verifyTreeDigest(repoSpec *git.RepoSpec, root fs.ConfirmedDir) error {
+	if repoSpec.Sha256 == "" {
+		return nil
+	}
+	sum, err := git.TreeDigest(root.String())
+	if err != nil {
+		return kusterr.WithClass(kusterr.ClassRemote,
+			messages.WithID(messages.RepoDigestFailed, err))
+	}
+	if sum != repoSpec.Sha256 {
+		return kusterr.WithClass(kusterr.ClassRemote, messages.Errorf(
+			messages.RepoDigestMismatch, repoSpec.Raw(), sum, repoSpec.Sha256))
+	}
+	return nil
+}
+
 func (fl *fileLoader) errIfGitContainmentViolation(
 	base fs.ConfirmedDir) error {
-	containingRepo := fl.containingRepo()
-	if containingRepo == nil {
+	repoLoader := fl.containingRepoLoader()
+	if repoLoader == nil {
 		return nil
 	}
+	containingRepo := repoLoader.repoSpec
 	if !base.HasPrefix(containingRepo.CloneDir()) {
-		return fmt.Errorf(
-			"security; bases in kustomizations found in "+
-				"cloned git repos must be within the repo, "+
-				"but base '%s' is outside '%s'",
-			base, containingRepo.CloneDir())
+		return kusterr.WithKind(ErrLoadRestricted, messages.Errorf(
+			messages.BaseNotInRepo, base, containingRepo.CloneDir()))
+	}
+	if containingRepo.Sha256 != "" && !base.HasPrefix(repoLoader.root) {
+		return kusterr.WithKind(ErrLoadRestricted, messages.Errorf(
+			messages.BaseNotInDigest,
+			containingRepo.Raw(), repoLoader.root, base))
 	}
 	return nil
 }
 
 // Looks back through referrers for a git repo, returning nil
-// if none found.
+// if none found, or if an artifact is found first.
 func (fl *fileLoader) containingRepo() *git.RepoSpec {
+	if l := fl.containingRepoLoader(); l != nil {
+		return l.repoSpec
+	}
+	return nil
+}
+
+// containingRepoLoader is containingRepo, but returns
+// the loader at the root of the clone.
+func (fl *fileLoader) containingRepoLoader() *fileLoader {
 	if fl.repoSpec != nil {
-		return fl.repoSpec
+		return fl
 	}
-	if fl.referrer == nil {
+	if fl.referrer == nil || fl.artifact != nil {
 		return nil
 	}
-	return fl.referrer.containingRepo()
+	return fl.referrer.containingRepoLoader()
 }
 
 // errIfArgEqualOrHigher tests whether the argument,
@@ -267,9 +394,8 @@ func (fl *fileLoader) containingRepo() *git.RepoSpec {
 func (fl *fileLoader) errIfArgEqualOrHigher(
 	candidateRoot fs.ConfirmedDir) error {
 	if fl.root.HasPrefix(candidateRoot) {
-		return fmt.Errorf(
-			"cycle detected: candidate root '%s' contains visited root '%s'",
-			candidateRoot, fl.root)
+		return kusterr.WithKind(ErrCycleDetected, messages.Errorf(
+			messages.CycleRoot, candidateRoot, fl.root))
 	}
 	if fl.referrer == nil {
 		return nil
@@ -285,9 +411,8 @@ func (fl *fileLoader) errIfRepoCycle(newRepoSpec *git.RepoSpec) error {
 	// TODO(monopole): Use parsed data instead of Raw().
 	if fl.repoSpec != nil &&
 		strings.HasPrefix(fl.repoSpec.Raw(), newRepoSpec.Raw()) {
-		return fmt.Errorf(
-			"cycle detected: URI '%s' referenced by previous URI '%s'",
-			newRepoSpec.Raw(), fl.repoSpec.Raw())
+		return kusterr.WithKind(ErrCycleDetected, messages.Errorf(
+			messages.CycleRepo, newRepoSpec.Raw(), fl.repoSpec.Raw()))
 	}
 	if fl.referrer == nil {
 		return nil
@@ -297,19 +422,250 @@ func (fl *fileLoader) errIfRepoCycle(newRepoSpec *git.RepoSpec) error {
 
 // Load returns the content of file at the given path,
 // else an error.  Relative paths are taken relative
-// to the root.
+// to the root.  An http(s) URL of a file is fetched,
+// an oci:// reference to an artifact pulled, and an
+// s3:// or gs:// URL of an object read.
 func (fl *fileLoader) Load(path string) ([]byte, error) {
+	if IsFileURL(path) {
+		return fl.loadURL(path)
+	}
+	if oci.IsReference(path) {
+		return fl.loadOCI(path)
+	}
+	if IsObjectURL(path) {
+		return fl.loadObject(path)
+	}
+	path, err := fl.allow(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := fl.fSys.ReadFile(path)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassLoad, err)
+	}
+	fl.traceDigest(path, b)
+	b, err = fl.opts.Decryption.decrypt(path, b)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassLoad, err)
+	}
+	fl.traceLoaded(path)
+	return b, nil
+}
+
+// allow returns the absolute path of a file
+// the loader may read, charging it to the budget.
+func (fl *fileLoader) allow(path string) (string, error) {
 	if !filepath.IsAbs(path) {
 		path = fl.root.Join(path)
 	}
 	path, err := fl.loadRestrictor(fl.fSys, fl.root, path)
 	if err != nil {
-		return nil, err
+		return "", kusterr.WithClass(kusterr.ClassLoad, err)
+	}
+	if fl.budget != nil {
+		if err := fl.budget.charge(fl.fSys, path); err != nil {
+			return "", kusterr.WithClass(kusterr.ClassLoad, err)
+		}
+	}
+	return path, nil
+}
+
+// Open returns a reader of the file at path, which,
+// unless it's encrypted or a URL, streams the file
+// rather than holding it in memory.
+func (fl *fileLoader) Open(path string) (io.ReadCloser, error) {
+	r, _, err := fl.open(path)
+	return r, err
+}
+
+// open is Open, also returning the size of the
+// content, or -1 if it's unknown.
+func (fl *fileLoader) open(path string) (io.ReadCloser, int64, error) {
+	if IsFileURL(path) || oci.IsReference(path) || IsObjectURL(path) {
+		b, err := fl.Load(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(b)), int64(len(b)), nil
+	}
+	path, err := fl.allow(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	f, err := fl.fSys.Open(path)
+	if err != nil {
+		return nil, 0, kusterr.WithClass(kusterr.ClassLoad, err)
+	}
+	size := int64(-1)
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
+	}
+	r := bufio.NewReader(f)
+	// Encrypted files are decrypted in one piece.
+	head, _ := r.Peek(encryptionHeaderSize)
+	if encryption(head) != "" {
+		b, err := ioutil.ReadAll(r)
+		f.Close()
+		if err == nil {
+			fl.traceDigest(path, b)
+			b, err = fl.opts.Decryption.decrypt(path, b)
+		}
+		if err != nil {
+			return nil, 0, kusterr.WithClass(kusterr.ClassLoad, err)
+		}
+		fl.traceLoaded(path)
+		return ioutil.NopCloser(bytes.NewReader(b)), int64(len(b)), nil
+	}
+	fl.traceLoaded(path)
+	if d := fl.digestTracer(); d != nil {
+		h := sha256.New()
+		return &digestingReadCloser{
+			Reader: io.TeeReader(r, h),
+			Closer: f,
+			digested: func() {
+				var sum [sha256.Size]byte
+				copy(sum[:], h.Sum(nil))
+				d.Digested(path, sum)
+			},
+		}, size, nil
+	}
+	return readCloser{r, f}, size, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// digestingReadCloser hashes a file as it's read,
+// telling the digest when closed, having read what
+// was left unread.
+type digestingReadCloser struct {
+	io.Reader
+	io.Closer
+	digested func()
+}
+
+func (r *digestingReadCloser) Close() error {
+	if _, err := io.Copy(ioutil.Discard, r.Reader); err == nil {
+		r.digested()
+	}
+	return r.Closer.Close()
+}
+
+// digestTracer returns the tracer, if it's a
+// DigestTracer and the loader's files are local.
+func (fl *fileLoader) digestTracer() DigestTracer {
+	d, ok := fl.tracer.(DigestTracer)
+	if !ok || fl.containingArtifact() != nil ||
+		fl.containingRepo() != nil {
+		return nil
+	}
+	return d
+}
+
+// traceDigest tells a DigestTracer the digest of
+// the content b of the local file at path.
+func (fl *fileLoader) traceDigest(path string, b []byte) {
+	if d := fl.digestTracer(); d != nil {
+		d.Digested(path, sha256.Sum256(b))
 	}
-	return fl.fSys.ReadFile(path)
 }
 
-// Cleanup runs the cleaner.
+// traceLoaded traces the file, or, if it's in an
+// artifact, the artifact.
+func (fl *fileLoader) traceLoaded(path string) {
+	if fl.tracer == nil {
+		return
+	}
+	if a := fl.containingArtifact(); a != nil {
+		fl.tracer.Loaded(a.ref, nil)
+	} else {
+		fl.tracer.Loaded(path, fl.containingRepo())
+	}
+	fl.traceDepends(fl.node(path, NodeFile))
+}
+
+func (fl *fileLoader) loadURL(u string) ([]byte, error) {
+	b, err := fl.opts.loadURL(u)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassRemote,
+			messages.WithID(messages.URLFetchFailed, err))
+	}
+	b, err = fl.opts.Decryption.decrypt(u, b)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassLoad, err)
+	}
+	if fl.tracer != nil {
+		fl.tracer.Loaded(u, nil)
+		fl.traceDepends(Node{Kind: NodeURL, Path: u})
+	}
+	return b, nil
+}
+
+func (fl *fileLoader) traceRoot() {
+	if fl.tracer == nil {
+		return
+	}
+	if a := fl.containingArtifact(); a != nil {
+		fl.tracer.Loaded(a.ref, nil)
+		return
+	}
+	fl.tracer.Rooted(fl.root.String(), fl.containingRepo())
+}
+
+// traceDepends tells a GraphTracer that the loader
+// read, or made a loader at, to.
+func (fl *fileLoader) traceDepends(to Node) {
+	g, ok := fl.tracer.(GraphTracer)
+	if !ok {
+		return
+	}
+	from := fl.node(fl.root.String(), NodeDirectory)
+	// Artifacts, and objects, are nodes as a whole.
+	if from != to {
+		g.Depends(from, to)
+	}
+}
+
+// node returns the Node of path, an absolute path of
+// a file or directory in the loader's root or, in a
+// clone or artifact, anywhere in it.
+func (fl *fileLoader) node(path string, kind NodeKind) Node {
+	if a := fl.containingArtifact(); a != nil {
+		if IsObjectURL(a.ref) {
+			return Node{Kind: NodeObjects, Path: a.ref}
+		}
+		if IsArchiveURL(a.ref) {
+			return Node{Kind: NodeArchive, Path: a.ref}
+		}
+		return Node{Kind: NodeArtifact, Path: a.ref}
+	}
+	r := fl.containingRepo()
+	if r == nil {
+		return Node{Kind: kind, Path: path}
+	}
+	rel, err := filepath.Rel(r.CloneDir().String(), path)
+	if err != nil || rel == "." {
+		rel = ""
+	}
+	return Node{
+		Kind:   kind,
+		Path:   filepath.ToSlash(rel),
+		Repo:   r.CloneSpec(),
+		Ref:    r.Ref,
+		Commit: r.Commit,
+	}
+}
+
+// Cleanup runs the cleaner, and cleans up the
+// loaders Prefetch made that New didn't return.
 func (fl *fileLoader) Cleanup() error {
+	for path, p := range fl.prefetched {
+		if p.ldr != nil {
+			p.ldr.Cleanup()
+		}
+		delete(fl.prefetched, path)
+	}
 	return fl.cleaner()
 }
@@ -25,9 +25,11 @@ import (
 	"strings"
 	"testing"
 
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/git"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
 	"sigs.k8s.io/kustomize/v3/pkg/validators"
 )
@@ -64,6 +66,14 @@ func MakeFakeFs(td []testData) fs.FileSystem {
 	return fSys
 }
 
+// testOptions returns the default options, but
+// for the cloner, if one is given.
+func testOptions(c git.Cloner) *Options {
+	o := DefaultOptions()
+	o.Cloner = c
+	return o.complete()
+}
+
 func makeLoader() *fileLoader {
 	return NewFileLoaderAtRoot(validators.MakeFakeValidator(), MakeFakeFs(testCases))
 
@@ -312,7 +322,9 @@ func TestRestrictionRootOnlyInRealLoader(t *testing.T) {
 	if err == nil {
 		t.Fatalf("expected error")
 	}
-	if !strings.Contains(err.Error(), "is not in or below") {
+	if !strings.Contains(err.Error(), "is not in or below") ||
+		!kusterr.Is(err, ErrLoadRestricted) ||
+		messages.IDOf(err) != messages.FileNotInRoot {
 		t.Fatalf("unexpected err: %v", err)
 	}
 
@@ -400,7 +412,7 @@ whatever
 	}
 	l, err := newLoaderAtGitClone(
 		repoSpec, validators.MakeFakeValidator(), fSys, nil,
-		git.DoNothingCloner(fs.ConfirmedDir(coRoot)))
+		testOptions(git.DoNothingCloner(fs.ConfirmedDir(coRoot))))
 	if err != nil {
 		t.Fatalf("unexpected err: %v\n", err)
 	}
@@ -408,11 +420,12 @@ whatever
 		t.Fatalf("expected root '%s', got '%s'\n",
 			coRoot+"/"+pathInRepo, l.Root())
 	}
-	if _, err = l.New(url); err == nil {
-		t.Fatalf("expected cycle error 1")
+	if _, err = l.New(url); !kusterr.Is(err, ErrCycleDetected) ||
+		messages.IDOf(err) != messages.CycleRepo {
+		t.Fatalf("expected cycle error 1, got %v", err)
 	}
-	if _, err = l.New(rootUrl + "/" + "foo"); err == nil {
-		t.Fatalf("expected cycle error 2")
+	if _, err = l.New(rootUrl + "/" + "foo"); !kusterr.Is(err, ErrCycleDetected) {
+		t.Fatalf("expected cycle error 2, got %v", err)
 	}
 
 	pathInRepo = "foo/overlay"
@@ -428,6 +441,52 @@ whatever
 	}
 }
 
+func TestNewLoaderAtGitCloneWithSha256(t *testing.T) {
+	cloneRoot, err := ioutil.TempDir("", "kustomize-test-")
+	if err != nil {
+		t.Fatalf("unexpected err: %v\n", err)
+	}
+	defer os.RemoveAll(cloneRoot)
+	fSys := fs.MakeRealFS()
+	fSys.MkdirAll(filepath.Join(cloneRoot, "foo", "base"))
+	fSys.MkdirAll(filepath.Join(cloneRoot, "foo", "other"))
+	fSys.WriteFile(
+		filepath.Join(cloneRoot, "foo", "base", "kustomization.yaml"),
+		[]byte("resources:\n- ../other\n"))
+	sum, err := git.TreeDigest(filepath.Join(cloneRoot, "foo", "base"))
+	if err != nil {
+		t.Fatalf("unexpected err: %v\n", err)
+	}
+
+	url := "github.com/someOrg/someRepo/foo/base?ref=v1&sha256="
+	repoSpec, err := git.NewRepoSpecFromUrl(url + sum)
+	if err != nil {
+		t.Fatalf("unexpected err: %v\n", err)
+	}
+	l, err := newLoaderAtGitClone(
+		repoSpec, validators.MakeFakeValidator(), fSys, nil,
+		testOptions(git.DoNothingCloner(fs.ConfirmedDir(cloneRoot))))
+	if err != nil {
+		t.Fatalf("unexpected err: %v\n", err)
+	}
+	// The digest doesn't cover the rest of the repo.
+	_, err = l.New("../other")
+	if err == nil || !strings.Contains(err.Error(), "only covers") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	repoSpec, err = git.NewRepoSpecFromUrl(url + strings.Repeat("0", 64))
+	if err != nil {
+		t.Fatalf("unexpected err: %v\n", err)
+	}
+	_, err = newLoaderAtGitClone(
+		repoSpec, validators.MakeFakeValidator(), fSys, nil,
+		testOptions(git.DoNothingCloner(fs.ConfirmedDir(cloneRoot))))
+	if err == nil || !strings.Contains(err.Error(), "has sha256="+sum) {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
 func TestLoaderDisallowsLocalBaseFromRemoteOverlay(t *testing.T) {
 	// Define an overlay-base structure in the file system.
 	topDir := "/whatever"
@@ -479,7 +538,7 @@ func TestLoaderDisallowsLocalBaseFromRemoteOverlay(t *testing.T) {
 	}
 	l1, err = newLoaderAtGitClone(
 		repoSpec, validators.MakeFakeValidator(), fSys, nil,
-		git.DoNothingCloner(fs.ConfirmedDir(cloneRoot)))
+		testOptions(git.DoNothingCloner(fs.ConfirmedDir(cloneRoot))))
 	if err != nil {
 		t.Fatalf("unexpected err: %v\n", err)
 	}
@@ -518,7 +577,7 @@ func TestLocalLoaderReferencingGitBase(t *testing.T) {
 	}
 	l1 := newLoaderAtConfirmedDir(
 		RestrictionRootOnly, validators.MakeFakeValidator(), root, fSys, nil,
-		git.DoNothingCloner(fs.ConfirmedDir(cloneRoot)))
+		testOptions(git.DoNothingCloner(fs.ConfirmedDir(cloneRoot))))
 	if l1.Root() != topDir {
 		t.Fatalf("unexpected root %s", l1.Root())
 	}
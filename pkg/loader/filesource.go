@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"io"
+	"log"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+)
+
+// DefaultGeneratorFileWarnSize is the size of a file a
+// ConfigMap or Secret is generated from past which a
+// build warns, as the API server refuses objects over
+// 1MiB, and many such files bloat the build's memory.
+const DefaultGeneratorFileWarnSize = 1 << 20
+
+const flagGeneratorFileWarnSize = "generator-file-warn-size"
+
+// AddFlagGeneratorFileWarnSize adds a flag setting
+// the size of a file source past which builds warn.
+func (o *Options) AddFlagGeneratorFileWarnSize(set *pflag.FlagSet) {
+	set.Int64Var(
+		&o.GeneratorFileWarnSize, flagGeneratorFileWarnSize,
+		DefaultGeneratorFileWarnSize,
+		"Size in bytes of a file a ConfigMap or Secret is "+
+			"generated from past which the build warns; 0 never warns.")
+}
+
+// loadString returns the content of a file source,
+// streamed into a string of the file's size, rather
+// than loaded then copied into a string.
+func (fl *fileLoader) loadString(path string) (string, error) {
+	r, size, err := fl.open(path)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	var b strings.Builder
+	if size > 0 {
+		b.Grow(int(size))
+	}
+	n, err := io.Copy(&b, r)
+	if err != nil {
+		return "", kusterr.WithClass(kusterr.ClassLoad, err)
+	}
+	if max := fl.opts.GeneratorFileWarnSize; max > 0 && n > max {
+		log.Printf(
+			"warning: file source %s has %d bytes, more than "+
+				"--%s %d", path, n, flagGeneratorFileWarnSize, max)
+	}
+	return b.String(), nil
+}
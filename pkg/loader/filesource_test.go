@@ -0,0 +1,81 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+func TestOpen(t *testing.T) {
+	saved := runDecryption
+	runDecryption = func(args []string, stdin []byte) ([]byte, error) {
+		return []byte("kind: Secret"), nil
+	}
+	defer func() { runDecryption = saved }()
+	l := makeDecryptingLoader()
+	l.opts.Decryption = Decryption{GitCryptKey: "key"}
+	for path, content := range map[string]string{
+		"plain.yaml": "kind: Pod",
+		"patch.yaml": "kind: Secret",
+	} {
+		r, err := l.Open(path)
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		b, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil || string(b) != content {
+			t.Fatalf("%s: expected '%s', got '%s', %v", path, content, b, err)
+		}
+	}
+	if _, err := l.Open("missing.yaml"); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if _, err := l.Open("../outside.yaml"); err == nil ||
+		!strings.Contains(err.Error(), "security") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestFileSourceWarnSize(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	l := makeDecryptingLoader()
+	for _, size := range []int64{0, 9, DefaultGeneratorFileWarnSize} {
+		l.opts.GeneratorFileWarnSize = size
+		pairs, err := l.LoadKvPairs(types.GeneratorArgs{
+			DataSources: types.DataSources{
+				FileSources: []string{"pod=plain.yaml"}}})
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if len(pairs) != 1 || pairs[0].Key != "pod" ||
+			pairs[0].Value != "kind: Pod" {
+			t.Fatalf("unexpected pairs %v", pairs)
+		}
+	}
+	if buf.String() != "" {
+		t.Fatalf("unexpected warning: %s", buf.String())
+	}
+	l.opts.GeneratorFileWarnSize = 8
+	if _, err := l.LoadKvPairs(types.GeneratorArgs{
+		DataSources: types.DataSources{
+			FileSources: []string{"plain.yaml"}}}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !strings.Contains(buf.String(),
+		"warning: file source plain.yaml has 9 bytes, "+
+			"more than --generator-file-warn-size 8") {
+		t.Fatalf("unexpected warning: %s", buf.String())
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+)
+
+// HTTPOptions control loading files from http(s) URLs,
+// e.g. a raw manifest a web server hosts, listed as a
+// resource.  Git repositories are cloned instead, as
+// remote bases.
+type HTTPOptions struct {
+	// Timeout bounds each request, including reading
+	// the body.  Zero means no timeout.
+	Timeout time.Duration
+}
+
+// DefaultHTTPOptions allow URLs, with a timeout.
+var DefaultHTTPOptions = HTTPOptions{
+	Timeout: 30 * time.Second,
+}
+
+const (
+	flagHTTPTimeout = "http-timeout"
+	flagHTTPDisable = "disable-http"
+)
+
+// AddFlagsHTTP adds flags setting the HTTPOptions, and
+// --disable-http, the deprecated --network=false.
+func (o *Options) AddFlagsHTTP(set *pflag.FlagSet) {
+	set.DurationVar(
+		&o.HTTP.Timeout, flagHTTPTimeout,
+		DefaultHTTPOptions.Timeout,
+		"Longest to wait for a file loaded from an http(s) URL; "+
+			"0 for no limit.")
+	set.Var(
+		offlineValue{&o.Git.Network}, flagHTTPDisable,
+		"Refuse to load files from http(s) URLs, e.g. in an "+
+			"air-gapped build.")
+	set.Lookup(flagHTTPDisable).NoOptDefVal = "true"
+	set.MarkDeprecated(flagHTTPDisable, "use --network=false")
+}
+
+// offlineValue is a bool flag that, if true,
+// disallows the network.
+type offlineValue struct {
+	network *bool
+}
+
+func (v offlineValue) String() string {
+	return strconv.FormatBool(v.network != nil && !*v.network)
+}
+
+func (v offlineValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*v.network = !b
+	return nil
+}
+
+func (v offlineValue) Type() string {
+	return "bool"
+}
+
+// fileExtensions are those of the URLs taken to be
+// files, rather than git repositories.
+var fileExtensions = []string{".yaml", ".yml", ".json"}
+
+// IsFileURL is true if s is an http(s) URL of a file,
+// which Load fetches, rather than of a git repository,
+// which New clones.
+func IsFileURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" ||
+		(u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(u.Path))
+	for _, x := range fileExtensions {
+		if ext == x {
+			return true
+		}
+	}
+	return false
+}
+
+// FetchURL returns the file at the http(s) URL as is,
+// e.g. still encrypted, to copy it rather than load it.
+func (o Options) FetchURL(u string) ([]byte, error) {
+	return o.loadURL(u)
+}
+
+// loadURL fetches the file at the URL, bounded by
+// the timeout and by the largest file remote bases
+// may have.
+func (o *Options) loadURL(u string) ([]byte, error) {
+	if err := o.Git.ErrIfOffline("load", u); err != nil {
+		return nil, err
+	}
+	max := o.RemoteLimits.MaxFileSize
+	var b []byte
+	err := o.Git.Retry(func() (err error) {
+		b, err = fetch(u, max, o.HTTP.Timeout)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if max > 0 && int64(len(b)) > max {
+		return nil, fmt.Errorf(
+			"security; %s has more than --%s %d bytes",
+			u, flagRemoteMaxFileSize, max)
+	}
+	return b, nil
+}
+
+// fetch gets the file at the URL, reading at most
+// a byte more than max, if max isn't zero, within
+// the timeout.  Errors of statuses other than those
+// of an overloaded or failing server are Permanent.
+func fetch(u string, max int64, timeout time.Duration) ([]byte, error) {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(u)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %s", u)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("fetching %s: %s", u, resp.Status)
+		if resp.StatusCode != http.StatusTooManyRequests &&
+			resp.StatusCode < http.StatusInternalServerError {
+			err = git.Permanent(err)
+		}
+		return nil, err
+	}
+	var body io.Reader = resp.Body
+	if max > 0 {
+		body = io.LimitReader(body, max+1)
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %s", u)
+	}
+	return b, nil
+}
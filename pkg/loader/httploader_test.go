@@ -0,0 +1,153 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestIsFileURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://example.com/manifests/app.yaml":          true,
+		"http://example.com/app.yml?token=abc":            true,
+		"https://example.com/crds/app.JSON":               true,
+		"https://github.com/someOrg/someRepo/base?ref=v1": false,
+		"github.com/someOrg/someRepo/app.yaml":            false,
+		"ftp://example.com/app.yaml":                      false,
+		"app.yaml":                                        false,
+		"/app/app.yaml":                                   false,
+	}
+	for u, expected := range tests {
+		if actual := IsFileURL(u); actual != expected {
+			t.Errorf("%s: expected %v, got %v", u, expected, actual)
+		}
+	}
+}
+
+func makeServer() *httptest.Server {
+	flaky := 0
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/app.yaml":
+				w.Write([]byte("kind: Pod"))
+			case "/flaky.yaml":
+				// Two of every three requests fail.
+				if flaky++; flaky%3 != 0 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Write([]byte("kind: Pod"))
+			case "/slow.yaml":
+				time.Sleep(time.Second)
+				w.Write([]byte("kind: Pod"))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+}
+
+func TestLoadURL(t *testing.T) {
+	s := makeServer()
+	defer s.Close()
+	recorder := NewDepRecorder()
+	l := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		fs.ConfirmedDir("/app"), fs.MakeFakeFS(), nil, testOptions(nil))
+	l.tracer = recorder
+	b, err := l.Load(s.URL + "/app.yaml")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if string(b) != "kind: Pod" {
+		t.Fatalf("unexpected content %s", b)
+	}
+	deps := recorder.Dependencies()
+	if len(deps.Files) != 0 || len(deps.Remotes) != 1 ||
+		deps.Remotes[0] != s.URL+"/app.yaml" {
+		t.Fatalf("unexpected dependencies %v", deps)
+	}
+
+	_, err = l.Load(s.URL + "/missing.yaml")
+	if err == nil || !strings.Contains(err.Error(), "404") ||
+		kusterr.ClassOf(err) != kusterr.ClassRemote {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	_, err = l.Load(s.URL + "/flaky.yaml")
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	l.opts.Git.Retries = 1
+	b, err = l.Load(s.URL + "/flaky.yaml")
+	if err != nil || string(b) != "kind: Pod" {
+		t.Fatalf("unexpected content %s: %v", b, err)
+	}
+	// Only failures a retry might fix are retried.
+	_, err = l.Load(s.URL + "/missing.yaml")
+	if err == nil || strings.Contains(err.Error(), "attempts") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	l.opts.Git.Retries = git.DefaultRemoteRetries
+
+	l.opts.RemoteLimits = RemoteLimits{MaxFileSize: 5}
+	_, err = l.Load(s.URL + "/app.yaml")
+	if err == nil || !strings.Contains(err.Error(), "--remote-max-file-size 5") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	l.opts.RemoteLimits = DefaultRemoteLimits
+
+	l.opts.HTTP = HTTPOptions{Timeout: 10 * time.Millisecond}
+	_, err = l.Load(s.URL + "/slow.yaml")
+	if err == nil {
+		t.Fatalf("expected a timeout")
+	}
+
+	l.opts.Git.Network = false
+	_, err = l.Load(s.URL + "/app.yaml")
+	if err == nil || !strings.Contains(err.Error(), "--network=false") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestFlagDisableHTTP(t *testing.T) {
+	for args, network := range map[string]bool{
+		"":                     true,
+		"--disable-http":       false,
+		"--disable-http=false": true,
+		"--network=false":      false,
+	} {
+		o := DefaultOptions()
+		set := pflag.NewFlagSet("build", pflag.ContinueOnError)
+		set.SetOutput(ioutil.Discard)
+		o.AddFlags(set)
+		if err := set.Parse(strings.Fields(args)); err != nil {
+			t.Fatalf("%s: unexpected error: %v", args, err)
+		}
+		if o.Git.Network != network {
+			t.Fatalf("%s: expected network %t", args, network)
+		}
+	}
+}
+
+func TestNewRefusesFileURL(t *testing.T) {
+	l := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		fs.ConfirmedDir("/app"), fs.MakeFakeFS(), nil, testOptions(nil))
+	_, err := l.New("https://example.com/app.yaml")
+	if err == nil || kusterr.ClassOf(err) == kusterr.ClassRemote {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
@@ -68,11 +68,11 @@ func (fl *fileLoader) keyValuesFromFileSources(sources []string) ([]types.Pair,
 		if err != nil {
 			return nil, err
 		}
-		content, err := fl.Load(fPath)
+		content, err := fl.loadString(fPath)
 		if err != nil {
 			return nil, err
 		}
-		kvs = append(kvs, types.Pair{Key: k, Value: string(content)})
+		kvs = append(kvs, types.Pair{Key: k, Value: content})
 	}
 	return kvs, nil
 }
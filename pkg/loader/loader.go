@@ -18,6 +18,7 @@ limitations under the License.
 package loader
 
 import (
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/git"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
@@ -33,16 +34,65 @@ func NewLoader(
 	lr LoadRestrictorFunc,
 	v ifc.Validator,
 	target string, fSys fs.FileSystem) (ifc.Loader, error) {
-	repoSpec, err := git.NewRepoSpecFromUrl(target)
-	if err == nil {
+	return NewTracingLoader(lr, v, target, fSys, nil)
+}
+
+// NewTracingLoader is like NewLoader, but reports the
+// activity of the returned loader, and of all loaders
+// it creates, to the given tracer.
+func NewTracingLoader(
+	lr LoadRestrictorFunc,
+	v ifc.Validator,
+	target string, fSys fs.FileSystem, t Tracer) (ifc.Loader, error) {
+	return NewLoaderWithOptions(
+		lr, v, target, fSys, t, DefaultOptions())
+}
+
+// NewLoaderWithCloner is like NewTracingLoader, but gets
+// remote bases with the given cloner, e.g. one caching
+// clones across builds.
+func NewLoaderWithCloner(
+	lr LoadRestrictorFunc,
+	v ifc.Validator,
+	target string, fSys fs.FileSystem, t Tracer,
+	cloner git.Cloner) (ifc.Loader, error) {
+	o := DefaultOptions()
+	o.Cloner = cloner
+	return NewLoaderWithOptions(lr, v, target, fSys, t, o)
+}
+
+// NewLoaderWithOptions is like NewTracingLoader, but
+// the returned loader, and all loaders it creates,
+// follow the given options rather than the defaults.
+func NewLoaderWithOptions(
+	lr LoadRestrictorFunc,
+	v ifc.Validator,
+	target string, fSys fs.FileSystem, t Tracer,
+	o Options) (ifc.Loader, error) {
+	opts := o.complete()
+	var fl *fileLoader
+	repoSpec, err := opts.Git.NewRepoSpecFromUrl(target)
+	if IsArchiveURL(target) {
+		fl, err = newLoaderAtArchive(target, v, fSys, nil, opts)
+		if err != nil {
+			return nil, err
+		}
+	} else if err == nil {
 		// The target qualifies as a remote git target.
-		return newLoaderAtGitClone(
-			repoSpec, v, fSys, nil, git.ClonerUsingGitExec)
-	}
-	root, err := demandDirectoryRoot(fSys, target)
-	if err != nil {
-		return nil, err
+		fl, err = newLoaderAtGitClone(
+			repoSpec, v, fSys, nil, opts)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		root, err := demandDirectoryRoot(fSys, target)
+		if err != nil {
+			return nil, kusterr.WithClass(kusterr.ClassLoad, err)
+		}
+		fl = newLoaderAtConfirmedDir(
+			lr, v, root, fSys, nil, opts)
 	}
-	return newLoaderAtConfirmedDir(
-		lr, v, root, fSys, nil, git.ClonerUsingGitExec), nil
+	fl.tracer = t
+	fl.traceRoot()
+	return fl, nil
 }
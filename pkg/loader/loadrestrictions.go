@@ -18,9 +18,12 @@ package loader
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
 )
 
 //go:generate stringer -type=loadRestrictions
@@ -30,6 +33,7 @@ const (
 	unknown loadRestrictions = iota
 	rootOnly
 	none
+	repoRootOnly
 )
 
 const (
@@ -40,7 +44,9 @@ var (
 	flagValue = rootOnly.String()
 	flagHelp  = "if set to '" + none.String() +
 		"', local kustomizations may load files from outside their root. " +
-		"This does, however, break the relocatability of the kustomization."
+		"This does, however, break the relocatability of the kustomization.  " +
+		"If set to '" + repoRootOnly.String() + "', they may load files " +
+		"from anywhere in the git working tree enclosing their root."
 )
 
 func AddFlagLoadRestrictor(set *pflag.FlagSet) {
@@ -49,17 +55,26 @@ func AddFlagLoadRestrictor(set *pflag.FlagSet) {
 		rootOnly.String(), flagHelp)
 }
 
+// ValidateFlagLoadRestrictor returns the LoadRestrictorFunc
+// the load_restrictor flag names, with the SymlinkPolicy
+// the symlinks flag names.
 func ValidateFlagLoadRestrictor() (LoadRestrictorFunc, error) {
+	p, err := validateFlagSymlinks()
+	if err != nil {
+		return nil, err
+	}
 	switch flagValue {
 	case rootOnly.String():
-		return RestrictionRootOnly, nil
+		return WithSymlinkPolicy(RestrictionRootOnly, p), nil
+	case repoRootOnly.String():
+		// Links, too, may reach anywhere in the repo.
+		return atRepoRoot(WithSymlinkPolicy(RestrictionRootOnly, p)), nil
 	case none.String():
-		return RestrictionNone, nil
+		return WithSymlinkPolicy(RestrictionNone, p), nil
 	default:
-		return nil, fmt.Errorf(
-			"illegal flag value --%s %s; legal values: %v",
+		return nil, messages.Errorf(messages.FlagIllegalValue,
 			flagName, flagValue,
-			[]string{rootOnly.String(), none.String()})
+			[]string{rootOnly.String(), repoRootOnly.String(), none.String()})
 	}
 }
 
@@ -76,13 +91,48 @@ func RestrictionRootOnly(
 		return "", fmt.Errorf("'%s' must be a file", path)
 	}
 	if !d.HasPrefix(root) {
-		return "", fmt.Errorf(
-			"security; file '%s' is not in or below '%s'",
-			path, root)
+		return "", kusterr.WithKind(ErrLoadRestricted, messages.Errorf(
+			messages.FileNotInRoot, path, root))
 	}
 	return d.Join(f), nil
 }
 
+// RestrictionRepoRootOnly is RestrictionRootOnly, but
+// with the root of the git working tree enclosing root,
+// e.g. of a monorepo, in place of root.  Outside of any
+// working tree, it's RestrictionRootOnly.
+func RestrictionRepoRootOnly(
+	fSys fs.FileSystem, root fs.ConfirmedDir, path string) (string, error) {
+	return atRepoRoot(RestrictionRootOnly)(fSys, root, path)
+}
+
+// atRepoRoot returns a LoadRestrictorFunc that applies
+// lr with the root of the git working tree enclosing
+// root in place of root.
+func atRepoRoot(lr LoadRestrictorFunc) LoadRestrictorFunc {
+	return func(
+		fSys fs.FileSystem, root fs.ConfirmedDir, path string) (string, error) {
+		return lr(fSys, repoRoot(fSys, root), path)
+	}
+}
+
+// repoRoot returns the closest directory, of dir and
+// those above it, holding a .git directory, or, in a
+// worktree or submodule, file; else dir.
+func repoRoot(fSys fs.FileSystem, dir fs.ConfirmedDir) fs.ConfirmedDir {
+	for d := dir; ; {
+		dotGit := d.Join(".git")
+		if fSys.Exists(dotGit) || fSys.IsDir(dotGit) {
+			return d
+		}
+		up := fs.ConfirmedDir(filepath.Dir(d.String()))
+		if up == d {
+			return dir
+		}
+		d = up
+	}
+}
+
 func RestrictionNone(
 	_ fs.FileSystem, _ fs.ConfirmedDir, path string) (string, error) {
 	return path, nil
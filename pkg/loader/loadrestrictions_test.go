@@ -72,3 +72,44 @@ func TestRestrictionRootOnly(t *testing.T) {
 		t.Fatalf("unexpected err: %s", err)
 	}
 }
+
+func TestRestrictionRepoRootOnly(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.MkdirAll("/repo/.git")
+	fSys.WriteFile("/repo/shared/patch.yaml", []byte{})
+	root := fs.ConfirmedDir("/repo/apps/overlay")
+
+	// Legal; above root, in the repo.
+	path := "/repo/apps/overlay/../../shared/patch.yaml"
+	p, err := RestrictionRepoRootOnly(fSys, root, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != "/repo/shared/patch.yaml" {
+		t.Fatalf("expected '/repo/shared/patch.yaml', got '%s'", p)
+	}
+
+	// Illegal; outside the repo.
+	path = "/other/patch.yaml"
+	_, err = RestrictionRepoRootOnly(fSys, root, path)
+	if err == nil {
+		t.Fatal("should have an error")
+	}
+	if !strings.Contains(
+		err.Error(),
+		"file '/other/patch.yaml' is not in or below '/repo'") {
+		t.Fatalf("unexpected err: %s", err)
+	}
+
+	// Outside any repo, root is the limit.
+	root = fs.ConfirmedDir("/tmp/foo")
+	_, err = RestrictionRepoRootOnly(fSys, root, "/tmp/illegal")
+	if err == nil {
+		t.Fatal("should have an error")
+	}
+	if !strings.Contains(
+		err.Error(),
+		"file '/tmp/illegal' is not in or below '/tmp/foo'") {
+		t.Fatalf("unexpected err: %s", err)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+)
+
+// Objects in S3 and GCS buckets are read with the aws and
+// gsutil programs, so with whatever credentials they find,
+// e.g. in the environment or from the instance metadata.
+const (
+	schemeS3  = "s3://"
+	schemeGCS = "gs://"
+
+	programAWS    = "aws"
+	programGsutil = "gsutil"
+)
+
+// runObjectStore runs the aws or gsutil program;
+// tests replace it.
+var runObjectStore = runUsingExec
+
+// IsObjectURL is true if s is the URL of an object,
+// or a prefix of objects, in an S3 or GCS bucket,
+// e.g. s3://someBucket/bases/prod.
+func IsObjectURL(s string) bool {
+	return strings.HasPrefix(s, schemeS3) || strings.HasPrefix(s, schemeGCS)
+}
+
+// splitObjectURL splits the URL of a base, like those
+// of OCI artifacts, into the prefix of the objects to
+// fetch, ending in a slash, and the path of a directory
+// in them, if given after a //, as in
+// s3://someBucket/bases//overlays/prod.
+func splitObjectURL(s string) (string, string) {
+	scheme := schemeS3
+	if strings.HasPrefix(s, schemeGCS) {
+		scheme = schemeGCS
+	}
+	rest := strings.TrimPrefix(s, scheme)
+	subdir := ""
+	if i := strings.Index(rest, "//"); i >= 0 {
+		rest, subdir = rest[:i], rest[i+2:]
+	}
+	return scheme + strings.TrimSuffix(rest, "/") + "/", subdir
+}
+
+// object is an object listed under a prefix.
+type object struct {
+	// name is its path under the prefix.
+	name string
+	size int64
+}
+
+var (
+	// An aws s3 ls --recursive line, e.g.
+	// "2019-10-01 12:00:00       1234 bases/prod/app.yaml",
+	// has the key from the bucket up.
+	awsListLine = regexp.MustCompile(`^\S+\s+\S+\s+(\d+)\s(.+)$`)
+	// A gsutil ls -l line, e.g.
+	// "      1234  2019-10-01T12:00:00Z  gs://b/bases/prod/app.yaml",
+	// has the URL.
+	gsutilListLine = regexp.MustCompile(`^\s*(\d+)\s+\S+\s+(gs://.+)$`)
+)
+
+// listObjects lists the objects under prefix, a URL
+// ending in a slash.
+func listObjects(prefix string) ([]object, error) {
+	var args []string
+	var bucketURL string
+	if strings.HasPrefix(prefix, schemeGCS) {
+		args = []string{programGsutil, "ls", "-l", prefix + "**"}
+	} else {
+		args = []string{programAWS, "s3", "ls", "--recursive", prefix}
+		rest := strings.TrimPrefix(prefix, schemeS3)
+		bucketURL = schemeS3 + rest[:strings.Index(rest, "/")+1]
+	}
+	out, err := runObjectStore(args, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing %s", prefix)
+	}
+	var result []object
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		var size, u string
+		if bucketURL == "" {
+			m := gsutilListLine.FindStringSubmatch(s.Text())
+			if m == nil {
+				continue
+			}
+			size, u = m[1], m[2]
+		} else {
+			m := awsListLine.FindStringSubmatch(s.Text())
+			if m == nil {
+				continue
+			}
+			size, u = m[1], bucketURL+m[2]
+		}
+		// Directory placeholders end in a slash.
+		if !strings.HasPrefix(u, prefix) || strings.HasSuffix(u, "/") {
+			continue
+		}
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, object{name: u[len(prefix):], size: n})
+	}
+	return result, s.Err()
+}
+
+// readObject returns the content of the object at u.
+func readObject(u string) ([]byte, error) {
+	args := []string{programAWS, "s3", "cp", "--only-show-errors", u, "-"}
+	if strings.HasPrefix(u, schemeGCS) {
+		args = []string{programGsutil, "cat", u}
+	}
+	b, err := runObjectStore(args, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", u)
+	}
+	return b, nil
+}
+
+// loadObject reads the object at u, e.g. a resource
+// kept in a bucket.
+func (fl *fileLoader) loadObject(u string) ([]byte, error) {
+	if err := fl.opts.Git.ErrIfOffline("read", u); err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassRemote, err)
+	}
+	b, err := readObject(u)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassRemote,
+			messages.WithID(messages.ObjectReadFailed, err))
+	}
+	if max := fl.opts.RemoteLimits.MaxFileSize; max > 0 && int64(len(b)) > max {
+		return nil, kusterr.WithClass(kusterr.ClassRemote, messages.Errorf(
+			messages.RemoteFileTooLarge, u, flagRemoteMaxFileSize, max))
+	}
+	b, err = fl.opts.Decryption.decrypt(u, b)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassLoad, err)
+	}
+	if fl.tracer != nil {
+		fl.tracer.Loaded(u, nil)
+		fl.traceDepends(Node{Kind: NodeURL, Path: u})
+	}
+	return b, nil
+}
+
+// newLoaderAtObjects returns a new Loader rooted in a
+// temporary directory holding the objects of a bucket
+// under the prefix of s, which, like an artifact, it
+// may not load files from outside of.
+func newLoaderAtObjects(
+	s string, v ifc.Validator, fSys fs.FileSystem,
+	referrer *fileLoader, opts *Options) (*fileLoader, error) {
+	if err := opts.Git.ErrIfOffline("read", s); err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassRemote, err)
+	}
+	prefix, subdir := splitObjectURL(s)
+	objects, err := listObjects(prefix)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassRemote,
+			messages.WithID(messages.ObjectReadFailed, err))
+	}
+	if len(objects) == 0 {
+		return nil, kusterr.WithClass(kusterr.ClassRemote, messages.Errorf(
+			messages.NoObjects, prefix))
+	}
+	if max := opts.RemoteLimits.MaxFiles; max > 0 && len(objects) > max {
+		return nil, kusterr.WithClass(kusterr.ClassRemote, messages.Errorf(
+			messages.RemoteTooManyFiles, prefix, flagRemoteMaxFiles, max))
+	}
+	var total int64
+	for _, o := range objects {
+		total += o.size
+	}
+	if max := opts.RemoteLimits.MaxTotalBytes; max > 0 && total > max {
+		return nil, kusterr.WithClass(kusterr.ClassRemote, messages.Errorf(
+			messages.RemoteTooManyBytes, prefix, flagRemoteMaxTotalBytes, max))
+	}
+	dir, err := fs.NewTmpConfirmedDir()
+	if err != nil {
+		return nil, err
+	}
+	cleaner := func() error {
+		err := fSys.RemoveAll(dir.String())
+		fs.RemoveTmp(dir.String())
+		return err
+	}
+	for _, o := range objects {
+		path := dir.Join(filepath.FromSlash(o.name))
+		if !strings.HasPrefix(path, dir.String()+string(filepath.Separator)) {
+			cleaner()
+			return nil, kusterr.WithKind(ErrLoadRestricted, messages.Errorf(
+				messages.ObjectNotInPrefix, o.name, prefix))
+		}
+		b, err := readObject(prefix + o.name)
+		if err == nil {
+			err = fSys.MkdirAll(filepath.Dir(path))
+		}
+		if err == nil {
+			err = fSys.WriteFile(path, b)
+		}
+		if err != nil {
+			cleaner()
+			return nil, kusterr.WithClass(kusterr.ClassRemote,
+				messages.WithID(messages.ObjectReadFailed, err))
+		}
+	}
+	root, f, err := fSys.CleanedAbs(dir.Join(subdir))
+	if err == nil && f != "" {
+		err = messages.Errorf(messages.ExpectingDirectory, s, f)
+	}
+	if err == nil && !root.HasPrefix(dir) {
+		err = kusterr.WithKind(ErrLoadRestricted, messages.Errorf(
+			messages.PathNotInObjects, subdir))
+	}
+	if err != nil {
+		cleaner()
+		return nil, err
+	}
+	return &fileLoader{
+		// Objects, like clones, are never allowed
+		// to escape root.
+		loadRestrictor: RestrictionRootOnly,
+		validator:      v,
+		root:           root,
+		referrer:       referrer,
+		artifact:       &artifact{ref: s, dir: dir},
+		fSys:           fSys,
+		opts:           opts,
+		cleaner:        cleaner,
+		budget:         newRemoteBudget(opts.RemoteLimits),
+	}, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+// fakeObjectStore answers the aws and gsutil commands
+// the loader runs from the given objects, by URL.
+func fakeObjectStore(objects map[string]string) func([]string, []byte) ([]byte, error) {
+	return func(args []string, _ []byte) ([]byte, error) {
+		u := args[len(args)-1]
+		if u == "-" {
+			// aws s3 cp writes the object to stdout.
+			args = args[:len(args)-1]
+			u = args[len(args)-1]
+		}
+		command := strings.Join(args[:len(args)-1], " ")
+		var urls []string
+		for o := range objects {
+			urls = append(urls, o)
+		}
+		sort.Strings(urls)
+		var out strings.Builder
+		switch command {
+		case "aws s3 ls --recursive":
+			for _, o := range urls {
+				if strings.HasPrefix(o, u) {
+					key := o[strings.Index(o[len("s3://"):], "/")+len("s3://")+1:]
+					fmt.Fprintf(&out, "2019-10-01 12:00:00 %10d %s\n",
+						len(objects[o]), key)
+				}
+			}
+		case "gsutil ls -l":
+			for _, o := range urls {
+				if strings.HasPrefix(o, strings.TrimSuffix(u, "**")) {
+					fmt.Fprintf(&out, "%10d  2019-10-01T12:00:00Z  %s\n",
+						len(objects[o]), o)
+				}
+			}
+			fmt.Fprintf(&out, "TOTAL: %d objects\n", len(urls))
+		case "aws s3 cp --only-show-errors", "gsutil cat":
+			o, ok := objects[u]
+			if !ok {
+				return nil, errors.New("no such object")
+			}
+			out.WriteString(o)
+		default:
+			return nil, fmt.Errorf("unexpected command %v", args)
+		}
+		return []byte(out.String()), nil
+	}
+}
+
+func TestNewLoaderAtObjects(t *testing.T) {
+	for _, bucket := range []string{"s3://bucket", "gs://bucket"} {
+		saved := runObjectStore
+		runObjectStore = fakeObjectStore(map[string]string{
+			bucket + "/bases/base/kustomization.yaml": "resources: [pod.yaml]",
+			bucket + "/bases/base/pod.yaml":           "kind: Pod",
+			bucket + "/bases/prod/kustomization.yaml": "resources: [../base]",
+			bucket + "/bases/prod/":                   "",
+			bucket + "/basesx/secret.yaml":            "kind: Secret",
+			bucket + "/config.yaml":                   "kind: ConfigMap",
+		})
+		defer func() { runObjectStore = saved }()
+		fSys := fs.MakeFakeFS()
+		recorder := NewDepRecorder()
+		l := newLoaderAtConfirmedDir(
+			RestrictionRootOnly, validators.MakeFakeValidator(),
+			fs.ConfirmedDir("/app"), fSys, nil, testOptions(nil))
+		l.tracer = recorder
+
+		ldr, err := l.New(bucket + "/bases//prod")
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if !strings.HasSuffix(ldr.Root(), "/prod") {
+			t.Fatalf("unexpected root %s", ldr.Root())
+		}
+		base, err := ldr.New("../base")
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		b, err := base.Load("pod.yaml")
+		if err != nil || string(b) != "kind: Pod" {
+			t.Fatalf("unexpected %s, %v", b, err)
+		}
+		_, err = base.New("../..")
+		if err == nil || !strings.Contains(err.Error(), "security; bases in "+
+			"kustomizations found in buckets must be within the bucket") {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		dir := strings.TrimSuffix(ldr.Root(), "/prod")
+		if fSys.Exists(dir + "/secret.yaml") {
+			t.Fatalf("expected only objects under %s/bases/", bucket)
+		}
+
+		b, err = l.Load(bucket + "/config.yaml")
+		if err != nil || string(b) != "kind: ConfigMap" {
+			t.Fatalf("unexpected %s, %v", b, err)
+		}
+		deps := recorder.Dependencies()
+		expected := []string{bucket + "/bases//prod", bucket + "/config.yaml"}
+		if fmt.Sprint(deps.Remotes) != fmt.Sprint(expected) {
+			t.Fatalf("expected remotes %v, got %v", expected, deps.Remotes)
+		}
+
+		if err := ldr.Cleanup(); err != nil || fSys.Exists(dir) {
+			t.Fatalf("expected %s removed, got %v", dir, err)
+		}
+		_, err = l.New(bucket + "/missing")
+		if err == nil || !strings.Contains(err.Error(), "no objects under") {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		_, err = l.Load(bucket + "/missing.yaml")
+		if err == nil || !strings.Contains(err.Error(), "no such object") {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+}
+
+func TestNewLoaderAtObjectsLimits(t *testing.T) {
+	saved := runObjectStore
+	runObjectStore = fakeObjectStore(map[string]string{
+		"s3://bucket/base/kustomization.yaml": "resources: [pod.yaml]",
+		"s3://bucket/base/pod.yaml":           "kind: Pod",
+	})
+	defer func() { runObjectStore = saved }()
+	l := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		fs.ConfirmedDir("/app"), fs.MakeFakeFS(), nil, testOptions(nil))
+	l.opts.RemoteLimits = RemoteLimits{MaxFiles: 1}
+	_, err := l.New("s3://bucket/base")
+	if err == nil || !strings.Contains(err.Error(), "more than --remote-max-files 1") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"bytes"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/oci"
+)
+
+// isDocument is true if the file of an artifact is
+// YAML or JSON, by its name or media type.
+func isDocument(f oci.File) bool {
+	ext := strings.ToLower(path.Ext(f.Name))
+	for _, x := range fileExtensions {
+		if ext == x {
+			return true
+		}
+	}
+	return strings.Contains(f.MediaType, "yaml") ||
+		strings.Contains(f.MediaType, "json")
+}
+
+func (o *Options) newPuller() *oci.Puller {
+	return &oci.Puller{
+		Client:       &http.Client{Timeout: o.HTTP.Timeout},
+		MaxBlobSize:  o.RemoteLimits.MaxFileSize,
+		MaxTotalSize: o.RemoteLimits.MaxTotalBytes,
+	}
+}
+
+// ResolveArtifact returns the digest of the manifest
+// of the artifact s refers to, e.g. by tag, and maybe
+// with the path of a base in it.
+func (o Options) ResolveArtifact(s string) (string, error) {
+	s, _ = oci.SplitPath(s)
+	ref, err := oci.ParseReference(s)
+	if err != nil {
+		return "", err
+	}
+	if err := o.Git.ErrIfOffline("resolve", s); err != nil {
+		return "", err
+	}
+	return o.newPuller().Resolve(ref)
+}
+
+// pullArtifact pulls the artifact s refers to, which,
+// if pinned is true, must be pinned to a digest.  Like
+// fetching URLs, it's bounded by the timeout, and, like
+// cloning, by the RemoteLimits.
+func (o *Options) pullArtifact(s string, pinned bool) ([]oci.File, error) {
+	parse := oci.ParseReference
+	if pinned {
+		parse = oci.ParsePinnedReference
+	}
+	ref, err := parse(s)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassUsage, err)
+	}
+	if err := o.Git.ErrIfOffline("pull", s); err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassRemote, err)
+	}
+	files, err := o.newPuller().Pull(ref)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassRemote,
+			messages.WithID(messages.ArtifactPullFailed, err))
+	}
+	if max := o.RemoteLimits.MaxFiles; max > 0 && len(files) > max {
+		return nil, kusterr.WithClass(kusterr.ClassRemote, messages.Errorf(
+			messages.RemoteTooManyFiles, s, flagRemoteMaxFiles, max))
+	}
+	return files, nil
+}
+
+// loadOCI pulls the artifact, e.g. a bundle of
+// transformer configs, pinned to a digest, returning
+// its YAML and JSON files as one stream of YAML
+// documents.
+func (fl *fileLoader) loadOCI(s string) ([]byte, error) {
+	files, err := fl.opts.pullArtifact(s, true)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	for _, f := range files {
+		if !isDocument(f) {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n---\n")
+		}
+		b.Write(f.Data)
+	}
+	if b.Len() == 0 {
+		return nil, kusterr.WithClass(kusterr.ClassRemote, messages.Errorf(
+			messages.ArtifactNoDocuments, s))
+	}
+	if fl.tracer != nil {
+		fl.tracer.Loaded(s, nil)
+		fl.traceDepends(Node{Kind: NodeArtifact, Path: s})
+	}
+	return b.Bytes(), nil
+}
+
+// artifact is an OCI artifact of a base, or the
+// objects of a bucket, unpacked into a temporary
+// directory.
+type artifact struct {
+	// ref is the reference to the artifact, or the
+	// URL of the objects, as written
+	// in the kustomization file.
+	ref string
+	// dir holds its files.
+	dir fs.ConfirmedDir
+}
+
+// newLoaderAtArtifact returns a new Loader rooted in a
+// temporary directory holding the files of a pulled
+// OCI artifact, which, like a clone, it may not
+// load files from outside of.
+func newLoaderAtArtifact(
+	s string, v ifc.Validator, fSys fs.FileSystem,
+	referrer *fileLoader, opts *Options) (*fileLoader, error) {
+	ref, subdir := oci.SplitPath(s)
+	files, err := opts.pullArtifact(ref, false)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := fs.NewTmpConfirmedDir()
+	if err != nil {
+		return nil, err
+	}
+	cleaner := func() error {
+		err := fSys.RemoveAll(dir.String())
+		fs.RemoveTmp(dir.String())
+		return err
+	}
+	for _, f := range files {
+		path := dir.Join(filepath.FromSlash(f.Name))
+		err := fSys.MkdirAll(filepath.Dir(path))
+		if err == nil {
+			err = fSys.WriteFile(path, f.Data)
+		}
+		if err != nil {
+			cleaner()
+			return nil, errors.Wrapf(err, "unpacking %s", ref)
+		}
+	}
+	root, f, err := fSys.CleanedAbs(dir.Join(subdir))
+	if err == nil && f != "" {
+		err = messages.Errorf(messages.ExpectingDirectory, s, f)
+	}
+	if err == nil && !root.HasPrefix(dir) {
+		err = kusterr.WithKind(ErrLoadRestricted, messages.Errorf(
+			messages.PathNotInArtifact, subdir))
+	}
+	if err != nil {
+		cleaner()
+		return nil, err
+	}
+	return &fileLoader{
+		// Artifacts, like clones, are never allowed
+		// to escape root.
+		loadRestrictor: RestrictionRootOnly,
+		validator:      v,
+		root:           root,
+		referrer:       referrer,
+		artifact:       &artifact{ref: s, dir: dir},
+		fSys:           fSys,
+		opts:           opts,
+		cleaner:        cleaner,
+		budget:         newRemoteBudget(opts.RemoteLimits),
+	}, nil
+}
+
+func (fl *fileLoader) errIfArtifactContainmentViolation(
+	base fs.ConfirmedDir) error {
+	a := fl.containingArtifact()
+	if a == nil || base.HasPrefix(a.dir) {
+		return nil
+	}
+	id := messages.BaseNotInArtifact
+	if IsObjectURL(a.ref) {
+		id = messages.BaseNotInBucket
+	}
+	if IsArchiveURL(a.ref) {
+		id = messages.BaseNotInArchive
+	}
+	return kusterr.WithKind(
+		ErrLoadRestricted, messages.Errorf(id, base, a.ref))
+}
+
+// Looks back through referrers for an artifact, returning
+// nil if none found, or if a git repo is found first.
+func (fl *fileLoader) containingArtifact() *artifact {
+	if fl.artifact != nil {
+		return fl.artifact
+	}
+	if fl.referrer == nil || fl.repoSpec != nil {
+		return nil
+	}
+	return fl.referrer.containingArtifact()
+}
+
+func (fl *fileLoader) errIfArtifactCycle(s string) error {
+	if fl.artifact != nil && fl.artifact.ref == s {
+		return kusterr.WithKind(ErrCycleDetected, messages.Errorf(
+			messages.CycleArtifact, s))
+	}
+	if fl.referrer == nil {
+		return nil
+	}
+	return fl.referrer.errIfArtifactCycle(s)
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func digestOf(b string) string {
+	sum := sha256.Sum256([]byte(b))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// makeRegistry serves an artifact of the given files,
+// by title, returning the reference to it.
+func makeRegistry(files map[string]string) (*httptest.Server, string) {
+	blobs := make(map[string]string)
+	var layers []string
+	for title, content := range files {
+		blobs[digestOf(content)] = content
+		layers = append(layers, fmt.Sprintf(
+			`{"mediaType":"application/octet-stream","digest":"%s",`+
+				`"size":%d,"annotations":`+
+				`{"org.opencontainers.image.title":"%s"}}`,
+			digestOf(content), len(content), title))
+	}
+	m := `{"schemaVersion":2,"layers":[` + strings.Join(layers, ",") + `]}`
+	blobs[digestOf(m)] = m
+	s := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			i := strings.LastIndex(r.URL.Path, "/")
+			b, ok := blobs[r.URL.Path[i+1:]]
+			if !ok || !strings.HasPrefix(r.URL.Path, "/v2/org/configs/") {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(b))
+		}))
+	return s, "oci://" + strings.TrimPrefix(s.URL, "http://") +
+		"/org/configs@" + digestOf(m)
+}
+
+func TestLoadOCI(t *testing.T) {
+	s, ref := makeRegistry(map[string]string{
+		"configs/a.yaml": "namePrefix: []",
+		"README.md":      "# configs",
+	})
+	defer s.Close()
+	recorder := NewDepRecorder()
+	l := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		fs.ConfirmedDir("/app"), fs.MakeFakeFS(), nil, testOptions(nil))
+	l.tracer = recorder
+	b, err := l.Load(ref)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if string(b) != "namePrefix: []" {
+		t.Fatalf("unexpected content %s", b)
+	}
+	deps := recorder.Dependencies()
+	if len(deps.Remotes) != 1 || deps.Remotes[0] != ref {
+		t.Fatalf("unexpected dependencies %v", deps)
+	}
+
+	_, err = l.Load(strings.Split(ref, "@")[0])
+	if err == nil || !strings.Contains(err.Error(), "isn't pinned") ||
+		kusterr.ClassOf(err) != kusterr.ClassUsage {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	l.opts.Git.Network = false
+	_, err = l.Load(ref)
+	if err == nil || !strings.Contains(err.Error(), "--network=false") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestLoadOCIWithoutDocuments(t *testing.T) {
+	s, ref := makeRegistry(map[string]string{"README.md": "# configs"})
+	defer s.Close()
+	l := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		fs.ConfirmedDir("/app"), fs.MakeFakeFS(), nil, testOptions(nil))
+	_, err := l.Load(ref)
+	if err == nil || !strings.Contains(err.Error(), "has no YAML or JSON") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestNewLoaderAtArtifact(t *testing.T) {
+	s, ref := makeRegistry(map[string]string{
+		"base/kustomization.yaml": "resources: [pod.yaml]",
+		"base/pod.yaml":           "kind: Pod",
+		"prod/kustomization.yaml": "resources: [../base]",
+	})
+	defer s.Close()
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/etc/passwd", []byte("root"))
+	recorder := NewDepRecorder()
+	l := newLoaderAtConfirmedDir(
+		RestrictionNone, validators.MakeFakeValidator(),
+		fs.ConfirmedDir("/app"), fSys, nil, testOptions(nil))
+	l.tracer = recorder
+
+	ldr, err := l.New(ref + "//prod")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !strings.HasSuffix(ldr.Root(), "/prod") {
+		t.Fatalf("unexpected root %s", ldr.Root())
+	}
+	base, err := ldr.New("../base")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	b, err := base.Load("pod.yaml")
+	if err != nil || string(b) != "kind: Pod" {
+		t.Fatalf("unexpected %s, %v", b, err)
+	}
+	_, err = base.Load("/etc/passwd")
+	if err == nil || !strings.Contains(err.Error(), "is not in or below") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	_, err = base.New("../..")
+	if err == nil || !strings.Contains(err.Error(), "security; bases in "+
+		"kustomizations found in OCI artifacts must be within the artifact") ||
+		!kusterr.Is(err, ErrLoadRestricted) {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	_, err = base.New(ref + "//prod")
+	if err == nil || !strings.Contains(err.Error(), "cycle detected") ||
+		!kusterr.Is(err, ErrCycleDetected) {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	deps := recorder.Dependencies()
+	if len(deps.Files) != 0 || len(deps.Directories) != 0 ||
+		len(deps.Remotes) != 1 || deps.Remotes[0] != ref+"//prod" {
+		t.Fatalf("unexpected dependencies %v", deps)
+	}
+
+	dir := strings.TrimSuffix(ldr.Root(), "/prod")
+	if err := ldr.Cleanup(); err != nil || fSys.Exists(dir) {
+		t.Fatalf("expected %s removed, got %v", dir, err)
+	}
+
+	_, err = l.New(ref + "//../../etc")
+	if err == nil || !strings.Contains(err.Error(), "is outside the artifact") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	_, err = l.New(ref + "//base/pod.yaml")
+	if err == nil || !strings.Contains(err.Error(), "expecting directory") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
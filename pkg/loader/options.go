@@ -0,0 +1,91 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+)
+
+// Options say how a loader, and the loaders it makes,
+// get what's outside the kustomization, e.g. remote
+// bases, URLs and artifacts, and how they treat the
+// files they load.
+type Options struct {
+	// Git says how remote bases are cloned.
+	Git git.Options
+	// HTTP says how files are fetched from URLs.
+	HTTP HTTPOptions
+	// RemoteLimits bound what's loaded of remote bases.
+	RemoteLimits RemoteLimits
+	// Decryption says how encrypted files are decrypted.
+	Decryption Decryption
+	// GeneratorFileWarnSize is the size of a file source
+	// past which builds warn; zero never warns.
+	GeneratorFileWarnSize int64
+	// GeneratorCacheDir is the directory generated
+	// ConfigMaps are cached in across builds; empty
+	// caches them only within a build.
+	GeneratorCacheDir string
+	// Cloner clones remote bases; if nil, as Git says.
+	Cloner git.Cloner
+	// MaxClones is how many remote bases of a kustomization
+	// Prefetch clones at once; 1 clones them one by one,
+	// as New needs them.
+	MaxClones int
+}
+
+// DefaultOptions are those of a build given no flags.
+func DefaultOptions() Options {
+	return Options{
+		Git:                   git.DefaultOptions(),
+		HTTP:                  DefaultHTTPOptions,
+		RemoteLimits:          DefaultRemoteLimits,
+		GeneratorFileWarnSize: DefaultGeneratorFileWarnSize,
+		MaxClones:             DefaultMaxClones,
+	}
+}
+
+// AddFlags adds the flags setting the options of
+// commands that build, but for the GeneratorCacheDir.
+func (o *Options) AddFlags(set *pflag.FlagSet) {
+	o.AddFlagsRemoteLimits(set)
+	o.AddFlagsDecryption(set)
+	o.AddFlagsHTTP(set)
+	o.AddFlagGeneratorFileWarnSize(set)
+	o.Git.AddFlagsRepoCache(set)
+	o.Git.AddFlagNetwork(set)
+	o.Git.AddFlagRemoteRetries(set)
+	o.Git.AddFlagsCloneDepth(set)
+	o.Git.AddFlagGitClient(set)
+	o.Git.AddFlagsCredentials(set)
+	o.AddFlagMaxClones(set)
+}
+
+// LoadRewriteRules sets the Git RewriteRules to those
+// of kustomize's config directory, if any, which may
+// redirect remote bases, e.g. to mirrors.  Commands
+// load them before they make their loader.
+func (o *Options) LoadRewriteRules(fSys fs.FileSystem) error {
+	rules, err := git.LoadRewriteRules(fSys, git.RewriteRulesPath())
+	if err != nil {
+		return kusterr.WithClass(kusterr.ClassLoad, err)
+	}
+	o.Git.RewriteRules = rules
+	return nil
+}
+
+// complete returns the options with a Cloner, and
+// cloning at least one remote base at a time.
+func (o Options) complete() *Options {
+	if o.Cloner == nil {
+		o.Cloner = o.Git.Cloner()
+	}
+	if o.MaxClones < 1 {
+		o.MaxClones = 1
+	}
+	return &o
+}
@@ -0,0 +1,86 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"sync"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/oci"
+)
+
+// DefaultMaxClones is how many remote bases of a
+// kustomization are cloned at once by default.
+const DefaultMaxClones = 4
+
+const flagMaxClones = "max-clones"
+
+// AddFlagMaxClones adds the flag setting how many
+// remote bases are cloned at once.
+func (o *Options) AddFlagMaxClones(set *pflag.FlagSet) {
+	set.IntVar(
+		&o.MaxClones, flagMaxClones, DefaultMaxClones,
+		"How many remote bases of a kustomization are cloned at "+
+			"once; 1 clones them one by one.  The output doesn't change.")
+}
+
+// prefetched is the loader, or error, New returns
+// for the path of a remote base Prefetch cloned.
+type prefetched struct {
+	ldr *fileLoader
+	err error
+}
+
+// isRepoURL is true if New would clone path.
+func isRepoURL(path string) bool {
+	if IsFileURL(path) || oci.IsReference(path) || IsArchiveURL(path) {
+		return false
+	}
+	_, err := git.NewRepoSpecFromUrl(path)
+	return err == nil
+}
+
+// Prefetch clones the repos of the remote bases among
+// paths, up to the MaxClones of its Options at once,
+// for New to return loaders of when asked for them.
+// It returns the error of each path whose clone
+// failed, nil for the others.  Loaders not asked for
+// are cleaned up by Cleanup.
+func (fl *fileLoader) Prefetch(paths []string) []error {
+	errs := make([]error, len(paths))
+	var remotes []int
+	seen := make(map[string]bool)
+	for i, p := range paths {
+		if _, ok := fl.prefetched[p]; ok || seen[p] || !isRepoURL(p) {
+			continue
+		}
+		seen[p] = true
+		remotes = append(remotes, i)
+	}
+	if fl.opts.MaxClones < 2 || len(remotes) < 2 {
+		return errs
+	}
+	results := make([]prefetched, len(paths))
+	slots := make(chan struct{}, fl.opts.MaxClones)
+	var wg sync.WaitGroup
+	for _, i := range remotes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[i].ldr, results[i].err = fl.newLoader(paths[i])
+		}(i)
+	}
+	wg.Wait()
+	if fl.prefetched == nil {
+		fl.prefetched = make(map[string]*prefetched)
+	}
+	for _, i := range remotes {
+		fl.prefetched[paths[i]] = &results[i]
+		errs[i] = results[i].err
+	}
+	return errs
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+// blockingCloner clones a repo to /clones/{orgRepo}
+// once told to, by release being closed, reporting
+// each clone started, and failing for org/bad.
+type blockingCloner struct {
+	started chan string
+	release chan struct{}
+	mu      sync.Mutex
+	clones  int
+}
+
+func (c *blockingCloner) clone(repoSpec *git.RepoSpec) error {
+	c.started <- repoSpec.OrgRepo
+	<-c.release
+	c.mu.Lock()
+	c.clones++
+	c.mu.Unlock()
+	if repoSpec.OrgRepo == "org/bad" {
+		return errors.New("repository not found")
+	}
+	repoSpec.Dir = fs.ConfirmedDir("/clones/" + repoSpec.OrgRepo)
+	return nil
+}
+
+func TestPrefetch(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.MkdirAll("/app")
+	for _, r := range []string{"a", "b", "c"} {
+		fSys.MkdirAll("/clones/org/" + r + "/base")
+	}
+	root, err := demandDirectoryRoot(fSys, "/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := &blockingCloner{
+		started: make(chan string, 4),
+		release: make(chan struct{}),
+	}
+	ldr := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		root, fSys, nil, testOptions(c.clone))
+	ldr.opts.MaxClones = 2
+	paths := []string{
+		"github.com/org/a/base",
+		"local",
+		"github.com/org/b/base",
+		"github.com/org/bad",
+		"github.com/org/c/base",
+		"github.com/org/a/base",
+	}
+	done := make(chan []error)
+	go func() { done <- ldr.Prefetch(paths) }()
+	// Two clones start before either finishes.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-c.started:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("expected two clones at once")
+		}
+	}
+	select {
+	case r := <-c.started:
+		t.Fatalf("expected at most two clones at once, got %s too", r)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(c.release)
+	errs := <-done
+	for i, err := range errs {
+		if (err != nil) != (paths[i] == "github.com/org/bad") {
+			t.Fatalf("%s: unexpected error: %v", paths[i], err)
+		}
+	}
+	if c.clones != 4 {
+		t.Fatalf("expected 4 clones, got %d", c.clones)
+	}
+
+	// New returns the loaders cloned, rather than
+	// cloning again, then clones as usual.
+	for _, r := range []string{"a", "b", "c", "a"} {
+		l, err := ldr.New("github.com/org/" + r + "/base")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if l.Root() != "/clones/org/"+r+"/base" {
+			t.Fatalf("unexpected root %s", l.Root())
+		}
+	}
+	_, err = ldr.New("github.com/org/bad")
+	if err == nil || !strings.Contains(err.Error(), "repository not found") ||
+		!kusterr.Is(err, git.ErrRepoCloneFailed) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.clones != 5 {
+		t.Fatalf("expected 5 clones, got %d", c.clones)
+	}
+}
+
+func TestPrefetchOneByOne(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.MkdirAll("/app")
+	root, err := demandDirectoryRoot(fSys, "/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ldr := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		root, fSys, nil, testOptions(func(*git.RepoSpec) error {
+			t.Fatalf("unexpected clone")
+			return nil
+		}))
+	ldr.opts.MaxClones = 1
+	ldr.Prefetch([]string{"github.com/org/a", "github.com/org/b"})
+	if len(ldr.prefetched) != 0 {
+		t.Fatalf("unexpected prefetch %v", ldr.prefetched)
+	}
+}
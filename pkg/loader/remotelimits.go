@@ -0,0 +1,103 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+// RemoteLimits bound what may be loaded from the clone
+// of a remote base, including by the bases it has in
+// the same clone, so that a huge or malicious remote
+// base can't exhaust memory.  Zero means no limit.
+type RemoteLimits struct {
+	// MaxFileSize bounds the size of each file.
+	MaxFileSize int64
+	// MaxTotalBytes bounds the sum of the sizes of the files.
+	MaxTotalBytes int64
+	// MaxFiles bounds the number of files.
+	MaxFiles int
+}
+
+// DefaultRemoteLimits are generous for any real base.
+var DefaultRemoteLimits = RemoteLimits{
+	MaxFileSize:   10 << 20,
+	MaxTotalBytes: 100 << 20,
+	MaxFiles:      10000,
+}
+
+const (
+	flagRemoteMaxFileSize   = "remote-max-file-size"
+	flagRemoteMaxTotalBytes = "remote-max-total-bytes"
+	flagRemoteMaxFiles      = "remote-max-files"
+)
+
+// AddFlagsRemoteLimits adds flags setting the limits.
+func (o *Options) AddFlagsRemoteLimits(set *pflag.FlagSet) {
+	set.Int64Var(
+		&o.RemoteLimits.MaxFileSize, flagRemoteMaxFileSize,
+		DefaultRemoteLimits.MaxFileSize,
+		"Most bytes a file of a remote base may have; 0 for no limit.")
+	set.Int64Var(
+		&o.RemoteLimits.MaxTotalBytes, flagRemoteMaxTotalBytes,
+		DefaultRemoteLimits.MaxTotalBytes,
+		"Most bytes the files loaded from one clone of a remote base "+
+			"may have in all; 0 for no limit.")
+	set.IntVar(
+		&o.RemoteLimits.MaxFiles, flagRemoteMaxFiles,
+		DefaultRemoteLimits.MaxFiles,
+		"Most files that may be loaded from one clone of a remote base; "+
+			"0 for no limit.")
+}
+
+// remoteBudget counts what's been loaded from a clone.
+type remoteBudget struct {
+	limits RemoteLimits
+	files  int
+	bytes  int64
+}
+
+func newRemoteBudget(l RemoteLimits) *remoteBudget {
+	return &remoteBudget{limits: l}
+}
+
+// charge counts the file at the given path, which is about
+// to be read, returning an error if that breaks a limit.
+func (b *remoteBudget) charge(fSys fs.FileSystem, path string) error {
+	f, err := fSys.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+	l := b.limits
+	if l.MaxFileSize > 0 && size > l.MaxFileSize {
+		return fmt.Errorf(
+			"security; file '%s' of a remote base has %d bytes, "+
+				"more than --%s %d",
+			path, size, flagRemoteMaxFileSize, l.MaxFileSize)
+	}
+	if l.MaxTotalBytes > 0 && b.bytes+size > l.MaxTotalBytes {
+		return fmt.Errorf(
+			"security; loading file '%s' of a remote base makes "+
+				"%d bytes, more than --%s %d",
+			path, b.bytes+size, flagRemoteMaxTotalBytes, l.MaxTotalBytes)
+	}
+	if l.MaxFiles > 0 && b.files+1 > l.MaxFiles {
+		return fmt.Errorf(
+			"security; loading file '%s' of a remote base makes "+
+				"%d files, more than --%s %d",
+			path, b.files+1, flagRemoteMaxFiles, l.MaxFiles)
+	}
+	b.bytes += size
+	b.files++
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func makeRemoteLoader(t *testing.T, limits RemoteLimits) ifc.Loader {
+	coRoot := "/tmp"
+	fSys := fs.MakeFakeFS()
+	fSys.MkdirAll(coRoot + "/base/sub")
+	fSys.WriteFile(coRoot+"/base/small.yaml", []byte("abcd"))
+	fSys.WriteFile(coRoot+"/base/big.yaml", []byte("abcdefghij"))
+	fSys.WriteFile(coRoot+"/base/sub/small.yaml", []byte("abcd"))
+	repoSpec, err := git.NewRepoSpecFromUrl("github.com/someOrg/someRepo/base")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	opts := testOptions(git.DoNothingCloner(fs.ConfirmedDir(coRoot)))
+	opts.RemoteLimits = limits
+	l, err := newLoaderAtGitClone(
+		repoSpec, validators.MakeFakeValidator(), fSys, nil, opts)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	return l
+}
+
+func TestRemoteLimitsMaxFileSize(t *testing.T) {
+	l := makeRemoteLoader(t, RemoteLimits{MaxFileSize: 5})
+	if _, err := l.Load("small.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	_, err := l.Load("big.yaml")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "has 10 bytes, more than --remote-max-file-size 5") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestRemoteLimitsMaxTotalBytes(t *testing.T) {
+	l := makeRemoteLoader(t, RemoteLimits{MaxTotalBytes: 10})
+	if _, err := l.Load("small.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	// The budget is shared with loaders of subdirectories.
+	sub, err := l.New("sub")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err := sub.Load("small.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	_, err = l.Load("small.yaml")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "makes 12 bytes, more than --remote-max-total-bytes 10") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestRemoteLimitsMaxFiles(t *testing.T) {
+	l := makeRemoteLoader(t, RemoteLimits{MaxFiles: 2})
+	for i := 0; i < 2; i++ {
+		if _, err := l.Load("small.yaml"); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+	_, err := l.Load("small.yaml")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "makes 3 files, more than --remote-max-files 2") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestRemoteLimitsNotAppliedLocally(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/big.yaml", []byte("abcdefghij"))
+	o := DefaultOptions()
+	o.RemoteLimits = RemoteLimits{MaxFileSize: 5}
+	l, err := NewLoaderWithOptions(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		"/app", fSys, nil, o)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err := l.Load("big.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
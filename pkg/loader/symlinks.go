@@ -0,0 +1,106 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+)
+
+// SymlinkPolicy says which files reached through symbolic
+// links a local kustomization may load.
+type SymlinkPolicy string
+
+const (
+	// SymlinksWithinRoot follows links in the root only to
+	// files in or below the root, whatever the restrictor.
+	SymlinksWithinRoot SymlinkPolicy = "follow-within-root"
+	// SymlinksDeny loads no file reached through a link.
+	SymlinksDeny SymlinkPolicy = "deny"
+	// SymlinksAnywhere follows links in the root to files
+	// anywhere, as if the files were where the links are.
+	SymlinksAnywhere SymlinkPolicy = "follow-anywhere"
+)
+
+const flagSymlinksName = "symlinks"
+
+var (
+	flagSymlinksValue = string(SymlinksWithinRoot)
+	flagSymlinksHelp  = "Which files reached through symbolic links " +
+		"local kustomizations may load: '" + string(SymlinksWithinRoot) +
+		"' follows links in a root only to files in or below it, '" +
+		string(SymlinksDeny) + "' follows none, and '" +
+		string(SymlinksAnywhere) + "' follows links in a root to files " +
+		"anywhere.  Remote bases always follow links within their root."
+)
+
+// AddFlagSymlinks adds the flag setting the
+// SymlinkPolicy of ValidateFlagLoadRestrictor.
+func AddFlagSymlinks(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagSymlinksValue, flagSymlinksName,
+		string(SymlinksWithinRoot), flagSymlinksHelp)
+}
+
+func validateFlagSymlinks() (SymlinkPolicy, error) {
+	switch p := SymlinkPolicy(flagSymlinksValue); p {
+	case SymlinksWithinRoot,
+		SymlinksDeny,
+		SymlinksAnywhere:
+		return p, nil
+	default:
+		return "", messages.Errorf(messages.FlagIllegalValue,
+			flagSymlinksName, flagSymlinksValue,
+			[]string{
+				string(SymlinksWithinRoot),
+				string(SymlinksDeny),
+				string(SymlinksAnywhere),
+			})
+	}
+}
+
+// WithSymlinkPolicy returns a LoadRestrictorFunc that
+// applies the policy to files reached through symbolic
+// links, and lr to all files.  Under SymlinksAnywhere,
+// a file linked to from the root passes lr as if it
+// were where the link is.
+func WithSymlinkPolicy(
+	lr LoadRestrictorFunc, p SymlinkPolicy) LoadRestrictorFunc {
+	return func(
+		fSys fs.FileSystem, root fs.ConfirmedDir, path string) (string, error) {
+		lexical, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		d, f, err := fSys.CleanedAbs(lexical)
+		if err != nil {
+			return "", err
+		}
+		linked := d.Join(f)
+		if f == "" || linked == lexical {
+			return lr(fSys, root, path)
+		}
+		inRoot := fs.ConfirmedDir(filepath.Dir(lexical)).HasPrefix(root)
+		switch p {
+		case SymlinksDeny:
+			return "", kusterr.WithKind(ErrLoadRestricted, messages.Errorf(
+				messages.SymlinkDenied, path, flagSymlinksName, p))
+		case SymlinksAnywhere:
+			if inRoot {
+				return linked, nil
+			}
+		default:
+			if inRoot && !d.HasPrefix(root) {
+				return "", kusterr.WithKind(ErrLoadRestricted, messages.Errorf(
+					messages.SymlinkNotInRoot,
+					path, linked, root, flagSymlinksName, SymlinksAnywhere))
+			}
+		}
+		return lr(fSys, root, path)
+	}
+}
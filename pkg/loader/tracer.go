@@ -0,0 +1,297 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"crypto/sha256"
+	"sort"
+
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/oci"
+)
+
+// Tracer observes a loader, and all the loaders it
+// spawns, as they do their work.
+type Tracer interface {
+	// Loaded is called with the absolute path, or
+	// URL, of every file successfully read.  The repoSpec
+	// is non-nil if the file came from a cloned repo.
+	Loaded(path string, repoSpec *git.RepoSpec)
+	// Rooted is called with the absolute path of the
+	// root of every loader created.  The repoSpec is
+	// non-nil if the root is in a cloned repo.
+	Rooted(root string, repoSpec *git.RepoSpec)
+}
+
+// MultiTracer returns a Tracer notifying each of
+// the given tracers in turn.
+func MultiTracer(ts ...Tracer) Tracer {
+	return multiTracer(ts)
+}
+
+type multiTracer []Tracer
+
+func (ts multiTracer) Loaded(path string, repoSpec *git.RepoSpec) {
+	for _, t := range ts {
+		t.Loaded(path, repoSpec)
+	}
+}
+
+func (ts multiTracer) Rooted(root string, repoSpec *git.RepoSpec) {
+	for _, t := range ts {
+		t.Rooted(root, repoSpec)
+	}
+}
+
+func (ts multiTracer) Digested(path string, sum [sha256.Size]byte) {
+	for _, t := range ts {
+		if d, ok := t.(DigestTracer); ok {
+			d.Digested(path, sum)
+		}
+	}
+}
+
+func (ts multiTracer) Depends(from, to Node) {
+	for _, t := range ts {
+		if g, ok := t.(GraphTracer); ok {
+			g.Depends(from, to)
+		}
+	}
+}
+
+// GraphTracer is a Tracer also told which loader read
+// each file, and made each loader, so that it can record
+// which kustomizations depend on which files and bases.
+type GraphTracer interface {
+	Tracer
+	// Depends is called, with the root of a loader, for
+	// every file it reads, and every loader it makes with
+	// New, after Loaded or Rooted.
+	Depends(from, to Node)
+}
+
+// DigestTracer is a Tracer also told the sha256 of
+// each local file read, as it was read, so that records
+// of a build's inputs hash what the build used, rather
+// than files read again once it's done.
+type DigestTracer interface {
+	Tracer
+	// Digested is called with the absolute path of a
+	// local file, outside clones and artifacts, and the
+	// sha256 of its content, before any decryption, once
+	// it's been read in full.
+	Digested(path string, sum [sha256.Size]byte)
+}
+
+// NodeKind is the kind of thing a Node is.
+type NodeKind string
+
+const (
+	// NodeFile is a file.
+	NodeFile NodeKind = "file"
+	// NodeDirectory is the root of a kustomization.
+	NodeDirectory NodeKind = "directory"
+	// NodeURL is a file fetched over http(s), or an
+	// object read from a bucket.
+	NodeURL NodeKind = "url"
+	// NodeArtifact is an OCI artifact; the files in
+	// it aren't Nodes themselves.
+	NodeArtifact NodeKind = "artifact"
+	// NodeObjects is the objects of a bucket under
+	// a prefix, like an artifact.
+	NodeObjects NodeKind = "objects"
+	// NodeArchive is an archive downloaded from an
+	// http(s) URL, like an artifact.
+	NodeArchive NodeKind = "archive"
+)
+
+// Node is a file, kustomization root, or remote
+// thing a build read.
+type Node struct {
+	Kind NodeKind `json:"kind" yaml:"kind"`
+	// Path is the absolute path of a local file or
+	// directory, the path in the repo of one in a
+	// clone, or the URL or reference of a remote thing.
+	Path string `json:"path" yaml:"path"`
+	// Repo is, for a Node in a clone of a remote base,
+	// the URL cloned, e.g. https://github.com/org/repo.git.
+	Repo string `json:"repo,omitempty" yaml:"repo,omitempty"`
+	// Ref is the branch, tag or commit cloned.
+	Ref string `json:"ref,omitempty" yaml:"ref,omitempty"`
+	// Commit is the SHA of the commit cloned.
+	Commit string `json:"commit,omitempty" yaml:"commit,omitempty"`
+}
+
+// ID identifies the Node in a DepGraph; that of a Node
+// in a clone is like the URL of a remote base, e.g.
+// https://github.com/org/repo.git//base?ref=v1.
+func (n Node) ID() string {
+	if n.Repo == "" {
+		return n.Path
+	}
+	id := n.Repo
+	if n.Path != "" {
+		id += "//" + n.Path
+	}
+	if n.Ref != "" {
+		id += "?ref=" + n.Ref
+	}
+	return id
+}
+
+// DepGraph is the graph of which kustomizations
+// of a build read which files and bases.
+type DepGraph struct {
+	// Root is the ID of the kustomization built.
+	Root  string      `json:"root" yaml:"root"`
+	Nodes []GraphNode `json:"nodes" yaml:"nodes"`
+	Edges []Edge      `json:"edges" yaml:"edges"`
+}
+
+// GraphNode is a Node with its ID.
+type GraphNode struct {
+	ID   string `json:"id" yaml:"id"`
+	Node `json:",inline" yaml:",inline"`
+}
+
+// Edge says the kustomization From read, or
+// included, To; both are IDs of nodes.
+type Edge struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// GraphRecorder is a GraphTracer that records a DepGraph.
+type GraphRecorder struct {
+	root  string
+	nodes map[string]Node
+	edges map[Edge]bool
+}
+
+// NewGraphRecorder returns an empty GraphRecorder.
+func NewGraphRecorder() *GraphRecorder {
+	return &GraphRecorder{
+		nodes: make(map[string]Node),
+		edges: make(map[Edge]bool),
+	}
+}
+
+// Loaded implements Tracer.
+func (r *GraphRecorder) Loaded(string, *git.RepoSpec) {}
+
+// Rooted implements Tracer.
+func (r *GraphRecorder) Rooted(string, *git.RepoSpec) {}
+
+// Depends implements GraphTracer.
+func (r *GraphRecorder) Depends(from, to Node) {
+	// The first loader is the first to read a file,
+	// its kustomization file.
+	if r.root == "" {
+		r.root = from.ID()
+	}
+	r.nodes[from.ID()] = from
+	r.nodes[to.ID()] = to
+	r.edges[Edge{From: from.ID(), To: to.ID()}] = true
+}
+
+// Graph returns what's been recorded, sorted.
+func (r *GraphRecorder) Graph() DepGraph {
+	g := DepGraph{
+		Root:  r.root,
+		Nodes: []GraphNode{},
+		Edges: []Edge{},
+	}
+	for _, id := range sortedKeys(nodeIDs(r.nodes)) {
+		g.Nodes = append(g.Nodes, GraphNode{ID: id, Node: r.nodes[id]})
+	}
+	for e := range r.edges {
+		g.Edges = append(g.Edges, e)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g
+}
+
+func nodeIDs(m map[string]Node) map[string]bool {
+	result := make(map[string]bool, len(m))
+	for id := range m {
+		result[id] = true
+	}
+	return result
+}
+
+// Dependencies lists everything a build read.
+type Dependencies struct {
+	// Files are absolute paths of local files read.
+	Files []string `json:"files" yaml:"files"`
+	// Directories are absolute paths of local
+	// kustomization roots visited.
+	Directories []string `json:"directories" yaml:"directories"`
+	// Remotes are the URLs of remote bases, as
+	// written in the kustomization file, of files
+	// fetched over http(s), and of OCI artifacts pulled.  Files read from the clones
+	// of remote bases aren't listed individually.
+	Remotes []string `json:"remotes" yaml:"remotes"`
+}
+
+// DepRecorder is a Tracer that records dependencies.
+type DepRecorder struct {
+	files   map[string]bool
+	dirs    map[string]bool
+	remotes map[string]bool
+}
+
+// NewDepRecorder returns an empty DepRecorder.
+func NewDepRecorder() *DepRecorder {
+	return &DepRecorder{
+		files:   make(map[string]bool),
+		dirs:    make(map[string]bool),
+		remotes: make(map[string]bool),
+	}
+}
+
+// Loaded implements Tracer.
+func (r *DepRecorder) Loaded(path string, repoSpec *git.RepoSpec) {
+	if repoSpec != nil {
+		r.remotes[repoSpec.Raw()] = true
+		return
+	}
+	if IsFileURL(path) || oci.IsReference(path) || IsObjectURL(path) ||
+		IsArchiveURL(path) {
+		r.remotes[path] = true
+		return
+	}
+	r.files[path] = true
+}
+
+// Rooted implements Tracer.
+func (r *DepRecorder) Rooted(root string, repoSpec *git.RepoSpec) {
+	if repoSpec != nil {
+		r.remotes[repoSpec.Raw()] = true
+		return
+	}
+	r.dirs[root] = true
+}
+
+// Dependencies returns what's been recorded, sorted.
+func (r *DepRecorder) Dependencies() Dependencies {
+	return Dependencies{
+		Files:       sortedKeys(r.files),
+		Directories: sortedKeys(r.dirs),
+		Remotes:     sortedKeys(r.remotes),
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	result := []string{}
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
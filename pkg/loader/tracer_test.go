@@ -0,0 +1,204 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"crypto/sha256"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestDepRecorder(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/top/kustomization.yaml", []byte("resources: []"))
+	fSys.WriteFile("/top/sub/pod.yaml", []byte("kind: Pod"))
+	fSys.WriteFile("/clone/foo/base/pod.yaml", []byte("kind: Pod"))
+
+	rec := NewDepRecorder()
+	l1 := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		fs.ConfirmedDir("/top"), fSys, nil,
+		testOptions(git.DoNothingCloner(fs.ConfirmedDir("/clone"))))
+	l1.tracer = rec
+	l1.traceRoot()
+	if _, err := l1.Load("kustomization.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	l2, err := l1.New("sub")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err = l2.Load("pod.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	l3, err := l1.New("github.com/someOrg/someRepo/foo/base")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err = l3.Load("pod.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	// Failed loads aren't dependencies.
+	if _, err = l1.Load("missing.yaml"); err == nil {
+		t.Fatalf("expected err")
+	}
+
+	expected := Dependencies{
+		Files:       []string{"/top/kustomization.yaml", "/top/sub/pod.yaml"},
+		Directories: []string{"/top", "/top/sub"},
+		Remotes:     []string{"github.com/someOrg/someRepo/foo/base"},
+	}
+	if actual := rec.Dependencies(); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+// digestRecorder records the digests told it.
+type digestRecorder struct {
+	*DepRecorder
+	digests map[string][sha256.Size]byte
+}
+
+func (r digestRecorder) Digested(path string, sum [sha256.Size]byte) {
+	r.digests[path] = sum
+}
+
+func TestDigestTracer(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/top/kustomization.yaml", []byte("resources: []"))
+	fSys.WriteFile("/top/app.properties", []byte("a=1\nb=2\n"))
+	fSys.WriteFile("/clone/foo/base/pod.yaml", []byte("kind: Pod"))
+
+	rec := digestRecorder{NewDepRecorder(), make(map[string][sha256.Size]byte)}
+	l1 := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		fs.ConfirmedDir("/top"), fSys, nil,
+		testOptions(git.DoNothingCloner(fs.ConfirmedDir("/clone"))))
+	l1.tracer = MultiTracer(rec)
+	if _, err := l1.Load("kustomization.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	// A file streamed, but only partly read, is
+	// hashed whole when closed.
+	r, err := l1.Open("app.properties")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	r.Read(make([]byte, 2))
+	r.Close()
+	l2, err := l1.New("github.com/someOrg/someRepo/foo/base")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err = l2.Load("pod.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	expected := map[string][sha256.Size]byte{
+		"/top/kustomization.yaml": sha256.Sum256([]byte("resources: []")),
+		"/top/app.properties":     sha256.Sum256([]byte("a=1\nb=2\n")),
+	}
+	if !reflect.DeepEqual(rec.digests, expected) {
+		t.Fatalf("expected %x, got %x", expected, rec.digests)
+	}
+}
+
+func TestMultiTracer(t *testing.T) {
+	a, b := NewDepRecorder(), NewDepRecorder()
+	tr := MultiTracer(a, b)
+	tr.Rooted("/top", nil)
+	tr.Loaded("/top/kustomization.yaml", nil)
+	expected := Dependencies{
+		Files:       []string{"/top/kustomization.yaml"},
+		Directories: []string{"/top"},
+		Remotes:     []string{},
+	}
+	for _, r := range []*DepRecorder{a, b} {
+		if !reflect.DeepEqual(r.Dependencies(), expected) {
+			t.Fatalf("expected %v, got %v", expected, r.Dependencies())
+		}
+	}
+}
+
+func TestGraphRecorder(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/top/kustomization.yaml", []byte("resources: []"))
+	fSys.WriteFile("/top/sub/pod.yaml", []byte("kind: Pod"))
+	fSys.WriteFile("/clone/foo/base/pod.yaml", []byte("kind: Pod"))
+
+	rec := NewGraphRecorder()
+	l1 := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		fs.ConfirmedDir("/top"), fSys, nil,
+		testOptions(git.DoNothingCloner(fs.ConfirmedDir("/clone"))))
+	l1.tracer = MultiTracer(NewDepRecorder(), rec)
+	l1.traceRoot()
+	if _, err := l1.Load("kustomization.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	l2, err := l1.New("sub")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err = l2.Load("pod.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	l3, err := l1.New("github.com/someOrg/someRepo/foo/base")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err = l3.Load("pod.yaml"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err = l1.Load("missing.yaml"); err == nil {
+		t.Fatalf("expected err")
+	}
+
+	repo := "https://github.com/someOrg/someRepo.git"
+	expected := DepGraph{
+		Root: "/top",
+		Nodes: []GraphNode{
+			{ID: "/top", Node: Node{Kind: NodeDirectory, Path: "/top"}},
+			{ID: "/top/kustomization.yaml",
+				Node: Node{Kind: NodeFile, Path: "/top/kustomization.yaml"}},
+			{ID: "/top/sub", Node: Node{Kind: NodeDirectory, Path: "/top/sub"}},
+			{ID: "/top/sub/pod.yaml",
+				Node: Node{Kind: NodeFile, Path: "/top/sub/pod.yaml"}},
+			{ID: repo + "//foo/base",
+				Node: Node{Kind: NodeDirectory, Path: "foo/base", Repo: repo}},
+			{ID: repo + "//foo/base/pod.yaml",
+				Node: Node{Kind: NodeFile, Path: "foo/base/pod.yaml", Repo: repo}},
+		},
+		Edges: []Edge{
+			{From: "/top", To: "/top/kustomization.yaml"},
+			{From: "/top", To: "/top/sub"},
+			{From: "/top", To: repo + "//foo/base"},
+			{From: "/top/sub", To: "/top/sub/pod.yaml"},
+			{From: repo + "//foo/base", To: repo + "//foo/base/pod.yaml"},
+		},
+	}
+	if actual := rec.Graph(); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestNodeID(t *testing.T) {
+	for _, tc := range []struct {
+		n        Node
+		expected string
+	}{
+		{Node{Kind: NodeFile, Path: "/app/pod.yaml"}, "/app/pod.yaml"},
+		{Node{Kind: NodeDirectory, Repo: "https://h/r.git", Ref: "v1"},
+			"https://h/r.git?ref=v1"},
+		{Node{Kind: NodeDirectory, Path: "base", Repo: "https://h/r.git",
+			Ref: "v1", Commit: "abc"}, "https://h/r.git//base?ref=v1"},
+	} {
+		if actual := tc.n.ID(); actual != tc.expected {
+			t.Fatalf("expected %s, got %s", tc.expected, actual)
+		}
+	}
+}
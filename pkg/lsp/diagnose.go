@@ -0,0 +1,273 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/oci"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const diagnosticSource = "kustomize"
+
+var (
+	yamlLineRe     = regexp.MustCompile(`line (\d+)`)
+	unknownFieldRe = regexp.MustCompile(`unknown field "([^"]+)"`)
+)
+
+// Diagnose lints the content of the kustomization file
+// at the given absolute path, returning problems found.
+// The file itself isn't read; referenced files are.
+func Diagnose(
+	fSys fs.FileSystem, path string, content []byte) []Diagnostic {
+	lines := strings.Split(string(content), "\n")
+	result := []Diagnostic{}
+	j, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return append(result, diagnostic(
+			lines, yamlErrorLine(err.Error()), SeverityError, err.Error()))
+	}
+	if bytes.Equal(bytes.TrimSpace(j), []byte("null")) {
+		return result
+	}
+	if msg := checkShape(j); msg != "" {
+		return append(result, diagnostic(
+			lines, fieldLine(lines, "patches"), SeverityError, msg))
+	}
+	// The content is known to be valid yaml at this point,
+	// which FixKustomizationPreUnmarshalling requires.
+	var k types.Kustomization
+	j, err = yaml.YAMLToJSON(types.FixKustomizationPreUnmarshalling(content))
+	if err == nil {
+		dec := json.NewDecoder(bytes.NewReader(j))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&k)
+	}
+	if err != nil {
+		line := 0
+		if m := unknownFieldRe.FindStringSubmatch(err.Error()); m != nil {
+			line = fieldLine(lines, m[1])
+		}
+		return append(result, diagnostic(
+			lines, line, SeverityError, err.Error()))
+	}
+	for _, msg := range k.EnforceFields() {
+		field := strings.Fields(msg)[0]
+		result = append(result, diagnostic(
+			lines, fieldLine(lines, field), SeverityError, msg))
+	}
+	k.FixKustomizationPostUnmarshalling()
+	dir := filepath.Dir(path)
+	for _, p := range referencedPaths(&k) {
+		if msg := checkPath(fSys, dir, p.path, p.dirOk); msg != "" {
+			result = append(result, diagnostic(
+				lines, valueLine(lines, p.path), p.severity(msg), msg))
+		}
+	}
+	return result
+}
+
+// checkShape returns a message if the json can't be
+// handled by FixKustomizationPreUnmarshalling, which
+// exits the process on input it doesn't expect.
+func checkShape(j []byte) string {
+	var object map[string]interface{}
+	if err := json.Unmarshal(j, &object); err != nil {
+		return "kustomization must be a map of fields"
+	}
+	if p, ok := object["patches"]; ok {
+		if _, ok := p.([]interface{}); !ok {
+			return "patches must be a list"
+		}
+	}
+	return ""
+}
+
+type reference struct {
+	path string
+	// dirOk is true if the path may name a
+	// directory holding a kustomization file.
+	dirOk bool
+}
+
+func (r reference) severity(msg string) Severity {
+	if strings.HasPrefix(msg, "missing") {
+		return SeverityError
+	}
+	return SeverityWarning
+}
+
+// referencedPaths lists the local paths named in k.
+func referencedPaths(k *types.Kustomization) []reference {
+	var result []reference
+	add := func(dirOk bool, paths ...string) {
+		for _, p := range paths {
+			result = append(result, reference{path: p, dirOk: dirOk})
+		}
+	}
+	add(true, k.Resources...)
+	add(false, k.Crds...)
+	add(false, k.Configurations...)
+	add(false, k.ValuesFrom...)
+	add(true, k.Generators...)
+	add(true, k.Transformers...)
+	for _, p := range k.PatchesStrategicMerge {
+		// Inline patches span several lines.
+		if !strings.Contains(string(p), "\n") {
+			add(false, string(p))
+		}
+	}
+	for _, p := range k.PatchesJson6902 {
+		if p.Path != "" {
+			add(false, p.Path)
+		}
+	}
+	for _, p := range k.Patches {
+		if p.Path != "" {
+			add(false, p.Path)
+		}
+	}
+	return result
+}
+
+// checkPath returns a message if p, relative to dir,
+// doesn't name something kustomize could load.
+func checkPath(fSys fs.FileSystem, dir, p string, dirOk bool) string {
+	if isRemote(p) {
+		return ""
+	}
+	abs := resolve(dir, p)
+	if !fSys.IsDir(abs) && !fSys.Exists(abs) {
+		return fmt.Sprintf("missing file or directory '%s'", p)
+	}
+	if !fSys.IsDir(abs) {
+		return ""
+	}
+	if !dirOk {
+		return fmt.Sprintf("'%s' is a directory, expected a file", p)
+	}
+	if kustomizationFileIn(fSys, abs) == "" {
+		return fmt.Sprintf(
+			"directory '%s' has no kustomization file", p)
+	}
+	return ""
+}
+
+func isRemote(p string) bool {
+	_, err := git.NewRepoSpecFromUrl(p)
+	return err == nil || oci.IsReference(p) || loader.IsObjectURL(p) ||
+		loader.IsArchiveURL(p)
+}
+
+func resolve(dir, p string) string {
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+	return filepath.Join(dir, p)
+}
+
+// kustomizationFileIn returns the path of the
+// kustomization file in dir, or the empty string.
+func kustomizationFileIn(fSys fs.FileSystem, dir string) string {
+	for _, n := range pgmconfig.KustomizationFileNames {
+		p := filepath.Join(dir, n)
+		if fSys.Exists(p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// isKustomizationFile is true if the path's base
+// name is one kustomize recognizes.
+func isKustomizationFile(path string) bool {
+	base := filepath.Base(path)
+	for _, n := range pgmconfig.KustomizationFileNames {
+		if base == n {
+			return true
+		}
+	}
+	return false
+}
+
+// yamlErrorLine returns the zero-based line number
+// mentioned in a yaml error, or zero.
+func yamlErrorLine(msg string) int {
+	m := yamlLineRe.FindStringSubmatch(msg)
+	if m == nil {
+		return 0
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n < 1 {
+		return 0
+	}
+	return n - 1
+}
+
+// fieldLine returns the line declaring the given
+// top level field, or zero.
+func fieldLine(lines []string, field string) int {
+	for i, l := range lines {
+		if strings.HasPrefix(l, field+":") {
+			return i
+		}
+	}
+	return 0
+}
+
+// valueLine returns the first line holding the given
+// value as a list item or path, or zero.
+func valueLine(lines []string, value string) int {
+	for i, l := range lines {
+		if lineValue(l) == value {
+			return i
+		}
+	}
+	return 0
+}
+
+// lineValue extracts the scalar from a line like
+// "- foo.yaml" or "  path: foo.yaml".
+func lineValue(l string) string {
+	v := strings.TrimSpace(l)
+	if strings.HasPrefix(v, "- ") {
+		v = strings.TrimSpace(v[2:])
+	}
+	if strings.HasPrefix(v, "path:") {
+		v = strings.TrimSpace(v[len("path:"):])
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = strings.TrimSpace(v[:i])
+	}
+	return strings.Trim(v, `"'`)
+}
+
+func diagnostic(
+	lines []string, line int, s Severity, msg string) Diagnostic {
+	end := 0
+	if line < len(lines) {
+		end = len(lines[line])
+	}
+	return Diagnostic{
+		Range: Range{
+			Start: Position{Line: line},
+			End:   Position{Line: line, Character: end},
+		},
+		Severity: s,
+		Source:   diagnosticSource,
+		Message:  msg,
+	}
+}
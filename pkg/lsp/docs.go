@@ -0,0 +1,80 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package lsp
+
+// fieldDocs holds hover text for top level
+// kustomization fields, following the comments
+// on types.Kustomization.
+var fieldDocs = map[string]string{
+	"apiVersion": "Must be `kustomize.config.k8s.io/v1beta1`, if set.",
+	"kind":       "Must be `Kustomization`, if set.",
+	"namePrefix": "Prepended to the names of all resources.",
+	"nameSuffix": "Appended to the names of all resources.",
+	"generateNameSuffix": "Names resources that have a " +
+		"`metadata.generateName` and no name by appending it, " +
+		"with `{hash}` replaced by a hash of each, to the generateName.",
+	"namespace": "Added to all resources, replacing any " +
+		"namespace they already have.",
+	"namespaceFrom": "Derives the namespace, and optionally a " +
+		"name suffix, from a value, e.g. `--set env=...`, or the " +
+		"git branch, e.g. for preview environments.",
+	"commonLabels": "Labels added to all resources and to " +
+		"selectors.",
+	"commonAnnotations": "Annotations added to all resources.",
+	"patchesStrategicMerge": "Relative paths to, or inline " +
+		"content of, strategic merge patches.",
+	"patchesJson6902": "JSON patches (RFC 6902), each with a " +
+		"target resource and a path to the patch file.",
+	"patchesJsonPath": "Values to set at the fields a JSONPath " +
+		"finds, e.g. in list elements selected by name.",
+	"patches": "Patches, given as a path or inline, applied " +
+		"to resources matching an optional target selector.",
+	"ignoreFields": "Fields removed from the output after all " +
+		"patches and transformers, e.g. fields the cluster sets.",
+	"images":   "Image name, tag and digest replacements.",
+	"replicas": "Replica counts to set on resources, by name.",
+	"vars": "Values to capture from resources and substitute " +
+		"into `$(VAR)` references in other resources.",
+	"valuesFrom": "Relative paths or URLs of files of shared " +
+		"values, e.g. domain names, usable as `$(NAME)` vars and " +
+		"`${NAME}` template params, optionally checked by a schema.",
+	"resources": "Relative paths to resource files, or to " +
+		"directories, remote URLs or oci:// artifacts holding " +
+		"kustomizations.",
+	"crds": "Relative paths to CustomResourceDefinition " +
+		"files, allowing transformers to handle custom resources.",
+	"bases": "Deprecated; list bases in `resources` instead.",
+	"configMapGenerator": "ConfigMaps to generate from literals, " +
+		"files or env files.",
+	"secretGenerator": "Secrets to generate from literals, " +
+		"files or env files.",
+	"templates": "Template files whose `${param}` placeholders " +
+		"are replaced with the given params to make resources.",
+	"generatorOptions": "Labels, annotations and name hash " +
+		"options for all generated resources.",
+	"configurations": "Relative paths to transformer " +
+		"configuration files, or digest-pinned oci:// " +
+		"references to artifacts of them.",
+	"generators": "Relative paths to generator plugin " +
+		"configuration files.",
+	"transformers": "Relative paths to transformer plugin " +
+		"configuration files.",
+	"exporters": "Relative paths to exporter plugin " +
+		"configuration files; exporters replace the YAML output.",
+	"expectations": "Relative paths to expectation plugin " +
+		"configuration files; expectations check the output.",
+	"postRenderers": "Programs, each a `command` with `args`, " +
+		"the output is piped through, if the build allows it.",
+	"clusters": "Clusters to build for, each with its own " +
+		"`name` and `namespace`, `commonLabels` and `images` overrides.",
+	"inventory": "Adds an inventory object to the output.",
+	"openapi": "The Kubernetes `version`, or `path` to an OpenAPI " +
+		"document, whose schema guides strategic merge patches.",
+	"buildMetadata": "Metadata to add to the output: " +
+		"`originAnnotations` or `transformerAnnotations`.",
+	"tenancy": "The `namespaces` the output may use, and " +
+		"the `clusterScopedKinds` it may have.",
+	"dependsOn": "Each `resource`, as Kind/name, with the " +
+		"`dependencies` apply tools must apply first.",
+}
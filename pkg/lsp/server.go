@@ -0,0 +1,262 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lsp implements a language server for
+// kustomization files, offering diagnostics, go to
+// definition for referenced files and hover docs.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/version"
+)
+
+// Server speaks LSP over a pair of streams.
+type Server struct {
+	fSys fs.FileSystem
+	in   *bufio.Reader
+	out  io.Writer
+	// docs maps the URI of each open document to its text.
+	docs     map[string]string
+	shutdown bool
+}
+
+// NewServer returns a Server reading requests from in
+// and writing responses to out.
+func NewServer(fSys fs.FileSystem, in io.Reader, out io.Writer) *Server {
+	return &Server{
+		fSys: fSys,
+		in:   bufio.NewReader(in),
+		out:  out,
+		docs: make(map[string]string),
+	}
+}
+
+// Serve handles messages until the client sends
+// exit or closes the input stream.
+func (s *Server) Serve() error {
+	for {
+		body, err := s.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var m message
+		if err := json.Unmarshal(body, &m); err != nil {
+			if err := s.reply(nil, nil, &responseError{
+				Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if m.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit before shutdown")
+			}
+			return nil
+		}
+		if err := s.handle(&m); err != nil {
+			return err
+		}
+	}
+}
+
+// read returns the body of the next message.
+func (s *Server) read() ([]byte, error) {
+	header, err := textproto.NewReader(s.in).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, errors.Wrap(err, "bad Content-Length")
+	}
+	body := make([]byte, n)
+	_, err = io.ReadFull(s.in, body)
+	return body, err
+}
+
+func (s *Server) write(m *message) error {
+	m.JSONRPC = "2.0"
+	body, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
+
+func (s *Server) reply(
+	id *json.RawMessage, result interface{}, e *responseError) error {
+	if result == nil && e == nil {
+		// A result must be present on success, even if null.
+		result = json.RawMessage("null")
+	}
+	return s.write(&message{ID: id, Result: result, Error: e})
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	p, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return s.write(&message{Method: method, Params: p})
+}
+
+func (s *Server) handle(m *message) error {
+	switch m.Method {
+	case "initialize":
+		return s.reply(m.ID, initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:   1,
+				HoverProvider:      true,
+				DefinitionProvider: true,
+			},
+			ServerInfo: serverInfo{
+				Name:    "kustomize",
+				Version: version.Get().KustomizeVersion,
+			},
+		}, nil)
+	case "shutdown":
+		s.shutdown = true
+		return s.reply(m.ID, nil, nil)
+	case "textDocument/didOpen":
+		var p didOpenParams
+		if err := json.Unmarshal(m.Params, &p); err != nil {
+			return nil
+		}
+		s.docs[p.TextDocument.URI] = p.TextDocument.Text
+		return s.publish(p.TextDocument.URI)
+	case "textDocument/didChange":
+		var p didChangeParams
+		if err := json.Unmarshal(m.Params, &p); err != nil ||
+			len(p.ContentChanges) == 0 {
+			return nil
+		}
+		s.docs[p.TextDocument.URI] =
+			p.ContentChanges[len(p.ContentChanges)-1].Text
+		return s.publish(p.TextDocument.URI)
+	case "textDocument/didClose":
+		var p didCloseParams
+		if err := json.Unmarshal(m.Params, &p); err != nil {
+			return nil
+		}
+		delete(s.docs, p.TextDocument.URI)
+		return s.notify("textDocument/publishDiagnostics",
+			publishDiagnosticsParams{
+				URI: p.TextDocument.URI, Diagnostics: []Diagnostic{}})
+	case "textDocument/hover":
+		return s.answer(m, s.hover)
+	case "textDocument/definition":
+		return s.answer(m, s.definition)
+	}
+	if m.ID == nil {
+		// Unhandled notifications, like "initialized",
+		// are ignored.
+		return nil
+	}
+	return s.reply(m.ID, nil, &responseError{
+		Code: codeMethodNotFound, Message: "unsupported method " + m.Method})
+}
+
+// answer replies to a request about a position in a document.
+func (s *Server) answer(
+	m *message,
+	f func(path string, lines []string, pos Position) interface{}) error {
+	var p textDocumentPositionParams
+	if err := json.Unmarshal(m.Params, &p); err != nil {
+		return s.reply(m.ID, nil, &responseError{
+			Code: codeInvalidParams, Message: err.Error()})
+	}
+	text, ok := s.docs[p.TextDocument.URI]
+	path := uriToPath(p.TextDocument.URI)
+	if !ok || path == "" || !isKustomizationFile(path) {
+		return s.reply(m.ID, nil, nil)
+	}
+	lines := strings.Split(text, "\n")
+	if p.Position.Line < 0 || p.Position.Line >= len(lines) {
+		return s.reply(m.ID, nil, nil)
+	}
+	return s.reply(m.ID, f(path, lines, p.Position), nil)
+}
+
+func (s *Server) publish(uri string) error {
+	diags := []Diagnostic{}
+	if path := uriToPath(uri); path != "" && isKustomizationFile(path) {
+		diags = Diagnose(s.fSys, path, []byte(s.docs[uri]))
+	}
+	return s.notify("textDocument/publishDiagnostics",
+		publishDiagnosticsParams{URI: uri, Diagnostics: diags})
+}
+
+// hover documents the top level field on the given line.
+func (s *Server) hover(
+	_ string, lines []string, pos Position) interface{} {
+	l := lines[pos.Line]
+	i := strings.Index(l, ":")
+	if i < 1 || pos.Character > i {
+		return nil
+	}
+	field := l[:i]
+	doc, ok := fieldDocs[field]
+	if !ok {
+		return nil
+	}
+	return hover{
+		Contents: markupContent{
+			Kind: "markdown", Value: "**" + field + "**\n\n" + doc},
+		Range: &Range{
+			Start: Position{Line: pos.Line},
+			End:   Position{Line: pos.Line, Character: i},
+		},
+	}
+}
+
+// definition locates the file or kustomization
+// named on the given line.
+func (s *Server) definition(
+	path string, lines []string, pos Position) interface{} {
+	v := lineValue(lines[pos.Line])
+	if v == "" || isRemote(v) {
+		return nil
+	}
+	target := resolve(filepath.Dir(path), v)
+	if !s.fSys.IsDir(target) && !s.fSys.Exists(target) {
+		return nil
+	}
+	if s.fSys.IsDir(target) {
+		target = kustomizationFileIn(s.fSys, target)
+		if target == "" {
+			return nil
+		}
+	}
+	return []Location{{URI: pathToURI(target)}}
+}
+
+// uriToPath returns the path of a file URI,
+// or the empty string for other schemes.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func pathToURI(path string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String()
+}
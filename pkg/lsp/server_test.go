@@ -0,0 +1,186 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+func TestDiagnose(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/deployment.yaml", []byte("kind: Deployment"))
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(""))
+	fSys.Mkdir("/app/empty")
+
+	testCases := map[string]struct {
+		content  string
+		expected []Diagnostic
+	}{
+		"clean": {
+			content: `resources:
+- deployment.yaml
+- base
+- github.com/kubernetes-sigs/kustomize//examples/helloWorld
+`,
+		},
+		"badYaml": {
+			content: "resources:\n- a\n  b: c\n",
+			expected: []Diagnostic{{
+				Range: Range{
+					Start: Position{Line: 2},
+					End:   Position{Line: 2, Character: 6}},
+				Severity: SeverityError,
+			}},
+		},
+		"unknownField": {
+			content: "namePrefix: x-\nnameSufix: -y\n",
+			expected: []Diagnostic{{
+				Range: Range{
+					Start: Position{Line: 1},
+					End:   Position{Line: 1, Character: 13}},
+				Severity: SeverityError,
+			}},
+		},
+		"wrongKind": {
+			content: "kind: Deployment\n",
+			expected: []Diagnostic{{
+				Range: Range{
+					End: Position{Character: 16}},
+				Severity: SeverityError,
+				Message:  "kind should be Kustomization",
+			}},
+		},
+		"missingFiles": {
+			content: `resources:
+- deployment.yaml
+- nope.yaml
+- empty
+patchesStrategicMerge:
+- patch.yaml
+`,
+			expected: []Diagnostic{
+				{
+					Range: Range{
+						Start: Position{Line: 2},
+						End:   Position{Line: 2, Character: 11}},
+					Severity: SeverityError,
+					Message:  "missing file or directory 'nope.yaml'",
+				},
+				{
+					Range: Range{
+						Start: Position{Line: 3},
+						End:   Position{Line: 3, Character: 7}},
+					Severity: SeverityWarning,
+					Message:  "directory 'empty' has no kustomization file",
+				},
+				{
+					Range: Range{
+						Start: Position{Line: 5},
+						End:   Position{Line: 5, Character: 12}},
+					Severity: SeverityError,
+					Message:  "missing file or directory 'patch.yaml'",
+				},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		actual := Diagnose(
+			fSys, "/app/kustomization.yaml", []byte(tc.content))
+		if len(actual) != len(tc.expected) {
+			t.Fatalf("%s: expected %v, got %v", n, tc.expected, actual)
+		}
+		for i, e := range tc.expected {
+			a := actual[i]
+			if a.Range != e.Range || a.Severity != e.Severity {
+				t.Errorf("%s: expected %v, got %v", n, e, a)
+			}
+			if e.Message != "" && a.Message != e.Message {
+				t.Errorf("%s: expected message %q, got %q",
+					n, e.Message, a.Message)
+			}
+		}
+	}
+}
+
+func frame(s string) string {
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(s), s)
+}
+
+// readReplies splits the server's output into messages.
+func readReplies(t *testing.T, out []byte) []map[string]interface{} {
+	var result []map[string]interface{}
+	s := &Server{in: bufio.NewReader(bytes.NewReader(out))}
+	for {
+		body, err := s.read()
+		if err != nil {
+			return result
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result = append(result, m)
+	}
+}
+
+func TestServe(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(""))
+	text := "namePrefix: x-\\nresources:\\n- base\\n"
+	in := strings.Join([]string{
+		frame(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`),
+		frame(`{"jsonrpc":"2.0","method":"initialized","params":{}}`),
+		frame(`{"jsonrpc":"2.0","method":"textDocument/didOpen",` +
+			`"params":{"textDocument":{"uri":"file:///app/kustomization.yaml",` +
+			`"text":"` + text + `"}}}`),
+		frame(`{"jsonrpc":"2.0","id":2,"method":"textDocument/hover",` +
+			`"params":{"textDocument":{"uri":"file:///app/kustomization.yaml"},` +
+			`"position":{"line":0,"character":3}}}`),
+		frame(`{"jsonrpc":"2.0","id":3,"method":"textDocument/definition",` +
+			`"params":{"textDocument":{"uri":"file:///app/kustomization.yaml"},` +
+			`"position":{"line":2,"character":3}}}`),
+		frame(`{"jsonrpc":"2.0","id":4,"method":"shutdown"}`),
+		frame(`{"jsonrpc":"2.0","method":"exit"}`),
+	}, "")
+	var out bytes.Buffer
+	err := NewServer(fSys, strings.NewReader(in), &out).Serve()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	replies := readReplies(t, out.Bytes())
+	if len(replies) != 5 {
+		t.Fatalf("expected 5 messages, got %v", replies)
+	}
+	caps := replies[0]["result"].(map[string]interface{})["capabilities"]
+	if caps.(map[string]interface{})["hoverProvider"] != true {
+		t.Errorf("expected hover capability, got %v", caps)
+	}
+	if replies[1]["method"] != "textDocument/publishDiagnostics" {
+		t.Errorf("expected diagnostics, got %v", replies[1])
+	}
+	diags := replies[1]["params"].(map[string]interface{})["diagnostics"]
+	if len(diags.([]interface{})) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diags)
+	}
+	contents := replies[2]["result"].(map[string]interface{})["contents"]
+	if !strings.Contains(
+		contents.(map[string]interface{})["value"].(string), "Prepended") {
+		t.Errorf("unexpected hover %v", contents)
+	}
+	locs := replies[3]["result"].([]interface{})
+	uri := locs[0].(map[string]interface{})["uri"]
+	if uri != "file:///app/base/kustomization.yaml" {
+		t.Errorf("unexpected definition %v", uri)
+	}
+	if _, ok := replies[4]["result"]; !ok {
+		t.Errorf("expected shutdown result, got %v", replies[4])
+	}
+}
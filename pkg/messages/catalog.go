@@ -0,0 +1,169 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package messages is the catalog of messages kustomize
+// shows its users.  Each has an ID, which doesn't change
+// from release to release, so scripts can key off it, and
+// text, which may be translated.
+package messages
+
+// ID identifies a message.  IDs are a public contract;
+// don't rename or reuse them.
+type ID string
+
+const (
+	// Unknown is the ID of errors not in the catalog.
+	Unknown ID = "unknown"
+	// Error prefixes an error the CLI prints.
+	Error ID = "error"
+
+	// Flags and arguments.
+	BadFlag              ID = "bad-flag"
+	FlagIllegalValue     ID = "flag-illegal-value"
+	FlagWantValue        ID = "flag-want-value"
+	FlagRequires         ID = "flag-requires"
+	FlagsGoTogether      ID = "flags-go-together"
+	FlagNegative         ID = "flag-negative"
+	BuildOnePath         ID = "build-one-path"
+	BuildSkippedFailures ID = "build-skipped-failures"
+
+	// Loading files and bases.
+	FileNotInRoot       ID = "file-not-in-root"
+	SymlinkDenied       ID = "symlink-denied"
+	SymlinkNotInRoot    ID = "symlink-not-in-root"
+	ExpectingDirectory  ID = "expecting-directory"
+	NotKustomization    ID = "not-kustomization"
+	CycleRoot           ID = "cycle-root"
+	CycleRepo           ID = "cycle-repo"
+	CycleArtifact       ID = "cycle-artifact"
+	RepoCloneFailed     ID = "repo-clone-failed"
+	RepoDigestMismatch  ID = "repo-digest-mismatch"
+	BaseNotInRepo       ID = "base-not-in-repo"
+	BaseNotInDigest     ID = "base-not-in-digest"
+	BaseNotInArtifact   ID = "base-not-in-artifact"
+	BaseNotInBucket     ID = "base-not-in-bucket"
+	PathNotInArtifact   ID = "path-not-in-artifact"
+	PathNotInObjects    ID = "path-not-in-objects"
+	ObjectNotInPrefix   ID = "object-not-in-prefix"
+	BaseNotInArchive    ID = "base-not-in-archive"
+	PathNotInArchive    ID = "path-not-in-archive"
+	EntryNotInArchive   ID = "entry-not-in-archive"
+	LinkInArchive       ID = "link-in-archive"
+	TranslationsInvalid ID = "translations-invalid"
+
+	// Fetching remote bases and files.
+	NetworkDisabled      ID = "network-disabled"
+	RemoteBasesFailed    ID = "remote-bases-failed"
+	RepoDigestFailed     ID = "repo-digest-failed"
+	URLFetchFailed       ID = "url-fetch-failed"
+	ArtifactPullFailed   ID = "artifact-pull-failed"
+	ArtifactNoDocuments  ID = "artifact-no-documents"
+	ObjectReadFailed     ID = "object-read-failed"
+	NoObjects            ID = "no-objects"
+	ArchiveFetchFailed   ID = "archive-fetch-failed"
+	ArchiveExtractFailed ID = "archive-extract-failed"
+	RemoteTooManyFiles   ID = "remote-too-many-files"
+	RemoteTooManyBytes   ID = "remote-too-many-bytes"
+	RemoteFileTooLarge   ID = "remote-file-too-large"
+
+	// Validating kustomizations, resources and output.
+	KustomizationInvalid       ID = "kustomization-invalid"
+	KustomizationFieldsInvalid ID = "kustomization-fields-invalid"
+	InvalidKey                 ID = "invalid-key"
+	InvalidAnnotations         ID = "invalid-annotations"
+	InvalidLabels              ID = "invalid-labels"
+	DuplicateKeys              ID = "duplicate-keys"
+	PatchTargetNotFound        ID = "patch-target-not-found"
+	PatchSelectsNothing        ID = "patch-selects-nothing"
+	DependsOnNotFound          ID = "depends-on-not-found"
+	DependenciesNotInOutput    ID = "dependencies-not-in-output"
+	EncryptedFieldsChanged     ID = "encrypted-fields-changed"
+	OutputProblems             ID = "output-problems"
+	ExpectationsFailed         ID = "expectations-failed"
+	APIsNotServed              ID = "apis-not-served"
+	AssertionsFailed           ID = "assertions-failed"
+	ResourcesDiffer            ID = "resources-differ"
+
+	// Plugins.
+	PluginLoadFailed   ID = "plugin-load-failed"
+	PluginWrongKind    ID = "plugin-wrong-kind"
+	PluginFailed       ID = "plugin-failed"
+	PostRendererFailed ID = "post-renderer-failed"
+)
+
+// english is the text of each message, a format for
+// fmt.Sprintf.  Translations may reorder the arguments
+// with explicit indexes, e.g. %[2]s.
+var english = map[ID]string{
+	Error: "Error: %s",
+
+	FlagIllegalValue:     "illegal flag value --%s %s; legal values: %v",
+	FlagWantValue:        "illegal flag value --%s %s; want %s",
+	FlagRequires:         "--%s requires --%s",
+	FlagsGoTogether:      "--%s and --%s go together",
+	FlagNegative:         "--%s can't be negative",
+	BuildOnePath:         "specify one path to %s",
+	BuildSkippedFailures: "skipped %d failed parts of the build:\n%s",
+
+	FileNotInRoot: "security; file '%s' is not in or below '%s'",
+	SymlinkDenied: "security; file '%s' is reached through a " +
+		"symbolic link, which --%s %s doesn't follow",
+	SymlinkNotInRoot: "security; file '%s' links to '%s', which is " +
+		"not in or below '%s'; --%s %s would follow it",
+	ExpectingDirectory: "'%s' refers to file '%s'; expecting directory",
+	NotKustomization:   "unable to find one of %v in directory '%s'",
+	CycleRoot: "cycle detected: candidate root '%s' " +
+		"contains visited root '%s'",
+	CycleRepo: "cycle detected: URI '%s' referenced " +
+		"by previous URI '%s'",
+	CycleArtifact: "cycle detected: artifact '%s' referenced by itself",
+	RepoDigestMismatch: "security; '%s' has sha256=%s, rather than " +
+		"the sha256=%s its url pins",
+	BaseNotInRepo: "security; bases in kustomizations found in " +
+		"cloned git repos must be within the repo, " +
+		"but base '%s' is outside '%s'",
+	BaseNotInDigest: "security; the sha256 of '%s' only covers '%s', " +
+		"but base '%s' is outside it",
+	BaseNotInArtifact: "security; bases in kustomizations found in " +
+		"OCI artifacts must be within the artifact, " +
+		"but base '%s' is outside '%s'",
+	BaseNotInBucket: "security; bases in kustomizations found in " +
+		"buckets must be within the bucket, " +
+		"but base '%s' is outside '%s'",
+	BaseNotInArchive: "security; bases in kustomizations found in " +
+		"archives must be within the archive, " +
+		"but base '%s' is outside '%s'",
+	PathNotInArtifact:   "security; '%s' is outside the artifact",
+	PathNotInObjects:    "security; '%s' is outside the objects",
+	ObjectNotInPrefix:   "security; object '%s' is outside %s",
+	PathNotInArchive:    "security; '%s' is outside the archive",
+	EntryNotInArchive:   "security; entry '%s' is outside %s",
+	LinkInArchive:       "security; entry '%s' of %s is a link",
+	TranslationsInvalid: "reading translations %s",
+
+	NetworkDisabled:     "can't %s %s, as --%s=false",
+	RemoteBasesFailed:   "%d remote bases failed:\n%s",
+	ArtifactNoDocuments: "%s has no YAML or JSON files",
+	NoObjects:           "no objects under %s",
+	RemoteTooManyFiles:  "security; %s has more than --%s %d files",
+	RemoteTooManyBytes:  "security; %s has more than --%s %d bytes",
+	RemoteFileTooLarge:  "security; %s is larger than --%s %d bytes",
+
+	KustomizationFieldsInvalid: "Failed to read kustomization file under %s:\n%s",
+	InvalidKey:                 "%q is not a valid key name: %s",
+	DuplicateKeys:              "%s has duplicate keys: %s",
+	PatchTargetNotFound: "%s; %s; failed to find unique " +
+		"target for patch %s",
+	PatchSelectsNothing:     "the target of %s selects no resources",
+	DependsOnNotFound:       "dependsOn: no resource %s",
+	DependenciesNotInOutput: "dependencies not in the output:\n  %s",
+	EncryptedFieldsChanged:  "the build changed encrypted fields:\n%s",
+	OutputProblems:          "%d problems with the output:\n%s",
+	ExpectationsFailed:      "%d of %d expectations failed:\n  %s",
+	APIsNotServed: "%d resources have APIs kubernetes %s " +
+		"doesn't serve:\n%s",
+	AssertionsFailed: "%d of %d assertions failed",
+	ResourcesDiffer:  "%d resources differ between %s and %s",
+
+	PluginWrongKind: "plugin %s not %s",
+}
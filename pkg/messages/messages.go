@@ -0,0 +1,60 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package messages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+)
+
+// translations replace the english text of messages;
+// SetTranslations sets them.
+var translations map[ID]string
+
+// SetTranslations sets the text Sprintf uses, in place
+// of the english, for the messages in t.
+func SetTranslations(t map[ID]string) {
+	translations = t
+}
+
+// Sprintf formats the message with the given arguments,
+// in the language of the translations set, if they have
+// the message, or else in english.
+func Sprintf(id ID, args ...interface{}) string {
+	if f, ok := translations[id]; ok {
+		s := fmt.Sprintf(f, args...)
+		// A translation that doesn't take the arguments
+		// the english does is no use.
+		if !strings.Contains(s, "%!") {
+			return s
+		}
+	}
+	return fmt.Sprintf(english[id], args...)
+}
+
+// Errorf returns an error, with the given message ID,
+// whose message is Sprintf(id, args...).
+func Errorf(id ID, args ...interface{}) error {
+	return WithID(id, errors.New(Sprintf(id, args...)))
+}
+
+// WithID gives err the message ID, keeping its message,
+// or returns nil if err is nil.
+func WithID(id ID, err error) error {
+	return kusterr.WithID(string(id), err)
+}
+
+// IDOf returns the message ID of err, looking through
+// errors it wraps, or Unknown if it has none.  As with
+// kusterr.ClassOf, the innermost ID, that closest to
+// the root cause, wins.
+func IDOf(err error) ID {
+	if id := kusterr.IDOf(err); id != "" {
+		return ID(id)
+	}
+	return Unknown
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package messages
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+)
+
+func TestCatalog(t *testing.T) {
+	for id, text := range english {
+		if id == Unknown || strings.TrimSpace(text) == "" {
+			t.Errorf("%s: unexpected text %q", id, text)
+		}
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	defer SetTranslations(nil)
+	testCases := map[string]struct {
+		translations map[ID]string
+		expected     string
+	}{
+		"english": {
+			expected: "--a and --b go together",
+		},
+		"translated": {
+			translations: map[ID]string{
+				FlagsGoTogether: "--%[2]s nur mit --%[1]s",
+			},
+			expected: "--b nur mit --a",
+		},
+		"badTranslation": {
+			translations: map[ID]string{
+				FlagsGoTogether: "--%s",
+			},
+			expected: "--a and --b go together",
+		},
+		"otherTranslated": {
+			translations: map[ID]string{
+				FlagNegative: "--%s darf nicht negativ sein",
+			},
+			expected: "--a and --b go together",
+		},
+	}
+	for n, tc := range testCases {
+		SetTranslations(tc.translations)
+		if actual := Sprintf(FlagsGoTogether, "a", "b"); actual != tc.expected {
+			t.Errorf("%s: expected %q, got %q", n, tc.expected, actual)
+		}
+	}
+}
+
+func TestIDOf(t *testing.T) {
+	base := fmt.Errorf("boom")
+	testCases := map[string]struct {
+		err      error
+		expected ID
+	}{
+		"nil": {
+			err:      nil,
+			expected: Unknown,
+		},
+		"none": {
+			err:      base,
+			expected: Unknown,
+		},
+		"errorf": {
+			err:      Errorf(FlagNegative, "a"),
+			expected: FlagNegative,
+		},
+		"withID": {
+			err:      WithID(RepoCloneFailed, base),
+			expected: RepoCloneFailed,
+		},
+		"wrapped": {
+			err: kusterr.WithClass(kusterr.ClassLoad, errors.Wrap(
+				kusterr.WithKind(base, Errorf(FlagNegative, "a")), "loading")),
+			expected: FlagNegative,
+		},
+		"innermostWins": {
+			err: WithID(BadFlag, errors.Wrap(
+				Errorf(FlagNegative, "a"), "flags")),
+			expected: FlagNegative,
+		},
+	}
+	for n, tc := range testCases {
+		if actual := IDOf(tc.err); actual != tc.expected {
+			t.Errorf("%s: expected %s, got %s", n, tc.expected, actual)
+		}
+	}
+}
+
+func TestWithIDKeepsMessage(t *testing.T) {
+	err := WithID(BadFlag, fmt.Errorf("unknown flag: --x"))
+	if err.Error() != "unknown flag: --x" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+	if errors.Cause(err).Error() != "unknown flag: --x" {
+		t.Fatalf("unexpected cause %v", errors.Cause(err))
+	}
+	if WithID(BadFlag, nil) != nil {
+		t.Fatalf("expected nil")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package messages
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/yaml"
+)
+
+// LangEnv names the language of messages, e.g. de_DE,
+// overriding the usual LC_ALL, LC_MESSAGES and LANG.
+const LangEnv = "KUSTOMIZE_LANG"
+
+// Language returns the language of messages the
+// environment asks for, or "" for english.
+func Language() string {
+	for _, env := range []string{LangEnv, "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if lang := os.Getenv(env); lang != "" {
+			// Drop any codeset and modifier, as in de_DE.UTF-8@euro.
+			lang = strings.SplitN(lang, ".", 2)[0]
+			lang = strings.SplitN(lang, "@", 2)[0]
+			if lang == "C" || lang == "POSIX" {
+				return ""
+			}
+			return lang
+		}
+	}
+	return ""
+}
+
+// TranslationsDir is where LoadTranslations looks by default.
+func TranslationsDir() string {
+	return filepath.Join(pgmconfig.ConfigRoot(), "messages")
+}
+
+// LoadTranslations reads the translations for lang,
+// e.g. de_DE, from {dir}/de_DE.yaml or, failing that,
+// {dir}/de.yaml, files like
+//
+//	error: "Fehler: %s"
+//	flag-negative: "--%s darf nicht negativ sein"
+//
+// mapping message IDs to text.  Missing files hold no
+// translations, and english is never translated.
+func LoadTranslations(
+	fSys fs.FileSystem, dir, lang string) (map[ID]string, error) {
+	if lang == "" || lang == "en" || strings.HasPrefix(lang, "en_") {
+		return nil, nil
+	}
+	for _, l := range []string{lang, strings.SplitN(lang, "_", 2)[0]} {
+		path := filepath.Join(dir, l+".yaml")
+		if !fSys.Exists(path) {
+			continue
+		}
+		data, err := fSys.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var t map[ID]string
+		if err := yaml.Unmarshal(data, &t); err != nil {
+			return nil, WithID(TranslationsInvalid, errors.Wrap(
+				err, Sprintf(TranslationsInvalid, path)))
+		}
+		for id := range t {
+			if _, ok := english[id]; !ok {
+				log.Printf("warning: %s has unknown message %s", path, id)
+				delete(t, id)
+			}
+		}
+		return t, nil
+	}
+	return nil, nil
+}
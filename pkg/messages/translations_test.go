@@ -0,0 +1,110 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package messages
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+func TestLanguage(t *testing.T) {
+	envs := []string{LangEnv, "LC_ALL", "LC_MESSAGES", "LANG"}
+	for _, env := range envs {
+		if v, ok := os.LookupEnv(env); ok {
+			defer os.Setenv(env, v)
+		} else {
+			defer os.Unsetenv(env)
+		}
+	}
+	testCases := map[string]struct {
+		env      map[string]string
+		expected string
+	}{
+		"none": {
+			expected: "",
+		},
+		"lang": {
+			env:      map[string]string{"LANG": "de_DE.UTF-8"},
+			expected: "de_DE",
+		},
+		"modifier": {
+			env:      map[string]string{"LANG": "de_DE@euro"},
+			expected: "de_DE",
+		},
+		"lcAllFirst": {
+			env:      map[string]string{"LC_ALL": "fr", "LANG": "de_DE"},
+			expected: "fr",
+		},
+		"override": {
+			env:      map[string]string{LangEnv: "C", "LANG": "de_DE"},
+			expected: "",
+		},
+	}
+	for n, tc := range testCases {
+		for _, env := range envs {
+			os.Unsetenv(env)
+		}
+		for k, v := range tc.env {
+			os.Setenv(k, v)
+		}
+		if actual := Language(); actual != tc.expected {
+			t.Errorf("%s: expected %q, got %q", n, tc.expected, actual)
+		}
+	}
+}
+
+func TestLoadTranslations(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/messages/de.yaml", []byte(`
+error: "Fehler: %s"
+no-such-message: "?"
+`))
+	fSys.WriteFile("/messages/fr_CA.yaml", []byte(`
+error: "Erreur : %s"
+`))
+	fSys.WriteFile("/messages/nl.yaml", []byte(`[not, a, map]`))
+	testCases := map[string]struct {
+		lang     string
+		expected map[ID]string
+		err      bool
+	}{
+		"english": {
+			lang: "",
+		},
+		"exact": {
+			lang:     "fr_CA",
+			expected: map[ID]string{Error: "Erreur : %s"},
+		},
+		"language": {
+			lang:     "de_AT",
+			expected: map[ID]string{Error: "Fehler: %s"},
+		},
+		"missing": {
+			lang: "fr_FR",
+		},
+		"invalid": {
+			lang: "nl",
+			err:  true,
+		},
+	}
+	for n, tc := range testCases {
+		actual, err := LoadTranslations(fSys, "/messages", tc.lang)
+		if (err != nil) != tc.err {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		if err != nil {
+			if IDOf(err) != TranslationsInvalid {
+				t.Errorf("%s: unexpected ID %s", n, IDOf(err))
+			}
+			continue
+		}
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", n, tc.expected, actual)
+		}
+	}
+}
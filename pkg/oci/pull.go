@@ -0,0 +1,445 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// File is a file in an artifact.
+type File struct {
+	// Name is the file's slash-separated path, relative
+	// to the top of the artifact: its path in a tar
+	// layer, or the title of a layer that's a file, or
+	// the layer's digest.
+	Name string
+	// MediaType is the media type of a layer that's a
+	// file, or empty for a file in a tar layer.
+	MediaType string
+	Data      []byte
+}
+
+// Puller pulls artifacts.
+type Puller struct {
+	// Client makes the requests.
+	Client *http.Client
+	// MaxBlobSize bounds the size of the manifest and
+	// of each layer, and of the files in tar layers.
+	// Zero means no bound.
+	MaxBlobSize int64
+	// MaxTotalSize bounds the sum of the sizes of the
+	// files.  Zero means no bound.
+	MaxTotalSize int64
+	// auth is the Authorization header the registry
+	// last asked for.
+	auth string
+}
+
+const (
+	mediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	// annotationTitle names a layer that's a file, or,
+	// with annotationUnpack, a tar of a directory.
+	annotationTitle = "org.opencontainers.image.title"
+	// annotationUnpack marks the layers that ORAS, and
+	// tools like it, make of directories.
+	annotationUnpack = "io.deis.oras.content.unpack"
+)
+
+type manifest struct {
+	MediaType string       `json:"mediaType"`
+	Layers    []descriptor `json:"layers"`
+	Manifests []descriptor `json:"manifests"`
+}
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// Pull returns the files of the artifact, in the order
+// of its layers, the files of a tar layer sorted by
+// path.  A layer with a title is a file of that name,
+// unless, as ORAS marks directories, it's to be
+// unpacked; other tar layers are unpacked, as those of
+// images are.  Every blob, and the manifest if the
+// reference is pinned, is checked against its digest.
+func (p *Puller) Pull(ref Reference) ([]File, error) {
+	data, err := p.fetch(ref, descriptor{
+		MediaType: mediaTypeOCIManifest, Digest: ref.Digest, Size: -1},
+		ref.manifest())
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrapf(err, "manifest of %s", ref)
+	}
+	if len(m.Manifests) > 0 {
+		return nil, fmt.Errorf(
+			"%s is an index of manifests, rather than an artifact", ref)
+	}
+	var result []File
+	var total int64
+	for _, l := range m.Layers {
+		if !digestRegex.MatchString(l.Digest) {
+			return nil, fmt.Errorf(
+				"%s has a layer with digest '%s'", ref, l.Digest)
+		}
+		data, err := p.fetch(ref, l, "blobs/"+l.Digest)
+		if err != nil {
+			return nil, err
+		}
+		title := l.Annotations[annotationTitle]
+		var files []File
+		if (title == "" && strings.Contains(l.MediaType, "tar")) ||
+			l.Annotations[annotationUnpack] == "true" {
+			files, err = p.untar(data, strings.Contains(l.MediaType, "gzip"))
+		} else {
+			if title == "" {
+				title = l.Digest
+			}
+			files = []File{{Name: title, MediaType: l.MediaType, Data: data}}
+		}
+		for i := range files {
+			if err == nil {
+				files[i].Name, err = cleanName(files[i].Name)
+			}
+			total += int64(len(files[i].Data))
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "layer %s of %s", l.Digest, ref)
+		}
+		if p.MaxTotalSize > 0 && total > p.MaxTotalSize {
+			return nil, fmt.Errorf(
+				"security; the files of %s have more than %d bytes",
+				ref, p.MaxTotalSize)
+		}
+		result = append(result, files...)
+	}
+	return result, nil
+}
+
+// Resolve returns the digest of the manifest the
+// reference names now, e.g. to pin a tag.
+func (p *Puller) Resolve(ref Reference) (string, error) {
+	data, err := p.fetch(ref, descriptor{
+		MediaType: mediaTypeOCIManifest, Digest: ref.Digest, Size: -1},
+		ref.manifest())
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// cleanName returns the name of a file, cleaned, as a
+// path relative to the top of the artifact, or an error
+// if it isn't one.
+func cleanName(name string) (string, error) {
+	clean := path.Clean(strings.TrimPrefix(name, "./"))
+	if clean == "." || path.IsAbs(clean) ||
+		clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf(
+			"security; file '%s' is outside the artifact", name)
+	}
+	return clean, nil
+}
+
+// untar returns the regular files in the tar.
+func (p *Puller) untar(data []byte, gzipped bool) ([]File, error) {
+	var r io.Reader = bytes.NewReader(data)
+	if gzipped {
+		z, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		r = z
+	}
+	t := tar.NewReader(r)
+	var result []File
+	var total int64
+	for {
+		h, err := t.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag != tar.TypeReg && h.Typeflag != tar.TypeRegA {
+			continue
+		}
+		if p.MaxBlobSize > 0 && h.Size > p.MaxBlobSize {
+			return nil, fmt.Errorf(
+				"security; file %s has more than %d bytes",
+				h.Name, p.MaxBlobSize)
+		}
+		total += h.Size
+		if p.MaxTotalSize > 0 && total > p.MaxTotalSize {
+			return nil, fmt.Errorf(
+				"security; its files have more than %d bytes", p.MaxTotalSize)
+		}
+		b, err := ioutil.ReadAll(t)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, File{Name: path.Clean(h.Name), Data: b})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// fetch returns the blob, or the manifest, d describes,
+// checking its size and digest.
+func (p *Puller) fetch(
+	ref Reference, d descriptor, path string) ([]byte, error) {
+	if p.MaxBlobSize > 0 && d.Size > p.MaxBlobSize {
+		return nil, fmt.Errorf(
+			"security; %s of %s has more than %d bytes",
+			path, ref, p.MaxBlobSize)
+	}
+	resp, err := p.get(ref, path, d.MediaType)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var body io.Reader = resp.Body
+	if p.MaxBlobSize > 0 {
+		body = io.LimitReader(body, p.MaxBlobSize+1)
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %s of %s", path, ref)
+	}
+	if p.MaxBlobSize > 0 && int64(len(b)) > p.MaxBlobSize {
+		return nil, fmt.Errorf(
+			"security; %s of %s has more than %d bytes",
+			path, ref, p.MaxBlobSize)
+	}
+	if d.Digest == "" {
+		// A manifest pulled by tag.
+		return b, nil
+	}
+	sum := sha256.Sum256(b)
+	if actual := "sha256:" + hex.EncodeToString(sum[:]); actual != d.Digest {
+		return nil, fmt.Errorf(
+			"%s of %s has digest %s, rather than %s",
+			path, ref, actual, d.Digest)
+	}
+	return b, nil
+}
+
+// get gets the path in the repository, authorizing
+// as the registry asks to.
+func (p *Puller) get(
+	ref Reference, path, mediaType string) (*http.Response, error) {
+	u := ref.url(path)
+	resp, err := p.do(u, mediaType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %s of %s", path, ref)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		p.auth, err = p.authorize(ref, challenge)
+		if err != nil {
+			return nil, errors.Wrapf(err, "authorizing to %s", ref.Registry)
+		}
+		resp, err = p.do(u, mediaType)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching %s of %s", path, ref)
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetching %s of %s: %s", path, ref, resp.Status)
+	}
+	return resp, nil
+}
+
+func (p *Puller) do(u, mediaType string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if mediaType == mediaTypeOCIManifest {
+		mediaType += ", " + mediaTypeDockerManifest
+	}
+	req.Header.Set("Accept", mediaType)
+	if p.auth != "" {
+		req.Header.Set("Authorization", p.auth)
+	}
+	return p.client().Do(req)
+}
+
+func (p *Puller) client() *http.Client {
+	if p.Client == nil {
+		return http.DefaultClient
+	}
+	return p.Client
+}
+
+// authorize returns the Authorization header the
+// challenge of a registry asks for, getting a token
+// if it asks for one.
+func (p *Puller) authorize(ref Reference, challenge string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	user, password, hasCredentials := credentials(ref.Registry)
+	basic := "Basic " + base64.StdEncoding.EncodeToString(
+		[]byte(user+":"+password))
+	switch scheme {
+	case "basic":
+		if !hasCredentials {
+			return "", fmt.Errorf(
+				"no credentials for %s in %s", ref.Registry, dockerConfig())
+		}
+		return basic, nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("unsupported challenge '%s'", challenge)
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("challenge '%s' has no realm", challenge)
+	}
+	q := realm.Query()
+	if s := params["service"]; s != "" {
+		q.Set("service", s)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.Repository + ":pull"
+	}
+	q.Set("scope", scope)
+	realm.RawQuery = q.Encode()
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if hasCredentials {
+		req.Header.Set("Authorization", basic)
+	}
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("getting a token: %s", resp.Status)
+	}
+	var t struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&t)
+	if err != nil {
+		return "", errors.Wrap(err, "getting a token")
+	}
+	if t.Token == "" {
+		t.Token = t.AccessToken
+	}
+	return "Bearer " + t.Token, nil
+}
+
+// parseChallenge parses a WWW-Authenticate header, e.g.
+// Bearer realm="https://auth.example.com/token",service="x".
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	scheme := strings.ToLower(parts[0])
+	if len(parts) == 1 {
+		return scheme, params
+	}
+	rest := parts[1]
+	for rest != "" {
+		i := strings.Index(rest, "=")
+		if i < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:i]))
+		rest = strings.TrimSpace(rest[i+1:])
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			j := strings.Index(rest[1:], `"`)
+			if j < 0 {
+				break
+			}
+			value, rest = rest[1:j+1], rest[j+2:]
+		} else if j := strings.Index(rest, ","); j >= 0 {
+			value, rest = rest[:j], rest[j:]
+		} else {
+			value, rest = rest, ""
+		}
+		params[key] = value
+		rest = strings.TrimLeft(rest, ", ")
+	}
+	return scheme, params
+}
+
+// dockerConfig is the path of the docker config
+// file holding credentials for registries.
+func dockerConfig() string {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".docker")
+	}
+	return filepath.Join(dir, "config.json")
+}
+
+// credentials returns the user and password for the
+// registry in the docker config file, if it has them.
+// Credential helpers aren't supported.
+func credentials(registry string) (string, string, bool) {
+	data, err := ioutil.ReadFile(dockerConfig())
+	if err != nil {
+		return "", "", false
+	}
+	var c struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if json.Unmarshal(data, &c) != nil {
+		return "", "", false
+	}
+	for _, key := range []string{registry, "https://" + registry} {
+		a, ok := c.Auths[key]
+		if !ok {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(a.Auth)
+		if err != nil {
+			return "", "", false
+		}
+		parts := strings.SplitN(string(b), ":", 2)
+		if len(parts) != 2 {
+			return "", "", false
+		}
+		return parts[0], parts[1], true
+	}
+	return "", "", false
+}
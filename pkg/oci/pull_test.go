@@ -0,0 +1,222 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func digestOf(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// registry serves blobs and manifests by digest, as an
+// OCI registry does, asking for a bearer token got
+// from /token if token isn't empty.
+type registry struct {
+	blobs map[string][]byte
+	token string
+}
+
+func (r *registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		if req.URL.Query().Get("scope") != "repository:org/configs:pull" {
+			http.Error(w, "bad scope", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": r.token})
+		return
+	}
+	if r.token != "" && req.Header.Get("Authorization") != "Bearer "+r.token {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="http://`+
+			req.Host+`/token",service="registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	i := strings.LastIndex(req.URL.Path, "/")
+	if !strings.HasPrefix(req.URL.Path, "/v2/org/configs/") {
+		http.NotFound(w, req)
+		return
+	}
+	b, ok := r.blobs[req.URL.Path[i+1:]]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	w.Write(b)
+}
+
+// push adds the layers, and a manifest of them, whose
+// digest it returns.
+func (r *registry) push(layers ...descriptor) string {
+	m, _ := json.Marshal(manifest{
+		MediaType: mediaTypeOCIManifest, Layers: layers})
+	r.blobs[digestOf(m)] = m
+	return digestOf(m)
+}
+
+// layer adds a blob, returning a descriptor of it.
+func (r *registry) layer(mediaType, title string, b []byte) descriptor {
+	r.blobs[digestOf(b)] = b
+	d := descriptor{
+		MediaType: mediaType, Digest: digestOf(b), Size: int64(len(b))}
+	if title != "" {
+		d.Annotations = map[string]string{annotationTitle: title}
+	}
+	return d
+}
+
+func makeTarGz(files map[string]string) []byte {
+	var b bytes.Buffer
+	z := gzip.NewWriter(&b)
+	w := tar.NewWriter(z)
+	w.WriteHeader(&tar.Header{
+		Name: "./c/", Typeflag: tar.TypeDir, Mode: 0755})
+	for name, content := range files {
+		w.WriteHeader(&tar.Header{
+			Name: name, Typeflag: tar.TypeReg, Mode: 0644,
+			Size: int64(len(content))})
+		w.Write([]byte(content))
+	}
+	w.Close()
+	z.Close()
+	return b.Bytes()
+}
+
+func TestPull(t *testing.T) {
+	for _, token := range []string{"", "secret"} {
+		r := &registry{blobs: make(map[string][]byte), token: token}
+		s := httptest.NewServer(r)
+		defer s.Close()
+		digest := r.push(
+			r.layer("application/vnd.kustomize.config.v1+yaml",
+				"a.yaml", []byte("namePrefix: []")),
+			r.layer("application/vnd.oci.image.layer.v1.tar+gzip", "",
+				makeTarGz(map[string]string{
+					"./c/d.yml": "nameSuffix: []",
+					"b.yaml":    "commonLabels: []",
+				})))
+		ref, err := ParseReference(
+			"oci://" + strings.TrimPrefix(s.URL, "http://") +
+				"/org/configs@" + digest)
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		files, err := (&Puller{}).Pull(ref)
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		expected := []File{
+			{Name: "a.yaml",
+				MediaType: "application/vnd.kustomize.config.v1+yaml",
+				Data:      []byte("namePrefix: []")},
+			{Name: "b.yaml", Data: []byte("commonLabels: []")},
+			{Name: "c/d.yml", Data: []byte("nameSuffix: []")},
+		}
+		if !reflect.DeepEqual(files, expected) {
+			t.Fatalf("expected %v, got %v", expected, files)
+		}
+	}
+}
+
+func TestPullByTag(t *testing.T) {
+	r := &registry{blobs: make(map[string][]byte)}
+	s := httptest.NewServer(r)
+	defer s.Close()
+	dir := r.layer("application/vnd.oci.image.layer.v1.tar+gzip", "base",
+		makeTarGz(map[string]string{
+			"base/kustomization.yaml": "resources: [pod.yaml]",
+			"base/pod.yaml":           "kind: Pod",
+		}))
+	dir.Annotations[annotationUnpack] = "true"
+	digest := r.push(
+		r.layer("application/vnd.oci.image.layer.v1.tar", "README.md",
+			[]byte("# bases")),
+		dir)
+	r.blobs["v1"] = r.blobs[digest]
+	ref, err := ParseReference(
+		"oci://" + strings.TrimPrefix(s.URL, "http://") + "/org/configs:v1")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	files, err := (&Puller{}).Pull(ref)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	expected := []File{
+		{Name: "README.md",
+			MediaType: "application/vnd.oci.image.layer.v1.tar",
+			Data:      []byte("# bases")},
+		{Name: "base/kustomization.yaml", Data: []byte("resources: [pod.yaml]")},
+		{Name: "base/pod.yaml", Data: []byte("kind: Pod")},
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Fatalf("expected %v, got %v", expected, files)
+	}
+}
+
+func TestPullErrors(t *testing.T) {
+	r := &registry{blobs: make(map[string][]byte)}
+	s := httptest.NewServer(r)
+	defer s.Close()
+	host := strings.TrimPrefix(s.URL, "http://")
+	big := r.layer("application/yaml", "big.yaml",
+		bytes.Repeat([]byte("#"), 1001))
+	tampered := r.layer("application/yaml", "x.yaml", []byte("a: 1"))
+	r.blobs[tampered.Digest] = []byte("a: 2")
+	escaping := r.layer("application/yaml", "../../etc/x.yaml", []byte("b: 1"))
+	many := r.layer("application/vnd.oci.image.layer.v1.tar+gzip", "",
+		makeTarGz(map[string]string{
+			"a.yaml": strings.Repeat("#", 600),
+			"b.yaml": strings.Repeat("#", 600),
+		}))
+	index, _ := json.Marshal(manifest{
+		Manifests: []descriptor{{Digest: someDigest}}})
+	r.blobs[digestOf(index)] = index
+
+	tests := map[string]string{
+		r.push(big):      "security; blobs/" + big.Digest,
+		r.push(tampered): "has digest " + digestOf([]byte("a: 2")),
+		digestOf(index):  "is an index of manifests",
+		r.push(escaping): "security; file '../../etc/x.yaml' is outside",
+		r.push(many):     "security; its files have more than 1000 bytes",
+		someDigest:       "404 Not Found",
+	}
+	for digest, expected := range tests {
+		ref, err := ParseReference(
+			"oci://" + host + "/org/configs@" + digest)
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		_, err = (&Puller{MaxBlobSize: 1000, MaxTotalSize: 1000}).Pull(ref)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %s, got %v", expected, err)
+		}
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(
+		`Bearer realm="https://auth.example.com/token",` +
+			`service="registry.example.com",scope="repository:a/b:pull"`)
+	expected := map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:a/b:pull",
+	}
+	if scheme != "bearer" || !reflect.DeepEqual(params, expected) {
+		t.Fatalf("unexpected %s %v", scheme, params)
+	}
+}
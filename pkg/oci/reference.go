@@ -0,0 +1,157 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package oci pulls artifacts, e.g. bundles of
+// transformer configs, from OCI registries.
+package oci
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// Scheme starts references to artifacts.
+const Scheme = "oci://"
+
+// Reference names an artifact in a registry, by tag,
+// or pinned to the digest of its manifest, e.g.
+// oci://ghcr.io/someOrg/configs@sha256:...
+type Reference struct {
+	// Registry is the host, and maybe port, of the
+	// registry, e.g. ghcr.io or localhost:5000.
+	Registry string
+	// Repository is e.g. someOrg/configs.
+	Repository string
+	// Tag is e.g. v1, or empty if the reference is
+	// pinned and has none.
+	Tag string
+	// Digest is the digest of the manifest, or empty
+	// if the reference isn't pinned.
+	Digest string
+}
+
+// DefaultTag is the tag of a reference without one.
+const DefaultTag = "latest"
+
+var (
+	digestRegex     = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+	repositoryRegex = regexp.MustCompile(`^[a-z0-9]+([._/-][a-z0-9]+)*$`)
+	tagRegex        = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+)
+
+// IsReference is true if s looks like a Reference,
+// which ParseReference may yet reject.
+func IsReference(s string) bool {
+	return strings.HasPrefix(s, Scheme)
+}
+
+// SplitPath splits a reference to an artifact from the
+// path of a directory in it, if given after a //, as in
+// the URLs of git repos, e.g. the base overlays/prod of
+// oci://ghcr.io/someOrg/bases:v1//overlays/prod.
+func SplitPath(s string) (string, string) {
+	i := strings.Index(strings.TrimPrefix(s, Scheme), "//")
+	if i < 0 {
+		return s, ""
+	}
+	i += len(Scheme)
+	return s[:i], s[i+2:]
+}
+
+// ParseReference parses s, which names the artifact by
+// tag, DefaultTag if it has none, or by digest.  Given
+// both, the artifact is pulled by its digest.
+func ParseReference(s string) (Reference, error) {
+	if !IsReference(s) {
+		return Reference{}, fmt.Errorf("%s doesn't start with %s", s, Scheme)
+	}
+	name := strings.TrimPrefix(s, Scheme)
+	var digest string
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, digest = name[:i], name[i+1:]
+		if !digestRegex.MatchString(digest) {
+			return Reference{}, fmt.Errorf(
+				"%s has digest '%s', rather than sha256: and 64 hex digits",
+				s, digest)
+		}
+	}
+	j := strings.Index(name, "/")
+	if j < 1 {
+		return Reference{}, fmt.Errorf("%s lacks a registry", s)
+	}
+	repo, tag := name[j+1:], ""
+	if k := strings.LastIndex(repo, ":"); k >= 0 {
+		repo, tag = repo[:k], repo[k+1:]
+		if !tagRegex.MatchString(tag) {
+			return Reference{}, fmt.Errorf(
+				"%s has tag '%s', which isn't valid", s, tag)
+		}
+	}
+	if !repositoryRegex.MatchString(repo) {
+		return Reference{}, fmt.Errorf(
+			"%s has repository '%s', which isn't valid", s, repo)
+	}
+	if tag == "" && digest == "" {
+		tag = DefaultTag
+	}
+	return Reference{
+		Registry: name[:j], Repository: repo, Tag: tag, Digest: digest}, nil
+}
+
+// ParsePinnedReference is ParseReference, but s must be
+// pinned to a digest, so its content can't change.
+func ParsePinnedReference(s string) (Reference, error) {
+	r, err := ParseReference(s)
+	if err != nil {
+		return r, err
+	}
+	if !r.Pinned() {
+		return Reference{}, fmt.Errorf(
+			"%s isn't pinned; give the digest of the artifact, "+
+				"e.g. %s@sha256:...", s, s)
+	}
+	return r, nil
+}
+
+// Pinned is true if the reference has a digest.
+func (r Reference) Pinned() bool {
+	return r.Digest != ""
+}
+
+func (r Reference) String() string {
+	s := Scheme + r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Pinned() {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// manifest returns the path of the manifest in the
+// repository, by digest if pinned, else by tag.
+func (r Reference) manifest() string {
+	if r.Pinned() {
+		return "manifests/" + r.Digest
+	}
+	return "manifests/" + r.Tag
+}
+
+// url returns the URL of the given path, e.g.
+// manifests/sha256:..., in the repository.  Registries
+// on this machine are spoken to over plain http.
+func (r Reference) url(path string) string {
+	scheme := "https"
+	host := r.Registry
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if ip := net.ParseIP(host); host == "localhost" ||
+		(ip != nil && ip.IsLoopback()) {
+		scheme = "http"
+	}
+	return scheme + "://" + r.Registry + "/v2/" + r.Repository + "/" + path
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oci
+
+import (
+	"strings"
+	"testing"
+)
+
+const someDigest = "sha256:" +
+	"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestParseReference(t *testing.T) {
+	tests := map[string]Reference{
+		"oci://ghcr.io/someOrg/configs@" + someDigest: {},
+		"oci://ghcr.io/some-org/configs@" + someDigest: {
+			Registry: "ghcr.io", Repository: "some-org/configs",
+			Digest: someDigest},
+		"oci://localhost:5000/a/b/c:v1@" + someDigest: {
+			Registry: "localhost:5000", Repository: "a/b/c",
+			Tag: "v1", Digest: someDigest},
+		"oci://ghcr.io/org/bases:v1.2.0": {
+			Registry: "ghcr.io", Repository: "org/bases", Tag: "v1.2.0"},
+		"oci://ghcr.io/org/bases": {
+			Registry: "ghcr.io", Repository: "org/bases", Tag: "latest"},
+	}
+	for s, expected := range tests {
+		actual, err := ParseReference(s)
+		if expected.Registry == "" {
+			if err == nil || !strings.Contains(err.Error(), "isn't valid") {
+				t.Errorf("%s: unexpected err: %v", s, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected err: %v", s, err)
+			continue
+		}
+		if actual != expected {
+			t.Errorf("%s: expected %v, got %v", s, expected, actual)
+		}
+	}
+}
+
+func TestParseReferenceErrors(t *testing.T) {
+	tests := map[string]string{
+		"ghcr.io/org/configs@" + someDigest: "doesn't start with oci://",
+		"oci://ghcr.io/org/configs:v1":      "isn't pinned",
+		"oci://ghcr.io/org/configs":         "isn't pinned",
+		"oci://ghcr.io/org/configs@sha256:abc": "rather than sha256: " +
+			"and 64 hex digits",
+		"oci://ghcr.io@" + someDigest:      "lacks a registry",
+		"oci://ghcr.io/org/configs:v1/x:y": "has repository 'org/configs:v1/x'",
+		"oci://ghcr.io/org/configs:-v1":    "has tag '-v1', which isn't valid",
+	}
+	for s, expected := range tests {
+		_, err := ParsePinnedReference(s)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: unexpected err: %v", s, err)
+		}
+	}
+}
+
+func TestReferenceString(t *testing.T) {
+	for _, s := range []string{
+		"oci://ghcr.io/org/bases:v1",
+		"oci://ghcr.io/org/bases@" + someDigest,
+		"oci://ghcr.io/org/bases:v1@" + someDigest,
+	} {
+		r, err := ParseReference(s)
+		if err != nil {
+			t.Fatalf("%s: unexpected err: %v", s, err)
+		}
+		if r.String() != s {
+			t.Errorf("expected %s, got %s", s, r.String())
+		}
+	}
+}
+
+func TestReferenceURL(t *testing.T) {
+	tests := map[string]string{
+		"ghcr.io":         "https://ghcr.io/v2/org/configs/manifests/x",
+		"localhost:5000":  "http://localhost:5000/v2/org/configs/manifests/x",
+		"127.0.0.1:40000": "http://127.0.0.1:40000/v2/org/configs/manifests/x",
+	}
+	for registry, expected := range tests {
+		r := Reference{Registry: registry, Repository: "org/configs"}
+		if actual := r.url("manifests/x"); actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	tests := map[string][2]string{
+		"oci://ghcr.io/org/bases:v1":                {"oci://ghcr.io/org/bases:v1", ""},
+		"oci://ghcr.io/org/bases:v1//overlays/prod": {"oci://ghcr.io/org/bases:v1", "overlays/prod"},
+		"oci://localhost:5000/bases//base":          {"oci://localhost:5000/bases", "base"},
+	}
+	for s, expected := range tests {
+		ref, path := SplitPath(s)
+		if ref != expected[0] || path != expected[1] {
+			t.Errorf("%s: expected %v, got %s %s", s, expected, ref, path)
+		}
+	}
+}
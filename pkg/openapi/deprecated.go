@@ -0,0 +1,156 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+)
+
+// DeprecatedAPI is an API version of some kinds that
+// Kubernetes deprecated, and removed or will remove.
+type DeprecatedAPI struct {
+	// Group is the API group, empty for the core group.
+	Group string
+	// Version is the deprecated version of the group.
+	Version string
+	// Kinds are the kinds of the version deprecated.
+	Kinds []string
+	// Deprecated is the Kubernetes version that
+	// deprecated the API, e.g. 1.16.
+	Deprecated string
+	// Removed is the Kubernetes version that
+	// no longer serves the API.
+	Removed string
+	// Replacement is the group and version to use instead,
+	// e.g. apps/v1, if there's one.
+	Replacement string
+}
+
+// deprecatedAPIs are the deprecations, from the
+// Kubernetes release notes, the check knows of.
+var deprecatedAPIs = []DeprecatedAPI{
+	{"extensions", "v1beta1",
+		[]string{"DaemonSet", "Deployment", "ReplicaSet"},
+		"1.9", "1.16", "apps/v1"},
+	{"extensions", "v1beta1",
+		[]string{"NetworkPolicy"},
+		"1.9", "1.16", "networking.k8s.io/v1"},
+	{"extensions", "v1beta1",
+		[]string{"PodSecurityPolicy"},
+		"1.11", "1.16", "policy/v1beta1"},
+	{"extensions", "v1beta1",
+		[]string{"Ingress"},
+		"1.14", "1.22", "networking.k8s.io/v1"},
+	{"apps", "v1beta1",
+		[]string{"Deployment", "StatefulSet", "ReplicaSet"},
+		"1.9", "1.16", "apps/v1"},
+	{"apps", "v1beta2",
+		[]string{"DaemonSet", "Deployment", "StatefulSet", "ReplicaSet"},
+		"1.9", "1.16", "apps/v1"},
+	{"scheduling.k8s.io", "v1beta1",
+		[]string{"PriorityClass"},
+		"1.14", "1.22", "scheduling.k8s.io/v1"},
+	{"admissionregistration.k8s.io", "v1beta1",
+		[]string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"},
+		"1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"apiextensions.k8s.io", "v1beta1",
+		[]string{"CustomResourceDefinition"},
+		"1.16", "1.22", "apiextensions.k8s.io/v1"},
+	{"apiregistration.k8s.io", "v1beta1",
+		[]string{"APIService"},
+		"1.19", "1.22", "apiregistration.k8s.io/v1"},
+	{"rbac.authorization.k8s.io", "v1beta1",
+		[]string{"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"},
+		"1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"certificates.k8s.io", "v1beta1",
+		[]string{"CertificateSigningRequest"},
+		"1.19", "1.22", "certificates.k8s.io/v1"},
+	{"coordination.k8s.io", "v1beta1",
+		[]string{"Lease"},
+		"1.19", "1.22", "coordination.k8s.io/v1"},
+	{"networking.k8s.io", "v1beta1",
+		[]string{"Ingress", "IngressClass"},
+		"1.19", "1.22", "networking.k8s.io/v1"},
+	{"storage.k8s.io", "v1beta1",
+		[]string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"},
+		"1.19", "1.22", "storage.k8s.io/v1"},
+	{"batch", "v1beta1",
+		[]string{"CronJob"},
+		"1.21", "1.25", "batch/v1"},
+	{"discovery.k8s.io", "v1beta1",
+		[]string{"EndpointSlice"},
+		"1.21", "1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io", "v1beta1",
+		[]string{"Event"},
+		"1.19", "1.25", "events.k8s.io/v1"},
+	{"policy", "v1beta1",
+		[]string{"PodDisruptionBudget"},
+		"1.21", "1.25", "policy/v1"},
+	{"policy", "v1beta1",
+		[]string{"PodSecurityPolicy"},
+		"1.21", "1.25", ""},
+	{"node.k8s.io", "v1beta1",
+		[]string{"RuntimeClass"},
+		"1.20", "1.25", "node.k8s.io/v1"},
+	{"autoscaling", "v2beta1",
+		[]string{"HorizontalPodAutoscaler"},
+		"1.22", "1.25", "autoscaling/v2"},
+	{"autoscaling", "v2beta2",
+		[]string{"HorizontalPodAutoscaler"},
+		"1.23", "1.26", "autoscaling/v2"},
+	{"storage.k8s.io", "v1beta1",
+		[]string{"CSIStorageCapacity"},
+		"1.24", "1.27", "storage.k8s.io/v1"},
+}
+
+// Deprecation returns the deprecation of the API of
+// x by the given Kubernetes version, e.g. "1.22", and
+// whether that version removed it, or nil if the
+// version doesn't deprecate the API.
+func Deprecation(version string, x gvk.Gvk) (*DeprecatedAPI, bool, error) {
+	v, err := cleanVersion(version)
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range deprecatedAPIs {
+		d := &deprecatedAPIs[i]
+		if d.Group != x.Group || d.Version != x.Version ||
+			minor(v) < minor(d.Deprecated) || !d.hasKind(x.Kind) {
+			continue
+		}
+		return d, minor(v) >= minor(d.Removed), nil
+	}
+	return nil, false, nil
+}
+
+func (d *DeprecatedAPI) hasKind(kind string) bool {
+	for _, k := range d.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupVersion returns the apiVersion of the API.
+func (d *DeprecatedAPI) GroupVersion() string {
+	if d.Group == "" {
+		return d.Version
+	}
+	return d.Group + "/" + d.Version
+}
+
+// String says when the API was deprecated and removed,
+// and what to use instead.
+func (d *DeprecatedAPI) String() string {
+	s := fmt.Sprintf(
+		"%s is deprecated since kubernetes %s, and removed in %s",
+		d.GroupVersion(), d.Deprecated, d.Removed)
+	if d.Replacement != "" {
+		s += "; use " + d.Replacement
+	}
+	return s
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	. "sigs.k8s.io/kustomize/v3/pkg/openapi"
+)
+
+func TestDeprecation(t *testing.T) {
+	ingress := gvk.Gvk{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}
+	tests := []struct {
+		version string
+		x       gvk.Gvk
+		msg     string
+		removed bool
+	}{
+		{"1.13", ingress, "", false},
+		{"1.14", ingress, "extensions/v1beta1 is deprecated since " +
+			"kubernetes 1.14, and removed in 1.22; use networking.k8s.io/v1", false},
+		{"v1.22", ingress, "extensions/v1beta1 is deprecated since " +
+			"kubernetes 1.14, and removed in 1.22; use networking.k8s.io/v1", true},
+		{"1.16", gvk.Gvk{Group: "extensions", Version: "v1beta1", Kind: "Deployment"},
+			"extensions/v1beta1 is deprecated since " +
+				"kubernetes 1.9, and removed in 1.16; use apps/v1", true},
+		{"1.25", gvk.Gvk{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy"},
+			"policy/v1beta1 is deprecated since " +
+				"kubernetes 1.21, and removed in 1.25", true},
+		{"1.25", gvk.Gvk{Group: "apps", Version: "v1", Kind: "Deployment"}, "", false},
+		{"1.25", gvk.Gvk{Version: "v1", Kind: "Service"}, "", false},
+	}
+	for _, test := range tests {
+		d, removed, err := Deprecation(test.version, test.x)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		msg := ""
+		if d != nil {
+			msg = d.String()
+		}
+		if msg != test.msg || removed != test.removed {
+			t.Errorf("%s in %s: expected %q, %v; got %q, %v",
+				test.x, test.version, test.msg, test.removed, msg, removed)
+		}
+	}
+	if _, _, err := Deprecation("latest", ingress); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/yaml"
+)
+
+// Cluster reaches the API server of a cluster.
+type Cluster struct {
+	Server string
+	client *http.Client
+	// token, or username and password, authenticate requests.
+	token    string
+	username string
+	password string
+}
+
+// DefaultKubeconfig returns the first path in $KUBECONFIG,
+// else ~/.kube/config.
+func DefaultKubeconfig() string {
+	if l := filepath.SplitList(os.Getenv("KUBECONFIG")); len(l) > 0 {
+		return l[0]
+	}
+	return filepath.Join(pgmconfig.HomeDir(), ".kube", "config")
+}
+
+// kubeconfig holds the parts of a kubeconfig
+// file needed to reach a cluster.
+type kubeconfig struct {
+	CurrentContext string `json:"current-context"`
+	Clusters       []struct {
+		Name    string `json:"name"`
+		Cluster struct {
+			Server                   string `json:"server"`
+			CertificateAuthority     string `json:"certificate-authority"`
+			CertificateAuthorityData []byte `json:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `json:"insecure-skip-tls-verify"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+	Contexts []struct {
+		Name    string `json:"name"`
+		Context struct {
+			Cluster string `json:"cluster"`
+			User    string `json:"user"`
+		} `json:"context"`
+	} `json:"contexts"`
+	Users []struct {
+		Name string `json:"name"`
+		User struct {
+			ClientCertificate     string `json:"client-certificate"`
+			ClientCertificateData []byte `json:"client-certificate-data"`
+			ClientKey             string `json:"client-key"`
+			ClientKeyData         []byte `json:"client-key-data"`
+			Token                 string `json:"token"`
+			TokenFile             string `json:"tokenFile"`
+			Username              string `json:"username"`
+			Password              string `json:"password"`
+		} `json:"user"`
+	} `json:"users"`
+}
+
+// ClusterFromKubeconfig returns the cluster of the named
+// context, or of the current context if the name is empty.
+// Exec and auth provider plugins aren't supported.
+func ClusterFromKubeconfig(
+	fSys fs.FileSystem, path, context string) (*Cluster, error) {
+	data, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var kc kubeconfig
+	if err := yaml.Unmarshal(data, &kc); err != nil {
+		return nil, errors.Wrapf(err, "reading kubeconfig %s", path)
+	}
+	if context == "" {
+		context = kc.CurrentContext
+	}
+	if context == "" {
+		return nil, fmt.Errorf("kubeconfig %s has no current context", path)
+	}
+	// Relative paths in a kubeconfig are relative to its directory.
+	read := func(inline []byte, file string) ([]byte, error) {
+		if len(inline) > 0 || file == "" {
+			return inline, nil
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(path), file)
+		}
+		return fSys.ReadFile(file)
+	}
+	for _, ctx := range kc.Contexts {
+		if ctx.Name != context {
+			continue
+		}
+		c := &Cluster{}
+		tlsConfig := &tls.Config{}
+		found := false
+		for _, cl := range kc.Clusters {
+			if cl.Name != ctx.Context.Cluster {
+				continue
+			}
+			found = true
+			c.Server = strings.TrimSuffix(cl.Cluster.Server, "/")
+			tlsConfig.InsecureSkipVerify = cl.Cluster.InsecureSkipTLSVerify
+			ca, err := read(
+				cl.Cluster.CertificateAuthorityData,
+				cl.Cluster.CertificateAuthority)
+			if err != nil {
+				return nil, err
+			}
+			if len(ca) > 0 {
+				tlsConfig.RootCAs = x509.NewCertPool()
+				if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+					return nil, fmt.Errorf(
+						"bad certificate authority for cluster %s", cl.Name)
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf(
+				"context %s names unknown cluster %s",
+				context, ctx.Context.Cluster)
+		}
+		for _, u := range kc.Users {
+			if u.Name != ctx.Context.User {
+				continue
+			}
+			c.username, c.password = u.User.Username, u.User.Password
+			token, err := read([]byte(u.User.Token), u.User.TokenFile)
+			if err != nil {
+				return nil, err
+			}
+			c.token = strings.TrimSpace(string(token))
+			cert, err := read(
+				u.User.ClientCertificateData, u.User.ClientCertificate)
+			if err != nil {
+				return nil, err
+			}
+			key, err := read(u.User.ClientKeyData, u.User.ClientKey)
+			if err != nil {
+				return nil, err
+			}
+			if len(cert) > 0 {
+				pair, err := tls.X509KeyPair(cert, key)
+				if err != nil {
+					return nil, errors.Wrapf(
+						err, "client certificate of user %s", u.Name)
+				}
+				tlsConfig.Certificates = []tls.Certificate{pair}
+			}
+		}
+		c.client = &http.Client{
+			Timeout:   time.Minute,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("kubeconfig %s has no context %s", path, context)
+}
+
+func (c *Cluster) get(path string) ([]byte, error) {
+	req, err := http.NewRequest("GET", c.Server+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", c.Server+path, resp.Status)
+	}
+	return body, nil
+}
+
+// Version returns the Kubernetes version of
+// the cluster, e.g. "1.16".
+func (c *Cluster) Version() (string, error) {
+	body, err := c.get("/version")
+	if err != nil {
+		return "", err
+	}
+	var info struct {
+		Major string `json:"major"`
+		Minor string `json:"minor"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", errors.Wrap(err, "reading server version")
+	}
+	// Some providers report minor versions like "16+".
+	v := info.Major + "." + strings.TrimRight(info.Minor, "+")
+	return cleanVersion(v)
+}
+
+// Fetch downloads the cluster's OpenAPI document, which
+// covers its custom resources as well as builtin types,
+// and saves it in Dir under the given version, returning
+// the path written.
+func (c *Cluster) Fetch(fSys fs.FileSystem, version string) (string, error) {
+	v, err := cleanVersion(version)
+	if err != nil {
+		return "", err
+	}
+	doc, err := c.get("/openapi/v2")
+	if err != nil {
+		return "", err
+	}
+	if _, err := NewSchema(v, doc); err != nil {
+		return "", err
+	}
+	if err := fSys.MkdirAll(Dir()); err != nil {
+		return "", err
+	}
+	path := filepath.Join(Dir(), v+".json")
+	return path, fSys.WriteFile(path, doc)
+}
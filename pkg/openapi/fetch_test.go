@@ -0,0 +1,106 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	. "sigs.k8s.io/kustomize/v3/pkg/openapi"
+)
+
+func fakeAPIServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer sesame" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			switch r.URL.Path {
+			case "/version":
+				w.Write([]byte(`{"major": "1", "minor": "16+"}`))
+			case "/openapi/v2":
+				w.Write([]byte(fooSchema))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+}
+
+func writeKubeconfig(fSys fs.FileSystem, server string) {
+	fSys.WriteFile("/home/kube/config", []byte(`
+apiVersion: v1
+kind: Config
+current-context: dev
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev
+- name: other
+  context:
+    cluster: missing
+    user: dev
+clusters:
+- name: dev
+  cluster:
+    server: `+server+`
+users:
+- name: dev
+  user:
+    tokenFile: token
+`))
+	fSys.WriteFile("/home/kube/token", []byte("sesame\n"))
+}
+
+func TestFetch(t *testing.T) {
+	server := fakeAPIServer()
+	defer server.Close()
+	fSys := fs.MakeFakeFS()
+	writeKubeconfig(fSys, server.URL)
+
+	c, err := ClusterFromKubeconfig(fSys, "/home/kube/config", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, err := c.Version()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != "1.16" {
+		t.Fatalf("unexpected version %s", v)
+	}
+	path, err := c.Fetch(fSys, v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != filepath.Join(Dir(), "1.16.json") {
+		t.Fatalf("unexpected path %s", path)
+	}
+	s, err := ForVersion(fSys, "1.16")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Lookup(foo) == nil {
+		t.Fatalf("expected the fetched schema to have %v", foo)
+	}
+}
+
+func TestClusterFromKubeconfigErrors(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeKubeconfig(fSys, "https://example.com")
+	for context, msg := range map[string]string{
+		"other":   "unknown cluster missing",
+		"nowhere": "has no context nowhere",
+	} {
+		_, err := ClusterFromKubeconfig(fSys, "/home/kube/config", context)
+		if err == nil || !strings.Contains(err.Error(), msg) {
+			t.Fatalf("context %s: unexpected error %v", context, err)
+		}
+	}
+}
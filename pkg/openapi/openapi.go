@@ -0,0 +1,194 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package openapi selects the Kubernetes OpenAPI schema
+// that strategic merge patches consult for merge keys
+// and patch strategies.
+//
+// The API types of one Kubernetes version, BuiltinVersion,
+// are compiled into kustomize.  Schemas for other versions,
+// or for clusters with custom resources, are read from
+// OpenAPI v2 documents, e.g. those kept in Dir.
+package openapi
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/googleapis/gnostic/OpenAPIv2"
+	"github.com/googleapis/gnostic/compiler"
+	"github.com/pkg/errors"
+	"k8s.io/kube-openapi/pkg/util/proto"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+)
+
+// BuiltinVersion is the Kubernetes version whose
+// API types are compiled into kustomize.
+const BuiltinVersion = "1.14"
+
+const gvkExtension = "x-kubernetes-group-version-kind"
+
+// Schema holds the models of an OpenAPI document,
+// indexed by the gvk they describe.
+type Schema struct {
+	version string
+	models  map[gvk.Gvk]proto.Schema
+}
+
+// Builtin returns the schema of the compiled in API types.
+func Builtin() *Schema {
+	return &Schema{version: BuiltinVersion}
+}
+
+// NewSchema parses an OpenAPI v2 document, in JSON or YAML.
+// The version is informational, e.g. "1.16".
+func NewSchema(version string, data []byte) (*Schema, error) {
+	info, err := compiler.ReadInfoFromBytes("", data)
+	if err != nil {
+		return nil, errors.Wrap(err, "openapi document is not yaml or json")
+	}
+	doc, err := openapi_v2.NewDocument(info, compiler.NewContext("$root", nil))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid openapi document")
+	}
+	models, err := proto.NewOpenAPIData(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid openapi document")
+	}
+	s := &Schema{version: version, models: make(map[gvk.Gvk]proto.Schema)}
+	for _, name := range models.ListModels() {
+		m := models.LookupModel(name)
+		for _, x := range gvksOf(m) {
+			s.models[x] = m
+		}
+	}
+	return s, nil
+}
+
+// gvksOf returns the gvks named in a model's
+// x-kubernetes-group-version-kind extension.
+func gvksOf(m proto.Schema) []gvk.Gvk {
+	l, ok := m.GetExtensions()[gvkExtension].([]interface{})
+	if !ok {
+		return nil
+	}
+	var result []gvk.Gvk
+	for _, item := range l {
+		entry, ok := item.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		get := func(k string) string {
+			s, _ := entry[k].(string)
+			return s
+		}
+		result = append(result, gvk.Gvk{
+			Group: get("group"), Version: get("version"), Kind: get("kind")})
+	}
+	return result
+}
+
+// Version returns the Kubernetes version of the schema.
+func (s *Schema) Version() string {
+	if s == nil {
+		return BuiltinVersion
+	}
+	return s.version
+}
+
+// Lookup returns the model of the given gvk, or nil if
+// the schema lacks it, in which case the compiled in
+// API types, if any, should be used.
+func (s *Schema) Lookup(x gvk.Gvk) proto.Schema {
+	if s == nil {
+		return nil
+	}
+	return s.models[x]
+}
+
+var versionPattern = regexp.MustCompile(`^v?(1\.[0-9]+)$`)
+
+// Dir is where schemas fetched from clusters are kept,
+// one file per Kubernetes version, e.g. 1.16.json.
+func Dir() string {
+	return filepath.Join(pgmconfig.ConfigRoot(), "openapi")
+}
+
+// ForVersion returns the schema of the given Kubernetes
+// version, e.g. "1.16", read from Dir.  The builtin
+// version needs no file.
+func ForVersion(fSys fs.FileSystem, version string) (*Schema, error) {
+	v, err := cleanVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(Dir(), v+".json")
+	if !fSys.Exists(path) {
+		if v == BuiltinVersion {
+			return Builtin(), nil
+		}
+		return nil, fmt.Errorf(
+			"no openapi schema for kubernetes %s; save that of a "+
+				"cluster running it with 'kustomize openapi fetch'; "+
+				"available versions: %s",
+			v, strings.Join(Available(fSys), ", "))
+	}
+	data, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := NewSchema(v, data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+	return s, nil
+}
+
+// HasSchema is true if ForVersion finds a schema of the
+// given Kubernetes version.  Checks of deprecated APIs,
+// which need only the version, go ahead without one.
+func HasSchema(fSys fs.FileSystem, version string) (bool, error) {
+	v, err := cleanVersion(version)
+	if err != nil {
+		return false, err
+	}
+	return v == BuiltinVersion ||
+		fSys.Exists(filepath.Join(Dir(), v+".json")), nil
+}
+
+// Available returns the Kubernetes versions ForVersion accepts.
+func Available(fSys fs.FileSystem) []string {
+	result := []string{BuiltinVersion}
+	paths, _ := fSys.Glob(filepath.Join(Dir(), "*.json"))
+	for _, p := range paths {
+		v := strings.TrimSuffix(filepath.Base(p), ".json")
+		if versionPattern.MatchString(v) && v != BuiltinVersion {
+			result = append(result, v)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return minor(result[i]) < minor(result[j])
+	})
+	return result
+}
+
+// cleanVersion accepts versions like 1.16 and v1.16.
+func cleanVersion(version string) (string, error) {
+	m := versionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return "", fmt.Errorf(
+			"kubernetes version '%s' should look like %s", version, BuiltinVersion)
+	}
+	return m[1], nil
+}
+
+func minor(v string) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(v, "1."))
+	return n
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	. "sigs.k8s.io/kustomize/v3/pkg/openapi"
+)
+
+// fooSchema describes a Foo whose spec.bars
+// merge by name.
+const fooSchema = `
+swagger: "2.0"
+info:
+  title: test
+  version: v1.16.0
+paths: {}
+definitions:
+  com.example.v1.Foo:
+    type: object
+    properties:
+      apiVersion:
+        type: string
+      kind:
+        type: string
+      spec:
+        $ref: '#/definitions/com.example.v1.FooSpec'
+    x-kubernetes-group-version-kind:
+    - group: example.com
+      version: v1
+      kind: Foo
+  com.example.v1.FooSpec:
+    type: object
+    properties:
+      bars:
+        type: array
+        items:
+          $ref: '#/definitions/com.example.v1.Bar'
+        x-kubernetes-patch-merge-key: name
+        x-kubernetes-patch-strategy: merge
+  com.example.v1.Bar:
+    type: object
+    properties:
+      name:
+        type: string
+      value:
+        type: string
+`
+
+var foo = gvk.Gvk{Group: "example.com", Version: "v1", Kind: "Foo"}
+
+func TestNewSchema(t *testing.T) {
+	s, err := NewSchema("1.16", []byte(fooSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Version() != "1.16" {
+		t.Fatalf("unexpected version %s", s.Version())
+	}
+	if s.Lookup(foo) == nil {
+		t.Fatalf("expected a model for %v", foo)
+	}
+	if s.Lookup(gvk.Gvk{Version: "v1", Kind: "Pod"}) != nil {
+		t.Fatalf("unexpected model for Pod")
+	}
+	_, err = NewSchema("1.16", []byte("definitions: [oops]"))
+	if err == nil {
+		t.Fatalf("expected error for bad document")
+	}
+}
+
+func TestForVersion(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile(filepath.Join(Dir(), "1.16.json"), []byte(fooSchema))
+	fSys.WriteFile(filepath.Join(Dir(), "1.9.json"), []byte(fooSchema))
+	fSys.WriteFile(filepath.Join(Dir(), "notes.json"), []byte("{}"))
+
+	s, err := ForVersion(fSys, "v1.16")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Version() != "1.16" || s.Lookup(foo) == nil {
+		t.Fatalf("unexpected schema %v", s)
+	}
+
+	s, err = ForVersion(fSys, BuiltinVersion)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Version() != BuiltinVersion || s.Lookup(foo) != nil {
+		t.Fatalf("expected the builtin schema")
+	}
+
+	_, err = ForVersion(fSys, "1.18")
+	if err == nil ||
+		!strings.Contains(err.Error(), "available versions: 1.9, 1.14, 1.16") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = ForVersion(fSys, "latest")
+	if err == nil {
+		t.Fatalf("expected error for bad version")
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	if !reflect.DeepEqual(Available(fSys), []string{BuiltinVersion}) {
+		t.Fatalf("unexpected versions %v", Available(fSys))
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"k8s.io/kube-openapi/pkg/util/proto/validation"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+)
+
+// Validate returns the ways obj, a resource of the given
+// gvk, doesn't match the schema's model of it, e.g. fields
+// unknown to it or of the wrong type, or nil if the schema
+// has no model of it, as the compiled in schema has none.
+func (s *Schema) Validate(x gvk.Gvk, obj map[string]interface{}) []error {
+	m := s.Lookup(x)
+	if m == nil {
+		return nil
+	}
+	return validation.ValidateModel(obj, m, x.Kind)
+}
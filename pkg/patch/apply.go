@@ -0,0 +1,94 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// Type identifies how a patch is applied.
+type Type string
+
+const (
+	// StrategicMerge patches are partial resources.
+	StrategicMerge Type = "StrategicMerge"
+	// Json6902 patches are lists of RFC 6902 operations.
+	Json6902 Type = "Json6902"
+)
+
+// Apply applies a strategic merge or JSON 6902 patch,
+// in YAML or JSON, to a copy of the given resource,
+// returning the copy and the type of patch found.
+//
+// A strategic merge patch is applied whatever its
+// name, namespace and kind, as when a patch is given
+// an explicit target in a kustomization file.
+func Apply(
+	rf *resource.Factory, target *resource.Resource,
+	in []byte) (*resource.Resource, Type, error) {
+	t, err := detectType(in)
+	if err != nil {
+		return nil, "", err
+	}
+	result := target.DeepCopy()
+	switch t {
+	case Json6902:
+		ops, err := DecodeJson6902(in)
+		if err != nil {
+			return nil, "", err
+		}
+		raw, err := result.MarshalJSON()
+		if err != nil {
+			return nil, "", err
+		}
+		modified, err := ApplyJson6902(ops, raw)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "failed to apply json patch")
+		}
+		err = result.UnmarshalJSON(modified)
+		if err != nil {
+			return nil, "", err
+		}
+	default:
+		p, err := rf.FromBytes(in)
+		if err != nil {
+			return nil, "", err
+		}
+		p.SetName(result.GetName())
+		p.SetNamespace(result.GetNamespace())
+		p.SetGvk(result.GetGvk())
+		err = result.Patch(p.Kunstructured)
+		if err != nil {
+			return nil, "", errors.Wrap(
+				err, "failed to apply strategic merge patch")
+		}
+	}
+	return result, t, nil
+}
+
+// detectType reports whether the input is a list
+// (a JSON 6902 patch) or a map (a strategic merge patch).
+func detectType(in []byte) (Type, error) {
+	j, err := toJSON(in)
+	if err != nil {
+		return "", errors.Wrap(err, "patch is neither yaml nor json")
+	}
+	var v interface{}
+	if err := json.Unmarshal(j, &v); err != nil {
+		return "", errors.Wrap(err, "patch is neither yaml nor json")
+	}
+	switch v.(type) {
+	case []interface{}:
+		return Json6902, nil
+	case map[string]interface{}:
+		return StrategicMerge, nil
+	}
+	return "", fmt.Errorf(
+		"unable to get either a Strategic Merge Patch or " +
+			"JSON patch 6902 from input")
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+const deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx
+`
+
+func TestApply(t *testing.T) {
+	rf := resource.NewFactory(kunstruct.NewKunstructuredFactoryImpl())
+	testCases := map[string]struct {
+		patch    string
+		typ      Type
+		expected string
+		err      string
+	}{
+		"strategicMerge": {
+			patch: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: otherName
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx:1.17
+`,
+			typ: StrategicMerge,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - image: nginx:1.17
+        name: app
+`,
+		},
+		"json6902Yaml": {
+			patch: `- op: replace
+  path: /spec/replicas
+  value: 3
+`,
+			typ: Json6902,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: app
+`,
+		},
+		"json6902Json": {
+			patch: `[{"op": "remove", "path": "/spec/template"}]`,
+			typ:   Json6902,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+`,
+		},
+		"badOp": {
+			patch: `[{"op": "remove", "path": "/spec/nope"}]`,
+			err:   "failed to apply json patch",
+		},
+		"scalar": {
+			patch: `hello`,
+			err:   "unable to get either",
+		},
+	}
+	for n, tc := range testCases {
+		target, err := rf.FromBytes([]byte(deployment))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		before, _ := target.AsYAML()
+		result, typ, err := Apply(rf, target, []byte(tc.patch))
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error %q, got %v", n, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		if typ != tc.typ {
+			t.Errorf("%s: expected type %s, got %s", n, tc.typ, typ)
+		}
+		actual, err := result.AsYAML()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		if string(actual) != tc.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", n, tc.expected, actual)
+		}
+		after, _ := target.AsYAML()
+		if string(after) != string(before) {
+			t.Errorf("%s: target was modified:\n%s", n, after)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// DecodeJson6902 decodes a JSON 6902 patch, in YAML or
+// JSON, checking that each operation is one of those of
+// RFC 6902, with the fields it needs and valid pointers.
+func DecodeJson6902(in []byte) (jsonpatch.Patch, error) {
+	j, err := toJSON(in)
+	if err != nil {
+		return nil, err
+	}
+	ops, err := jsonpatch.DecodePatch(j)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		if err := checkOp(op); err != nil {
+			return nil, errors.Wrapf(err, "json patch operation [%d]", i)
+		}
+	}
+	return ops, nil
+}
+
+// checkOp checks the fields an operation needs.
+func checkOp(op jsonpatch.Operation) error {
+	kind := op.Kind()
+	var needsFrom, needsValue bool
+	switch kind {
+	case "add", "replace", "test":
+		needsValue = true
+	case "remove":
+	case "copy", "move":
+		needsFrom = true
+	case "unknown":
+		return fmt.Errorf("missing op")
+	default:
+		return fmt.Errorf(
+			"unknown op '%s'; expected add, remove, replace, "+
+				"move, copy or test", kind)
+	}
+	path, err := op.Path()
+	if err != nil {
+		return fmt.Errorf("%s needs a path", kind)
+	}
+	if err := CheckPointer(path); err != nil {
+		return err
+	}
+	if needsFrom {
+		from, err := op.From()
+		if err != nil {
+			return fmt.Errorf("%s needs a from", kind)
+		}
+		if err := CheckPointer(from); err != nil {
+			return err
+		}
+	}
+	if _, ok := op["value"]; needsValue && !ok {
+		return fmt.Errorf("%s needs a value", kind)
+	}
+	return nil
+}
+
+// toJSON converts YAML to JSON, leaving JSON, which
+// may be indented with tabs unlike YAML, as it is.
+func toJSON(in []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(in, utf8BOM))
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') &&
+		json.Valid(trimmed) {
+		return trimmed, nil
+	}
+	return yaml.YAMLToJSON(in)
+}
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// ApplyJson6902 applies the operations to a JSON document
+// one by one, so that an error names the failing one.
+// A failing test operation fails, with the value found.
+func ApplyJson6902(ops jsonpatch.Patch, doc []byte) ([]byte, error) {
+	var err error
+	for i, op := range ops {
+		var result []byte
+		result, err = jsonpatch.Patch{op}.Apply(doc)
+		if err != nil {
+			return nil, opError(i, op, doc, err)
+		}
+		doc = result
+	}
+	return doc, nil
+}
+
+func opError(
+	i int, op jsonpatch.Operation, doc []byte, err error) error {
+	path, _ := op.Path()
+	desc := fmt.Sprintf("json patch operation [%d] %s %s", i, op.Kind(), path)
+	if from, e := op.From(); e == nil {
+		desc += " from " + from
+	}
+	if errors.Cause(err) != jsonpatch.ErrTestFailed {
+		return errors.Wrap(err, desc)
+	}
+	expected, _ := json.Marshal(rawValue(op["value"]))
+	found, ok := lookup(doc, path)
+	if !ok {
+		return fmt.Errorf(
+			"%s failed: expected %s, found nothing", desc, expected)
+	}
+	actual, _ := json.Marshal(found)
+	return fmt.Errorf(
+		"%s failed: expected %s, found %s", desc, expected, actual)
+}
+
+func rawValue(m *json.RawMessage) interface{} {
+	var v interface{}
+	if m != nil {
+		json.Unmarshal(*m, &v)
+	}
+	return v
+}
+
+// lookup returns the value at a JSON pointer in a document.
+func lookup(doc []byte, pointer string) (interface{}, bool) {
+	var v interface{}
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, false
+	}
+	if pointer == "" {
+		return v, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = UnescapeToken(token)
+		switch typed := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = typed[token]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(typed) {
+				return nil, false
+			}
+			v = typed[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"strings"
+	"testing"
+)
+
+const deploymentJson = `{"metadata":{"name":"app","annotations":{"a.io/b":"x"}},` +
+	`"spec":{"replicas":1,"template":{"spec":{"containers":[{"name":"app","image":"nginx"}]}}}}`
+
+func TestApplyJson6902(t *testing.T) {
+	testCases := map[string]struct {
+		patch    string
+		expected string
+		err      string
+	}{
+		"testPasses": {
+			patch: `
+- op: test
+  path: /spec/replicas
+  value: 1
+- op: replace
+  path: /spec/replicas
+  value: 3
+`,
+			expected: `"replicas":3`,
+		},
+		"testFails": {
+			patch: `
+- op: replace
+  path: /spec/replicas
+  value: 2
+- op: test
+  path: /spec/replicas
+  value: 1
+`,
+			err: "json patch operation [1] test /spec/replicas failed: " +
+				"expected 1, found 2",
+		},
+		"testFindsNothing": {
+			patch: `
+- op: test
+  path: /metadata/annotations/a.io~1c
+  value: x
+`,
+			err: "json patch operation [0] test /metadata/annotations/a.io~1c " +
+				"failed: expected \"x\", found nothing",
+		},
+		"testEscapedPointer": {
+			patch: `
+- op: test
+  path: /metadata/annotations/a.io~1b
+  value: x
+`,
+			expected: `"a.io/b":"x"`,
+		},
+		"copy": {
+			patch: `
+- op: copy
+  from: /spec/template/spec/containers/0
+  path: /spec/template/spec/containers/-
+`,
+			expected: `"containers":[{"name":"app","image":"nginx"},{"name":"app","image":"nginx"}]`,
+		},
+		"move": {
+			patch: `
+- op: move
+  from: /metadata/annotations
+  path: /metadata/labels
+`,
+			expected: `"labels":{"a.io/b":"x"}`,
+		},
+		"moveMissing": {
+			patch: `
+- op: move
+  from: /metadata/labels
+  path: /metadata/annotations
+`,
+			err: "json patch operation [0] move /metadata/annotations " +
+				"from /metadata/labels",
+		},
+	}
+	for n, tc := range testCases {
+		ops, err := DecodeJson6902([]byte(tc.patch))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		actual, err := ApplyJson6902(ops, []byte(deploymentJson))
+		if tc.err != "" {
+			if err == nil {
+				t.Fatalf("%s: expected error", n)
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("%s: expected '%s' in '%v'", n, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		if !strings.Contains(string(actual), tc.expected) {
+			t.Fatalf("%s: expected '%s' in %s", n, tc.expected, actual)
+		}
+	}
+}
+
+func TestDecodeJson6902Formats(t *testing.T) {
+	for n, in := range map[string]string{
+		"json":         `[{"op": "remove", "path": "/a"}]`,
+		"jsonWithTabs": "\n[\n\t{\n\t\t\"op\": \"remove\",\n\t\t\"path\": \"/a\"\n\t}\n]\n",
+		"jsonWithBOM":  "\xef\xbb\xbf[{\"op\": \"remove\", \"path\": \"/a\"}]",
+		"yaml":         "- op: remove\n  path: /a\n",
+	} {
+		ops, err := DecodeJson6902([]byte(in))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		if len(ops) != 1 || ops[0].Kind() != "remove" {
+			t.Fatalf("%s: unexpected ops %v", n, ops)
+		}
+	}
+}
+
+func TestDecodeJson6902Errors(t *testing.T) {
+	testCases := map[string]struct {
+		patch string
+		err   string
+	}{
+		"unknownOp": {
+			patch: `[{"op": "append", "path": "/a", "value": 1}]`,
+			err:   "json patch operation [0]: unknown op 'append'",
+		},
+		"missingOp": {
+			patch: `[{"path": "/a"}]`,
+			err:   "json patch operation [0]: missing op",
+		},
+		"noPath": {
+			patch: `[{"op": "remove", "path": "/a"}, {"op": "remove"}]`,
+			err:   "json patch operation [1]: remove needs a path",
+		},
+		"noFrom": {
+			patch: `[{"op": "copy", "path": "/a"}]`,
+			err:   "json patch operation [0]: copy needs a from",
+		},
+		"unescapedKey": {
+			patch: `[{"op": "remove", "path": "/metadata/annotations/a.io/b"}]`,
+			err: "json patch operation [0]: path '/metadata/annotations/a.io/b' " +
+				"goes into a value of annotations",
+		},
+		"noValue": {
+			patch: `[{"op": "test", "path": "/a"}]`,
+			err:   "json patch operation [0]: test needs a value",
+		},
+	}
+	for n, tc := range testCases {
+		_, err := DecodeJson6902([]byte(tc.patch))
+		if err == nil {
+			t.Fatalf("%s: expected error", n)
+		}
+		if !strings.Contains(err.Error(), tc.err) {
+			t.Fatalf("%s: expected '%s' in '%v'", n, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EscapeToken escapes a key, e.g. an annotation key
+// holding a '/', for use in a JSON pointer (RFC 6901):
+// '~' becomes '~0' and '/' becomes '~1'.
+func EscapeToken(key string) string {
+	key = strings.Replace(key, "~", "~0", -1)
+	return strings.Replace(key, "/", "~1", -1)
+}
+
+// UnescapeToken reverses EscapeToken.
+func UnescapeToken(token string) string {
+	token = strings.Replace(token, "~1", "/", -1)
+	return strings.Replace(token, "~0", "~", -1)
+}
+
+// JoinPointer makes a JSON pointer from keys, escaping them.
+func JoinPointer(keys ...string) string {
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString("/")
+		b.WriteString(EscapeToken(k))
+	}
+	return b.String()
+}
+
+// SplitPointer returns the unescaped keys of a JSON
+// pointer, after checking it with CheckPointer.
+func SplitPointer(pointer string) ([]string, error) {
+	if err := CheckPointer(pointer); err != nil {
+		return nil, err
+	}
+	if pointer == "" {
+		return nil, nil
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = UnescapeToken(t)
+	}
+	return tokens, nil
+}
+
+// stringMaps are fields known to hold maps of strings,
+// given with the field holding them, if it matters.
+var stringMaps = map[string]string{
+	"labels":       "metadata",
+	"annotations":  "metadata",
+	"matchLabels":  "",
+	"nodeSelector": "",
+}
+
+// CheckPointer checks the syntax of a JSON pointer, and that
+// it doesn't go into a value of a map of strings, like
+// annotations, as it would if a '/' in a key isn't escaped.
+// Errors suggest a fix where there is a likely one.
+func CheckPointer(pointer string) error {
+	if pointer == "" {
+		return nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return fmt.Errorf(
+			"path '%s' must start with '/'; did you mean '%s'?",
+			pointer, "/"+strings.Replace(pointer, ".", "/", -1))
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for _, t := range tokens {
+		for i := strings.Index(t, "~"); i >= 0; i = strings.Index(t, "~") {
+			if i+1 == len(t) || (t[i+1] != '0' && t[i+1] != '1') {
+				return fmt.Errorf(
+					"path '%s' has a '~' not followed by 0 or 1; "+
+						"write '~0' for '~' and '~1' for '/'", pointer)
+			}
+			t = t[i+2:]
+		}
+	}
+	for i, t := range tokens {
+		parent, ok := stringMaps[t]
+		if !ok || i+2 >= len(tokens) {
+			continue
+		}
+		if parent != "" && (i == 0 || tokens[i-1] != parent) {
+			continue
+		}
+		key := strings.Join(tokens[i+1:], "/")
+		fixed := "/" + strings.Join(
+			append(tokens[:i+1:i+1], EscapeToken(key)), "/")
+		return fmt.Errorf(
+			"path '%s' goes into a value of %s, which holds strings; "+
+				"did you mean '%s', for key '%s'?",
+			pointer, t, fixed, UnescapeToken(key))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEscapeToken(t *testing.T) {
+	for key, token := range map[string]string{
+		"replicas":                          "replicas",
+		"deployment.kubernetes.io/revision": "deployment.kubernetes.io~1revision",
+		"a~b/c":                             "a~0b~1c",
+	} {
+		if actual := EscapeToken(key); actual != token {
+			t.Errorf("expected %s, got %s", token, actual)
+		}
+		if actual := UnescapeToken(token); actual != key {
+			t.Errorf("expected %s, got %s", key, actual)
+		}
+	}
+}
+
+func TestJoinAndSplitPointer(t *testing.T) {
+	keys := []string{"metadata", "annotations", "a.io/b"}
+	pointer := JoinPointer(keys...)
+	if pointer != "/metadata/annotations/a.io~1b" {
+		t.Fatalf("unexpected pointer %s", pointer)
+	}
+	actual, err := SplitPointer(pointer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, keys) {
+		t.Fatalf("expected %v, got %v", keys, actual)
+	}
+}
+
+func TestCheckPointer(t *testing.T) {
+	testCases := map[string]string{
+		"":                                    "",
+		"/spec/replicas":                      "",
+		"/spec/template/spec/containers/-":    "",
+		"/metadata/annotations/a.io~1b":       "",
+		"/spec/template/metadata/labels/app":  "",
+		"/spec/labels/a/b":                    "",
+		"spec.replicas":                       "did you mean '/spec/replicas'?",
+		"/metadata/annotations/a~b":           "has a '~' not followed by 0 or 1",
+		"/metadata/annotations/a~":            "has a '~' not followed by 0 or 1",
+		"/metadata/annotations/a.io/b":        "did you mean '/metadata/annotations/a.io~1b', for key 'a.io/b'?",
+		"/spec/selector/matchLabels/a.io/b/c": "did you mean '/spec/selector/matchLabels/a.io~1b~1c'",
+	}
+	for pointer, expected := range testCases {
+		err := CheckPointer(pointer)
+		if expected == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", pointer, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected error", pointer)
+			continue
+		}
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected '%s' in '%v'", pointer, expected, err)
+		}
+	}
+}
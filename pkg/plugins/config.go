@@ -6,8 +6,6 @@ package plugins
 import (
 	"fmt"
 	"github.com/spf13/pflag"
-	"path/filepath"
-	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
 )
 
@@ -31,11 +29,7 @@ func ActivePluginConfig() *types.PluginConfig {
 }
 
 func DefaultPluginConfig() *types.PluginConfig {
-	return &types.PluginConfig{
-		Enabled: false,
-		DirectoryPath: filepath.Join(
-			pgmconfig.ConfigRoot(), pgmconfig.PluginRoot),
-	}
+	return types.DisabledPluginConfig()
 }
 
 func NotEnabledErr(name string) error {
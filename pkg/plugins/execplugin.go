@@ -19,13 +19,16 @@ package plugins
 import (
 	"bytes"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"os/exec"
 	"strings"
 	"syscall"
 
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/yaml"
@@ -36,7 +39,8 @@ const (
 )
 
 // ExecPlugin record the name and args of an executable
-// It triggers the executable generator and transformer
+// It triggers the executable generator, transformer, exporter
+// and expectation
 type ExecPlugin struct {
 	// absolute path of the executable
 	path string
@@ -104,7 +108,7 @@ func (p *ExecPlugin) processOptionalArgsFields() error {
 }
 
 func (p *ExecPlugin) writeConfig() (string, error) {
-	tmpFile, err := ioutil.TempFile("", "kust-pipe")
+	tmpFile, err := fs.NewTmpFile("pipe-")
 	if err != nil {
 		return "", err
 	}
@@ -127,7 +131,8 @@ func (p *ExecPlugin) writeConfig() (string, error) {
 func (p *ExecPlugin) Generate() (resmap.ResMap, error) {
 	output, err := p.invokePlugin(nil)
 	if err != nil {
-		return nil, err
+		return nil, kusterr.WithClass(kusterr.ClassPlugin,
+			messages.WithID(messages.PluginFailed, err))
 	}
 	return p.rf.NewResMapFromBytes(output)
 }
@@ -148,19 +153,53 @@ func (p *ExecPlugin) Transform(rm resmap.ResMap) error {
 	// invoke the plugin with resources as the input
 	output, err := p.invokePlugin(resources)
 	if err != nil {
-		return fmt.Errorf("%v %s", err, string(output))
+		return kusterr.WithClass(kusterr.ClassPlugin, messages.WithID(
+			messages.PluginFailed, fmt.Errorf("%v %s", err, string(output))))
 	}
 
 	// update the original ResMap based on the output
 	return p.updateResMapValues(output, rm)
 }
 
+// Export feeds the resources to the plugin,
+// returning its output as is.
+func (p *ExecPlugin) Export(rm resmap.ResMap) ([]byte, error) {
+	resources, err := rm.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+	output, err := p.invokePlugin(resources)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassPlugin, messages.WithID(
+			messages.PluginFailed, fmt.Errorf("%v %s", err, string(output))))
+	}
+	return output, nil
+}
+
+// Check feeds the resources to the plugin, which
+// exits non-zero, writing why, if they fail it.
+func (p *ExecPlugin) Check(rm resmap.ResMap) error {
+	resources, err := rm.AsYaml()
+	if err != nil {
+		return err
+	}
+	output, err := p.invokePlugin(resources)
+	if err != nil {
+		if why := strings.TrimSpace(string(output)); why != "" {
+			return errors.New(why)
+		}
+		return err
+	}
+	return nil
+}
+
 // invokePlugin invokes the plugin
 func (p *ExecPlugin) invokePlugin(input []byte) ([]byte, error) {
 	args, err := p.getArgs()
 	if err != nil {
 		return nil, err
 	}
+	defer fs.RemoveTmp(args[0])
 	cmd := exec.Command(p.path, args...)
 	cmd.Env = p.getEnv()
 	cmd.Stdin = bytes.NewReader(input)
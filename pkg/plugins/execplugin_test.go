@@ -17,6 +17,9 @@ limitations under the License.
 package plugins
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -87,3 +90,57 @@ metadata:
 		t.Fatalf("unexpected arg array: %v", p.args)
 	}
 }
+
+func TestExecPluginCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-exec-check")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	// Fails for resources named root.
+	script := filepath.Join(dir, "NoRoot")
+	err = ioutil.WriteFile(script, []byte(`#!/bin/sh
+if grep -q "name: root" -; then
+  echo "ConfigMap/root is named root"
+  exit 1
+fi
+`), 0755)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	rf := resmap.NewFactory(
+		resource.NewFactory(
+			kunstruct.NewKunstructuredFactoryImpl()), nil)
+	p := NewExecPlugin(script)
+	err = p.Config(loadertest.NewFakeLoader("/app"), rf, []byte(`
+apiVersion: someteam.example.com/v1
+kind: NoRoot
+metadata:
+  name: some-random-name
+`))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	for name, expected := range map[string]string{
+		"web":  "",
+		"root": "ConfigMap/root is named root",
+	} {
+		m, err := rf.NewResMapFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + name))
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		err = p.Check(m)
+		if expected == "" && err != nil {
+			t.Fatalf("%s: unexpected err: %v", name, err)
+		}
+		if expected != "" && (err == nil || err.Error() != expected) {
+			t.Fatalf("%s: expected err %q, got %v", name, expected, err)
+		}
+	}
+}
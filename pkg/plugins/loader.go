@@ -8,10 +8,14 @@ import (
 	"path/filepath"
 	"plugin"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
@@ -26,11 +30,48 @@ type Configurable interface {
 type Loader struct {
 	pc *types.PluginConfig
 	rf *resmap.Factory
+	// Absolute paths of the plugin files loaded.
+	loaded *loadedSet
+}
+
+// loadedSet is the absolute paths of the plugin files
+// loaded by a Loader, and by those derived from it.
+type loadedSet struct {
+	sync.Mutex
+	paths map[string]bool
+}
+
+func (s *loadedSet) add(path string) {
+	s.Lock()
+	defer s.Unlock()
+	s.paths[path] = true
 }
 
 func NewLoader(
 	pc *types.PluginConfig, rf *resmap.Factory) *Loader {
-	return &Loader{pc: pc, rf: rf}
+	return &Loader{
+		pc: pc, rf: rf,
+		loaded: &loadedSet{paths: make(map[string]bool)}}
+}
+
+// WithFactory returns a Loader like this one, configuring
+// plugins with the given factory, e.g. one with the
+// options of a build.  The two share what's Loaded.
+func (l *Loader) WithFactory(rf *resmap.Factory) *Loader {
+	return &Loader{pc: l.pc, rf: rf, loaded: l.loaded}
+}
+
+// Loaded returns the absolute paths, sorted, of the
+// executables and Go plugin objects loaded.
+func (l *Loader) Loaded() []string {
+	l.loaded.Lock()
+	defer l.loaded.Unlock()
+	var result []string
+	for p := range l.loaded.paths {
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result
 }
 
 func (l *Loader) LoadGenerators(
@@ -50,11 +91,13 @@ func (l *Loader) LoadGenerator(
 	ldr ifc.Loader, res *resource.Resource) (transformers.Generator, error) {
 	c, err := l.loadAndConfigurePlugin(ldr, res)
 	if err != nil {
-		return nil, err
+		return nil, kusterr.WithClass(kusterr.ClassPlugin,
+			messages.WithID(messages.PluginLoadFailed, err))
 	}
 	g, ok := c.(transformers.Generator)
 	if !ok {
-		return nil, fmt.Errorf("plugin %s not a generator", res.OrgId())
+		return nil, kusterr.WithClass(kusterr.ClassPlugin,
+			messages.Errorf(messages.PluginWrongKind, res.OrgId(), "a generator"))
 	}
 	return g, nil
 }
@@ -76,15 +119,73 @@ func (l *Loader) LoadTransformer(
 	ldr ifc.Loader, res *resource.Resource) (transformers.Transformer, error) {
 	c, err := l.loadAndConfigurePlugin(ldr, res)
 	if err != nil {
-		return nil, err
+		return nil, kusterr.WithClass(kusterr.ClassPlugin,
+			messages.WithID(messages.PluginLoadFailed, err))
 	}
 	t, ok := c.(transformers.Transformer)
 	if !ok {
-		return nil, fmt.Errorf("plugin %s not a transformer", res.OrgId())
+		return nil, kusterr.WithClass(kusterr.ClassPlugin,
+			messages.Errorf(messages.PluginWrongKind, res.OrgId(), "a transformer"))
 	}
 	return t, nil
 }
 
+func (l *Loader) LoadExporters(
+	ldr ifc.Loader, rm resmap.ResMap) ([]transformers.Exporter, error) {
+	var result []transformers.Exporter
+	for _, res := range rm.Resources() {
+		e, err := l.LoadExporter(ldr, res)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+func (l *Loader) LoadExporter(
+	ldr ifc.Loader, res *resource.Resource) (transformers.Exporter, error) {
+	c, err := l.loadAndConfigurePlugin(ldr, res)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassPlugin,
+			messages.WithID(messages.PluginLoadFailed, err))
+	}
+	e, ok := c.(transformers.Exporter)
+	if !ok {
+		return nil, kusterr.WithClass(kusterr.ClassPlugin,
+			messages.Errorf(messages.PluginWrongKind, res.OrgId(), "an exporter"))
+	}
+	return e, nil
+}
+
+func (l *Loader) LoadExpectations(
+	ldr ifc.Loader, rm resmap.ResMap) ([]transformers.Expectation, error) {
+	var result []transformers.Expectation
+	for _, res := range rm.Resources() {
+		e, err := l.LoadExpectation(ldr, res)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+func (l *Loader) LoadExpectation(
+	ldr ifc.Loader, res *resource.Resource) (transformers.Expectation, error) {
+	c, err := l.loadAndConfigurePlugin(ldr, res)
+	if err != nil {
+		return nil, kusterr.WithClass(kusterr.ClassPlugin,
+			messages.WithID(messages.PluginLoadFailed, err))
+	}
+	e, ok := c.(transformers.Expectation)
+	if !ok {
+		return nil, kusterr.WithClass(kusterr.ClassPlugin,
+			messages.Errorf(messages.PluginWrongKind, res.OrgId(), "an expectation"))
+	}
+	return e, nil
+}
+
 func relativePluginPath(id resid.ResId) string {
 	return filepath.Join(
 		id.Group,
@@ -126,6 +227,7 @@ func (l *Loader) loadAndConfigurePlugin(
 func (l *Loader) loadPlugin(resId resid.ResId) (Configurable, error) {
 	p := NewExecPlugin(l.absolutePluginPath(resId))
 	if p.isAvailable() {
+		l.loaded.add(p.path)
 		return p, nil
 	}
 	c, err := l.loadGoPlugin(resId)
@@ -140,15 +242,22 @@ func (l *Loader) loadPlugin(resId resid.ResId) (Configurable, error) {
 // Each test makes its own loader, and tries to load its own plugins,
 // but the loaded .so files are in shared memory, so one will get
 // "this plugin already loaded" errors if the registry is maintained
-// as a Loader instance variable.  So make it a package variable.
-var registry = make(map[string]Configurable)
+// as a Loader instance variable.  So make it a package variable,
+// guarded by registryMu, as concurrent builds share it.
+var (
+	registry   = make(map[string]Configurable)
+	registryMu sync.Mutex
+)
 
 func (l *Loader) loadGoPlugin(id resid.ResId) (Configurable, error) {
 	regId := relativePluginPath(id)
+	absPath := l.absolutePluginPath(id)
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	if c, ok := registry[regId]; ok {
+		l.loaded.add(absPath + ".so")
 		return copyPlugin(c), nil
 	}
-	absPath := l.absolutePluginPath(id)
 	p, err := plugin.Open(absPath + ".so")
 	if err != nil {
 		return nil, errors.Wrapf(err, "plugin %s fails to load", absPath)
@@ -164,6 +273,7 @@ func (l *Loader) loadGoPlugin(id resid.ResId) (Configurable, error) {
 		return nil, fmt.Errorf("plugin %s not configurable", regId)
 	}
 	registry[regId] = c
+	l.loaded.add(absPath + ".so")
 	return copyPlugin(c), nil
 }
 
@@ -0,0 +1,257 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package render is a stable API for dev tools, like
+// Skaffold and Tilt, that build kustomizations and need
+// to set freshly built image tags or their own labels
+// without editing the user's kustomization files.
+package render
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/image"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/plugin/builtin"
+	"sigs.k8s.io/yaml"
+)
+
+// Profile holds overrides applied, in memory, to
+// the output of a kustomization.
+type Profile struct {
+	// Images replace images as the images field of
+	// a kustomization would, after the build, so
+	// Name must match the image name in the output.
+	Images []image.Image
+
+	// Labels are added to the metadata of all resources
+	// and of pod templates, but unlike commonLabels
+	// not to selectors, which are often immutable.
+	Labels map[string]string
+
+	// Annotations are added to all resources,
+	// as by commonAnnotations.
+	Annotations map[string]string
+}
+
+// Renderer builds kustomizations.
+type Renderer struct {
+	fSys fs.FileSystem
+	v    ifc.Validator
+	rf   *resmap.Factory
+	pf   resmap.PatchFactory
+	pl   *plugins.Loader
+	lr   loader.LoadRestrictorFunc
+	lo   loader.Options
+	t    loader.Tracer
+}
+
+// NewRenderer returns a Renderer reading from the given
+// file system, with the defaults of 'kustomize build':
+// files must be under their kustomization's root, and
+// plugins are disabled.
+func NewRenderer(fSys fs.FileSystem) *Renderer {
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(
+		resource.NewFactory(kunstruct.NewKunstructuredFactoryImpl()), pf)
+	return &Renderer{
+		fSys: fSys,
+		v:    validator.NewKustValidator(),
+		rf:   rf,
+		pf:   pf,
+		pl:   plugins.NewLoader(plugins.DefaultPluginConfig(), rf),
+		lr:   loader.RestrictionRootOnly,
+		lo:   loader.DefaultOptions(),
+	}
+}
+
+// NewRendererWith returns a Renderer like NewRenderer's,
+// but validating with v, making resources and patches
+// with rf and pf, and loading plugins with pl, as the
+// kustomize commands do.
+func NewRendererWith(
+	fSys fs.FileSystem, v ifc.Validator, rf *resmap.Factory,
+	pf resmap.PatchFactory, pl *plugins.Loader) *Renderer {
+	r := NewRenderer(fSys)
+	r.v, r.rf, r.pf, r.pl = v, rf, pf, pl
+	return r
+}
+
+// WithLoadRestrictor returns a Renderer like this one
+// restricting the files kustomizations read with lr.
+func (r *Renderer) WithLoadRestrictor(lr loader.LoadRestrictorFunc) *Renderer {
+	c := *r
+	c.lr = lr
+	return &c
+}
+
+// WithPluginConfig returns a Renderer like this one
+// loading plugins as pc says.
+func (r *Renderer) WithPluginConfig(pc *types.PluginConfig) *Renderer {
+	c := *r
+	c.pl = plugins.NewLoader(pc, r.rf)
+	return &c
+}
+
+// WithLoaderOptions returns a Renderer like this one
+// loading files and remote bases as o says.
+func (r *Renderer) WithLoaderOptions(o loader.Options) *Renderer {
+	c := *r
+	c.lo = o
+	return &c
+}
+
+// WithTracer returns a Renderer like this one
+// reporting what its loaders read to t.
+func (r *Renderer) WithTracer(t loader.Tracer) *Renderer {
+	c := *r
+	c.t = t
+	return &c
+}
+
+// WithResourceOptions returns a Renderer like this one
+// making resources, its plugins' too, as o says.
+func (r *Renderer) WithResourceOptions(o resource.Options) *Renderer {
+	c := *r
+	c.rf = r.rf.WithOptions(o)
+	c.pl = r.pl.WithFactory(c.rf)
+	return &c
+}
+
+// Target loads the kustomization at the given path,
+// without building it, for callers that need more than
+// its resources.  Call the returned func when done
+// with the target, to remove what its loader made,
+// e.g. clones of remote bases.
+func (r *Renderer) Target(
+	path string) (*target.KustTarget, func() error, error) {
+	kt, ldr, err := r.load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return kt, ldr.Cleanup, nil
+}
+
+func (r *Renderer) load(path string) (*target.KustTarget, ifc.Loader, error) {
+	ldr, err := loader.NewLoaderWithOptions(
+		r.lr, r.v, path, r.fSys, r.t, r.lo)
+	if err != nil {
+		return nil, nil, err
+	}
+	kt, err := target.NewKustTarget(ldr, r.rf, r.pf, r.pl)
+	if err != nil {
+		ldr.Cleanup()
+		return nil, nil, err
+	}
+	return kt, ldr, nil
+}
+
+// Render builds the kustomization at the given path,
+// then applies the profile, if any.
+func (r *Renderer) Render(path string, p *Profile) (resmap.ResMap, error) {
+	kt, ldr, err := r.load(path)
+	if err != nil {
+		return nil, err
+	}
+	defer ldr.Cleanup()
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return m, nil
+	}
+	ts, err := r.profileTransformers(ldr, p)
+	if err != nil {
+		return nil, err
+	}
+	err = transformers.NewMultiTransformer(ts).Transform(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RenderYaml is Render with output ordered
+// and formatted as by 'kustomize build'.
+func (r *Renderer) RenderYaml(path string, p *Profile) ([]byte, error) {
+	m, err := r.Render(path, p)
+	if err != nil {
+		return nil, err
+	}
+	err = builtin.NewLegacyOrderTransformerPlugin().Transform(m)
+	if err != nil {
+		return nil, err
+	}
+	return m.AsYaml()
+}
+
+func (r *Renderer) profileTransformers(
+	ldr ifc.Loader, p *Profile) ([]transformers.Transformer, error) {
+	tc := config.MakeDefaultConfig()
+	var result []transformers.Transformer
+	for _, img := range p.Images {
+		if img.Name == "" {
+			return nil, errors.New("profile image must have a name")
+		}
+		c := struct {
+			ImageTag   image.Image
+			FieldSpecs []config.FieldSpec
+		}{ImageTag: img, FieldSpecs: tc.Images}
+		t := builtin.NewImageTagTransformerPlugin()
+		if err := r.configure(ldr, t, c); err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	if len(p.Labels) > 0 {
+		c := struct {
+			Labels     map[string]string
+			FieldSpecs []config.FieldSpec
+		}{Labels: p.Labels}
+		for _, spec := range tc.CommonLabels {
+			if strings.HasSuffix(spec.Path, "metadata/labels") {
+				c.FieldSpecs = append(c.FieldSpecs, spec)
+			}
+		}
+		t := builtin.NewLabelTransformerPlugin()
+		if err := r.configure(ldr, t, c); err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	if len(p.Annotations) > 0 {
+		c := struct {
+			Annotations map[string]string
+			FieldSpecs  []config.FieldSpec
+		}{Annotations: p.Annotations, FieldSpecs: tc.CommonAnnotations}
+		t := builtin.NewAnnotationsTransformerPlugin()
+		if err := r.configure(ldr, t, c); err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func (r *Renderer) configure(
+	ldr ifc.Loader, p plugins.Configurable, c interface{}) error {
+	y, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return p.Config(ldr, r.rf, y)
+}
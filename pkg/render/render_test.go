@@ -0,0 +1,154 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package render_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/image"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	. "sigs.k8s.io/kustomize/v3/pkg/render"
+)
+
+func writeApp(fSys fs.FileSystem) {
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+namePrefix: dev-
+resources:
+- deployment.yaml
+images:
+- name: web
+  newTag: stable
+`))
+	fSys.WriteFile("/app/deployment.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: web
+`))
+}
+
+func TestRenderWithoutProfile(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeApp(fSys)
+	actual, err := NewRenderer(fSys).RenderYaml("/app", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dev-web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - image: web:stable
+        name: web
+`
+	if string(actual) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestRenderWithProfile(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeApp(fSys)
+	before, _ := fSys.ReadFile("/app/kustomization.yaml")
+	actual, err := NewRenderer(fSys).RenderYaml("/app", &Profile{
+		Images: []image.Image{
+			{Name: "web", NewName: "localhost:5000/web", NewTag: "abc123"}},
+		Labels:      map[string]string{"skaffold.dev/run-id": "42"},
+		Annotations: map[string]string{"tilt.dev/managed": "true"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    tilt.dev/managed: "true"
+  labels:
+    skaffold.dev/run-id: "42"
+  name: dev-web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      annotations:
+        tilt.dev/managed: "true"
+      labels:
+        app: web
+        skaffold.dev/run-id: "42"
+    spec:
+      containers:
+      - image: localhost:5000/web:abc123
+        name: web
+`
+	if string(actual) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+	after, _ := fSys.ReadFile("/app/kustomization.yaml")
+	if string(before) != string(after) {
+		t.Fatalf("kustomization file changed")
+	}
+}
+
+func TestRenderProfileImageWithoutName(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeApp(fSys)
+	_, err := NewRenderer(fSys).Render(
+		"/app", &Profile{Images: []image.Image{{NewTag: "v1"}}})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestRenderWithTracer(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeApp(fSys)
+	rec := loader.NewDepRecorder()
+	_, err := NewRenderer(fSys).WithTracer(rec).Render("/app", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files := rec.Dependencies().Files
+	if len(files) != 2 || files[0] != "/app/deployment.yaml" {
+		t.Fatalf("unexpected files: %v", files)
+	}
+}
+
+func TestTarget(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	writeApp(fSys)
+	kt, cleanup, err := NewRenderer(fSys).Target("/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cleanup()
+	if kt.Root() != "/app" {
+		t.Fatalf("unexpected root: %s", kt.Root())
+	}
+}
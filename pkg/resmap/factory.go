@@ -6,6 +6,7 @@ package resmap
 import (
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/expansion"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
@@ -13,13 +14,14 @@ import (
 
 // Factory makes instances of ResMap.
 type Factory struct {
-	resF *resource.Factory
-	tf   PatchFactory
+	resF     *resource.Factory
+	tf       PatchFactory
+	maxProcs int
 }
 
 // NewFactory returns a new resmap.Factory.
 func NewFactory(rf *resource.Factory, tf PatchFactory) *Factory {
-	return &Factory{resF: rf, tf: tf}
+	return &Factory{resF: rf, tf: tf, maxProcs: 1}
 }
 
 // RF returns a resource.Factory.
@@ -27,6 +29,35 @@ func (rmF *Factory) RF() *resource.Factory {
 	return rmF.resF
 }
 
+// WithOptions returns a Factory like this one, whose
+// resources follow the given options.
+func (rmF *Factory) WithOptions(o resource.Options) *Factory {
+	f := *rmF
+	f.resF = rmF.resF.WithOptions(o)
+	return &f
+}
+
+// WithMaxProcs returns a Factory like this one, whose
+// MaxProcs is n, or 1 if n is less.
+func (rmF *Factory) WithMaxProcs(n int) *Factory {
+	if n < 1 {
+		n = 1
+	}
+	f := *rmF
+	f.maxProcs = n
+	return &f
+}
+
+// MaxProcs is how many goroutines transformers that
+// change resources independently of each other may
+// spread their work over; 1 runs serially.
+func (rmF *Factory) MaxProcs() int {
+	if rmF.maxProcs < 1 {
+		return 1
+	}
+	return rmF.maxProcs
+}
+
 func New() ResMap {
 	return newOne()
 }
@@ -47,9 +78,13 @@ func (rmF *Factory) FromFile(
 	if err != nil {
 		return nil, err
 	}
+	if err := rmF.resF.CheckDuplicateKeys(path, content); err != nil {
+		return nil, err
+	}
 	m, err := rmF.NewResMapFromBytes(content)
 	if err != nil {
-		return nil, kusterr.Handler(err, path)
+		return nil, kusterr.WithClass(
+			kusterr.ClassLoad, kusterr.Handler(err, path))
 	}
 	return m, nil
 }
@@ -63,6 +98,36 @@ func (rmF *Factory) NewResMapFromBytes(b []byte) (ResMap, error) {
 	return newResMapFromResourceSlice(resources)
 }
 
+// FromTemplateArgs returns the resources in the
+// given templates, with their placeholders replaced.
+func (rmF *Factory) FromTemplateArgs(
+	ldr ifc.Loader, args types.TemplateArgs) (ResMap, error) {
+	result := New()
+	for _, path := range args.Files {
+		content, err := ldr.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := rmF.resF.CheckDuplicateKeys(path, content); err != nil {
+			return nil, err
+		}
+		s, err := expansion.ExpandTemplate(string(content), args.Params)
+		if err != nil {
+			return nil, errors.Wrapf(err, "template %s", path)
+		}
+		m, err := rmF.NewResMapFromBytes([]byte(s))
+		if err != nil {
+			return nil, kusterr.WithClass(
+				kusterr.ClassLoad, kusterr.Handler(err, path))
+		}
+		err = result.AppendAll(m)
+		if err != nil {
+			return nil, errors.Wrapf(err, "template %s", path)
+		}
+	}
+	return result, nil
+}
+
 // NewResMapFromConfigMapArgs returns a Resource slice given
 // a configmap metadata slice from kustomization file.
 func (rmF *Factory) NewResMapFromConfigMapArgs(
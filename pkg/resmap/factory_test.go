@@ -5,7 +5,6 @@ package resmap_test
 
 import (
 	"encoding/base64"
-	"reflect"
 	"testing"
 
 	"sigs.k8s.io/kustomize/v3/internal/loadertest"
@@ -102,8 +101,8 @@ metadata:
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !reflect.DeepEqual(m, expected) {
-		t.Fatalf("%#v doesn't match expected %#v", m, expected)
+	if err := expected.ErrorIfNotEqualLists(m); err != nil {
+		t.Fatalf("actual doesn't match expected: %v", err)
 	}
 }
 
@@ -0,0 +1,60 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resmap
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// GetPatchTargets returns the resources targeted by a patch
+// with the given id.  An id with a version must match one
+// resource exactly, as for GetById.  An id without a version,
+// and perhaps without a group, matches its kind in any version,
+// and group, so that a patch survives apiVersion bumps in
+// bases; if that matches resources of more than one version,
+// all are returned, with a warning.
+func GetPatchTargets(m ResMap, id resid.ResId) ([]*resource.Resource, error) {
+	if id.Version != "" {
+		r, err := m.GetById(id)
+		if err != nil {
+			return nil, err
+		}
+		return []*resource.Resource{r}, nil
+	}
+	matcher := func(x resid.ResId) bool {
+		return x.Name == id.Name && x.IsNsEquals(id) &&
+			x.Gvk.IsSelected(&id.Gvk)
+	}
+	result := m.GetMatchingResourcesByOriginalId(matcher)
+	if len(result) == 0 {
+		result = m.GetMatchingResourcesByCurrentId(matcher)
+	}
+	switch len(result) {
+	case 0:
+		return nil, fmt.Errorf(
+			"no matches for versionless id %s; "+
+				"failed to find target for patch", id)
+	case 1:
+		return result, nil
+	}
+	var versions []string
+	for _, r := range result {
+		x := r.GetGvk()
+		if x.Group == "" {
+			versions = append(versions, x.Version)
+		} else {
+			versions = append(versions, x.Group+"/"+x.Version)
+		}
+	}
+	log.Printf(
+		"warning: patch target %s '%s' matches %d resources, "+
+			"of apiVersions %s; patching all of them",
+		id.Kind, id.Name, len(result), strings.Join(versions, ", "))
+	return result, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resmap_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+func TestGetPatchTargets(t *testing.T) {
+	m, err := rmF.NewResMapFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: web
+---
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testCases := map[string]struct {
+		gvk   gvk.Gvk
+		count int
+		err   string
+	}{
+		"exact": {
+			gvk:   gvk.Gvk{Group: "apps", Version: "v1", Kind: "Deployment"},
+			count: 1,
+		},
+		"exactWrongVersion": {
+			gvk: gvk.Gvk{Group: "apps", Version: "v1beta1", Kind: "Deployment"},
+			err: "failed to find unique target for patch",
+		},
+		"noVersion": {
+			gvk:   gvk.Gvk{Group: "apps", Kind: "Deployment"},
+			count: 1,
+		},
+		"kindOnly": {
+			gvk:   gvk.Gvk{Kind: "Deployment"},
+			count: 1,
+		},
+		"kindOnlyManyVersions": {
+			gvk:   gvk.Gvk{Kind: "Ingress"},
+			count: 2,
+		},
+		"kindOnlyNoMatch": {
+			gvk: gvk.Gvk{Kind: "Service"},
+			err: "no matches for versionless id",
+		},
+	}
+	for n, tc := range testCases {
+		result, err := resmap.GetPatchTargets(m, resid.NewResId(tc.gvk, "web"))
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error '%s', got %v", n, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		if len(result) != tc.count {
+			t.Errorf("%s: expected %d targets, got %d", n, tc.count, len(result))
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resmap
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// The functions here let Go programs query a ResMap
+// without walking map[string]interface{} by hand.
+//
+// Field paths are dot separated, with list indices in
+// brackets, e.g.
+//
+//   spec.template.spec.containers[0].image
+//
+// A path element of "*", or an index of [*], matches
+// every map entry or list item at that point, e.g.
+//
+//   spec.template.spec.containers[*].image
+//   metadata.labels.*
+
+// FieldValue is a value found in a resource.
+type FieldValue struct {
+	// Id is the CurId of the resource holding the value.
+	Id resid.ResId
+	// Path is the path to the value, without wildcards.
+	Path string
+	// Value is a string, bool, number, map or slice.
+	Value interface{}
+}
+
+// GetBy returns the one resource the selector matches,
+// failing on no match or multiple matches.
+func GetBy(m ResMap, s types.Selector) (*resource.Resource, error) {
+	result, err := m.Select(s)
+	if err != nil {
+		return nil, err
+	}
+	switch len(result) {
+	case 0:
+		return nil, fmt.Errorf("no resource matches selector %v", s)
+	case 1:
+		return result[0], nil
+	}
+	return nil, fmt.Errorf(
+		"%d resources match selector %v", len(result), s)
+}
+
+// Count returns the number of resources the selector matches.
+func Count(m ResMap, s types.Selector) (int, error) {
+	result, err := m.Select(s)
+	return len(result), err
+}
+
+// ReadField returns the values at the given path in
+// the resources the selector matches, in resource
+// order.  Resources lacking the field contribute nothing.
+func ReadField(
+	m ResMap, s types.Selector, path string) ([]FieldValue, error) {
+	parts, err := parseFieldPath(path)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := m.Select(s)
+	if err != nil {
+		return nil, err
+	}
+	var result []FieldValue
+	for _, r := range resources {
+		id := r.CurId()
+		walkFieldPath(r.Map(), parts, "", func(p string, v interface{}) {
+			result = append(result, FieldValue{Id: id, Path: p, Value: v})
+		})
+	}
+	return result, nil
+}
+
+// GroupBy groups the resources the selector matches by
+// the value at the given path, which mustn't contain
+// wildcards.  Resources lacking the field are grouped
+// under the empty string.
+func GroupBy(
+	m ResMap, s types.Selector,
+	path string) (map[string][]*resource.Resource, error) {
+	parts, err := parseFieldPath(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range parts {
+		if p.wildcard {
+			return nil, fmt.Errorf(
+				"cannot group by wildcard path '%s'", path)
+		}
+	}
+	resources, err := m.Select(s)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]*resource.Resource)
+	for _, r := range resources {
+		key := ""
+		walkFieldPath(r.Map(), parts, "", func(_ string, v interface{}) {
+			key = fmt.Sprintf("%v", v)
+		})
+		result[key] = append(result[key], r)
+	}
+	return result, nil
+}
+
+type pathPart struct {
+	// key is a map key, used if index < 0.
+	key      string
+	index    int
+	wildcard bool
+}
+
+func parseFieldPath(path string) ([]pathPart, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty field path")
+	}
+	var result []pathPart
+	for _, elem := range strings.Split(path, ".") {
+		key := elem
+		var indices []string
+		if i := strings.Index(elem, "["); i >= 0 {
+			if !strings.HasSuffix(elem, "]") {
+				return nil, fmt.Errorf("bad field path '%s'", path)
+			}
+			key = elem[:i]
+			indices = strings.Split(elem[i+1:len(elem)-1], "][")
+		}
+		if key == "" {
+			return nil, fmt.Errorf("bad field path '%s'", path)
+		}
+		result = append(result,
+			pathPart{key: key, index: -1, wildcard: key == "*"})
+		for _, s := range indices {
+			if s == "*" {
+				result = append(result, pathPart{index: 0, wildcard: true})
+				continue
+			}
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf(
+					"bad index '%s' in field path '%s'", s, path)
+			}
+			result = append(result, pathPart{index: n})
+		}
+	}
+	return result, nil
+}
+
+// walkFieldPath calls f with each value, and its
+// concrete path, that parts matches in obj.
+func walkFieldPath(
+	obj interface{}, parts []pathPart,
+	prefix string, f func(string, interface{})) {
+	if len(parts) == 0 {
+		f(prefix, obj)
+		return
+	}
+	p := parts[0]
+	if p.index < 0 {
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			return
+		}
+		join := func(k string) string {
+			if prefix == "" {
+				return k
+			}
+			return prefix + "." + k
+		}
+		if !p.wildcard {
+			if v, ok := m[p.key]; ok {
+				walkFieldPath(v, parts[1:], join(p.key), f)
+			}
+			return
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walkFieldPath(m[k], parts[1:], join(k), f)
+		}
+		return
+	}
+	l, ok := obj.([]interface{})
+	if !ok {
+		return
+	}
+	at := func(i int) string {
+		return prefix + "[" + strconv.Itoa(i) + "]"
+	}
+	if !p.wildcard {
+		if p.index < len(l) {
+			walkFieldPath(l[p.index], parts[1:], at(p.index), f)
+		}
+		return
+	}
+	for i, v := range l {
+		walkFieldPath(v, parts[1:], at(i), f)
+	}
+}
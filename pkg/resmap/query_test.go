@@ -0,0 +1,160 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resmap_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+func setupRMForQuery(t *testing.T) resmap.ResMap {
+	result, err := rmF.NewResMapFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    tier: front
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.17
+      - name: sidecar
+        image: envoy:1.12
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: db
+  labels:
+    tier: back
+spec:
+  template:
+    spec:
+      containers:
+      - name: postgres
+        image: postgres:11
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  labels:
+    tier: front
+`))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return result
+}
+
+func TestQueryCountAndGetBy(t *testing.T) {
+	m := setupRMForQuery(t)
+	n, err := resmap.Count(m, types.Selector{LabelSelector: "tier=front"})
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2, got %d, %v", n, err)
+	}
+	r, err := resmap.GetBy(m, types.Selector{
+		Gvk: gvk.Gvk{Kind: "Deployment"}, Name: "web"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if r.GetName() != "web" || r.GetKind() != "Deployment" {
+		t.Fatalf("unexpected resource %v", r)
+	}
+	_, err = resmap.GetBy(m, types.Selector{Name: "web"})
+	if err == nil {
+		t.Fatalf("expected error on multiple matches")
+	}
+	_, err = resmap.GetBy(m, types.Selector{Name: "cache"})
+	if err == nil {
+		t.Fatalf("expected error on no match")
+	}
+}
+
+func TestQueryReadField(t *testing.T) {
+	m := setupRMForQuery(t)
+	testCases := map[string]struct {
+		sel      types.Selector
+		path     string
+		expected []string
+	}{
+		"wildcardIndex": {
+			sel:  types.Selector{Gvk: gvk.Gvk{Kind: "Deployment"}},
+			path: "spec.template.spec.containers[*].image",
+			expected: []string{
+				"web spec.template.spec.containers[0].image nginx:1.17",
+				"web spec.template.spec.containers[1].image envoy:1.12",
+				"db spec.template.spec.containers[0].image postgres:11",
+			},
+		},
+		"index": {
+			sel:  types.Selector{Name: "web"},
+			path: "spec.template.spec.containers[1].name",
+			expected: []string{
+				"web spec.template.spec.containers[1].name sidecar",
+			},
+		},
+		"wildcardKey": {
+			sel:  types.Selector{Name: "db"},
+			path: "metadata.*",
+			expected: []string{
+				"db metadata.labels map[tier:back]",
+				"db metadata.name db",
+			},
+		},
+		"missing": {
+			sel:  types.Selector{},
+			path: "spec.replicas",
+		},
+	}
+	for n, tc := range testCases {
+		values, err := resmap.ReadField(m, tc.sel, tc.path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", n, err)
+		}
+		var actual []string
+		for _, v := range values {
+			actual = append(actual,
+				fmt.Sprintf("%s %s %v", v.Id.Name, v.Path, v.Value))
+		}
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", n, tc.expected, actual)
+		}
+	}
+	for _, bad := range []string{"", "a..b", "a[x]", "a[0"} {
+		if _, err := resmap.ReadField(m, types.Selector{}, bad); err == nil {
+			t.Errorf("expected error for path %q", bad)
+		}
+	}
+}
+
+func TestQueryGroupBy(t *testing.T) {
+	m := setupRMForQuery(t)
+	groups, err := resmap.GroupBy(m, types.Selector{}, "metadata.labels.tier")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(groups["front"]) != 2 || len(groups["back"]) != 1 {
+		t.Fatalf("unexpected groups %v", groups)
+	}
+	groups, err = resmap.GroupBy(m, types.Selector{}, "kind")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(groups["Deployment"]) != 2 || len(groups["Service"]) != 1 {
+		t.Fatalf("unexpected groups %v", groups)
+	}
+	_, err = resmap.GroupBy(m, types.Selector{}, "metadata.labels.*")
+	if err == nil {
+		t.Fatalf("expected error for wildcard path")
+	}
+}
@@ -9,9 +9,12 @@ import (
 	"bytes"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
 
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
@@ -291,7 +294,7 @@ func (m *resWrangler) Debug(title string) {
 			fmt.Println("---")
 		}
 		fmt.Printf("# %d  %s\n", i, r.OrgId())
-		blob, err := yaml.Marshal(r.Map())
+		blob, err := yaml.Marshal(r.Redacted().Map())
 		if err != nil {
 			panic(err)
 		}
@@ -376,9 +379,9 @@ func (m *resWrangler) GetById(
 	if err2 == nil {
 		return match, nil
 	}
-	return nil, fmt.Errorf(
-		"%s; %s; failed to find unique target for patch %s",
-		err1.Error(), err2.Error(), id.GvknString())
+	return nil, kusterr.WithClass(kusterr.ClassValidation, messages.Errorf(
+		messages.PatchTargetNotFound,
+		err1.Error(), err2.Error(), id.GvknString()))
 }
 
 type resFinder func(IdMatcher) []*resource.Resource
@@ -444,7 +447,7 @@ func (m *resWrangler) AsYaml() ([]byte, error) {
 	var b []byte
 	buf := bytes.NewBuffer(b)
 	for _, res := range m.Resources() {
-		out, err := yaml.Marshal(res.Map())
+		out, err := res.AsYAML()
 		if err != nil {
 			return nil, err
 		}
@@ -658,11 +661,44 @@ func anchorRegex(pattern string) string {
 	return "^" + pattern + "$"
 }
 
+// selectorRegex compiles the name or namespace of a
+// Selector, an anchored regular expression, or, if it
+// has a * and no other regular expression syntax, a
+// wildcard pattern, e.g. app-* or app.example-*.
+func selectorRegex(pattern string) (*regexp.Regexp, error) {
+	if isWildcard(pattern) {
+		parts := strings.Split(pattern, "*")
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		pattern = strings.Join(parts, ".*")
+	}
+	r, err := regexp.Compile(anchorRegex(pattern))
+	if err != nil {
+		return nil, errors.Wrapf(err, "selector '%s'", pattern)
+	}
+	return r, nil
+}
+
+// isWildcard is true if a * of the pattern
+// can't be part of a regular expression.
+func isWildcard(pattern string) bool {
+	return strings.Contains(pattern, "*") &&
+		!strings.Contains(pattern, ".*") &&
+		!strings.ContainsAny(pattern, `+?()[]{}|^$\`)
+}
+
 // Select returns a list of resources that
 // are selected by a Selector
 func (m *resWrangler) Select(s types.Selector) ([]*resource.Resource, error) {
-	ns := regexp.MustCompile(anchorRegex(s.Namespace))
-	nm := regexp.MustCompile(anchorRegex(s.Name))
+	ns, err := selectorRegex(s.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	nm, err := selectorRegex(s.Name)
+	if err != nil {
+		return nil, err
+	}
 	var result []*resource.Resource
 	for _, r := range m.Resources() {
 		curId := r.CurId()
@@ -176,3 +176,51 @@ func TestFindPatchTargets(t *testing.T) {
 	}
 
 }
+
+func TestSelectWildcards(t *testing.T) {
+	rm := setupRMForPatchTargets(t)
+	testcases := map[string]struct {
+		target types.Selector
+		count  int
+	}{
+		"prefix": {
+			target: types.Selector{Name: "name*"},
+			count:  3,
+		},
+		"suffix": {
+			target: types.Selector{Name: "*name1"},
+			count:  2,
+		},
+		"infix": {
+			target: types.Selector{Name: "x-*1"},
+			count:  1,
+		},
+		"namespace": {
+			target: types.Selector{Namespace: "*default"},
+			count:  3,
+		},
+		// With other syntax, a * is a regular expression's.
+		"regex": {
+			target: types.Selector{Name: "x-nam(e)*1"},
+			count:  1,
+		},
+	}
+	for name, testcase := range testcases {
+		actual, err := rm.Select(testcase.target)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if len(actual) != testcase.count {
+			t.Errorf("%s: expected %d objects, but got %d:\n%v",
+				name, testcase.count, len(actual), actual)
+		}
+	}
+}
+
+func TestSelectBadRegex(t *testing.T) {
+	rm := setupRMForPatchTargets(t)
+	_, err := rm.Select(types.Selector{Name: "name(1"})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// Container holds the commonly used fields
+// of a container in a pod spec.
+type Container struct {
+	Name  string
+	Image string
+	// Init is true for entries in initContainers.
+	Init bool
+}
+
+// podTemplatePaths maps workload kinds to
+// the location of their pod template.
+var podTemplatePaths = map[string][]string{
+	"Deployment":            {"spec", "template"},
+	"DaemonSet":             {"spec", "template"},
+	"ReplicaSet":            {"spec", "template"},
+	"ReplicationController": {"spec", "template"},
+	"StatefulSet":           {"spec", "template"},
+	"Job":                   {"spec", "template"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template"},
+}
+
+// ApplyOrderAnnotation, set to an integer weight, puts
+// a resource in the sorted output before the resources
+// without it, if negative, or after them, if positive,
+// so that its author can say, e.g., that a database
+// comes before the app using it.  Resources of the
+// same weight keep the default order.
+const ApplyOrderAnnotation = "kustomize.config.k8s.io/apply-order"
+
+// ApplyOrder returns the weight of the
+// ApplyOrderAnnotation, 0 if it's unset.
+func (r *Resource) ApplyOrder() (int, error) {
+	v, ok := r.GetAnnotations()[ApplyOrderAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	w, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return 0, fmt.Errorf(
+			"%s: annotation %s is '%s', expected an integer",
+			r.CurId(), ApplyOrderAnnotation, v)
+	}
+	return w, nil
+}
+
+// GetReplicas returns spec.replicas.
+func (r *Resource) GetReplicas() (int64, error) {
+	v, err := r.GetFieldValue("spec.replicas")
+	if err != nil {
+		return 0, err
+	}
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case float64:
+		return int64(n), nil
+	}
+	return 0, fmt.Errorf(
+		"%s: spec.replicas is %T, expected an integer", r.CurId(), v)
+}
+
+// SetReplicas sets spec.replicas, adding it if missing.
+func (r *Resource) SetReplicas(n int64) error {
+	spec, err := r.mapAt([]string{"spec"})
+	if _, ok := err.(types.NoFieldError); ok {
+		spec = map[string]interface{}{}
+		r.Map()["spec"] = spec
+	} else if err != nil {
+		return err
+	}
+	if v, ok := spec["replicas"]; ok {
+		switch v.(type) {
+		case int64, float64:
+		default:
+			return fmt.Errorf(
+				"%s: spec.replicas is %T, expected an integer", r.CurId(), v)
+		}
+	}
+	spec["replicas"] = n
+	return nil
+}
+
+// PodTemplate is the pod template of a workload.
+// It isn't a copy; changing it changes the resource.
+type PodTemplate struct {
+	m    map[string]interface{}
+	what string
+}
+
+// HasPodSpec is true of Pods, and of the workloads
+// holding a pod template, e.g. Deployments.
+func (r *Resource) HasPodSpec() bool {
+	_, ok := podTemplatePaths[r.GetKind()]
+	return ok || r.GetKind() == "Pod"
+}
+
+// PodTemplate returns the pod template of a workload.
+func (r *Resource) PodTemplate() (*PodTemplate, error) {
+	path, ok := podTemplatePaths[r.GetKind()]
+	if !ok {
+		return nil, fmt.Errorf(
+			"%s: kind %s has no pod template", r.CurId(), r.GetKind())
+	}
+	return r.PodTemplateAt(path)
+}
+
+// PodTemplateAt returns the pod template at the given
+// path, for kinds, e.g. custom resources, PodTemplate
+// doesn't know.
+func (r *Resource) PodTemplateAt(path []string) (*PodTemplate, error) {
+	m, err := r.mapAt(path)
+	if err != nil {
+		return nil, err
+	}
+	return &PodTemplate{
+		m: m, what: r.CurId().String() + ": " + strings.Join(path, ".")}, nil
+}
+
+// GetAnnotations returns the annotations of the
+// pod template, or nil if it has none.
+func (t *PodTemplate) GetAnnotations() map[string]string {
+	md, _ := t.m["metadata"].(map[string]interface{})
+	a, _ := md["annotations"].(map[string]interface{})
+	if a == nil {
+		return nil
+	}
+	result := make(map[string]string, len(a))
+	for k, v := range a {
+		if s, ok := v.(string); ok {
+			result[k] = s
+		}
+	}
+	return result
+}
+
+// SetAnnotation sets an annotation of the pod
+// template, adding its metadata if missing.
+func (t *PodTemplate) SetAnnotation(key, value string) error {
+	m := t.m
+	for _, field := range []string{"metadata", "annotations"} {
+		v, ok := m[field]
+		if !ok || v == nil {
+			v = map[string]interface{}{}
+			m[field] = v
+		}
+		var err error
+		m, err = asMap(v, t.what+"."+field)
+		if err != nil {
+			return err
+		}
+	}
+	m[key] = value
+	return nil
+}
+
+// podSpec returns the pod spec of a workload or Pod.
+func (r *Resource) podSpec() (map[string]interface{}, error) {
+	if r.GetKind() == "Pod" {
+		return r.mapAt([]string{"spec"})
+	}
+	path, ok := podTemplatePaths[r.GetKind()]
+	if !ok {
+		return nil, fmt.Errorf(
+			"%s: kind %s has no pod template", r.CurId(), r.GetKind())
+	}
+	return r.mapAt(append(append([]string{}, path...), "spec"))
+}
+
+// Containers returns the init containers and
+// containers of a workload or Pod, in that order.
+func (r *Resource) Containers() ([]Container, error) {
+	var result []Container
+	err := r.eachContainer(func(c map[string]interface{}, init bool) error {
+		name, _ := c["name"].(string)
+		image, _ := c["image"].(string)
+		result = append(result, Container{Name: name, Image: image, Init: init})
+		return nil
+	})
+	return result, err
+}
+
+// SetImage sets the image of the named container,
+// or init container, of a workload or Pod.
+func (r *Resource) SetImage(container, image string) error {
+	found := false
+	err := r.eachContainer(func(c map[string]interface{}, _ bool) error {
+		if name, _ := c["name"].(string); name == container {
+			c["image"] = image
+			found = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf(
+			"%s: no container named '%s'", r.CurId(), container)
+	}
+	return nil
+}
+
+// GetServiceAccountName returns the service
+// account name of a workload or Pod, or the
+// empty string if none is set.
+func (r *Resource) GetServiceAccountName() (string, error) {
+	spec, err := r.podSpec()
+	if err != nil {
+		return "", err
+	}
+	v, ok := spec["serviceAccountName"]
+	if !ok {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf(
+			"%s: serviceAccountName is %T, expected a string", r.CurId(), v)
+	}
+	return s, nil
+}
+
+func (r *Resource) eachContainer(
+	f func(c map[string]interface{}, init bool) error) error {
+	spec, err := r.podSpec()
+	if err != nil {
+		return err
+	}
+	for _, field := range []string{"initContainers", "containers"} {
+		v, ok := spec[field]
+		if !ok {
+			continue
+		}
+		l, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf(
+				"%s: %s is %T, expected a list", r.CurId(), field, v)
+		}
+		for i, item := range l {
+			c, err := asMap(item, fmt.Sprintf(
+				"%s: %s[%d]", r.CurId(), field, i))
+			if err != nil {
+				return err
+			}
+			if err := f(c, field == "initContainers"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mapAt returns the map at the given path.
+func (r *Resource) mapAt(path []string) (map[string]interface{}, error) {
+	m := r.Map()
+	for i, p := range path {
+		at := strings.Join(path[:i+1], ".")
+		v, ok := m[p]
+		if !ok {
+			return nil, types.NoFieldError{Field: at}
+		}
+		var err error
+		m, err = asMap(v, r.CurId().String()+": "+at)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func asMap(v interface{}, what string) (map[string]interface{}, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is %T, expected a map", what, v)
+	}
+	return m, nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+func TestWorkloadAccessors(t *testing.T) {
+	r, err := factory.FromBytes([]byte(`
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          serviceAccountName: backup-sa
+          initContainers:
+          - name: init
+            image: busybox
+          containers:
+          - name: backup
+            image: backup:1.0
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cs, err := r.Containers()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Container{
+		{Name: "init", Image: "busybox", Init: true},
+		{Name: "backup", Image: "backup:1.0"},
+	}
+	if !reflect.DeepEqual(cs, expected) {
+		t.Fatalf("expected %v, got %v", expected, cs)
+	}
+	sa, err := r.GetServiceAccountName()
+	if err != nil || sa != "backup-sa" {
+		t.Fatalf("expected backup-sa, got %q, %v", sa, err)
+	}
+	err = r.SetImage("backup", "backup:2.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cs, _ = r.Containers()
+	if cs[1].Image != "backup:2.0" {
+		t.Fatalf("image not set: %v", cs)
+	}
+	if err = r.SetImage("nope", "x"); err == nil {
+		t.Fatalf("expected error for unknown container")
+	}
+	if _, err = r.GetReplicas(); err == nil {
+		t.Fatalf("expected error for missing replicas")
+	}
+	if _, ok := err.(types.NoFieldError); !ok {
+		t.Fatalf("expected NoFieldError, got %T", err)
+	}
+}
+
+func TestGetReplicas(t *testing.T) {
+	r, err := factory.FromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n, err := r.GetReplicas()
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3, got %d, %v", n, err)
+	}
+	if err = r.SetReplicas(5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, _ = r.GetReplicas(); n != 5 {
+		t.Fatalf("expected 5, got %d", n)
+	}
+	tmpl, err := r.PodTemplate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a := tmpl.GetAnnotations(); a != nil {
+		t.Fatalf("expected no annotations, got %v", a)
+	}
+	if err = tmpl.SetAnnotation("a", "b"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, err := r.GetFieldValue("spec.template.metadata.annotations.a")
+	if err != nil || v != "b" {
+		t.Fatalf("expected annotation b, got %v, %v", v, err)
+	}
+	sa, err := r.GetServiceAccountName()
+	if err != nil || sa != "" {
+		t.Fatalf("expected no service account, got %q, %v", sa, err)
+	}
+}
+
+func TestSetReplicas(t *testing.T) {
+	r, err := factory.FromBytes([]byte(`
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = r.SetReplicas(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, err := r.GetReplicas(); err != nil || n != 2 {
+		t.Fatalf("expected 2, got %d, %v", n, err)
+	}
+	r.Map()["spec"].(map[string]interface{})["replicas"] = "two"
+	if err = r.SetReplicas(3); err == nil {
+		t.Fatalf("expected error for replicas that aren't an integer")
+	}
+}
+
+func TestAccessorErrors(t *testing.T) {
+	if _, err := testConfigMap.PodTemplate(); err == nil {
+		t.Errorf("expected error for ConfigMap pod template")
+	}
+	if _, err := testConfigMap.Containers(); err == nil {
+		t.Errorf("expected error for ConfigMap containers")
+	}
+	r, err := factory.FromBytes([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bad
+spec:
+  containers: nginx
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Containers(); err == nil {
+		t.Errorf("expected error for malformed containers")
+	}
+}
+
+func TestApplyOrder(t *testing.T) {
+	for annotations, expected := range map[string]int{
+		"{}": 0,
+		`{kustomize.config.k8s.io/apply-order: "-10"}`: -10,
+		`{kustomize.config.k8s.io/apply-order: "5"}`:   5,
+	} {
+		r, err := factory.FromBytes([]byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  annotations: ` + annotations + `
+`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		w, err := r.ApplyOrder()
+		if err != nil || w != expected {
+			t.Fatalf("%s: expected %d, got %d, %v", annotations, expected, w, err)
+		}
+	}
+	r, err := factory.FromBytes([]byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  annotations:
+    kustomize.config.k8s.io/apply-order: first
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.ApplyOrder(); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"bytes"
+	"log"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+)
+
+// DuplicateKeys says what to do when a map in a loaded
+// file has a key more than once, which decoding
+// collapses into the last of its values.
+type DuplicateKeys string
+
+const (
+	// DuplicateKeysError fails the build.
+	DuplicateKeysError DuplicateKeys = "error"
+	// DuplicateKeysWarn lets the last value win, with a warning.
+	DuplicateKeysWarn DuplicateKeys = "warn"
+	// DuplicateKeysLastWins lets the last value win, silently.
+	DuplicateKeysLastWins DuplicateKeys = "last-wins"
+)
+
+// CheckDuplicateKeys looks for maps with duplicate keys
+// in the YAML or JSON documents loaded from path, failing
+// or warning as the DuplicateKeys of the factory's options
+// say.  Syntax errors are left to the decoding of the
+// documents.
+func (rf *Factory) CheckDuplicateKeys(path string, data []byte) error {
+	duplicateKeys := rf.opts.DuplicateKeys
+	if duplicateKeys == "" || duplicateKeys == DuplicateKeysLastWins {
+		return nil
+	}
+	dups := findDuplicateKeys(data)
+	if len(dups) == 0 {
+		return nil
+	}
+	msg := messages.Sprintf(
+		messages.DuplicateKeys, path, strings.Join(dups, "; "))
+	if duplicateKeys == DuplicateKeysError {
+		return kusterr.WithClass(kusterr.ClassValidation,
+			messages.WithID(messages.DuplicateKeys, errors.New(msg)))
+	}
+	log.Printf("warning: %s; the last value of each wins", msg)
+	return nil
+}
+
+// findDuplicateKeys returns where the documents in data
+// repeat a key of a map, e.g. `line 7: key "replicas"
+// already set in map`.
+func findDuplicateKeys(data []byte) []string {
+	d := yaml.NewDecoder(bytes.NewReader(data))
+	d.SetStrict(true)
+	var result []string
+	for {
+		var doc interface{}
+		err := d.Decode(&doc)
+		if err == nil {
+			continue
+		}
+		te, ok := err.(*yaml.TypeError)
+		if !ok {
+			// The end of the data, or a syntax error.
+			return result
+		}
+		for _, e := range te.Errors {
+			if strings.Contains(e, "already set in map") {
+				result = append(result, e)
+			}
+		}
+	}
+}
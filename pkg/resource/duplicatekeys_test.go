@@ -0,0 +1,38 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource_test
+
+import (
+	"testing"
+
+	. "sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestCheckDuplicateKeys(t *testing.T) {
+	tests := map[string]string{
+		"a: 1\nb: 2\n":      "",
+		"a: 1\na: 2\n":      `f has duplicate keys: line 2: key "a" already set in map`,
+		"a: 1\n---\na: 2\n": "",
+		"a: 1\n---\nb:\n  c: 1\n  c: 2\n---\nd: 1\nd: 2\n": `f has duplicate keys: ` +
+			`line 5: key "c" already set in map; ` +
+			`line 8: key "d" already set in map`,
+		`{"a": 1, "a": 2}`: `f has duplicate keys: line 1: key "a" already set in map`,
+		// Syntax errors are the decoder's to report.
+		"a: [1\na: 2\n": "",
+	}
+	for in, expected := range tests {
+		rf := factory.WithOptions(Options{DuplicateKeys: DuplicateKeysError})
+		err := rf.CheckDuplicateKeys("f", []byte(in))
+		if expected == "" && err != nil {
+			t.Errorf("%q: unexpected err: %v", in, err)
+		}
+		if expected != "" && (err == nil || err.Error() != expected) {
+			t.Errorf("%q: expected %s, got %v", in, expected, err)
+		}
+		rf = factory.WithOptions(Options{DuplicateKeys: DuplicateKeysWarn})
+		if err := rf.CheckDuplicateKeys("f", []byte(in)); err != nil {
+			t.Errorf("%q: unexpected err: %v", in, err)
+		}
+	}
+}
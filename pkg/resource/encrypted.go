@@ -0,0 +1,233 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// encryptedField is the value, as read, of a field
+// holding an encrypted payload, which any change
+// would corrupt.
+type encryptedField struct {
+	// path is the path to the field, through the
+	// keys of maps and the indexes of lists.
+	path  []string
+	value interface{}
+}
+
+func (f encryptedField) String() string {
+	var parts []string
+	for _, p := range f.path {
+		parts = append(parts, strings.Replace(p, "/", `\/`, -1))
+	}
+	return strings.Join(parts, "/")
+}
+
+const (
+	// sopsField holds the metadata, e.g. the MAC, of a
+	// resource encrypted with SOPS.
+	sopsField = "sops"
+	// sopsPrefix starts each value SOPS encrypted.
+	sopsPrefix = "ENC["
+)
+
+// KeepEncryptedFields records the fields of the resource
+// holding encrypted payloads, as they are, for
+// RestoreEncryptedFields and ChangedEncryptedFields.
+// Those are the fields listed in its
+// EncryptedFieldsAnnotation, the spec.encryptedData of a
+// SealedSecret, and, in a resource SOPS encrypted, its
+// sops metadata and the values SOPS encrypted.
+func (r *Resource) KeepEncryptedFields() {
+	r.encrypted = nil
+	var paths [][]string
+	if a := r.GetAnnotations()[types.EncryptedFieldsAnnotation]; a != "" {
+		for _, p := range strings.Split(a, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				paths = append(paths, config.FieldSpec{Path: p}.PathSlice())
+			}
+		}
+	}
+	if r.GetKind() == "SealedSecret" && r.GetGvk().Group == "bitnami.com" {
+		paths = append(paths, []string{"spec", "encryptedData"})
+	}
+	if len(paths) == 0 && !r.mayHaveField(sopsField) {
+		return
+	}
+	obj := r.Map()
+	for _, p := range paths {
+		if v, ok := valueAt(obj, p); ok {
+			r.encrypted = append(
+				r.encrypted, encryptedField{path: p, value: deepCopy(v)})
+		}
+	}
+	if v, ok := obj[sopsField]; ok {
+		r.encrypted = append(r.encrypted, encryptedField{
+			path: []string{sopsField}, value: deepCopy(v)})
+		for k, v := range obj {
+			if k != sopsField {
+				r.encrypted = appendSOPSValues(r.encrypted, []string{k}, v)
+			}
+		}
+	}
+}
+
+// mayHaveField is false if the resource, as read,
+// surely has no top level field of the given name,
+// without decoding it.
+func (r *Resource) mayHaveField(name string) bool {
+	if o, ok := r.Kunstructured.(original); ok && o.Original() != nil &&
+		!bytes.Contains(o.Original(), []byte(name)) {
+		return false
+	}
+	_, ok := r.Map()[name]
+	return ok
+}
+
+// appendSOPSValues appends the values SOPS encrypted
+// at or under path.
+func appendSOPSValues(
+	fields []encryptedField, path []string, v interface{}) []encryptedField {
+	switch x := v.(type) {
+	case string:
+		if strings.HasPrefix(x, sopsPrefix) {
+			fields = append(fields, encryptedField{path: path, value: x})
+		}
+	case map[string]interface{}:
+		for k, e := range x {
+			fields = appendSOPSValues(fields, appendPath(path, k), e)
+		}
+	case []interface{}:
+		for i, e := range x {
+			fields = appendSOPSValues(
+				fields, appendPath(path, strconv.Itoa(i)), e)
+		}
+	}
+	return fields
+}
+
+func appendPath(path []string, p string) []string {
+	return append(path[:len(path):len(path)], p)
+}
+
+// HasEncryptedFields is true if KeepEncryptedFields
+// recorded any fields of the resource.
+func (r *Resource) HasEncryptedFields() bool {
+	return len(r.encrypted) > 0
+}
+
+// ChangedEncryptedFields returns the paths of the
+// fields KeepEncryptedFields recorded that have since
+// changed, or gone.
+func (r *Resource) ChangedEncryptedFields() []string {
+	if len(r.encrypted) == 0 {
+		return nil
+	}
+	var result []string
+	obj := r.Map()
+	for _, f := range r.encrypted {
+		v, ok := valueAt(obj, f.path)
+		if !ok || !reflect.DeepEqual(v, f.value) {
+			result = append(result, f.String())
+		}
+	}
+	return result
+}
+
+// RestoreEncryptedFields sets the fields KeepEncryptedFields
+// recorded back to their values, if they've changed and
+// their maps or lists are still there, returning the paths
+// of those set.
+func (r *Resource) RestoreEncryptedFields() []string {
+	if len(r.encrypted) == 0 {
+		return nil
+	}
+	var result []string
+	obj := r.Map()
+	for _, f := range r.encrypted {
+		v, ok := valueAt(obj, f.path)
+		if ok && reflect.DeepEqual(v, f.value) {
+			continue
+		}
+		if setValueAt(obj, f.path, deepCopy(f.value)) {
+			result = append(result, f.String())
+		}
+	}
+	if len(result) > 0 {
+		r.SetMap(obj)
+	}
+	return result
+}
+
+// valueAt returns the value at path in obj.
+func valueAt(obj interface{}, path []string) (interface{}, bool) {
+	for _, p := range path {
+		switch x := obj.(type) {
+		case map[string]interface{}:
+			v, ok := x[p]
+			if !ok {
+				return nil, false
+			}
+			obj = v
+		case []interface{}:
+			i, err := strconv.Atoi(p)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil, false
+			}
+			obj = x[i]
+		default:
+			return nil, false
+		}
+	}
+	return obj, true
+}
+
+// setValueAt sets the value at path in obj, if the map
+// or list holding it is there.
+func setValueAt(obj interface{}, path []string, v interface{}) bool {
+	parent, ok := valueAt(obj, path[:len(path)-1])
+	if !ok {
+		return false
+	}
+	last := path[len(path)-1]
+	switch x := parent.(type) {
+	case map[string]interface{}:
+		x[last] = v
+		return true
+	case []interface{}:
+		i, err := strconv.Atoi(last)
+		if err != nil || i < 0 || i >= len(x) {
+			return false
+		}
+		x[i] = v
+		return true
+	}
+	return false
+}
+
+// deepCopy copies a value decoded from JSON or YAML.
+func deepCopy(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i, e := range x {
+			l[i] = deepCopy(e)
+		}
+		return l
+	}
+	return v
+}
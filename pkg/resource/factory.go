@@ -16,7 +16,8 @@ import (
 
 // Factory makes instances of Resource.
 type Factory struct {
-	kf ifc.KunstructuredFactory
+	kf   ifc.KunstructuredFactory
+	opts Options
 }
 
 // NewFactory makes an instance of Factory.
@@ -24,6 +25,18 @@ func NewFactory(kf ifc.KunstructuredFactory) *Factory {
 	return &Factory{kf: kf}
 }
 
+// WithOptions returns a Factory like this one, whose
+// resources follow the given options.
+func (rf *Factory) WithOptions(o Options) *Factory {
+	return &Factory{kf: rf.kf, opts: o}
+}
+
+// Options returns the options the factory's
+// resources follow.
+func (rf *Factory) Options() Options {
+	return rf.opts
+}
+
 func (rf *Factory) Hasher() ifc.KunstructuredHasher {
 	return rf.kf.Hasher()
 }
@@ -69,10 +82,11 @@ func (rf *Factory) makeOne(
 		o = types.NewGenArgs(nil, nil)
 	}
 	r := &Resource{
-		Kunstructured: u,
-		options:       o,
+		Kunstructured:  u,
+		options:        o,
+		factoryOptions: &rf.opts,
 	}
-	return r.setOriginalName(r.GetName()).setOriginalNs(r.GetNamespace())
+	return r.setOriginalName(r.idName()).setOriginalNs(r.GetNamespace())
 }
 
 // SliceFromPatches returns a slice of resources given a patch path
@@ -85,9 +99,13 @@ func (rf *Factory) SliceFromPatches(
 		if err != nil {
 			return nil, err
 		}
+		if err := rf.CheckDuplicateKeys(string(path), content); err != nil {
+			return nil, err
+		}
 		res, err := rf.SliceFromBytes(content)
 		if err != nil {
-			return nil, kusterr.Handler(err, string(path))
+			return nil, kusterr.WithClass(
+				kusterr.ClassLoad, kusterr.Handler(err, string(path)))
 		}
 		result = append(result, res...)
 	}
@@ -118,7 +136,16 @@ func (rf *Factory) SliceFromBytes(in []byte) ([]*Resource, error) {
 		u := kunStructs[0]
 		kunStructs = kunStructs[1:]
 		if strings.HasSuffix(u.GetKind(), "List") {
-			items := u.Map()["items"]
+			// GetFieldValue, unlike Map, fails if the
+			// List couldn't be decoded.
+			items, err := u.GetFieldValue("items")
+			if _, ok := err.(types.NoFieldError); ok {
+				// a List without items
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
 			itemsSlice, ok := items.([]interface{})
 			if !ok {
 				if items == nil {
@@ -151,6 +178,7 @@ func (rf *Factory) MakeConfigMap(
 	ldr ifc.Loader,
 	options *types.GeneratorOptions,
 	args *types.ConfigMapArgs) (*Resource, error) {
+	options = types.MergeGlobalOptionsIntoLocal(args.Options, options)
 	u, err := rf.kf.MakeConfigMap(ldr, options, args)
 	if err != nil {
 		return nil, err
@@ -167,6 +195,7 @@ func (rf *Factory) MakeSecret(
 	ldr ifc.Loader,
 	options *types.GeneratorOptions,
 	args *types.SecretArgs) (*Resource, error) {
+	options = types.MergeGlobalOptionsIntoLocal(args.Options, options)
 	u, err := rf.kf.MakeSecret(ldr, options, args)
 	if err != nil {
 		return nil, err
@@ -17,7 +17,6 @@ limitations under the License.
 package resource_test
 
 import (
-	"reflect"
 	"testing"
 
 	"sigs.k8s.io/kustomize/v3/internal/loadertest"
@@ -98,6 +97,18 @@ items:
 	patch7 := `
 apiVersion: v1
 kind: List
+`
+	patchList5 := types.PatchStrategicMerge("patch8.yaml")
+	patch8 := `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: winnie
+  data:
+    honey: .inf
 `
 	testDeploymentSpec := map[string]interface{}{
 		"template": map[string]interface{}{
@@ -139,6 +150,7 @@ kind: List
 	l.AddFile("/"+string(patchList2), []byte(patch5))
 	l.AddFile("/"+string(patchList3), []byte(patch6))
 	l.AddFile("/"+string(patchList4), []byte(patch7))
+	l.AddFile("/"+string(patchList5), []byte(patch8))
 
 	tests := []struct {
 		name        string
@@ -188,6 +200,12 @@ kind: List
 			expectedOut: []*Resource{},
 			expectedErr: false,
 		},
+		{
+			name:        "listThatDoesNotDecode",
+			input:       []types.PatchStrategicMerge{patchList5},
+			expectedOut: []*Resource{},
+			expectedErr: true,
+		},
 	}
 	for _, test := range tests {
 		rs, err := factory.SliceFromPatches(l, test.input)
@@ -202,7 +220,7 @@ kind: List
 				test.name, len(rs), len(test.expectedOut))
 		}
 		for i := range rs {
-			if !reflect.DeepEqual(test.expectedOut[i], rs[i]) {
+			if !test.expectedOut[i].Equals(rs[i]) {
 				t.Fatalf("%s: Got: %v\nexpected:%v",
 					test.name, test.expectedOut[i], rs[i])
 			}
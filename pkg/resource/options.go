@@ -0,0 +1,55 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/openapi"
+)
+
+// Options say how the resources a Factory makes are
+// checked, patched and shown.  The zero Options are
+// those of a build given no flags.
+type Options struct {
+	// DuplicateKeys says what CheckDuplicateKeys does;
+	// empty means DuplicateKeysLastWins.
+	DuplicateKeys DuplicateKeys
+	// Redaction says how String, and so error messages,
+	// and Redacted show Secrets.
+	Redaction Redaction
+	// RedactionKey is the key of RedactHash.
+	RedactionKey []byte
+	// PreserveUntouched outputs resources no transformer
+	// changed as they were read, comments, key order,
+	// quoting and all.
+	PreserveUntouched bool
+	// Schema is the OpenAPI schema used by strategic
+	// merge patches; nil uses the compiled in API types.
+	Schema *openapi.Schema
+}
+
+// schemaPatcher is a Kunstructured that patches with
+// the given schema rather than the compiled in types.
+type schemaPatcher interface {
+	PatchWithSchema(ifc.Kunstructured, *openapi.Schema) error
+}
+
+// opts returns the options of the factory that
+// made the resource.
+func (r *Resource) opts() Options {
+	if r.factoryOptions == nil {
+		return Options{}
+	}
+	return *r.factoryOptions
+}
+
+// Patch applies the patch to the resource, by a
+// strategic merge patch with the schema of its
+// factory's options, if the resource's type has one.
+func (r *Resource) Patch(patch ifc.Kunstructured) error {
+	if p, ok := r.Kunstructured.(schemaPatcher); ok {
+		return p.PatchWithSchema(patch, r.opts().Schema)
+	}
+	return r.Kunstructured.Patch(patch)
+}
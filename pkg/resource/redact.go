@@ -0,0 +1,92 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+)
+
+// Redaction says how the values of Secrets are shown.
+type Redaction int
+
+const (
+	// RedactNone shows values as they are.
+	RedactNone Redaction = iota
+	// RedactMask replaces values with RedactedValue.
+	RedactMask
+	// RedactHash replaces values with an HMAC of them,
+	// under a key, so changes remain visible to those
+	// comparing outputs made with the same key, but the
+	// values can't be found by hashing guesses without it.
+	RedactHash
+)
+
+// RedactionKeySize is the size of the keys
+// NewRedactionKey returns.
+const RedactionKeySize = 32
+
+// NewRedactionKey returns a random key for RedactHash.
+// Outputs redacted with different keys can't be compared;
+// to compare those of builds, give them the same key.
+func NewRedactionKey() ([]byte, error) {
+	key := make([]byte, RedactionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// RedactedValue replaces the values of Secrets under RedactMask.
+const RedactedValue = "<redacted>"
+
+// Redacted returns the resource, or if it's a Secret and
+// the Redaction of its factory's options asks for it, a
+// copy with redacted values.
+func (r *Resource) Redacted() *Resource {
+	redaction := r.opts().Redaction
+	if redaction == RedactNone || !r.isSecret() {
+		return r
+	}
+	rc := r.DeepCopy()
+	rc.Redact(redaction, r.opts().RedactionKey)
+	return rc
+}
+
+// Redact replaces the values of the data and stringData
+// fields of a Secret, keeping their keys.  RedactHash
+// uses key, and masks values, as RedactMask, if it's
+// empty, rather than hash them unkeyed.
+func (r *Resource) Redact(how Redaction, key []byte) {
+	if how == RedactNone || !r.isSecret() {
+		return
+	}
+	m := r.Map()
+	for _, field := range []string{"data", "stringData"} {
+		data, ok := m[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for k, v := range data {
+			data[k] = redactValue(fmt.Sprint(v), how, key)
+		}
+	}
+	r.SetMap(m)
+}
+
+func redactValue(v string, how Redaction, key []byte) string {
+	if how == RedactHash && len(key) > 0 {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(v))
+		return fmt.Sprintf("<redacted:hmac-sha256:%x>", h.Sum(nil))
+	}
+	return RedactedValue
+}
+
+func (r *Resource) isSecret() bool {
+	g := r.GetGvk()
+	return g.Group == "" && g.Kind == "Secret"
+}
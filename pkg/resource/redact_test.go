@@ -0,0 +1,92 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource_test
+
+import (
+	"strings"
+	"testing"
+
+	. "sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func makeSecret() *Resource {
+	return makeSecretWith(factory)
+}
+
+func makeSecretWith(rf *Factory) *Resource {
+	return rf.FromMap(
+		map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name": "pooh",
+			},
+			"data": map[string]interface{}{
+				"password": "aHVubnk=",
+			},
+			"stringData": map[string]interface{}{
+				"user": "pooh",
+			},
+		})
+}
+
+func TestRedact(t *testing.T) {
+	s := makeSecret()
+	s.Redact(RedactMask, nil)
+	data, _ := s.GetStringMap("data")
+	stringData, _ := s.GetStringMap("stringData")
+	if data["password"] != RedactedValue || stringData["user"] != RedactedValue {
+		t.Fatalf("unexpected values %v %v", data, stringData)
+	}
+
+	key := []byte("key")
+	s = makeSecret()
+	s.Redact(RedactHash, key)
+	data, _ = s.GetStringMap("data")
+	// The HMAC-SHA256 of aHVubnk= under key.
+	if data["password"] != "<redacted:hmac-sha256:"+
+		"6e22776948c65726bd65fdeb2345af52f447b4a5498e3680f605664b9a97f511>" {
+		t.Fatalf("unexpected value %s", data["password"])
+	}
+	other := makeSecret()
+	other.Redact(RedactHash, key)
+	otherData, _ := other.GetStringMap("data")
+	if data["password"] != otherData["password"] {
+		t.Fatalf("hashes of the same value under the same key differ")
+	}
+	other = makeSecret()
+	other.Redact(RedactHash, []byte("other key"))
+	otherData, _ = other.GetStringMap("data")
+	if data["password"] == otherData["password"] {
+		t.Fatalf("hashes of the same value under other keys match")
+	}
+	other = makeSecret()
+	other.Redact(RedactHash, nil)
+	otherData, _ = other.GetStringMap("data")
+	if otherData["password"] != RedactedValue {
+		t.Fatalf("expected the value masked without a key, got %s",
+			otherData["password"])
+	}
+
+	cm := testConfigMap.DeepCopy()
+	cm.Redact(RedactMask, nil)
+	if !cm.Equals(testConfigMap) {
+		t.Fatalf("expected a ConfigMap to be left alone")
+	}
+}
+
+func TestRedactedString(t *testing.T) {
+	s := makeSecret()
+	if !strings.Contains(s.String(), "aHVubnk=") {
+		t.Fatalf("expected the value without redaction: %s", s)
+	}
+	s = makeSecretWith(factory.WithOptions(Options{Redaction: RedactMask}))
+	if strings.Contains(s.String(), "aHVubnk=") {
+		t.Fatalf("expected the value to be redacted: %s", s)
+	}
+	data, _ := s.GetStringMap("data")
+	if data["password"] != "aHVubnk=" {
+		t.Fatalf("String should not change the secret")
+	}
+}
@@ -25,6 +25,9 @@ type Resource struct {
 	refVarNames  []string
 	namePrefixes []string
 	nameSuffixes []string
+	encrypted    []encryptedField
+	// The options of the factory that made it.
+	factoryOptions *Options
 }
 
 // ResCtx is an interface describing the contextual added
@@ -52,14 +55,22 @@ func (r *Resource) DeepCopy() *Resource {
 	return rc
 }
 
-// Replace performs replace with other resource.
+// Replace performs replace with other resource, r being
+// that of a generator replacing other, e.g. of a base.
+// Its data is r's.  Its labels and annotations are
+// other's, with r's winning for the same keys.  Its
+// name, namespace and behavior are other's.  Its options,
+// e.g. disableNameSuffixHash, are other's but for those
+// r's generator sets, as types.GenArgs OverriddenBy says.
 func (r *Resource) Replace(other *Resource) {
 	r.SetLabels(mergeStringMaps(other.GetLabels(), r.GetLabels()))
 	r.SetAnnotations(
 		mergeStringMaps(other.GetAnnotations(), r.GetAnnotations()))
 	r.SetName(other.GetName())
 	r.SetNamespace(other.GetNamespace())
+	options := r.options
 	r.copyOtherFields(other)
+	r.options = other.options.OverriddenBy(options)
 }
 
 func (r *Resource) copyOtherFields(other *Resource) {
@@ -70,11 +81,12 @@ func (r *Resource) copyOtherFields(other *Resource) {
 	r.refVarNames = copyStringSlice(other.refVarNames)
 	r.namePrefixes = copyStringSlice(other.namePrefixes)
 	r.nameSuffixes = copyStringSlice(other.nameSuffixes)
+	r.encrypted = other.encrypted
+	r.factoryOptions = other.factoryOptions
 }
 
 func (r *Resource) Equals(o *Resource) bool {
-	return r.ReferencesEqual(o) &&
-		reflect.DeepEqual(r.Kunstructured, o.Kunstructured)
+	return r.ReferencesEqual(o) && r.KunstructEqual(o)
 }
 
 func (r *Resource) ReferencesEqual(o *Resource) bool {
@@ -92,8 +104,10 @@ func (r *Resource) ReferencesEqual(o *Resource) bool {
 	return len(setSelf) == len(setOther)
 }
 
+// KunstructEqual is true if the resources have the same
+// content, however far each has been decoded.
 func (r *Resource) KunstructEqual(o *Resource) bool {
-	return reflect.DeepEqual(r.Kunstructured, o.Kunstructured)
+	return reflect.DeepEqual(r.Map(), o.Map())
 }
 
 // Merge performs merge with other resource.
@@ -213,9 +227,10 @@ func (r *Resource) setOriginalNs(n string) *Resource {
 	return r
 }
 
-// String returns resource as JSON.
+// String returns resource as JSON, with Secret values
+// redacted per the Redaction of its factory's options.
 func (r *Resource) String() string {
-	bs, err := r.MarshalJSON()
+	bs, err := r.Redacted().MarshalJSON()
 	if err != nil {
 		return "<" + err.Error() + ">"
 	}
@@ -223,8 +238,12 @@ func (r *Resource) String() string {
 }
 
 // AsYAML returns the resource in Yaml form.
-// Easier to read than JSON.
+// Easier to read than JSON.  It's the document the
+// resource was read from if that's Untouched.
 func (r *Resource) AsYAML() ([]byte, error) {
+	if doc := r.Untouched(); doc != nil {
+		return doc, nil
+	}
 	json, err := r.MarshalJSON()
 	if err != nil {
 		return nil, err
@@ -242,6 +261,21 @@ func (r *Resource) NeedHashSuffix() bool {
 	return r.options != nil && r.options.NeedsHashSuffix()
 }
 
+// NeedHashAnnotation checks if the resource's hash
+// is to be written to the pod templates referring to it.
+func (r *Resource) NeedHashAnnotation() bool {
+	return r.options != nil && r.options.NeedsHashAnnotation()
+}
+
+// HashIncludes returns the metadata, of
+// types.HashIncludesOptions, the resource's hash covers.
+func (r *Resource) HashIncludes() []string {
+	if r.options == nil {
+		return nil
+	}
+	return r.options.HashIncludes()
+}
+
 // GetNamespace returns the namespace the resource thinks it's in.
 func (r *Resource) GetNamespace() string {
 	namespace, _ := r.GetString("metadata.namespace")
@@ -249,6 +283,24 @@ func (r *Resource) GetNamespace() string {
 	return namespace
 }
 
+// GetGenerateName returns the prefix from which the
+// API server generates the name of a resource without one.
+func (r *Resource) GetGenerateName() string {
+	generateName, _ := r.GetString("metadata.generateName")
+	return generateName
+}
+
+// idName is the name identifying the resource: its name,
+// or, if the API server is to generate one, its
+// generateName, e.g. migrate-, which no name equals,
+// as names don't end in a dash.
+func (r *Resource) idName() string {
+	if n := r.GetName(); n != "" {
+		return n
+	}
+	return r.GetGenerateName()
+}
+
 // OrgId returns the original, immutable ResId for the resource.
 // This doesn't have to be unique in a ResMap.
 // TODO: compute this once and save it in the resource.
@@ -262,7 +314,7 @@ func (r *Resource) OrgId() resid.ResId {
 // This should be unique in any ResMap.
 func (r *Resource) CurId() resid.ResId {
 	return resid.NewResIdWithNamespace(
-		r.GetGvk(), r.GetName(), r.GetNamespace())
+		r.GetGvk(), r.idName(), r.GetNamespace())
 }
 
 // GetRefBy returns the ResIds that referred to current resource
@@ -0,0 +1,64 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+// original is a Kunstructured that keeps the YAML
+// document it was read from.
+type original interface {
+	Original() []byte
+}
+
+const flagPreserveUntouched = "preserve-untouched"
+
+// AddFlagPreserveUntouched adds the flag setting whether
+// resources no transformer changed are output as read.
+func (o *Options) AddFlagPreserveUntouched(set *pflag.FlagSet) {
+	set.BoolVar(
+		&o.PreserveUntouched, flagPreserveUntouched, false,
+		"Output each resource that no transformer changed byte for "+
+			"byte as in its file, keeping comments, key order and "+
+			"quoting, rather than re-marshaled.")
+}
+
+// Untouched returns the YAML document the resource was
+// read from, if the PreserveUntouched of its factory's
+// options asks for it and the resource still has the
+// content of that document, or nil, in which case the
+// resource must be marshaled.
+func (r *Resource) Untouched() []byte {
+	if !r.opts().PreserveUntouched {
+		return nil
+	}
+	o, ok := r.Kunstructured.(original)
+	if !ok || o.Original() == nil {
+		return nil
+	}
+	doc := o.Original()
+	// Comparing both as JSON ignores the differences
+	// between the types their numbers decode to.
+	var was, is map[string]interface{}
+	if yaml.Unmarshal(doc, &was) != nil {
+		return nil
+	}
+	j, err := r.MarshalJSON()
+	if err != nil || json.Unmarshal(j, &is) != nil {
+		return nil
+	}
+	if !reflect.DeepEqual(was, is) {
+		return nil
+	}
+	if !bytes.HasSuffix(doc, []byte("\n")) {
+		doc = append(doc[:len(doc):len(doc)], '\n')
+	}
+	return doc
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource_test
+
+import (
+	"testing"
+
+	. "sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+const untouched = `# The name is quoted, as it might look like a number.
+kind: ConfigMap
+apiVersion: v1
+metadata: {name: "0123"}
+data:
+  greeting: 'hello'
+  count: "3"`
+
+func TestUntouched(t *testing.T) {
+	r, err := factory.FromBytes([]byte(untouched))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Untouched() != nil {
+		t.Fatalf("expected nothing unless asked for")
+	}
+
+	r, err = factory.WithOptions(Options{PreserveUntouched: true}).
+		FromBytes([]byte(untouched))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, r := range []*Resource{r, r.DeepCopy()} {
+		y, err := r.AsYAML()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(y) != untouched+"\n" {
+			t.Fatalf("expected the document as read, got\n%s", y)
+		}
+	}
+
+	// Reading every field, or setting one to what
+	// it was, leaves the resource untouched.
+	r.Map()
+	r.SetName("0123")
+	if r.Untouched() == nil || r.DeepCopy().Untouched() == nil {
+		t.Fatalf("expected the document as read")
+	}
+
+	r.SetLabels(map[string]string{"app": "web"})
+	if r.Untouched() != nil {
+		t.Fatalf("expected nothing for a changed resource")
+	}
+	y, err := r.AsYAML()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: v1
+data:
+  count: "3"
+  greeting: hello
+kind: ConfigMap
+metadata:
+  labels:
+    app: web
+  name: "0123"
+`
+	if string(y) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, y)
+	}
+}
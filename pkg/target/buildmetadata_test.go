@@ -0,0 +1,184 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/internal/loadertest"
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+)
+
+func writeBaseForOrigin(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+configMapGenerator:
+- name: cm
+  literals:
+  - a=b
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+`)
+	th.WriteF("/app/overlays/prod/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`)
+}
+
+func TestOriginAnnotations(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlays/prod")
+	writeBaseForOrigin(th)
+	th.WriteK("/app/overlays/prod", `
+resources:
+- ../../base
+- service.yaml
+buildMetadata:
+- originAnnotations
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    config.kubernetes.io/origin: ../../base/deployment.yaml
+  name: dep
+---
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  annotations:
+    config.kubernetes.io/origin: ../../base/kustomization.yaml
+  name: cm-5k62mh6dh9
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    config.kubernetes.io/origin: service.yaml
+  name: svc
+`)
+}
+
+func TestOriginAnnotationsOff(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlays/prod")
+	writeBaseForOrigin(th)
+	th.WriteK("/app/overlays/prod", `
+resources:
+- ../../base
+- service.yaml
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	for _, r := range m.Resources() {
+		if len(r.GetAnnotations()) != 0 {
+			t.Fatalf("unexpected annotations on %s", r.CurId())
+		}
+	}
+}
+
+func TestBadBuildMetadata(t *testing.T) {
+	ldr := loadertest.NewFakeLoader("/app")
+	ldr.AddFile("/app/kustomization.yaml", []byte(`
+buildMetadata:
+- everything
+`))
+	_, err := target.NewKustTarget(ldr, nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "everything") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTransformerAnnotations(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteK("/app/base", `
+namePrefix: base-
+resources:
+- deployment.yaml
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app
+`)
+	th.WriteF("/app/overlay/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+- service.yaml
+commonLabels:
+  team: a
+images:
+- name: app
+  newTag: v2
+buildMetadata:
+- transformerAnnotations
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    config.kubernetes.io/transformations: PrefixSuffixTransformer,LabelTransformer,ImageTagTransformer
+  labels:
+    team: a
+  name: base-dep
+spec:
+  selector:
+    matchLabels:
+      team: a
+  template:
+    metadata:
+      labels:
+        team: a
+    spec:
+      containers:
+      - image: app:v2
+        name: app
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    config.kubernetes.io/transformations: LabelTransformer
+  labels:
+    team: a
+  name: svc
+spec:
+  selector:
+    team: a
+`)
+}
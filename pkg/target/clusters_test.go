@@ -0,0 +1,145 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/internal/loadertest"
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+)
+
+func writeClusters(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app", `
+namespace: web
+commonLabels:
+  app: web
+images:
+- name: nginx
+  newTag: "1.17"
+resources:
+- deployment.yaml
+clusters:
+- name: east
+  namespace: web-east
+  commonLabels:
+    region: east
+- name: west
+  images:
+  - name: nginx
+    newTag: "1.16"
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`)
+}
+
+func TestClusters(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeClusters(th)
+	kt := th.MakeKustTarget()
+	if !reflect.DeepEqual(kt.Clusters(), []string{"east", "west"}) {
+		t.Fatalf("unexpected clusters %v", kt.Clusters())
+	}
+	east, err := kt.ForCluster("east")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	m, err := east.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+    region: east
+  name: web
+  namespace: web-east
+spec:
+  selector:
+    matchLabels:
+      app: web
+      region: east
+  template:
+    metadata:
+      labels:
+        app: web
+        region: east
+    spec:
+      containers:
+      - image: nginx:1.17
+        name: web
+`)
+	west, err := kt.ForCluster("west")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	m, err = west.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+  name: web
+  namespace: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - image: nginx:1.16
+        name: web
+`)
+	if _, err := kt.ForCluster("north"); err == nil {
+		t.Fatalf("expected an error for an unknown cluster")
+	}
+}
+
+func TestBadClusters(t *testing.T) {
+	ldr := loadertest.NewFakeLoader("/app")
+	ldr.AddFile("/app/kustomization.yaml", []byte(`
+clusters:
+- name: east
+- name: east
+- name: a/b
+- namespace: x
+`))
+	_, err := target.NewKustTarget(ldr, nil, nil, nil)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, msg := range []string{
+		"duplicate cluster name east",
+		"cluster name a/b must not contain path separators",
+		"cluster name '' is invalid",
+	} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Fatalf("expected %q in error %v", msg, err)
+		}
+	}
+}
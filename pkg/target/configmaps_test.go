@@ -178,6 +178,52 @@ metadata:
 `)
 }
 
+func TestGeneratorReplaceKeepsBaseMetadata(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteK("/app/base", `
+configMapGenerator:
+- name: cm
+  literals:
+  - from=base
+  options:
+    labels:
+      fromBase: "yes"
+      shared: base
+    annotations:
+      note: base
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+configMapGenerator:
+- name: cm
+  behavior: replace
+  literals:
+  - from=overlay
+  options:
+    labels:
+      shared: overlay
+    disableNameSuffixHash: true
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  from: overlay
+kind: ConfigMap
+metadata:
+  annotations:
+    note: base
+  labels:
+    fromBase: "yes"
+    shared: overlay
+  name: cm
+`)
+}
+
 func TestGeneratorOverlays(t *testing.T) {
 	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
 	th.WriteK("/app/base1", `
@@ -330,3 +330,67 @@ spec:
   location: Arizona
 `)
 }
+
+func TestCustomConfigRemovesDefault(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteK("/app/base", `
+commonLabels:
+  app: myApp
+resources:
+- deployment.yaml
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`)
+	th.WriteK("/app/overlay", `
+commonLabels:
+  env: prod
+resources:
+- ../base
+configurations:
+- config.yaml
+`)
+	th.WriteF("/app/overlay/config.yaml", `
+commonLabels:
+- kind: Deployment
+  path: spec/template/metadata/labels
+  behavior: remove
+- kind: Deployment
+  path: spec/selector/matchLabels
+  behavior: remove
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: myApp
+    env: prod
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: myApp
+  template:
+    metadata:
+      labels:
+        app: myApp
+    spec:
+      containers:
+      - image: nginx
+        name: web
+`)
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// annotateDependsOn adds the resources each entry of
+// the dependsOn field depends on to the dependent's
+// DependsOnAnnotation, to be resolved once the names
+// are final.
+func annotateDependsOn(deps []types.DependsOn, m resmap.ResMap) error {
+	for _, d := range deps {
+		matches, err := matchRef(m, d.Resource)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return kusterr.WithClass(kusterr.ClassValidation, messages.Errorf(
+				messages.DependsOnNotFound, d.Resource))
+		}
+		for _, r := range matches {
+			a := r.GetAnnotations()
+			if a == nil {
+				a = make(map[string]string)
+			}
+			a[types.DependsOnAnnotation] = joinUnique(
+				a[types.DependsOnAnnotation], d.Dependencies)
+			r.SetAnnotations(a)
+		}
+	}
+	return nil
+}
+
+// resolveDependsOn replaces the DependsOnAnnotation of
+// each resource with the ApplyDependsOnAnnotation,
+// naming the resources depended on as they're output,
+// and fails if one isn't in the output.
+func resolveDependsOn(m resmap.ResMap) error {
+	var problems []string
+	for _, r := range m.Resources() {
+		a := r.GetAnnotations()
+		v, ok := a[types.DependsOnAnnotation]
+		if !ok {
+			continue
+		}
+		var ids []string
+		for _, ref := range splitList(v) {
+			target, err := resolveRef(m, r, ref)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf(
+					"%s '%s' depends on %s: %v",
+					r.GetKind(), r.GetName(), ref, err))
+				continue
+			}
+			ids = append(ids, applyToolId(target))
+		}
+		delete(a, types.DependsOnAnnotation)
+		a[types.ApplyDependsOnAnnotation] = joinUnique(
+			a[types.ApplyDependsOnAnnotation], ids)
+		r.SetAnnotations(a)
+	}
+	if len(problems) > 0 {
+		return kusterr.WithClass(kusterr.ClassValidation, messages.Errorf(
+			messages.DependenciesNotInOutput,
+			strings.Join(problems, "\n  ")))
+	}
+	return nil
+}
+
+// matchRef returns the resources of kind and name
+// ref, as Kind/name, by their original or current name.
+func matchRef(m resmap.ResMap, ref string) ([]*resource.Resource, error) {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, kusterr.WithClass(kusterr.ClassUsage, fmt.Errorf(
+			"dependsOn: '%s' should be Kind/name", ref))
+	}
+	var result []*resource.Resource
+	for _, r := range m.Resources() {
+		if r.GetKind() == parts[0] &&
+			(r.GetOriginalName() == parts[1] || r.GetName() == parts[1]) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+// resolveRef returns the resource ref names, preferring
+// one in the namespace of the dependent r if several match.
+func resolveRef(
+	m resmap.ResMap, r *resource.Resource,
+	ref string) (*resource.Resource, error) {
+	matches, err := matchRef(m, ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) > 1 {
+		var same []*resource.Resource
+		for _, x := range matches {
+			if x.GetNamespace() == r.GetNamespace() {
+				same = append(same, x)
+			}
+		}
+		matches = same
+	}
+	switch {
+	case len(matches) == 0:
+		return nil, fmt.Errorf("no such resource")
+	case len(matches) > 1:
+		return nil, fmt.Errorf("%d resources match", len(matches))
+	case matches[0] == r:
+		return nil, fmt.Errorf("a resource can't depend on itself")
+	}
+	return matches[0], nil
+}
+
+// applyToolId names a resource as apply tools expect,
+// group/namespaces/namespace/kind/name, or, if cluster
+// scoped, group/kind/name.  A resource without a
+// namespace is taken to be in "default".
+func applyToolId(r *resource.Resource) string {
+	id := r.CurId()
+	if !id.IsNamespaceableKind() {
+		return id.Group + "/" + id.Kind + "/" + r.GetName()
+	}
+	ns := r.GetNamespace()
+	if ns == "" {
+		ns = "default"
+	}
+	return id.Group + "/namespaces/" + ns + "/" + id.Kind + "/" + r.GetName()
+}
+
+func splitList(list string) []string {
+	var result []string
+	for _, s := range strings.Split(list, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// joinUnique appends the values missing
+// from the comma separated list to it.
+func joinUnique(list string, values []string) string {
+	result := splitList(list)
+	for _, v := range values {
+		found := false
+		for _, x := range result {
+			if x == v {
+				found = true
+			}
+		}
+		if !found {
+			result = append(result, v)
+		}
+	}
+	return strings.Join(result, ",")
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeDependsOnBase(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- resources.yaml
+dependsOn:
+- resource: Deployment/app
+  dependencies:
+  - StatefulSet/db
+`)
+	th.WriteF("/app/base/resources.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  annotations:
+    kustomize.config.k8s.io/depends-on: ConfigMap/config
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.example.com
+`)
+}
+
+func TestDependsOn(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	writeDependsOnBase(th)
+	th.WriteK("/app/overlay", `
+namePrefix: prod-
+namespace: prod
+resources:
+- ../base
+dependsOn:
+- resource: StatefulSet/db
+  dependencies:
+  - CustomResourceDefinition/crontabs.example.com
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    config.kubernetes.io/depends-on: /namespaces/prod/ConfigMap/prod-config,apps/namespaces/prod/StatefulSet/prod-db
+  name: prod-app
+  namespace: prod
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  annotations:
+    config.kubernetes.io/depends-on: apiextensions.k8s.io/CustomResourceDefinition/crontabs.example.com
+  name: prod-db
+  namespace: prod
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: prod-config
+  namespace: prod
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.example.com
+`)
+}
+
+func TestDependsOnMissing(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	writeDependsOnBase(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+dependsOn:
+- resource: StatefulSet/db
+  dependencies:
+  - Service/db
+  - StatefulSet/db
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	for _, s := range []string{
+		"StatefulSet 'db' depends on Service/db: no such resource",
+		"StatefulSet 'db' depends on StatefulSet/db: a resource can't depend on itself",
+	} {
+		if !strings.Contains(err.Error(), s) {
+			t.Fatalf("expected %q in error: %v", s, err)
+		}
+	}
+
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+dependsOn:
+- resource: Job/migrate
+  dependencies:
+  - StatefulSet/db
+`)
+	_, err = th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil || !strings.Contains(err.Error(), "no resource Job/migrate") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
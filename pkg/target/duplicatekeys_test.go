@@ -0,0 +1,143 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/internal/loadertest"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+)
+
+func writeDuplicateKeys(th *kusttest_test.KustTestHarness) {
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  replicas: 2
+`)
+	th.WriteF("/app/patch.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`)
+	th.WriteF("/app/ops.yaml", `
+- op: add
+  path: /metadata/labels
+  value:
+    app: web
+`)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patchesStrategicMerge:
+- patch.yaml
+patchesJson6902:
+- target:
+    group: apps
+    version: v1
+    kind: Deployment
+    name: web
+  path: ops.yaml
+`)
+}
+
+func TestDuplicateKeysLastWins(t *testing.T) {
+	for _, d := range []resource.DuplicateKeys{
+		resource.DuplicateKeysLastWins, resource.DuplicateKeysWarn} {
+		th := kusttest_test.NewKustTestHarness(t, "/app")
+		writeDuplicateKeys(th)
+		th.SetResourceOptions(resource.Options{DuplicateKeys: d})
+		m, err := th.MakeKustTarget().MakeCustomizedResMap()
+		if err != nil {
+			t.Fatalf("%s: Err: %v", d, err)
+		}
+		th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+  name: web
+spec:
+  replicas: 3
+`)
+	}
+}
+
+func TestDuplicateKeysError(t *testing.T) {
+	for file, expected := range map[string]string{
+		"deployment.yaml": `deployment.yaml has duplicate keys: ` +
+			`line 8: key "replicas" already set in map`,
+		"patch.yaml": `patch.yaml has duplicate keys: ` +
+			`line 6: key "name" already set in map`,
+		"ops.yaml": `ops.yaml has duplicate keys: ` +
+			`line 3: key "op" already set in map`,
+	} {
+		th := kusttest_test.NewKustTestHarness(t, "/app")
+		th.SetResourceOptions(
+			resource.Options{DuplicateKeys: resource.DuplicateKeysError})
+		writeDuplicateKeys(th)
+		th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+		switch file {
+		case "deployment.yaml":
+			writeDuplicateKeys(th)
+		case "patch.yaml":
+			th.WriteF("/app/patch.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  name: web
+`)
+		case "ops.yaml":
+			th.WriteF("/app/ops.yaml", `
+- op: add
+  op: replace
+  path: /spec/replicas
+  value: 4
+`)
+		}
+		_, err := th.MakeKustTarget().MakeCustomizedResMap()
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("%s: unexpected err: %v", file, err)
+		}
+	}
+}
+
+func TestDuplicateKeysInKustomization(t *testing.T) {
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil).WithOptions(
+		resource.Options{DuplicateKeys: resource.DuplicateKeysError})
+	ldr := loadertest.NewFakeLoader("/app")
+	ldr.AddFile("/app/kustomization.yaml", []byte(`
+resources:
+- deployment.yaml
+resources:
+- service.yaml
+`))
+	_, err := target.NewKustTarget(ldr, rf, nil, nil)
+	if err == nil || err.Error() != "/app/kustomization.yaml has duplicate "+
+		`keys: line 5: key "resources" already set in map` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+)
+
+// encryptedFieldsKeeper runs the named transformer, then
+// sets back, with a warning, the encrypted fields it
+// changed, as a change would corrupt their payloads.
+type encryptedFieldsKeeper struct {
+	t    transformers.Transformer
+	name string
+}
+
+func (k *encryptedFieldsKeeper) Transform(m resmap.ResMap) error {
+	if err := k.t.Transform(m); err != nil {
+		return err
+	}
+	for _, r := range m.Resources() {
+		for _, p := range r.RestoreEncryptedFields() {
+			log.Printf(
+				"warning: %s changed encrypted field %s of %s %s; kept as it was",
+				k.name, p, r.GetKind(), r.GetName())
+		}
+	}
+	return nil
+}
+
+// keepEncryptedFields wraps the transformers in
+// encryptedFieldsKeepers, if any resource of m has
+// encrypted fields.
+func keepEncryptedFields(m resmap.ResMap,
+	ts []transformers.Transformer, names []string) []transformers.Transformer {
+	if !anyEncrypted(m) {
+		return ts
+	}
+	result := make([]transformers.Transformer, len(ts))
+	for i, t := range ts {
+		result[i] = &encryptedFieldsKeeper{t: t, name: names[i]}
+	}
+	return result
+}
+
+func anyEncrypted(m resmap.ResMap) bool {
+	for _, r := range m.Resources() {
+		if r.HasEncryptedFields() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEncryptedFields fails if the build changed
+// any encrypted field, e.g. by a var.
+func checkEncryptedFields(m resmap.ResMap) error {
+	var changed []string
+	for _, r := range m.Resources() {
+		for _, p := range r.ChangedEncryptedFields() {
+			changed = append(changed, fmt.Sprintf(
+				"%s %s: %s", r.GetKind(), r.GetName(), p))
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return kusterr.WithClass(kusterr.ClassValidation, messages.Errorf(
+		messages.EncryptedFieldsChanged, strings.Join(changed, "\n")))
+}
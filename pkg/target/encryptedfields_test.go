@@ -0,0 +1,162 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestEncryptedFieldsKept(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteK("/app/base", `
+resources:
+- secret.yaml
+- sealed.yaml
+- sops.yaml
+`)
+	th.WriteF("/app/base/secret.yaml", `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+  annotations:
+    kustomize.config.k8s.io/encrypted-fields: data/password
+data:
+  user: YWRtaW4=
+  password: ZW5jcnlwdGVk
+`)
+	th.WriteF("/app/base/sealed.yaml", `
+apiVersion: bitnami.com/v1alpha1
+kind: SealedSecret
+metadata:
+  name: api
+spec:
+  encryptedData:
+    token: AgBy3i4OJSWK
+`)
+	th.WriteF("/app/base/sops.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  key: ENC[AES256_GCM,data:Tr7o,iv:1=,tag:2=,type:str]
+  mode: fast
+sops:
+  mac: ENC[AES256_GCM,data:p673,iv:3=,tag:4=,type:str]
+  version: 3.4.0
+`)
+	th.WriteK("/app/overlay", `
+namePrefix: prod-
+resources:
+- ../base
+patchesStrategicMerge:
+- patch.yaml
+`)
+	th.WriteF("/app/overlay/patch.yaml", `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+data:
+  user: cm9vdA==
+  password: cGxhaW4=
+---
+apiVersion: bitnami.com/v1alpha1
+kind: SealedSecret
+metadata:
+  name: api
+spec:
+  encryptedData:
+    token: plain
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  key: plain
+  mode: slow
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  password: ZW5jcnlwdGVk
+  user: cm9vdA==
+kind: Secret
+metadata:
+  name: prod-db
+---
+apiVersion: bitnami.com/v1alpha1
+kind: SealedSecret
+metadata:
+  name: prod-api
+spec:
+  encryptedData:
+    token: AgBy3i4OJSWK
+---
+apiVersion: v1
+data:
+  key: ENC[AES256_GCM,data:Tr7o,iv:1=,tag:2=,type:str]
+  mode: slow
+kind: ConfigMap
+metadata:
+  name: prod-app
+sops:
+  mac: ENC[AES256_GCM,data:p673,iv:3=,tag:4=,type:str]
+  version: 3.4.0
+`)
+}
+
+func TestEncryptedFieldsChangedByVar(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteK("/app/overlay", `
+resources:
+- deployment.yaml
+- service.yaml
+vars:
+- name: SVC
+  objref:
+    kind: Service
+    name: backend
+    apiVersion: v1
+`)
+	th.WriteF("/app/overlay/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  annotations:
+    kustomize.config.k8s.io/encrypted-fields: spec/template/spec/containers/0/args
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+        args:
+        - $(SVC)
+`)
+	th.WriteF("/app/overlay/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: backend
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"Deployment app: spec/template/spec/containers/0/args") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/accumulator"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+// checkExpectations checks the output of the build
+// against the expectations of the kustomization,
+// returning an error listing each that fails.
+func (kt *KustTarget) checkExpectations(m resmap.ResMap) error {
+	if len(kt.kustomization.Expectations) == 0 {
+		return nil
+	}
+	ra := accumulator.MakeEmptyAccumulator()
+	err := kt.accumulateResources(ra, kt.kustomization.Expectations)
+	if err != nil {
+		return err
+	}
+	var failures []string
+	for _, res := range ra.ResMap().Resources() {
+		e, err := kt.pLdr.LoadExpectation(kt.ldr, res)
+		if err != nil {
+			return err
+		}
+		if err := e.Check(m); err != nil {
+			failures = append(failures, fmt.Sprintf(
+				"%s %s: %v", res.GetKind(), res.GetName(), err))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return kusterr.WithClass(kusterr.ClassValidation, messages.Errorf(
+		messages.ExpectationsFailed,
+		len(failures), ra.ResMap().Size(),
+		strings.Join(failures, "\n  ")))
+}
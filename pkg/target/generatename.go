@@ -0,0 +1,49 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/hasher"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// generateNamer names the resources the API server
+// would, those with a generateName and no name, by
+// appending the suffix, with any types.HashPlaceholder
+// replaced, to their generateName.
+type generateNamer struct {
+	suffix string
+}
+
+func (g generateNamer) Transform(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		prefix := r.GetGenerateName()
+		if r.GetName() != "" || prefix == "" {
+			continue
+		}
+		name := prefix + g.suffix
+		if strings.Contains(g.suffix, types.HashPlaceholder) {
+			j, err := r.MarshalJSON()
+			if err != nil {
+				return err
+			}
+			h, err := hasher.Encode(hasher.Hash(string(j)))
+			if err != nil {
+				return err
+			}
+			name = prefix + strings.Replace(
+				g.suffix, types.HashPlaceholder, h, -1)
+		}
+		r.SetName(name)
+		obj := r.Map()
+		if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+			delete(meta, "generateName")
+		}
+		r.SetMap(obj)
+	}
+	return nil
+}
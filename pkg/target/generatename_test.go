@@ -0,0 +1,156 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+const generateNameJobs = `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  generateName: migrate-
+spec:
+  template:
+    spec:
+      containers:
+      - name: migrate
+        image: migrate:v1
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  generateName: backup-
+spec:
+  template:
+    spec:
+      containers:
+      - name: backup
+        image: backup:v1
+`
+
+func writeGenerateNameBase(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- jobs.yaml
+`)
+	th.WriteF("/app/base/jobs.yaml", generateNameJobs)
+}
+
+func TestGenerateName(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	writeGenerateNameBase(th)
+	th.WriteK("/app/overlay", `
+namePrefix: prod-
+nameSuffix: -v2
+namespace: prod
+commonLabels:
+  env: prod
+resources:
+- ../base
+patchesStrategicMerge:
+- patch.yaml
+`)
+	th.WriteF("/app/overlay/patch.yaml", `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  generateName: migrate-
+spec:
+  backoffLimit: 1
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  generateName: prod-migrate-v2-
+  labels:
+    env: prod
+  namespace: prod
+spec:
+  backoffLimit: 1
+  template:
+    metadata:
+      labels:
+        env: prod
+    spec:
+      containers:
+      - image: migrate:v1
+        name: migrate
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  generateName: prod-backup-v2-
+  labels:
+    env: prod
+  namespace: prod
+spec:
+  template:
+    metadata:
+      labels:
+        env: prod
+    spec:
+      containers:
+      - image: backup:v1
+        name: backup
+`)
+}
+
+func TestGenerateNameSuffix(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	writeGenerateNameBase(th)
+	th.WriteK("/app/overlay", `
+generateNameSuffix: "{hash}"
+resources:
+- ../base
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, r := range m.Resources() {
+		if r.GetGenerateName() != "" {
+			t.Fatalf("expected no generateName, got %s", r.GetGenerateName())
+		}
+	}
+	names := namesByGenerateName(m)
+	if len(names["migrate-"]) != len("migrate-")+10 ||
+		len(names["backup-"]) != len("backup-")+10 {
+		t.Fatalf("unexpected names %v", names)
+	}
+
+	// The name changes with the content.
+	th.WriteF("/app/base/jobs.yaml", strings.Replace(
+		generateNameJobs, "migrate:v1", "migrate:v2", 1))
+	m, err = th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	changed := namesByGenerateName(m)
+	if changed["migrate-"] == names["migrate-"] ||
+		changed["backup-"] != names["backup-"] {
+		t.Fatalf("expected only the migrate Job renamed, got %v", changed)
+	}
+}
+
+// namesByGenerateName returns the names of the resources
+// of m, by the prefix they start with.
+func namesByGenerateName(m resmap.ResMap) map[string]string {
+	result := make(map[string]string)
+	for _, r := range m.Resources() {
+		n := r.GetName()
+		result[n[:strings.Index(n, "-")+1]] = n
+	}
+	return result
+}
@@ -113,3 +113,34 @@ metadata:
   name: shouldHaveHash-2k9hc848ff
 `)
 }
+
+func TestGeneratorOptionsHashIncludes(t *testing.T) {
+	names := make(map[string]bool)
+	for _, options := range []string{``, `
+generatorOptions:
+  hashIncludes:
+  - labels
+`} {
+		for _, env := range []string{"prod", "dev"} {
+			th := kusttest_test.NewKustTestHarness(t, "/app")
+			th.WriteK("/app", `
+commonLabels:
+  env: `+env+`
+configMapGenerator:
+- name: config
+  literals:
+  - MODE=fast
+`+options)
+			m, err := th.MakeKustTarget().MakeCustomizedResMap()
+			if err != nil {
+				t.Fatalf("Err: %v", err)
+			}
+			names[m.Resources()[0].GetName()] = true
+		}
+	}
+	// Only with the labels hashed do their
+	// values change the name.
+	if len(names) != 3 {
+		t.Fatalf("expected 3 names, got %v", names)
+	}
+}
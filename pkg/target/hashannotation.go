@@ -0,0 +1,139 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// hashAnnotator writes the GeneratedHashAnnotation to
+// the pod templates referring, by the fields given in
+// the name references, to generated resources with the
+// HashAnnotation option.
+type hashAnnotator struct {
+	hasher ifc.KunstructuredHasher
+	refs   []config.NameBackReferences
+}
+
+// podTemplate is the pod template, at path, of a resource.
+type podTemplate struct {
+	r    *resource.Resource
+	path string
+}
+
+func (ha *hashAnnotator) Transform(m resmap.ResMap) error {
+	hashes := make(map[podTemplate][]string)
+	var templates []podTemplate
+	for _, r := range m.Resources() {
+		if !r.NeedHashAnnotation() {
+			continue
+		}
+		h, err := ha.hasher.Hash(r)
+		if err != nil {
+			return err
+		}
+		entry := r.GetKind() + "/" + r.GetName() + "=" + h
+		for _, t := range ha.referringTemplates(m, r) {
+			if _, ok := hashes[t]; !ok {
+				templates = append(templates, t)
+			}
+			hashes[t] = append(hashes[t], entry)
+		}
+	}
+	for _, t := range templates {
+		entries := hashes[t]
+		sort.Strings(entries)
+		sum := sha256.Sum256([]byte(strings.Join(entries, ",")))
+		tmpl, err := t.r.PodTemplateAt(strings.Split(t.path, "/"))
+		if err != nil {
+			return err
+		}
+		err = tmpl.SetAnnotation(
+			types.GeneratedHashAnnotation, fmt.Sprintf("%x", sum[:5]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// referringTemplates returns the pod templates holding
+// references to the given resource.
+func (ha *hashAnnotator) referringTemplates(
+	m resmap.ResMap, target *resource.Resource) []podTemplate {
+	var result []podTemplate
+	for _, backRef := range ha.refs {
+		if !target.GetGvk().IsSelected(&backRef.Gvk) {
+			continue
+		}
+		for _, fs := range backRef.FieldSpecs {
+			path := fs.PathSlice()
+			i := templateIndex(path)
+			if i < 0 {
+				continue
+			}
+			for _, r := range m.Resources() {
+				if !r.GetGvk().IsSelected(&fs.Gvk) ||
+					r.GetNamespace() != target.GetNamespace() ||
+					!refersTo(r.Map(), path, target.GetName()) {
+					continue
+				}
+				t := podTemplate{r: r, path: strings.Join(path[:i+1], "/")}
+				if !containsTemplate(result, t) {
+					result = append(result, t)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// templateIndex returns the index in a path of the
+// pod template holding the field, or -1.
+func templateIndex(path []string) int {
+	for i := len(path) - 2; i >= 0; i-- {
+		if path[i] == "template" && path[i+1] == "spec" {
+			return i
+		}
+	}
+	return -1
+}
+
+func containsTemplate(ts []podTemplate, t podTemplate) bool {
+	for _, x := range ts {
+		if x == t {
+			return true
+		}
+	}
+	return false
+}
+
+// refersTo returns true if the field at the path,
+// through any lists, holds the name.
+func refersTo(node interface{}, path []string, name string) bool {
+	if len(path) == 0 {
+		s, ok := node.(string)
+		return ok && s == name
+	}
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		return refersTo(typed[strings.TrimSuffix(path[0], "[]")], path[1:], name)
+	case []interface{}:
+		for _, item := range typed {
+			if refersTo(item, path, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+func writeHashAnnotationApp(th *kusttest_test.KustTestHarness, value string) {
+	th.WriteK("/app", `
+namePrefix: p-
+generatorOptions:
+  hashAnnotation: true
+configMapGenerator:
+- name: config
+  literals:
+  - key=`+value+`
+secretGenerator:
+- name: secret
+  literals:
+  - password=secret
+resources:
+- deployment.yaml
+- service.yaml
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:1
+        envFrom:
+        - configMapRef:
+            name: config
+      volumes:
+      - name: secret
+        secret:
+          secretName: secret
+`)
+	th.WriteF("/app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`)
+}
+
+func TestHashAnnotation(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeHashAnnotationApp(th, "a")
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: p-web
+spec:
+  template:
+    metadata:
+      annotations:
+        config.kubernetes.io/generated-hash: 7da9ff1dcb
+    spec:
+      containers:
+      - envFrom:
+        - configMapRef:
+            name: p-config
+        image: web:1
+        name: web
+      volumes:
+      - name: secret
+        secret:
+          secretName: p-secret
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: p-web
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: v1
+data:
+  key: a
+kind: ConfigMap
+metadata:
+  name: p-config
+---
+apiVersion: v1
+data:
+  password: c2VjcmV0
+kind: Secret
+metadata:
+  name: p-secret
+type: Opaque
+`)
+}
+
+func TestHashAnnotationChangesWithContent(t *testing.T) {
+	annotation := func(value string) string {
+		th := kusttest_test.NewKustTestHarness(t, "/app")
+		writeHashAnnotationApp(th, value)
+		m, err := th.MakeKustTarget().MakeCustomizedResMap()
+		if err != nil {
+			t.Fatalf("Err: %v", err)
+		}
+		r, err := m.GetByCurrentId(resid.NewResId(
+			gvk.Gvk{Group: "apps", Version: "v1", Kind: "Deployment"}, "p-web"))
+		if err != nil {
+			t.Fatalf("Err: %v", err)
+		}
+		a, err := r.GetFieldValue("spec.template.metadata.annotations")
+		if err != nil {
+			t.Fatalf("Err: %v", err)
+		}
+		return a.(map[string]interface{})[types.GeneratedHashAnnotation].(string)
+	}
+	if annotation("a") == annotation("b") {
+		t.Fatalf("expected the annotation to change with the ConfigMap")
+	}
+}
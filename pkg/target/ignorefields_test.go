@@ -0,0 +1,79 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestIgnoreFields(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteK("/app/base", `
+resources:
+- service.yaml
+- deployment.yaml
+`)
+	th.WriteF("/app/base/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  clusterIP: 10.0.0.1
+  ports:
+  - port: 80
+    nodePort: 30080
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  annotations:
+    deployment.kubernetes.io/revision: "3"
+spec:
+  replicas: 2
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+patches:
+- target:
+    kind: Service
+  patch: |-
+    - op: replace
+      path: /spec/clusterIP
+      value: 10.0.0.2
+ignoreFields:
+- target:
+    kind: Service
+  paths:
+  - spec/clusterIP
+  - spec/ports/nodePort
+- paths:
+  - metadata/annotations/deployment.kubernetes.io\/revision
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+`)
+}
@@ -4,8 +4,10 @@
 package target_test
 
 import (
-	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"strings"
 	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
 )
 
 func makeResourcesForPatchTest(th *kusttest_test.KustTestHarness) {
@@ -139,6 +141,41 @@ spec:
 `)
 }
 
+func TestJSONPatchInlineTestFails(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/base")
+	makeResourcesForPatchTest(th)
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+
+patchesJson6902:
+- target:
+    group: apps
+    version: v1
+    kind: Deployment
+    name: nginx
+  patch: |-
+    - op: test
+      path: /spec/template/spec/containers/0/name
+      value: nginx
+    - op: test
+      path: /spec/template/spec/containers/0/image
+      value: nginx:1.7
+    - op: replace
+      path: /spec/template/spec/containers/0/image
+      value: image1
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		"json patch operation [1] test /spec/template/spec/containers/0/image "+
+			"failed: expected \"nginx:1.7\", found \"nginx\"") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestExtendedPatchInlineJSON(t *testing.T) {
 	th := kusttest_test.NewKustTestHarness(t, "/app/base")
 	makeResourcesForPatchTest(th)
@@ -0,0 +1,63 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+)
+
+// Failure is a part of a build that KeepGoing skipped.
+type Failure struct {
+	// Path is the resource file, base or kustomization
+	// file that failed, relative to the kustomization
+	// built.
+	Path  string `json:"path"`
+	Error string `json:"error"`
+	class kusterr.Class
+}
+
+// KeepGoing makes the build skip the resource files,
+// bases and generators that fail, rather than stop, so
+// it builds all it can.  Failures lists what it skipped.
+func (kt *KustTarget) KeepGoing() {
+	kt.failures = &[]Failure{}
+}
+
+// Failures returns the parts of the build KeepGoing skipped,
+// in the order they failed.
+func (kt *KustTarget) Failures() []Failure {
+	if kt.failures == nil {
+		return nil
+	}
+	return *kt.failures
+}
+
+// FailuresClass is the class of the failures, if they
+// share one.
+func FailuresClass(failures []Failure) kusterr.Class {
+	if len(failures) == 0 {
+		return kusterr.ClassUnknown
+	}
+	c := failures[0].class
+	for _, f := range failures[1:] {
+		if f.class != c {
+			return kusterr.ClassUnknown
+		}
+	}
+	return c
+}
+
+// skip records the failure of path, relative to kt's
+// kustomization, and returns true, if keeping going.
+func (kt *KustTarget) skip(path string, err error) bool {
+	if kt.failures == nil {
+		return false
+	}
+	*kt.failures = append(*kt.failures, Failure{
+		Path:  joinOrigin(kt.origin, path),
+		Error: err.Error(),
+		class: kusterr.ClassOf(err),
+	})
+	return true
+}
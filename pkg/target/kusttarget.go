@@ -9,14 +9,25 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/accumulator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/image"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/oci"
+	"sigs.k8s.io/kustomize/v3/pkg/openapi"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
 	"sigs.k8s.io/kustomize/v3/pkg/plugins"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
@@ -24,6 +35,12 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
+// ErrNotKustomization is the kind of the errors of
+// directories, built or included, holding no kustomization
+// file, for programs building them to test for with
+// errors.Is.
+var ErrNotKustomization = errors.New("not a kustomization")
+
 // KustTarget encapsulates the entirety of a kustomization build.
 type KustTarget struct {
 	kustomization *types.Kustomization
@@ -31,6 +48,30 @@ type KustTarget struct {
 	rFactory      *resmap.Factory
 	tFactory      resmap.PatchFactory
 	pLdr          *plugins.Loader
+	// kustFile is the name of the kustomization file.
+	kustFile string
+	// origin is the path of this kustomization's directory
+	// relative to the kustomization being built, or empty
+	// for the latter.
+	origin string
+	// buildMetadata holds the buildMetadata values of this
+	// kustomization and of those including it.
+	buildMetadata []string
+	// failures, shared by the targets of a build, collects
+	// the parts skipped under KeepGoing; nil if not.
+	failures *[]Failure
+	// values are those of the catalogs of valuesFrom,
+	// and setValues, once loaded.
+	values    map[string]string
+	setValues map[string]string
+	// namespace and nameSuffix are those of the
+	// kustomization, or as namespaceFrom derives them,
+	// once values are loaded.
+	namespace  string
+	nameSuffix string
+	// opts, shared by the targets of a build, say how
+	// strictly it checks what it builds.
+	opts Options
 }
 
 // NewKustTarget returns a new instance of KustTarget primed with a Loader.
@@ -39,22 +80,23 @@ func NewKustTarget(
 	rFactory *resmap.Factory,
 	tFactory resmap.PatchFactory,
 	pLdr *plugins.Loader) (*KustTarget, error) {
-	content, err := loadKustFile(ldr)
+	content, kustFile, err := loadKustFile(ldr, rFactory)
 	if err != nil {
-		return nil, err
+		return nil, kusterr.WithClass(kusterr.ClassLoad, err)
 	}
 	content = types.FixKustomizationPreUnmarshalling(content)
 	var k types.Kustomization
 	err = unmarshal(content, &k)
 	if err != nil {
-		return nil, err
+		return nil, kusterr.WithClass(kusterr.ClassValidation,
+			messages.WithID(messages.KustomizationInvalid, err))
 	}
 	k.FixKustomizationPostUnmarshalling()
 	errs := k.EnforceFields()
 	if len(errs) > 0 {
-		return nil, fmt.Errorf(
-			"Failed to read kustomization file under %s:\n"+
-				strings.Join(errs, "\n"), ldr.Root())
+		return nil, kusterr.WithClass(kusterr.ClassValidation, messages.Errorf(
+			messages.KustomizationFieldsInvalid,
+			ldr.Root(), strings.Join(errs, "\n")))
 	}
 	return &KustTarget{
 		kustomization: &k,
@@ -62,6 +104,8 @@ func NewKustTarget(
 		rFactory:      rFactory,
 		tFactory:      tFactory,
 		pLdr:          pLdr,
+		kustFile:      kustFile,
+		buildMetadata: k.BuildMetadata,
 	}, nil
 }
 
@@ -77,25 +121,37 @@ func commaOr(q []string) string {
 	return strings.Join(q[:len(q)-1], ", ") + " or " + q[len(q)-1]
 }
 
-func loadKustFile(ldr ifc.Loader) ([]byte, error) {
+// loadKustFile loads the kustomization file in the root
+// of ldr, checking it for duplicate keys as rf's options
+// say, if there's an rf; targets made only to validate
+// kustomizations may have none.
+func loadKustFile(
+	ldr ifc.Loader, rf *resmap.Factory) ([]byte, string, error) {
 	var content []byte
+	var name string
 	match := 0
 	for _, kf := range pgmconfig.KustomizationFileNames {
 		c, err := ldr.Load(kf)
 		if err == nil {
 			match += 1
 			content = c
+			name = kf
 		}
 	}
 	switch match {
 	case 0:
-		return nil, fmt.Errorf(
-			"unable to find one of %v in directory '%s'",
-			commaOr(quoted(pgmconfig.KustomizationFileNames)), ldr.Root())
+		return nil, "", kusterr.WithKind(ErrNotKustomization, messages.Errorf(
+			messages.NotKustomization,
+			commaOr(quoted(pgmconfig.KustomizationFileNames)), ldr.Root()))
 	case 1:
-		return content, nil
+		if rf == nil {
+			return content, name, nil
+		}
+		err := rf.RF().CheckDuplicateKeys(
+			filepath.Join(ldr.Root(), name), content)
+		return content, name, err
 	default:
-		return nil, fmt.Errorf(
+		return nil, "", fmt.Errorf(
 			"Found multiple kustomization files under: %s\n", ldr.Root())
 	}
 }
@@ -110,6 +166,12 @@ func unmarshal(y []byte, o interface{}) error {
 	return dec.Decode(o)
 }
 
+// Root is the directory of the target's kustomization,
+// as its loader sees it.
+func (kt *KustTarget) Root() string {
+	return kt.ldr.Root()
+}
+
 // MakeCustomizedResMap creates a ResMap per kustomization instructions.
 // The Resources in the returned ResMap are fully customized.
 func (kt *KustTarget) MakeCustomizedResMap() (resmap.ResMap, error) {
@@ -120,8 +182,98 @@ func (kt *KustTarget) MakePruneConfigMap() (resmap.ResMap, error) {
 	return kt.makeCustomizedResMap(types.GarbageCollect)
 }
 
+// Clusters returns the names of the clusters
+// the kustomization declares.
+func (kt *KustTarget) Clusters() []string {
+	var result []string
+	for _, c := range kt.kustomization.Clusters {
+		result = append(result, c.Name)
+	}
+	return result
+}
+
+// ForCluster returns a target building the kustomization
+// with the overrides of the named cluster.
+func (kt *KustTarget) ForCluster(name string) (*KustTarget, error) {
+	for _, c := range kt.kustomization.Clusters {
+		if c.Name == name {
+			return kt.WithOverrides(c), nil
+		}
+	}
+	return nil, fmt.Errorf("kustomization has no cluster %s", name)
+}
+
+// WithOverrides returns a target building the kustomization
+// with the overrides of c, as if it were one of its clusters.
+func (kt *KustTarget) WithOverrides(c types.Cluster) *KustTarget {
+	k := *kt.kustomization
+	k.Clusters = nil
+	if c.Namespace != "" {
+		k.Namespace = c.Namespace
+		k.NamespaceFrom = nil
+	}
+	if len(c.CommonLabels) > 0 {
+		k.CommonLabels = make(map[string]string)
+		for key, v := range kt.kustomization.CommonLabels {
+			k.CommonLabels[key] = v
+		}
+		for key, v := range c.CommonLabels {
+			k.CommonLabels[key] = v
+		}
+	}
+	k.Images = mergeImages(kt.kustomization.Images, c.Images)
+	result := *kt
+	result.kustomization = &k
+	return &result
+}
+
+// mergeImages returns the images, replacing those
+// with the name of an override by the override,
+// followed by the remaining overrides.
+func mergeImages(images, overrides []image.Image) []image.Image {
+	result := append([]image.Image{}, images...)
+	for _, o := range overrides {
+		found := false
+		for i := range result {
+			if result[i].Name == o.Name {
+				result[i] = o
+				found = true
+			}
+		}
+		if !found {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+// MakeExporters returns the exporters of the kustomization,
+// which render the output of MakeCustomizedResMap in their
+// own formats.
+func (kt *KustTarget) MakeExporters() ([]transformers.Exporter, error) {
+	ra := accumulator.MakeEmptyAccumulator()
+	err := kt.accumulateResources(ra, kt.kustomization.Exporters)
+	if err != nil {
+		return nil, err
+	}
+	return kt.pLdr.LoadExporters(kt.ldr, ra.ResMap())
+}
+
 func (kt *KustTarget) makeCustomizedResMap(
 	garbagePolicy types.GarbagePolicy) (resmap.ResMap, error) {
+	if o := kt.rFactory.RF().Options(); o.Schema == nil {
+		// Nothing, e.g. a command line flag, chose a schema.
+		s, err := kt.openAPISchema()
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			o.Schema = s
+			kt.rFactory = kt.rFactory.WithOptions(o)
+			kt.pLdr = kt.pLdr.WithFactory(kt.rFactory)
+		}
+	}
+
 	ra, err := kt.AccumulateTarget()
 	if err != nil {
 		return nil, err
@@ -143,19 +295,88 @@ func (kt *KustTarget) makeCustomizedResMap(
 	}
 
 	// With all the back references fixed, it's OK to resolve Vars.
+	if kt.opts.Strict {
+		ra.FailOnUnusedVars()
+	}
 	err = ra.ResolveVars()
 	if err != nil {
 		return nil, err
 	}
 
+	// Pod templates referring to generated resources that keep
+	// their names get the hash of those resources instead.
+	err = ra.Transform(&hashAnnotator{
+		hasher: kt.rFactory.RF().Hasher(),
+		refs:   ra.GetTransformerConfig().NameReference,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkEncryptedFields(ra.ResMap())
+	if err != nil {
+		return nil, err
+	}
+
+	err = ra.Transform(localConfigRemover{})
+	if err != nil {
+		return nil, err
+	}
+
+	if kt.kustomization.GenerateNameSuffix != "" {
+		err = ra.Transform(generateNamer{
+			suffix: kt.kustomization.GenerateNameSuffix})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = resolveDependsOn(ra.ResMap())
+	if err != nil {
+		return nil, err
+	}
+
 	err = kt.computeInventory(ra, garbagePolicy)
 	if err != nil {
 		return nil, err
 	}
 
+	err = kt.checkStrict(ra.ResMap())
+	if err != nil {
+		return nil, err
+	}
+
+	err = kt.checkExpectations(ra.ResMap())
+	if err != nil {
+		return nil, err
+	}
+
 	return ra.ResMap(), nil
 }
 
+// openAPISchema returns the schema named in the
+// kustomization, or nil if it names none.
+func (kt *KustTarget) openAPISchema() (*openapi.Schema, error) {
+	c := kt.kustomization.OpenAPI
+	switch {
+	case c == nil:
+		return nil, nil
+	case c.Path != "":
+		data, err := kt.ldr.Load(c.Path)
+		if err != nil {
+			return nil, err
+		}
+		s, err := openapi.NewSchema("", data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "openapi path %s", c.Path)
+		}
+		return s, nil
+	case c.Version != "":
+		return openapi.ForVersion(fs.MakeRealFS(), c.Version)
+	}
+	return nil, nil
+}
+
 func (kt *KustTarget) addHashesToNames(
 	ra *accumulator.ResAccumulator) error {
 	p := builtin.NewHashTransformerPlugin()
@@ -176,7 +397,7 @@ func (kt *KustTarget) computeInventory(
 		return fmt.Errorf("don't know how to do that")
 	}
 
-	if inv.ConfigMap.Namespace != kt.kustomization.Namespace {
+	if inv.ConfigMap.Namespace != kt.namespace {
 		return fmt.Errorf("namespace mismatch")
 	}
 
@@ -201,6 +422,32 @@ func (kt *KustTarget) shouldAddHashSuffixesToGeneratedResources() bool {
 		!kt.kustomization.GeneratorOptions.DisableNameSuffixHash
 }
 
+// mergeTransformerConfigs merges the default transformer
+// config, then each custom config in turn, into the
+// accumulator.  Behaviors in a custom config thus apply
+// to everything configured so far, including by bases.
+func (kt *KustTarget) mergeTransformerConfigs(
+	ra *accumulator.ResAccumulator) error {
+	err := ra.MergeConfig(config.MakeDefaultConfig())
+	if err != nil {
+		return errors.Wrap(err, "merging default config")
+	}
+	f := config.NewFactory(kt.ldr)
+	for _, path := range kt.kustomization.Configurations {
+		configs, err := f.ConfigsFromFile(path)
+		if err != nil {
+			return err
+		}
+		for _, tConfig := range configs {
+			err = ra.MergeConfig(tConfig)
+			if err != nil {
+				return errors.Wrapf(err, "merging config %s", path)
+			}
+		}
+	}
+	return nil
+}
+
 // AccumulateTarget returns a new ResAccumulator,
 // holding customized resources and the data/rules used
 // to do so.  The name back references and vars are
@@ -212,16 +459,10 @@ func (kt *KustTarget) AccumulateTarget() (
 	if err != nil {
 		return nil, errors.Wrap(err, "accumulating resources")
 	}
-	tConfig, err := config.MakeTransformerConfig(
-		kt.ldr, kt.kustomization.Configurations)
+	err = kt.mergeTransformerConfigs(ra)
 	if err != nil {
 		return nil, err
 	}
-	err = ra.MergeConfig(tConfig)
-	if err != nil {
-		return nil, errors.Wrapf(
-			err, "merging config %v", tConfig)
-	}
 	crdTc, err := config.LoadConfigFromCRDs(kt.ldr, kt.kustomization.Crds)
 	if err != nil {
 		return nil, errors.Wrapf(
@@ -232,6 +473,14 @@ func (kt *KustTarget) AccumulateTarget() (
 		return nil, errors.Wrapf(
 			err, "merging CRDs %v", crdTc)
 	}
+	kt.values, err = kt.loadValues()
+	if err != nil {
+		return nil, err
+	}
+	kt.namespace, kt.nameSuffix, err = kt.deriveNamespace()
+	if err != nil {
+		return nil, err
+	}
 	err = kt.runGenerators(ra)
 	if err != nil {
 		return nil, err
@@ -240,11 +489,21 @@ func (kt *KustTarget) AccumulateTarget() (
 	if err != nil {
 		return nil, err
 	}
+	err = annotateDependsOn(kt.kustomization.DependsOn, ra.ResMap())
+	if err != nil {
+		return nil, err
+	}
+	err = checkTenancy(
+		kt.kustomization.Tenancy, ra.ResMap(), kt.rFactory.MaxProcs())
+	if err != nil {
+		return nil, err
+	}
 	err = ra.MergeVars(kt.kustomization.Vars)
 	if err != nil {
 		return nil, errors.Wrapf(
 			err, "merging vars %v", kt.kustomization.Vars)
 	}
+	ra.SetValues(kt.values)
 	return ra, nil
 }
 
@@ -257,8 +516,12 @@ func (kt *KustTarget) runGenerators(
 	for _, g := range generators {
 		resMap, err := g.Generate()
 		if err != nil {
+			if kt.skip(kt.kustFile, err) {
+				continue
+			}
 			return err
 		}
+		kt.annotateOrigin(resMap, kt.kustFile)
 		// The legacy generators allow override.
 		err = ra.AbsorbAll(resMap)
 		if err != nil {
@@ -272,8 +535,10 @@ func (kt *KustTarget) runGenerators(
 	for _, g := range generators {
 		resMap, err := g.Generate()
 		if err != nil {
-			return err
+			return kusterr.WithClass(kusterr.ClassPlugin,
+				messages.WithID(messages.PluginFailed, err))
 		}
+		kt.annotateOrigin(resMap, kt.kustFile)
 		err = ra.AppendAll(resMap)
 		if err != nil {
 			return errors.Wrapf(err, "merging from generator %v", g)
@@ -299,45 +564,265 @@ func (kt *KustTarget) runTransformers(ra *accumulator.ResAccumulator) error {
 		return err
 	}
 	r = append(r, lts...)
-	lts, err = kt.configureExternalTransformers()
+	var names []string
+	for _, t := range lts {
+		names = append(names, builtinName(t))
+	}
+	lts, kinds, err := kt.configureExternalTransformers()
+	if err != nil {
+		return err
+	}
+	r = append(r, lts...)
+	names = append(names, kinds...)
+	// Fields are ignored last, so nothing can add them back.
+	lts, err = kt.configureBuiltinIgnoreFieldsTransformer()
 	if err != nil {
 		return err
 	}
 	r = append(r, lts...)
-	t := transformers.NewMultiTransformer(r)
-	return ra.Transform(t)
+	for _, t := range lts {
+		names = append(names, builtinName(t))
+	}
+	if kt.opts.Strict {
+		r = checkPatchTargets(r, names)
+	}
+	r = trackPatchConflicts(r, names, kt.opts.PatchConflicts)
+	r = keepEncryptedFields(ra.ResMap(), r, names)
+	annotate := kt.hasBuildMetadata(types.TransformerAnnotations)
+	if !annotate && !anyAnnotated(
+		ra.ResMap(), types.SkipTransformersAnnotation) {
+		t := transformers.NewMultiTransformer(r)
+		return ra.Transform(t)
+	}
+	for i, t := range r {
+		err := ra.Transform(&resourceAwareTransformer{
+			t: t, name: names[i], annotate: annotate})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (kt *KustTarget) configureExternalTransformers() ([]transformers.Transformer, error) {
+// configureExternalTransformers returns the transformer
+// plugins and the kinds of their configurations.
+func (kt *KustTarget) configureExternalTransformers() (
+	[]transformers.Transformer, []string, error) {
 	ra := accumulator.MakeEmptyAccumulator()
 	err := kt.accumulateResources(ra, kt.kustomization.Transformers)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	var kinds []string
+	for _, r := range ra.ResMap().Resources() {
+		kinds = append(kinds, r.GetKind())
 	}
-	return kt.pLdr.LoadTransformers(kt.ldr, ra.ResMap())
+	result, err := kt.pLdr.LoadTransformers(kt.ldr, ra.ResMap())
+	return result, kinds, err
+}
+
+// builtinName returns the name of a builtin
+// transformer, e.g. PrefixSuffixTransformer.
+func builtinName(t transformers.Transformer) string {
+	n := fmt.Sprintf("%T", t)
+	n = n[strings.LastIndex(n, ".")+1:]
+	return strings.TrimSuffix(n, "Plugin")
+}
+
+// resourceAwareTransformer runs the named transformer on
+// the resources not listing it in their
+// SkipTransformersAnnotation, keeping their order.
+// If annotate is true, the name is appended to the
+// TransformerAnnotation of the resources it changes.
+type resourceAwareTransformer struct {
+	t        transformers.Transformer
+	name     string
+	annotate bool
+}
+
+func (rt *resourceAwareTransformer) Transform(m resmap.ResMap) error {
+	t, name, annotate := rt.t, rt.name, rt.annotate
+	all := m.Resources()
+	skipped := make(map[int]*resource.Resource)
+	target := m
+	for i, r := range all {
+		if skipsTransformer(r, name) {
+			skipped[i] = r
+		}
+	}
+	if len(skipped) > 0 {
+		target = resmap.New()
+		for i, r := range all {
+			if _, ok := skipped[i]; ok {
+				continue
+			}
+			if err := target.Append(r); err != nil {
+				return err
+			}
+		}
+	}
+	before := make(map[*resource.Resource]*resource.Resource)
+	if annotate {
+		for _, r := range target.Resources() {
+			before[r] = r.DeepCopy()
+		}
+	}
+	err := t.Transform(target)
+	if err != nil {
+		return err
+	}
+	if annotate {
+		for _, r := range target.Resources() {
+			old, ok := before[r]
+			if ok && reflect.DeepEqual(old.Map(), r.Map()) {
+				continue
+			}
+			a := r.GetAnnotations()
+			if a == nil {
+				a = make(map[string]string)
+			}
+			if a[types.TransformerAnnotation] == "" {
+				a[types.TransformerAnnotation] = name
+			} else {
+				a[types.TransformerAnnotation] += "," + name
+			}
+			r.SetAnnotations(a)
+		}
+	}
+	if len(skipped) == 0 {
+		return nil
+	}
+	// Put the skipped resources back where they were.
+	done := target.Resources()
+	m.Clear()
+	for i := range all {
+		r, ok := skipped[i]
+		if !ok {
+			if len(done) == 0 {
+				continue
+			}
+			r, done = done[0], done[1:]
+		}
+		if err := m.Append(r); err != nil {
+			return err
+		}
+	}
+	for _, r := range done {
+		if err := m.Append(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func skipsTransformer(r *resource.Resource, name string) bool {
+	for _, n := range strings.Split(
+		r.GetAnnotations()[types.SkipTransformersAnnotation], ",") {
+		if strings.TrimSpace(n) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func anyAnnotated(m resmap.ResMap, annotation string) bool {
+	for _, r := range m.Resources() {
+		if _, ok := r.GetAnnotations()[annotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// localConfigRemover drops resources marked with a
+// LocalConfigAnnotation, and strips the annotations
+// configuring the build from the rest.
+type localConfigRemover struct{}
+
+func (localConfigRemover) Transform(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		a := r.GetAnnotations()
+		if a[types.LocalConfigAnnotation] == "true" {
+			if err := m.Remove(r.CurId()); err != nil {
+				return err
+			}
+			continue
+		}
+		changed := false
+		for _, k := range types.LocalConfigAnnotations {
+			if _, ok := a[k]; ok {
+				delete(a, k)
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if len(a) == 0 {
+			a = nil
+		}
+		r.SetAnnotations(a)
+	}
+	return nil
 }
 
 // accumulateResources fills the given resourceAccumulator
 // with resources read from the given list of paths.
 func (kt *KustTarget) accumulateResources(
 	ra *accumulator.ResAccumulator, paths []string) error {
+	if err := kt.prefetch(paths); err != nil {
+		return err
+	}
 	for _, path := range paths {
 		ldr, err := kt.ldr.New(path)
-		if err == nil {
+		switch {
+		case err == nil:
 			err = kt.accumulateDirectory(ra, ldr, path)
-			if err != nil {
-				return err
-			}
-		} else {
+		case kusterr.ClassOf(err) == kusterr.ClassRemote:
+			// Don't mask a failed fetch with a
+			// misleading "file not found".
+		default:
 			err = kt.accumulateFile(ra, path)
-			if err != nil {
-				return err
-			}
+		}
+		if err != nil && !kt.skip(path, err) {
+			return err
 		}
 	}
 	return nil
 }
 
+// prefetcher is a loader that can clone the remote
+// bases among paths at once, ahead of New.
+type prefetcher interface {
+	Prefetch(paths []string) []error
+}
+
+// prefetch clones the remote bases among paths at once,
+// if the loader can.  If more than one fails, and failures
+// aren't skipped, it returns an error listing each, rather
+// than leaving them to be found one at a time.
+func (kt *KustTarget) prefetch(paths []string) error {
+	p, ok := kt.ldr.(prefetcher)
+	if !ok {
+		return nil
+	}
+	errs := p.Prefetch(paths)
+	if kt.failures != nil {
+		return nil
+	}
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", paths[i], err))
+		}
+	}
+	if len(failed) < 2 {
+		return nil
+	}
+	return kusterr.WithClass(kusterr.ClassRemote, messages.Errorf(
+		messages.RemoteBasesFailed, len(failed), strings.Join(failed, "\n")))
+}
+
 func (kt *KustTarget) accumulateDirectory(
 	ra *accumulator.ResAccumulator, ldr ifc.Loader, path string) error {
 	defer ldr.Cleanup()
@@ -346,6 +831,10 @@ func (kt *KustTarget) accumulateDirectory(
 	if err != nil {
 		return errors.Wrapf(err, "couldn't make target for path '%s'", path)
 	}
+	subKt.origin = joinOrigin(kt.origin, path)
+	subKt.buildMetadata = append(subKt.buildMetadata, kt.buildMetadata...)
+	subKt.failures = kt.failures
+	subKt.opts = kt.opts
 	subRa, err := subKt.AccumulateTarget()
 	if err != nil {
 		return errors.Wrapf(
@@ -365,9 +854,83 @@ func (kt *KustTarget) accumulateFile(
 	if err != nil {
 		return errors.Wrapf(err, "accumulating resources from '%s'", path)
 	}
+	kt.annotateOrigin(resources, path)
+	for _, r := range resources.Resources() {
+		r.KeepEncryptedFields()
+	}
 	err = ra.AppendAll(resources)
 	if err != nil {
 		return errors.Wrapf(err, "merging resources from '%s'", path)
 	}
 	return nil
 }
+
+func (kt *KustTarget) hasBuildMetadata(option string) bool {
+	for _, m := range kt.buildMetadata {
+		if m == option {
+			return true
+		}
+	}
+	return false
+}
+
+// annotateOrigin records the file the resources came
+// from, if originAnnotations is on.
+func (kt *KustTarget) annotateOrigin(m resmap.ResMap, path string) {
+	if !kt.hasBuildMetadata(types.OriginAnnotations) {
+		return
+	}
+	origin := joinOrigin(kt.origin, path)
+	for _, r := range m.Resources() {
+		a := r.GetAnnotations()
+		if a == nil {
+			a = make(map[string]string)
+		}
+		a[types.OriginAnnotation] = origin
+		r.SetAnnotations(a)
+	}
+}
+
+// joinOrigin appends a relative path to an origin,
+// which may be a remote kustomization's URL, a
+// reference to an OCI artifact, a bucket's URL,
+// or an archive's.
+func joinOrigin(origin, path string) string {
+	if loader.IsFileURL(path) {
+		return path
+	}
+	if origin == "" {
+		return filepath.ToSlash(path)
+	}
+	if loader.IsArchiveURL(origin) {
+		// Keep any query, e.g. ?sha256=3d4a..., at the end.
+		query := ""
+		if i := strings.Index(origin, "?"); i >= 0 {
+			origin, query = origin[:i], origin[i:]
+		}
+		if !strings.Contains(strings.SplitN(origin, "://", 2)[1], "//") {
+			return origin + "//" + filepath.ToSlash(path) + query
+		}
+		return origin + "/" + filepath.ToSlash(path) + query
+	}
+	if _, err := git.NewRepoSpecFromUrl(origin); err == nil {
+		// Keep any query, e.g. ?ref=v1, at the end.
+		if i := strings.Index(origin, "?"); i >= 0 {
+			return origin[:i] + "/" + path + origin[i:]
+		}
+		return origin + "/" + path
+	}
+	if oci.IsReference(origin) {
+		if _, dir := oci.SplitPath(origin); dir == "" {
+			return origin + "//" + filepath.ToSlash(path)
+		}
+		return origin + "/" + filepath.ToSlash(path)
+	}
+	if loader.IsObjectURL(origin) {
+		if !strings.Contains(strings.SplitN(origin, "://", 2)[1], "//") {
+			return origin + "//" + filepath.ToSlash(path)
+		}
+		return origin + "/" + filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(filepath.Join(origin, path))
+}
@@ -44,6 +44,7 @@ func (kt *KustTarget) configureBuiltinGenerators() (
 	configurators := []generatorConfigurator{
 		kt.configureBuiltinConfigMapGenerator,
 		kt.configureBuiltinSecretGenerator,
+		kt.configureBuiltinTemplateGenerator,
 	}
 	var result []transformers.Generator
 	for _, f := range configurators {
@@ -70,6 +71,7 @@ func (kt *KustTarget) configureBuiltinTransformers(
 		kt.configureBuiltinLabelTransformer,
 		kt.configureBuiltinAnnotationsTransformer,
 		kt.configureBuiltinPatchJson6902Transformer,
+		kt.configureBuiltinPatchJsonPathTransformer,
 		kt.configureBuiltinReplicaCountTransformer,
 		kt.configureBuiltinImageTagTransformer,
 	}
@@ -127,6 +129,20 @@ func (kt *KustTarget) configureBuiltinConfigMapGenerator() (
 	return
 }
 
+func (kt *KustTarget) configureBuiltinTemplateGenerator() (
+	result []transformers.Generator, err error) {
+	for _, args := range kt.kustomization.Templates {
+		args.Params = withValues(kt.values, args.Params)
+		p := builtin.NewTemplateGeneratorPlugin()
+		err = kt.configureBuiltinPlugin(p, args, "template")
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return
+}
+
 func (kt *KustTarget) configureBuiltinNamespaceTransformer(
 	tConfig *config.TransformerConfig) (
 	result []transformers.Transformer, err error) {
@@ -134,7 +150,7 @@ func (kt *KustTarget) configureBuiltinNamespaceTransformer(
 		types.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 		FieldSpecs       []config.FieldSpec
 	}
-	c.Namespace = kt.kustomization.Namespace
+	c.Namespace = kt.namespace
 	c.FieldSpecs = tConfig.NameSpace
 	p := builtin.NewNamespaceTransformerPlugin()
 	err = kt.configureBuiltinPlugin(p, c, "namespace")
@@ -167,6 +183,20 @@ func (kt *KustTarget) configureBuiltinPatchJson6902Transformer(
 	return
 }
 
+func (kt *KustTarget) configureBuiltinPatchJsonPathTransformer(
+	tConfig *config.TransformerConfig) (
+	result []transformers.Transformer, err error) {
+	for _, args := range kt.kustomization.PatchesJsonPath {
+		p := builtin.NewPatchJsonPathTransformerPlugin()
+		err = kt.configureBuiltinPlugin(p, args, "patchJsonPath")
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return
+}
+
 func (kt *KustTarget) configureBuiltinPatchStrategicMergeTransformer(
 	tConfig *config.TransformerConfig) (
 	result []transformers.Transformer, err error) {
@@ -212,6 +242,19 @@ func (kt *KustTarget) configureBuiltinPatchTransformer(
 	return
 }
 
+func (kt *KustTarget) configureBuiltinIgnoreFieldsTransformer() (
+	result []transformers.Transformer, err error) {
+	for _, args := range kt.kustomization.IgnoreFields {
+		p := builtin.NewIgnoreFieldsTransformerPlugin()
+		err = kt.configureBuiltinPlugin(p, args, "ignoreFields")
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return
+}
+
 func (kt *KustTarget) configureBuiltinLabelTransformer(
 	tConfig *config.TransformerConfig) (
 	result []transformers.Transformer, err error) {
@@ -257,7 +300,7 @@ func (kt *KustTarget) configureBuiltinNameTransformer(
 		FieldSpecs []config.FieldSpec
 	}
 	c.Prefix = kt.kustomization.NamePrefix
-	c.Suffix = kt.kustomization.NameSuffix
+	c.Suffix = kt.nameSuffix
 	c.FieldSpecs = tConfig.NamePrefix
 	p := builtin.NewPrefixSuffixTransformerPlugin()
 	err = kt.configureBuiltinPlugin(p, c, "prefixsuffix")
@@ -5,18 +5,27 @@ package target_test
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
 
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/internal/loadertest"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
 	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	. "sigs.k8s.io/kustomize/v3/pkg/target"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
 )
 
 const (
@@ -192,6 +201,9 @@ func TestKustomizationNotFound(t *testing.T) {
 		`unable to find one of 'kustomization.yaml', 'kustomization.yml' or 'Kustomization' in directory '/foo'` {
 		t.Fatalf("unexpected error: %q", err)
 	}
+	if !kusterr.Is(err, ErrNotKustomization) {
+		t.Fatalf("expected ErrNotKustomization, got %v", err)
+	}
 }
 
 func TestResourceNotFound(t *testing.T) {
@@ -442,3 +454,56 @@ resources:
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestRemoteBaseFailuresReportedTogether(t *testing.T) {
+	fSys := fs.MakeFakeFS()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- github.com/org/a
+- github.com/org/b/base
+- github.com/org/c/base
+`))
+	fSys.WriteFile("/clones/org/c/base/kustomization.yaml", []byte(`
+resources:
+- service.yaml
+`))
+	fSys.WriteFile("/clones/org/c/base/service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`))
+	ldr, err := loader.NewLoaderWithCloner(
+		loader.RestrictionRootOnly, validators.MakeFakeValidator(),
+		"/app", fSys, nil, func(repoSpec *git.RepoSpec) error {
+			if repoSpec.OrgRepo != "org/c" {
+				return errors.New("repository not found")
+			}
+			repoSpec.Dir = fs.ConfirmedDir("/clones/org/c")
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ldr.Cleanup()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	kt, err := NewKustTarget(ldr, rf, transformer.NewFactoryImpl(),
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = kt.MakeCustomizedResMap()
+	if err == nil || kusterr.ClassOf(err) != kusterr.ClassRemote {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"2 remote bases failed",
+		"github.com/org/a: repository not found",
+		"github.com/org/b/base: repository not found",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in: %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestLocalConfig(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+namePrefix: p-
+commonLabels:
+  app: web
+resources:
+- resources.yaml
+vars:
+- name: HOST
+  objRef:
+    apiVersion: v1
+    kind: ConfigMap
+    name: settings
+  fieldref:
+    fieldpath: data.host
+`)
+	th.WriteF("/app/resources.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  annotations:
+    config.kubernetes.io/local-config: "true"
+data:
+  host: example.com
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  annotations:
+    owner: web-team
+    config.kubernetes.io/skip-transformers: PrefixSuffixTransformer
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+        args:
+        - --host=$(HOST)
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: other
+  annotations:
+    config.kubernetes.io/local-config: "false"
+    config.kubernetes.io/skip-transformers: LabelTransformer
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    owner: web-team
+  labels:
+    app: web
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - args:
+        - --host=example.com
+        image: web
+        name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: p-other
+`)
+}
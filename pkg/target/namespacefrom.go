@@ -0,0 +1,83 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// deriveNamespace returns the namespace and name suffix
+// of the kustomization, as namespaceFrom, if set, derives
+// them from a value or the git branch.
+func (kt *KustTarget) deriveNamespace() (string, string, error) {
+	k := kt.kustomization
+	n := k.NamespaceFrom
+	if n == nil {
+		return k.Namespace, k.NameSuffix, nil
+	}
+	env, ok := kt.values[n.Value]
+	if !ok || n.Value == "" {
+		if !n.GitBranch {
+			return "", "", fmt.Errorf(
+				"namespaceFrom: no value %s; give one, e.g. "+
+					"with kustomize build --set %s=...", n.Value, n.Value)
+		}
+		var err error
+		env, err = gitBranch(kt.ldr.Root())
+		if err != nil {
+			return "", "", errors.Wrap(err, "namespaceFrom")
+		}
+	}
+	label := dnsLabel(env, maxNamespaceLength-len(n.Prefix))
+	if label == "" {
+		return "", "", fmt.Errorf(
+			"namespaceFrom: '%s' has no letters or digits", env)
+	}
+	suffix := k.NameSuffix
+	if n.NameSuffix {
+		suffix += "-" + label
+	}
+	return n.Prefix + label, suffix, nil
+}
+
+const maxNamespaceLength = 63
+
+var notInLabel = regexp.MustCompile(`[^a-z0-9]+`)
+
+// dnsLabel returns s, e.g. a branch like feature/XYZ,
+// as lowercase letters, digits and dashes, e.g.
+// feature-xyz, at most max of them, neither starting nor
+// ending with a dash.
+func dnsLabel(s string, max int) string {
+	s = notInLabel.ReplaceAllString(strings.ToLower(s), "-")
+	s = strings.Trim(s, "-")
+	if max < 0 {
+		max = 0
+	}
+	if len(s) > max {
+		s = strings.TrimRight(s[:max], "-")
+	}
+	return s
+}
+
+// gitBranch returns the branch checked out in dir.
+func gitBranch(dir string) (string, error) {
+	cmd := exec.Command("git", "symbolic-ref", "--short", "HEAD")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf(
+			"no value given, and %s isn't on a git branch: %s",
+			dir, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
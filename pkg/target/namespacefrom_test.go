@@ -0,0 +1,75 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeNamespaceFrom(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app", `
+namespace: myapp
+nameSuffix: -v1
+namespaceFrom:
+  value: env
+  prefix: myapp-
+  nameSuffix: true
+resources:
+- service.yaml
+`)
+	th.WriteF("/app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+}
+
+func TestNamespaceFromValue(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeNamespaceFrom(th)
+	kt := th.MakeKustTarget()
+	kt.SetValues(map[string]string{"env": "Feature/XYZ_1"})
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-v1-feature-xyz-1
+  namespace: myapp-feature-xyz-1
+`)
+}
+
+func TestNamespaceFromLongValue(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeNamespaceFrom(th)
+	kt := th.MakeKustTarget()
+	kt.SetValues(map[string]string{
+		"env": "feature-" + strings.Repeat("x", 49) + "-more"})
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	// The namespace is cut to 63 characters, less the dash.
+	ns := "myapp-feature-" + strings.Repeat("x", 49)
+	if got := m.Resources()[0].GetNamespace(); got != ns {
+		t.Fatalf("expected namespace %s, got %s", ns, got)
+	}
+}
+
+func TestNamespaceFromMissingValue(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeNamespaceFrom(th)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil || !strings.Contains(
+		err.Error(), "namespaceFrom: no value env") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
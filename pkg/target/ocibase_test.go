@@ -0,0 +1,135 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+// makeArtifactRegistry serves, with the tag v1, an
+// artifact holding the files as ORAS pushes a
+// directory: a tar layer titled with its name.
+func makeArtifactRegistry(files map[string]string) *httptest.Server {
+	var b bytes.Buffer
+	z := gzip.NewWriter(&b)
+	w := tar.NewWriter(z)
+	for name, content := range files {
+		w.WriteHeader(&tar.Header{
+			Name: name, Typeflag: tar.TypeReg, Mode: 0644,
+			Size: int64(len(content))})
+		w.Write([]byte(content))
+	}
+	w.Close()
+	z.Close()
+	layer := b.String()
+	m := fmt.Sprintf(`{"schemaVersion":2,"layers":[`+
+		`{"mediaType":"application/vnd.oci.image.layer.v1.tar+gzip",`+
+		`"digest":"%s","size":%d,"annotations":{`+
+		`"org.opencontainers.image.title":"bases",`+
+		`"io.deis.oras.content.unpack":"true"}}]}`,
+		digestOf(layer), len(layer))
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/org/bases/manifests/v1":
+				w.Write([]byte(m))
+			case "/v2/org/bases/blobs/" + digestOf(layer):
+				w.Write([]byte(layer))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+}
+
+func TestBaseFromOCI(t *testing.T) {
+	s := makeArtifactRegistry(map[string]string{
+		"bases/web/kustomization.yaml": `
+resources:
+- deployment.yaml
+- ../common
+`,
+		"bases/web/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`,
+		"bases/common/kustomization.yaml": `
+resources:
+- service.yaml
+`,
+		"bases/common/service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`,
+	})
+	defer s.Close()
+	base := "oci://" + strings.TrimPrefix(s.URL, "http://") +
+		"/org/bases:v1//bases/web"
+
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+namePrefix: prod-
+resources:
+- `+base+`
+buildMetadata:
+- originAnnotations
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    config.kubernetes.io/origin: `+base+`/deployment.yaml
+  name: prod-web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    config.kubernetes.io/origin: `+base+`/../common/service.yaml
+  name: prod-web
+`)
+}
+
+func TestBaseFromOCIOutsideArtifact(t *testing.T) {
+	s := makeArtifactRegistry(map[string]string{
+		"bases/web/kustomization.yaml": `
+resources:
+- /app/secret.yaml
+`,
+	})
+	defer s.Close()
+
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+resources:
+- oci://`+strings.TrimPrefix(s.URL, "http://")+`/org/bases:v1//bases/web
+`)
+	th.WriteF("/app/secret.yaml", `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil || !strings.Contains(err.Error(), "is not in or below") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
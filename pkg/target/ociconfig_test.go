@@ -0,0 +1,99 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func digestOf(b string) string {
+	sum := sha256.Sum256([]byte(b))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestConfigurationsFromOCI(t *testing.T) {
+	config := `
+namePrefix:
+- path: spec/backupName
+  kind: Database
+---
+commonLabels:
+- path: spec/selector
+  create: true
+  kind: Database
+`
+	layer := digestOf(config)
+	m := fmt.Sprintf(`{"schemaVersion":2,"layers":[`+
+		`{"mediaType":"application/yaml","digest":"%s","size":%d,`+
+		`"annotations":{"org.opencontainers.image.title":"db.yaml"}}]}`,
+		layer, len(config))
+	s := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/platform/configs/manifests/" + digestOf(m):
+				w.Write([]byte(m))
+			case "/v2/platform/configs/blobs/" + layer:
+				w.Write([]byte(config))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+	defer s.Close()
+
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+namePrefix: prod-
+commonLabels:
+  team: data
+resources:
+- db.yaml
+configurations:
+- oci://`+strings.TrimPrefix(s.URL, "http://")+
+		`/platform/configs:v1@`+digestOf(m)+`
+`)
+	th.WriteF("/app/db.yaml", `
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: orders
+spec:
+  backupName: orders-backup
+`)
+	res, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(res, `
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  labels:
+    team: data
+  name: prod-orders
+spec:
+  backupName: prod-orders-backup
+  selector:
+    team: data
+`)
+}
+
+func TestConfigurationsFromUnpinnedOCI(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+configurations:
+- oci://ghcr.io/platform/configs:v1
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil || !strings.Contains(err.Error(), "isn't pinned") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeFooWithPatch(th *kusttest_test.KustTestHarness) {
+	th.WriteF("/app/foo.yaml", `
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+spec:
+  bars:
+  - name: a
+    value: "1"
+  - name: b
+    value: "2"
+`)
+	th.WriteF("/app/patch.yaml", `
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+spec:
+  bars:
+  - name: b
+    value: "3"
+`)
+	th.WriteF("/app/schema.yaml", `
+swagger: "2.0"
+info:
+  title: test
+  version: v1.16.0
+paths: {}
+definitions:
+  com.example.v1.Foo:
+    type: object
+    properties:
+      apiVersion:
+        type: string
+      kind:
+        type: string
+      metadata:
+        type: object
+      spec:
+        $ref: '#/definitions/com.example.v1.FooSpec'
+    x-kubernetes-group-version-kind:
+    - group: example.com
+      version: v1
+      kind: Foo
+  com.example.v1.FooSpec:
+    type: object
+    properties:
+      bars:
+        type: array
+        items:
+          $ref: '#/definitions/com.example.v1.Bar'
+        x-kubernetes-patch-merge-key: name
+        x-kubernetes-patch-strategy: merge
+  com.example.v1.Bar:
+    type: object
+    properties:
+      name:
+        type: string
+      value:
+        type: string
+`)
+}
+
+func TestPatchWithoutOpenAPISchema(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeFooWithPatch(th)
+	th.WriteK("/app", `
+resources:
+- foo.yaml
+patchesStrategicMerge:
+- patch.yaml
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	// Without a schema, the list is replaced.
+	th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+spec:
+  bars:
+  - name: b
+    value: "3"
+`)
+}
+
+func TestPatchWithOpenAPISchema(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeFooWithPatch(th)
+	th.WriteK("/app", `
+resources:
+- foo.yaml
+patchesStrategicMerge:
+- patch.yaml
+openapi:
+  path: schema.yaml
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+spec:
+  bars:
+  - name: a
+    value: "1"
+  - name: b
+    value: "3"
+`)
+	if th.RF().Options().Schema != nil {
+		t.Fatalf("schema should be that of the build alone")
+	}
+}
+
+func TestOpenAPIVersionNotAvailable(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeFooWithPatch(th)
+	th.WriteK("/app", `
+resources:
+- foo.yaml
+openapi:
+  version: "1.2"
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected error for unavailable version")
+	}
+}
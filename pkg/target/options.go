@@ -0,0 +1,26 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+// Options say how strictly a build checks what it
+// builds.  The zero Options are those of a build
+// given no flags.
+type Options struct {
+	// PatchConflicts says what to do when patches of one
+	// kustomization change the same field of a resource;
+	// empty means PatchConflictsLastWins.
+	PatchConflicts PatchConflicts
+	// Strict makes builds fail, rather than warn or go on,
+	// if vars are never replaced, if patches select no
+	// resources, or if output resources have invalid names,
+	// labels or annotations, or, given a schema of their
+	// kinds, fields it doesn't allow.
+	Strict bool
+}
+
+// SetOptions sets the options of the build of the
+// kustomization, and of the kustomizations it includes.
+func (kt *KustTarget) SetOptions(o Options) {
+	kt.opts = o
+}
@@ -0,0 +1,184 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+)
+
+// PatchConflicts says what to do when patches of one
+// kustomization change the same field of a resource.
+type PatchConflicts string
+
+const (
+	// PatchConflictsError fails the build.
+	PatchConflictsError PatchConflicts = "error"
+	// PatchConflictsLastWins lets the last patch win, silently.
+	PatchConflictsLastWins PatchConflicts = "last-wins"
+	// PatchConflictsReport lets the last patch win, with a warning.
+	PatchConflictsReport PatchConflicts = "report"
+)
+
+// patchFields are the fields of the patches
+// the builtin patch transformers apply.
+var patchFields = map[string]string{
+	"PatchStrategicMergeTransformer": "patchesStrategicMerge",
+	"PatchJson6902Transformer":       "patchesJson6902",
+	"PatchJsonPathTransformer":       "patchesJsonPath",
+	"PatchTransformer":               "patches",
+}
+
+// trackPatchConflicts wraps the patch transformers, given
+// with the names of all the transformers, to find fields
+// changed by more than one of them, doing as p says.
+func trackPatchConflicts(
+	ts []transformers.Transformer, names []string,
+	p PatchConflicts) []transformers.Transformer {
+	if p == "" || p == PatchConflictsLastWins {
+		return ts
+	}
+	tracker := &patchConflictTracker{
+		conflicts: p,
+		changedBy: make(map[resid.ResId]map[string]string),
+	}
+	counts := make(map[string]int)
+	var result []transformers.Transformer
+	for i, t := range ts {
+		field, ok := patchFields[names[i]]
+		if !ok {
+			result = append(result, t)
+			continue
+		}
+		name := field
+		if field != "patchesStrategicMerge" {
+			// The others have a transformer per patch.
+			name = field + "[" + strconv.Itoa(counts[field]) + "]"
+			counts[field]++
+		}
+		result = append(result, &trackedPatch{
+			t: t, name: name, tracker: tracker})
+	}
+	return result
+}
+
+// patchConflictTracker records which patch last changed
+// each field of each resource.
+type patchConflictTracker struct {
+	conflicts PatchConflicts
+	changedBy map[resid.ResId]map[string]string
+}
+
+func (pt *patchConflictTracker) record(
+	id resid.ResId, field, patch string) error {
+	fields, ok := pt.changedBy[id]
+	if !ok {
+		fields = make(map[string]string)
+		pt.changedBy[id] = fields
+	}
+	if prev, ok := fields[field]; ok && prev != patch {
+		msg := fmt.Sprintf(
+			"patches %s and %s both change field %s of %s",
+			prev, patch, field, id.GvknString())
+		if pt.conflicts == PatchConflictsError {
+			return fmt.Errorf("%s", msg)
+		}
+		log.Printf("warning: %s; %s wins", msg, patch)
+	}
+	fields[field] = patch
+	return nil
+}
+
+// trackedPatch runs a patch transformer, then records
+// the fields it changed.
+type trackedPatch struct {
+	t       transformers.Transformer
+	name    string
+	tracker *patchConflictTracker
+}
+
+func (tp *trackedPatch) Transform(m resmap.ResMap) error {
+	before := make(map[resid.ResId]map[string]interface{})
+	for _, r := range m.Resources() {
+		before[r.CurId()] = r.DeepCopy().Map()
+	}
+	err := tp.t.Transform(m)
+	if err != nil {
+		return err
+	}
+	for _, r := range m.Resources() {
+		old, ok := before[r.CurId()]
+		if !ok {
+			continue
+		}
+		changed := make(map[string]bool)
+		changedFields("", old, r.Map(), changed)
+		var fields []string
+		for f := range changed {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+		for _, f := range fields {
+			err = tp.tracker.record(r.CurId(), f, tp.name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// changedFields adds the paths of the fields that differ
+// between a and b to the result, descending into maps and
+// lists so that a map added or removed as a whole
+// contributes the paths of the fields in it.
+func changedFields(
+	path string, a, b interface{}, result map[string]bool) {
+	am, aIsMap := a.(map[string]interface{})
+	bm, bIsMap := b.(map[string]interface{})
+	if (aIsMap || a == nil) && (bIsMap || b == nil) && (aIsMap || bIsMap) {
+		for k := range am {
+			changedFields(joinField(path, k), am[k], bm[k], result)
+		}
+		for k := range bm {
+			if _, ok := am[k]; !ok {
+				changedFields(joinField(path, k), nil, bm[k], result)
+			}
+		}
+		return
+	}
+	al, aIsList := a.([]interface{})
+	bl, bIsList := b.([]interface{})
+	if (aIsList || a == nil) && (bIsList || b == nil) && (aIsList || bIsList) {
+		for i := 0; i < len(al) || i < len(bl); i++ {
+			var x, y interface{}
+			if i < len(al) {
+				x = al[i]
+			}
+			if i < len(bl) {
+				y = bl[i]
+			}
+			changedFields(
+				path+"["+strconv.Itoa(i)+"]", x, y, result)
+		}
+		return
+	}
+	if !reflect.DeepEqual(a, b) {
+		result[path] = true
+	}
+}
+
+func joinField(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+)
+
+func writeConflictingPatches(th *kusttest_test.KustTestHarness) {
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:1
+`)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patchesStrategicMerge:
+- |-
+  apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: web
+  spec:
+    replicas: 2
+patchesJson6902:
+- target:
+    group: apps
+    version: v1
+    kind: Deployment
+    name: web
+  patch: |-
+    - op: replace
+      path: /spec/template/spec/containers/0/image
+      value: web:2
+patches:
+- target:
+    kind: Deployment
+  patch: |-
+    - op: replace
+      path: /spec/replicas
+      value: 3
+`)
+}
+
+func TestPatchConflicts(t *testing.T) {
+	for _, p := range []target.PatchConflicts{
+		target.PatchConflictsLastWins, target.PatchConflictsReport} {
+		th := kusttest_test.NewKustTestHarness(t, "/app")
+		writeConflictingPatches(th)
+		kt := th.MakeKustTarget()
+		kt.SetOptions(target.Options{PatchConflicts: p})
+		m, err := kt.MakeCustomizedResMap()
+		if err != nil {
+			t.Fatalf("%s: Err: %v", p, err)
+		}
+		th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - image: web:2
+        name: web
+`)
+	}
+}
+
+func TestPatchConflictsError(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeConflictingPatches(th)
+	kt := th.MakeKustTarget()
+	kt.SetOptions(target.Options{PatchConflicts: target.PatchConflictsError})
+	_, err := kt.MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		"patches patchesStrategicMerge and patches[0] both change "+
+			"field spec.replicas of apps_v1_Deployment|web") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPatchConflictsOnlyWithinKustomization(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+patchesJson6902:
+- target:
+    group: apps
+    version: v1
+    kind: Deployment
+    name: web
+  patch: |-
+    - op: replace
+      path: /spec/replicas
+      value: 2
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+patchesJson6902:
+- target:
+    group: apps
+    version: v1
+    kind: Deployment
+    name: web
+  patch: |-
+    - op: replace
+      path: /spec/replicas
+      value: 3
+`)
+	kt := th.MakeKustTarget()
+	kt.SetOptions(target.Options{PatchConflicts: target.PatchConflictsError})
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`)
+}
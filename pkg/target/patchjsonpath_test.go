@@ -0,0 +1,90 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeJsonPathBase(th *kusttest_test.KustTestHarness) {
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        image: sidecar:1
+      - name: app
+        image: app:1
+`)
+}
+
+func TestPatchesJsonPath(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeJsonPathBase(th)
+	th.WriteK("/app", `
+namePrefix: dev-
+resources:
+- deployment.yaml
+patchesJsonPath:
+- target:
+    kind: Deployment
+    name: web
+  path: spec.template.spec.containers[?(@.name=='app')].image
+  value: app:2
+- path: metadata.annotations['example.com/owner']
+  value: team-a
+- path: spec.replicas
+  value: 3
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    example.com/owner: team-a
+  name: dev-web
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - image: sidecar:1
+        name: sidecar
+      - image: app:2
+        name: app
+`)
+}
+
+func TestPatchesJsonPathFindsNothing(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeJsonPathBase(th)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patchesJsonPath:
+- path: spec.template.spec.containers[?(@.name=='nope')].image
+  value: app:2
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		"jsonpath 'spec.template.spec.containers[?(@.name=='nope')].image' "+
+			"found no field in 1 resources") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
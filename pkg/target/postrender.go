@@ -0,0 +1,94 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// postRenderEnv are the environment variables passed
+// on to postRenderers; the rest, e.g. credentials, are
+// kept from them.
+var postRenderEnv = []string{"PATH", "HOME", "TMPDIR"}
+
+func runPostRenderer(cmd *exec.Cmd) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if why := strings.TrimSpace(stderr.String()); why != "" {
+			return nil, errors.Wrap(err, why)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// PostRenderers returns the postRenderers of the
+// kustomization, ignoring those of its bases.
+func (kt *KustTarget) PostRenderers() []types.PostRenderer {
+	return kt.kustomization.PostRenderers
+}
+
+// PostRender pipes the output of a build through
+// each of the postRenderers in turn, returning the
+// output of the last.  They run in the directory of
+// the kustomization, with little of the environment.
+func (kt *KustTarget) PostRender(out []byte) ([]byte, error) {
+	root := kt.ldr.Root()
+	for i, p := range kt.kustomization.PostRenderers {
+		path, err := postRendererPath(root, p.Command)
+		if err != nil {
+			return nil, kusterr.WithClass(kusterr.ClassUsage, errors.Wrapf(
+				err, "postRenderers[%d]", i))
+		}
+		cmd := exec.Command(path, p.Args...)
+		cmd.Stdin = bytes.NewReader(out)
+		if _, err := os.Stat(root); err == nil {
+			cmd.Dir = root
+		}
+		for _, k := range postRenderEnv {
+			if v, ok := os.LookupEnv(k); ok {
+				cmd.Env = append(cmd.Env, k+"="+v)
+			}
+		}
+		out, err = runPostRenderer(cmd)
+		if err != nil {
+			return nil, kusterr.WithClass(kusterr.ClassPlugin, messages.WithID(
+				messages.PostRendererFailed, errors.Wrapf(
+					err, "postRenderers[%d] %s", i, p.Command)))
+		}
+	}
+	return out, nil
+}
+
+// postRendererPath returns the path of the command,
+// which, if it has a slash, must be in the root.
+func postRendererPath(root, command string) (string, error) {
+	switch {
+	case command == "":
+		return "", fmt.Errorf("no command")
+	case !strings.Contains(command, "/"):
+		return command, nil
+	case filepath.IsAbs(command):
+		return "", fmt.Errorf(
+			"command %s must be relative to the kustomization", command)
+	}
+	path := filepath.Join(root, command)
+	if !strings.HasPrefix(path, filepath.Clean(root)+string(filepath.Separator)) {
+		return "", fmt.Errorf(
+			"command %s is outside the kustomization's directory", command)
+	}
+	return path, nil
+}
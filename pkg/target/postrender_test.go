@@ -0,0 +1,58 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestPostRender(t *testing.T) {
+	os.Setenv("KUSTOMIZE_TEST_SECRET", "hunter2")
+	defer os.Unsetenv("KUSTOMIZE_TEST_SECRET")
+
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+postRenderers:
+- command: sed
+  args: ["s/replicas: 1/replicas: 3/"]
+- command: sh
+  args:
+  - -c
+  - 'cat; echo "# ${KUSTOMIZE_TEST_SECRET:-unset}"'
+`)
+	kt := th.MakeKustTarget()
+	out, err := kt.PostRender([]byte("kind: Deployment\nreplicas: 1\n"))
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	expected := "kind: Deployment\nreplicas: 3\n# unset\n"
+	if string(out) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestPostRenderFails(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	for entry, msg := range map[string]string{
+		"{command: sh, args: [-c, 'echo broken >&2; exit 1']}": "broken",
+		"{command: ../bin/render}":                             "outside",
+		"{command: /usr/bin/yq}":                               "relative",
+		"{args: [-i]}":                                         "no command",
+	} {
+		th.WriteK("/app", `
+postRenderers:
+- {command: cat}
+- `+entry+`
+`)
+		_, err := th.MakeKustTarget().PostRender(nil)
+		if err == nil || !strings.Contains(err.Error(), msg) ||
+			!strings.Contains(err.Error(), "postRenderers[1]") {
+			t.Errorf("%s: unexpected err: %v", entry, err)
+		}
+	}
+}
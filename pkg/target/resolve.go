@@ -0,0 +1,198 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// Contribution is the value a kustomization file sets a
+// field to.
+type Contribution struct {
+	// File is the kustomization file, relative to the
+	// kustomization resolved.
+	File  string      `json:"file"`
+	Value interface{} `json:"value"`
+}
+
+// Resolution is the effective value of a field for the
+// resources of one kustomization in a build, and the
+// contributions to it of that kustomization and those
+// including it, outermost first.
+type Resolution struct {
+	// Path is the kustomization's directory, relative to
+	// the kustomization resolved.
+	Path          string         `json:"path"`
+	Value         interface{}    `json:"value,omitempty"`
+	Contributions []Contribution `json:"contributions,omitempty"`
+}
+
+// resolvableField gets a field from a kustomization, nil
+// if unset, and combines contributions to it, outermost
+// first, as the build does.
+type resolvableField struct {
+	get     func(k *types.Kustomization) interface{}
+	combine func(c []Contribution) interface{}
+}
+
+var resolvableFields = map[string]resolvableField{
+	"namespace": {
+		get: func(k *types.Kustomization) interface{} {
+			return unlessEmpty(k.Namespace)
+		},
+		// The outermost namespace replaces the others.
+		combine: func(c []Contribution) interface{} {
+			if len(c) == 0 {
+				return nil
+			}
+			return c[0].Value
+		},
+	},
+	"namePrefix": {
+		get: func(k *types.Kustomization) interface{} {
+			return unlessEmpty(k.NamePrefix)
+		},
+		combine: func(c []Contribution) interface{} {
+			var s string
+			for _, x := range c {
+				s += x.Value.(string)
+			}
+			return unlessEmpty(s)
+		},
+	},
+	"nameSuffix": {
+		get: func(k *types.Kustomization) interface{} {
+			return unlessEmpty(k.NameSuffix)
+		},
+		combine: func(c []Contribution) interface{} {
+			var s string
+			for _, x := range c {
+				s = x.Value.(string) + s
+			}
+			return unlessEmpty(s)
+		},
+	},
+	"commonLabels": {
+		get: func(k *types.Kustomization) interface{} {
+			return unlessEmptyMap(k.CommonLabels)
+		},
+		combine: mergeMaps,
+	},
+	"commonAnnotations": {
+		get: func(k *types.Kustomization) interface{} {
+			return unlessEmptyMap(k.CommonAnnotations)
+		},
+		combine: mergeMaps,
+	},
+}
+
+// ResolvableFields returns the fields Resolve knows.
+func ResolvableFields() []string {
+	var result []string
+	for f := range resolvableFields {
+		result = append(result, f)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func unlessEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func unlessEmptyMap(m map[string]string) interface{} {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// mergeMaps merges maps, outer values winning, as
+// transformers of overlays run after those of bases.
+func mergeMaps(c []Contribution) interface{} {
+	if len(c) == 0 {
+		return nil
+	}
+	result := make(map[string]string)
+	for i := len(c) - 1; i >= 0; i-- {
+		for k, v := range c[i].Value.(map[string]string) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Resolve returns the effective value of field for the
+// resources of each kustomization in the build, in the
+// order the build reaches them.
+func (kt *KustTarget) Resolve(field string) ([]Resolution, error) {
+	f, ok := resolvableFields[field]
+	if !ok {
+		return nil, fmt.Errorf(
+			"can't resolve '%s'; resolvable fields: %v",
+			field, ResolvableFields())
+	}
+	var result []Resolution
+	err := kt.resolve(f, nil, &result)
+	return result, err
+}
+
+func (kt *KustTarget) resolve(
+	f resolvableField, chain []Contribution,
+	result *[]Resolution) error {
+	if v := f.get(kt.kustomization); v != nil {
+		// Copy, as sibling bases extend the same chain.
+		chain = append(chain[:len(chain):len(chain)], Contribution{
+			File:  joinOrigin(kt.origin, kt.kustFile),
+			Value: v,
+		})
+	}
+	path := kt.origin
+	if path == "" {
+		path = "."
+	}
+	*result = append(*result, Resolution{
+		Path:          path,
+		Value:         f.combine(chain),
+		Contributions: chain,
+	})
+	for _, path := range kt.kustomization.Resources {
+		ldr, err := kt.ldr.New(path)
+		if err != nil {
+			if kusterr.ClassOf(err) == kusterr.ClassRemote {
+				return err
+			}
+			// A file of resources.
+			continue
+		}
+		err = kt.resolveDirectory(f, chain, result, ldr, path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kt *KustTarget) resolveDirectory(
+	f resolvableField, chain []Contribution, result *[]Resolution,
+	ldr ifc.Loader, path string) error {
+	defer ldr.Cleanup()
+	subKt, err := NewKustTarget(
+		ldr, kt.rFactory, kt.tFactory, kt.pLdr)
+	if err != nil {
+		return errors.Wrapf(err, "couldn't make target for path '%s'", path)
+	}
+	subKt.origin = joinOrigin(kt.origin, path)
+	subKt.opts = kt.opts
+	return subKt.resolve(f, chain, result)
+}
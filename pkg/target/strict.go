@@ -0,0 +1,102 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/internal/kusterr"
+	"sigs.k8s.io/kustomize/v3/pkg/messages"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/plugin/builtin"
+)
+
+// checkPatchTargets wraps the patch transformers of the
+// patches field, given with the names of all the
+// transformers, to fail if their targets select no
+// resources, as builds do under Options.Strict.
+func checkPatchTargets(
+	ts []transformers.Transformer, names []string) []transformers.Transformer {
+	var result []transformers.Transformer
+	count := 0
+	for i, t := range ts {
+		p, ok := t.(*builtin.PatchTransformerPlugin)
+		if !ok || names[i] != "PatchTransformer" {
+			result = append(result, t)
+			continue
+		}
+		result = append(result, &targetedPatch{
+			t: t, p: p, name: "patches[" + strconv.Itoa(count) + "]"})
+		count++
+	}
+	return result
+}
+
+// targetedPatch runs a patch transformer, failing first
+// if its target selects no resources.
+type targetedPatch struct {
+	t    transformers.Transformer
+	p    *builtin.PatchTransformerPlugin
+	name string
+}
+
+func (tp *targetedPatch) Transform(m resmap.ResMap) error {
+	if tp.p.Target != nil {
+		resources, err := m.Select(*tp.p.Target)
+		if err != nil {
+			return err
+		}
+		if len(resources) == 0 {
+			return kusterr.WithClass(kusterr.ClassValidation, messages.Errorf(
+				messages.PatchSelectsNothing, tp.name))
+		}
+	}
+	return tp.t.Transform(m)
+}
+
+// checkStrict fails, under Options.Strict, if resources
+// of m have invalid names, labels or annotations, or
+// fields the schema of the build doesn't allow.
+func (kt *KustTarget) checkStrict(m resmap.ResMap) error {
+	if !kt.opts.Strict {
+		return nil
+	}
+	v := kt.ldr.Validator()
+	var problems []string
+	add := func(kind, name, msg string) {
+		problems = append(problems, fmt.Sprintf("%s %s: %s", kind, name, msg))
+	}
+	validateLabels := v.MakeLabelValidator()
+	validateAnnotations := v.MakeAnnotationValidator()
+	schema := kt.rFactory.RF().Options().Schema
+	for _, r := range m.Resources() {
+		kind, name := r.GetKind(), r.GetName()
+		if name != "" {
+			for _, msg := range v.ValidateName(r.GetGvk(), name) {
+				add(kind, name, "invalid name: "+msg)
+			}
+		}
+		if validateLabels != nil {
+			if err := validateLabels(r.GetLabels()); err != nil {
+				add(kind, name, "invalid labels: "+err.Error())
+			}
+		}
+		if validateAnnotations != nil {
+			if err := validateAnnotations(r.GetAnnotations()); err != nil {
+				add(kind, name, "invalid annotations: "+err.Error())
+			}
+		}
+		for _, err := range schema.Validate(r.GetGvk(), r.Map()) {
+			add(kind, name, err.Error())
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return kusterr.WithClass(kusterr.ClassValidation, messages.Errorf(
+		messages.OutputProblems, len(problems), strings.Join(problems, "\n")))
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+)
+
+func TestStrict(t *testing.T) {
+	testCases := map[string]struct {
+		kustomization string
+		files         map[string]string
+		expectedErr   string
+	}{
+		"unused var": {
+			kustomization: `
+resources:
+- service.yaml
+vars:
+- name: SVC
+  objref:
+    kind: Service
+    name: backend
+    apiVersion: v1
+`,
+			files: map[string]string{"service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: backend
+`},
+			expectedErr: "vars that were never replaced: SVC",
+		},
+		"unmatched patch": {
+			kustomization: `
+resources:
+- service.yaml
+patches:
+- target:
+    kind: Deployment
+  patch: |-
+    - op: add
+      path: /spec/replicas
+      value: 2
+`,
+			files: map[string]string{"service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: backend
+`},
+			expectedErr: "the target of patches[0] selects no resources",
+		},
+		"invalid name": {
+			kustomization: `
+resources:
+- service.yaml
+`,
+			files: map[string]string{"service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: Backend_Service
+`},
+			expectedErr: "Service Backend_Service: invalid name",
+		},
+		"field unknown to the schema": {
+			kustomization: `
+resources:
+- foo.yaml
+openapi:
+  path: schema.yaml
+`,
+			files: map[string]string{"foo.yaml": `
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+spec:
+  colour: red
+`},
+			expectedErr: `Foo foo: ValidationError(Foo.spec): unknown field "colour"`,
+		},
+	}
+	for name, tc := range testCases {
+		th := kusttest_test.NewKustTestHarness(t, "/app")
+		writeFooWithPatch(th)
+		th.WriteK("/app", tc.kustomization)
+		for f, content := range tc.files {
+			th.WriteF("/app/"+f, content)
+		}
+		// The checks fail only under Options.Strict.
+		if _, err := th.MakeKustTarget().MakeCustomizedResMap(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		kt := th.MakeKustTarget()
+		kt.SetOptions(target.Options{Strict: true})
+		_, err := kt.MakeCustomizedResMap()
+		if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+			t.Fatalf("%s: expected error containing %q, got %v",
+				name, tc.expectedErr, err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+// writeSymlinkTree writes, under dir, an app root
+// with files and links to files in and outside it:
+//
+//	app/files/{deployment.yaml,patch.yaml,app.env}
+//	app/in-{deployment.yaml,patch.yaml,app.env} -> files/...
+//	app/out-{deployment.yaml,patch.yaml,app.env} -> ../shared/...
+//	shared/{deployment.yaml,patch.yaml,app.env}
+func writeSymlinkTree(t *testing.T, dir string) {
+	files := map[string]string{
+		"deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+`,
+		"patch.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+`,
+		"app.env": "MODE=fast\n",
+	}
+	for _, d := range []string{"app/files", "shared"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0777); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for name, content := range files {
+		for _, d := range []string{"app/files", "shared"} {
+			err := ioutil.WriteFile(
+				filepath.Join(dir, d, name), []byte(content), 0666)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		err := os.Symlink(
+			filepath.Join("files", name),
+			filepath.Join(dir, "app", "in-"+name))
+		if err == nil {
+			err = os.Symlink(
+				filepath.Join("..", "shared", name),
+				filepath.Join(dir, "app", "out-"+name))
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestSymlinkPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-symlinks-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	writeSymlinkTree(t, dir)
+	kustomization := func(prefix string) map[string]string {
+		return map[string]string{
+			"resource": `
+resources:
+- ` + prefix + `deployment.yaml
+`,
+			"patch": `
+resources:
+- files/deployment.yaml
+patchesStrategicMerge:
+- ` + prefix + `patch.yaml
+`,
+			"env file": `
+configMapGenerator:
+- name: app
+  env: ` + prefix + `app.env
+`,
+		}
+	}
+	testCases := []struct {
+		policy loader.SymlinkPolicy
+		// prefix picks the links, or the files.
+		prefix string
+		// expectedErr is empty if the build succeeds.
+		expectedErr string
+	}{
+		{loader.SymlinksWithinRoot, "files/", ""},
+		{loader.SymlinksWithinRoot, "in-", ""},
+		{loader.SymlinksWithinRoot, "out-", "links to"},
+		{loader.SymlinksDeny, "files/", ""},
+		{loader.SymlinksDeny, "in-", "reached through a symbolic link"},
+		{loader.SymlinksDeny, "out-", "reached through a symbolic link"},
+		{loader.SymlinksAnywhere, "in-", ""},
+		{loader.SymlinksAnywhere, "out-", ""},
+	}
+	fSys := fs.MakeRealFS()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	for _, tc := range testCases {
+		for what, k := range kustomization(tc.prefix) {
+			err := fSys.WriteFile(
+				filepath.Join(dir, "app", "kustomization.yaml"), []byte(k))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ldr, err := loader.NewLoader(
+				loader.WithSymlinkPolicy(loader.RestrictionRootOnly, tc.policy),
+				validators.MakeFakeValidator(), filepath.Join(dir, "app"), fSys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tg, err := target.NewKustTarget(
+				ldr, rf, transformer.NewFactoryImpl(),
+				plugins.NewLoader(plugins.ActivePluginConfig(), rf))
+			if err == nil {
+				_, err = tg.MakeCustomizedResMap()
+			}
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("%s %s%s: unexpected error: %v",
+						tc.policy, tc.prefix, what, err)
+				}
+				continue
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("%s %s%s: expected error containing %q, got %v",
+					tc.policy, tc.prefix, what, tc.expectedErr, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeTemplates(th *kusttest_test.KustTestHarness) {
+	th.WriteF("/app/deployment.yaml.tmpl", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ${app}
+spec:
+  template:
+    spec:
+      containers:
+      - name: ${app}
+        image: ${registry}/${app}:${tag}
+        args: ["--home", "$${HOME}"]
+`)
+}
+
+func TestTemplates(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeTemplates(th)
+	th.WriteK("/app", `
+namePrefix: dev-
+templates:
+- files:
+  - deployment.yaml.tmpl
+  params:
+    app: web
+    registry: example.com
+    tag: v1
+- files:
+  - deployment.yaml.tmpl
+  params:
+    app: worker
+    registry: example.com
+    tag: v2
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dev-web
+spec:
+  template:
+    spec:
+      containers:
+      - args:
+        - --home
+        - ${HOME}
+        image: example.com/web:v1
+        name: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dev-worker
+spec:
+  template:
+    spec:
+      containers:
+      - args:
+        - --home
+        - ${HOME}
+        image: example.com/worker:v2
+        name: worker
+`)
+}
+
+func TestTemplatesMissingParam(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeTemplates(th)
+	th.WriteK("/app", `
+templates:
+- files:
+  - deployment.yaml.tmpl
+  params:
+    app: web
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil ||
+		!strings.Contains(err.Error(), "no params for placeholders registry, tag") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// checkTenancy returns an error listing the resources
+// outside the namespaces, or of the cluster scoped
+// kinds, the tenancy policy allows.
+func checkTenancy(t *types.Tenancy, m resmap.ResMap, procs int) error {
+	if t == nil {
+		return nil
+	}
+	// problem[i] is that of the i-th resource, if any
+	problem := make([]string, m.Size())
+	transformers.ForEachResource(m, procs, func(i int, r *resource.Resource) error {
+		id := r.CurId()
+		if !id.IsNamespaceableKind() {
+			if !contains(t.ClusterScopedKinds, id.Kind) {
+				problem[i] = fmt.Sprintf(
+					"%s '%s' is cluster scoped",
+					id.Kind, id.Name)
+			}
+			return nil
+		}
+		ns := id.EffectiveNamespace()
+		if len(t.Namespaces) > 0 && !contains(t.Namespaces, ns) {
+			problem[i] = fmt.Sprintf(
+				"%s '%s' is in namespace '%s'",
+				id.Kind, id.Name, ns)
+		}
+		return nil
+	})
+	var problems []string
+	for _, p := range problem {
+		if p != "" {
+			problems = append(problems, p)
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf(
+		"tenancy allows namespaces %v and cluster scoped kinds %v, but %s",
+		t.Namespaces, t.ClusterScopedKinds, strings.Join(problems, "; "))
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeTenancyBase(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- resources.yaml
+`)
+	th.WriteF("/app/base/resources.yaml", `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: team-a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+`)
+}
+
+func TestTenancyViolations(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	writeTenancyBase(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+tenancy:
+  namespaces:
+  - team-a
+  clusterScopedKinds:
+  - Namespace
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	for _, s := range []string{
+		"Service 'web' is in namespace 'default'",
+		"ClusterRole 'reader' is cluster scoped",
+	} {
+		if !strings.Contains(err.Error(), s) {
+			t.Fatalf("expected %q in error: %v", s, err)
+		}
+	}
+	if strings.Contains(err.Error(), "Namespace 'team-a'") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTenancyAllowed(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	writeTenancyBase(th)
+	th.WriteK("/app/overlay", `
+namespace: team-a
+resources:
+- ../base
+tenancy:
+  namespaces:
+  - team-a
+  clusterScopedKinds:
+  - Namespace
+  - ClusterRole
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
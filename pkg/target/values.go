@@ -0,0 +1,193 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/expansion"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// loadValues loads the catalogs of valuesFrom, in
+// order, later values replacing earlier ones, then
+// setValues, and checks the values against the schemas
+// of all of them, returning the values as strings.
+func (kt *KustTarget) loadValues() (map[string]string, error) {
+	values := make(map[string]interface{})
+	schema := make(map[string]types.ValueSchema)
+	for _, path := range kt.kustomization.ValuesFrom {
+		content, err := kt.ldr.Load(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "valuesFrom %s", path)
+		}
+		if err := kt.rFactory.RF().CheckDuplicateKeys(path, content); err != nil {
+			return nil, err
+		}
+		var c types.ValuesCatalog
+		if err := yaml.UnmarshalStrict(content, &c); err != nil {
+			return nil, errors.Wrapf(err, "valuesFrom %s", path)
+		}
+		for k, v := range c.Values {
+			values[k] = v
+		}
+		for k, s := range c.Schema {
+			schema[k] = s
+		}
+	}
+	for k, v := range kt.setValues {
+		values[k] = scalar(v)
+	}
+	result := make(map[string]string, len(values))
+	var problems []string
+	for _, k := range sortedNames(values, schema) {
+		v, ok := values[k]
+		s := schema[k]
+		if !ok {
+			if s.Required {
+				problems = append(problems, fmt.Sprintf(
+					"value %s is required", k))
+			}
+			continue
+		}
+		str, err := checkValue(k, v, s)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		result[k] = str
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf(
+			"valuesFrom %v: %s",
+			kt.kustomization.ValuesFrom, strings.Join(problems, "; "))
+	}
+	return result, nil
+}
+
+func sortedNames(
+	values map[string]interface{},
+	schema map[string]types.ValueSchema) []string {
+	var result []string
+	for k := range values {
+		result = append(result, k)
+	}
+	for k := range schema {
+		if _, ok := values[k]; !ok {
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// SetValues sets values, e.g. given on the command line,
+// replacing those of the kustomization's valuesFrom, but
+// not those of its bases.
+func (kt *KustTarget) SetValues(values map[string]string) {
+	kt.setValues = values
+}
+
+// checkValue returns the value as a string, or an
+// error if it isn't a scalar the schema allows.
+func checkValue(
+	name string, v interface{}, s types.ValueSchema) (string, error) {
+	if !expansion.IsParamName(name) {
+		return "", fmt.Errorf(
+			"value name '%s' isn't letters, digits and underscores", name)
+	}
+	var str, actual string
+	switch x := v.(type) {
+	case string:
+		str, actual = x, "string"
+	case bool:
+		str, actual = strconv.FormatBool(x), "boolean"
+	case float64:
+		str, actual = strconv.FormatFloat(x, 'f', -1, 64), "number"
+		if x == math.Trunc(x) {
+			actual = "integer"
+		}
+	default:
+		return "", fmt.Errorf(
+			"value %s is %v, rather than a string, number or boolean",
+			name, v)
+	}
+	switch s.Type {
+	case "", actual:
+	case "number":
+		if actual != "integer" {
+			return "", fmt.Errorf(
+				"value %s has type %s, rather than number", name, actual)
+		}
+	case "string", "integer", "boolean":
+		return "", fmt.Errorf(
+			"value %s has type %s, rather than %s", name, actual, s.Type)
+	default:
+		return "", fmt.Errorf(
+			"schema of value %s has type '%s', rather than "+
+				"string, integer, number or boolean", name, s.Type)
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return "", fmt.Errorf(
+				"schema of value %s has pattern '%s': %v", name, s.Pattern, err)
+		}
+		if !re.MatchString(str) {
+			return "", fmt.Errorf(
+				"value %s is '%s', which doesn't match '%s'",
+				name, str, s.Pattern)
+		}
+	}
+	if len(s.Enum) > 0 && !contains(s.Enum, str) {
+		return "", fmt.Errorf(
+			"value %s is '%s', rather than one of %v", name, str, s.Enum)
+	}
+	return str, nil
+}
+
+// scalar returns s, e.g. a value given on the command
+// line, as the number or boolean it spells, if any, and
+// if that's spelled s, so 1.10 stays a string.
+func scalar(s string) interface{} {
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	switch x := v.(type) {
+	case bool:
+		if strconv.FormatBool(x) == s {
+			return x
+		}
+	case float64:
+		if strconv.FormatFloat(x, 'f', -1, 64) == s {
+			return x
+		}
+	}
+	return s
+}
+
+// withValues returns the params of a template, with
+// the values for the placeholders they lack.
+func withValues(
+	values map[string]string, params map[string]string) map[string]string {
+	if len(values) == 0 {
+		return params
+	}
+	result := make(map[string]string, len(values)+len(params))
+	for k, v := range values {
+		result[k] = v
+	}
+	for k, v := range params {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,235 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeValues(th *kusttest_test.KustTestHarness) {
+	th.WriteF("/app/values.yaml", `
+values:
+  registry: example.com
+  domain: example.org
+  replicas: 3
+schema:
+  registry:
+    type: string
+    required: true
+  domain:
+    type: string
+    pattern: '^[a-z0-9.-]+$'
+  replicas:
+    type: integer
+`)
+}
+
+func TestValuesInTemplates(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeTemplates(th)
+	writeValues(th)
+	th.WriteK("/app", `
+valuesFrom:
+- values.yaml
+templates:
+- files:
+  - deployment.yaml.tmpl
+  params:
+    app: web
+    tag: v1
+- files:
+  - deployment.yaml.tmpl
+  params:
+    app: worker
+    registry: other.example.com
+    tag: v2
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - args:
+        - --home
+        - ${HOME}
+        image: example.com/web:v1
+        name: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  template:
+    spec:
+      containers:
+      - args:
+        - --home
+        - ${HOME}
+        image: other.example.com/worker:v2
+        name: worker
+`)
+}
+
+func writeValuesBase(th *kusttest_test.KustTestHarness) {
+	writeValues(th)
+	th.WriteK("/app/base", `
+valuesFrom:
+- ../values.yaml
+resources:
+- deployment.yaml
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: example.com/web:v1
+        env:
+        - name: URL
+          value: https://web.$(domain)
+`)
+}
+
+func TestValuesAsVars(t *testing.T) {
+	th := kusttest_test.NewKustTestNoLoadRestrictorHarness(t, "/app/base")
+	writeValuesBase(th)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+        - name: URL
+          value: https://web.example.org
+        image: example.com/web:v1
+        name: web
+`)
+}
+
+func TestValuesOfOverlayReplaceThoseOfBase(t *testing.T) {
+	th := kusttest_test.NewKustTestNoLoadRestrictorHarness(t, "/app/prod")
+	writeValuesBase(th)
+	th.WriteF("/app/prod/values.yaml", `
+values:
+  domain: prod.example.org
+`)
+	th.WriteK("/app/prod", `
+valuesFrom:
+- ../values.yaml
+- values.yaml
+resources:
+- ../base
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+        - name: URL
+          value: https://web.prod.example.org
+        image: example.com/web:v1
+        name: web
+`)
+}
+
+func TestValuesAgainstSchema(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteF("/app/values.yaml", `
+values:
+  domain: Example.org
+  replicas: 2.5
+  env: qa
+  bad-name: x
+schema:
+  registry:
+    required: true
+  domain:
+    pattern: '^[a-z0-9.-]+$'
+  replicas:
+    type: integer
+  env:
+    enum: [dev, prod]
+`)
+	th.WriteK("/app", `
+valuesFrom:
+- values.yaml
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, want := range []string{
+		"value name 'bad-name' isn't letters, digits and underscores",
+		"value domain is 'Example.org', which doesn't match",
+		"value env is 'qa', rather than one of [dev prod]",
+		"value registry is required",
+		"value replicas has type number, rather than integer",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in: %v", want, err)
+		}
+	}
+}
+
+func TestValueNamedLikeVar(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeValues(th)
+	th.WriteK("/app", `
+valuesFrom:
+- values.yaml
+resources:
+- service.yaml
+vars:
+- name: domain
+  objref:
+    apiVersion: v1
+    kind: Service
+    name: web
+`)
+	th.WriteF("/app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil || !strings.Contains(
+		err.Error(), "var 'domain' has the name of a value") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
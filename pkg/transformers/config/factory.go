@@ -18,6 +18,7 @@ package config
 
 import (
 	"log"
+	"strings"
 
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/yaml"
@@ -29,18 +30,26 @@ type Factory struct {
 }
 
 // MakeTransformerConfig returns a merger of custom config,
-// if any, with default config.
+// if any, with default config.  Custom configs are merged
+// in order, so their behaviors apply to the default config
+// and the custom configs before them.
 func MakeTransformerConfig(
 	ldr ifc.Loader, paths []string) (*TransformerConfig, error) {
 	t1 := MakeDefaultConfig()
-	if len(paths) == 0 {
-		return t1, nil
-	}
-	t2, err := NewFactory(ldr).FromFiles(paths)
-	if err != nil {
-		return nil, err
+	f := NewFactory(ldr)
+	for _, path := range paths {
+		configs, err := f.ConfigsFromFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, t2 := range configs {
+			t1, err = t1.Merge(t2)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
-	return t1.Merge(t2)
+	return t1, nil
 }
 
 func NewFactory(l ifc.Loader) *Factory {
@@ -59,22 +68,73 @@ func (tf *Factory) FromFiles(
 	paths []string) (*TransformerConfig, error) {
 	result := &TransformerConfig{}
 	for _, path := range paths {
-		data, err := tf.loader().Load(path)
+		configs, err := tf.ConfigsFromFile(path)
 		if err != nil {
 			return nil, err
 		}
-		t, err := makeTransformerConfigFromBytes(data)
-		if err != nil {
-			return nil, err
+		for _, t := range configs {
+			result, err = result.Merge(t)
+			if err != nil {
+				return nil, err
+			}
 		}
-		result, err = result.Merge(t)
+	}
+	return result, nil
+}
+
+// FromFile returns a TransformerConfig object from a
+// file, with any behaviors it specifies left in place
+// for a later Merge.
+func (tf *Factory) FromFile(path string) (*TransformerConfig, error) {
+	data, err := tf.loader().Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return makeTransformerConfigFromBytes(data)
+}
+
+// ConfigsFromFile returns a TransformerConfig for each
+// YAML document in a file, e.g. in a bundle of them
+// pulled from an OCI registry, to be merged in turn.
+func (tf *Factory) ConfigsFromFile(path string) ([]*TransformerConfig, error) {
+	data, err := tf.loader().Load(path)
+	if err != nil {
+		return nil, err
+	}
+	var result []*TransformerConfig
+	for _, doc := range splitDocuments(data) {
+		t, err := makeTransformerConfigFromBytes(doc)
 		if err != nil {
 			return nil, err
 		}
+		result = append(result, t)
 	}
 	return result, nil
 }
 
+// splitDocuments returns the non-empty YAML
+// documents in data, split on lines of ---.
+func splitDocuments(data []byte) [][]byte {
+	var result [][]byte
+	var doc []string
+	add := func() {
+		s := strings.Join(doc, "\n")
+		if strings.TrimSpace(s) != "" {
+			result = append(result, []byte(s))
+		}
+		doc = nil
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimRight(line, " \t\r") == "---" {
+			add()
+			continue
+		}
+		doc = append(doc, line)
+	}
+	add()
+	return result
+}
+
 // makeTransformerConfigFromBytes returns a TransformerConfig object from bytes
 func makeTransformerConfigFromBytes(data []byte) (*TransformerConfig, error) {
 	var t TransformerConfig
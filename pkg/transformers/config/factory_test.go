@@ -40,3 +40,33 @@ namePrefix:
 		t.Fatalf("expected %v\n but go6t %v\n", expected, tcfg)
 	}
 }
+
+func TestConfigsFromFile(t *testing.T) {
+	ldr := loadertest.NewFakeLoader("/app")
+	ldr.AddFile("/app/configs.yaml", []byte(`---
+namePrefix:
+- path: nameprefix/path
+  kind: SomeKind
+---
+# Only a comment.
+---
+nameSuffix:
+- path: namesuffix/path
+  kind: SomeKind
+---
+`))
+	configs, err := NewFactory(ldr).ConfigsFromFile("configs.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []*TransformerConfig{
+		{NamePrefix: []FieldSpec{
+			{Gvk: gvk.Gvk{Kind: "SomeKind"}, Path: "nameprefix/path"}}},
+		{},
+		{NameSuffix: []FieldSpec{
+			{Gvk: gvk.Gvk{Kind: "SomeKind"}, Path: "namesuffix/path"}}},
+	}
+	if !reflect.DeepEqual(configs, expected) {
+		t.Fatalf("expected %v\n but got %v\n", expected, configs)
+	}
+}
@@ -45,6 +45,40 @@ type FieldSpec struct {
 	gvk.Gvk            `json:",inline,omitempty" yaml:",inline,omitempty"`
 	Path               string `json:"path,omitempty" yaml:"path,omitempty"`
 	CreateIfNotPresent bool   `json:"create,omitempty" yaml:"create,omitempty"`
+	// Behavior says how this FieldSpec combines with
+	// those already configured; see MergeBehavior.
+	// It's only meaningful in a configuration being
+	// merged, and is cleared by the merge.
+	Behavior MergeBehavior `json:"behavior,omitempty" yaml:"behavior,omitempty"`
+}
+
+// MergeBehavior says how a configuration entry or
+// list combines with one already configured.
+type MergeBehavior string
+
+const (
+	// BehaviorUnspecified is the same as BehaviorMerge.
+	BehaviorUnspecified MergeBehavior = ""
+	// BehaviorMerge adds an entry that isn't already
+	// present, failing if it conflicts with one that is.
+	BehaviorMerge MergeBehavior = "merge"
+	// BehaviorReplace adds an entry, replacing any
+	// present entry with the same key.  For a whole
+	// list, it discards the list already configured.
+	BehaviorReplace MergeBehavior = "replace"
+	// BehaviorRemove removes present entries with the
+	// same key.  An entry with no match is ignored.
+	BehaviorRemove MergeBehavior = "remove"
+)
+
+func (b MergeBehavior) validate() error {
+	switch b {
+	case BehaviorUnspecified, BehaviorMerge, BehaviorReplace, BehaviorRemove:
+		return nil
+	}
+	return fmt.Errorf(
+		"unknown behavior '%s'; expected one of %v", b,
+		[]MergeBehavior{BehaviorMerge, BehaviorReplace, BehaviorRemove})
 }
 
 const (
@@ -117,14 +151,38 @@ func (s fsSlice) mergeAll(incoming fsSlice) (result fsSlice, err error) {
 // If the item's primary key is already present, and there are no
 // conflicts, it is ignored (we don't want duplicates).
 // If there is a conflict, the merge fails.
+// The item's Behavior can ask for it to replace or
+// remove the present item instead.
 func (s fsSlice) mergeOne(x FieldSpec) (fsSlice, error) {
+	b := x.Behavior
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	x.Behavior = BehaviorUnspecified
 	i := s.index(x)
-	if i > -1 {
-		// It's already there.
-		if s[i].CreateIfNotPresent != x.CreateIfNotPresent {
-			return nil, fmt.Errorf("conflicting fieldspecs")
+	switch b {
+	case BehaviorReplace:
+		if i > -1 {
+			result := append(fsSlice{}, s...)
+			result[i] = x
+			return result, nil
+		}
+	case BehaviorRemove:
+		var result fsSlice
+		for _, y := range s {
+			if !y.effectivelyEquals(x) {
+				result = append(result, y)
+			}
+		}
+		return result, nil
+	default:
+		if i > -1 {
+			// It's already there.
+			if s[i].CreateIfNotPresent != x.CreateIfNotPresent {
+				return nil, fmt.Errorf("conflicting fieldspecs")
+			}
+			return s, nil
 		}
-		return s, nil
 	}
 	return append(s, x), nil
 }
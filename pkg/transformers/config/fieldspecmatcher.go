@@ -0,0 +1,107 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"sort"
+	"sync"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+)
+
+// CompiledFieldSpec is a FieldSpec with its path
+// already split by PathSlice.
+type CompiledFieldSpec struct {
+	FieldSpec
+	Segments []string
+}
+
+// FieldSpecMatcher answers which of a list of FieldSpecs
+// select a given Gvk.  The specs are compiled once, into
+// a trie keyed on group, version then kind (the empty key
+// being a wildcard), and the answer for each Gvk is
+// remembered, so a transformer visiting thousands of
+// resources of a handful of kinds neither rescans nor
+// resplits its field specs per resource.
+// It's safe for concurrent use.
+type FieldSpecMatcher struct {
+	specs []CompiledFieldSpec
+	root  *matcherNode
+	mu    sync.Mutex
+	cache map[gvk.Gvk][]CompiledFieldSpec
+}
+
+type matcherNode struct {
+	children map[string]*matcherNode
+	// indices into FieldSpecMatcher.specs
+	specs []int
+}
+
+func (n *matcherNode) child(key string) *matcherNode {
+	if n.children == nil {
+		n.children = make(map[string]*matcherNode)
+	}
+	c, ok := n.children[key]
+	if !ok {
+		c = &matcherNode{}
+		n.children[key] = c
+	}
+	return c
+}
+
+// NewFieldSpecMatcher compiles fss.
+func NewFieldSpecMatcher(fss []FieldSpec) *FieldSpecMatcher {
+	m := &FieldSpecMatcher{
+		root:  &matcherNode{},
+		cache: make(map[gvk.Gvk][]CompiledFieldSpec),
+	}
+	for i, fs := range fss {
+		m.specs = append(m.specs, CompiledFieldSpec{
+			FieldSpec: fs, Segments: fs.PathSlice()})
+		leaf := m.root.child(fs.Group).child(fs.Version).child(fs.Kind)
+		leaf.specs = append(leaf.specs, i)
+	}
+	return m
+}
+
+// Matching returns the specs selecting x, in the order
+// they were given to NewFieldSpecMatcher.  The result
+// is shared, and mustn't be modified.
+func (m *FieldSpecMatcher) Matching(x gvk.Gvk) []CompiledFieldSpec {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if result, ok := m.cache[x]; ok {
+		return result
+	}
+	var indices []int
+	for _, g := range lookup(m.root, x.Group) {
+		for _, v := range lookup(g, x.Version) {
+			for _, k := range lookup(v, x.Kind) {
+				indices = append(indices, k.specs...)
+			}
+		}
+	}
+	sort.Ints(indices)
+	var result []CompiledFieldSpec
+	for _, i := range indices {
+		result = append(result, m.specs[i])
+	}
+	m.cache[x] = result
+	return result
+}
+
+// lookup returns the children of n selecting key, i.e.
+// the one for key itself, and the wildcard.
+func lookup(n *matcherNode, key string) []*matcherNode {
+	var result []*matcherNode
+	if c, ok := n.children[key]; ok {
+		result = append(result, c)
+	}
+	if key != "" {
+		if c, ok := n.children[""]; ok {
+			result = append(result, c)
+		}
+	}
+	return result
+}
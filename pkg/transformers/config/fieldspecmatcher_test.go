@@ -0,0 +1,70 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+)
+
+func TestFieldSpecMatcher(t *testing.T) {
+	fss := []FieldSpec{
+		{Path: "metadata/labels", CreateIfNotPresent: true},
+		{Gvk: gvk.Gvk{Group: "apps", Kind: "Deployment"},
+			Path: "spec/template/metadata/labels"},
+		{Gvk: gvk.Gvk{Kind: "Deployment"},
+			Path: "spec/selector/matchLabels"},
+		{Gvk: gvk.Gvk{Version: "v1", Kind: "Service"},
+			Path: "spec/selector"},
+		{Gvk: gvk.Gvk{Kind: "Ingress"},
+			Path: `metadata/annotations/ingress.kubernetes.io\/auth-secret`},
+	}
+	m := NewFieldSpecMatcher(fss)
+	tests := map[string]struct {
+		x        gvk.Gvk
+		expected []int
+	}{
+		"apps deployment": {
+			gvk.Gvk{Group: "apps", Version: "v1", Kind: "Deployment"},
+			[]int{0, 1, 2}},
+		"extensions deployment": {
+			gvk.Gvk{Group: "extensions", Version: "v1beta1", Kind: "Deployment"},
+			[]int{0, 2}},
+		"service": {
+			gvk.Gvk{Version: "v1", Kind: "Service"},
+			[]int{0, 3}},
+		"other service version": {
+			gvk.Gvk{Version: "v2", Kind: "Service"},
+			[]int{0}},
+		"ingress": {
+			gvk.Gvk{Group: "extensions", Version: "v1beta1", Kind: "Ingress"},
+			[]int{0, 4}},
+		"empty": {
+			gvk.Gvk{},
+			[]int{0}},
+	}
+	for n, tc := range tests {
+		for pass := 0; pass < 2; pass++ {
+			var expected []FieldSpec
+			for _, i := range tc.expected {
+				if !tc.x.IsSelected(&fss[i].Gvk) {
+					t.Fatalf("%s: bad test, %v doesn't select %v", n, fss[i].Gvk, tc.x)
+				}
+				expected = append(expected, fss[i])
+			}
+			var actual []FieldSpec
+			for _, c := range m.Matching(tc.x) {
+				if !reflect.DeepEqual(c.Segments, c.PathSlice()) {
+					t.Fatalf("%s: unexpected segments %v", n, c.Segments)
+				}
+				actual = append(actual, c.FieldSpec)
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("%s: expected %v, got %v", n, expected, actual)
+			}
+		}
+	}
+}
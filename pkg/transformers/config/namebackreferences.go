@@ -53,6 +53,11 @@ import (
 type NameBackReferences struct {
 	gvk.Gvk    `json:",inline,omitempty" yaml:",inline,omitempty"`
 	FieldSpecs fsSlice `json:"FieldSpecs,omitempty" yaml:"FieldSpecs,omitempty"`
+	// Behavior says how this combines with the
+	// NameBackReferences already configured for the
+	// same Gvk: merging FieldSpecs, replacing them
+	// or removing the Gvk entirely.
+	Behavior MergeBehavior `json:"behavior,omitempty" yaml:"behavior,omitempty"`
 }
 
 func (n NameBackReferences) String() string {
@@ -86,19 +91,37 @@ func (s nbrSlice) mergeAll(o nbrSlice) (result nbrSlice, err error) {
 func (s nbrSlice) mergeOne(other NameBackReferences) (nbrSlice, error) {
 	var result nbrSlice
 	var err error
+	b := other.Behavior
+	if err = b.validate(); err != nil {
+		return nil, err
+	}
+	other.Behavior = BehaviorUnspecified
+	incoming := other.FieldSpecs
+	// Used as is, the FieldSpecs mustn't carry behaviors.
+	other.FieldSpecs, err = fsSlice(nil).mergeAll(incoming)
+	if err != nil {
+		return nil, err
+	}
 	found := false
 	for _, c := range s {
 		if c.Gvk.Equals(other.Gvk) {
-			c.FieldSpecs, err = c.FieldSpecs.mergeAll(other.FieldSpecs)
-			if err != nil {
-				return nil, err
-			}
 			found = true
+			switch b {
+			case BehaviorRemove:
+				continue
+			case BehaviorReplace:
+				c = other
+			default:
+				c.FieldSpecs, err = c.FieldSpecs.mergeAll(incoming)
+				if err != nil {
+					return nil, err
+				}
+			}
 		}
 		result = append(result, c)
 	}
 
-	if !found {
+	if !found && b != BehaviorRemove {
 		result = append(result, other)
 	}
 	return result, nil
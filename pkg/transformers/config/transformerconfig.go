@@ -19,8 +19,10 @@ limitations under the License.
 package config
 
 import (
+	"fmt"
 	"log"
 	"sort"
+	"strings"
 
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config/defaultconfig"
 )
@@ -36,6 +38,60 @@ type TransformerConfig struct {
 	VarReference      fsSlice  `json:"varReference,omitempty" yaml:"varReference,omitempty"`
 	Images            fsSlice  `json:"images,omitempty" yaml:"images,omitempty"`
 	Replicas          fsSlice  `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+
+	// Behaviors maps the field names above, e.g.
+	// commonLabels, to how the list given here combines
+	// with the list already configured when merged;
+	// BehaviorMerge (the default) or BehaviorReplace.
+	// Entries in the lists may carry their own behavior.
+	Behaviors map[string]MergeBehavior `json:"behaviors,omitempty" yaml:"behaviors,omitempty"`
+}
+
+// listNames are the field names of the lists in
+// a TransformerConfig, in declaration order.
+var listNames = []string{
+	"namePrefix", "nameSuffix", "namespace", "commonLabels",
+	"commonAnnotations", "nameReference", "varReference",
+	"images", "replicas",
+}
+
+// ListName returns the field name of the list in a
+// TransformerConfig matching the argument, ignoring
+// case, e.g. "nameReference" for "namereference".
+func ListName(name string) (string, error) {
+	for _, n := range listNames {
+		if strings.EqualFold(n, name) {
+			return n, nil
+		}
+	}
+	return "", fmt.Errorf(
+		"unknown transformer configuration '%s'; expected one of %v",
+		name, listNames)
+}
+
+func (t *TransformerConfig) validateBehaviors() error {
+	for name, b := range t.Behaviors {
+		known := false
+		for _, n := range listNames {
+			if n == name {
+				known = true
+			}
+		}
+		if !known {
+			return fmt.Errorf(
+				"behavior given for unknown field '%s'; expected one of %v",
+				name, listNames)
+		}
+		if b == BehaviorRemove {
+			return fmt.Errorf(
+				"behavior '%s' for field '%s' must be '%s' or '%s'",
+				b, name, BehaviorMerge, BehaviorReplace)
+		}
+		if err := b.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // MakeEmptyConfig returns an empty TransformerConfig object
@@ -97,47 +153,69 @@ func (t *TransformerConfig) AddNamereferenceFieldSpec(
 }
 
 // Merge merges two TransformerConfigs objects into
-// a new TransformerConfig object
+// a new TransformerConfig object, following the
+// behaviors given in the input.  The result has
+// no behaviors.
 func (t *TransformerConfig) Merge(input *TransformerConfig) (
 	merged *TransformerConfig, err error) {
 	if input == nil {
 		return t, nil
 	}
+	if err = input.validateBehaviors(); err != nil {
+		return nil, err
+	}
+	base := func(name string, s fsSlice) fsSlice {
+		if input.Behaviors[name] == BehaviorReplace {
+			return nil
+		}
+		return s
+	}
 	merged = &TransformerConfig{}
-	merged.NamePrefix, err = t.NamePrefix.mergeAll(input.NamePrefix)
+	merged.NamePrefix, err = base(
+		"namePrefix", t.NamePrefix).mergeAll(input.NamePrefix)
 	if err != nil {
 		return nil, err
 	}
-	merged.NameSuffix, err = t.NameSuffix.mergeAll(input.NameSuffix)
+	merged.NameSuffix, err = base(
+		"nameSuffix", t.NameSuffix).mergeAll(input.NameSuffix)
 	if err != nil {
 		return nil, err
 	}
-	merged.NameSpace, err = t.NameSpace.mergeAll(input.NameSpace)
+	merged.NameSpace, err = base(
+		"namespace", t.NameSpace).mergeAll(input.NameSpace)
 	if err != nil {
 		return nil, err
 	}
-	merged.CommonAnnotations, err = t.CommonAnnotations.mergeAll(
+	merged.CommonAnnotations, err = base(
+		"commonAnnotations", t.CommonAnnotations).mergeAll(
 		input.CommonAnnotations)
 	if err != nil {
 		return nil, err
 	}
-	merged.CommonLabels, err = t.CommonLabels.mergeAll(input.CommonLabels)
+	merged.CommonLabels, err = base(
+		"commonLabels", t.CommonLabels).mergeAll(input.CommonLabels)
 	if err != nil {
 		return nil, err
 	}
-	merged.VarReference, err = t.VarReference.mergeAll(input.VarReference)
+	merged.VarReference, err = base(
+		"varReference", t.VarReference).mergeAll(input.VarReference)
 	if err != nil {
 		return nil, err
 	}
-	merged.NameReference, err = t.NameReference.mergeAll(input.NameReference)
+	nameReference := t.NameReference
+	if input.Behaviors["nameReference"] == BehaviorReplace {
+		nameReference = nil
+	}
+	merged.NameReference, err = nameReference.mergeAll(input.NameReference)
 	if err != nil {
 		return nil, err
 	}
-	merged.Images, err = t.Images.mergeAll(input.Images)
+	merged.Images, err = base("images", t.Images).mergeAll(input.Images)
 	if err != nil {
 		return nil, err
 	}
-	merged.Replicas, err = t.Replicas.mergeAll(input.Replicas)
+	merged.Replicas, err = base(
+		"replicas", t.Replicas).mergeAll(input.Replicas)
 	if err != nil {
 		return nil, err
 	}
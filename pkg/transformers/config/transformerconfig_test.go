@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"strings"
 	"testing"
 
 	"reflect"
@@ -180,3 +181,158 @@ func TestMerge(t *testing.T) {
 		t.Fatalf("expected: %v\n but got: %v\n", cfga, actual)
 	}
 }
+
+func TestMergeBehaviors(t *testing.T) {
+	deployLabels := FieldSpec{
+		Gvk:                gvk.Gvk{Group: "apps", Kind: "Deployment"},
+		Path:               "spec/template/metadata/labels",
+		CreateIfNotPresent: true,
+	}
+	jobLabels := FieldSpec{
+		Gvk:                gvk.Gvk{Group: "batch", Kind: "Job"},
+		Path:               "spec/template/metadata/labels",
+		CreateIfNotPresent: true,
+	}
+	podRefs := FieldSpec{
+		Gvk:  gvk.Gvk{Kind: "Pod"},
+		Path: "spec/volumes/configMap/name",
+	}
+	deployRefs := FieldSpec{
+		Gvk:  gvk.Gvk{Kind: "Deployment"},
+		Path: "spec/template/spec/volumes/configMap/name",
+	}
+	base := &TransformerConfig{}
+	base.AddLabelFieldSpec(deployLabels)
+	base.AddLabelFieldSpec(jobLabels)
+	base.AddNamereferenceFieldSpec(NameBackReferences{
+		Gvk:        gvk.Gvk{Kind: "ConfigMap"},
+		FieldSpecs: fsSlice{podRefs, deployRefs},
+	})
+	base.AddNamereferenceFieldSpec(NameBackReferences{
+		Gvk:        gvk.Gvk{Kind: "Secret"},
+		FieldSpecs: fsSlice{podRefs},
+	})
+
+	testCases := map[string]struct {
+		input    string
+		expected func() *TransformerConfig
+		err      string
+	}{
+		"removeEntry": {
+			input: `
+commonLabels:
+- kind: Deployment
+  path: spec/template/metadata/labels
+  behavior: remove
+`,
+			expected: func() *TransformerConfig {
+				e := &TransformerConfig{}
+				e.AddLabelFieldSpec(jobLabels)
+				e.NameReference = base.NameReference
+				return e
+			},
+		},
+		"replaceEntry": {
+			input: `
+commonLabels:
+- group: batch
+  kind: Job
+  path: spec/template/metadata/labels
+  behavior: replace
+`,
+			expected: func() *TransformerConfig {
+				e := &TransformerConfig{}
+				e.AddLabelFieldSpec(deployLabels)
+				j := jobLabels
+				j.CreateIfNotPresent = false
+				e.AddLabelFieldSpec(j)
+				e.NameReference = base.NameReference
+				return e
+			},
+		},
+		"replaceList": {
+			input: `
+behaviors:
+  commonLabels: replace
+commonLabels:
+- kind: MyKind
+  path: spec/labels
+`,
+			expected: func() *TransformerConfig {
+				e := &TransformerConfig{}
+				e.AddLabelFieldSpec(FieldSpec{
+					Gvk: gvk.Gvk{Kind: "MyKind"}, Path: "spec/labels"})
+				e.NameReference = base.NameReference
+				return e
+			},
+		},
+		"nameReference": {
+			input: `
+nameReference:
+- kind: ConfigMap
+  FieldSpecs:
+  - kind: Pod
+    path: spec/volumes/configMap/name
+    behavior: remove
+- kind: Secret
+  behavior: remove
+`,
+			expected: func() *TransformerConfig {
+				e := &TransformerConfig{}
+				e.AddLabelFieldSpec(deployLabels)
+				e.AddLabelFieldSpec(jobLabels)
+				e.AddNamereferenceFieldSpec(NameBackReferences{
+					Gvk:        gvk.Gvk{Kind: "ConfigMap"},
+					FieldSpecs: fsSlice{deployRefs},
+				})
+				return e
+			},
+		},
+		"conflictWithoutBehavior": {
+			input: `
+commonLabels:
+- group: batch
+  kind: Job
+  path: spec/template/metadata/labels
+`,
+			err: "conflicting fieldspecs",
+		},
+		"unknownBehavior": {
+			input: `
+commonLabels:
+- kind: Job
+  path: a/b
+  behavior: delete
+`,
+			err: "unknown behavior 'delete'",
+		},
+		"unknownList": {
+			input: `
+behaviors:
+  labels: replace
+`,
+			err: "unknown field 'labels'",
+		},
+	}
+	for n, tc := range testCases {
+		input, err := makeTransformerConfigFromBytes([]byte(tc.input))
+		if err != nil {
+			t.Fatalf("%s: unexpected err: %v", n, err)
+		}
+		actual, err := base.Merge(input)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error %q, got %v", n, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected err: %v", n, err)
+		}
+		expected := tc.expected()
+		expected.sortFields()
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected: %v\n but got: %v\n", n, expected, actual)
+		}
+	}
+}
@@ -21,13 +21,15 @@ import (
 	"fmt"
 
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
 )
 
 // mapTransformer applies a string->string map to fieldSpecs.
 type mapTransformer struct {
 	m          map[string]string
-	fieldSpecs []config.FieldSpec
+	fieldSpecs *config.FieldSpecMatcher
+	procs      int
 }
 
 var _ Transformer = &mapTransformer{}
@@ -35,44 +37,44 @@ var _ Transformer = &mapTransformer{}
 // NewLabelsMapTransformer constructs a mapTransformer.
 func NewLabelsMapTransformer(
 	m map[string]string, fs []config.FieldSpec) (Transformer, error) {
-	return NewMapTransformer(fs, m)
+	return NewMapTransformer(fs, m, 1)
 }
 
 // NewAnnotationsMapTransformer construct a mapTransformer.
 func NewAnnotationsMapTransformer(
 	m map[string]string, fs []config.FieldSpec) (Transformer, error) {
-	return NewMapTransformer(fs, m)
+	return NewMapTransformer(fs, m, 1)
 }
 
-// NewMapTransformer construct a mapTransformer.
+// NewMapTransformer construct a mapTransformer, which
+// spreads its work over up to procs goroutines.
 func NewMapTransformer(
-	pc []config.FieldSpec, m map[string]string) (Transformer, error) {
+	pc []config.FieldSpec, m map[string]string,
+	procs int) (Transformer, error) {
 	if m == nil {
 		return NewNoOpTransformer(), nil
 	}
 	if pc == nil {
 		return nil, errors.New("fieldSpecs is not expected to be nil")
 	}
-	return &mapTransformer{fieldSpecs: pc, m: m}, nil
+	return &mapTransformer{
+		fieldSpecs: config.NewFieldSpecMatcher(pc), m: m, procs: procs}, nil
 }
 
 // Transform apply each <key, value> pair in the mapTransformer to the
 // fields specified in mapTransformer.
 func (o *mapTransformer) Transform(m resmap.ResMap) error {
-	for _, r := range m.Resources() {
-		for _, path := range o.fieldSpecs {
-			if !r.OrgId().IsSelected(&path.Gvk) {
-				continue
-			}
+	return ForEachResource(m, o.procs, func(_ int, r *resource.Resource) error {
+		for _, path := range o.fieldSpecs.Matching(r.OrgId().Gvk) {
 			err := MutateField(
-				r.Map(), path.PathSlice(),
+				r.Map(), path.Segments,
 				path.CreateIfNotPresent, o.addMap)
 			if err != nil {
 				return err
 			}
 		}
-	}
-	return nil
+		return nil
+	})
 }
 
 func (o *mapTransformer) addMap(in interface{}) (interface{}, error) {
@@ -16,6 +16,8 @@ import (
 
 type nameReferenceTransformer struct {
 	backRefs []config.NameBackReferences
+	// matchers[i] matches backRefs[i].FieldSpecs
+	matchers []*config.FieldSpecMatcher
 }
 
 var _ Transformer = &nameReferenceTransformer{}
@@ -26,7 +28,11 @@ func NewNameReferenceTransformer(br []config.NameBackReferences) Transformer {
 	if br == nil {
 		log.Fatal("backrefs not expected to be nil")
 	}
-	return &nameReferenceTransformer{backRefs: br}
+	matchers := make([]*config.FieldSpecMatcher, len(br))
+	for i := range br {
+		matchers[i] = config.NewFieldSpecMatcher(br[i].FieldSpecs)
+	}
+	return &nameReferenceTransformer{backRefs: br, matchers: matchers}
 }
 
 // Transform updates name references in resource A that
@@ -77,28 +83,25 @@ func NewNameReferenceTransformer(br []config.NameBackReferences) Transformer {
 // body of the resource object (the value in the ResMap).
 //
 func (o *nameReferenceTransformer) Transform(m resmap.ResMap) error {
-	// TODO: Too much looping, here and in transitive calls.
 	for _, referrer := range m.Resources() {
 		var candidates resmap.ResMap
-		for _, target := range o.backRefs {
-			for _, fSpec := range target.FieldSpecs {
-				if referrer.OrgId().IsSelected(&fSpec.Gvk) {
-					if candidates == nil {
-						candidates = m.SubsetThatCouldBeReferencedByResource(referrer)
-					}
-					err := MutateField(
-						referrer.Map(),
-						fSpec.PathSlice(),
-						fSpec.CreateIfNotPresent,
-						o.getNewNameFunc(
-							// referrer could be an HPA instance,
-							// target could be Gvk for Deployment,
-							// candidate a list of resources "reachable"
-							// from the HPA.
-							referrer, target.Gvk, candidates))
-					if err != nil {
-						return err
-					}
+		for i, target := range o.backRefs {
+			for _, fSpec := range o.matchers[i].Matching(referrer.OrgId().Gvk) {
+				if candidates == nil {
+					candidates = m.SubsetThatCouldBeReferencedByResource(referrer)
+				}
+				err := MutateField(
+					referrer.Map(),
+					fSpec.Segments,
+					fSpec.CreateIfNotPresent,
+					o.getNewNameFunc(
+						// referrer could be an HPA instance,
+						// target could be Gvk for Deployment,
+						// candidate a list of resources "reachable"
+						// from the HPA.
+						referrer, target.Gvk, candidates))
+				if err != nil {
+					return err
 				}
 			}
 		}
@@ -26,7 +26,7 @@ import (
 type RefVarTransformer struct {
 	varMap            map[string]interface{}
 	replacementCounts map[string]int
-	fieldSpecs        []config.FieldSpec
+	fieldSpecs        *config.FieldSpecMatcher
 	mappingFunc       func(string) interface{}
 }
 
@@ -37,7 +37,7 @@ func NewRefVarTransformer(
 	varMap map[string]interface{}, fs []config.FieldSpec) *RefVarTransformer {
 	return &RefVarTransformer{
 		varMap:     varMap,
-		fieldSpecs: fs,
+		fieldSpecs: config.NewFieldSpecMatcher(fs),
 	}
 }
 
@@ -106,13 +106,11 @@ func (rv *RefVarTransformer) Transform(m resmap.ResMap) error {
 	rv.mappingFunc = expansion.MappingFuncFor(
 		rv.replacementCounts, rv.varMap)
 	for _, res := range m.Resources() {
-		for _, fieldSpec := range rv.fieldSpecs {
-			if res.OrgId().IsSelected(&fieldSpec.Gvk) {
-				if err := MutateField(
-					res.Map(), fieldSpec.PathSlice(),
-					false, rv.replaceVars); err != nil {
-					return err
-				}
+		for _, fieldSpec := range rv.fieldSpecs.Matching(res.OrgId().Gvk) {
+			if err := MutateField(
+				res.Map(), fieldSpec.Segments,
+				false, rv.replaceVars); err != nil {
+				return err
 			}
 		}
 	}
@@ -0,0 +1,47 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+// RemoveField removes the field at the given path, if
+// present, from each element of any list on the way,
+// then removes the maps left empty by that.
+// It returns true if it removed anything.
+func RemoveField(m map[string]interface{}, pathToField []string) bool {
+	removed, _ := removeField(m, pathToField)
+	return removed
+}
+
+// removeField returns whether it removed anything,
+// and whether that left m empty.
+func removeField(
+	m map[string]interface{}, pathToField []string) (bool, bool) {
+	if len(pathToField) == 0 {
+		return false, false
+	}
+	key := pathToField[0]
+	v, found := m[key]
+	if !found {
+		return false, false
+	}
+	if len(pathToField) == 1 {
+		delete(m, key)
+		return true, len(m) == 0
+	}
+	removed, empty := false, false
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		removed, empty = removeField(typed, pathToField[1:])
+	case []interface{}:
+		for _, item := range typed {
+			if itemMap, ok := item.(map[string]interface{}); ok {
+				r, _ := removeField(itemMap, pathToField[1:])
+				removed = removed || r
+			}
+		}
+	}
+	if empty {
+		delete(m, key)
+	}
+	return removed, len(m) == 0
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemoveField(t *testing.T) {
+	type testCase struct {
+		description string
+		path        []string
+		removed     bool
+		expected    map[string]interface{}
+	}
+	makeObj := func() map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "svc",
+				"annotations": map[string]interface{}{
+					"deployment.kubernetes.io/revision": "3",
+				},
+			},
+			"spec": map[string]interface{}{
+				"clusterIP": "10.0.0.1",
+				"ports": []interface{}{
+					map[string]interface{}{"port": 80, "nodePort": 30080},
+					map[string]interface{}{"port": 443},
+				},
+			},
+		}
+	}
+	testCases := []testCase{
+		{
+			description: "scalar",
+			path:        []string{"spec", "clusterIP"},
+			removed:     true,
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name": "svc",
+					"annotations": map[string]interface{}{
+						"deployment.kubernetes.io/revision": "3",
+					},
+				},
+				"spec": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{"port": 80, "nodePort": 30080},
+						map[string]interface{}{"port": 443},
+					},
+				},
+			},
+		},
+		{
+			description: "last of a map",
+			path: []string{
+				"metadata", "annotations", "deployment.kubernetes.io/revision"},
+			removed: true,
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name": "svc",
+				},
+				"spec": map[string]interface{}{
+					"clusterIP": "10.0.0.1",
+					"ports": []interface{}{
+						map[string]interface{}{"port": 80, "nodePort": 30080},
+						map[string]interface{}{"port": 443},
+					},
+				},
+			},
+		},
+		{
+			description: "in list",
+			path:        []string{"spec", "ports", "nodePort"},
+			removed:     true,
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name": "svc",
+					"annotations": map[string]interface{}{
+						"deployment.kubernetes.io/revision": "3",
+					},
+				},
+				"spec": map[string]interface{}{
+					"clusterIP": "10.0.0.1",
+					"ports": []interface{}{
+						map[string]interface{}{"port": 80},
+						map[string]interface{}{"port": 443},
+					},
+				},
+			},
+		},
+		{
+			description: "absent",
+			path:        []string{"spec", "selector", "app"},
+			removed:     false,
+			expected:    makeObj(),
+		},
+	}
+	for _, tc := range testCases {
+		obj := makeObj()
+		removed := RemoveField(obj, tc.path)
+		if removed != tc.removed {
+			t.Errorf("%s: expected removed %v", tc.description, tc.removed)
+		}
+		if !reflect.DeepEqual(obj, tc.expected) {
+			t.Errorf("%s: expected\n%v\nbut got\n%v",
+				tc.description, tc.expected, obj)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"sync"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// ForEachResource calls f on each resource of m, with its
+// index, for transformers that change or check resources
+// independently of each other.  The resources are split
+// into up to procs contiguous shards, run in parallel, e.g.
+// the MaxProcs of a resmap.Factory; 1 runs serially.
+// The error returned is that of the first failing resource
+// in m's order, so it doesn't depend on scheduling.
+func ForEachResource(
+	m resmap.ResMap, procs int,
+	f func(int, *resource.Resource) error) error {
+	rs := m.Resources()
+	n := procs
+	if n > len(rs) {
+		n = len(rs)
+	}
+	if n <= 1 {
+		for i, r := range rs {
+			if err := f(i, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	size := (len(rs) + n - 1) / n
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for s := 0; s*size < len(rs); s++ {
+		lo, hi := s*size, (s+1)*size
+		if hi > len(rs) {
+			hi = len(rs)
+		}
+		wg.Add(1)
+		go func(s, lo, hi int) {
+			defer wg.Done()
+			for i := lo; i < hi; i++ {
+				if err := f(i, rs[i]); err != nil {
+					errs[s] = err
+					return
+				}
+			}
+		}(s, lo, hi)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
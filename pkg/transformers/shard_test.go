@@ -0,0 +1,49 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"fmt"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resmaptest"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+func TestForEachResource(t *testing.T) {
+	b := resmaptest_test.NewRmBuilder(t, rf)
+	for i := 0; i < 10; i++ {
+		b.Add(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name": fmt.Sprintf("cm%d", i),
+			}})
+	}
+	m := b.ResMap()
+	for _, procs := range []int{1, 3, 4, 20} {
+		visited := make([]string, m.Size())
+		err := ForEachResource(m, procs, func(i int, r *resource.Resource) error {
+			visited[i] = r.GetName()
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, name := range visited {
+			if name != fmt.Sprintf("cm%d", i) {
+				t.Fatalf("%d procs: resource %d visited as %q", procs, i, name)
+			}
+		}
+		err = ForEachResource(m, procs, func(i int, r *resource.Resource) error {
+			if i == 5 || i == 8 {
+				return fmt.Errorf("failed on %s", r.GetName())
+			}
+			return nil
+		})
+		if err == nil || err.Error() != "failed on cm5" {
+			t.Fatalf("%d procs: unexpected error %v", procs, err)
+		}
+	}
+}
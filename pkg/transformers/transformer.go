@@ -31,3 +31,16 @@ type Transformer interface {
 type Generator interface {
 	Generate() (resmap.ResMap, error)
 }
+
+// An Exporter renders a finished resmap.ResMap in some
+// format other than Kubernetes YAML, e.g. docker-compose.
+type Exporter interface {
+	Export(m resmap.ResMap) ([]byte, error)
+}
+
+// An Expectation checks a finished resmap.ResMap, e.g.
+// against a convention of the team owning it, returning
+// an error naming the resources that break it.
+type Expectation interface {
+	Check(m resmap.ResMap) error
+}
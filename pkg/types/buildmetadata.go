@@ -0,0 +1,43 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// Values of the buildMetadata field.
+func isBuildMetadataOption(m string) bool {
+	for _, o := range BuildMetadataOptions {
+		if m == o {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// OriginAnnotations adds an OriginAnnotation to
+	// every resource.
+	OriginAnnotations = "originAnnotations"
+
+	// TransformerAnnotations adds a TransformerAnnotation
+	// to every resource a transformer changes.
+	TransformerAnnotations = "transformerAnnotations"
+)
+
+// BuildMetadataOptions lists the legal buildMetadata values.
+var BuildMetadataOptions = []string{
+	OriginAnnotations,
+	TransformerAnnotations,
+}
+
+const (
+	// OriginAnnotation holds the path, relative to the
+	// kustomization being built, of the file a resource
+	// was read from, or of the kustomization file whose
+	// generators made it.
+	OriginAnnotation = "config.kubernetes.io/origin"
+
+	// TransformerAnnotation holds a comma separated list
+	// of the transformers that changed a resource, in the
+	// order they ran, e.g. PatchTransformer,LabelTransformer.
+	TransformerAnnotation = "config.kubernetes.io/transformations"
+)
@@ -0,0 +1,49 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/image"
+)
+
+// Cluster names one of the clusters a kustomization is
+// built for, and the overrides its output needs.
+type Cluster struct {
+	// Name identifies the cluster, e.g. in the output
+	// directory, so it must be usable as a file name.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Namespace, if set, replaces the namespace field.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// CommonLabels are added to those of the commonLabels
+	// field, replacing any with the same key.
+	CommonLabels map[string]string `json:"commonLabels,omitempty" yaml:"commonLabels,omitempty"`
+
+	// Images replace those of the images field with the
+	// same name, or are added to them.
+	Images []image.Image `json:"images,omitempty" yaml:"images,omitempty"`
+}
+
+func checkClusters(clusters []Cluster) []string {
+	var errs []string
+	seen := make(map[string]bool)
+	for _, c := range clusters {
+		switch {
+		case c.Name == "" || c.Name == "." || c.Name == "..":
+			errs = append(errs, fmt.Sprintf(
+				"cluster name '%s' is invalid", c.Name))
+		case strings.ContainsAny(c.Name, `/\`):
+			errs = append(errs, fmt.Sprintf(
+				"cluster name %s must not contain path separators", c.Name))
+		case seen[c.Name]:
+			errs = append(errs, "duplicate cluster name "+c.Name)
+		}
+		seen[c.Name] = true
+	}
+	return errs
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+const (
+	// DependsOnAnnotation holds a comma separated list of
+	// the resources a resource depends on, each as Kind/name,
+	// e.g. "StatefulSet/db,ConfigMap/config", named as in
+	// the kustomization, before prefixes, suffixes and hashes.
+	DependsOnAnnotation = "kustomize.config.k8s.io/depends-on"
+
+	// ApplyDependsOnAnnotation is the annotation apply tools,
+	// e.g. those of sigs.k8s.io/cli-utils, read to apply a
+	// resource, and wait for it to be ready, before those
+	// depending on it.  The build writes it from the
+	// DependsOnAnnotation and the dependsOn field.
+	ApplyDependsOnAnnotation = "config.kubernetes.io/depends-on"
+)
+
+// DependsOn says that a resource depends on others.
+type DependsOn struct {
+	// Resource is the kind and name, e.g. Deployment/app,
+	// of the dependent resource, named as in the
+	// kustomization, before prefixes, suffixes and hashes.
+	Resource string `json:"resource" yaml:"resource"`
+
+	// Dependencies lists the resources it depends
+	// on, likewise.
+	Dependencies []string `json:"dependencies" yaml:"dependencies"`
+}
@@ -17,10 +17,87 @@ limitations under the License.
 package types
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
 
+// GeneratedHashAnnotation holds, on pod templates, a hash
+// of the contents of the generated resources they refer to
+// that have the HashAnnotation generator option.
+const GeneratedHashAnnotation = "config.kubernetes.io/generated-hash"
+
+// Values of the HashIncludes generator option.
+const (
+	// HashIncludesLabels hashes the labels.
+	HashIncludesLabels = "labels"
+	// HashIncludesAnnotations hashes the annotations,
+	// but those of config.kubernetes.io and
+	// kustomize.config.k8s.io, kustomize's own.
+	HashIncludesAnnotations = "annotations"
+)
+
+// HashIncludesOptions are the legal HashIncludes values.
+var HashIncludesOptions = []string{
+	HashIncludesLabels,
+	HashIncludesAnnotations,
+}
+
+func checkHashIncludes(includes []string) []string {
+	var errs []string
+	for _, s := range includes {
+		if s != HashIncludesLabels && s != HashIncludesAnnotations {
+			errs = append(errs, fmt.Sprintf(
+				"generatorOptions hashIncludes value %s should be one of %v",
+				s, HashIncludesOptions))
+		}
+	}
+	return errs
+}
+
+// MergeGlobalOptionsIntoLocal returns the options of
+// a generator, local, merged into the kustomization's,
+// global.  Labels and annotations of local win over
+// those of global; a flag either sets wins; local's
+// HashIncludes, if any, replace global's.  Either
+// may be nil, as may the result.
+func MergeGlobalOptionsIntoLocal(
+	local, global *GeneratorOptions) *GeneratorOptions {
+	if local == nil {
+		return global
+	}
+	if global == nil {
+		return local
+	}
+	merged := &GeneratorOptions{
+		Labels:                mergeStringMaps(global.Labels, local.Labels),
+		Annotations:           mergeStringMaps(global.Annotations, local.Annotations),
+		DisableNameSuffixHash: global.DisableNameSuffixHash || local.DisableNameSuffixHash,
+		HashAnnotation:        global.HashAnnotation || local.HashAnnotation,
+		HashIncludes:          global.HashIncludes,
+	}
+	if len(local.HashIncludes) > 0 {
+		merged.HashIncludes = local.HashIncludes
+	}
+	return merged
+}
+
+// mergeStringMaps returns a copy of a with the
+// entries of b, nil if both are empty.
+func mergeStringMaps(a, b map[string]string) map[string]string {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(a)+len(b))
+	for k, v := range a {
+		m[k] = v
+	}
+	for k, v := range b {
+		m[k] = v
+	}
+	return m
+}
+
 // GenArgs contains both generator args and options
 type GenArgs struct {
 	args *GeneratorArgs
@@ -50,9 +127,41 @@ func (g *GenArgs) String() string {
 // NeedsHashSuffix returns true if the hash suffix is needed.
 // It is needed when the two conditions are both met
 //  1) GenArgs is not nil
-//  2) DisableNameSuffixHash in GeneratorOptions is not set to true
+//  2) DisableNameSuffixHash and HashAnnotation in
+//     GeneratorOptions are not set to true
 func (g *GenArgs) NeedsHashSuffix() bool {
-	return g.args != nil && (g.opts == nil || g.opts.DisableNameSuffixHash == false)
+	return g.args != nil && (g.opts == nil ||
+		(g.opts.DisableNameSuffixHash == false && g.opts.HashAnnotation == false))
+}
+
+// NeedsHashAnnotation returns true if the hash is to be written
+// to the pod templates referring to the generated resource.
+func (g *GenArgs) NeedsHashAnnotation() bool {
+	return g.args != nil && g.opts != nil && g.opts.HashAnnotation
+}
+
+// HashIncludes returns the metadata the hash covers,
+// of HashIncludesOptions.
+func (g *GenArgs) HashIncludes() []string {
+	if g.args == nil || g.opts == nil {
+		return nil
+	}
+	return g.opts.HashIncludes
+}
+
+// OverriddenBy returns the GenArgs of a resource that
+// o, of a generator replacing or merging with it, gives
+// its options to, as MergeGlobalOptionsIntoLocal merges
+// them: those o sets win, the others g's are kept.  The
+// GeneratorArgs, so the behavior, are g's.
+func (g *GenArgs) OverriddenBy(o *GenArgs) *GenArgs {
+	if g == nil || o == nil {
+		return g
+	}
+	return &GenArgs{
+		args: g.args,
+		opts: MergeGlobalOptionsIntoLocal(o.opts, g.opts),
+	}
 }
 
 // Behavior returns Behavior field of GeneratorArgs
@@ -17,6 +17,7 @@ limitations under the License.
 package types_test
 
 import (
+	"reflect"
 	"testing"
 
 	. "sigs.k8s.io/kustomize/v3/pkg/types"
@@ -41,6 +42,12 @@ func TestGenArgs_String(t *testing.T) {
 				&GeneratorOptions{DisableNameSuffixHash: false}),
 			expected: "{nsfx:true,beh:merge}",
 		},
+		{
+			ga: NewGenArgs(
+				&GeneratorArgs{Behavior: "merge"},
+				&GeneratorOptions{HashAnnotation: true}),
+			expected: "{nsfx:false,beh:merge}",
+		},
 	}
 	for _, test := range tests {
 		if test.ga.String() != test.expected {
@@ -48,3 +55,51 @@ func TestGenArgs_String(t *testing.T) {
 		}
 	}
 }
+
+func TestMergeGlobalOptionsIntoLocal(t *testing.T) {
+	global := &GeneratorOptions{
+		Labels:       map[string]string{"a": "global", "b": "global"},
+		Annotations:  map[string]string{"c": "global"},
+		HashIncludes: []string{HashIncludesLabels},
+	}
+	local := &GeneratorOptions{
+		Labels:                map[string]string{"b": "local"},
+		DisableNameSuffixHash: true,
+	}
+	if MergeGlobalOptionsIntoLocal(nil, global) != global ||
+		MergeGlobalOptionsIntoLocal(local, nil) != local ||
+		MergeGlobalOptionsIntoLocal(nil, nil) != nil {
+		t.Fatal("expected the options given if only one is")
+	}
+	expected := &GeneratorOptions{
+		Labels:                map[string]string{"a": "global", "b": "local"},
+		Annotations:           map[string]string{"c": "global"},
+		DisableNameSuffixHash: true,
+		HashIncludes:          []string{HashIncludesLabels},
+	}
+	actual := MergeGlobalOptionsIntoLocal(local, global)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, actual)
+	}
+	if global.Labels["b"] != "global" {
+		t.Fatal("global options changed")
+	}
+}
+
+func TestGenArgs_OverriddenBy(t *testing.T) {
+	base := NewGenArgs(
+		&GeneratorArgs{Behavior: "create"},
+		&GeneratorOptions{HashIncludes: []string{HashIncludesLabels}})
+	overlay := NewGenArgs(
+		&GeneratorArgs{Behavior: "replace"},
+		&GeneratorOptions{DisableNameSuffixHash: true})
+	g := base.OverriddenBy(overlay)
+	if g.Behavior() != BehaviorCreate || g.NeedsHashSuffix() ||
+		!reflect.DeepEqual(g.HashIncludes(), []string{HashIncludesLabels}) {
+		t.Fatalf("unexpected %v, hashIncludes %v", g, g.HashIncludes())
+	}
+	g = base.OverriddenBy(NewGenArgs(&GeneratorArgs{Behavior: "replace"}, nil))
+	if !g.NeedsHashSuffix() || len(g.HashIncludes()) != 1 {
+		t.Fatalf("expected the base's options, got %v", g)
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"regexp"
+	"strings"
+)
+
+// HashPlaceholder, in a GenerateNameSuffix, is replaced
+// by a hash of the content of each resource named.
+const HashPlaceholder = "{hash}"
+
+var generateNameSuffixPattern = regexp.MustCompile(`^[a-z0-9.-]*$`)
+
+func checkGenerateNameSuffix(s string) []string {
+	if generateNameSuffixPattern.MatchString(
+		strings.Replace(s, HashPlaceholder, "", -1)) {
+		return nil
+	}
+	return []string{
+		"generateNameSuffix should have only lowercase letters, " +
+			"digits, '-', '.' and " + HashPlaceholder}
+}
@@ -5,8 +5,12 @@
 package types
 
 import (
+	"fmt"
+	"path/filepath"
+
 	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/image"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
 )
 
 const (
@@ -44,9 +48,21 @@ type Kustomization struct {
 	// file including generated configmaps and secrets.
 	NameSuffix string `json:"nameSuffix,omitempty" yaml:"nameSuffix,omitempty"`
 
+	// GenerateNameSuffix, if set, names the resources that
+	// have a metadata.generateName and no name, which the
+	// API server would name, by appending it, with any
+	// {hash} replaced by a hash of the resource, to their
+	// generateName, e.g. for apply tools that need names.
+	GenerateNameSuffix string `json:"generateNameSuffix,omitempty" yaml:"generateNameSuffix,omitempty"`
+
 	// Namespace to add to all objects.
 	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 
+	// NamespaceFrom, if set, replaces Namespace with one
+	// derived from a value or the git branch, e.g. for
+	// preview environments.
+	NamespaceFrom *NamespaceFrom `json:"namespaceFrom,omitempty" yaml:"namespaceFrom,omitempty"`
+
 	// CommonLabels to add to all objects and selectors.
 	CommonLabels map[string]string `json:"commonLabels,omitempty" yaml:"commonLabels,omitempty"`
 
@@ -64,11 +80,21 @@ type Kustomization struct {
 	// and http://jsonpatch.com
 	PatchesJson6902 []PatchJson6902 `json:"patchesJson6902,omitempty" yaml:"patchesJson6902,omitempty"`
 
+	// PatchesJsonPath is a list of values to set at the
+	// fields a JSONPath finds, e.g. selecting list elements
+	// by name rather than by index as JSON patches must.
+	PatchesJsonPath []PatchJsonPath `json:"patchesJsonPath,omitempty" yaml:"patchesJsonPath,omitempty"`
+
 	// Patches is a list of patches, where each one can be either a
 	// Strategic Merge Patch or a JSON patch.
 	// Each patch can be applied to multiple target objects.
 	Patches []Patch `json:"patches,omitempty" yaml:"patches,omitempty"`
 
+	// IgnoreFields lists fields removed from the output after
+	// all patches and transformers, e.g. fields set by the
+	// cluster that the output shouldn't fight over.
+	IgnoreFields []IgnoreField `json:"ignoreFields,omitempty" yaml:"ignoreFields,omitempty"`
+
 	// Images is a list of (image name, new name, new tag or digest)
 	// for changing image names, tags or digests. This can also be achieved with a
 	// patch, but this operator is simpler to specify.
@@ -87,6 +113,13 @@ type Kustomization struct {
 	// value of the specified field has been determined.
 	Vars []Var `json:"vars,omitempty" yaml:"vars,omitempty"`
 
+	// ValuesFrom lists files of shared values, local or
+	// remote, loaded in order, later values replacing
+	// earlier ones.  A value is a param of the templates
+	// of this kustomization, and, like a var, replaces
+	// $(name) in the fields vars do.
+	ValuesFrom []string `json:"valuesFrom,omitempty" yaml:"valuesFrom,omitempty"`
+
 	//
 	// Operands - what kustomize operates on.
 	//
@@ -125,10 +158,16 @@ type Kustomization struct {
 	// the map will have a suffix hash generated from its contents.
 	SecretGenerator []SecretArgs `json:"secretGenerator,omitempty" yaml:"secretGenerator,omitempty"`
 
+	// Templates is a list of template files to generate
+	// resources from, with values for their ${param}
+	// placeholders.
+	Templates []TemplateArgs `json:"templates,omitempty" yaml:"templates,omitempty"`
+
 	// GeneratorOptions modify behavior of all ConfigMap and Secret generators.
 	GeneratorOptions *GeneratorOptions `json:"generatorOptions,omitempty" yaml:"generatorOptions,omitempty"`
 
-	// Configurations is a list of transformer configuration files
+	// Configurations is a list of transformer configuration files,
+	// or digest-pinned oci:// references to artifacts of them.
 	Configurations []string `json:"configurations,omitempty" yaml:"configurations,omitempty"`
 
 	// Generators is a list of files containing custom generators
@@ -137,9 +176,51 @@ type Kustomization struct {
 	// Transformers is a list of files containing transformers
 	Transformers []string `json:"transformers,omitempty" yaml:"transformers,omitempty"`
 
+	// Exporters is a list of files containing exporters,
+	// whose output replaces the YAML form of the output
+	// of the build.  The exporters of bases are ignored.
+	Exporters []string `json:"exporters,omitempty" yaml:"exporters,omitempty"`
+
+	// Expectations is a list of files containing
+	// expectations, which check the output of the build,
+	// failing it if they don't hold.  The expectations
+	// of bases are ignored.
+	Expectations []string `json:"expectations,omitempty" yaml:"expectations,omitempty"`
+
+	// PostRenderers are programs the output of the build
+	// is piped through, in turn, after the exporters, e.g.
+	// to edit it with yq.  Builds only run them if allowed
+	// to.  The postRenderers of bases are ignored.
+	PostRenderers []PostRenderer `json:"postRenderers,omitempty" yaml:"postRenderers,omitempty"`
+
+	// Clusters lists the clusters to build for, each
+	// with its own overrides.  The build then has one
+	// output per cluster.
+	Clusters []Cluster `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+
 	// Inventory appends an object that contains the record
 	// of all other objects, which can be used in apply, prune and delete
 	Inventory *Inventory `json:"inventory,omitempty" yaml:"inventory:omitempty"`
+
+	// OpenAPI selects the Kubernetes API schema used by
+	// strategic merge patches.  Only the value in the
+	// kustomization being built is used; bases inherit it.
+	OpenAPI *OpenAPIConfig `json:"openapi,omitempty" yaml:"openapi,omitempty"`
+
+	// BuildMetadata lists kinds of metadata to add to the
+	// output, e.g. originAnnotations.  Options set here
+	// also apply to the kustomizations this one includes.
+	BuildMetadata []string `json:"buildMetadata,omitempty" yaml:"buildMetadata,omitempty"`
+
+	// Tenancy restricts the namespaces of the resources,
+	// and the kinds of cluster scoped ones, of the
+	// output of this kustomization.
+	Tenancy *Tenancy `json:"tenancy,omitempty" yaml:"tenancy,omitempty"`
+
+	// DependsOn lists resources that depend on others,
+	// written to the output as annotations telling apply
+	// tools which to apply, and wait for, first.
+	DependsOn []DependsOn `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
 }
 
 //go:generate stringer -type=GarbagePolicy
@@ -190,6 +271,32 @@ func (k *Kustomization) EnforceFields() []string {
 	if k.Kind != "" && k.Kind != KustomizationKind {
 		errs = append(errs, "kind should be "+KustomizationKind)
 	}
+	if k.OpenAPI != nil && k.OpenAPI.Version != "" && k.OpenAPI.Path != "" {
+		errs = append(errs, "openapi should have a version or a path, not both")
+	}
+	errs = append(errs, checkClusters(k.Clusters)...)
+	errs = append(errs, checkNamespaceFrom(k.NamespaceFrom)...)
+	errs = append(errs, checkGenerateNameSuffix(k.GenerateNameSuffix)...)
+	if k.GeneratorOptions != nil {
+		errs = append(errs, checkHashIncludes(k.GeneratorOptions.HashIncludes)...)
+	}
+	for _, g := range k.ConfigMapGenerator {
+		if g.Options != nil {
+			errs = append(errs, checkHashIncludes(g.Options.HashIncludes)...)
+		}
+	}
+	for _, g := range k.SecretGenerator {
+		if g.Options != nil {
+			errs = append(errs, checkHashIncludes(g.Options.HashIncludes)...)
+		}
+	}
+	for _, m := range k.BuildMetadata {
+		if !isBuildMetadataOption(m) {
+			errs = append(errs, fmt.Sprintf(
+				"buildMetadata value %s should be one of %v",
+				m, BuildMetadataOptions))
+		}
+	}
 	return errs
 }
 
@@ -211,6 +318,10 @@ type GeneratorArgs struct {
 
 	// DataSources for the generator.
 	DataSources `json:",inline,omitempty" yaml:",inline,omitempty"`
+
+	// Options of this generator alone, merged into
+	// the kustomization's GeneratorOptions.
+	Options *GeneratorOptions `json:"options,omitempty" yaml:"options,omitempty"`
 }
 
 // PluginConfig holds plugin configuration.
@@ -225,6 +336,17 @@ type PluginConfig struct {
 	Enabled bool
 }
 
+// DisabledPluginConfig returns a PluginConfig with plugins
+// disabled, looked for, if enabled, in the directory
+// under the config root.
+func DisabledPluginConfig() *PluginConfig {
+	return &PluginConfig{
+		Enabled: false,
+		DirectoryPath: filepath.Join(
+			pgmconfig.ConfigRoot(), pgmconfig.PluginRoot),
+	}
+}
+
 // ConfigMapArgs contains the metadata of how to generate a configmap.
 type ConfigMapArgs struct {
 	// GeneratorArgs for the configmap.
@@ -294,6 +416,18 @@ type GeneratorOptions struct {
 	// suffix to the names of generated resources that is a hash of the
 	// resource contents.
 	DisableNameSuffixHash bool `json:"disableNameSuffixHash,omitempty" yaml:"disableNameSuffixHash,omitempty"`
+
+	// HashAnnotation if true keeps the names of generated resources,
+	// as DisableNameSuffixHash does, but writes a hash of their
+	// contents to the GeneratedHashAnnotation of the pod templates
+	// referring to them, so changes still roll out, without leaving
+	// old generated resources behind to be pruned.
+	HashAnnotation bool `json:"hashAnnotation,omitempty" yaml:"hashAnnotation,omitempty"`
+
+	// HashIncludes lists the metadata, of HashIncludesOptions,
+	// the hash of generated resources covers besides their
+	// data, kind and name, and, of Secrets, type.
+	HashIncludes []string `json:"hashIncludes,omitempty" yaml:"hashIncludes,omitempty"`
 }
 
 type PluginType string
@@ -314,6 +448,27 @@ type Inventory struct {
 	ConfigMap NameArgs `json:"configMap,omitempty" yaml:"configMap,omitempty"`
 }
 
+// TemplateArgs names template files and the
+// values of their ${param} placeholders.
+type TemplateArgs struct {
+	// Files are relative paths to templates, each
+	// holding one or more resources.
+	Files []string `json:"files,omitempty" yaml:"files,omitempty"`
+
+	// Params maps placeholder names to values.
+	Params map[string]string `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// OpenAPIConfig names an OpenAPI schema.
+type OpenAPIConfig struct {
+	// Version is a Kubernetes version, e.g. "1.16".
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// Path is the relative path to an OpenAPI v2 document,
+	// e.g. one fetched from a cluster with custom resources.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
 type NameArgs struct {
 	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
 	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
@@ -360,6 +515,20 @@ type Replica struct {
 	Count int64 `json:"count,omitempty" yaml:"count,omitempty"`
 }
 
+// PatchJsonPath sets a value at the fields a JSONPath
+// finds in the target resources, e.g.
+// spec.template.spec.containers[?(@.name=='app')].image
+type PatchJsonPath struct {
+	// Target selects the resources; if nil, all of them.
+	Target *Selector `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Path is the JSONPath.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Value is set at each field the path finds.
+	Value interface{} `json:"value" yaml:"value"`
+}
+
 // Patch represent either a Strategic Merge Patch or a JSON patch
 // and its targets.
 // The content of the patch can either be from a file
@@ -375,11 +544,26 @@ type Patch struct {
 	Target *Selector `json:"target,omitempty" yaml:"target,omitempty"`
 }
 
+// IgnoreField lists fields to remove from resources.
+type IgnoreField struct {
+	// Paths to the fields, like fieldSpec paths, e.g.
+	// spec/clusterIP, or with an escaped slash in a key,
+	// metadata/annotations/deployment.kubernetes.io\/revision
+	Paths []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+
+	// Target selects the resources; if nil, all of them.
+	Target *Selector `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
 // Selector specifies a set of resources.
 // Any resource that matches intersection of all conditions
 // is included in this set.
 type Selector struct {
-	gvk.Gvk   `json:",inline,omitempty" yaml:",inline,omitempty"`
+	gvk.Gvk `json:",inline,omitempty" yaml:",inline,omitempty"`
+
+	// Namespace and Name are anchored regular expressions,
+	// or, with a * and no other regular expression syntax,
+	// wildcard patterns, e.g. app-* matching app-web.
 	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
 
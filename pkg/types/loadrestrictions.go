@@ -0,0 +1,31 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// LoadRestrictions are the restrictions on what files
+// a kustomization may refer to, as named by the
+// sigs.k8s.io/kustomize/api types; the CLI's
+// --load_restrictor flag has the same values.
+//
+//go:generate stringer -type=LoadRestrictions
+type LoadRestrictions int
+
+const (
+	LoadRestrictionsUnknown LoadRestrictions = iota
+
+	// Files referenced by a kustomization file must be in
+	// or under the directory holding the kustomization
+	// file itself.
+	LoadRestrictionsRootOnly
+
+	// The kustomization file may specify absolute or
+	// relative paths to patch or resources files outside
+	// its own tree.
+	LoadRestrictionsNone
+
+	// Files referenced by a kustomization file must be in
+	// the git working tree holding it, or, outside of
+	// one, under its own directory.
+	LoadRestrictionsRepoRootOnly
+)
@@ -0,0 +1,31 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+const (
+	// LocalConfigAnnotation, set to "true", marks a resource
+	// used only during the build, e.g. as the source of a
+	// var, and so left out of the output.
+	LocalConfigAnnotation = "config.kubernetes.io/local-config"
+
+	// SkipTransformersAnnotation holds a comma separated list
+	// of transformers that must not change a resource, named
+	// as in a TransformerAnnotation, e.g.
+	// "PrefixSuffixTransformer,LabelTransformer".
+	SkipTransformersAnnotation = "config.kubernetes.io/skip-transformers"
+
+	// EncryptedFieldsAnnotation holds a comma separated list
+	// of paths, like those of transformer configurations, of
+	// fields with encrypted values that no transformer may
+	// change, e.g. "data/password,data/token".
+	EncryptedFieldsAnnotation = "kustomize.config.k8s.io/encrypted-fields"
+)
+
+// LocalConfigAnnotations lists the annotations
+// stripped from the output of a build.
+var LocalConfigAnnotations = []string{
+	LocalConfigAnnotation,
+	SkipTransformersAnnotation,
+	EncryptedFieldsAnnotation,
+}
@@ -0,0 +1,34 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// NamespaceFrom derives the namespace, e.g. of a preview
+// environment, from a value, e.g. one given by
+// `kustomize build --set`, or the git branch.
+type NamespaceFrom struct {
+	// Value names the value holding the environment,
+	// e.g. env for `--set env=feature-xyz`.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+
+	// GitBranch, if true, takes the environment from the
+	// branch checked out in the kustomization's directory
+	// when there's no value.
+	GitBranch bool `json:"gitBranch,omitempty" yaml:"gitBranch,omitempty"`
+
+	// Prefix goes before the environment, e.g. myapp-
+	// makes the namespace of feature-xyz myapp-feature-xyz.
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+
+	// NameSuffix, if true, also suffixes the names of the
+	// resources with a dash and the environment, after
+	// any nameSuffix.
+	NameSuffix bool `json:"nameSuffix,omitempty" yaml:"nameSuffix,omitempty"`
+}
+
+func checkNamespaceFrom(n *NamespaceFrom) []string {
+	if n == nil || n.Value != "" || n.GitBranch {
+		return nil
+	}
+	return []string{"namespaceFrom should have a value or gitBranch"}
+}
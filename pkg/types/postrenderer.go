@@ -0,0 +1,16 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// PostRenderer is a program the output of a build
+// is piped through, e.g. yq, its output replacing it.
+type PostRenderer struct {
+	// Command is the program, a path relative to the
+	// kustomization, or, if it has no slash, found in
+	// the $PATH.
+	Command string `json:"command" yaml:"command"`
+
+	// Args are the arguments of the program.
+	Args []string `json:"args,omitempty" yaml:"args,omitempty"`
+}
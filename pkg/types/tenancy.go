@@ -0,0 +1,18 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// Tenancy restricts where the resources of a
+// kustomization, including those of its bases,
+// may go, e.g. for a team sharing a cluster.
+type Tenancy struct {
+	// Namespaces are those the namespaced resources
+	// must be in.  If empty, any namespace will do.
+	// A resource without a namespace is in "default".
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+
+	// ClusterScopedKinds are the kinds of the cluster
+	// scoped resources allowed, e.g. Namespace.
+	ClusterScopedKinds []string `json:"clusterScopedKinds,omitempty" yaml:"clusterScopedKinds,omitempty"`
+}
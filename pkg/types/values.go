@@ -0,0 +1,36 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// ValuesCatalog is a file of values, e.g. domain names
+// and registry hosts, that kustomizations share by
+// listing it in valuesFrom.
+type ValuesCatalog struct {
+	// Values maps names to strings, numbers or booleans.
+	Values map[string]interface{} `json:"values,omitempty" yaml:"values,omitempty"`
+
+	// Schema maps names to what their values must be,
+	// in this catalog and those loaded after it.
+	Schema map[string]ValueSchema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// ValueSchema constrains a value.
+type ValueSchema struct {
+	// Type is string, integer, number or boolean;
+	// if empty, any of them.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Pattern is a regular expression matching the
+	// value, as in JSON Schema, unanchored.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+	// Enum lists the values allowed, if not empty.
+	Enum []string `json:"enum,omitempty" yaml:"enum,omitempty"`
+
+	// Required is true if the value must be given.
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+
+	// Description says what the value is for.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
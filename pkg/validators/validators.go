@@ -8,6 +8,8 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 )
 
 // FakeValidator can be used in tests.
@@ -74,6 +76,15 @@ func (v *FakeValidator) ValidateNamespace(s string) []string {
 	return []string{"doesn't match"}
 }
 
+// ValidateName validates name by regexp
+func (v *FakeValidator) ValidateName(_ gvk.Gvk, s string) []string {
+	pattern := regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+	if pattern.MatchString(s) {
+		return nil
+	}
+	return []string{"doesn't match"}
+}
+
 // Validator replaces apimachinery validation in tests.
 // Can be set to fail or succeed to test error handling.
 // Can confirm if run or not run by surrounding code.
@@ -0,0 +1,77 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package version holds build information about the
+// kustomize binary, so that wrapping tools can assert
+// compatibility programmatically.
+package version
+
+import (
+	"runtime"
+
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// These are set at link time via -ldflags, see releasing/goreleaser.yaml.
+var (
+	kustomizeVersion = "unknown"
+	gitCommit        = "$Format:%H$" // sha1 from git, output of $(git rev-parse HEAD)
+
+	buildDate = "1970-01-01T00:00:00Z" // build date in ISO8601 format, output of $(date -u +'%Y-%m-%dT%H:%M:%SZ')
+)
+
+// PluginABIVersion identifies the contract between
+// kustomize and its Go plugins (the exported symbol
+// name and the Configurable/Generator/Transformer
+// method sets).  It changes whenever a plugin built
+// against an older kustomize would no longer load.
+const PluginABIVersion = "v1"
+
+// Info describes a kustomize binary.
+type Info struct {
+	// KustomizeVersion is a kustomize binary version.
+	KustomizeVersion string `json:"kustomizeVersion" yaml:"kustomizeVersion"`
+	// GitCommit is a git commit
+	GitCommit string `json:"gitCommit" yaml:"gitCommit"`
+	// BuildDate is a build date of the binary.
+	BuildDate string `json:"buildDate" yaml:"buildDate"`
+	// GoOs holds OS name.
+	GoOs string `json:"goOs" yaml:"goOs"`
+	// GoArch holds architecture name.
+	GoArch string `json:"goArch" yaml:"goArch"`
+	// PluginABIVersion is the plugin contract version.
+	PluginABIVersion string `json:"pluginAbiVersion" yaml:"pluginAbiVersion"`
+	// KustomizationAPIVersions lists the kustomization
+	// file apiVersions this binary accepts.
+	KustomizationAPIVersions []string `json:"kustomizationApiVersions" yaml:"kustomizationApiVersions"`
+}
+
+// Get returns information about the running binary.
+func Get() Info {
+	return Info{
+		KustomizeVersion:         kustomizeVersion,
+		GitCommit:                gitCommit,
+		BuildDate:                buildDate,
+		GoOs:                     runtime.GOOS,
+		GoArch:                   runtime.GOARCH,
+		PluginABIVersion:         PluginABIVersion,
+		KustomizationAPIVersions: SupportedKustomizationAPIVersions(),
+	}
+}
+
+// SupportedKustomizationAPIVersions returns the
+// kustomization apiVersions understood by this binary.
+func SupportedKustomizationAPIVersions() []string {
+	return []string{types.KustomizationVersion}
+}
+
+// Supports returns true if this binary accepts
+// kustomization files with the given apiVersion.
+func (i Info) Supports(apiVersion string) bool {
+	for _, v := range i.KustomizationAPIVersions {
+		if v == apiVersion {
+			return true
+		}
+	}
+	return false
+}
@@ -13,6 +13,7 @@ import (
 type AnnotationsTransformerPlugin struct {
 	Annotations map[string]string  `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 	FieldSpecs  []config.FieldSpec `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+	procs       int
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -24,6 +25,7 @@ func (p *AnnotationsTransformerPlugin) Config(
 	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
 	p.Annotations = nil
 	p.FieldSpecs = nil
+	p.procs = rf.MaxProcs()
 	return yaml.Unmarshal(c, p)
 }
 
@@ -31,6 +33,7 @@ func (p *AnnotationsTransformerPlugin) Transform(m resmap.ResMap) error {
 	t, err := transformers.NewMapTransformer(
 		p.FieldSpecs,
 		p.Annotations,
+		p.procs,
 	)
 	if err != nil {
 		return err
@@ -6,10 +6,13 @@ import (
 
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
 )
 
 type HashTransformerPlugin struct {
 	hasher ifc.KunstructuredHasher
+	procs  int
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -20,12 +23,13 @@ func NewHashTransformerPlugin() *HashTransformerPlugin {
 func (p *HashTransformerPlugin) Config(
 	ldr ifc.Loader, rf *resmap.Factory, config []byte) (err error) {
 	p.hasher = rf.RF().Hasher()
+	p.procs = rf.MaxProcs()
 	return nil
 }
 
 // Transform appends hash to generated resources.
 func (p *HashTransformerPlugin) Transform(m resmap.ResMap) error {
-	for _, res := range m.Resources() {
+	return transformers.ForEachResource(m, p.procs, func(_ int, res *resource.Resource) error {
 		if res.NeedHashSuffix() {
 			h, err := p.hasher.Hash(res)
 			if err != nil {
@@ -33,6 +37,6 @@ func (p *HashTransformerPlugin) Transform(m resmap.ResMap) error {
 			}
 			res.SetName(fmt.Sprintf("%s-%s", res.GetName(), h))
 		}
-	}
-	return nil
+		return nil
+	})
 }
@@ -9,8 +9,10 @@ import (
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/image"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 	"sigs.k8s.io/yaml"
 )
 
@@ -46,7 +48,37 @@ func (p *ImageTagTransformerPlugin) Transform(m resmap.ResMap) error {
 			}
 		}
 		// Kept for backward compatibility
-		if err := p.findAndReplaceImage(r.Map()); err != nil && r.OrgId().Kind != `CustomResourceDefinition` {
+		if err := p.updateImages(r); err != nil && r.OrgId().Kind != `CustomResourceDefinition` {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateImages replaces the matching images of the
+// containers of a workload or Pod, or, for other
+// kinds, of anything in the object that looks like
+// a list of containers.
+func (p *ImageTagTransformerPlugin) updateImages(r *resource.Resource) error {
+	if !r.HasPodSpec() {
+		return p.findAndReplaceImage(r.Map())
+	}
+	containers, err := r.Containers()
+	if _, ok := err.(types.NoFieldError); ok {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		if !isImageMatched(c.Image, p.ImageTag.Name) {
+			continue
+		}
+		newImage, err := p.mutateImage(c.Image)
+		if err != nil {
+			return err
+		}
+		if err := r.SetImage(c.Name, newImage.(string)); err != nil {
 			return err
 		}
 	}
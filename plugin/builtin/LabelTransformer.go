@@ -13,6 +13,7 @@ import (
 type LabelTransformerPlugin struct {
 	Labels     map[string]string  `json:"labels,omitempty" yaml:"labels,omitempty"`
 	FieldSpecs []config.FieldSpec `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+	procs      int
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -24,6 +25,7 @@ func (p *LabelTransformerPlugin) Config(
 	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
 	p.Labels = nil
 	p.FieldSpecs = nil
+	p.procs = rf.MaxProcs()
 	return yaml.Unmarshal(c, p)
 }
 
@@ -31,6 +33,7 @@ func (p *LabelTransformerPlugin) Transform(m resmap.ResMap) error {
 	t, err := transformers.NewMapTransformer(
 		p.FieldSpecs,
 		p.Labels,
+		p.procs,
 	)
 	if err != nil {
 		return err
@@ -14,6 +14,8 @@ import (
 // dependencies (like Namespace, StorageClass, etc.)
 // first, and resources with a high number of dependencies
 // (like ValidatingWebhookConfiguration) last.
+// Resources with the apply-order annotation are moved
+// before or after the others, per its weight.
 type LegacyOrderTransformerPlugin struct{}
 
 //noinspection GoUnusedGlobalVariable
@@ -37,6 +39,16 @@ func (p *LegacyOrderTransformerPlugin) Transform(m resmap.ResMap) (err error) {
 			return errors.Wrap(err, "expected match for sorting")
 		}
 	}
+	weights := make(map[*resource.Resource]int)
+	for _, r := range resources {
+		weights[r], err = r.ApplyOrder()
+		if err != nil {
+			return err
+		}
+	}
+	sort.SliceStable(resources, func(i, j int) bool {
+		return weights[resources[i]] < weights[resources[j]]
+	})
 	m.Clear()
 	for _, r := range resources {
 		m.Append(r)
@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/patch"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
@@ -47,21 +48,16 @@ func (p *PatchJson6902TransformerPlugin) Config(
 		if err != nil {
 			return err
 		}
+		err = rf.RF().CheckDuplicateKeys(p.Path, rawOp)
+		if err != nil {
+			return err
+		}
 		p.JsonOp = string(rawOp)
 		if p.JsonOp == "" {
 			return fmt.Errorf("patch file '%s' empty seems to be empty", p.Path)
 		}
 	}
-	if p.JsonOp[0] != '[' {
-		// if it doesn't seem to be JSON, imagine
-		// it is YAML, and convert to JSON.
-		op, err := yaml.YAMLToJSON([]byte(p.JsonOp))
-		if err != nil {
-			return err
-		}
-		p.JsonOp = string(op)
-	}
-	p.decodedPatch, err = jsonpatch.DecodePatch([]byte(p.JsonOp))
+	p.decodedPatch, err = patch.DecodeJson6902([]byte(p.JsonOp))
 	if err != nil {
 		return errors.Wrapf(err, "decoding %s", p.JsonOp)
 	}
@@ -82,18 +78,24 @@ func (p *PatchJson6902TransformerPlugin) Transform(m resmap.ResMap) error {
 		p.Target.Name,
 		p.Target.Namespace,
 	)
-	obj, err := m.GetById(id)
-	if err != nil {
-		return err
-	}
-	rawObj, err := obj.MarshalJSON()
+	objs, err := resmap.GetPatchTargets(m, id)
 	if err != nil {
 		return err
 	}
-	modifiedObj, err := p.decodedPatch.Apply(rawObj)
-	if err != nil {
-		return errors.Wrapf(
-			err, "failed to apply json patch '%s'", p.JsonOp)
+	for _, obj := range objs {
+		rawObj, err := obj.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		modifiedObj, err := patch.ApplyJson6902(p.decodedPatch, rawObj)
+		if err != nil {
+			return errors.Wrapf(
+				err, "failed to apply json patch '%s'", p.JsonOp)
+		}
+		err = obj.UnmarshalJSON(modifiedObj)
+		if err != nil {
+			return err
+		}
 	}
-	return obj.UnmarshalJSON(modifiedObj)
+	return nil
 }
@@ -4,6 +4,7 @@ package builtin
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
@@ -78,6 +79,16 @@ func (p *PrefixSuffixTransformerPlugin) Transform(m resmap.ResMap) error {
 				// empty
 				r.AddNamePrefix(p.Prefix)
 				r.AddNameSuffix(p.Suffix)
+				if r.GetName() == "" && r.GetGenerateName() != "" {
+					err := transformers.MutateField(
+						r.Map(),
+						[]string{"metadata", "generateName"},
+						false,
+						p.addPrefixSuffixToGenerateName)
+					if err != nil {
+						return err
+					}
+				}
 			}
 
 			// the addPrefixSuffix method will not
@@ -118,3 +129,19 @@ func (p *PrefixSuffixTransformerPlugin) addPrefixSuffix(
 	}
 	return fmt.Sprintf("%s%s%s", p.Prefix, s, p.Suffix), nil
 }
+
+// addPrefixSuffixToGenerateName puts the suffix before
+// the dash a generateName usually ends in, as the API
+// server appends random characters to it.
+func (p *PrefixSuffixTransformerPlugin) addPrefixSuffixToGenerateName(
+	in interface{}) (interface{}, error) {
+	s, ok := in.(string)
+	if !ok {
+		return nil, fmt.Errorf("%#v is expected to be %T", in, s)
+	}
+	if strings.HasSuffix(s, "-") {
+		return fmt.Sprintf(
+			"%s%s%s-", p.Prefix, strings.TrimSuffix(s, "-"), p.Suffix), nil
+	}
+	return fmt.Sprintf("%s%s%s", p.Prefix, s, p.Suffix), nil
+}
@@ -16,6 +16,7 @@ import (
 type plugin struct {
 	Annotations map[string]string  `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 	FieldSpecs  []config.FieldSpec `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+	procs       int
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -25,6 +26,7 @@ func (p *plugin) Config(
 	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
 	p.Annotations = nil
 	p.FieldSpecs = nil
+	p.procs = rf.MaxProcs()
 	return yaml.Unmarshal(c, p)
 }
 
@@ -32,6 +34,7 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 	t, err := transformers.NewMapTransformer(
 		p.FieldSpecs,
 		p.Annotations,
+		p.procs,
 	)
 	if err != nil {
 		return err
@@ -0,0 +1,68 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate go run sigs.k8s.io/kustomize/v3/cmd/pluginator
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Remove fields, e.g. those managed by the cluster,
+// from the selected resources.
+type plugin struct {
+	Paths  []string        `json:"paths,omitempty" yaml:"paths,omitempty"`
+	Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
+	p.Paths = nil
+	p.Target = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if len(p.Paths) == 0 {
+		return fmt.Errorf("must specify paths in\n%s", string(c))
+	}
+	for _, path := range p.Paths {
+		if path == "" {
+			return fmt.Errorf("empty path in\n%s", string(c))
+		}
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		p.removeFields(r)
+	}
+	return nil
+}
+
+func (p *plugin) removeFields(r *resource.Resource) {
+	for _, path := range p.Paths {
+		transformers.RemoveField(
+			r.Map(), config.FieldSpec{Path: path}.PathSlice())
+	}
+}
@@ -117,3 +117,69 @@ metadata:
   name: pomegranate
 `)
 }
+
+func TestLegacyOrderTransformerApplyOrder(t *testing.T) {
+	tc := plugins_test.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "LegacyOrderTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: LegacyOrderTransformer
+metadata:
+  name: notImportantHere
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: papaya
+---
+apiVersion: v1
+kind: Deployment
+metadata:
+  name: app
+---
+apiVersion: v1
+kind: StatefulSet
+metadata:
+  name: db
+  annotations:
+    kustomize.config.k8s.io/apply-order: "-1"
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: apple
+  annotations:
+    kustomize.config.k8s.io/apply-order: "1"
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+kind: StatefulSet
+metadata:
+  annotations:
+    kustomize.config.k8s.io/apply-order: "-1"
+  name: db
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: papaya
+---
+apiVersion: v1
+kind: Deployment
+metadata:
+  name: app
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  annotations:
+    kustomize.config.k8s.io/apply-order: "1"
+  name: apple
+`)
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate go run sigs.k8s.io/kustomize/v3/cmd/pluginator
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/jsonpath"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Set a value at the fields a JSONPath finds
+// in the selected resources.
+type plugin struct {
+	path   jsonpath.Path
+	Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+	Path   string          `json:"path,omitempty" yaml:"path,omitempty"`
+	Value  interface{}     `json:"value" yaml:"value"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
+	p.Target = nil
+	p.Path = ""
+	p.Value = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	p.path, err = jsonpath.Parse(p.Path)
+	return err
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	count := 0
+	for _, r := range resources {
+		count += p.path.Set(r.Map(), p.Value)
+	}
+	if count == 0 {
+		return fmt.Errorf(
+			"jsonpath '%s' found no field in %d resources",
+			p.Path, len(resources))
+	}
+	return nil
+}
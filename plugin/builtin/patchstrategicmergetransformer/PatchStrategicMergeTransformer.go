@@ -70,18 +70,12 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 		return err
 	}
 	for _, patch := range patches.Resources() {
-		target, err := m.GetById(patch.OrgId())
+		targets, err := resmap.GetPatchTargets(m, patch.OrgId())
 		if err != nil {
 			return err
 		}
-		err = target.Patch(patch.Kunstructured)
-		if err != nil {
-			return err
-		}
-		// remove the resource from resmap
-		// when the patch is to $patch: delete that target
-		if len(target.Map()) == 0 {
-			err = m.Remove(target.CurId())
+		for _, target := range targets {
+			err = p.patch(m, target, patch)
 			if err != nil {
 				return err
 			}
@@ -89,3 +83,23 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 	}
 	return nil
 }
+
+func (p *plugin) patch(
+	m resmap.ResMap, target, patch *resource.Resource) error {
+	if !patch.GetGvk().Equals(target.GetGvk()) {
+		// A patch without a version may target any; it
+		// must be merged as, and not change, the target's.
+		patch = patch.DeepCopy()
+		patch.SetGvk(target.GetGvk())
+	}
+	err := target.Patch(patch.Kunstructured)
+	if err != nil {
+		return err
+	}
+	// remove the resource from resmap
+	// when the patch is to $patch: delete that target
+	if len(target.Map()) == 0 {
+		return m.Remove(target.CurId())
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/evanphx/json-patch"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/patch"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
@@ -52,6 +53,10 @@ func (p *plugin) Config(
 		if err != nil {
 			return
 		}
+		err = rf.RF().CheckDuplicateKeys(p.Path, in)
+		if err != nil {
+			return
+		}
 	}
 	if p.Patch != "" {
 		in = []byte(p.Patch)
@@ -105,7 +110,7 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 			if err != nil {
 				return err
 			}
-			modifiedObj, err := p.decodedPatch.Apply(rawObj)
+			modifiedObj, err := patch.ApplyJson6902(p.decodedPatch, rawObj)
 			if err != nil {
 				return errors.Wrapf(
 					err, "failed to apply json patch '%s'", p.Patch)
@@ -133,17 +138,8 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 // a bytes input
 func jsonPatchFromBytes(
 	in []byte) (jsonpatch.Patch, error) {
-	ops := string(in)
-	if ops == "" {
+	if len(in) == 0 {
 		return nil, fmt.Errorf("empty json patch operations")
 	}
-
-	if ops[0] != '[' {
-		jsonOps, err := yaml.YAMLToJSON(in)
-		if err != nil {
-			return nil, err
-		}
-		ops = string(jsonOps)
-	}
-	return jsonpatch.DecodePatch([]byte(ops))
+	return patch.DecodeJson6902(in)
 }
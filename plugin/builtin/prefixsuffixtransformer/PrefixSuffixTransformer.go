@@ -7,6 +7,7 @@ package main
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
@@ -79,6 +80,16 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 				// empty
 				r.AddNamePrefix(p.Prefix)
 				r.AddNameSuffix(p.Suffix)
+				if r.GetName() == "" && r.GetGenerateName() != "" {
+					err := transformers.MutateField(
+						r.Map(),
+						[]string{"metadata", "generateName"},
+						false,
+						p.addPrefixSuffixToGenerateName)
+					if err != nil {
+						return err
+					}
+				}
 			}
 
 			// the addPrefixSuffix method will not
@@ -119,3 +130,19 @@ func (p *plugin) addPrefixSuffix(
 	}
 	return fmt.Sprintf("%s%s%s", p.Prefix, s, p.Suffix), nil
 }
+
+// addPrefixSuffixToGenerateName puts the suffix before
+// the dash a generateName usually ends in, as the API
+// server appends random characters to it.
+func (p *plugin) addPrefixSuffixToGenerateName(
+	in interface{}) (interface{}, error) {
+	s, ok := in.(string)
+	if !ok {
+		return nil, fmt.Errorf("%#v is expected to be %T", in, s)
+	}
+	if strings.HasSuffix(s, "-") {
+		return fmt.Sprintf(
+			"%s%s%s-", p.Prefix, strings.TrimSuffix(s, "-"), p.Suffix), nil
+	}
+	return fmt.Sprintf("%s%s%s", p.Prefix, s, p.Suffix), nil
+}
@@ -10,6 +10,7 @@ import (
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
@@ -44,10 +45,7 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 
 		for _, res := range append(matchOriginal, matchCurrent...) {
 			found = true
-			err := transformers.MutateField(
-				res.Map(), replicaSpec.PathSlice(),
-				replicaSpec.CreateIfNotPresent, p.addReplicas)
-			if err != nil {
+			if err := p.setReplicas(res, replicaSpec); err != nil {
 				return err
 			}
 		}
@@ -73,6 +71,21 @@ func (p *plugin) createMatcher(i int) resmap.IdMatcher {
 	}
 }
 
+// setReplicas sets the replicas of a resource at
+// the field the spec gives.
+func (p *plugin) setReplicas(
+	res *resource.Resource, fs config.FieldSpec) error {
+	if fs.Path != "spec/replicas" {
+		return transformers.MutateField(
+			res.Map(), fs.PathSlice(), fs.CreateIfNotPresent, p.addReplicas)
+	}
+	_, err := res.GetReplicas()
+	if _, ok := err.(types.NoFieldError); ok && !fs.CreateIfNotPresent {
+		return nil
+	}
+	return res.SetReplicas(p.Replica.Count)
+}
+
 func (p *plugin) addReplicas(in interface{}) (interface{}, error) {
 	switch m := in.(type) {
 	case int64:
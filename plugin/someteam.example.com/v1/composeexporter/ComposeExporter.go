@@ -0,0 +1,158 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// An exporter example.  Writes the containers of
+// Deployments, StatefulSets, DaemonSets and Pods as
+// the services of a docker-compose file.  Other
+// resources, e.g. Services, are ignored.
+type plugin struct {
+	types.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+	// ComposeVersion is the version of the compose
+	// file format, by default "3".
+	ComposeVersion string `json:"composeVersion,omitempty" yaml:"composeVersion,omitempty"`
+}
+
+//nolint: golint
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+type container struct {
+	Name    string   `json:"name"`
+	Image   string   `json:"image"`
+	Command []string `json:"command"`
+	Args    []string `json:"args"`
+	Env     []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"env"`
+	Ports []struct {
+		ContainerPort int `json:"containerPort"`
+		HostPort      int `json:"hostPort"`
+	} `json:"ports"`
+}
+
+type podSpec struct {
+	Containers []container `json:"containers"`
+}
+
+// workload holds the fields of the supported kinds
+// that map to compose services.
+type workload struct {
+	Spec struct {
+		podSpec
+		Replicas *int `json:"replicas"`
+		Template struct {
+			Spec podSpec `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+type service struct {
+	Image       string            `json:"image,omitempty"`
+	Entrypoint  []string          `json:"entrypoint,omitempty"`
+	Command     []string          `json:"command,omitempty"`
+	Environment map[string]string `json:"environment,omitempty"`
+	Expose      []string          `json:"expose,omitempty"`
+	Ports       []string          `json:"ports,omitempty"`
+	Deploy      *deploy           `json:"deploy,omitempty"`
+}
+
+type deploy struct {
+	Replicas int `json:"replicas"`
+}
+
+func (p *plugin) Config(
+	_ ifc.Loader, _ *resmap.Factory, config []byte) error {
+	p.ComposeVersion = ""
+	err := yaml.Unmarshal(config, p)
+	if p.ComposeVersion == "" {
+		p.ComposeVersion = "3"
+	}
+	return err
+}
+
+func (p *plugin) Export(m resmap.ResMap) ([]byte, error) {
+	services := make(map[string]service)
+	for _, r := range m.Resources() {
+		w, err := toWorkload(r)
+		if err != nil {
+			return nil, err
+		}
+		if w == nil {
+			continue
+		}
+		spec := w.Spec.Template.Spec
+		if r.GetKind() == "Pod" {
+			spec = w.Spec.podSpec
+		}
+		for _, c := range spec.Containers {
+			name := r.GetName()
+			if len(spec.Containers) > 1 {
+				name += "-" + c.Name
+			}
+			if _, ok := services[name]; ok {
+				return nil, fmt.Errorf("duplicate compose service %s", name)
+			}
+			s := service{
+				Image:      c.Image,
+				Entrypoint: c.Command,
+				Command:    c.Args,
+			}
+			for _, e := range c.Env {
+				if s.Environment == nil {
+					s.Environment = make(map[string]string)
+				}
+				s.Environment[e.Name] = e.Value
+			}
+			for _, port := range c.Ports {
+				cp := strconv.Itoa(port.ContainerPort)
+				if port.HostPort == 0 {
+					s.Expose = append(s.Expose, cp)
+				} else {
+					s.Ports = append(s.Ports,
+						strconv.Itoa(port.HostPort)+":"+cp)
+				}
+			}
+			if w.Spec.Replicas != nil {
+				s.Deploy = &deploy{Replicas: *w.Spec.Replicas}
+			}
+			services[name] = s
+		}
+	}
+	return yaml.Marshal(struct {
+		Version  string             `json:"version"`
+		Services map[string]service `json:"services"`
+	}{Version: p.ComposeVersion, Services: services})
+}
+
+// toWorkload returns nil for unsupported kinds.
+func toWorkload(r *resource.Resource) (*workload, error) {
+	switch r.GetKind() {
+	case "Deployment", "StatefulSet", "DaemonSet", "Pod":
+	default:
+		return nil, nil
+	}
+	b, err := json.Marshal(r.Map())
+	if err != nil {
+		return nil, err
+	}
+	var w workload
+	if err := json.Unmarshal(b, &w); err != nil {
+		return nil, fmt.Errorf("%s: %v", r.CurId(), err)
+	}
+	return &w, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	plugins_test "sigs.k8s.io/kustomize/v3/pkg/plugins/test"
+)
+
+func TestComposeExporterPlugin(t *testing.T) {
+	tc := plugins_test.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"someteam.example.com", "v1", "ComposeExporter")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	out := th.LoadAndRunExporter(`
+apiVersion: someteam.example.com/v1
+kind: ComposeExporter
+metadata:
+  name: notImportantHere
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.17
+        args:
+        - --verbose
+        env:
+        - name: MODE
+          value: prod
+        ports:
+        - containerPort: 80
+          hostPort: 8080
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: db
+spec:
+  containers:
+  - name: db
+    image: postgres
+    ports:
+    - containerPort: 5432
+`)
+	th.AssertOutputEqualsExpected(out, `
+services:
+  db:
+    expose:
+    - "5432"
+    image: postgres
+  web:
+    command:
+    - --verbose
+    deploy:
+      replicas: 2
+    environment:
+      MODE: prod
+    image: nginx:1.17
+    ports:
+    - 8080:80
+version: "3"
+`)
+}
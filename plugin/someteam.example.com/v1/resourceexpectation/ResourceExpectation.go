@@ -0,0 +1,103 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// An expectation example.  Expects the resources the
+// target selects to number count, if set, and each to
+// have the values of fields, e.g. for exactly one
+// Ingress,
+//
+//   target:
+//     kind: Ingress
+//   count: 1
+//
+// or for Deployments that don't run as root,
+//
+//   target:
+//     kind: Deployment
+//   fields:
+//     spec.template.spec.securityContext.runAsNonRoot: true
+type plugin struct {
+	types.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+	Target           types.Selector         `json:"target,omitempty" yaml:"target,omitempty"`
+	Count            *int                   `json:"count,omitempty" yaml:"count,omitempty"`
+	Fields           map[string]interface{} `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+//nolint: golint
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ ifc.Loader, _ *resmap.Factory, config []byte) error {
+	p.Target = types.Selector{}
+	p.Count = nil
+	p.Fields = nil
+	return yaml.Unmarshal(config, p)
+}
+
+func (p *plugin) Check(m resmap.ResMap) error {
+	resources, err := m.Select(p.Target)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, r := range resources {
+		names = append(names, r.GetKind()+"/"+r.GetName())
+	}
+	if p.Count != nil && len(resources) != *p.Count {
+		return fmt.Errorf("expected %d resources, got %d: %s",
+			*p.Count, len(resources), strings.Join(names, ", "))
+	}
+	var fields []string
+	for f := range p.Fields {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	var problems []string
+	for _, f := range fields {
+		// Compare as JSON, as the numbers of the
+		// config and the resources differ in type.
+		expected, err := json.Marshal(p.Fields[f])
+		if err != nil {
+			return err
+		}
+		var offenders []string
+		for i, r := range resources {
+			v, err := r.GetFieldValue(f)
+			if err != nil {
+				offenders = append(offenders, names[i])
+				continue
+			}
+			got, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			if string(got) != string(expected) {
+				offenders = append(offenders, names[i])
+			}
+		}
+		if len(offenders) > 0 {
+			problems = append(problems, fmt.Sprintf(
+				"%s isn't %s in %s",
+				f, expected, strings.Join(offenders, ", ")))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	plugins_test "sigs.k8s.io/kustomize/v3/pkg/plugins/test"
+)
+
+const resources = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: true
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  template:
+    spec:
+      containers:
+      - name: worker
+        image: worker
+---
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: web
+`
+
+func TestResourceExpectationPlugin(t *testing.T) {
+	tc := plugins_test.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"someteam.example.com", "v1", "ResourceExpectation")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	err := th.ErrorFromLoadAndCheckExpectation(`
+apiVersion: someteam.example.com/v1
+kind: ResourceExpectation
+metadata:
+  name: one-ingress
+target:
+  kind: Ingress
+count: 1
+`, resources)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = th.ErrorFromLoadAndCheckExpectation(`
+apiVersion: someteam.example.com/v1
+kind: ResourceExpectation
+metadata:
+  name: one-deployment
+target:
+  kind: Deployment
+count: 1
+`, resources)
+	if err == nil ||
+		err.Error() != "expected 1 resources, got 2: Deployment/web, Deployment/worker" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = th.ErrorFromLoadAndCheckExpectation(`
+apiVersion: someteam.example.com/v1
+kind: ResourceExpectation
+metadata:
+  name: non-root
+target:
+  kind: Deployment
+fields:
+  spec.template.spec.securityContext.runAsNonRoot: true
+`, resources)
+	if err == nil || err.Error() !=
+		"spec.template.spec.securityContext.runAsNonRoot isn't true in Deployment/worker" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}